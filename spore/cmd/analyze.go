@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"math"
 	"os"
+	"sort"
 	"strings"
 
 	"github.com/spf13/cobra"
@@ -17,12 +18,17 @@ var (
 	analyzeTopN         int
 	analyzeStaleDays    int64
 	analyzeHubThreshold int
+	analyzeSampleK      int
+	analyzeResolution   float64
 )
 
 var analyzeCmd = &cobra.Command{
 	Use:   "analyze",
 	Short: "Analyze graph structure: topology, staleness, bridges, health score",
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := SignalContext(cmd)
+		defer cancel()
+
 		db, err := OpenDatabase()
 		if err != nil {
 			return err
@@ -42,9 +48,17 @@ var analyzeCmd = &cobra.Command{
 			HubThreshold: analyzeHubThreshold,
 			TopN:         analyzeTopN,
 			StaleDays:    analyzeStaleDays,
+			Resolution:   analyzeResolution,
+			Centrality: &graph.CentralityConfig{
+				Betweenness: true,
+				PageRank:    true,
+				SampleK:     analyzeSampleK,
+			},
+			Scorers: graph.DefaultScorers(),
+			Weights: graph.DefaultWeights(),
 		}
 
-		report := graph.Analyze(snap, config)
+		report := graph.Analyze(ctx, snap, config)
 
 		if analyzeJSON {
 			enc := json.NewEncoder(os.Stdout)
@@ -63,6 +77,8 @@ func init() {
 	analyzeCmd.Flags().IntVar(&analyzeTopN, "top-n", 10, "Number of top items to show per section")
 	analyzeCmd.Flags().Int64Var(&analyzeStaleDays, "stale-days", 60, "Days since update to consider a node stale")
 	analyzeCmd.Flags().IntVar(&analyzeHubThreshold, "hub-threshold", 15, "Minimum degree to consider a node a hub")
+	analyzeCmd.Flags().IntVar(&analyzeSampleK, "sample-k", 0, "Approximate betweenness centrality from k random sources instead of every node (0 = exact)")
+	analyzeCmd.Flags().Float64Var(&analyzeResolution, "resolution", 1.0, "Louvain community-detection resolution (gamma): higher finds more, smaller communities")
 	rootCmd.AddCommand(analyzeCmd)
 }
 
@@ -74,11 +90,16 @@ func printHumanReadable(report *graph.AnalysisReport, snap *graph.GraphSnapshot)
 	}
 	bar := strings.Repeat("█", barLen) + strings.Repeat("░", 20-barLen)
 	fmt.Printf("\n  Graph Health: %.0f%%  [%s]\n", report.HealthScore*100, bar)
-	fmt.Printf("  breakdown: connectivity=%.2f components=%.2f staleness=%.2f fragility=%.2f\n\n",
-		report.HealthBreakdown.Connectivity,
-		report.HealthBreakdown.Components,
-		report.HealthBreakdown.Staleness,
-		report.HealthBreakdown.Fragility)
+	names := make([]string, 0, len(report.HealthBreakdown))
+	for name := range report.HealthBreakdown {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%s=%.2f", name, report.HealthBreakdown[name]))
+	}
+	fmt.Printf("  breakdown: %s\n\n", strings.Join(parts, " "))
 
 	// Topology
 	t := report.Topology
@@ -126,6 +147,18 @@ func printHumanReadable(report *graph.AnalysisReport, snap *graph.GraphSnapshot)
 				truncID(hub.ID), hub.Degree, hub.InDegree, hub.OutDegree, truncTitle(hub.Title, 40))
 		}
 	}
+	if len(t.BetweennessHubs) > 0 {
+		fmt.Println("\n  Top hubs by betweenness (structural bridges):")
+		for _, hub := range t.BetweennessHubs {
+			fmt.Printf("    %s score=%.2f  %s\n", truncID(hub.ID), hub.Score, truncTitle(hub.Title, 40))
+		}
+	}
+	if len(t.PageRankHubs) > 0 {
+		fmt.Println("\n  Top hubs by PageRank:")
+		for _, hub := range t.PageRankHubs {
+			fmt.Printf("    %s score=%.4f  %s\n", truncID(hub.ID), hub.Score, truncTitle(hub.Title, 40))
+		}
+	}
 
 	// Staleness
 	s := report.Staleness
@@ -207,6 +240,29 @@ func printHumanReadable(report *graph.AnalysisReport, snap *graph.GraphSnapshot)
 		}
 	}
 
+	// Communities
+	cr := report.Communities
+	if cr != nil && len(cr.Sizes) > 0 {
+		fmt.Println("\n  COMMUNITIES")
+		fmt.Println("  ────────────────────────────────────────")
+		fmt.Printf("  %d communities, modularity=%.3f\n", len(cr.Sizes), cr.Modularity)
+		if len(cr.FragileConnections) > 0 {
+			fmt.Printf("  %d fragile inter-community connections (<=2 edges):\n", len(cr.FragileConnections))
+			limit := 10
+			if len(cr.FragileConnections) < limit {
+				limit = len(cr.FragileConnections)
+			}
+			for _, fc := range cr.FragileConnections[:limit] {
+				s := ""
+				if fc.CrossEdges != 1 {
+					s = "s"
+				}
+				fmt.Printf("    community %d <-> community %d (%d edge%s)\n",
+					fc.CommunityA, fc.CommunityB, fc.CrossEdges, s)
+			}
+		}
+	}
+
 	fmt.Println()
 }
 
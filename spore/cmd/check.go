@@ -0,0 +1,165 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/graph"
+)
+
+var (
+	checkReadOnly bool
+	checkRepair   bool
+	checkJSON     bool
+)
+
+var checkCmd = &cobra.Command{
+	Use:   "check",
+	Short: "Audit graph database integrity, with optional repair",
+	Long:  "Verifies the structural integrity of the database discovered by --db/MYCELICA_DB: dangling edges, duplicate edges, disallowed self-loops, orphaned nodes, stale FTS index entries, malformed UUIDs, and cycles in edge types that should be DAG-shaped. --repair deletes dangling edges and rebuilds the FTS index; everything else is reported only.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if checkRepair && cmd.Flags().Changed("read-only") && checkReadOnly {
+			return fmt.Errorf("--read-only and --repair are mutually exclusive")
+		}
+
+		d, err := OpenDatabase()
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+
+		snap, err := graph.SnapshotFromDB(d)
+		if err != nil {
+			return fmt.Errorf("loading graph: %w", err)
+		}
+		report := graph.CheckIntegrity(snap)
+
+		ftsOrphans, err := d.FTSOrphanRowIDs()
+		if err != nil {
+			return fmt.Errorf("checking FTS index: %w", err)
+		}
+
+		var repaired *repairSummary
+		if checkRepair {
+			repaired, err = repairIntegrity(d, report, ftsOrphans)
+			if err != nil {
+				return fmt.Errorf("repairing: %w", err)
+			}
+		}
+
+		if checkJSON {
+			out := struct {
+				*graph.IntegrityReport
+				FTSOrphanCount int            `json:"fts_orphan_count"`
+				Repaired       *repairSummary `json:"repaired,omitempty"`
+			}{IntegrityReport: report, FTSOrphanCount: len(ftsOrphans), Repaired: repaired}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(out)
+		}
+
+		printCheckReport(report, len(ftsOrphans), repaired)
+		return nil
+	},
+}
+
+// repairSummary records what --repair actually changed, for both the JSON
+// and human-readable output.
+type repairSummary struct {
+	DeletedEdges  int      `json:"deleted_edges"`
+	FailedDeletes []string `json:"failed_deletes,omitempty"`
+	FTSRebuilt    bool     `json:"fts_rebuilt"`
+}
+
+// repairIntegrity deletes every dangling edge report found and rebuilds the
+// FTS index if it had orphaned rows. It keeps going on a per-edge delete
+// failure, collecting the offending edge IDs rather than aborting the whole
+// repair.
+func repairIntegrity(d *db.DB, report *graph.IntegrityReport, ftsOrphans []int64) (*repairSummary, error) {
+	summary := &repairSummary{}
+	for _, de := range report.DanglingEdges {
+		if err := d.DeleteEdge(de.EdgeID); err != nil {
+			summary.FailedDeletes = append(summary.FailedDeletes, de.EdgeID)
+			continue
+		}
+		summary.DeletedEdges++
+	}
+
+	if len(ftsOrphans) > 0 {
+		if err := d.RebuildFTS(); err != nil {
+			return summary, fmt.Errorf("rebuilding FTS index: %w", err)
+		}
+		summary.FTSRebuilt = true
+	}
+	return summary, nil
+}
+
+func printCheckReport(report *graph.IntegrityReport, ftsOrphanCount int, repaired *repairSummary) {
+	total := report.Count() + ftsOrphanCount
+	if total == 0 {
+		fmt.Println("\n  Graph integrity: clean (no defects found)")
+		return
+	}
+
+	fmt.Println("\n  GRAPH INTEGRITY")
+	fmt.Println("  ────────────────────────────────────────")
+
+	if n := len(report.DanglingEdges); n > 0 {
+		fmt.Printf("  %d dangling edge(s):\n", n)
+		for _, de := range report.DanglingEdges {
+			fmt.Printf("    - %s\n", de.String())
+		}
+	}
+	if n := len(report.DuplicateEdges); n > 0 {
+		fmt.Printf("  %d duplicate edge group(s):\n", n)
+		for _, dg := range report.DuplicateEdges {
+			fmt.Printf("    - %s (%s -> %s): %v\n", dg.EdgeType, dg.Source, dg.Target, dg.EdgeIDs)
+		}
+	}
+	if n := len(report.SelfLoops); n > 0 {
+		fmt.Printf("  %d self-loop(s):\n", n)
+		for _, sl := range report.SelfLoops {
+			fmt.Printf("    - %s on %s (%s)\n", sl.EdgeID, sl.NodeID, sl.EdgeType)
+		}
+	}
+	if n := len(report.OrphanedNodes); n > 0 {
+		fmt.Printf("  %d orphaned node(s): %v\n", n, report.OrphanedNodes)
+	}
+	if n := len(report.MalformedUUIDs); n > 0 {
+		fmt.Printf("  %d malformed UUID(s):\n", n)
+		for _, m := range report.MalformedUUIDs {
+			fmt.Printf("    - %s: %s\n", m.Kind, m.ID)
+		}
+	}
+	if n := len(report.Cycles); n > 0 {
+		fmt.Printf("  %d cycle(s) in DAG-shaped edge types:\n", n)
+		for _, c := range report.Cycles {
+			fmt.Printf("    - %s: %v\n", c.EdgeType, c.NodeIDs)
+		}
+	}
+	if ftsOrphanCount > 0 {
+		fmt.Printf("  %d stale FTS index row(s) with no matching node\n", ftsOrphanCount)
+	}
+
+	if repaired != nil {
+		fmt.Printf("\n  repaired: deleted %d dangling edge(s)", repaired.DeletedEdges)
+		if len(repaired.FailedDeletes) > 0 {
+			fmt.Printf(", %d failed: %v", len(repaired.FailedDeletes), repaired.FailedDeletes)
+		}
+		if repaired.FTSRebuilt {
+			fmt.Print(", rebuilt FTS index")
+		}
+		fmt.Println()
+	}
+	fmt.Println()
+}
+
+func init() {
+	checkCmd.Flags().BoolVar(&checkReadOnly, "read-only", true, "Report defects without modifying the database (default)")
+	checkCmd.Flags().BoolVar(&checkRepair, "repair", false, "Delete dangling edges and rebuild the FTS index")
+	checkCmd.Flags().BoolVar(&checkJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(checkCmd)
+}
@@ -18,6 +18,7 @@ var (
 	ctxItemsOnly     bool
 	ctxJSON          bool
 	ctxEdgeTypes     string
+	ctxFollowInverse bool
 )
 
 var contextCmd = &cobra.Command{
@@ -42,6 +43,7 @@ var contextCmd = &cobra.Command{
 			MaxCost:       ctxMaxCost,
 			NotSuperseded: ctxNotSuperseded,
 			ItemsOnly:     ctxItemsOnly,
+			FollowInverse: ctxFollowInverse,
 		}
 
 		if ctxEdgeTypes != "" {
@@ -93,6 +95,7 @@ func init() {
 	contextCmd.Flags().BoolVar(&ctxItemsOnly, "items-only", false, "Skip categories from results")
 	contextCmd.Flags().BoolVar(&ctxJSON, "json", false, "JSON output")
 	contextCmd.Flags().StringVar(&ctxEdgeTypes, "edge-types", "", "Comma-separated edge type allowlist")
+	contextCmd.Flags().BoolVar(&ctxFollowInverse, "follow-inverse", false, "Also traverse registered mirror edge kinds backward (e.g. references as referenced_by), at a cost premium")
 	rootCmd.AddCommand(contextCmd)
 }
 
@@ -0,0 +1,116 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/db"
+)
+
+var (
+	doctorFix  bool
+	doctorJSON bool
+)
+
+var doctorCmd = &cobra.Command{
+	Use:   "doctor",
+	Short: "Audit orchestration-specific graph invariants, with optional repair",
+	Long:  "Scans the nodes/edges tables discovered by --db/MYCELICA_DB for defects specific to the orchestration loop: dangling edge references, broken or cyclic superseded_by chains, dead tracks self-loops left by a deleted task, escalation nodes with no surviving task target, category nodes disconnected from any item, and edges with confidence outside [0,1] or NaN. --fix deletes dangling edges and nulls bogus superseded_by pointers in a single transaction; everything else is reported only.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := OpenDatabase()
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+
+		report, err := d.Doctor()
+		if err != nil {
+			return fmt.Errorf("running doctor: %w", err)
+		}
+
+		var fixed *db.DoctorFixSummary
+		if doctorFix {
+			fixed, err = d.DoctorFix(report)
+			if err != nil {
+				return fmt.Errorf("applying fix: %w", err)
+			}
+		}
+
+		if doctorJSON {
+			out := struct {
+				*db.DoctorReport
+				Fixed *db.DoctorFixSummary `json:"fixed,omitempty"`
+			}{DoctorReport: report, Fixed: fixed}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(out)
+		}
+
+		printDoctorReport(report, fixed)
+		return nil
+	},
+}
+
+func printDoctorReport(report *db.DoctorReport, fixed *db.DoctorFixSummary) {
+	if report.Count() == 0 {
+		fmt.Println("\n  Doctor: clean (no defects found)")
+		return
+	}
+
+	fmt.Println("\n  DOCTOR REPORT")
+	fmt.Println("  ────────────────────────────────────────")
+
+	if n := len(report.DanglingEdges); n > 0 {
+		fmt.Printf("  %d dangling edge(s):\n", n)
+		for _, de := range report.DanglingEdges {
+			fmt.Printf("    - edge %s (%s): %s -> %s, missing node %s\n", de.EdgeID, de.EdgeType, de.Source, de.Target, de.MissingNode)
+		}
+	}
+	if n := len(report.BrokenSupersessions); n > 0 {
+		fmt.Printf("  %d broken superseded_by pointer(s):\n", n)
+		for _, bs := range report.BrokenSupersessions {
+			fmt.Printf("    - edge %s -> %s (%s)\n", bs.EdgeID, bs.SupersededBy, bs.Reason)
+		}
+	}
+	if n := len(report.DeadTrackSelfLoops); n > 0 {
+		fmt.Printf("  %d dead tracks self-loop(s):\n", n)
+		for _, dt := range report.DeadTrackSelfLoops {
+			fmt.Printf("    - edge %s on missing node %s\n", dt.EdgeID, dt.NodeID)
+		}
+	}
+	if n := len(report.OrphanedEscalations); n > 0 {
+		fmt.Printf("  %d orphaned escalation(s):\n", n)
+		for _, oe := range report.OrphanedEscalations {
+			if oe.TaskNodeID == "" {
+				fmt.Printf("    - %s: no tracks edge to a task node\n", oe.NodeID)
+			} else {
+				fmt.Printf("    - %s: task node %s no longer exists\n", oe.NodeID, oe.TaskNodeID)
+			}
+		}
+	}
+	if n := len(report.OrphanedCategoryNodes); n > 0 {
+		fmt.Printf("  %d orphaned category node(s):\n", n)
+		for _, oc := range report.OrphanedCategoryNodes {
+			fmt.Printf("    - %s\n", oc.NodeID)
+		}
+	}
+	if n := len(report.BadConfidenceEdges); n > 0 {
+		fmt.Printf("  %d edge(s) with bad confidence:\n", n)
+		for _, bc := range report.BadConfidenceEdges {
+			fmt.Printf("    - edge %s: %v\n", bc.EdgeID, bc.Confidence)
+		}
+	}
+
+	if fixed != nil {
+		fmt.Printf("\n  fixed: deleted %d dangling edge(s), cleared %d superseded_by pointer(s)\n", fixed.DeletedEdges, fixed.ClearedSupersededBy)
+	}
+	fmt.Println()
+}
+
+func init() {
+	doctorCmd.Flags().BoolVar(&doctorFix, "fix", false, "Delete dangling edges and null bogus superseded_by pointers")
+	doctorCmd.Flags().BoolVar(&doctorJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(doctorCmd)
+}
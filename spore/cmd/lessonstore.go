@@ -0,0 +1,29 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"mycelica/spore/internal/orchestrate"
+)
+
+// resolveLessonStore builds a LessonStore from a --lesson-store flag value:
+// "" or "sqlite" uses the graph database itself, "memory" is an ephemeral
+// in-process store, and "pebble:<dir>" opens a disk-backed store at dir for
+// corpora too large to scan comfortably via sqlite.
+func resolveLessonStore(spec string) (orchestrate.LessonStore, error) {
+	switch {
+	case spec == "" || spec == "sqlite":
+		return nil, nil // nil lets RunOrchestration/RunLoop default to sqlite
+	case spec == "memory":
+		return orchestrate.NewMemoryLessonStore(), nil
+	case strings.HasPrefix(spec, "pebble:"):
+		dir := strings.TrimPrefix(spec, "pebble:")
+		if dir == "" {
+			return nil, fmt.Errorf("--lesson-store=pebble: requires a directory, e.g. pebble:/var/lib/spore/lessons")
+		}
+		return orchestrate.NewPebbleLessonStore(dir)
+	default:
+		return nil, fmt.Errorf("unknown --lesson-store %q (want sqlite, memory, or pebble:<dir>)", spec)
+	}
+}
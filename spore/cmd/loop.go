@@ -4,37 +4,64 @@ import (
 	"encoding/json"
 	"fmt"
 	"os"
+	"time"
 
 	"github.com/spf13/cobra"
 	"mycelica/spore/internal/orchestrate"
 )
 
 var (
-	loopFile             string
-	loopStdin            bool
-	loopBudget           float64
-	loopMaxRuns          int
-	loopMaxBounces       int
-	loopMaxTurns         int
-	loopDryRun           bool
-	loopReset            bool
-	loopNoCommit         bool
-	loopPauseOnEscalation bool
-	loopExperiment       string
-	loopCoderModel       string
-	loopVerbose          bool
-	loopJSON             bool
+	loopFile               string
+	loopStdin              bool
+	loopBudget             float64
+	loopMaxRuns            int
+	loopMaxBounces         int
+	loopMaxTurns           int
+	loopDryRun             bool
+	loopReset              bool
+	loopNoCommit           bool
+	loopPauseOnEscalation  bool
+	loopExperiment         string
+	loopCoderModel         string
+	loopVerbose            bool
+	loopJSON               bool
+	loopVerdictRules       string
+	loopVerdictMatchers    string
+	loopEnforcementPolicy  string
+	loopRun                string
+	loopSkip               string
+	loopLessonStore        string
+	loopCoderTimeout       time.Duration
+	loopVerifierTimeout    time.Duration
+	loopSummarizerTimeout  time.Duration
+	loopCandidates         int
+	loopParallelCandidates bool
+	loopParallelism        int
+	loopDAG                bool
+	loopTaskTimeout        time.Duration
+	loopDeadline           time.Duration
+	loopTreatTimeoutAsEsc  bool
+	loopRetryTimeouts      bool
+	loopSignCommits        bool
+	loopSquashOnLoopEnd    bool
 )
 
 var loopCmd = &cobra.Command{
 	Use:   "loop [flags]",
 	Short: "Run multiple tasks from a file or stdin with budget tracking",
-	Long: `Dispatches tasks from a file (one per line, or --- delimited) through the
-coder -> verifier -> summarizer pipeline. Tracks budget, persists state for
-resume across restarts, and auto-commits between verified tasks.
+	Long: `Dispatches tasks from a file through the coder -> verifier -> summarizer
+pipeline. Tracks budget, persists state for resume across restarts, and
+auto-commits between verified tasks.
 
-Lines starting with # and blank lines are ignored.`,
+The file is either a plain task list (one per line, or --- delimited;
+lines starting with # and blank lines are ignored) or a structured YAML
+task manifest: a top-level list of {id, prompt, depends_on, budget_usd,
+timeout, max_retries, tags, orch_overrides} entries. The format is
+auto-detected.`,
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := SignalContext(cmd)
+		defer cancel()
+
 		// Determine source
 		source := loopFile
 		if loopStdin {
@@ -51,29 +78,75 @@ Lines starting with # and blank lines are ignored.`,
 		defer d.Close()
 
 		orchConfig := orchestrate.OrchestrationConfig{
-			TaskFile:    orchestrate.DefaultTaskFileConfig(),
-			MaxBounces:  loopMaxBounces,
-			MaxTurns:    loopMaxTurns,
-			CoderModel:  loopCoderModel,
-			OutputDir:   "/tmp/spore/",
-			Experiment:  loopExperiment,
-			DryRun:      loopDryRun,
-			Verbose:     loopVerbose,
-			JSON:        loopJSON,
+			TaskFile:           orchestrate.DefaultTaskFileConfig(),
+			MaxBounces:         loopMaxBounces,
+			MaxTurns:           loopMaxTurns,
+			CoderModel:         loopCoderModel,
+			OutputDir:          "/tmp/spore/",
+			Experiment:         loopExperiment,
+			DryRun:             loopDryRun,
+			Verbose:            loopVerbose,
+			JSON:               loopJSON,
+			CoderTimeout:       loopCoderTimeout,
+			VerifierTimeout:    loopVerifierTimeout,
+			SummarizerTimeout:  loopSummarizerTimeout,
+			Candidates:         loopCandidates,
+			ParallelCandidates: loopParallelCandidates,
+		}
+
+		if loopVerdictRules != "" {
+			rules, err := orchestrate.LoadVerdictRules(loopVerdictRules)
+			if err != nil {
+				return fmt.Errorf("loading verdict rules: %w", err)
+			}
+			orchConfig.VerdictRules = rules
+		}
+
+		if loopVerdictMatchers != "" {
+			matchers, err := orchestrate.LoadVerdictMatchers(loopVerdictMatchers)
+			if err != nil {
+				return fmt.Errorf("loading verdict matchers: %w", err)
+			}
+			orchConfig.VerdictMatchers = matchers
 		}
 
 		config := orchestrate.LoopConfig{
-			Source:            source,
-			Budget:            loopBudget,
-			MaxRuns:           loopMaxRuns,
-			StopOnEscalation:  3,
-			Reset:             loopReset,
-			AutoCommit:        !loopNoCommit,
-			PauseOnEscalation: loopPauseOnEscalation,
-			OrchConfig:        orchConfig,
+			Source:                   source,
+			Budget:                   loopBudget,
+			MaxRuns:                  loopMaxRuns,
+			StopOnEscalation:         3,
+			Reset:                    loopReset,
+			AutoCommit:               !loopNoCommit,
+			PauseOnEscalation:        loopPauseOnEscalation,
+			OrchConfig:               orchConfig,
+			RunPattern:               loopRun,
+			SkipPattern:              loopSkip,
+			Parallelism:              loopParallelism,
+			DAG:                      loopDAG,
+			TaskTimeout:              loopTaskTimeout,
+			LoopDeadline:             loopDeadline,
+			TreatTimeoutAsEscalation: loopTreatTimeoutAsEsc,
+			RetryTimeouts:            loopRetryTimeouts,
+			SignCommits:              loopSignCommits,
+			SquashOnLoopEnd:          loopSquashOnLoopEnd,
+		}
+
+		if loopEnforcementPolicy != "" {
+			policy, err := orchestrate.LoadEnforcementPolicy(loopEnforcementPolicy)
+			if err != nil {
+				return fmt.Errorf("loading enforcement policy: %w", err)
+			}
+			config.Enforcement = policy
+			config.OrchConfig.Enforcement = policy
+		}
+
+		lessonStore, err := resolveLessonStore(loopLessonStore)
+		if err != nil {
+			return err
 		}
+		config.OrchConfig.LessonStore = lessonStore
 
-		result, err := orchestrate.RunLoop(d, config)
+		result, err := orchestrate.RunLoop(ctx, d, config)
 		if err != nil {
 			return err
 		}
@@ -103,5 +176,24 @@ func init() {
 	loopCmd.Flags().StringVar(&loopCoderModel, "coder-model", "", "Override coder model")
 	loopCmd.Flags().BoolVar(&loopVerbose, "verbose", false, "Verbose output")
 	loopCmd.Flags().BoolVar(&loopJSON, "json", false, "Output as JSON")
+	loopCmd.Flags().StringVar(&loopVerdictRules, "verdict-rules", "", "Path to a YAML file of expr-based verdict rules")
+	loopCmd.Flags().StringVar(&loopVerdictMatchers, "verdict-matchers", "", "Path to a YAML file of JSONPath-based verdict matchers")
+	loopCmd.Flags().StringVar(&loopEnforcementPolicy, "enforcement-policy", "", "Path to a YAML file of scoped enforcement rules")
+	loopCmd.Flags().StringVar(&loopRun, "run", "", "Only dispatch tasks whose name/tags match this slash-separated pattern (go test -run style)")
+	loopCmd.Flags().StringVar(&loopSkip, "skip", "", "Skip tasks whose name/tags match this slash-separated pattern")
+	loopCmd.Flags().StringVar(&loopLessonStore, "lesson-store", "sqlite", "Lesson storage backend: sqlite, memory, or pebble:<dir>")
+	loopCmd.Flags().DurationVar(&loopCoderTimeout, "coder-timeout", 0, "Max duration for the coder phase (0 = no phase-specific deadline)")
+	loopCmd.Flags().DurationVar(&loopVerifierTimeout, "verifier-timeout", 0, "Max duration for the verifier phase (0 = no phase-specific deadline)")
+	loopCmd.Flags().DurationVar(&loopSummarizerTimeout, "summarizer-timeout", 0, "Max duration for the summarizer phase (0 = no phase-specific deadline)")
+	loopCmd.Flags().IntVar(&loopCandidates, "candidates", 1, "Number of coder attempts per bounce, each in an isolated git worktree; best VerdictSupports wins")
+	loopCmd.Flags().BoolVar(&loopParallelCandidates, "parallel-candidates", false, "Spawn all --candidates coder attempts concurrently instead of one at a time")
+	loopCmd.Flags().IntVar(&loopParallelism, "parallelism", 1, "Maximum tasks to run concurrently (1 = sequential, the original behavior)")
+	loopCmd.Flags().BoolVar(&loopDAG, "dag", false, "Print a topological depends_on plan for a structured task manifest and exit (implies --dry-run)")
+	loopCmd.Flags().DurationVar(&loopTaskTimeout, "task-timeout", 0, "Default per-task deadline for tasks that don't set their own timeout (0 = none)")
+	loopCmd.Flags().DurationVar(&loopDeadline, "loop-deadline", 0, "Deadline for the entire loop run; cancels the in-flight task and stops dispatching once elapsed (0 = none)")
+	loopCmd.Flags().BoolVar(&loopTreatTimeoutAsEsc, "treat-timeout-as-escalation", false, "Count a task timeout toward consecutive-escalation abort logic like an escalated verdict")
+	loopCmd.Flags().BoolVar(&loopRetryTimeouts, "retry-timeouts", false, "On resume, retry tasks that previously timed out instead of skipping them like verified tasks")
+	loopCmd.Flags().BoolVar(&loopSignCommits, "sign-commits", false, "GPG/SSH-sign auto-commits with git commit -S")
+	loopCmd.Flags().BoolVar(&loopSquashOnLoopEnd, "squash-on-loop-end", false, "Defer all per-task auto-commits into a single commit at the end of the loop")
 	rootCmd.AddCommand(loopCmd)
 }
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/db"
+)
+
+var (
+	lsDelimiter         string
+	lsMaxKeys           int
+	lsContinuationToken string
+	lsJSON              bool
+)
+
+var lsCmd = &cobra.Command{
+	Use:   "ls [prefix]",
+	Short: "List nodes under a prefix, S3 ListObjectsV2-style",
+	Long:  "Treats node titles as '/'-delimited keys. Nodes sharing a path segment past [prefix] collapse into one common-prefix 'folder' entry instead of being listed individually. Paginate with --continuation-token set to the previous call's next_continuation_token.",
+	Args:  cobra.MaximumNArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		var prefix string
+		if len(args) > 0 {
+			prefix = args[0]
+		}
+
+		d, err := OpenDatabase()
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+
+		page, err := d.ListNodes(db.ListNodesParams{
+			Prefix:            prefix,
+			Delimiter:         lsDelimiter,
+			MaxKeys:           lsMaxKeys,
+			ContinuationToken: lsContinuationToken,
+		})
+		if err != nil {
+			return err
+		}
+
+		if lsJSON {
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(page)
+		}
+
+		for _, p := range page.CommonPrefixes {
+			fmt.Printf("  %s\n", p)
+		}
+		for _, n := range page.Nodes {
+			id := n.ID
+			if len(id) > 8 {
+				id = id[:8]
+			}
+			fmt.Printf("  %s  %s\n", id, n.Title)
+		}
+		if page.IsTruncated {
+			fmt.Printf("\n--continuation-token %s\n", page.NextContinuationToken)
+		}
+		return nil
+	},
+}
+
+func init() {
+	lsCmd.Flags().StringVar(&lsDelimiter, "delimiter", "/", "Path segment delimiter")
+	lsCmd.Flags().IntVar(&lsMaxKeys, "max-keys", 1000, "Maximum entries (nodes + common prefixes) to return")
+	lsCmd.Flags().StringVar(&lsContinuationToken, "continuation-token", "", "Resume from a previous page's next_continuation_token")
+	lsCmd.Flags().BoolVar(&lsJSON, "json", false, "Output as JSON")
+	rootCmd.AddCommand(lsCmd)
+}
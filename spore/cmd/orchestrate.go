@@ -5,22 +5,36 @@ import (
 	"fmt"
 	"os"
 	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"mycelica/spore/internal/orchestrate"
 )
 
 var (
-	orchMaxBounces  int
-	orchMaxTurns    int
-	orchNoSummarize bool
-	orchDryRun      bool
-	orchVerbose     bool
-	orchQuiet       bool
-	orchOutputDir   string
-	orchExperiment  string
-	orchCoderModel  string
-	orchJSON        bool
+	orchMaxBounces         int
+	orchMaxTurns           int
+	orchNoSummarize        bool
+	orchDryRun             bool
+	orchVerbose            bool
+	orchQuiet              bool
+	orchOutputDir          string
+	orchExperiment         string
+	orchCoderModel         string
+	orchJSON               bool
+	orchVerdictRules       string
+	orchVerdictMatchers    string
+	orchEnforcementPolicy  string
+	orchLessonStore        string
+	orchCoderTimeout       time.Duration
+	orchVerifierTimeout    time.Duration
+	orchSummarizerTimeout  time.Duration
+	orchCandidates         int
+	orchParallelCandidates bool
+	orchResumeRunID        string
+	orchTaskFileFormat     string
+	orchRollback           string
+	orchSupervise          bool
 )
 
 var orchestrateCmd = &cobra.Command{
@@ -29,6 +43,9 @@ var orchestrateCmd = &cobra.Command{
 	Long:  "Orchestrates a multi-agent pipeline: coder writes code, verifier checks it, summarizer records the outcome. Bounces on verification failure.",
 	Args:  cobra.MinimumNArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := SignalContext(cmd)
+		defer cancel()
+
 		task := strings.Join(args, " ")
 
 		d, err := OpenDatabase()
@@ -38,19 +55,66 @@ var orchestrateCmd = &cobra.Command{
 		defer d.Close()
 
 		config := orchestrate.OrchestrationConfig{
-			TaskFile:    orchestrate.DefaultTaskFileConfig(),
-			MaxBounces:  orchMaxBounces,
-			MaxTurns:    orchMaxTurns,
-			CoderModel:  orchCoderModel,
-			OutputDir:   orchOutputDir,
-			Experiment:  orchExperiment,
-			DryRun:      orchDryRun,
-			NoSummarize: orchNoSummarize,
-			Verbose:     orchVerbose,
-			Quiet:       orchQuiet,
-			JSON:        orchJSON,
+			TaskFile:           orchestrate.DefaultTaskFileConfig(),
+			MaxBounces:         orchMaxBounces,
+			MaxTurns:           orchMaxTurns,
+			CoderModel:         orchCoderModel,
+			OutputDir:          orchOutputDir,
+			Experiment:         orchExperiment,
+			DryRun:             orchDryRun,
+			NoSummarize:        orchNoSummarize,
+			Verbose:            orchVerbose,
+			Quiet:              orchQuiet,
+			JSON:               orchJSON,
+			CoderTimeout:       orchCoderTimeout,
+			VerifierTimeout:    orchVerifierTimeout,
+			SummarizerTimeout:  orchSummarizerTimeout,
+			Candidates:         orchCandidates,
+			ParallelCandidates: orchParallelCandidates,
+			ResumeRunID:        orchResumeRunID,
+			Rollback:           orchestrate.RollbackPolicy(orchRollback),
+		}
+		config.TaskFile.TaskFileFormat = orchTaskFileFormat
+
+		if orchVerdictRules != "" {
+			rules, err := orchestrate.LoadVerdictRules(orchVerdictRules)
+			if err != nil {
+				return fmt.Errorf("loading verdict rules: %w", err)
+			}
+			config.VerdictRules = rules
+		}
+
+		if orchVerdictMatchers != "" {
+			matchers, err := orchestrate.LoadVerdictMatchers(orchVerdictMatchers)
+			if err != nil {
+				return fmt.Errorf("loading verdict matchers: %w", err)
+			}
+			config.VerdictMatchers = matchers
+		}
+
+		if orchEnforcementPolicy != "" {
+			policy, err := orchestrate.LoadEnforcementPolicy(orchEnforcementPolicy)
+			if err != nil {
+				return fmt.Errorf("loading enforcement policy: %w", err)
+			}
+			config.Enforcement = policy
+		}
+
+		if orchSupervise {
+			config.Supervisor = &orchestrate.SupervisorPolicy{
+				StartSeconds: 10 * time.Second,
+				StartRetries: 3,
+				RestartOn:    orchestrate.RestartOnFailure(),
+				Backoff:      orchestrate.DefaultBackoffPolicy(),
+			}
 		}
 
+		lessonStore, err := resolveLessonStore(orchLessonStore)
+		if err != nil {
+			return err
+		}
+		config.LessonStore = lessonStore
+
 		if !orchQuiet && !orchJSON {
 			taskShort := task
 			if len(taskShort) > 60 {
@@ -59,7 +123,7 @@ var orchestrateCmd = &cobra.Command{
 			fmt.Printf("Orchestrating: %s\n", taskShort)
 		}
 
-		result, err := orchestrate.RunOrchestration(d, task, config)
+		result, err := orchestrate.RunOrchestration(ctx, d, task, config)
 
 		if orchJSON && result != nil {
 			enc := json.NewEncoder(os.Stdout)
@@ -99,5 +163,41 @@ func init() {
 	orchestrateCmd.Flags().StringVar(&orchExperiment, "experiment", "", "A/B experiment label")
 	orchestrateCmd.Flags().StringVar(&orchCoderModel, "coder-model", "", "Override coder model")
 	orchestrateCmd.Flags().BoolVar(&orchJSON, "json", false, "Output as JSON")
+	orchestrateCmd.Flags().StringVar(&orchVerdictRules, "verdict-rules", "", "Path to a YAML file of expr-based verdict rules")
+	orchestrateCmd.Flags().StringVar(&orchVerdictMatchers, "verdict-matchers", "", "Path to a YAML file of JSONPath-based verdict matchers")
+	orchestrateCmd.Flags().StringVar(&orchEnforcementPolicy, "enforcement-policy", "", "Path to a YAML file of scoped enforcement rules")
+	orchestrateCmd.Flags().StringVar(&orchLessonStore, "lesson-store", "sqlite", "Lesson storage backend: sqlite, memory, or pebble:<dir>")
+	orchestrateCmd.Flags().DurationVar(&orchCoderTimeout, "coder-timeout", 0, "Max duration for the coder phase (0 = no phase-specific deadline)")
+	orchestrateCmd.Flags().DurationVar(&orchVerifierTimeout, "verifier-timeout", 0, "Max duration for the verifier phase (0 = no phase-specific deadline)")
+	orchestrateCmd.Flags().DurationVar(&orchSummarizerTimeout, "summarizer-timeout", 0, "Max duration for the summarizer phase (0 = no phase-specific deadline)")
+	orchestrateCmd.Flags().IntVar(&orchCandidates, "candidates", 1, "Number of coder attempts per bounce, each in an isolated git worktree; best VerdictSupports wins")
+	orchestrateCmd.Flags().BoolVar(&orchParallelCandidates, "parallel-candidates", false, "Spawn all --candidates coder attempts concurrently instead of one at a time")
+	orchestrateCmd.Flags().StringVar(&orchResumeRunID, "resume-run-id", "", "Resume a previously interrupted run from its last checkpoint")
+	orchestrateCmd.Flags().StringVar(&orchTaskFileFormat, "task-file-format", "markdown", "Task file output format: markdown, html, or json")
+	orchestrateCmd.Flags().StringVar(&orchRollback, "rollback", "", "Auto-revert policy: '' (never), on-escalation, or between-bounces")
+	orchestrateCmd.Flags().BoolVar(&orchSupervise, "supervise-restarts", false, "Auto-restart a crashed coder/verifier subprocess with exponential backoff")
+	orchestrateCmd.AddCommand(orchestrateRollbackCmd)
 	rootCmd.AddCommand(orchestrateCmd)
 }
+
+var orchRollbackOutputDir string
+
+var orchestrateRollbackCmd = &cobra.Command{
+	Use:   "rollback <run-id>",
+	Short: "Revert a run's working tree back to the HEAD it started from",
+	Long:  "Resets the working tree a run mutated back to its pre-run HEAD, discarding the run's changes into a git stash entry (see RollbackPolicy). Only works for runs that executed with --rollback set to on-escalation or between-bounces.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		runID := args[0]
+		rr, err := orchestrate.Rollback(orchRollbackOutputDir, runID)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Rolled back %s to %s\n", rr.WorkDir, rr.PreRunHEAD)
+		return nil
+	},
+}
+
+func init() {
+	orchestrateRollbackCmd.Flags().StringVar(&orchRollbackOutputDir, "output-dir", "/tmp/spore/", "Task file output directory the run's rollback record was saved under")
+}
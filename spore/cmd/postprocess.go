@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/analysis"
+	"mycelica/spore/internal/graph"
+)
+
+var (
+	postprocessJSON       bool
+	postprocessStaleDays  int64
+	postprocessResolution float64
+)
+
+var postprocessCmd = &cobra.Command{
+	Use:   "postprocess",
+	Short: "Materialize bridge/community/staleness analysis into synthesized graph edges",
+	Long: "Runs ComputeBridges, ComputeCommunities, and ComputeStaleness over the current graph " +
+		"and writes their findings back as synthesized structurally_critical_via, same_community_as, " +
+		"and superseded_summary_of edges -- so context-for-task's traversal (or its --edge-types " +
+		"allowlist) can act on that structure instead of it only being printed by `analyze`. " +
+		"Each run first deletes the prior run's synthesized edges of these three kinds, so reruns " +
+		"don't accumulate duplicates.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := SignalContext(cmd)
+		defer cancel()
+
+		d, err := OpenDatabase()
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+
+		passes := []analysis.Pass{
+			analysis.StructurallyCriticalPass(),
+			analysis.SameCommunityPass(&graph.CommunityConfig{Resolution: postprocessResolution, FragileThreshold: 2}),
+			analysis.SupersededSummaryPass(postprocessStaleDays),
+		}
+		kinds := make([]string, len(passes))
+		for i, p := range passes {
+			kinds[i] = p.EdgeType
+		}
+
+		deleted, err := analysis.DeleteTransitEdges(d, kinds...)
+		if err != nil {
+			return fmt.Errorf("clearing prior synthesized edges: %w", err)
+		}
+
+		stats, err := analysis.PostProcess(ctx, d, passes...)
+		if err != nil {
+			return fmt.Errorf("post-processing: %w", err)
+		}
+
+		if postprocessJSON {
+			out := struct {
+				Deleted *analysis.DeleteStats `json:"deleted"`
+				*analysis.PostStats
+			}{deleted, stats}
+			enc := json.NewEncoder(os.Stdout)
+			enc.SetIndent("", "  ")
+			return enc.Encode(out)
+		}
+
+		printPostprocessReport(deleted, stats)
+		return nil
+	},
+}
+
+func printPostprocessReport(deleted *analysis.DeleteStats, stats *analysis.PostStats) {
+	fmt.Println("\n  POSTPROCESS")
+	fmt.Println("  ────────────────────────────────────────")
+	fmt.Printf("  cleared %d prior synthesized edge(s)", deleted.Deleted)
+	if len(deleted.FailedDeletes) > 0 {
+		fmt.Printf(" (%d failed)", len(deleted.FailedDeletes))
+	}
+	fmt.Println()
+
+	for _, name := range []string{"structurally_critical", "same_community", "superseded_summary"} {
+		ps := stats.PassStats[name]
+		if ps == nil {
+			continue
+		}
+		fmt.Printf("  %s: %d created", name, ps.Created)
+		if ps.Failed > 0 {
+			fmt.Printf(", %d failed", ps.Failed)
+		}
+		fmt.Println()
+	}
+	fmt.Printf("\n  %d edge(s) created total\n\n", stats.EdgesCreated)
+}
+
+func init() {
+	postprocessCmd.Flags().BoolVar(&postprocessJSON, "json", false, "Output as JSON")
+	postprocessCmd.Flags().Int64Var(&postprocessStaleDays, "stale-days", 60, "Days since update to consider a summary's target stale")
+	postprocessCmd.Flags().Float64Var(&postprocessResolution, "resolution", 1.0, "Louvain community-detection resolution (gamma) for the same-community pass")
+	rootCmd.AddCommand(postprocessCmd)
+}
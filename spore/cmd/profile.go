@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/orchestrate"
+)
+
+var profileCmd = &cobra.Command{
+	Use:   "profile",
+	Short: "Manage the persisted default orchestration profile",
+	Long:  "Reads or writes profile.toml next to the database (see DiscoverDB). `retry` (and a future `run` command) fall back to it for any flag that isn't set explicitly or via a MYCELICA_RETRY_* environment variable, so reproducible A/B experiments don't need the same flag list repeated on every invocation.",
+}
+
+var (
+	profileMaxBounces  int
+	profileMaxTurns    int
+	profileCoderModel  string
+	profileExperiment  string
+	profileOutputDir   string
+	profileNoSummarize bool
+	profileVerbose     bool
+	profileQuiet       bool
+	profileJSON        bool
+)
+
+var profileSetCmd = &cobra.Command{
+	Use:   "set",
+	Short: "Merge the given flags into profile.toml",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath, err := DiscoverDB()
+		if err != nil {
+			return err
+		}
+		path := orchestrate.ProfilePath(dbPath)
+
+		p, err := orchestrate.LoadProfile(path)
+		if err != nil {
+			return err
+		}
+
+		if cmd.Flags().Changed("max-bounces") {
+			p.MaxBounces = profileMaxBounces
+		}
+		if cmd.Flags().Changed("max-turns") {
+			p.MaxTurns = profileMaxTurns
+		}
+		if cmd.Flags().Changed("coder-model") {
+			p.CoderModel = profileCoderModel
+		}
+		if cmd.Flags().Changed("experiment") {
+			p.Experiment = profileExperiment
+		}
+		if cmd.Flags().Changed("output-dir") {
+			p.OutputDir = profileOutputDir
+		}
+		if cmd.Flags().Changed("no-summarize") {
+			p.NoSummarize = profileNoSummarize
+		}
+		if cmd.Flags().Changed("verbose") {
+			p.Verbose = profileVerbose
+		}
+		if cmd.Flags().Changed("quiet") {
+			p.Quiet = profileQuiet
+		}
+		if cmd.Flags().Changed("json") {
+			p.JSON = profileJSON
+		}
+
+		if err := orchestrate.SaveProfile(path, p); err != nil {
+			return err
+		}
+		fmt.Printf("Wrote profile to %s\n", path)
+		return nil
+	},
+}
+
+var profileShowCmd = &cobra.Command{
+	Use:   "show",
+	Short: "Print the persisted profile",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		dbPath, err := DiscoverDB()
+		if err != nil {
+			return err
+		}
+		p, err := orchestrate.LoadProfile(orchestrate.ProfilePath(dbPath))
+		if err != nil {
+			return err
+		}
+		return p.Encode(os.Stdout)
+	},
+}
+
+func init() {
+	profileSetCmd.Flags().IntVar(&profileMaxBounces, "max-bounces", 0, "Default max coder->verifier bounces")
+	profileSetCmd.Flags().IntVar(&profileMaxTurns, "max-turns", 0, "Default max Claude turns per agent")
+	profileSetCmd.Flags().StringVar(&profileCoderModel, "coder-model", "", "Default coder model override")
+	profileSetCmd.Flags().StringVar(&profileExperiment, "experiment", "", "Default A/B experiment label")
+	profileSetCmd.Flags().StringVar(&profileOutputDir, "output-dir", "", "Default task file output directory")
+	profileSetCmd.Flags().BoolVar(&profileNoSummarize, "no-summarize", false, "Default: skip summarizer after verification")
+	profileSetCmd.Flags().BoolVar(&profileVerbose, "verbose", false, "Default: verbose output")
+	profileSetCmd.Flags().BoolVar(&profileQuiet, "quiet", false, "Default: suppress non-essential output")
+	profileSetCmd.Flags().BoolVar(&profileJSON, "json", false, "Default: output as JSON")
+
+	profileCmd.AddCommand(profileSetCmd, profileShowCmd)
+	rootCmd.AddCommand(profileCmd)
+}
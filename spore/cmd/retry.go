@@ -28,6 +28,9 @@ var retryCmd = &cobra.Command{
 	Long:  "Resolves the original task from a run node ID (prefix or full), extracts the task description, and runs a fresh orchestration.",
 	Args:  cobra.ExactArgs(1),
 	RunE: func(cmd *cobra.Command, args []string) error {
+		ctx, cancel := SignalContext(cmd)
+		defer cancel()
+
 		d, err := OpenDatabase()
 		if err != nil {
 			return err
@@ -51,28 +54,50 @@ var retryCmd = &cobra.Command{
 			shortID = shortID[:8]
 		}
 
-		if !retryQuiet && !retryJSON {
+		// Resolve each flag as explicit flag > MYCELICA_RETRY_* env var >
+		// persisted profile > built-in default, so reproducible A/B
+		// experiments don't need the same long flag list on every
+		// invocation (see `spore profile`).
+		defaults, err := orchestrate.DefaultOrchestrationConfig().LoadDefaults(orchestrate.ProfilePath(d.Path))
+		if err != nil {
+			return fmt.Errorf("loading profile: %w", err)
+		}
+
+		maxBounces := ResolveIntFlag(cmd, "max-bounces", "MYCELICA_RETRY_MAX_BOUNCES", defaults.MaxBounces, retryMaxBounces)
+		maxTurns := ResolveIntFlag(cmd, "max-turns", "MYCELICA_RETRY_MAX_TURNS", defaults.MaxTurns, retryMaxTurns)
+		coderModel := ResolveStringFlag(cmd, "coder-model", "MYCELICA_RETRY_CODER_MODEL", defaults.CoderModel, retryCoderModel)
+		experiment := ResolveStringFlag(cmd, "experiment", "MYCELICA_RETRY_EXPERIMENT", defaults.Experiment, retryExperiment)
+		noSummarize := ResolveBoolFlag(cmd, "no-summarize", "MYCELICA_RETRY_NO_SUMMARIZE", defaults.NoSummarize, retryNoSummarize)
+		verbose := ResolveBoolFlag(cmd, "verbose", "MYCELICA_RETRY_VERBOSE", defaults.Verbose, retryVerbose)
+		quiet := ResolveBoolFlag(cmd, "quiet", "MYCELICA_RETRY_QUIET", defaults.Quiet, retryQuiet)
+		jsonOut := ResolveBoolFlag(cmd, "json", "MYCELICA_RETRY_JSON", defaults.JSON, retryJSON)
+		outputDir := defaults.OutputDir
+		if outputDir == "" {
+			outputDir = "/tmp/spore/"
+		}
+
+		if !quiet && !jsonOut {
 			fmt.Printf("[retry] Original run: %s (%s)\n", shortID, node.Title)
 			fmt.Printf("[retry] Task: %s\n", task)
-			fmt.Printf("[retry] Retrying with max_bounces=%d, max_turns=%d\n", retryMaxBounces, retryMaxTurns)
+			fmt.Printf("[retry] Retrying with max_bounces=%d, max_turns=%d\n", maxBounces, maxTurns)
 		}
 
 		config := orchestrate.OrchestrationConfig{
 			TaskFile:    orchestrate.DefaultTaskFileConfig(),
-			MaxBounces:  retryMaxBounces,
-			MaxTurns:    retryMaxTurns,
-			CoderModel:  retryCoderModel,
-			OutputDir:   "/tmp/spore/",
-			Experiment:  retryExperiment,
-			NoSummarize: retryNoSummarize,
-			Verbose:     retryVerbose,
-			Quiet:       retryQuiet,
-			JSON:        retryJSON,
+			MaxBounces:  maxBounces,
+			MaxTurns:    maxTurns,
+			CoderModel:  coderModel,
+			OutputDir:   outputDir,
+			Experiment:  experiment,
+			NoSummarize: noSummarize,
+			Verbose:     verbose,
+			Quiet:       quiet,
+			JSON:        jsonOut,
 		}
 
-		result, err := orchestrate.RunOrchestration(d, task, config)
+		result, err := orchestrate.RunOrchestration(ctx, d, task, config)
 
-		if retryJSON && result != nil {
+		if jsonOut && result != nil {
 			enc := json.NewEncoder(os.Stdout)
 			enc.SetIndent("", "  ")
 			_ = enc.Encode(result)
@@ -82,7 +107,7 @@ var retryCmd = &cobra.Command{
 			return nil
 		}
 
-		if result != nil && !retryQuiet {
+		if result != nil && !quiet {
 			shortRunID := result.RunID
 			if len(shortRunID) > 8 {
 				shortRunID = shortRunID[:8]
@@ -1,12 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
 	"os"
+	"os/signal"
 	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
 
 	"github.com/spf13/cobra"
 	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/orchestrate"
 )
 
 var dbPath string
@@ -16,7 +22,20 @@ var rootCmd = &cobra.Command{
 	Short: "Spore graph analysis and orchestration",
 }
 
+// shutdownDrainTimeout bounds how long Execute's ShutdownCoordinator hook
+// waits for each tracked Claude subprocess's graceful SIGTERM before
+// escalating to SIGKILL itself.
+const shutdownDrainTimeout = 15 * time.Second
+
 func Execute() {
+	// Hooked once here (the closest thing this CLI has to main) so every
+	// orchestrate.RegisterProcess call -- coder/verifier/summarizer
+	// subprocesses and their shims -- gets drained on SIGINT/SIGTERM instead
+	// of each call site fire-and-forget-killing its own.
+	go func() {
+		_ = orchestrate.WaitForDeath([]os.Signal{os.Interrupt, syscall.SIGTERM}, shutdownDrainTimeout)
+	}()
+
 	if err := rootCmd.Execute(); err != nil {
 		fmt.Fprintln(os.Stderr, err)
 		os.Exit(1)
@@ -73,6 +92,55 @@ func DiscoverDB() (string, error) {
 }
 
 // OpenDatabase discovers and opens the database
+// SignalContext derives a context from cmd's own context that's cancelled on
+// SIGINT/SIGTERM, so Ctrl-C on a stuck or multi-minute command (retry,
+// analyze) returns partial results instead of leaving a zombie subprocess.
+// Callers must defer the returned CancelFunc.
+func SignalContext(cmd *cobra.Command) (context.Context, context.CancelFunc) {
+	return signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+}
+
+// ResolveIntFlag implements the flag > env > fallback precedence (restic's
+// RESTIC_HOST pattern): if flagName was explicitly set on cmd, current (the
+// flag's parsed value) wins; otherwise envVar is consulted; otherwise
+// fallback (typically a persisted profile value already merged with the
+// built-in default via OrchestrationConfig.LoadDefaults) is used.
+func ResolveIntFlag(cmd *cobra.Command, flagName, envVar string, fallback, current int) int {
+	if cmd.Flags().Changed(flagName) {
+		return current
+	}
+	if v := os.Getenv(envVar); v != "" {
+		if n, err := strconv.Atoi(v); err == nil {
+			return n
+		}
+	}
+	return fallback
+}
+
+// ResolveStringFlag is ResolveIntFlag for string-valued flags.
+func ResolveStringFlag(cmd *cobra.Command, flagName, envVar, fallback, current string) string {
+	if cmd.Flags().Changed(flagName) {
+		return current
+	}
+	if v := os.Getenv(envVar); v != "" {
+		return v
+	}
+	return fallback
+}
+
+// ResolveBoolFlag is ResolveIntFlag for bool-valued flags.
+func ResolveBoolFlag(cmd *cobra.Command, flagName, envVar string, fallback, current bool) bool {
+	if cmd.Flags().Changed(flagName) {
+		return current
+	}
+	if v := os.Getenv(envVar); v != "" {
+		if b, err := strconv.ParseBool(v); err == nil {
+			return b
+		}
+	}
+	return fallback
+}
+
 func OpenDatabase() (*db.DB, error) {
 	path, err := DiscoverDB()
 	if err != nil {
@@ -0,0 +1,22 @@
+package cmd
+
+import (
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/orchestrate"
+)
+
+// shimCmd is the re-exec entry point Detached ClaudeConfig runs spawn
+// themselves under (see orchestrate.spawnShimmed). It's not meant to be
+// invoked by a person, so it's hidden from `spore --help`.
+var shimCmd = &cobra.Command{
+	Use:    orchestrate.ShimSubcommand + " <run-id>",
+	Hidden: true,
+	Args:   cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		return orchestrate.RunShim(args[0])
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(shimCmd)
+}
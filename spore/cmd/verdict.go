@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/orchestrate"
+)
+
+var verdictDryRunMatchers string
+
+var verdictDryRunCmd = &cobra.Command{
+	Use:   "verdict-dry-run <transcript-file>",
+	Short: "Show which verdict matcher fires against a verifier transcript",
+	Long:  "Loads a --matchers YAML file and runs each matcher in order against the given verifier transcript (JSON), printing the matched path/value and which one (if any) fired.",
+	Args:  cobra.ExactArgs(1),
+	RunE: func(cmd *cobra.Command, args []string) error {
+		if verdictDryRunMatchers == "" {
+			return fmt.Errorf("--matchers is required")
+		}
+		matchers, err := orchestrate.LoadVerdictMatchers(verdictDryRunMatchers)
+		if err != nil {
+			return fmt.Errorf("loading verdict matchers: %w", err)
+		}
+
+		transcript, err := os.ReadFile(args[0])
+		if err != nil {
+			return fmt.Errorf("reading transcript: %w", err)
+		}
+
+		verdict, attempts := orchestrate.EvaluateVerdictMatchers(matchers, string(transcript))
+		for _, a := range attempts {
+			status := "no match"
+			if a.Err != "" {
+				status = "error: " + a.Err
+			} else if a.Matched {
+				status = fmt.Sprintf("MATCHED -> %s", a.Verdict)
+			}
+			fmt.Printf("  %-20s value=%-20q %s\n", a.Matcher, a.Value, status)
+		}
+
+		if verdict == nil {
+			fmt.Println("\nNo matcher fired.")
+			return nil
+		}
+		fmt.Printf("\nFired: %s (confidence %.2f)\n", verdict.Verdict, verdict.Confidence)
+		return nil
+	},
+}
+
+func init() {
+	verdictDryRunCmd.Flags().StringVar(&verdictDryRunMatchers, "matchers", "", "Path to a YAML file of JSONPath-based verdict matchers")
+	rootCmd.AddCommand(verdictDryRunCmd)
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+	"mycelica/spore/internal/graph"
+)
+
+var (
+	vizFormat    string
+	vizFocus     string
+	vizFocusHops int
+	vizOverlay   string
+	vizOut       string
+	vizHub       int
+)
+
+var vizCmd = &cobra.Command{
+	Use:   "viz",
+	Short: "Render the graph as Graphviz DOT, SVG, or a self-contained HTML bundle",
+	Long: "Renders the current graph for visual debugging. --overlay bridges highlights " +
+		"articulation points and bridge edges from ComputeBridges; --overlay community clusters " +
+		"nodes by ComputeCommunities instead of by folder region; --overlay staleness colors stale " +
+		"nodes from ComputeStaleness. --focus restricts rendering to a node's k-hop neighborhood. " +
+		"--format svg and --format html shell out to Graphviz's `dot` binary, which must be " +
+		"installed separately.",
+	RunE: func(cmd *cobra.Command, args []string) error {
+		d, err := OpenDatabase()
+		if err != nil {
+			return err
+		}
+		defer d.Close()
+
+		snap, err := graph.SnapshotFromDB(d)
+		if err != nil {
+			return fmt.Errorf("loading graph: %w", err)
+		}
+
+		opts := graph.RenderOptions{
+			HubThreshold: vizHub,
+			ClusterBy:    "region",
+			Focus:        vizFocus,
+			FocusHops:    vizFocusHops,
+		}
+
+		var overlay *graph.BridgeReport
+		switch vizOverlay {
+		case "", "bridges":
+			overlay = graph.ComputeBridges(snap)
+		case "community":
+			opts.ClusterBy = "community"
+			opts.Communities = graph.ComputeCommunities(snap, graph.DefaultCommunityConfig())
+		case "staleness":
+			opts.Staleness = graph.ComputeStaleness(snap, 60)
+		default:
+			return fmt.Errorf("unknown --overlay %q: want bridges, community, or staleness", vizOverlay)
+		}
+
+		var output []byte
+		switch vizFormat {
+		case "", "dot":
+			output, err = graph.RenderDOT(snap, overlay, opts)
+		case "svg":
+			var dot []byte
+			dot, err = graph.RenderDOT(snap, overlay, opts)
+			if err == nil {
+				output, err = graph.RenderSVG(dot)
+			}
+		case "html":
+			output, err = graph.RenderHTMLZip(snap, overlay, opts)
+		default:
+			return fmt.Errorf("unknown --format %q: want dot, svg, or html", vizFormat)
+		}
+		if err != nil {
+			return fmt.Errorf("rendering %s: %w", vizFormat, err)
+		}
+
+		if vizOut == "" || vizOut == "-" {
+			_, err = os.Stdout.Write(output)
+			return err
+		}
+		return os.WriteFile(vizOut, output, 0o644)
+	},
+}
+
+func init() {
+	vizCmd.Flags().StringVar(&vizFormat, "format", "dot", "Output format: dot, svg, or html (a zip bundle)")
+	vizCmd.Flags().StringVar(&vizFocus, "focus", "", "Restrict rendering to this node's k-hop neighborhood")
+	vizCmd.Flags().IntVar(&vizFocusHops, "focus-hops", 2, "Neighborhood radius for --focus")
+	vizCmd.Flags().StringVar(&vizOverlay, "overlay", "bridges", "Overlay: bridges, community, or staleness")
+	vizCmd.Flags().StringVar(&vizOut, "out", "", "Output file path (default: stdout)")
+	vizCmd.Flags().IntVar(&vizHub, "hub-threshold", 15, "Minimum degree to enlarge a node as a hub (0 disables)")
+	rootCmd.AddCommand(vizCmd)
+}
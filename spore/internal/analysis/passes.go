@@ -0,0 +1,105 @@
+package analysis
+
+import (
+	"fmt"
+	"sort"
+
+	"mycelica/spore/internal/graph"
+)
+
+// StructurallyCriticalEdgeType is the edge_type StructurallyCriticalPass
+// writes: a directed edge from a bridge edge's target back to its source,
+// flagging that removing the underlying edge would disconnect the graph.
+const StructurallyCriticalEdgeType = "structurally_critical_via"
+
+// StructurallyCriticalPass materializes graph.ComputeBridges' BridgeEdges as
+// synthesized structurally_critical_via edges, one per bridge, so a
+// traversal can recognize (or, via --edge-types, deliberately avoid) a path
+// that runs through a single point of failure.
+func StructurallyCriticalPass() Pass {
+	return Pass{
+		Name:     "structurally_critical",
+		EdgeType: StructurallyCriticalEdgeType,
+		Compute: func(snap *graph.GraphSnapshot) []SynthesizedEdge {
+			report := graph.ComputeBridges(snap)
+			out := make([]SynthesizedEdge, 0, len(report.BridgeEdges))
+			for _, be := range report.BridgeEdges {
+				out = append(out, SynthesizedEdge{
+					SourceID: be.TargetID,
+					TargetID: be.SourceID,
+					Reason:   "bridge edge: removing it disconnects the graph",
+				})
+			}
+			return out
+		},
+	}
+}
+
+// SameCommunityEdgeType is the edge_type SameCommunityPass writes.
+const SameCommunityEdgeType = "same_community_as"
+
+// SameCommunityPass materializes graph.ComputeCommunities' Louvain output as
+// synthesized same_community_as edges. Rather than the O(n^2) pairwise edges
+// a full clique per community would need, it stars each community: every
+// non-representative member gets one edge to that community's
+// lowest-ID member, keeping the synthesized edge count linear in node count
+// while still letting a traversal reach (or exclude) "the rest of my
+// community" in one hop via the representative.
+func SameCommunityPass(config *graph.CommunityConfig) Pass {
+	return Pass{
+		Name:     "same_community",
+		EdgeType: SameCommunityEdgeType,
+		Compute: func(snap *graph.GraphSnapshot) []SynthesizedEdge {
+			report := graph.ComputeCommunities(snap, config)
+
+			members := make(map[int][]string)
+			for _, a := range report.Assignments {
+				members[a.Community] = append(members[a.Community], a.ID)
+			}
+
+			var out []SynthesizedEdge
+			for community, ids := range members {
+				if len(ids) < 2 {
+					continue
+				}
+				sort.Strings(ids)
+				rep := ids[0]
+				for _, id := range ids[1:] {
+					out = append(out, SynthesizedEdge{
+						SourceID: id,
+						TargetID: rep,
+						Reason:   fmt.Sprintf("same Louvain community (%d)", community),
+					})
+				}
+			}
+			return out
+		},
+	}
+}
+
+// SupersededSummaryEdgeType is the edge_type SupersededSummaryPass writes.
+const SupersededSummaryEdgeType = "superseded_summary_of"
+
+// SupersededSummaryPass materializes graph.ComputeStaleness's StaleSummaries
+// -- a "summarizes" edge whose target was updated after the summary was
+// written -- as synthesized superseded_summary_of edges, so retrieval can
+// recognize a summary node as outdated independent of the summarizes edge
+// itself still pointing at the (now stale) summary.
+func SupersededSummaryPass(staleDays int64) Pass {
+	return Pass{
+		Name:     "superseded_summary",
+		EdgeType: SupersededSummaryEdgeType,
+		Compute: func(snap *graph.GraphSnapshot) []SynthesizedEdge {
+			report := graph.ComputeStaleness(snap, staleDays)
+			out := make([]SynthesizedEdge, 0, len(report.StaleSummaries))
+			for _, ss := range report.StaleSummaries {
+				out = append(out, SynthesizedEdge{
+					SourceID: ss.SummaryNodeID,
+					TargetID: ss.TargetNodeID,
+					Reason:   fmt.Sprintf("target updated %dd after this summary", ss.DriftDays),
+				})
+			}
+			return out
+		},
+	}
+}
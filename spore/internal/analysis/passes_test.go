@@ -0,0 +1,102 @@
+package analysis
+
+import (
+	"fmt"
+	"testing"
+
+	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/graph"
+)
+
+func quickSnapshot(nodeIDs []string, edges [][2]string) *graph.GraphSnapshot {
+	now := int64(1_700_000_000_000)
+	var nodes []*graph.NodeInfo
+	for _, id := range nodeIDs {
+		nodes = append(nodes, &graph.NodeInfo{
+			ID: id, Title: "Node " + id, NodeType: "page",
+			CreatedAt: now, UpdatedAt: now, Depth: 0,
+		})
+	}
+	var edgeInfos []graph.EdgeInfo
+	for i, e := range edges {
+		edgeInfos = append(edgeInfos, graph.EdgeInfo{
+			ID: fmt.Sprintf("e%d", i), Source: e[0], Target: e[1],
+			EdgeType: "related", CreatedAt: now,
+		})
+	}
+	return graph.NewSnapshot(nodes, edgeInfos)
+}
+
+func TestStructurallyCriticalPass_OneBridgeOneEdge(t *testing.T) {
+	// A-B-C: the two edges are both bridges.
+	snap := quickSnapshot([]string{"A", "B", "C"}, [][2]string{{"A", "B"}, {"B", "C"}})
+	edges := StructurallyCriticalPass().Compute(snap)
+	if len(edges) != 2 {
+		t.Fatalf("expected 2 synthesized edges (one per bridge), got %d: %+v", len(edges), edges)
+	}
+}
+
+func TestStructurallyCriticalPass_CycleHasNoBridges(t *testing.T) {
+	snap := quickSnapshot([]string{"A", "B", "C"}, [][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}})
+	edges := StructurallyCriticalPass().Compute(snap)
+	if len(edges) != 0 {
+		t.Errorf("a triangle has no bridges, expected 0 synthesized edges, got %d", len(edges))
+	}
+}
+
+func TestSameCommunityPass_StarsEachCommunity(t *testing.T) {
+	// Two disjoint triangles: each should become its own community, starred
+	// at its lowest-ID member.
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D", "E", "F"},
+		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}, {"D", "E"}, {"E", "F"}, {"F", "D"}},
+	)
+	edges := SameCommunityPass(&graph.CommunityConfig{Resolution: 1.0, FragileThreshold: 2}).Compute(snap)
+	if len(edges) != 4 {
+		t.Fatalf("expected 4 star edges (2 per 3-node community), got %d: %+v", len(edges), edges)
+	}
+	for _, e := range edges {
+		if e.TargetID != "A" && e.TargetID != "D" {
+			t.Errorf("expected every star edge to target its community's lowest-ID member (A or D), got target %q", e.TargetID)
+		}
+	}
+}
+
+func TestSupersededSummaryPass_DetectsDrift(t *testing.T) {
+	now := int64(1_700_000_000_000)
+	summary := &graph.NodeInfo{ID: "s", Title: "Summary", NodeType: "page", CreatedAt: now, UpdatedAt: now}
+	target := &graph.NodeInfo{ID: "t", Title: "Target", NodeType: "page", CreatedAt: now, UpdatedAt: now + 10*86_400_000}
+	snap := graph.NewSnapshot(
+		[]*graph.NodeInfo{summary, target},
+		[]graph.EdgeInfo{{ID: "e0", Source: "s", Target: "t", EdgeType: "summarizes", CreatedAt: now}},
+	)
+	edges := SupersededSummaryPass(60).Compute(snap)
+	if len(edges) != 1 {
+		t.Fatalf("expected 1 superseded-summary edge, got %d: %+v", len(edges), edges)
+	}
+	if edges[0].SourceID != "s" || edges[0].TargetID != "t" {
+		t.Errorf("expected s -> t, got %+v", edges[0])
+	}
+}
+
+func TestIsSynthesized(t *testing.T) {
+	synth := `{"synthesized":true,"pass":"structurally_critical"}`
+	notSynth := `{"note":"hand-authored"}`
+	cases := []struct {
+		name     string
+		metadata *string
+		want     bool
+	}{
+		{"synthesized metadata", &synth, true},
+		{"unrelated metadata", &notSynth, false},
+		{"nil metadata", nil, false},
+	}
+	for _, c := range cases {
+		t.Run(c.name, func(t *testing.T) {
+			got := isSynthesized(db.Edge{Metadata: c.metadata})
+			if got != c.want {
+				t.Errorf("isSynthesized(%v) = %v, want %v", c.metadata, got, c.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,158 @@
+// Package analysis materializes graph.Compute* findings back into the
+// store as synthesized edges, so structure that's currently only printed by
+// `spore analyze` (articulation points, Louvain communities, drifted
+// summaries) can actually shape `spore context-for-task`'s traversal.
+package analysis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/graph"
+)
+
+// SynthesizedEdge is one edge a Pass wants materialized. SourceID/TargetID
+// must name existing nodes; PostProcess does not validate this itself and
+// relies on db.CreateEdge to surface a failure per-edge instead.
+type SynthesizedEdge struct {
+	SourceID   string
+	TargetID   string
+	Reason     string
+	Confidence float64
+}
+
+// Pass computes one kind of synthesized edge from a graph snapshot. Name
+// identifies the pass in PostStats and in each edge's Metadata; EdgeType is
+// the edge_type written to the store and is what DeleteTransitEdges matches
+// on to wipe a pass's prior output.
+type Pass struct {
+	Name     string
+	EdgeType string
+	Compute  func(snap *graph.GraphSnapshot) []SynthesizedEdge
+}
+
+// PassStat is one pass's outcome within a PostProcess run.
+type PassStat struct {
+	Created int `json:"created"`
+	Failed  int `json:"failed"`
+}
+
+// PostStats summarizes a PostProcess run across all passes.
+type PostStats struct {
+	PassStats    map[string]*PassStat `json:"pass_stats"`
+	EdgesCreated int                  `json:"edges_created"`
+	EdgesFailed  int                  `json:"edges_failed"`
+}
+
+// synthesizedMetadata marshals the provenance Metadata every synthesized
+// edge carries. There's no dedicated "synthesized" column on the edges
+// table (core edge writes all go through mycelica-cli, which owns the
+// schema), so provenance rides in the existing free-form Metadata JSON
+// field instead, the same place ad hoc per-edge data already lives.
+func synthesizedMetadata(passName string) string {
+	meta := struct {
+		Synthesized bool   `json:"synthesized"`
+		Pass        string `json:"pass"`
+	}{true, passName}
+	b, _ := json.Marshal(meta) // fixed shape, never fails
+	return string(b)
+}
+
+// PostProcess loads the current graph, runs each pass over it, and writes
+// every resulting SynthesizedEdge via db.CreateEdge so embedding generation,
+// FTS indexing, and hierarchy processing stay consistent with hand-created
+// edges. ctx is checked between passes (not per-edge, since CreateEdge shells
+// out and isn't itself cancellable); a cancelled ctx stops before the next
+// pass and returns the stats gathered so far alongside ctx.Err().
+//
+// A single edge's CreateEdge failure doesn't abort the run -- it's counted
+// in PassStats[name].Failed and PostStats.EdgesFailed, mirroring how
+// repairIntegrity (cmd/check.go) keeps going past a per-edge delete failure.
+func PostProcess(ctx context.Context, d *db.DB, passes ...Pass) (*PostStats, error) {
+	snap, err := graph.SnapshotFromDB(d)
+	if err != nil {
+		return nil, fmt.Errorf("loading graph: %w", err)
+	}
+
+	stats := &PostStats{PassStats: make(map[string]*PassStat, len(passes))}
+	for _, p := range passes {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+
+		ps := &PassStat{}
+		for _, e := range p.Compute(snap) {
+			_, err := d.CreateEdge(e.SourceID, e.TargetID, p.EdgeType, db.CreateEdgeOpts{
+				Reason:     e.Reason,
+				Confidence: e.Confidence,
+				Metadata:   synthesizedMetadata(p.Name),
+			})
+			if err != nil {
+				ps.Failed++
+				continue
+			}
+			ps.Created++
+		}
+		stats.PassStats[p.Name] = ps
+		stats.EdgesCreated += ps.Created
+		stats.EdgesFailed += ps.Failed
+	}
+	return stats, nil
+}
+
+// DeleteStats summarizes a DeleteTransitEdges run.
+type DeleteStats struct {
+	Deleted       int      `json:"deleted"`
+	FailedDeletes []string `json:"failed_deletes,omitempty"`
+}
+
+// DeleteTransitEdges deletes every existing edge whose edge_type is one of
+// kinds and whose Metadata marks it synthesized (see synthesizedMetadata),
+// so a fresh PostProcess run doesn't pile up duplicate edges alongside the
+// prior run's output. Hand-created edges that happen to share one of kinds'
+// edge_type are left alone.
+//
+// This is not one atomic transaction: like DeleteEdge generally, each delete
+// shells out to mycelica-cli individually, so a run interrupted partway
+// through leaves some of the matched edges deleted and some not. A per-edge
+// failure is collected in FailedDeletes rather than aborting the rest, the
+// same trade-off cmd/check.go's repairIntegrity makes for bulk edge deletes.
+func DeleteTransitEdges(d *db.DB, kinds ...string) (*DeleteStats, error) {
+	allowed := make(map[string]bool, len(kinds))
+	for _, k := range kinds {
+		allowed[k] = true
+	}
+
+	edges, err := d.AllEdges()
+	if err != nil {
+		return nil, fmt.Errorf("loading edges: %w", err)
+	}
+
+	stats := &DeleteStats{}
+	for _, e := range edges {
+		if !allowed[e.EdgeType] || !isSynthesized(e) {
+			continue
+		}
+		if err := d.DeleteEdge(e.ID); err != nil {
+			stats.FailedDeletes = append(stats.FailedDeletes, e.ID)
+			continue
+		}
+		stats.Deleted++
+	}
+	return stats, nil
+}
+
+func isSynthesized(e db.Edge) bool {
+	if e.Metadata == nil {
+		return false
+	}
+	var meta struct {
+		Synthesized bool `json:"synthesized"`
+	}
+	if err := json.Unmarshal([]byte(*e.Metadata), &meta); err != nil {
+		return false
+	}
+	return meta.Synthesized
+}
@@ -0,0 +1,174 @@
+package db
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Backend is the thing that actually performs node/edge mutations on behalf
+// of a DB. The default, cliBackend, forks a fresh mycelica-cli process per
+// call. daemonBackend (backend_daemon.go) instead talks to a long-lived
+// "mycelica-cli serve" process over a Unix socket, avoiding the per-call
+// process startup, SQLite open, and embedding-model warmup.
+type Backend interface {
+	CreateNode(title string, opts CreateNodeOpts) (string, error)
+	CreateEdge(sourceID, targetID, edgeType string, opts CreateEdgeOpts) (string, error)
+	DeleteNode(id string) error
+	DeleteEdge(id string) error
+	Close() error
+}
+
+// backendForEnv picks the Backend for a DB opened against dbPath, based on
+// MYCELICA_DB_MODE ("daemon" or "cli", default "cli"). If daemon mode is
+// requested but the daemon fails to start, it falls back to cliBackend
+// rather than failing OpenDB outright.
+func backendForEnv(dbPath string) Backend {
+	if strings.ToLower(os.Getenv("MYCELICA_DB_MODE")) != "daemon" {
+		return newCLIBackend(dbPath)
+	}
+
+	backend, err := NewDaemonBackend(dbPath)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[db] MYCELICA_DB_MODE=daemon requested but daemon failed to start: %v (falling back to cli backend)\n", err)
+		return newCLIBackend(dbPath)
+	}
+	return backend
+}
+
+// cliBackend is the original exec.Command-per-call implementation.
+type cliBackend struct {
+	dbPath string
+}
+
+func newCLIBackend(dbPath string) *cliBackend {
+	return &cliBackend{dbPath: dbPath}
+}
+
+func (b *cliBackend) CreateNode(title string, opts CreateNodeOpts) (string, error) {
+	binary, err := FindCLIBinary()
+	if err != nil {
+		return "", fmt.Errorf("finding CLI binary: %w", err)
+	}
+
+	args := []string{"node", "create", "--title", title, "--json", "--db", b.dbPath}
+
+	if opts.Content != "" {
+		args = append(args, "--content", opts.Content)
+	}
+	if opts.AgentID != "" {
+		args = append(args, "--agent-id", opts.AgentID)
+	}
+	if opts.NodeClass != "" {
+		args = append(args, "--node-class", opts.NodeClass)
+	}
+	if opts.MetaType != "" {
+		args = append(args, "--meta-type", opts.MetaType)
+	}
+	if opts.Source != "" {
+		args = append(args, "--source", opts.Source)
+	}
+	if opts.Author != "" {
+		args = append(args, "--author", opts.Author)
+	}
+
+	cmd := exec.Command(binary, args...)
+	out, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("creating node: %w (stderr: %s)", err, stderr)
+	}
+
+	return parseCreatedID(out)
+}
+
+func (b *cliBackend) CreateEdge(sourceID, targetID, edgeType string, opts CreateEdgeOpts) (string, error) {
+	binary, err := FindCLIBinary()
+	if err != nil {
+		return "", fmt.Errorf("finding CLI binary: %w", err)
+	}
+
+	args := []string{
+		"spore", "create-edge",
+		"--from", sourceID,
+		"--to", targetID,
+		"--type", edgeType,
+		"--json", "--db", b.dbPath,
+	}
+
+	if opts.Content != "" {
+		args = append(args, "--content", opts.Content)
+	}
+	if opts.Reason != "" {
+		args = append(args, "--reason", opts.Reason)
+	}
+	if opts.Agent != "" {
+		args = append(args, "--agent", opts.Agent)
+	}
+	if opts.Confidence > 0 {
+		args = append(args, "--confidence", fmt.Sprintf("%.2f", opts.Confidence))
+	}
+	if opts.Metadata != "" {
+		args = append(args, "--metadata", opts.Metadata)
+	}
+	if opts.Supersedes != "" {
+		args = append(args, "--supersedes", opts.Supersedes)
+	}
+
+	cmd := exec.Command(binary, args...)
+	out, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return "", fmt.Errorf("creating edge: %w (stderr: %s)", err, stderr)
+	}
+
+	return parseCreatedID(out)
+}
+
+func (b *cliBackend) DeleteNode(id string) error {
+	binary, err := FindCLIBinary()
+	if err != nil {
+		return fmt.Errorf("finding CLI binary: %w", err)
+	}
+
+	cmd := exec.Command(binary, "node", "delete", id, "--db", b.dbPath)
+	out, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return fmt.Errorf("deleting node %s: %w (stderr: %s) (stdout: %s)", id, err, stderr, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+func (b *cliBackend) DeleteEdge(id string) error {
+	binary, err := FindCLIBinary()
+	if err != nil {
+		return fmt.Errorf("finding CLI binary: %w", err)
+	}
+
+	cmd := exec.Command(binary, "edge", "delete", id, "--db", b.dbPath)
+	out, err := cmd.Output()
+	if err != nil {
+		stderr := ""
+		if exitErr, ok := err.(*exec.ExitError); ok {
+			stderr = strings.TrimSpace(string(exitErr.Stderr))
+		}
+		return fmt.Errorf("deleting edge %s: %w (stderr: %s) (stdout: %s)", id, err, stderr, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// Close is a no-op: cliBackend has no persistent state to release.
+func (b *cliBackend) Close() error {
+	return nil
+}
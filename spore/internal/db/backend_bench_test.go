@@ -0,0 +1,81 @@
+package db
+
+import (
+	"fmt"
+	"testing"
+)
+
+// benchmarkCreateOpts builds distinct node titles per iteration so repeated
+// benchmark runs against a real database don't collide.
+func benchmarkNodeOpts(i int) (string, CreateNodeOpts) {
+	return fmt.Sprintf("bench-node-%d", i), CreateNodeOpts{
+		AgentID: "spore:bench",
+		Source:  "backend_bench_test.go",
+	}
+}
+
+// BenchmarkCreateNode_CLI measures the per-call cost of the cliBackend (one
+// mycelica-cli process fork/exec per node). Skipped when mycelica-cli or a
+// test database aren't available, matching writes_test.go's integration
+// test gating.
+func BenchmarkCreateNode_CLI(b *testing.B) {
+	if _, err := FindCLIBinary(); err != nil {
+		b.Skip("mycelica-cli not found")
+	}
+	dbPath := findTestDB(b)
+	if dbPath == "" {
+		b.Skip("database not found (.mycelica.db)")
+	}
+
+	d, err := OpenDB(dbPath)
+	if err != nil {
+		b.Fatalf("opening database: %v", err)
+	}
+	defer d.Close()
+	d.backend = newCLIBackend(dbPath)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		title, opts := benchmarkNodeOpts(i)
+		id, err := d.CreateNode(title, opts)
+		if err != nil {
+			b.Fatalf("CreateNode: %v", err)
+		}
+		d.DeleteNode(id)
+	}
+}
+
+// BenchmarkCreateNode_Daemon measures the same workload against a
+// daemonBackend, demonstrating the fork/exec + SQLite-open + embedding
+// warmup avoided per call. Skipped unless mycelica-cli supports "serve" --
+// not available in this Go-only tree/sandbox, so this benchmark documents
+// the intended A/B comparison rather than asserting a concrete speedup here.
+func BenchmarkCreateNode_Daemon(b *testing.B) {
+	dbPath := findTestDB(b)
+	if dbPath == "" {
+		b.Skip("database not found (.mycelica.db)")
+	}
+
+	backend, err := NewDaemonBackend(dbPath)
+	if err != nil {
+		b.Skipf("daemon backend unavailable: %v", err)
+	}
+	defer backend.Close()
+
+	d, err := OpenDB(dbPath)
+	if err != nil {
+		b.Fatalf("opening database: %v", err)
+	}
+	defer d.Close()
+	d.backend = backend
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		title, opts := benchmarkNodeOpts(i)
+		id, err := d.CreateNode(title, opts)
+		if err != nil {
+			b.Fatalf("CreateNode: %v", err)
+		}
+		d.DeleteNode(id)
+	}
+}
@@ -0,0 +1,278 @@
+package db
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// daemonReadyLine is the line mycelica-cli serve is expected to write to
+// stderr once its Unix socket is accepting connections.
+const daemonReadyLine = "mycelica-cli serve: ready"
+
+// daemonStartTimeout bounds how long StartDaemon waits for daemonReadyLine
+// before giving up and killing the subprocess.
+const daemonStartTimeout = 10 * time.Second
+
+// DaemonHandle is a running "mycelica-cli serve" subprocess.
+type DaemonHandle struct {
+	cmd        *exec.Cmd
+	SocketPath string
+}
+
+// StartDaemon spawns "mycelica-cli serve" against dbPath over a fresh Unix
+// socket and waits for it to report readiness on stderr. Companion to
+// FindCLIBinary, which it uses to locate the binary.
+func StartDaemon(ctx context.Context, dbPath string) (*DaemonHandle, error) {
+	binary, err := FindCLIBinary()
+	if err != nil {
+		return nil, fmt.Errorf("finding CLI binary: %w", err)
+	}
+
+	sockPath := filepath.Join(os.TempDir(), fmt.Sprintf("mycelica-daemon-%d-%s.sock", os.Getpid(), filepath.Base(dbPath)))
+	os.Remove(sockPath) // stale socket left behind by a prior crash
+
+	cmd := exec.CommandContext(ctx, binary, "serve", "--db", dbPath, "--socket", sockPath)
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, fmt.Errorf("attaching daemon stderr: %w", err)
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting daemon: %w", err)
+	}
+
+	ready := make(chan error, 1)
+	go func() {
+		scanner := bufio.NewScanner(stderr)
+		for scanner.Scan() {
+			if strings.Contains(scanner.Text(), daemonReadyLine) {
+				ready <- nil
+				return
+			}
+		}
+		ready <- fmt.Errorf("daemon exited before reporting ready (%v)", scanner.Err())
+	}()
+
+	select {
+	case err := <-ready:
+		if err != nil {
+			cmd.Process.Kill()
+			return nil, err
+		}
+	case <-time.After(daemonStartTimeout):
+		cmd.Process.Kill()
+		return nil, fmt.Errorf("timed out waiting for daemon ready signal after %s", daemonStartTimeout)
+	}
+
+	return &DaemonHandle{cmd: cmd, SocketPath: sockPath}, nil
+}
+
+// Close kills the daemon subprocess and removes its socket file.
+func (h *DaemonHandle) Close() error {
+	if h.cmd.Process != nil {
+		h.cmd.Process.Kill()
+	}
+	h.cmd.Wait()
+	os.Remove(h.SocketPath)
+	return nil
+}
+
+// daemonRequest/daemonResponse are length-prefixed JSON messages exchanged
+// over the daemon's Unix socket: a 4-byte big-endian length, then that many
+// bytes of JSON.
+type daemonRequest struct {
+	Method string      `json:"method"` // "node.create", "edge.create", "node.delete", "edge.delete"
+	Params interface{} `json:"params,omitempty"`
+}
+
+type daemonResponse struct {
+	Result json.RawMessage `json:"result,omitempty"`
+	Error  string          `json:"error,omitempty"`
+}
+
+func writeFrame(w io.Writer, payload []byte) error {
+	var lenBuf [4]byte
+	binary.BigEndian.PutUint32(lenBuf[:], uint32(len(payload)))
+	if _, err := w.Write(lenBuf[:]); err != nil {
+		return err
+	}
+	_, err := w.Write(payload)
+	return err
+}
+
+func readFrame(r io.Reader) ([]byte, error) {
+	var lenBuf [4]byte
+	if _, err := io.ReadFull(r, lenBuf[:]); err != nil {
+		return nil, err
+	}
+	buf := make([]byte, binary.BigEndian.Uint32(lenBuf[:]))
+	if _, err := io.ReadFull(r, buf); err != nil {
+		return nil, err
+	}
+	return buf, nil
+}
+
+// daemonBackend talks to a long-lived mycelica-cli serve process over a
+// Unix socket instead of forking a fresh process per call. On a connection
+// failure it respawns the daemon once and retries the call before giving up.
+type daemonBackend struct {
+	mu     sync.Mutex
+	dbPath string
+	handle *DaemonHandle
+	conn   net.Conn
+}
+
+// NewDaemonBackend starts the daemon for dbPath and connects to its socket.
+func NewDaemonBackend(dbPath string) (Backend, error) {
+	b := &daemonBackend{dbPath: dbPath}
+	if err := b.respawn(context.Background()); err != nil {
+		return nil, err
+	}
+	return b, nil
+}
+
+// respawn kills any existing daemon/connection and starts a fresh one.
+func (b *daemonBackend) respawn(ctx context.Context) error {
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+	if b.handle != nil {
+		b.handle.Close()
+		b.handle = nil
+	}
+
+	handle, err := StartDaemon(ctx, b.dbPath)
+	if err != nil {
+		return err
+	}
+	conn, err := net.Dial("unix", handle.SocketPath)
+	if err != nil {
+		handle.Close()
+		return fmt.Errorf("connecting to daemon socket: %w", err)
+	}
+	b.handle = handle
+	b.conn = conn
+	return nil
+}
+
+// call sends method/params and decodes the result into result (if non-nil).
+// On any connection-level failure it respawns the daemon once and retries.
+func (b *daemonBackend) call(method string, params, result interface{}) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	attempt := func() error {
+		if b.conn == nil {
+			if err := b.respawn(context.Background()); err != nil {
+				return err
+			}
+		}
+
+		reqPayload, err := json.Marshal(daemonRequest{Method: method, Params: params})
+		if err != nil {
+			return fmt.Errorf("encoding %s request: %w", method, err)
+		}
+		if err := writeFrame(b.conn, reqPayload); err != nil {
+			b.conn = nil
+			return err
+		}
+		respPayload, err := readFrame(b.conn)
+		if err != nil {
+			b.conn = nil
+			return err
+		}
+
+		var resp daemonResponse
+		if err := json.Unmarshal(respPayload, &resp); err != nil {
+			return fmt.Errorf("decoding %s response: %w", method, err)
+		}
+		if resp.Error != "" {
+			return fmt.Errorf("%s: %s", method, resp.Error)
+		}
+		if result != nil && len(resp.Result) > 0 {
+			if err := json.Unmarshal(resp.Result, result); err != nil {
+				return fmt.Errorf("decoding %s result: %w", method, err)
+			}
+		}
+		return nil
+	}
+
+	if err := attempt(); err != nil {
+		return attempt() // b.conn was cleared above; this respawns and retries once
+	}
+	return nil
+}
+
+func (b *daemonBackend) CreateNode(title string, opts CreateNodeOpts) (string, error) {
+	params := struct {
+		Title string `json:"title"`
+		CreateNodeOpts
+	}{Title: title, CreateNodeOpts: opts}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := b.call("node.create", params, &result); err != nil {
+		return "", err
+	}
+	return result.ID, nil
+}
+
+func (b *daemonBackend) CreateEdge(sourceID, targetID, edgeType string, opts CreateEdgeOpts) (string, error) {
+	params := struct {
+		From string `json:"from"`
+		To   string `json:"to"`
+		Type string `json:"type"`
+		CreateEdgeOpts
+	}{From: sourceID, To: targetID, Type: edgeType, CreateEdgeOpts: opts}
+
+	var result struct {
+		ID string `json:"id"`
+	}
+	if err := b.call("edge.create", params, &result); err != nil {
+		return "", err
+	}
+	return result.ID, nil
+}
+
+func (b *daemonBackend) DeleteNode(id string) error {
+	params := struct {
+		ID string `json:"id"`
+	}{ID: id}
+	return b.call("node.delete", params, nil)
+}
+
+func (b *daemonBackend) DeleteEdge(id string) error {
+	params := struct {
+		ID string `json:"id"`
+	}{ID: id}
+	return b.call("edge.delete", params, nil)
+}
+
+// Close tears down the daemon subprocess and its socket connection.
+func (b *daemonBackend) Close() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.conn != nil {
+		b.conn.Close()
+		b.conn = nil
+	}
+	if b.handle != nil {
+		err := b.handle.Close()
+		b.handle = nil
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,54 @@
+package db
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteReadFrame_RoundTrip(t *testing.T) {
+	var buf bytes.Buffer
+	payload := []byte(`{"method":"node.create","params":{"title":"x"}}`)
+
+	if err := writeFrame(&buf, payload); err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+
+	got, err := readFrame(&buf)
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+	if !bytes.Equal(got, payload) {
+		t.Errorf("got %q, want %q", got, payload)
+	}
+}
+
+func TestWriteReadFrame_Empty(t *testing.T) {
+	var buf bytes.Buffer
+	if err := writeFrame(&buf, nil); err != nil {
+		t.Fatalf("writeFrame: %v", err)
+	}
+	got, err := readFrame(&buf)
+	if err != nil {
+		t.Fatalf("readFrame: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("got %q, want empty", got)
+	}
+}
+
+func TestBackendForEnv_DefaultsToCLI(t *testing.T) {
+	backend := backendForEnv("/tmp/does-not-matter.db")
+	if _, ok := backend.(*cliBackend); !ok {
+		t.Errorf("got %T, want *cliBackend", backend)
+	}
+}
+
+func TestBackendForEnv_DaemonFallsBackWhenUnavailable(t *testing.T) {
+	t.Setenv("MYCELICA_DB_MODE", "daemon")
+	t.Setenv("MYCELICA_CLI", "/nonexistent/path/mycelica-cli")
+
+	backend := backendForEnv("/tmp/does-not-matter.db")
+	if _, ok := backend.(*cliBackend); !ok {
+		t.Errorf("got %T, want fallback to *cliBackend when daemon start fails", backend)
+	}
+}
@@ -0,0 +1,92 @@
+package db
+
+import "fmt"
+
+// BlameLine is one persisted line attribution, as computed by
+// orchestrate.BlameRun: which commit last touched a line, and — when that
+// commit came from a tracked orchestration run — which run and agent role
+// wrote it.
+type BlameLine struct {
+	File      string
+	Line      int
+	RunID     string
+	AgentRole string
+	CommitSHA string
+}
+
+func (d *DB) ensureBlameSchema() error {
+	_, err := d.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS blame (
+			file       TEXT NOT NULL,
+			line       INTEGER NOT NULL,
+			run_id     TEXT NOT NULL DEFAULT '',
+			agent_role TEXT NOT NULL DEFAULT '',
+			commit_sha TEXT NOT NULL,
+			PRIMARY KEY (file, line)
+		);
+	`)
+	return err
+}
+
+// SaveBlame replaces the persisted attribution for every (file, line) pair
+// in lines, so repeated BlameRun calls for the same files stay current
+// rather than accumulating stale rows.
+func (d *DB) SaveBlame(lines []BlameLine) error {
+	if err := d.ensureBlameSchema(); err != nil {
+		return fmt.Errorf("ensuring blame schema: %w", err)
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO blame (file, line, run_id, agent_role, commit_sha)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT (file, line) DO UPDATE SET
+			run_id = excluded.run_id,
+			agent_role = excluded.agent_role,
+			commit_sha = excluded.commit_sha
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, l := range lines {
+		if _, err := stmt.Exec(l.File, l.Line, l.RunID, l.AgentRole, l.CommitSHA); err != nil {
+			return fmt.Errorf("saving blame row for %s:%d: %w", l.File, l.Line, err)
+		}
+	}
+	return tx.Commit()
+}
+
+// BlameForFile returns every persisted line attribution for file, ordered by
+// line number, for the blame_line MCP tool (see orchestrate.blameLineTool)
+// and operator queries of "which agent wrote this bug".
+func (d *DB) BlameForFile(file string) ([]BlameLine, error) {
+	if err := d.ensureBlameSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring blame schema: %w", err)
+	}
+
+	rows, err := d.conn.Query(`
+		SELECT file, line, run_id, agent_role, commit_sha
+		FROM blame WHERE file = ? ORDER BY line
+	`, file)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []BlameLine
+	for rows.Next() {
+		var l BlameLine
+		if err := rows.Scan(&l.File, &l.Line, &l.RunID, &l.AgentRole, &l.CommitSHA); err != nil {
+			return nil, err
+		}
+		result = append(result, l)
+	}
+	return result, rows.Err()
+}
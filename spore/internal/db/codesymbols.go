@@ -0,0 +1,270 @@
+package db
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// identifierPattern matches Go/Rust/Python-style identifiers: a letter or
+// underscore followed by letters, digits, or underscores. This is loose by
+// design -- it's a superset covering all three languages' identifier rules,
+// and false positives (matching inside string literals or comments) are
+// harmless since they just add extra, still-searchable symbols.
+var identifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// minIdentifierLen filters out short, low-signal tokens (loop variables like
+// "i", keywords) the same way BuildFTSQuery filters words under 3 chars.
+const minIdentifierLen = 3
+
+// extractIdentifiers pulls every distinct identifier-like token out of
+// content, for indexing by IndexCodeSymbols.
+func extractIdentifiers(content string) []string {
+	seen := make(map[string]bool)
+	var out []string
+	for _, tok := range identifierPattern.FindAllString(content, -1) {
+		if len(tok) < minIdentifierLen || seen[tok] {
+			continue
+		}
+		seen[tok] = true
+		out = append(out, tok)
+	}
+	return out
+}
+
+// trigramsOf returns the distinct case-folded trigrams of s (Zoekt's
+// approach: lowercase before slicing so search is case-insensitive). Strings
+// shorter than 3 runes produce no trigrams.
+func trigramsOf(s string) []string {
+	s = strings.ToLower(s)
+	runes := []rune(s)
+	if len(runes) < 3 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var out []string
+	for i := 0; i+3 <= len(runes); i++ {
+		tri := string(runes[i : i+3])
+		if !seen[tri] {
+			seen[tri] = true
+			out = append(out, tri)
+		}
+	}
+	return out
+}
+
+func (d *DB) ensureCodeSymbolsSchema() error {
+	_, err := d.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS code_symbols (
+			node_id TEXT NOT NULL,
+			symbol  TEXT NOT NULL,
+			PRIMARY KEY (node_id, symbol)
+		);
+		CREATE TABLE IF NOT EXISTS code_symbol_trigrams (
+			trigram TEXT NOT NULL,
+			node_id TEXT NOT NULL,
+			PRIMARY KEY (trigram, node_id)
+		);
+	`)
+	return err
+}
+
+// IndexCodeSymbols extracts identifiers from content and persists them plus
+// their trigram postings for nodeID, replacing whatever was previously
+// indexed for that node. Call it whenever a code-class node's content is
+// created or updated.
+func (d *DB) IndexCodeSymbols(nodeID, content string) error {
+	if err := d.ensureCodeSymbolsSchema(); err != nil {
+		return fmt.Errorf("ensuring code_symbols schema: %w", err)
+	}
+
+	symbols := extractIdentifiers(content)
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM code_symbols WHERE node_id = ?`, nodeID); err != nil {
+		return fmt.Errorf("clearing prior symbols for %s: %w", nodeID, err)
+	}
+	if _, err := tx.Exec(`DELETE FROM code_symbol_trigrams WHERE node_id = ?`, nodeID); err != nil {
+		return fmt.Errorf("clearing prior trigrams for %s: %w", nodeID, err)
+	}
+
+	symbolStmt, err := tx.Prepare(`INSERT INTO code_symbols (node_id, symbol) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer symbolStmt.Close()
+
+	trigramStmt, err := tx.Prepare(`INSERT OR IGNORE INTO code_symbol_trigrams (trigram, node_id) VALUES (?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer trigramStmt.Close()
+
+	for _, sym := range symbols {
+		if _, err := symbolStmt.Exec(nodeID, sym); err != nil {
+			return fmt.Errorf("indexing symbol %q for %s: %w", sym, nodeID, err)
+		}
+		for _, tri := range trigramsOf(sym) {
+			if _, err := trigramStmt.Exec(tri, nodeID); err != nil {
+				return fmt.Errorf("indexing trigram %q for %s: %w", tri, nodeID, err)
+			}
+		}
+	}
+
+	return tx.Commit()
+}
+
+// ReindexCodeSymbols rebuilds the code symbol/trigram index for every
+// code-class node (those with file_path in their tags) currently in the
+// database. Node creation is delegated to the mycelica-cli binary (see
+// CreateNode), so there's no in-process hook to call IndexCodeSymbols as
+// each node is ingested; this is the explicit backfill entrypoint, analogous
+// to RebuildFTS for the FTS index.
+func (d *DB) ReindexCodeSymbols() error {
+	rows, err := d.conn.Query(`SELECT id, content FROM nodes WHERE tags LIKE '%file_path%'`)
+	if err != nil {
+		return fmt.Errorf("listing code-class nodes: %w", err)
+	}
+	type nodeContent struct {
+		id      string
+		content string
+	}
+	var toIndex []nodeContent
+	for rows.Next() {
+		var id string
+		var content *string
+		if err := rows.Scan(&id, &content); err != nil {
+			rows.Close()
+			return err
+		}
+		text := ""
+		if content != nil {
+			text = *content
+		}
+		toIndex = append(toIndex, nodeContent{id: id, content: text})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return err
+	}
+	rows.Close()
+
+	for _, nc := range toIndex {
+		if err := d.IndexCodeSymbols(nc.id, nc.content); err != nil {
+			return fmt.Errorf("indexing %s: %w", nc.id, err)
+		}
+	}
+	return nil
+}
+
+// SearchCodeSymbols finds code-class nodes whose indexed identifiers contain
+// query as a literal (case-insensitive) substring, following Zoekt's
+// trigram-postings approach: intersect the posting lists for each trigram in
+// query to get candidates cheaply, then verify each candidate with an actual
+// substring check (trigram co-occurrence alone can false-positive). Queries
+// under 3 characters produce no trigrams and return no results, matching
+// BuildFTSQuery's existing minimum-token-length convention.
+func (d *DB) SearchCodeSymbols(query string, limit int) ([]Node, error) {
+	if err := d.ensureCodeSymbolsSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring code_symbols schema: %w", err)
+	}
+
+	q := strings.ToLower(strings.TrimSpace(query))
+	trigrams := trigramsOf(q)
+	if len(trigrams) == 0 {
+		return nil, nil
+	}
+
+	counts := make(map[string]int, 64)
+	for _, tri := range trigrams {
+		rows, err := d.conn.Query(`SELECT node_id FROM code_symbol_trigrams WHERE trigram = ?`, tri)
+		if err != nil {
+			return nil, fmt.Errorf("looking up trigram %q: %w", tri, err)
+		}
+		for rows.Next() {
+			var nodeID string
+			if err := rows.Scan(&nodeID); err != nil {
+				rows.Close()
+				return nil, err
+			}
+			counts[nodeID]++
+		}
+		if err := rows.Err(); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		rows.Close()
+	}
+
+	var candidates []string
+	for nodeID, count := range counts {
+		if count == len(trigrams) {
+			candidates = append(candidates, nodeID)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(candidates))
+	args := make([]interface{}, len(candidates))
+	for i, id := range candidates {
+		placeholders[i] = "?"
+		args[i] = id
+	}
+	symRows, err := d.conn.Query(
+		`SELECT node_id, symbol FROM code_symbols WHERE node_id IN (`+strings.Join(placeholders, ",")+`)`,
+		args...,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("loading candidate symbols: %w", err)
+	}
+	matchCounts := make(map[string]int, len(candidates))
+	for symRows.Next() {
+		var nodeID, symbol string
+		if err := symRows.Scan(&nodeID, &symbol); err != nil {
+			symRows.Close()
+			return nil, err
+		}
+		if strings.Contains(strings.ToLower(symbol), q) {
+			matchCounts[nodeID]++
+		}
+	}
+	if err := symRows.Err(); err != nil {
+		symRows.Close()
+		return nil, err
+	}
+	symRows.Close()
+
+	var verified []string
+	for nodeID, count := range matchCounts {
+		if count > 0 {
+			verified = append(verified, nodeID)
+		}
+	}
+	sort.Slice(verified, func(i, j int) bool {
+		if matchCounts[verified[i]] != matchCounts[verified[j]] {
+			return matchCounts[verified[i]] > matchCounts[verified[j]]
+		}
+		return verified[i] < verified[j]
+	})
+	if limit > 0 && len(verified) > limit {
+		verified = verified[:limit]
+	}
+
+	nodes := make([]Node, 0, len(verified))
+	for _, id := range verified {
+		node, err := d.GetNode(id)
+		if err != nil || node == nil {
+			continue
+		}
+		nodes = append(nodes, *node)
+	}
+	return nodes, nil
+}
@@ -0,0 +1,150 @@
+package db
+
+import "testing"
+
+func TestExtractIdentifiers_FiltersShortTokens(t *testing.T) {
+	got := extractIdentifiers("func GenerateTaskFile(d *DB, i int) { x := 1 }")
+	want := map[string]bool{"func": true, "GenerateTaskFile": true, "int": true}
+	for _, tok := range got {
+		if len(tok) < 3 {
+			t.Errorf("expected no tokens under 3 chars, got %q", tok)
+		}
+	}
+	for w := range want {
+		found := false
+		for _, tok := range got {
+			if tok == w {
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("expected %q among extracted identifiers, got %v", w, got)
+		}
+	}
+}
+
+func TestTrigramsOf_ShortStringHasNone(t *testing.T) {
+	if got := trigramsOf("ab"); got != nil {
+		t.Errorf("expected no trigrams for a 2-char string, got %v", got)
+	}
+}
+
+func TestTrigramsOf_CaseFolded(t *testing.T) {
+	got := trigramsOf("Foo")
+	want := []string{"foo"}
+	if len(got) != 1 || got[0] != want[0] {
+		t.Errorf("trigramsOf(%q) = %v, want %v", "Foo", got, want)
+	}
+}
+
+func TestIndexCodeSymbols_SearchFindsIdentifier(t *testing.T) {
+	d := setupTestDB(t)
+
+	if err := d.IndexCodeSymbols("n1", "func ContextForTask(id string) error { return nil }"); err != nil {
+		t.Fatalf("IndexCodeSymbols: %v", err)
+	}
+	if _, err := d.conn.Exec(
+		`INSERT INTO nodes (id, title, created_at, updated_at) VALUES (?, ?, 1000, 1000)`,
+		"n1", "context.go",
+	); err != nil {
+		t.Fatalf("inserting node: %v", err)
+	}
+
+	results, err := d.SearchCodeSymbols("ContextFor", 10)
+	if err != nil {
+		t.Fatalf("SearchCodeSymbols: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "n1" {
+		t.Fatalf("expected n1 to match, got %+v", results)
+	}
+}
+
+func TestSearchCodeSymbols_NoTrigramFalsePositive(t *testing.T) {
+	d := setupTestDB(t)
+
+	if err := d.IndexCodeSymbols("n1", "func ReadFile(path string) {}"); err != nil {
+		t.Fatalf("IndexCodeSymbols: %v", err)
+	}
+	if _, err := d.conn.Exec(
+		`INSERT INTO nodes (id, title, created_at, updated_at) VALUES (?, ?, 1000, 1000)`,
+		"n1", "io.go",
+	); err != nil {
+		t.Fatalf("inserting node: %v", err)
+	}
+
+	// "ReadFile" and "WriteFile" share every trigram in "File" but a query
+	// for a substring absent from any indexed identifier should still miss.
+	results, err := d.SearchCodeSymbols("WriteFile", 10)
+	if err != nil {
+		t.Fatalf("SearchCodeSymbols: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected no match for an unindexed identifier, got %+v", results)
+	}
+}
+
+func TestSearchCodeSymbols_ShortQueryReturnsEmpty(t *testing.T) {
+	d := setupTestDB(t)
+
+	results, err := d.SearchCodeSymbols("ab", 10)
+	if err != nil {
+		t.Fatalf("SearchCodeSymbols: %v", err)
+	}
+	if len(results) != 0 {
+		t.Errorf("expected a sub-trigram query to return no results, got %+v", results)
+	}
+}
+
+func TestIndexCodeSymbols_ReplacesPriorIndex(t *testing.T) {
+	d := setupTestDB(t)
+
+	if err := d.IndexCodeSymbols("n1", "func OldName() {}"); err != nil {
+		t.Fatalf("IndexCodeSymbols: %v", err)
+	}
+	if err := d.IndexCodeSymbols("n1", "func NewName() {}"); err != nil {
+		t.Fatalf("IndexCodeSymbols: %v", err)
+	}
+	if _, err := d.conn.Exec(
+		`INSERT INTO nodes (id, title, created_at, updated_at) VALUES (?, ?, 1000, 1000)`,
+		"n1", "renamed.go",
+	); err != nil {
+		t.Fatalf("inserting node: %v", err)
+	}
+
+	if results, err := d.SearchCodeSymbols("OldName", 10); err != nil || len(results) != 0 {
+		t.Errorf("expected the old identifier to no longer match, got %+v (err=%v)", results, err)
+	}
+	results, err := d.SearchCodeSymbols("NewName", 10)
+	if err != nil || len(results) != 1 {
+		t.Fatalf("expected the new identifier to match, got %+v (err=%v)", results, err)
+	}
+}
+
+func TestReindexCodeSymbols_OnlyIndexesFilePathTaggedNodes(t *testing.T) {
+	d := setupTestDB(t)
+
+	if _, err := d.conn.Exec(
+		`INSERT INTO nodes (id, title, content, tags, created_at, updated_at) VALUES (?, ?, ?, ?, 1000, 1000)`,
+		"code1", "handler.go", "func HandleRequest() {}", `{"file_path":"src/handler.go"}`,
+	); err != nil {
+		t.Fatalf("inserting code node: %v", err)
+	}
+	if _, err := d.conn.Exec(
+		`INSERT INTO nodes (id, title, content, created_at, updated_at) VALUES (?, ?, ?, 1000, 1000)`,
+		"prose1", "Notes", "HandleRequest is discussed here",
+	); err != nil {
+		t.Fatalf("inserting prose node: %v", err)
+	}
+
+	if err := d.ReindexCodeSymbols(); err != nil {
+		t.Fatalf("ReindexCodeSymbols: %v", err)
+	}
+
+	results, err := d.SearchCodeSymbols("HandleRequest", 10)
+	if err != nil {
+		t.Fatalf("SearchCodeSymbols: %v", err)
+	}
+	if len(results) != 1 || results[0].ID != "code1" {
+		t.Fatalf("expected only the tagged code node to be indexed, got %+v", results)
+	}
+}
@@ -0,0 +1,78 @@
+package db
+
+import "fmt"
+
+// CommunityRow is one persisted union-find entry backing
+// graph.CommunityIndex, so incremental community detection survives
+// restarts without recomputing the whole forest from scratch.
+type CommunityRow struct {
+	NodeID string
+	Parent string
+	Rank   int
+	Size   int
+}
+
+func (d *DB) ensureCommunitySchema() error {
+	_, err := d.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS community_state (
+			node_id TEXT PRIMARY KEY,
+			parent  TEXT NOT NULL,
+			rank    INTEGER NOT NULL,
+			size    INTEGER NOT NULL
+		);
+	`)
+	return err
+}
+
+// LoadCommunityState returns every persisted union-find entry. Returns an
+// empty slice, not an error, on a fresh database with no saved state yet.
+func (d *DB) LoadCommunityState() ([]CommunityRow, error) {
+	if err := d.ensureCommunitySchema(); err != nil {
+		return nil, fmt.Errorf("ensuring community schema: %w", err)
+	}
+
+	rows, err := d.conn.Query(`SELECT node_id, parent, rank, size FROM community_state`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var result []CommunityRow
+	for rows.Next() {
+		var r CommunityRow
+		if err := rows.Scan(&r.NodeID, &r.Parent, &r.Rank, &r.Size); err != nil {
+			return nil, err
+		}
+		result = append(result, r)
+	}
+	return result, rows.Err()
+}
+
+// SaveCommunityState replaces the persisted union-find forest with rows.
+func (d *DB) SaveCommunityState(rows []CommunityRow) error {
+	if err := d.ensureCommunitySchema(); err != nil {
+		return fmt.Errorf("ensuring community schema: %w", err)
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if _, err := tx.Exec(`DELETE FROM community_state`); err != nil {
+		return err
+	}
+	stmt, err := tx.Prepare(`INSERT INTO community_state (node_id, parent, rank, size) VALUES (?, ?, ?, ?)`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, r := range rows {
+		if _, err := stmt.Exec(r.NodeID, r.Parent, r.Rank, r.Size); err != nil {
+			return fmt.Errorf("saving community row for %s: %w", r.NodeID, err)
+		}
+	}
+	return tx.Commit()
+}
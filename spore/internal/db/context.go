@@ -5,6 +5,12 @@ import (
 	"math"
 )
 
+// inverseTraversalCost is the flat premium added when FollowInverse lets
+// Dijkstra walk a registered mirrored kind backward (e.g. as
+// "referenced_by" rather than "references"), on top of that effective
+// type's usual cost.
+const inverseTraversalCost = 0.25
+
 // ContextNode represents a node reached by Dijkstra traversal from a source.
 type ContextNode struct {
 	Rank      int       `json:"rank"`
@@ -16,6 +22,9 @@ type ContextNode struct {
 	Path      []PathHop `json:"path"`
 	NodeClass *string   `json:"nodeClass"`
 	IsItem    bool      `json:"isItem"`
+	// AltPaths holds alternate routes from the source to this node, populated
+	// only by ContextForTaskWithAlternates.
+	AltPaths [][]PathHop `json:"altPaths,omitempty"`
 }
 
 // PathHop represents one hop in a path from source to destination.
@@ -35,6 +44,33 @@ type ContextConfig struct {
 	ExcludeEdgeTypes []string // blocklist
 	NotSuperseded    bool
 	ItemsOnly        bool
+
+	// FollowInverse allows traversal backward across an edge whose type is
+	// registered in DefaultEdgeKindRegistry (e.g. arriving at a node via the
+	// target side of a "references" edge, i.e. as if it were a
+	// "referenced_by" edge). Without it, such edges are only walked forward
+	// (source -> target); edges with no registered mirror are unaffected and
+	// always traversable in both directions, as before. Backward traversal
+	// of a mirrored kind is charged inverseTraversalCost on top of its usual
+	// cost.
+	FollowInverse bool
+
+	// ANNSeedVec, if set, seeds the Dijkstra frontier from the top-ANNSeedK
+	// approximate nearest neighbors of this embedding (via the HNSW index)
+	// instead of a single sourceID. sourceID is still used for path
+	// reconstruction and is always included in the frontier.
+	ANNSeedVec []float32
+	ANNSeedK   int
+
+	// SemanticWeight is alpha in cost' = alpha*edge_cost + (1-alpha)*(1-cosine_sim),
+	// blending each edge's traversal cost with how semantically close its
+	// neighbor is to ANNSeedVec. Only applies when ANNSeedVec is set --
+	// without a query embedding there's nothing to blend, so every existing
+	// caller (which never sets ANNSeedVec) is unaffected regardless of this
+	// field's zero value. alpha=1 is pure graph cost (edge_cost unchanged);
+	// alpha=0 is pure vector distance (edge_cost ignored). A neighbor with
+	// no embedding falls back to the unblended edge_cost.
+	SemanticWeight float64
 }
 
 // DefaultContextConfig returns sensible defaults matching the CLI.
@@ -116,12 +152,39 @@ func (d *DB) ContextForTask(sourceID string, config *ContextConfig) ([]ContextNo
 			excludeSet[t] = true
 		}
 	}
+	edgeKinds := DefaultEdgeKindRegistry()
 
 	dist := map[string]float64{sourceID: 0.0}
 	prev := map[string]prevEntry{}
 	visited := map[string]bool{}
 
 	h := &dijkstraHeap{{distance: 0.0, nodeID: sourceID, hops: 0}}
+
+	// Seed the frontier from ANN hits, if requested, so traversal starts from
+	// several semantically related nodes rather than a single sourceID.
+	if len(config.ANNSeedVec) > 0 {
+		k := config.ANNSeedK
+		if k <= 0 {
+			k = 5
+		}
+		if hits, err := d.SearchNodesByEmbedding(config.ANNSeedVec, k); err == nil {
+			for _, hit := range hits {
+				if hit.NodeID == sourceID {
+					continue
+				}
+				seedDist := 1.0 - float64(hit.Similarity)
+				if seedDist < 0 {
+					seedDist = 0
+				}
+				if existing, ok := dist[hit.NodeID]; !ok || seedDist < existing {
+					dist[hit.NodeID] = seedDist
+					prev[hit.NodeID] = prevEntry{prevNodeID: sourceID, edgeID: "", edgeType: "ann_seed"}
+					*h = append(*h, dijkstraEntry{distance: seedDist, nodeID: hit.NodeID, hops: 1})
+				}
+			}
+		}
+	}
+
 	heap.Init(h)
 
 	var results []ContextNode
@@ -183,41 +246,81 @@ func (d *DB) ContextForTask(sourceID string, config *ContextConfig) ([]ContextNo
 				continue
 			}
 
-			// Edge type allowlist
-			if allowSet != nil && !allowSet[edge.EdgeType] {
+			mirrorKind := edgeKinds.Mirror(edge.EdgeType)
+
+			// Edge type allowlist (matches either side of a mirror pair)
+			if allowSet != nil && !allowSet[edge.EdgeType] && !(mirrorKind != "" && allowSet[mirrorKind]) {
 				continue
 			}
 
-			// Edge type blocklist
-			if excludeSet != nil && excludeSet[edge.EdgeType] {
+			// Edge type blocklist (matches either side of a mirror pair)
+			if excludeSet != nil && (excludeSet[edge.EdgeType] || (mirrorKind != "" && excludeSet[mirrorKind])) {
 				continue
 			}
 
 			// Get neighbor (bidirectional traversal)
 			neighbor := edge.TargetID
+			backward := false
 			if edge.SourceID != current {
 				neighbor = edge.SourceID
+				backward = true
+			}
+
+			// A mirrored kind is directional by default: only forward
+			// (source -> target) unless FollowInverse opts into also
+			// walking it backward, as its mirror.
+			if backward && mirrorKind != "" && !config.FollowInverse {
+				continue
 			}
 
 			if visited[neighbor] {
 				continue
 			}
 
+			// effectiveType is what this traversal actually represents --
+			// edge.EdgeType forward, or its mirror when walked backward --
+			// and is what both the cost model and the reported path use.
+			effectiveType := edge.EdgeType
+			if backward && mirrorKind != "" {
+				effectiveType = mirrorKind
+			}
+
 			// Compute cost
 			confidence := 0.5
 			if edge.Confidence != nil {
 				confidence = *edge.Confidence
 			}
-			typePriority := EdgeTypePriority(edge.EdgeType)
+			typePriority := EdgeTypePriority(effectiveType)
 			baseCost := math.Max((1.0-confidence)*(1.0-0.5*typePriority), 0.001)
 
 			// Structural edge penalty: high-confidence but low-information edges
 			// (same file, hierarchy) get a cost floor so they don't flood the budget
 			// before semantic edges.
-			if IsStructuralEdge(edge.EdgeType) {
+			if IsStructuralEdge(effectiveType) {
 				baseCost = math.Max(baseCost, 0.4)
 			}
 
+			// Inverse-traversal premium: walking a mirrored kind backward is
+			// more speculative than its authored direction.
+			if backward && mirrorKind != "" {
+				baseCost += inverseTraversalCost
+			}
+
+			// Blend in semantic similarity to the query embedding, if hybrid
+			// mode is active and the neighbor has one.
+			if len(config.ANNSeedVec) > 0 {
+				alpha := config.SemanticWeight
+				if alpha < 0 {
+					alpha = 0
+				} else if alpha > 1 {
+					alpha = 1
+				}
+				if neighborVec, err := d.GetNodeEmbedding(neighbor, DefaultEmbeddingModel); err == nil && len(neighborVec) > 0 {
+					semDist := cosineDistance(normalizeVector(config.ANNSeedVec), normalizeVector(neighborVec))
+					baseCost = alpha*baseCost + (1-alpha)*semDist
+				}
+			}
+
 			newDist := currentDist + baseCost
 
 			// Cost ceiling
@@ -232,7 +335,7 @@ func (d *DB) ContextForTask(sourceID string, config *ContextConfig) ([]ContextNo
 				prev[neighbor] = prevEntry{
 					prevNodeID: current,
 					edgeID:     edge.ID,
-					edgeType:   edge.EdgeType,
+					edgeType:   effectiveType,
 				}
 				heap.Push(h, dijkstraEntry{
 					distance: newDist,
@@ -274,9 +377,9 @@ func (d *DB) reconstructPath(prev map[string]prevEntry, source, target string) (
 			}
 		}
 		path = append(path, PathHop{
-			EdgeID:   entry.edgeID,
-			EdgeType: entry.edgeType,
-			NodeID:   current,
+			EdgeID:    entry.edgeID,
+			EdgeType:  entry.edgeType,
+			NodeID:    current,
 			NodeTitle: title,
 		})
 		current = entry.prevNodeID
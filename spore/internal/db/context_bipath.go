@@ -0,0 +1,287 @@
+package db
+
+import (
+	"container/heap"
+	"math"
+)
+
+// PathResult is the minimum-cost path found by PathBetween.
+type PathResult struct {
+	Path     []PathHop `json:"path"`
+	Distance float64   `json:"distance"`
+}
+
+// biNeighbor is one candidate relaxation step out of a node, shared by both
+// the forward and backward frontier of PathBetween -- the traversal model
+// (mirror-kind direction, inverse-traversal premium, structural floor) is
+// already symmetric regardless of which end it's computed from, so one
+// helper serves both sides.
+type biNeighbor struct {
+	neighbor      string
+	edgeID        string
+	effectiveType string
+	cost          float64
+}
+
+// biNeighbors lists current's traversable neighbors under config's filters,
+// pricing each one with edgeCostAs -- the same allow/exclude, NotSuperseded,
+// mirror-kind, and FollowInverse rules ContextForTask and shortestPath use.
+func biNeighbors(d *DB, current string, config *ContextConfig, edgeKinds *EdgeKindRegistry, allowSet, excludeSet map[string]bool) ([]biNeighbor, error) {
+	edges, err := d.GetEdgesForNode(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []biNeighbor
+	for _, edge := range edges {
+		if config.NotSuperseded && edge.SupersededBy != nil {
+			continue
+		}
+
+		mirrorKind := edgeKinds.Mirror(edge.EdgeType)
+
+		if allowSet != nil && !allowSet[edge.EdgeType] && !(mirrorKind != "" && allowSet[mirrorKind]) {
+			continue
+		}
+		if excludeSet != nil && (excludeSet[edge.EdgeType] || (mirrorKind != "" && excludeSet[mirrorKind])) {
+			continue
+		}
+
+		neighbor := edge.TargetID
+		backward := false
+		if edge.SourceID != current {
+			neighbor = edge.SourceID
+			backward = true
+		}
+		if backward && mirrorKind != "" && !config.FollowInverse {
+			continue
+		}
+
+		effectiveType := edge.EdgeType
+		if backward && mirrorKind != "" {
+			effectiveType = mirrorKind
+		}
+
+		cost := edgeCostAs(edge, effectiveType)
+		if backward && mirrorKind != "" {
+			cost += inverseTraversalCost
+		}
+
+		out = append(out, biNeighbor{neighbor: neighbor, edgeID: edge.ID, effectiveType: effectiveType, cost: cost})
+	}
+	return out, nil
+}
+
+// PathBetween finds the minimum-cost path between two specific nodes,
+// reusing ContextForTask's edge-cost model (edge-type priority, structural
+// penalty floor, confidence term). It runs a bidirectional Dijkstra: one
+// frontier expands forward from srcID, the other backward from dstID, and a
+// running best mu = min(distF[u] + w(u,v) + distB[v]) is updated on every
+// relaxation that touches a node the other frontier has already reached.
+// The search stops once the cheaper frontier's best remaining distance
+// alone can't beat mu. Returns nil, nil if the two nodes aren't connected
+// within config's limits.
+func (d *DB) PathBetween(srcID, dstID string, config *ContextConfig) (*PathResult, error) {
+	result, _, err := d.pathBetween(srcID, dstID, config)
+	return result, err
+}
+
+// pathBetween is PathBetween's implementation, additionally returning the
+// number of distinct nodes popped off either frontier so tests can confirm
+// the bidirectional search terminates having visited fewer nodes than a
+// single-source expansion would.
+func (d *DB) pathBetween(srcID, dstID string, config *ContextConfig) (*PathResult, int, error) {
+	if config == nil {
+		config = DefaultContextConfig()
+	}
+	if srcID == dstID {
+		return &PathResult{Path: nil, Distance: 0}, 1, nil
+	}
+
+	if config.ItemsOnly {
+		node, err := d.GetNode(dstID)
+		if err != nil || node == nil || !node.IsItem {
+			return nil, 0, nil
+		}
+	}
+
+	maxHops := config.MaxHops
+	if maxHops <= 0 {
+		maxHops = 6
+	}
+	maxCost := config.MaxCost
+	if maxCost <= 0 {
+		maxCost = 3.0
+	}
+
+	var allowSet map[string]bool
+	if config.EdgeTypes != nil {
+		allowSet = make(map[string]bool, len(config.EdgeTypes))
+		for _, t := range config.EdgeTypes {
+			allowSet[t] = true
+		}
+	}
+	var excludeSet map[string]bool
+	if config.ExcludeEdgeTypes != nil {
+		excludeSet = make(map[string]bool, len(config.ExcludeEdgeTypes))
+		for _, t := range config.ExcludeEdgeTypes {
+			excludeSet[t] = true
+		}
+	}
+	edgeKinds := DefaultEdgeKindRegistry()
+
+	distF := map[string]float64{srcID: 0}
+	distB := map[string]float64{dstID: 0}
+	prevF := map[string]prevEntry{}
+	prevB := map[string]prevEntry{}
+	visitedF := map[string]bool{}
+	visitedB := map[string]bool{}
+
+	hF := &dijkstraHeap{{distance: 0, nodeID: srcID, hops: 0}}
+	hB := &dijkstraHeap{{distance: 0, nodeID: dstID, hops: 0}}
+	heap.Init(hF)
+	heap.Init(hB)
+
+	mu := math.Inf(1)
+	meet := ""
+
+	for hF.Len() > 0 && hB.Len() > 0 {
+		topF := (*hF)[0]
+		topB := (*hB)[0]
+		if topF.distance+topB.distance >= mu {
+			break
+		}
+
+		forward := topF.distance <= topB.distance
+
+		var entry dijkstraEntry
+		if forward {
+			entry = heap.Pop(hF).(dijkstraEntry)
+			if visitedF[entry.nodeID] {
+				continue
+			}
+			visitedF[entry.nodeID] = true
+		} else {
+			entry = heap.Pop(hB).(dijkstraEntry)
+			if visitedB[entry.nodeID] {
+				continue
+			}
+			visitedB[entry.nodeID] = true
+		}
+
+		if otherDist, ok := otherFrontierDist(forward, entry.nodeID, distF, distB); ok {
+			if cand := entry.distance + otherDist; cand < mu {
+				mu = cand
+				meet = entry.nodeID
+			}
+		}
+
+		if entry.hops >= maxHops {
+			continue
+		}
+
+		neighbors, err := biNeighbors(d, entry.nodeID, config, edgeKinds, allowSet, excludeSet)
+		if err != nil {
+			continue
+		}
+
+		dist, prev, visited, h := distF, prevF, visitedF, hF
+		if !forward {
+			dist, prev, visited, h = distB, prevB, visitedB, hB
+		}
+
+		for _, ne := range neighbors {
+			if visited[ne.neighbor] {
+				continue
+			}
+			newDist := entry.distance + ne.cost
+			if newDist > maxCost {
+				continue
+			}
+			if prevDist, ok := dist[ne.neighbor]; !ok || newDist < prevDist {
+				dist[ne.neighbor] = newDist
+				prev[ne.neighbor] = prevEntry{prevNodeID: entry.nodeID, edgeID: ne.edgeID, edgeType: ne.effectiveType}
+				heap.Push(h, dijkstraEntry{distance: newDist, nodeID: ne.neighbor, hops: entry.hops + 1})
+			}
+			if otherDist, ok := otherFrontierDist(forward, ne.neighbor, distF, distB); ok {
+				if cand := dist[ne.neighbor] + otherDist; cand < mu {
+					mu = cand
+					meet = ne.neighbor
+				}
+			}
+		}
+	}
+
+	visitedCount := len(visitedF) + len(visitedB)
+
+	if meet == "" {
+		return nil, visitedCount, nil
+	}
+
+	path, err := d.reconstructBiPath(prevF, prevB, srcID, dstID, meet)
+	if err != nil {
+		return nil, visitedCount, err
+	}
+	return &PathResult{Path: path, Distance: mu}, visitedCount, nil
+}
+
+// otherFrontierDist returns the opposite frontier's distance to nodeID, if
+// it has one yet.
+func otherFrontierDist(forward bool, nodeID string, distF, distB map[string]float64) (float64, bool) {
+	if forward {
+		d, ok := distB[nodeID]
+		return d, ok
+	}
+	d, ok := distF[nodeID]
+	return d, ok
+}
+
+// reconstructBiPath stitches the forward half (srcID to meet, walking prevF)
+// and backward half (meet to dstID, walking prevB) into one source-to-target
+// PathHop slice.
+func (d *DB) reconstructBiPath(prevF, prevB map[string]prevEntry, srcID, dstID, meet string) ([]PathHop, error) {
+	forward, err := d.reconstructPath(prevF, srcID, meet)
+	if err != nil {
+		return nil, err
+	}
+
+	// prevB is rooted at dstID, so walking prevB[cur].prevNodeID from meet
+	// moves one step closer to dstID at a time -- already in meet-to-dst
+	// order, unlike forward's prevF which needed reversing.
+	var backward []PathHop
+	cur := meet
+	for cur != dstID {
+		entry, ok := prevB[cur]
+		if !ok {
+			break
+		}
+		next := entry.prevNodeID
+		backward = append(backward, PathHop{
+			EdgeID:    entry.edgeID,
+			EdgeType:  entry.edgeType,
+			NodeID:    next,
+			NodeTitle: d.nodeTitleOrID(next),
+		})
+		cur = next
+	}
+
+	return append(forward, backward...), nil
+}
+
+// nodeTitleOrID resolves id's AI title, falling back to its stored title and
+// then to a truncated ID, matching reconstructPath's own fallback.
+func (d *DB) nodeTitleOrID(id string) string {
+	title := id
+	if len(title) > 8 {
+		title = title[:8]
+	}
+	node, err := d.GetNode(id)
+	if err == nil && node != nil {
+		if node.AITitle != nil {
+			title = *node.AITitle
+		} else {
+			title = node.Title
+		}
+	}
+	return title
+}
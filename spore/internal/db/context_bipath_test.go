@@ -0,0 +1,192 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"testing"
+)
+
+func TestPathBetween_ShortestPathWins(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	// A -> B via two paths:
+	//   direct: A --(low confidence)--> B  cost = high
+	//   indirect: A --(high conf)--> M --(high conf)--> B  cost = low+low
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "M", "Node M", true)
+	insertEdge(t, d, "e_direct", "A", "B", "related", f64(0.1)) // cost = 0.9*0.85 = 0.765
+	insertEdge(t, d, "e_am", "A", "M", "supports", f64(0.95))   // cost = 0.05*0.75 = 0.0375
+	insertEdge(t, d, "e_mb", "M", "B", "supports", f64(0.95))   // cost = 0.0375
+
+	result, err := d.PathBetween("A", "B", DefaultContextConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil {
+		t.Fatal("expected a path, got nil")
+	}
+	if len(result.Path) != 2 {
+		t.Fatalf("expected path via M (2 hops), got %d hops: %+v", len(result.Path), result.Path)
+	}
+	if result.Path[0].NodeID != "M" || result.Path[1].NodeID != "B" {
+		t.Errorf("expected A -> M -> B, got %+v", result.Path)
+	}
+	if result.Distance > 0.1 {
+		t.Errorf("distance should be ~0.075, got %f", result.Distance)
+	}
+}
+
+func TestPathBetween_StructuralPenalty(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B1", "Structural", true)
+	insertNode(t, d, "B2", "Semantic", true)
+	insertEdge(t, d, "e1", "A", "B1", "defined_in", f64(0.9))
+	insertEdge(t, d, "e2", "A", "B2", "supports", f64(0.9))
+
+	toB1, err := d.PathBetween("A", "B1", DefaultContextConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	toB2, err := d.PathBetween("A", "B2", DefaultContextConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if math.Abs(toB1.Distance-0.4) > 0.001 {
+		t.Errorf("B1 distance = %.4f, expected ~0.4 (structural floor)", toB1.Distance)
+	}
+	if math.Abs(toB2.Distance-0.075) > 0.001 {
+		t.Errorf("B2 distance = %.4f, expected ~0.075", toB2.Distance)
+	}
+}
+
+func TestPathBetween_SameNode(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+
+	result, err := d.PathBetween("A", "A", DefaultContextConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result == nil || result.Distance != 0 || len(result.Path) != 0 {
+		t.Errorf("expected a zero-distance, empty path, got %+v", result)
+	}
+}
+
+func TestPathBetween_Unreachable(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+
+	result, err := d.PathBetween("A", "B", DefaultContextConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != nil {
+		t.Errorf("expected nil for an unreachable pair, got %+v", result)
+	}
+}
+
+func TestPathBetween_RespectsEdgeTypesAndNotSuperseded(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "related", f64(0.5))
+
+	cfg := &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, EdgeTypes: []string{"supports"}}
+	if result, err := d.PathBetween("A", "B", cfg); err != nil {
+		t.Fatal(err)
+	} else if result != nil {
+		t.Errorf("expected nil when the only edge isn't in the allowlist, got %+v", result)
+	}
+
+	conn := d.Conn()
+	if _, err := conn.Exec(`UPDATE edges SET superseded_by = 'e2' WHERE id = 'e1'`); err != nil {
+		t.Fatal(err)
+	}
+	cfg = &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, NotSuperseded: true}
+	if result, err := d.PathBetween("A", "B", cfg); err != nil {
+		t.Fatal(err)
+	} else if result != nil {
+		t.Errorf("expected nil when the only edge is superseded, got %+v", result)
+	}
+}
+
+func TestPathBetween_ItemsOnlyRejectsNonItemDestination(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "Cat", "Category", false)
+	insertEdge(t, d, "e1", "A", "Cat", "related", f64(0.5))
+
+	cfg := &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, ItemsOnly: true}
+	result, err := d.PathBetween("A", "Cat", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != nil {
+		t.Errorf("expected nil: Cat isn't an item and ItemsOnly is set, got %+v", result)
+	}
+}
+
+// TestPathBetween_VisitsFewerNodesThanSingleSource builds a bridge between
+// src and dst flanked by cheap bridge hops, with expensive decoy leaves
+// hanging off both ends. A single-source expansion has no notion of dst, so
+// it visits every decoy whose cost is still under MaxCost. The
+// bidirectional search's mu converges on the cheap bridge long before
+// either frontier's heap would pop an expensive decoy, so it should visit
+// strictly fewer nodes.
+func TestPathBetween_VisitsFewerNodesThanSingleSource(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "src", "Source", true)
+	insertNode(t, d, "dst", "Dest", true)
+	insertNode(t, d, "br1", "Bridge 1", true)
+	insertNode(t, d, "br2", "Bridge 2", true)
+	insertNode(t, d, "br3", "Bridge 3", true)
+	insertEdge(t, d, "e_src_br1", "src", "br1", "supports", f64(0.99))
+	insertEdge(t, d, "e_br1_br2", "br1", "br2", "supports", f64(0.99))
+	insertEdge(t, d, "e_br2_br3", "br2", "br3", "supports", f64(0.99))
+	insertEdge(t, d, "e_br3_dst", "br3", "dst", "supports", f64(0.99))
+
+	const decoysPerSide = 30
+	for i := 0; i < decoysPerSide; i++ {
+		srcDecoy := fmt.Sprintf("src_decoy_%d", i)
+		dstDecoy := fmt.Sprintf("dst_decoy_%d", i)
+		insertNode(t, d, srcDecoy, "Decoy", true)
+		insertNode(t, d, dstDecoy, "Decoy", true)
+		insertEdge(t, d, "e_"+srcDecoy, "src", srcDecoy, "related", f64(0.05))
+		insertEdge(t, d, "e_"+dstDecoy, "dst", dstDecoy, "related", f64(0.05))
+	}
+
+	cfg := &ContextConfig{Budget: 1000, MaxHops: 20, MaxCost: 3.0}
+
+	single, err := d.ContextForTask("src", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(single) < decoysPerSide {
+		t.Fatalf("expected the single-source expansion to reach every decoy, got %d results", len(single))
+	}
+
+	_, visited, err := d.pathBetween("src", "dst", cfg)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if visited >= len(single) {
+		t.Errorf("bidirectional search visited %d nodes, single-source reached %d -- expected strictly fewer", visited, len(single))
+	}
+}
@@ -0,0 +1,334 @@
+package db
+
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// weightedPath is a candidate path together with its total cost, used by the
+// K-shortest-paths min-heap.
+type weightedPath struct {
+	cost float64
+	hops []PathHop
+}
+
+// pathHeap is a min-heap of weightedPath ordered by ascending cost.
+type pathHeap []weightedPath
+
+func (h pathHeap) Len() int            { return len(h) }
+func (h pathHeap) Less(i, j int) bool  { return h[i].cost < h[j].cost }
+func (h pathHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *pathHeap) Push(x interface{}) { *h = append(*h, x.(weightedPath)) }
+func (h *pathHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// edgeCost is the same edge-weighting formula used by ContextForTask:
+// (1 - confidence) * (1 - 0.5*type_priority), floored at 0.001, with a 0.4
+// floor for structural edges.
+func edgeCost(e Edge) float64 {
+	confidence := 0.5
+	if e.Confidence != nil {
+		confidence = *e.Confidence
+	}
+	typePriority := EdgeTypePriority(e.EdgeType)
+	cost := math.Max((1.0-confidence)*(1.0-0.5*typePriority), 0.001)
+	if IsStructuralEdge(e.EdgeType) {
+		cost = math.Max(cost, 0.4)
+	}
+	return cost
+}
+
+// edgeCostAs is edgeCost but pricing e as if its type were effectiveType --
+// used for a mirrored kind walked backward, where the cost model should
+// reflect the mirror's priority/structural-ness rather than the edge's own
+// stored type.
+func edgeCostAs(e Edge, effectiveType string) float64 {
+	confidence := 0.5
+	if e.Confidence != nil {
+		confidence = *e.Confidence
+	}
+	typePriority := EdgeTypePriority(effectiveType)
+	cost := math.Max((1.0-confidence)*(1.0-0.5*typePriority), 0.001)
+	if IsStructuralEdge(effectiveType) {
+		cost = math.Max(cost, 0.4)
+	}
+	return cost
+}
+
+// shortestPath runs Dijkstra from sourceID to targetID, respecting the same
+// filters as ContextForTask -- including mirror-aware --edge-types matching
+// and FollowInverse-gated backward traversal of registered mirror kinds --
+// while excluding any edge in excludedEdges (keyed
+// "sourceID|targetID|edgeID", undirected) and any node in excludedNodes
+// (sourceID itself is never excluded). Returns the path hops from source to
+// target and the total cost, or ok=false if unreachable.
+func (d *DB) shortestPath(sourceID, targetID string, config *ContextConfig, excludedEdges map[string]bool, excludedNodes map[string]bool) ([]PathHop, float64, bool) {
+	maxHops := config.MaxHops
+	if maxHops <= 0 {
+		maxHops = 6
+	}
+	maxCost := config.MaxCost
+	if maxCost <= 0 {
+		maxCost = 3.0
+	}
+
+	var allowSet map[string]bool
+	if config.EdgeTypes != nil {
+		allowSet = make(map[string]bool, len(config.EdgeTypes))
+		for _, t := range config.EdgeTypes {
+			allowSet[t] = true
+		}
+	}
+	var excludeTypeSet map[string]bool
+	if config.ExcludeEdgeTypes != nil {
+		excludeTypeSet = make(map[string]bool, len(config.ExcludeEdgeTypes))
+		for _, t := range config.ExcludeEdgeTypes {
+			excludeTypeSet[t] = true
+		}
+	}
+	edgeKinds := DefaultEdgeKindRegistry()
+
+	dist := map[string]float64{sourceID: 0.0}
+	prev := map[string]prevEntry{}
+	visited := map[string]bool{}
+
+	h := &dijkstraHeap{{distance: 0.0, nodeID: sourceID, hops: 0}}
+	heap.Init(h)
+
+	for h.Len() > 0 {
+		entry := heap.Pop(h).(dijkstraEntry)
+		current := entry.nodeID
+		if visited[current] {
+			continue
+		}
+		visited[current] = true
+
+		if current == targetID {
+			path, _ := d.reconstructPath(prev, sourceID, current)
+			return path, entry.distance, true
+		}
+		if entry.hops >= maxHops {
+			continue
+		}
+
+		edges, err := d.GetEdgesForNode(current)
+		if err != nil {
+			continue
+		}
+		for _, edge := range edges {
+			if config.NotSuperseded && edge.SupersededBy != nil {
+				continue
+			}
+
+			mirrorKind := edgeKinds.Mirror(edge.EdgeType)
+
+			if allowSet != nil && !allowSet[edge.EdgeType] && !(mirrorKind != "" && allowSet[mirrorKind]) {
+				continue
+			}
+			if excludeTypeSet != nil && (excludeTypeSet[edge.EdgeType] || (mirrorKind != "" && excludeTypeSet[mirrorKind])) {
+				continue
+			}
+
+			neighbor := edge.TargetID
+			backward := false
+			if edge.SourceID != current {
+				neighbor = edge.SourceID
+				backward = true
+			}
+			if backward && mirrorKind != "" && !config.FollowInverse {
+				continue
+			}
+			if visited[neighbor] || excludedNodes[neighbor] {
+				continue
+			}
+			if excludedEdges[undirectedEdgeKey(current, neighbor, edge.ID)] {
+				continue
+			}
+
+			effectiveType := edge.EdgeType
+			if backward && mirrorKind != "" {
+				effectiveType = mirrorKind
+			}
+
+			cost := edgeCostAs(edge, effectiveType)
+			if backward && mirrorKind != "" {
+				cost += inverseTraversalCost
+			}
+
+			newDist := entry.distance + cost
+			if newDist > maxCost {
+				continue
+			}
+			if prevDist, ok := dist[neighbor]; !ok || newDist < prevDist {
+				dist[neighbor] = newDist
+				prev[neighbor] = prevEntry{prevNodeID: current, edgeID: edge.ID, edgeType: effectiveType}
+				heap.Push(h, dijkstraEntry{distance: newDist, nodeID: neighbor, hops: entry.hops + 1})
+			}
+		}
+	}
+	return nil, 0, false
+}
+
+func undirectedEdgeKey(a, b, edgeID string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return fmt.Sprintf("%s|%s|%s", a, b, edgeID)
+}
+
+// pathCost sums the edge costs along a reconstructed path. Since PathHop
+// doesn't carry cost, this re-fetches edges; acceptable given K and path
+// lengths are both small in practice.
+func (d *DB) pathCost(hops []PathHop) float64 {
+	var total float64
+	for _, hop := range hops {
+		edges, err := d.GetEdgesForNode(hop.NodeID)
+		if err != nil {
+			continue
+		}
+		for _, e := range edges {
+			if e.ID == hop.EdgeID {
+				total += edgeCost(e)
+				break
+			}
+		}
+	}
+	return total
+}
+
+// KShortestPaths implements Yen's algorithm on top of the existing
+// Dijkstra routine: compute the shortest path P1, then for i=2..K generate
+// candidate deviations from each spur node on P_{i-1} by excluding edges and
+// root-prefix nodes shared with previously found paths, and take the
+// cheapest unseen candidate as the next path.
+func (d *DB) KShortestPaths(sourceID, targetID string, K int, config *ContextConfig) ([][]PathHop, error) {
+	if config == nil {
+		config = DefaultContextConfig()
+	}
+	if K <= 0 {
+		K = 1
+	}
+
+	first, cost, ok := d.shortestPath(sourceID, targetID, config, nil, nil)
+	if !ok {
+		return nil, nil
+	}
+	paths := [][]PathHop{first}
+	costs := []float64{cost}
+
+	candidates := &pathHeap{}
+	heap.Init(candidates)
+	seen := map[string]bool{pathKey(first): true}
+
+	for len(paths) < K {
+		prevPath := paths[len(paths)-1]
+
+		for i := 0; i < len(prevPath); i++ {
+			spurNodeID := prevPath[i].NodeID
+			rootPath := prevPath[:i+1]
+			rootCost := d.pathCost(rootPath)
+
+			excludedEdges := make(map[string]bool)
+			excludedNodes := make(map[string]bool)
+			for _, p := range paths {
+				if len(p) <= i || !sameRootPrefix(p, rootPath, i) {
+					continue
+				}
+				from := sourceID
+				if i > 0 {
+					from = p[i-1].NodeID
+				}
+				excludedEdges[undirectedEdgeKey(from, p[i].NodeID, p[i].EdgeID)] = true
+			}
+			for j := 0; j < i; j++ {
+				excludedNodes[rootPath[j].NodeID] = true
+			}
+
+			spurPath, spurCost, ok := d.shortestPath(spurNodeID, targetID, config, excludedEdges, excludedNodes)
+			if !ok {
+				continue
+			}
+
+			total := append(append([]PathHop{}, rootPath[:i]...), spurPath...)
+			totalCost := rootCost + spurCost
+			if seen[pathKey(total)] {
+				continue
+			}
+			heap.Push(candidates, weightedPath{cost: totalCost, hops: total})
+		}
+
+		if candidates.Len() == 0 {
+			break
+		}
+		next := heap.Pop(candidates).(weightedPath)
+		for seen[pathKey(next.hops)] && candidates.Len() > 0 {
+			next = heap.Pop(candidates).(weightedPath)
+		}
+		if seen[pathKey(next.hops)] {
+			break
+		}
+		seen[pathKey(next.hops)] = true
+		paths = append(paths, next.hops)
+		costs = append(costs, next.cost)
+	}
+
+	return paths, nil
+}
+
+func pathKey(hops []PathHop) string {
+	ids := make([]string, len(hops))
+	for i, h := range hops {
+		ids[i] = h.NodeID
+	}
+	return fmt.Sprintf("%v", ids)
+}
+
+// sameRootPrefix reports whether p shares the same first i+1 node IDs as root.
+func sameRootPrefix(p, root []PathHop, i int) bool {
+	if len(p) <= i || len(root) <= i {
+		return false
+	}
+	for j := 0; j <= i; j++ {
+		if p[j].NodeID != root[j].NodeID {
+			return false
+		}
+	}
+	return true
+}
+
+// ContextForTaskWithAlternates runs ContextForTask and, for each result,
+// additionally computes up to AltPaths alternate routes from sourceID using
+// Yen's algorithm so callers can display "reached via A, or via B".
+func (d *DB) ContextForTaskWithAlternates(sourceID string, config *ContextConfig, altPaths int) ([]ContextNode, error) {
+	results, err := d.ContextForTask(sourceID, config)
+	if err != nil {
+		return nil, err
+	}
+	if altPaths <= 0 {
+		return results, nil
+	}
+
+	for i := range results {
+		// The primary path is already in results[i].Path; fetch K = altPaths+1
+		// so there's at least one alternate beyond the one already found.
+		kpaths, err := d.KShortestPaths(sourceID, results[i].NodeID, altPaths+1, config)
+		if err != nil || len(kpaths) <= 1 {
+			continue
+		}
+		var alternates [][]PathHop
+		for _, p := range kpaths[1:] {
+			alternates = append(alternates, p)
+			if len(alternates) >= altPaths {
+				break
+			}
+		}
+		results[i].AltPaths = alternates
+	}
+	return results, nil
+}
@@ -561,9 +561,9 @@ func TestDijkstra_ShortestPathWins(t *testing.T) {
 	insertNode(t, d, "A", "Node A", true)
 	insertNode(t, d, "B", "Node B", true)
 	insertNode(t, d, "M", "Node M", true)
-	insertEdge(t, d, "e_direct", "A", "B", "related", f64(0.1))   // cost = (0.9)*(0.85) = 0.765
-	insertEdge(t, d, "e_am", "A", "M", "supports", f64(0.95))     // cost = (0.05)*(0.75) = 0.0375
-	insertEdge(t, d, "e_mb", "M", "B", "supports", f64(0.95))     // cost = 0.0375
+	insertEdge(t, d, "e_direct", "A", "B", "related", f64(0.1)) // cost = (0.9)*(0.85) = 0.765
+	insertEdge(t, d, "e_am", "A", "M", "supports", f64(0.95))   // cost = (0.05)*(0.75) = 0.0375
+	insertEdge(t, d, "e_mb", "M", "B", "supports", f64(0.95))   // cost = 0.0375
 
 	results, err := d.ContextForTask("A", DefaultContextConfig())
 	if err != nil {
@@ -590,3 +590,204 @@ func TestDijkstra_ShortestPathWins(t *testing.T) {
 		t.Errorf("B distance should be ~0.075, got %f", bResult.Distance)
 	}
 }
+
+func TestDijkstra_FollowInverse_DisabledByDefault(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	// A --references--> B; starting from B, "references" has a registered
+	// mirror (referenced_by) so it's forward-only unless FollowInverse is set.
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "references", f64(0.8))
+
+	results, err := d.ContextForTask("B", DefaultContextConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 0 {
+		t.Fatalf("expected 0 results without FollowInverse, got %d", len(results))
+	}
+}
+
+func TestDijkstra_FollowInverse_WalksMirrorBackward(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "references", f64(0.8))
+
+	config := &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, FollowInverse: true}
+	results, err := d.ContextForTask("B", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].NodeID != "A" {
+		t.Fatalf("expected to reach A via referenced_by, got %+v", results)
+	}
+	if len(results[0].Path) != 1 || results[0].Path[0].EdgeType != "referenced_by" {
+		t.Errorf("expected path hop reported as referenced_by, got %+v", results[0].Path)
+	}
+
+	// Cost should be the forward cost plus the inverse-traversal premium.
+	forwardCost := edgeCostAs(Edge{EdgeType: "references", Confidence: f64(0.8)}, "referenced_by")
+	wantCost := forwardCost + inverseTraversalCost
+	if math.Abs(results[0].Distance-wantCost) > 1e-9 {
+		t.Errorf("expected distance %f (forward + inverse premium), got %f", wantCost, results[0].Distance)
+	}
+}
+
+func TestDijkstra_EdgeTypesMatchMirrorSide(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	// Stored as "references" but the allowlist only names its mirror.
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "references", f64(0.8))
+
+	config := &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, EdgeTypes: []string{"referenced_by"}}
+	results, err := d.ContextForTask("A", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(results) != 1 || results[0].NodeID != "B" {
+		t.Fatalf("expected edge-types allowlist to match the mirror side, got %+v", results)
+	}
+}
+
+func TestKShortestPaths_FollowInverse(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "references", f64(0.8))
+
+	// Without FollowInverse, B can't reach A at all.
+	none, err := d.KShortestPaths("B", "A", 1, DefaultContextConfig())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(none) != 0 {
+		t.Fatalf("expected no path B->A without FollowInverse, got %+v", none)
+	}
+
+	config := &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, FollowInverse: true}
+	paths, err := d.KShortestPaths("B", "A", 1, config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 1 || len(paths[0]) != 1 || paths[0][0].EdgeType != "referenced_by" {
+		t.Fatalf("expected one referenced_by hop with FollowInverse, got %+v", paths)
+	}
+}
+
+func TestHybridContext_SemanticWeightPureGraph(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "related", f64(0.5))
+	if err := d.SetNodeEmbedding("B", DefaultEmbeddingModel, []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Query embedding orthogonal to B's -- if semantic distance leaked in
+	// despite alpha=1, B's cost would rise to 0.5*baseCost + 0.5*1.0.
+	config := &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, ANNSeedVec: []float32{0, 1}, SemanticWeight: 1.0}
+	results, err := d.ContextForTask("A", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := edgeCost(Edge{EdgeType: "related", Confidence: f64(0.5)})
+	b := mustFind(t, results, "B")
+	if math.Abs(b.Distance-want) > 1e-9 {
+		t.Errorf("alpha=1 should leave edge cost unblended: got %f, want %f", b.Distance, want)
+	}
+}
+
+func TestHybridContext_SemanticWeightPureVector(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	// Low-confidence edge -- a high graph cost that alpha=0 should fully
+	// discard in favor of semantic distance.
+	insertEdge(t, d, "e1", "A", "B", "related", f64(0.01))
+	if err := d.SetNodeEmbedding("B", DefaultEmbeddingModel, []float32{1, 0}); err != nil {
+		t.Fatal(err)
+	}
+
+	// Query embedding identical to B's -- cosine similarity 1, distance 0.
+	config := &ContextConfig{Budget: 20, MaxHops: 6, MaxCost: 3.0, ANNSeedVec: []float32{1, 0}, SemanticWeight: 0.0}
+	results, err := d.ContextForTask("A", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b := mustFind(t, results, "B")
+	if b.Distance > 1e-9 {
+		t.Errorf("alpha=0 should price B purely on semantic distance (0 here), got %f", b.Distance)
+	}
+}
+
+func TestHybridContext_RespectsItemsOnlyAndNotSuperseded(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	// A -> Cat (category, excluded from results but still traversed)
+	//        -> B (live)
+	//        -> Stale (reachable only via a superseded edge)
+	// None of Cat/B/Stale have an embedding, so ANN seeding can't find them
+	// on its own -- every result here must come from graph traversal with
+	// hybrid edge-cost blending still applied.
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "Cat", "Category", false)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "Stale", "Stale Node", true)
+	insertEdge(t, d, "e1", "A", "Cat", "related", f64(0.5))
+	insertEdge(t, d, "e2", "Cat", "B", "related", f64(0.5))
+	if _, err := d.conn.Exec(
+		`INSERT INTO edges (id, source_id, target_id, type, created_at, confidence, superseded_by) VALUES (?, ?, ?, ?, 1000, ?, ?)`,
+		"e3", "Cat", "Stale", "related", 0.5, "e4",
+	); err != nil {
+		t.Fatal(err)
+	}
+
+	config := &ContextConfig{
+		Budget: 20, MaxHops: 6, MaxCost: 3.0,
+		ItemsOnly:      true,
+		NotSuperseded:  true,
+		ANNSeedVec:     []float32{1, 0},
+		SemanticWeight: 0.5,
+	}
+	results, err := d.ContextForTask("A", config)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, r := range results {
+		if r.NodeID == "Cat" {
+			t.Errorf("ItemsOnly should still exclude the category, even in hybrid mode: %+v", results)
+		}
+		if r.NodeID == "Stale" {
+			t.Errorf("NotSuperseded should still exclude the stale edge's target, even in hybrid mode: %+v", results)
+		}
+	}
+	mustFind(t, results, "B") // reached through Cat, which is still traversed
+}
+
+func mustFind(t *testing.T, results []ContextNode, nodeID string) *ContextNode {
+	t.Helper()
+	for i := range results {
+		if results[i].NodeID == nodeID {
+			return &results[i]
+		}
+	}
+	t.Fatalf("%s not found in %+v", nodeID, results)
+	return nil
+}
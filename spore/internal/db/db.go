@@ -11,6 +11,14 @@ import (
 type DB struct {
 	conn *sql.DB
 	Path string
+
+	// ranker is EdgesForContext's active ContextRanker, lazily loaded from
+	// context_ranker_config by contextRanker() on first use. nil until then.
+	ranker ContextRanker
+
+	// backend performs node/edge mutations -- see Backend in backend.go.
+	// Selected by OpenDB from MYCELICA_DB_MODE.
+	backend Backend
 }
 
 // OpenDB opens a SQLite database with WAL mode and foreign keys enabled
@@ -32,12 +40,23 @@ func OpenDB(path string) (*DB, error) {
 		return nil, fmt.Errorf("enabling foreign keys: %w", err)
 	}
 
-	return &DB{conn: conn, Path: path}, nil
+	return &DB{conn: conn, Path: path, backend: backendForEnv(path)}, nil
 }
 
-// Close closes the database connection
+// Close closes the database connection and the active Backend. Tests that
+// build a *DB via a struct literal instead of OpenDB leave backend nil.
 func (d *DB) Close() error {
-	return d.conn.Close()
+	connErr := d.conn.Close()
+	if d.backend == nil {
+		return connErr
+	}
+	if backendErr := d.backend.Close(); backendErr != nil {
+		if connErr != nil {
+			return fmt.Errorf("%v (also: closing backend: %w)", connErr, backendErr)
+		}
+		return fmt.Errorf("closing backend: %w", backendErr)
+	}
+	return connErr
 }
 
 // Conn returns the underlying sql.DB for custom queries
@@ -0,0 +1,256 @@
+package db
+
+import "math"
+
+// DanglingEdgeRef is an edge whose source or target doesn't name a node that
+// exists in the nodes table.
+type DanglingEdgeRef struct {
+	EdgeID      string `json:"edge_id"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	EdgeType    string `json:"edge_type"`
+	MissingNode string `json:"missing_node"` // whichever of Source/Target is absent; both if neither exists
+}
+
+// BrokenSupersession is an edge whose superseded_by pointer is bogus: it
+// either names an edge that doesn't exist, or the chain of superseded_by
+// pointers loops back on itself instead of terminating.
+type BrokenSupersession struct {
+	EdgeID       string `json:"edge_id"`
+	SupersededBy string `json:"superseded_by"`
+	Reason       string `json:"reason"` // "missing" or "cycle"
+}
+
+// DeadTrackSelfLoop is a self-referential "tracks" edge -- the shape
+// recordRunStatus writes on every task node -- whose own node no longer
+// exists.
+type DeadTrackSelfLoop struct {
+	EdgeID string `json:"edge_id"`
+	NodeID string `json:"node_id"`
+}
+
+// OrphanedEscalation is an escalation node (meta_type "escalation", created
+// by createEscalation) whose "tracks" edge to the task it escalated is
+// missing, or whose task node no longer exists.
+type OrphanedEscalation struct {
+	NodeID     string `json:"node_id"`
+	TaskNodeID string `json:"task_node_id,omitempty"` // empty if the tracks edge itself is missing
+}
+
+// OrphanedCategoryNode is a non-item node with no edge connecting it, in
+// either direction, to any item node.
+type OrphanedCategoryNode struct {
+	NodeID string `json:"node_id"`
+}
+
+// BadConfidenceEdge is an edge whose confidence falls outside [0,1] or is NaN.
+type BadConfidenceEdge struct {
+	EdgeID     string  `json:"edge_id"`
+	Confidence float64 `json:"confidence"`
+}
+
+// DoctorReport is the full audit of one database's graph integrity, scoped
+// to the orchestration-specific invariants CheckIntegrity doesn't cover:
+// supersession chains, run-tracking self-loops, escalation linkage, and
+// category/confidence hygiene.
+type DoctorReport struct {
+	DanglingEdges         []DanglingEdgeRef      `json:"dangling_edges"`
+	BrokenSupersessions   []BrokenSupersession   `json:"broken_supersessions"`
+	DeadTrackSelfLoops    []DeadTrackSelfLoop    `json:"dead_track_self_loops"`
+	OrphanedEscalations   []OrphanedEscalation   `json:"orphaned_escalations"`
+	OrphanedCategoryNodes []OrphanedCategoryNode `json:"orphaned_category_nodes"`
+	BadConfidenceEdges    []BadConfidenceEdge    `json:"bad_confidence_edges"`
+}
+
+// Count returns the total number of defects across every category.
+func (r *DoctorReport) Count() int {
+	return len(r.DanglingEdges) + len(r.BrokenSupersessions) + len(r.DeadTrackSelfLoops) +
+		len(r.OrphanedEscalations) + len(r.OrphanedCategoryNodes) + len(r.BadConfidenceEdges)
+}
+
+// Doctor audits the database for graph integrity defects without modifying
+// anything. It reads every node and edge into memory, so it's meant for
+// periodic/manual audits rather than a hot path.
+func (d *DB) Doctor() (*DoctorReport, error) {
+	nodes, err := d.AllNodes()
+	if err != nil {
+		return nil, err
+	}
+	edges, err := d.AllEdges()
+	if err != nil {
+		return nil, err
+	}
+
+	nodeByID := make(map[string]Node, len(nodes))
+	for _, n := range nodes {
+		nodeByID[n.ID] = n
+	}
+	edgeByID := make(map[string]Edge, len(edges))
+	for _, e := range edges {
+		edgeByID[e.ID] = e
+	}
+
+	report := &DoctorReport{}
+
+	for _, e := range edges {
+		_, hasSource := nodeByID[e.SourceID]
+		_, hasTarget := nodeByID[e.TargetID]
+		if !hasSource || !hasTarget {
+			missing := e.SourceID
+			if hasSource {
+				missing = e.TargetID
+			}
+			report.DanglingEdges = append(report.DanglingEdges, DanglingEdgeRef{
+				EdgeID: e.ID, Source: e.SourceID, Target: e.TargetID, EdgeType: e.EdgeType, MissingNode: missing,
+			})
+		}
+
+		if e.EdgeType == "tracks" && e.SourceID == e.TargetID && !hasSource {
+			report.DeadTrackSelfLoops = append(report.DeadTrackSelfLoops, DeadTrackSelfLoop{EdgeID: e.ID, NodeID: e.SourceID})
+		}
+
+		if e.Confidence != nil {
+			c := *e.Confidence
+			if math.IsNaN(c) || c < 0 || c > 1 {
+				report.BadConfidenceEdges = append(report.BadConfidenceEdges, BadConfidenceEdge{EdgeID: e.ID, Confidence: c})
+			}
+		}
+	}
+
+	report.BrokenSupersessions = findBrokenSupersessions(edges, edgeByID)
+
+	for _, n := range nodes {
+		if n.MetaType == nil || *n.MetaType != "escalation" {
+			continue
+		}
+		target, ok := trackTarget(edges, n.ID)
+		if !ok {
+			report.OrphanedEscalations = append(report.OrphanedEscalations, OrphanedEscalation{NodeID: n.ID})
+			continue
+		}
+		if _, exists := nodeByID[target]; !exists {
+			report.OrphanedEscalations = append(report.OrphanedEscalations, OrphanedEscalation{NodeID: n.ID, TaskNodeID: target})
+		}
+	}
+
+	touching := make(map[string][]string, len(nodes))
+	for _, e := range edges {
+		touching[e.SourceID] = append(touching[e.SourceID], e.TargetID)
+		touching[e.TargetID] = append(touching[e.TargetID], e.SourceID)
+	}
+	for _, n := range nodes {
+		if n.IsItem {
+			continue
+		}
+		connectedToItem := false
+		for _, other := range touching[n.ID] {
+			if otherNode, ok := nodeByID[other]; ok && otherNode.IsItem {
+				connectedToItem = true
+				break
+			}
+		}
+		if !connectedToItem {
+			report.OrphanedCategoryNodes = append(report.OrphanedCategoryNodes, OrphanedCategoryNode{NodeID: n.ID})
+		}
+	}
+
+	return report, nil
+}
+
+// findBrokenSupersessions walks each edge's superseded_by pointer, reporting
+// a "missing" defect when the pointer names an edge that doesn't exist, and
+// a "cycle" defect when following the chain loops back on itself instead of
+// terminating. Edges already attributed to a reported cycle are skipped as
+// additional starting points, so one cycle is reported once.
+func findBrokenSupersessions(edges []Edge, edgeByID map[string]Edge) []BrokenSupersession {
+	var broken []BrokenSupersession
+	inReportedCycle := make(map[string]bool)
+
+	for _, e := range edges {
+		if e.SupersededBy == nil || inReportedCycle[e.ID] {
+			continue
+		}
+
+		if _, ok := edgeByID[*e.SupersededBy]; !ok {
+			broken = append(broken, BrokenSupersession{EdgeID: e.ID, SupersededBy: *e.SupersededBy, Reason: "missing"})
+			continue
+		}
+
+		order := []string{}
+		seen := make(map[string]int)
+		cur := e.ID
+		for {
+			if idx, ok := seen[cur]; ok {
+				for _, id := range order[idx:] {
+					inReportedCycle[id] = true
+				}
+				broken = append(broken, BrokenSupersession{EdgeID: e.ID, SupersededBy: *e.SupersededBy, Reason: "cycle"})
+				break
+			}
+			seen[cur] = len(order)
+			order = append(order, cur)
+
+			ce, ok := edgeByID[cur]
+			if !ok || ce.SupersededBy == nil {
+				break
+			}
+			cur = *ce.SupersededBy
+		}
+	}
+
+	return broken
+}
+
+// trackTarget returns the target of escID's outgoing "tracks" edge -- the
+// edge createEscalation draws from the escalation node to the task node it
+// escalated -- and whether one was found.
+func trackTarget(edges []Edge, escID string) (string, bool) {
+	for _, e := range edges {
+		if e.EdgeType == "tracks" && e.SourceID == escID {
+			return e.TargetID, true
+		}
+	}
+	return "", false
+}
+
+// DoctorFixSummary records what --fix actually changed.
+type DoctorFixSummary struct {
+	DeletedEdges        int `json:"deleted_edges"`
+	ClearedSupersededBy int `json:"cleared_superseded_by"`
+}
+
+// DoctorFix applies the subset of report's defects that are safe to repair
+// automatically: it deletes every dangling edge and nulls out every bogus
+// superseded_by pointer, inside a single transaction so a mid-way failure
+// leaves the database unchanged. Everything else in report (dead track
+// self-loops, orphaned escalations, orphaned category nodes, bad confidence
+// values) is left for a human to resolve -- none of them have a repair
+// that's unambiguously correct to automate.
+func (d *DB) DoctorFix(report *DoctorReport) (*DoctorFixSummary, error) {
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	summary := &DoctorFixSummary{}
+
+	for _, de := range report.DanglingEdges {
+		if _, err := tx.Exec(`DELETE FROM edges WHERE id = ?`, de.EdgeID); err != nil {
+			return nil, err
+		}
+		summary.DeletedEdges++
+	}
+
+	for _, bs := range report.BrokenSupersessions {
+		if _, err := tx.Exec(`UPDATE edges SET superseded_by = NULL WHERE id = ?`, bs.EdgeID); err != nil {
+			return nil, err
+		}
+		summary.ClearedSupersededBy++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
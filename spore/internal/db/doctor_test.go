@@ -0,0 +1,238 @@
+package db
+
+import "testing"
+
+// insertNodeWithMeta is insertNode plus a meta_type, for escalation/category fixtures.
+func insertNodeWithMeta(t *testing.T, d *DB, id, title string, isItem bool, metaType string) {
+	t.Helper()
+	item := 0
+	if isItem {
+		item = 1
+	}
+	_, err := d.conn.Exec(
+		`INSERT INTO nodes (id, type, title, created_at, updated_at, is_item, meta_type) VALUES (?, 'page', ?, 1000, 1000, ?, ?)`,
+		id, title, item, metaType,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+// insertEdgeSuperseded is insertEdge plus a superseded_by pointer.
+func insertEdgeSuperseded(t *testing.T, d *DB, id, source, target, edgeType string, supersededBy *string) {
+	t.Helper()
+	_, err := d.conn.Exec(
+		`INSERT INTO edges (id, source_id, target_id, type, created_at, superseded_by) VALUES (?, ?, ?, ?, 1000, ?)`,
+		id, source, target, edgeType, supersededBy,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func strp(s string) *string { return &s }
+
+func TestDoctor_CleanGraphReportsNoDefects(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "supports", f64(0.8))
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Count() != 0 {
+		t.Fatalf("expected a clean report, got %+v", report)
+	}
+}
+
+func TestDoctor_DanglingEdge(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertEdge(t, d, "e1", "A", "ghost", "supports", f64(0.8))
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.DanglingEdges) != 1 {
+		t.Fatalf("expected 1 dangling edge, got %+v", report.DanglingEdges)
+	}
+	de := report.DanglingEdges[0]
+	if de.EdgeID != "e1" || de.MissingNode != "ghost" {
+		t.Errorf("got %+v", de)
+	}
+}
+
+func TestDoctor_BrokenSupersessionMissing(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdgeSuperseded(t, d, "e1", "A", "B", "supports", strp("ghost-edge"))
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.BrokenSupersessions) != 1 {
+		t.Fatalf("expected 1 broken supersession, got %+v", report.BrokenSupersessions)
+	}
+	if got := report.BrokenSupersessions[0].Reason; got != "missing" {
+		t.Errorf("reason = %q, want missing", got)
+	}
+}
+
+func TestDoctor_BrokenSupersessionCycle(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdgeSuperseded(t, d, "e1", "A", "B", "supports", strp("e2"))
+	insertEdgeSuperseded(t, d, "e2", "A", "B", "supports", strp("e1"))
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.BrokenSupersessions) != 1 {
+		t.Fatalf("expected the cycle reported once, got %+v", report.BrokenSupersessions)
+	}
+	if got := report.BrokenSupersessions[0].Reason; got != "cycle" {
+		t.Errorf("reason = %q, want cycle", got)
+	}
+}
+
+func TestDoctor_DeadTrackSelfLoop(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertEdge(t, d, "e1", "gone", "gone", "tracks", nil)
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.DeadTrackSelfLoops) != 1 {
+		t.Fatalf("expected 1 dead track self-loop, got %+v", report.DeadTrackSelfLoops)
+	}
+	if got := report.DeadTrackSelfLoops[0].NodeID; got != "gone" {
+		t.Errorf("node = %q, want gone", got)
+	}
+}
+
+func TestDoctor_OrphanedEscalation(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNodeWithMeta(t, d, "esc1", "Escalation: do the thing", true, "escalation")
+	insertEdge(t, d, "e1", "esc1", "task-gone", "tracks", nil)
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanedEscalations) != 1 {
+		t.Fatalf("expected 1 orphaned escalation, got %+v", report.OrphanedEscalations)
+	}
+	if got := report.OrphanedEscalations[0].TaskNodeID; got != "task-gone" {
+		t.Errorf("task node = %q, want task-gone", got)
+	}
+}
+
+func TestDoctor_OrphanedEscalationNoTrackEdge(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNodeWithMeta(t, d, "esc1", "Escalation: do the thing", true, "escalation")
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanedEscalations) != 1 || report.OrphanedEscalations[0].TaskNodeID != "" {
+		t.Fatalf("expected 1 orphaned escalation with no task node, got %+v", report.OrphanedEscalations)
+	}
+}
+
+func TestDoctor_OrphanedCategoryNode(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "cat1", "Category", false)
+	insertNode(t, d, "item1", "An item", true)
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanedCategoryNodes) != 1 || report.OrphanedCategoryNodes[0].NodeID != "cat1" {
+		t.Fatalf("expected cat1 orphaned, got %+v", report.OrphanedCategoryNodes)
+	}
+
+	insertEdge(t, d, "e1", "cat1", "item1", "belongs_to", nil)
+	report, err = d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.OrphanedCategoryNodes) != 0 {
+		t.Fatalf("expected no orphans once linked to an item, got %+v", report.OrphanedCategoryNodes)
+	}
+}
+
+func TestDoctor_BadConfidence(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "supports", f64(1.5))
+	insertEdge(t, d, "e2", "A", "B", "related", f64(-0.1))
+	insertEdge(t, d, "e3", "A", "B", "questions", f64(0.5))
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(report.BadConfidenceEdges) != 2 {
+		t.Fatalf("expected 2 bad-confidence edges, got %+v", report.BadConfidenceEdges)
+	}
+}
+
+func TestDoctorFix_DeletesDanglingAndClearsSupersession(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "ghost", "supports", f64(0.8))
+	insertEdgeSuperseded(t, d, "e2", "A", "B", "supports", strp("ghost-edge"))
+
+	report, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	fixed, err := d.DoctorFix(report)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fixed.DeletedEdges != 1 || fixed.ClearedSupersededBy != 1 {
+		t.Fatalf("got %+v", fixed)
+	}
+
+	after, err := d.Doctor()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if after.Count() != 0 {
+		t.Fatalf("expected a clean report after fix, got %+v", after)
+	}
+}
@@ -0,0 +1,43 @@
+package db
+
+// EdgeKindRegistry maps a directional edge kind to its semantic mirror (e.g.
+// "references" <-> "referenced_by"), so a relation authored in one direction
+// can be matched -- or, via ContextForTask's FollowInverse, traversed -- as
+// its inverse without a second row in the edges table. graph.NewSnapshot
+// uses the same registry to synthesize mirror adjacency entries.
+type EdgeKindRegistry struct {
+	mirrors map[string]string
+}
+
+// NewEdgeKindRegistry returns an empty registry.
+func NewEdgeKindRegistry() *EdgeKindRegistry {
+	return &EdgeKindRegistry{mirrors: make(map[string]string)}
+}
+
+// DefaultEdgeKindRegistry returns the mirror pairs spore ships with.
+func DefaultEdgeKindRegistry() *EdgeKindRegistry {
+	r := NewEdgeKindRegistry()
+	r.Register("references", "referenced_by")
+	r.Register("summarizes", "summarized_by")
+	r.Register("supersedes", "superseded_by")
+	return r
+}
+
+// Register adds a bidirectional mirror pair: kind maps to inverse and
+// inverse maps back to kind.
+func (r *EdgeKindRegistry) Register(kind, inverse string) {
+	r.mirrors[kind] = inverse
+	r.mirrors[inverse] = kind
+}
+
+// Mirror returns kind's registered inverse, or "" if kind has none.
+func (r *EdgeKindRegistry) Mirror(kind string) string {
+	return r.mirrors[kind]
+}
+
+// Matches reports whether candidate is kind itself or kind's registered
+// mirror -- the rule ContextForTask's --edge-types allow/exclude lists use
+// so they match either side of a mirror pair.
+func (r *EdgeKindRegistry) Matches(kind, candidate string) bool {
+	return candidate == kind || r.Mirror(kind) == candidate
+}
@@ -1,6 +1,6 @@
 package db
 
-import "sort"
+import "fmt"
 
 // scanEdge scans a row into an Edge. The row must have all 14 columns in standard order.
 func scanEdge(scanner interface{ Scan(dest ...any) error }) (Edge, error) {
@@ -88,49 +88,50 @@ func IsStructuralEdge(edgeType string) bool {
 	}
 }
 
-// EdgesForContext returns the top-N most relevant edges for a node,
-// scored by 0.3*recency + 0.3*confidence + 0.4*type_priority.
-// Matches schema.rs:5674-5709.
+// EdgesForContext returns the top-N most relevant edges for a node, scored
+// by d's active ContextRanker (DefaultLinearRanker's
+// 0.3*recency + 0.3*confidence + 0.4*type_priority blend, originally
+// matching schema.rs:5674-5709, unless overridden via SetContextRanker or a
+// saved context_ranker_config row). Implemented as the MaxDepth=1 special
+// case of Expand: a single-node frontier expanded in both directions,
+// scored and truncated by ScoreEdges. When the active ranker is a
+// *LinearRanker, each call's score components are logged to ranker_traces
+// for later CalibrateRanker training.
 func (d *DB) EdgesForContext(nodeID string, topN int, notSuperseded bool) ([]Edge, error) {
-	all, err := d.GetEdgesForNode(nodeID)
+	expanded, err := d.Expand([]string{nodeID}, ExpandSpec{
+		Direction:         DirectionBoth,
+		MaxDepth:          1,
+		NotSuperseded:     notSuperseded,
+		IncludeStructural: true,
+	})
 	if err != nil {
 		return nil, err
 	}
 
-	if notSuperseded {
-		filtered := all[:0]
-		for _, e := range all {
-			if e.SupersededBy == nil {
-				filtered = append(filtered, e)
-			}
-		}
-		all = filtered
-	}
-
-	if len(all) == 0 {
-		return all, nil
+	ranker, err := d.contextRanker()
+	if err != nil {
+		return nil, err
 	}
+	scored := ScoreEdges(expanded.Edges, topN, ranker)
 
-	// Compute time range for recency normalization
-	oldest := all[0].CreatedAt
-	newest := all[0].CreatedAt
-	for _, e := range all[1:] {
-		if e.CreatedAt < oldest {
-			oldest = e.CreatedAt
-		}
-		if e.CreatedAt > newest {
-			newest = e.CreatedAt
+	if lr, ok := ranker.(*LinearRanker); ok && len(expanded.Edges) > 0 {
+		if err := d.logRankerTraces(buildRankerTraces(scored, lr, expanded.Edges)); err != nil {
+			return nil, fmt.Errorf("logging ranker traces: %w", err)
 		}
 	}
+	return scored, nil
+}
+
+// buildRankerTraces computes the score components lr.Score folds together
+// for each edge in scored, so CalibrateRanker can regress against them
+// later. allEdges is the unscored candidate set, used for the same
+// oldest/newest recency normalization lr.Score itself used.
+func buildRankerTraces(scored []Edge, lr *LinearRanker, allEdges []Edge) []rankerTrace {
+	oldest, newest := edgeTimeRange(allEdges)
 	timeRange := float64(newest - oldest)
 
-	// Score and sort
-	type scored struct {
-		score float64
-		edge  Edge
-	}
-	items := make([]scored, len(all))
-	for i, e := range all {
+	traces := make([]rankerTrace, len(scored))
+	for i, e := range scored {
 		recency := 1.0
 		if timeRange > 0 {
 			recency = float64(e.CreatedAt-oldest) / timeRange
@@ -139,24 +140,13 @@ func (d *DB) EdgesForContext(nodeID string, topN int, notSuperseded bool) ([]Edg
 		if e.Confidence != nil {
 			confidence = *e.Confidence
 		}
-		typePriority := EdgeTypePriority(e.EdgeType)
-		items[i] = scored{
-			score: 0.3*recency + 0.3*confidence + 0.4*typePriority,
-			edge:  e,
+		traces[i] = rankerTrace{
+			edgeID:       e.ID,
+			recency:      recency,
+			confidence:   confidence,
+			typePriority: lr.typePriority(e.EdgeType),
+			finalScore:   lr.Score(e, oldest, newest),
 		}
 	}
-
-	sort.Slice(items, func(i, j int) bool {
-		return items[i].score > items[j].score
-	})
-
-	if len(items) > topN {
-		items = items[:topN]
-	}
-
-	result := make([]Edge, len(items))
-	for i, s := range items {
-		result[i] = s.edge
-	}
-	return result, nil
+	return traces
 }
@@ -2,15 +2,34 @@ package db
 
 import (
 	"encoding/binary"
+	"encoding/json"
+	"fmt"
 	"math"
 )
 
+// DefaultEmbeddingModel is the model ID legacy callers use when they don't
+// care about comparing multiple embedding models. GetNodeEmbedding and
+// friends fall back to the old single-column nodes.embedding for this model
+// ID when node_embeddings has no row yet, so existing CLI-populated
+// embeddings keep working without a backfill.
+const DefaultEmbeddingModel = "default"
+
 // NodeEmbedding pairs a node ID with its deserialized embedding vector.
 type NodeEmbedding struct {
 	ID        string
 	Embedding []float32
 }
 
+// EmbeddingModel describes one registered embedding model: its vector
+// dimensionality and arbitrary metadata (provider, checkpoint, etc), so
+// multiple models' vectors can coexist on the same node (see
+// RegisterEmbeddingModel).
+type EmbeddingModel struct {
+	ID   string
+	Dim  int
+	Meta map[string]string
+}
+
 // bytesToEmbedding converts a little-endian byte slice to []float32.
 // Each 4 bytes = one LE float32. Short trailing chunk → 0.0.
 func bytesToEmbedding(data []byte) []float32 {
@@ -26,8 +45,123 @@ func bytesToEmbedding(data []byte) []float32 {
 	return result
 }
 
-// GetNodeEmbedding returns the embedding for a single node, or nil if not set.
-func (d *DB) GetNodeEmbedding(id string) ([]float32, error) {
+// bytesToEmbeddingForDim is bytesToEmbedding's strict counterpart for rows
+// stamped with a registered model's dimensionality: a length mismatch means
+// the row or the model registration is corrupt, so it's reported rather than
+// silently padded.
+func bytesToEmbeddingForDim(data []byte, dim int) ([]float32, error) {
+	if len(data) != 4*dim {
+		return nil, fmt.Errorf("embedding is %d bytes, want %d for dim %d", len(data), 4*dim, dim)
+	}
+	return bytesToEmbedding(data), nil
+}
+
+// ensureEmbeddingSchema creates the node_embeddings and embedding_models
+// tables if they're missing. Idempotent; cheap enough to call at the top of
+// every exported method in this file, matching the HNSWIndex convention.
+func (d *DB) ensureEmbeddingSchema() error {
+	_, err := d.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS embedding_models (
+			id         TEXT PRIMARY KEY,
+			dim        INTEGER NOT NULL,
+			meta       TEXT NOT NULL DEFAULT '{}',
+			created_at INTEGER NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS node_embeddings (
+			node_id    TEXT NOT NULL,
+			model_id   TEXT NOT NULL,
+			dim        INTEGER NOT NULL,
+			vector     BLOB NOT NULL,
+			created_at INTEGER NOT NULL,
+			PRIMARY KEY (node_id, model_id)
+		);
+	`)
+	return err
+}
+
+// RegisterEmbeddingModel records a model ID's dimensionality and metadata so
+// SetNodeEmbedding/GetNodeEmbedding can validate vectors stored under it.
+// Safe to call repeatedly for the same ID; the latest dim/meta win.
+func (d *DB) RegisterEmbeddingModel(id string, dim int, meta map[string]string) error {
+	if err := d.ensureEmbeddingSchema(); err != nil {
+		return fmt.Errorf("ensuring embedding schema: %w", err)
+	}
+	if meta == nil {
+		meta = map[string]string{}
+	}
+	metaJSON, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("marshaling embedding model metadata: %w", err)
+	}
+	_, err = d.conn.Exec(`
+		INSERT INTO embedding_models (id, dim, meta, created_at) VALUES (?, ?, ?, unixepoch())
+		ON CONFLICT(id) DO UPDATE SET dim = excluded.dim, meta = excluded.meta
+	`, id, dim, string(metaJSON))
+	return err
+}
+
+// ListEmbeddingModels returns every registered embedding model.
+func (d *DB) ListEmbeddingModels() ([]EmbeddingModel, error) {
+	if err := d.ensureEmbeddingSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring embedding schema: %w", err)
+	}
+	rows, err := d.conn.Query(`SELECT id, dim, meta FROM embedding_models ORDER BY id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var models []EmbeddingModel
+	for rows.Next() {
+		var m EmbeddingModel
+		var metaJSON string
+		if err := rows.Scan(&m.ID, &m.Dim, &metaJSON); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal([]byte(metaJSON), &m.Meta); err != nil {
+			return nil, fmt.Errorf("parsing metadata for model %s: %w", m.ID, err)
+		}
+		models = append(models, m)
+	}
+	return models, rows.Err()
+}
+
+// SetNodeEmbedding stores vec for nodeID under modelID in node_embeddings,
+// replacing any previous vector for that (node, model) pair.
+func (d *DB) SetNodeEmbedding(nodeID, modelID string, vec []float32) error {
+	if err := d.ensureEmbeddingSchema(); err != nil {
+		return fmt.Errorf("ensuring embedding schema: %w", err)
+	}
+	_, err := d.conn.Exec(`
+		INSERT INTO node_embeddings (node_id, model_id, dim, vector, created_at) VALUES (?, ?, ?, ?, unixepoch())
+		ON CONFLICT(node_id, model_id) DO UPDATE SET dim = excluded.dim, vector = excluded.vector, created_at = excluded.created_at
+	`, nodeID, modelID, len(vec), embeddingToBytes(vec))
+	return err
+}
+
+// GetNodeEmbedding returns id's embedding under modelID, or nil if not set.
+// For DefaultEmbeddingModel, falls back to the legacy nodes.embedding column
+// when node_embeddings has no row, so pre-existing CLI-populated embeddings
+// keep working without a backfill.
+func (d *DB) GetNodeEmbedding(id, modelID string) ([]float32, error) {
+	if err := d.ensureEmbeddingSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring embedding schema: %w", err)
+	}
+
+	var data []byte
+	var dim int
+	err := d.conn.QueryRow(`SELECT vector, dim FROM node_embeddings WHERE node_id = ? AND model_id = ?`, id, modelID).Scan(&data, &dim)
+	if err == nil {
+		return bytesToEmbeddingForDim(data, dim)
+	}
+
+	if modelID != DefaultEmbeddingModel {
+		return nil, nil
+	}
+	return d.getLegacyNodeEmbedding(id)
+}
+
+func (d *DB) getLegacyNodeEmbedding(id string) ([]float32, error) {
 	var data []byte
 	err := d.conn.QueryRow("SELECT embedding FROM nodes WHERE id = ?", id).Scan(&data)
 	if err != nil {
@@ -39,32 +173,91 @@ func (d *DB) GetNodeEmbedding(id string) ([]float32, error) {
 	return bytesToEmbedding(data), nil
 }
 
-// GetNodesWithEmbeddings returns all (id, embedding) pairs for nodes that have embeddings.
-func (d *DB) GetNodesWithEmbeddings() ([]NodeEmbedding, error) {
-	rows, err := d.conn.Query("SELECT id, embedding FROM nodes WHERE embedding IS NOT NULL")
-	if err != nil {
-		return nil, err
+// GetNodesWithEmbeddings returns every (id, embedding) pair registered under
+// modelID. For DefaultEmbeddingModel, also includes nodes whose embedding
+// only exists in the legacy nodes.embedding column (skipping any that
+// already have a node_embeddings row, which takes precedence).
+func (d *DB) GetNodesWithEmbeddings(modelID string) ([]NodeEmbedding, error) {
+	if err := d.ensureEmbeddingSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring embedding schema: %w", err)
 	}
-	defer rows.Close()
 
+	seen := map[string]bool{}
 	var result []NodeEmbedding
+
+	rows, err := d.conn.Query(`SELECT node_id, vector, dim FROM node_embeddings WHERE model_id = ?`, modelID)
+	if err != nil {
+		return nil, err
+	}
 	for rows.Next() {
 		var id string
 		var data []byte
-		if err := rows.Scan(&id, &data); err != nil {
+		var dim int
+		if err := rows.Scan(&id, &data, &dim); err != nil {
+			rows.Close()
+			return nil, err
+		}
+		vec, err := bytesToEmbeddingForDim(data, dim)
+		if err != nil {
+			rows.Close()
+			return nil, fmt.Errorf("node %s: %w", id, err)
+		}
+		seen[id] = true
+		result = append(result, NodeEmbedding{ID: id, Embedding: vec})
+	}
+	if err := rows.Err(); err != nil {
+		rows.Close()
+		return nil, err
+	}
+	rows.Close()
+
+	if modelID != DefaultEmbeddingModel {
+		return result, nil
+	}
+
+	legacyRows, err := d.conn.Query("SELECT id, embedding FROM nodes WHERE embedding IS NOT NULL")
+	if err != nil {
+		return nil, err
+	}
+	defer legacyRows.Close()
+	for legacyRows.Next() {
+		var id string
+		var data []byte
+		if err := legacyRows.Scan(&id, &data); err != nil {
 			return nil, err
 		}
-		result = append(result, NodeEmbedding{
-			ID:        id,
-			Embedding: bytesToEmbedding(data),
-		})
+		if seen[id] {
+			continue
+		}
+		result = append(result, NodeEmbedding{ID: id, Embedding: bytesToEmbedding(data)})
 	}
-	return result, rows.Err()
+	return result, legacyRows.Err()
 }
 
-// CountNodesWithEmbeddings returns the count of nodes with non-null embeddings.
-func (d *DB) CountNodesWithEmbeddings() (int, error) {
+// CountNodesWithEmbeddings returns the count of nodes with a vector under
+// modelID (node_embeddings for any model, plus the legacy nodes.embedding
+// column when modelID is DefaultEmbeddingModel).
+func (d *DB) CountNodesWithEmbeddings(modelID string) (int, error) {
+	if err := d.ensureEmbeddingSchema(); err != nil {
+		return 0, fmt.Errorf("ensuring embedding schema: %w", err)
+	}
+
 	var count int
-	err := d.conn.QueryRow("SELECT COUNT(*) FROM nodes WHERE embedding IS NOT NULL").Scan(&count)
-	return count, err
+	if err := d.conn.QueryRow(`SELECT COUNT(*) FROM node_embeddings WHERE model_id = ?`, modelID).Scan(&count); err != nil {
+		return 0, err
+	}
+	if modelID != DefaultEmbeddingModel {
+		return count, nil
+	}
+
+	var legacyCount int
+	err := d.conn.QueryRow(`
+		SELECT COUNT(*) FROM nodes
+		WHERE embedding IS NOT NULL
+		AND id NOT IN (SELECT node_id FROM node_embeddings WHERE model_id = ?)
+	`, modelID).Scan(&legacyCount)
+	if err != nil {
+		return 0, err
+	}
+	return count + legacyCount, nil
 }
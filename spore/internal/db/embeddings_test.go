@@ -72,3 +72,112 @@ func TestBytesToEmbedding_384Dim(t *testing.T) {
 		t.Errorf("result[100] should be ~1.0, got %f", result[100])
 	}
 }
+
+func TestBytesToEmbeddingForDim_Mismatch(t *testing.T) {
+	data := make([]byte, 8) // 2 floats
+	if _, err := bytesToEmbeddingForDim(data, 3); err == nil {
+		t.Error("expected an error for a dim/length mismatch, got nil")
+	}
+	if _, err := bytesToEmbeddingForDim(data, 2); err != nil {
+		t.Errorf("expected no error for a matching dim, got %v", err)
+	}
+}
+
+func TestRegisterAndListEmbeddingModels(t *testing.T) {
+	d, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("opening db: %v", err)
+	}
+	defer d.Close()
+
+	if err := d.RegisterEmbeddingModel("text-embedding-3-small", 1536, map[string]string{"provider": "openai"}); err != nil {
+		t.Fatalf("registering model: %v", err)
+	}
+	if err := d.RegisterEmbeddingModel("nomic-embed-v1.5", 768, nil); err != nil {
+		t.Fatalf("registering model: %v", err)
+	}
+
+	models, err := d.ListEmbeddingModels()
+	if err != nil {
+		t.Fatalf("listing models: %v", err)
+	}
+	if len(models) != 2 {
+		t.Fatalf("expected 2 models, got %d", len(models))
+	}
+	if models[1].ID != "text-embedding-3-small" || models[1].Dim != 1536 || models[1].Meta["provider"] != "openai" {
+		t.Errorf("models[1] = %+v, want text-embedding-3-small/1536/openai", models[1])
+	}
+}
+
+func TestSetAndGetNodeEmbedding_PerModel(t *testing.T) {
+	d, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("opening db: %v", err)
+	}
+	defer d.Close()
+
+	small := []float32{0.1, 0.2, 0.3}
+	big := []float32{0.4, 0.5, 0.6, 0.7}
+	if err := d.SetNodeEmbedding("n1", "model-a", small); err != nil {
+		t.Fatalf("setting embedding: %v", err)
+	}
+	if err := d.SetNodeEmbedding("n1", "model-b", big); err != nil {
+		t.Fatalf("setting embedding: %v", err)
+	}
+
+	gotA, err := d.GetNodeEmbedding("n1", "model-a")
+	if err != nil {
+		t.Fatalf("getting embedding: %v", err)
+	}
+	if len(gotA) != 3 || gotA[0] != 0.1 {
+		t.Errorf("model-a embedding = %v, want %v", gotA, small)
+	}
+
+	gotB, err := d.GetNodeEmbedding("n1", "model-b")
+	if err != nil {
+		t.Fatalf("getting embedding: %v", err)
+	}
+	if len(gotB) != 4 || gotB[3] != 0.7 {
+		t.Errorf("model-b embedding = %v, want %v", gotB, big)
+	}
+
+	count, err := d.CountNodesWithEmbeddings("model-a")
+	if err != nil {
+		t.Fatalf("counting: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("count = %d, want 1", count)
+	}
+}
+
+func TestGetNodeEmbedding_DefaultModelFallsBackToLegacyColumn(t *testing.T) {
+	d, err := OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("opening db: %v", err)
+	}
+	defer d.Close()
+
+	if _, err := d.conn.Exec(`CREATE TABLE nodes (id TEXT PRIMARY KEY, embedding BLOB)`); err != nil {
+		t.Fatalf("creating nodes table: %v", err)
+	}
+	if _, err := d.conn.Exec(`INSERT INTO nodes (id, embedding) VALUES (?, ?)`, "n1", embeddingToBytes([]float32{1, 2})); err != nil {
+		t.Fatalf("inserting legacy embedding: %v", err)
+	}
+
+	got, err := d.GetNodeEmbedding("n1", DefaultEmbeddingModel)
+	if err != nil {
+		t.Fatalf("getting embedding: %v", err)
+	}
+	if len(got) != 2 || got[0] != 1 || got[1] != 2 {
+		t.Errorf("got %v, want legacy embedding [1 2]", got)
+	}
+
+	// A non-default model must not see the legacy column.
+	got, err = d.GetNodeEmbedding("n1", "other-model")
+	if err != nil {
+		t.Fatalf("getting embedding: %v", err)
+	}
+	if got != nil {
+		t.Errorf("expected nil for non-default model, got %v", got)
+	}
+}
@@ -0,0 +1,286 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Direction constrains which end of an edge Expand treats as the "outgoing"
+// side when stepping from the frontier to its neighbors.
+type Direction string
+
+const (
+	DirectionOut  Direction = "out"
+	DirectionIn   Direction = "in"
+	DirectionBoth Direction = "both"
+)
+
+// ExpandSpec configures one Expand traversal, mirroring Dgraph's
+// expand(predicate) primitive: start from seeds, follow matching edges one
+// layer at a time, and stop at MaxDepth or MaxVisited.
+type ExpandSpec struct {
+	EdgeTypes         []string // allowlist; nil means all
+	Direction         Direction
+	MaxDepth          int
+	NotSuperseded     bool
+	IncludeStructural bool // whether to follow edges IsStructuralEdge flags
+
+	// MaxVisited caps the total number of distinct node IDs Expand will
+	// discover across all layers, including seeds. Defaults to 10000.
+	MaxVisited int
+}
+
+// ExpandResult is the outcome of one Expand traversal.
+type ExpandResult struct {
+	// Frontiers[0] is the seed set; Frontiers[i] is the newly discovered
+	// nodes at depth i.
+	Frontiers [][]string
+	// HitCounts counts, per discovered node, how many distinct nodes in the
+	// layer before it had an edge leading to it — the "reached by the most
+	// distinct sources" signal from Dgraph's film/director traversal pattern.
+	HitCounts map[string]int
+	// Edges are all edges actually traversed, in depth order.
+	Edges []Edge
+}
+
+// EdgesForContext's score blend, factored out so ScoreEdges can't drift from it.
+const (
+	scoreWeightRecency    = 0.3
+	scoreWeightConfidence = 0.3
+	scoreWeightType       = 0.4
+)
+
+// Expand performs a layered BFS from seeds, following edges that match spec,
+// batching each layer's neighbor lookup into a single IN (...) query rather
+// than one query per frontier node. EdgesForContext is the MaxDepth=1 special
+// case of this traversal.
+func (d *DB) Expand(seeds []string, spec ExpandSpec) (*ExpandResult, error) {
+	result := &ExpandResult{HitCounts: map[string]int{}}
+	if len(seeds) == 0 {
+		return result, nil
+	}
+
+	maxDepth := spec.MaxDepth
+	if maxDepth <= 0 {
+		maxDepth = 1
+	}
+	maxVisited := spec.MaxVisited
+	if maxVisited <= 0 {
+		maxVisited = 10000
+	}
+	direction := spec.Direction
+	if direction == "" {
+		direction = DirectionBoth
+	}
+
+	visited := make(map[string]bool, len(seeds))
+	seedLayer := append([]string(nil), seeds...)
+	for _, s := range seedLayer {
+		visited[s] = true
+	}
+	result.Frontiers = append(result.Frontiers, seedLayer)
+
+	hitSources := map[string]map[string]bool{}
+	frontier := seedLayer
+
+	for depth := 0; depth < maxDepth && len(frontier) > 0 && len(visited) < maxVisited; depth++ {
+		edges, err := d.edgesTouching(frontier, direction)
+		if err != nil {
+			return nil, fmt.Errorf("expanding frontier at depth %d: %w", depth, err)
+		}
+		edges = filterEdges(edges, spec.EdgeTypes, spec.NotSuperseded, spec.IncludeStructural)
+
+		frontierSet := make(map[string]bool, len(frontier))
+		for _, id := range frontier {
+			frontierSet[id] = true
+		}
+
+		var next []string
+		nextSeen := map[string]bool{}
+		for _, e := range edges {
+			from, to, ok := stepEdge(e, frontierSet, direction)
+			if !ok {
+				continue
+			}
+
+			result.Edges = append(result.Edges, e)
+			if hitSources[to] == nil {
+				hitSources[to] = map[string]bool{}
+			}
+			hitSources[to][from] = true
+
+			if visited[to] || nextSeen[to] {
+				continue
+			}
+			if len(visited) >= maxVisited {
+				continue
+			}
+			visited[to] = true
+			nextSeen[to] = true
+			next = append(next, to)
+		}
+
+		if len(next) == 0 {
+			break
+		}
+		result.Frontiers = append(result.Frontiers, next)
+		frontier = next
+	}
+
+	for target, sources := range hitSources {
+		result.HitCounts[target] = len(sources)
+	}
+	return result, nil
+}
+
+// stepEdge decides whether e advances the traversal from the current
+// frontier, and if so which end is the source ("from") and which is the
+// newly-reached neighbor ("to"). Edges connecting two nodes already in the
+// same frontier layer are attributed once, from source to target.
+func stepEdge(e Edge, frontierSet map[string]bool, direction Direction) (from, to string, ok bool) {
+	switch {
+	case frontierSet[e.SourceID] && direction != DirectionIn:
+		return e.SourceID, e.TargetID, true
+	case frontierSet[e.TargetID] && direction != DirectionOut:
+		return e.TargetID, e.SourceID, true
+	default:
+		return "", "", false
+	}
+}
+
+// filterEdges applies Expand's type allowlist, superseded, and structural
+// filters. Kept separate from the SQL in edgesTouching so the batched query
+// only has to vary on direction and frontier membership.
+func filterEdges(edges []Edge, edgeTypes []string, notSuperseded, includeStructural bool) []Edge {
+	var allowSet map[string]bool
+	if edgeTypes != nil {
+		allowSet = make(map[string]bool, len(edgeTypes))
+		for _, t := range edgeTypes {
+			allowSet[t] = true
+		}
+	}
+
+	filtered := edges[:0]
+	for _, e := range edges {
+		if allowSet != nil && !allowSet[e.EdgeType] {
+			continue
+		}
+		if notSuperseded && e.SupersededBy != nil {
+			continue
+		}
+		if !includeStructural && IsStructuralEdge(e.EdgeType) {
+			continue
+		}
+		filtered = append(filtered, e)
+	}
+	return filtered
+}
+
+// edgesTouching fetches, in a single query, every edge touching any node in
+// ids — the batched counterpart to GetEdgesForNode that Expand uses to avoid
+// one query per frontier node per layer.
+func (d *DB) edgesTouching(ids []string, direction Direction) ([]Edge, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(ids))
+	for i := range ids {
+		placeholders[i] = "?"
+	}
+	inClause := strings.Join(placeholders, ", ")
+
+	var where string
+	var args []any
+	switch direction {
+	case DirectionOut:
+		where = fmt.Sprintf("source_id IN (%s)", inClause)
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	case DirectionIn:
+		where = fmt.Sprintf("target_id IN (%s)", inClause)
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	default: // DirectionBoth
+		where = fmt.Sprintf("source_id IN (%s) OR target_id IN (%s)", inClause, inClause)
+		for _, id := range ids {
+			args = append(args, id)
+		}
+		for _, id := range ids {
+			args = append(args, id)
+		}
+	}
+
+	rows, err := d.conn.Query(`
+		SELECT id, source_id, target_id, type, label, weight, confidence,
+		       agent_id, reason, content, created_at, updated_at,
+		       superseded_by, metadata
+		FROM edges WHERE `+where, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var edges []Edge
+	for rows.Next() {
+		e, err := scanEdge(rows)
+		if err != nil {
+			return nil, err
+		}
+		edges = append(edges, e)
+	}
+	return edges, rows.Err()
+}
+
+// ScoreEdges scores a set of edges with ranker and returns them sorted
+// descending by score, truncated to topN. Exposed so Expand callers can
+// rank a multi-hop result the same way EdgesForContext ranks a single-hop
+// one. Pass DefaultLinearRanker() to reproduce EdgesForContext's original
+// hardcoded blend.
+func ScoreEdges(edges []Edge, topN int, ranker ContextRanker) []Edge {
+	if len(edges) == 0 {
+		return edges
+	}
+	oldest, newest := edgeTimeRange(edges)
+
+	type scored struct {
+		score float64
+		edge  Edge
+	}
+	items := make([]scored, len(edges))
+	for i, e := range edges {
+		items[i] = scored{score: ranker.Score(e, oldest, newest), edge: e}
+	}
+
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].score > items[j].score
+	})
+	if len(items) > topN {
+		items = items[:topN]
+	}
+
+	result := make([]Edge, len(items))
+	for i, s := range items {
+		result[i] = s.edge
+	}
+	return result
+}
+
+// edgeTimeRange returns the min and max CreatedAt across edges, which
+// ScoreEdges and EdgesForContext's ranker-trace logging both use to
+// normalize recency the same way. Callers must pass a non-empty slice.
+func edgeTimeRange(edges []Edge) (oldest, newest int64) {
+	oldest, newest = edges[0].CreatedAt, edges[0].CreatedAt
+	for _, e := range edges[1:] {
+		if e.CreatedAt < oldest {
+			oldest = e.CreatedAt
+		}
+		if e.CreatedAt > newest {
+			newest = e.CreatedAt
+		}
+	}
+	return oldest, newest
+}
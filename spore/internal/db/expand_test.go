@@ -0,0 +1,188 @@
+package db
+
+import "testing"
+
+// A -> B -> C, a simple two-hop chain.
+func TestExpand_TwoHopChain(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertEdge(t, d, "e1", "A", "B", "derives_from", f64(0.8))
+	insertEdge(t, d, "e2", "B", "C", "derives_from", f64(0.8))
+
+	result, err := d.Expand([]string{"A"}, ExpandSpec{Direction: DirectionOut, MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(result.Frontiers) != 3 {
+		t.Fatalf("expected 3 frontiers (seed, depth1, depth2), got %d: %v", len(result.Frontiers), result.Frontiers)
+	}
+	if got := result.Frontiers[1]; len(got) != 1 || got[0] != "B" {
+		t.Errorf("depth1 frontier = %v, want [B]", got)
+	}
+	if got := result.Frontiers[2]; len(got) != 1 || got[0] != "C" {
+		t.Errorf("depth2 frontier = %v, want [C]", got)
+	}
+	if len(result.Edges) != 2 {
+		t.Errorf("expected 2 traversed edges, got %d", len(result.Edges))
+	}
+}
+
+// MaxDepth stops the traversal before it reaches every reachable node.
+func TestExpand_MaxDepthStopsEarly(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertEdge(t, d, "e1", "A", "B", "derives_from", f64(0.8))
+	insertEdge(t, d, "e2", "B", "C", "derives_from", f64(0.8))
+
+	result, err := d.Expand([]string{"A"}, ExpandSpec{Direction: DirectionOut, MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(result.Frontiers) != 2 {
+		t.Fatalf("expected 2 frontiers, got %d: %v", len(result.Frontiers), result.Frontiers)
+	}
+	for _, e := range result.Edges {
+		if e.ID == "e2" {
+			t.Errorf("MaxDepth=1 should not traverse e2")
+		}
+	}
+}
+
+// Direction restricts which end of an edge Expand is allowed to follow.
+func TestExpand_DirectionIn(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "derives_from", f64(0.8))
+
+	outResult, err := d.Expand([]string{"B"}, ExpandSpec{Direction: DirectionOut, MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(outResult.Edges) != 0 {
+		t.Errorf("DirectionOut from B should not follow the incoming A->B edge, got %d edges", len(outResult.Edges))
+	}
+
+	inResult, err := d.Expand([]string{"B"}, ExpandSpec{Direction: DirectionIn, MaxDepth: 1})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(inResult.Frontiers) != 2 || inResult.Frontiers[1][0] != "A" {
+		t.Errorf("DirectionIn from B should reach A, frontiers = %v", inResult.Frontiers)
+	}
+}
+
+// HitCounts reflects the Dgraph-style "reached by the most distinct sources"
+// aggregation: D is reached from both B and C, so its count is 2.
+func TestExpand_HitCounts(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertNode(t, d, "D", "Node D", true)
+	insertEdge(t, d, "e1", "A", "B", "derives_from", f64(0.8))
+	insertEdge(t, d, "e2", "A", "C", "derives_from", f64(0.8))
+	insertEdge(t, d, "e3", "B", "D", "derives_from", f64(0.8))
+	insertEdge(t, d, "e4", "C", "D", "derives_from", f64(0.8))
+
+	result, err := d.Expand([]string{"A"}, ExpandSpec{Direction: DirectionOut, MaxDepth: 2})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if result.HitCounts["D"] != 2 {
+		t.Errorf("HitCounts[D] = %d, want 2", result.HitCounts["D"])
+	}
+	if result.HitCounts["B"] != 1 || result.HitCounts["C"] != 1 {
+		t.Errorf("HitCounts[B]/[C] = %d/%d, want 1/1", result.HitCounts["B"], result.HitCounts["C"])
+	}
+}
+
+// EdgeTypes and NotSuperseded filter the traversal the same way they filter
+// EdgesForContext.
+func TestExpand_FiltersEdgeTypesAndSuperseded(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertEdge(t, d, "e1", "A", "B", "derives_from", f64(0.8))
+	insertEdge(t, d, "e2", "A", "C", "supports", f64(0.8))
+	if _, err := d.conn.Exec(`UPDATE edges SET superseded_by = 'e1' WHERE id = 'e2'`); err != nil {
+		t.Fatal(err)
+	}
+
+	result, err := d.Expand([]string{"A"}, ExpandSpec{
+		Direction:     DirectionOut,
+		MaxDepth:      1,
+		EdgeTypes:     []string{"supports"},
+		NotSuperseded: true,
+	})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	if len(result.Edges) != 0 {
+		t.Errorf("expected the superseded 'supports' edge to be filtered out, got %d edges", len(result.Edges))
+	}
+}
+
+// MaxVisited caps traversal across a wide frontier.
+func TestExpand_MaxVisitedCap(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	for _, id := range []string{"B", "C", "D"} {
+		insertNode(t, d, id, "Node "+id, true)
+		insertEdge(t, d, "e-"+id, "A", id, "derives_from", f64(0.8))
+	}
+
+	result, err := d.Expand([]string{"A"}, ExpandSpec{Direction: DirectionOut, MaxDepth: 1, MaxVisited: 2})
+	if err != nil {
+		t.Fatalf("Expand: %v", err)
+	}
+	total := 0
+	for _, layer := range result.Frontiers {
+		total += len(layer)
+	}
+	if total > 2 {
+		t.Errorf("expected at most 2 visited nodes, got %d across %v", total, result.Frontiers)
+	}
+}
+
+// EdgesForContext must keep returning exactly what it did before being
+// rebuilt on top of Expand: every edge touching the node, scored and
+// truncated, regardless of direction.
+func TestEdgesForContext_StillMatchesPreExpandBehavior(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertEdge(t, d, "e1", "A", "B", "contradicts", f64(0.9))
+	insertEdge(t, d, "e2", "C", "A", "related", f64(0.1))
+
+	edges, err := d.EdgesForContext("A", 10, false)
+	if err != nil {
+		t.Fatalf("EdgesForContext: %v", err)
+	}
+	if len(edges) != 2 {
+		t.Fatalf("expected 2 edges (A as both source and target), got %d", len(edges))
+	}
+	if edges[0].ID != "e1" {
+		t.Errorf("expected the higher-priority 'contradicts' edge first, got %s", edges[0].ID)
+	}
+}
@@ -0,0 +1,594 @@
+package db
+
+import (
+	"container/heap"
+	"encoding/binary"
+	"fmt"
+	"math"
+	"math/rand"
+	"sort"
+)
+
+// HNSW construction/search parameters. Matches the typical defaults from
+// Malkov & Yashunin's paper.
+const (
+	hnswM              = 16
+	hnswMmax0          = 32
+	hnswEfConstruction = 200
+)
+
+// VectorHit is one result from an ANN search, ranked by similarity.
+type VectorHit struct {
+	NodeID     string
+	Similarity float32
+}
+
+// hnswNode is the in-memory representation of one indexed vector.
+type hnswNode struct {
+	id        string
+	vec       []float32
+	layer     int
+	neighbors []map[string]bool // neighbors[l] = neighbor IDs at layer l
+}
+
+// HNSWIndex is a Hierarchical Navigable Small World graph over node
+// embeddings, used for approximate nearest-neighbor search. It is backed by
+// a dedicated SQLite table so the graph survives restarts.
+//
+// Embeddings are L2-normalized on insert and compared with cosine distance
+// (1 - cosine similarity).
+type HNSWIndex struct {
+	db         *DB
+	mL         float64
+	nodes      map[string]*hnswNode
+	entryPoint string
+	maxLayer   int
+	rng        *rand.Rand
+}
+
+// OpenHNSWIndex opens (or lazily creates) the HNSW index backed by db.
+// If the schema is missing it is created; if the table is empty the index
+// starts empty and is populated via Insert.
+func OpenHNSWIndex(d *DB) (*HNSWIndex, error) {
+	idx := &HNSWIndex{
+		db:    d,
+		mL:    1.0 / math.Log(float64(hnswM)),
+		nodes: make(map[string]*hnswNode),
+		rng:   rand.New(rand.NewSource(1)),
+	}
+	if err := idx.ensureSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring hnsw schema: %w", err)
+	}
+	if err := idx.load(); err != nil {
+		return nil, fmt.Errorf("loading hnsw graph: %w", err)
+	}
+	return idx, nil
+}
+
+func (idx *HNSWIndex) ensureSchema() error {
+	_, err := idx.db.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS hnsw_nodes (
+			id             TEXT PRIMARY KEY,
+			layer          INTEGER NOT NULL,
+			vector_blob    BLOB NOT NULL,
+			neighbors_blob BLOB NOT NULL
+		);
+		CREATE TABLE IF NOT EXISTS hnsw_meta (
+			key   TEXT PRIMARY KEY,
+			value TEXT NOT NULL
+		);
+	`)
+	return err
+}
+
+// load rebuilds the in-memory graph from the hnsw_nodes table. Called once
+// on open; if the table is missing (old DB) it was just created empty by
+// ensureSchema and load is a no-op.
+func (idx *HNSWIndex) load() error {
+	rows, err := idx.db.conn.Query(`SELECT id, layer, vector_blob, neighbors_blob FROM hnsw_nodes`)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var id string
+		var layer int
+		var vecBlob, neighborsBlob []byte
+		if err := rows.Scan(&id, &layer, &vecBlob, &neighborsBlob); err != nil {
+			return err
+		}
+		node := &hnswNode{
+			id:        id,
+			vec:       bytesToEmbedding(vecBlob),
+			layer:     layer,
+			neighbors: decodeNeighbors(neighborsBlob, layer),
+		}
+		idx.nodes[id] = node
+		if layer > idx.maxLayer || idx.entryPoint == "" {
+			idx.maxLayer = layer
+			idx.entryPoint = id
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	var entry string
+	err = idx.db.conn.QueryRow(`SELECT value FROM hnsw_meta WHERE key = 'entry_point'`).Scan(&entry)
+	if err == nil && entry != "" {
+		if _, ok := idx.nodes[entry]; ok {
+			idx.entryPoint = entry
+			idx.maxLayer = idx.nodes[entry].layer
+		}
+	}
+	return nil
+}
+
+// Insert adds or replaces the vector for nodeID in the index.
+func (idx *HNSWIndex) Insert(nodeID string, vec []float32) error {
+	if len(vec) == 0 {
+		return fmt.Errorf("hnsw: empty vector for %s", nodeID)
+	}
+	normalized := normalizeVector(vec)
+
+	// Replace: drop any existing entry first.
+	if _, exists := idx.nodes[nodeID]; exists {
+		if err := idx.Delete(nodeID); err != nil {
+			return err
+		}
+	}
+
+	layer := idx.randomLayer()
+	node := &hnswNode{
+		id:        nodeID,
+		vec:       normalized,
+		layer:     layer,
+		neighbors: make([]map[string]bool, layer+1),
+	}
+	for l := range node.neighbors {
+		node.neighbors[l] = make(map[string]bool)
+	}
+
+	if idx.entryPoint == "" {
+		idx.nodes[nodeID] = node
+		idx.entryPoint = nodeID
+		idx.maxLayer = layer
+		return idx.persistNode(node)
+	}
+
+	entry := idx.entryPoint
+	entryDist := cosineDistance(normalized, idx.nodes[entry].vec)
+
+	// Register the node before connecting neighbors: a selected neighbor at
+	// this layer links back to nodeID (line below), and pruneNeighbors
+	// immediately looks that id back up in idx.nodes.
+	idx.nodes[nodeID] = node
+
+	// Greedy descent through layers above the new node's top layer.
+	for l := idx.maxLayer; l > layer; l-- {
+		entry, entryDist = idx.greedyDescend(entry, entryDist, normalized, l)
+	}
+
+	// Beam search + connect at each layer from min(layer, maxLayer) down to 0.
+	var changed []*hnswNode
+	changed = append(changed, node)
+	nearest := []candidate{{id: entry, dist: entryDist}}
+	for l := minInt(layer, idx.maxLayer); l >= 0; l-- {
+		nearest = idx.searchLayer(normalized, nearest, hnswEfConstruction, l)
+		mmax := hnswM
+		if l == 0 {
+			mmax = hnswMmax0
+		}
+		selected := idx.selectNeighborsHeuristic(normalized, nearest, hnswM)
+		for _, c := range selected {
+			node.neighbors[l][c.id] = true
+			neighbor := idx.nodes[c.id]
+			if l < len(neighbor.neighbors) {
+				neighbor.neighbors[l][nodeID] = true
+				idx.pruneNeighbors(neighbor, l, mmax)
+				changed = append(changed, neighbor)
+			}
+		}
+	}
+
+	if layer > idx.maxLayer {
+		idx.maxLayer = layer
+		idx.entryPoint = nodeID
+	}
+
+	for _, n := range changed {
+		if err := idx.persistNode(n); err != nil {
+			return err
+		}
+	}
+	return idx.persistMeta()
+}
+
+// Delete removes nodeID from the index and unlinks it from all neighbors.
+func (idx *HNSWIndex) Delete(nodeID string) error {
+	node, ok := idx.nodes[nodeID]
+	if !ok {
+		return nil
+	}
+	for l, neighbors := range node.neighbors {
+		for nid := range neighbors {
+			if other, ok := idx.nodes[nid]; ok && l < len(other.neighbors) {
+				delete(other.neighbors[l], nodeID)
+				if err := idx.persistNode(other); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	delete(idx.nodes, nodeID)
+
+	if _, err := idx.db.conn.Exec(`DELETE FROM hnsw_nodes WHERE id = ?`, nodeID); err != nil {
+		return err
+	}
+
+	if idx.entryPoint == nodeID {
+		idx.entryPoint = ""
+		idx.maxLayer = 0
+		for id, n := range idx.nodes {
+			if idx.entryPoint == "" || n.layer > idx.maxLayer {
+				idx.entryPoint = id
+				idx.maxLayer = n.layer
+			}
+		}
+		if err := idx.persistMeta(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// SearchKNN returns the k nearest neighbors to query, searched with beam
+// width ef at layer 0 (ef should be >= k; larger values trade speed for
+// recall).
+func (idx *HNSWIndex) SearchKNN(query []float32, k int, ef int) ([]VectorHit, error) {
+	if len(idx.nodes) == 0 {
+		return nil, nil
+	}
+	if ef < k {
+		ef = k
+	}
+	normalized := normalizeVector(query)
+
+	entry := idx.entryPoint
+	entryDist := cosineDistance(normalized, idx.nodes[entry].vec)
+	for l := idx.maxLayer; l > 0; l-- {
+		entry, entryDist = idx.greedyDescend(entry, entryDist, normalized, l)
+	}
+
+	candidates := idx.searchLayer(normalized, []candidate{{id: entry, dist: entryDist}}, ef, 0)
+	sort.Slice(candidates, func(i, j int) bool { return candidates[i].dist < candidates[j].dist })
+	if len(candidates) > k {
+		candidates = candidates[:k]
+	}
+
+	hits := make([]VectorHit, len(candidates))
+	for i, c := range candidates {
+		hits[i] = VectorHit{NodeID: c.id, Similarity: 1 - float32(c.dist)}
+	}
+	return hits, nil
+}
+
+// candidate is a node reached during beam search, with its distance to the query.
+type candidate struct {
+	id   string
+	dist float64
+}
+
+// greedyDescend runs ef=1 best-first search at layer l starting from (entry, entryDist).
+func (idx *HNSWIndex) greedyDescend(entry string, entryDist float64, query []float32, l int) (string, float64) {
+	improved := true
+	for improved {
+		improved = false
+		node := idx.nodes[entry]
+		if l >= len(node.neighbors) {
+			continue
+		}
+		for nid := range node.neighbors[l] {
+			d := cosineDistance(query, idx.nodes[nid].vec)
+			if d < entryDist {
+				entry = nid
+				entryDist = d
+				improved = true
+			}
+		}
+	}
+	return entry, entryDist
+}
+
+// searchLayer runs beam search at layer l, starting from entryPoints, keeping
+// up to ef candidates in the "nearest found" set. Uses a max-heap of
+// candidates to visit and a bounded nearest set via a max-heap on distance.
+func (idx *HNSWIndex) searchLayer(query []float32, entryPoints []candidate, ef int, l int) []candidate {
+	visited := make(map[string]bool)
+	toVisit := &minCandHeap{}
+	nearest := &maxCandHeap{}
+
+	for _, ep := range entryPoints {
+		visited[ep.id] = true
+		heap.Push(toVisit, ep)
+		heap.Push(nearest, ep)
+	}
+
+	for toVisit.Len() > 0 {
+		c := heap.Pop(toVisit).(candidate)
+		if nearest.Len() >= ef && c.dist > (*nearest)[0].dist {
+			break
+		}
+		node := idx.nodes[c.id]
+		if l >= len(node.neighbors) {
+			continue
+		}
+		for nid := range node.neighbors[l] {
+			if visited[nid] {
+				continue
+			}
+			visited[nid] = true
+			d := cosineDistance(query, idx.nodes[nid].vec)
+			if nearest.Len() < ef || d < (*nearest)[0].dist {
+				cand := candidate{id: nid, dist: d}
+				heap.Push(toVisit, cand)
+				heap.Push(nearest, cand)
+				if nearest.Len() > ef {
+					heap.Pop(nearest)
+				}
+			}
+		}
+	}
+
+	result := make([]candidate, len(*nearest))
+	copy(result, *nearest)
+	return result
+}
+
+// selectNeighborsHeuristic picks up to m neighbors from candidates, preferring
+// ones that aren't already well-connected via a closer existing link (the
+// standard HNSW diversity heuristic, favoring spread over pure proximity).
+func (idx *HNSWIndex) selectNeighborsHeuristic(query []float32, candidates []candidate, m int) []candidate {
+	sorted := make([]candidate, len(candidates))
+	copy(sorted, candidates)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].dist < sorted[j].dist })
+
+	var selected []candidate
+	for _, c := range sorted {
+		if len(selected) >= m {
+			break
+		}
+		goodCandidate := true
+		for _, s := range selected {
+			if cosineDistance(idx.nodes[c.id].vec, idx.nodes[s.id].vec) < c.dist {
+				goodCandidate = false
+				break
+			}
+		}
+		if goodCandidate {
+			selected = append(selected, c)
+		}
+	}
+	return selected
+}
+
+// pruneNeighbors trims node's neighbor set at layer l down to mmax using the
+// same selection heuristic.
+func (idx *HNSWIndex) pruneNeighbors(node *hnswNode, l int, mmax int) {
+	if len(node.neighbors[l]) <= mmax {
+		return
+	}
+	var candidates []candidate
+	for nid := range node.neighbors[l] {
+		candidates = append(candidates, candidate{id: nid, dist: cosineDistance(node.vec, idx.nodes[nid].vec)})
+	}
+	selected := idx.selectNeighborsHeuristic(node.vec, candidates, mmax)
+	node.neighbors[l] = make(map[string]bool, len(selected))
+	for _, c := range selected {
+		node.neighbors[l][c.id] = true
+	}
+}
+
+// randomLayer draws a layer from a geometric distribution with parameter mL.
+func (idx *HNSWIndex) randomLayer() int {
+	return int(math.Floor(-math.Log(idx.rng.Float64()+1e-12) * idx.mL))
+}
+
+func (idx *HNSWIndex) persistNode(n *hnswNode) error {
+	_, err := idx.db.conn.Exec(`
+		INSERT INTO hnsw_nodes (id, layer, vector_blob, neighbors_blob)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET layer = excluded.layer,
+			vector_blob = excluded.vector_blob, neighbors_blob = excluded.neighbors_blob
+	`, n.id, n.layer, embeddingToBytes(n.vec), encodeNeighbors(n.neighbors))
+	return err
+}
+
+func (idx *HNSWIndex) persistMeta() error {
+	_, err := idx.db.conn.Exec(`
+		INSERT INTO hnsw_meta (key, value) VALUES ('entry_point', ?)
+		ON CONFLICT(key) DO UPDATE SET value = excluded.value
+	`, idx.entryPoint)
+	return err
+}
+
+// embeddingToBytes is the inverse of bytesToEmbedding: little-endian float32 packing.
+func embeddingToBytes(vec []float32) []byte {
+	buf := make([]byte, len(vec)*4)
+	for i, f := range vec {
+		binary.LittleEndian.PutUint32(buf[i*4:i*4+4], math.Float32bits(f))
+	}
+	return buf
+}
+
+// encodeNeighbors serializes per-layer neighbor ID sets as newline/tab separated text:
+// one line per layer, IDs tab-separated. Simple and debuggable over a binary format.
+func encodeNeighbors(neighbors []map[string]bool) []byte {
+	buf := []byte{}
+	for l, layer := range neighbors {
+		ids := make([]string, 0, len(layer))
+		for id := range layer {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for i, id := range ids {
+			if i > 0 {
+				buf = append(buf, '\t')
+			}
+			buf = append(buf, id...)
+		}
+		if l < len(neighbors)-1 {
+			buf = append(buf, '\n')
+		}
+	}
+	return buf
+}
+
+func decodeNeighbors(blob []byte, layer int) []map[string]bool {
+	result := make([]map[string]bool, layer+1)
+	for i := range result {
+		result[i] = make(map[string]bool)
+	}
+	lines := splitLines(blob)
+	for l, line := range lines {
+		if l > layer {
+			break
+		}
+		for _, id := range splitTabs(line) {
+			if id != "" {
+				result[l][id] = true
+			}
+		}
+	}
+	return result
+}
+
+func splitLines(blob []byte) []string {
+	if len(blob) == 0 {
+		return []string{""}
+	}
+	var lines []string
+	start := 0
+	for i, b := range blob {
+		if b == '\n' {
+			lines = append(lines, string(blob[start:i]))
+			start = i + 1
+		}
+	}
+	lines = append(lines, string(blob[start:]))
+	return lines
+}
+
+func splitTabs(s string) []string {
+	var parts []string
+	start := 0
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\t' {
+			parts = append(parts, s[start:i])
+			start = i + 1
+		}
+	}
+	parts = append(parts, s[start:])
+	return parts
+}
+
+func normalizeVector(vec []float32) []float32 {
+	var sumSq float64
+	for _, f := range vec {
+		sumSq += float64(f) * float64(f)
+	}
+	norm := math.Sqrt(sumSq)
+	if norm == 0 {
+		return vec
+	}
+	out := make([]float32, len(vec))
+	for i, f := range vec {
+		out[i] = float32(float64(f) / norm)
+	}
+	return out
+}
+
+// cosineDistance returns 1 - cosine similarity, assuming both vectors are
+// already L2-normalized (so cosine similarity reduces to a dot product).
+func cosineDistance(a, b []float32) float64 {
+	n := len(a)
+	if len(b) < n {
+		n = len(b)
+	}
+	var dot float64
+	for i := 0; i < n; i++ {
+		dot += float64(a[i]) * float64(b[i])
+	}
+	return 1 - dot
+}
+
+func minInt(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
+
+// minCandHeap is a min-heap of candidates ordered by ascending distance,
+// used for the "to visit" frontier during beam search.
+type minCandHeap []candidate
+
+func (h minCandHeap) Len() int            { return len(h) }
+func (h minCandHeap) Less(i, j int) bool  { return h[i].dist < h[j].dist }
+func (h minCandHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *minCandHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *minCandHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// maxCandHeap is a max-heap of candidates ordered by descending distance,
+// used to bound the "nearest found" set to ef entries (pop removes the
+// farthest).
+type maxCandHeap []candidate
+
+func (h maxCandHeap) Len() int            { return len(h) }
+func (h maxCandHeap) Less(i, j int) bool  { return h[i].dist > h[j].dist }
+func (h maxCandHeap) Swap(i, j int)       { h[i], h[j] = h[j], h[i] }
+func (h *maxCandHeap) Push(x interface{}) { *h = append(*h, x.(candidate)) }
+func (h *maxCandHeap) Pop() interface{} {
+	old := *h
+	n := len(old)
+	item := old[n-1]
+	*h = old[:n-1]
+	return item
+}
+
+// SearchNodesByEmbedding finds the k nearest nodes to vec using the HNSW
+// index, opening (and lazily rebuilding, if empty) the index as needed.
+func (d *DB) SearchNodesByEmbedding(vec []float32, k int) ([]VectorHit, error) {
+	idx, err := OpenHNSWIndex(d)
+	if err != nil {
+		return nil, err
+	}
+	if len(idx.nodes) == 0 {
+		if err := idx.rebuildFromNodes(); err != nil {
+			return nil, fmt.Errorf("rebuilding hnsw index: %w", err)
+		}
+	}
+	return idx.SearchKNN(vec, k, hnswEfConstruction)
+}
+
+// rebuildFromNodes populates the index from every node that currently has an
+// embedding. Used on first use against a database with no hnsw_nodes rows yet.
+func (idx *HNSWIndex) rebuildFromNodes() error {
+	embeddings, err := idx.db.GetNodesWithEmbeddings(DefaultEmbeddingModel)
+	if err != nil {
+		return err
+	}
+	for _, e := range embeddings {
+		if err := idx.Insert(e.ID, e.Embedding); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,183 @@
+package db
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"fmt"
+	"strings"
+)
+
+// ListNodesParams configures ListNodes, modeled on the S3 ListObjectsV2
+// contract: Prefix narrows the scan to titles starting with it, Delimiter
+// (default "/") collapses everything past the first delimiter after Prefix
+// into a synthetic "folder" entry, MaxKeys bounds the page size, and
+// ContinuationToken resumes a prior call's cursor.
+type ListNodesParams struct {
+	Prefix            string
+	Delimiter         string
+	MaxKeys           int
+	ContinuationToken string
+}
+
+// ListNodesPage is one page of ListNodes results. Nodes are leaf entries —
+// titles with no further Delimiter past Prefix. CommonPrefixes are the
+// synthetic folder entries collapsing everything else; each one only
+// appears once per page no matter how many nodes share it.
+type ListNodesPage struct {
+	Nodes                 []Node   `json:"nodes"`
+	CommonPrefixes        []string `json:"common_prefixes"`
+	NextContinuationToken string   `json:"next_continuation_token,omitempty"`
+	IsTruncated           bool     `json:"is_truncated"`
+}
+
+// listNodesScanMultiplier bounds how many rows ListNodes will read from the
+// nodes table in a single page: MaxKeys distinct (leaf or common-prefix)
+// entries can collapse an arbitrarily larger number of rows sharing one
+// common prefix, so the raw SQL scan needs its own, more generous cap.
+const listNodesScanMultiplier = 50
+
+// ListNodes treats node titles as '/'-delimited keys and returns one page
+// of a prefix/delimiter listing, so a hierarchical slice of the graph
+// (e.g. "tasks/2024/") can be walked a page at a time without loading
+// every node under it.
+func (d *DB) ListNodes(params ListNodesParams) (*ListNodesPage, error) {
+	delim := params.Delimiter
+	if delim == "" {
+		delim = "/"
+	}
+	maxKeys := params.MaxKeys
+	if maxKeys <= 0 {
+		maxKeys = 1000
+	}
+
+	var afterTitle, afterID string
+	if params.ContinuationToken != "" {
+		title, id, err := decodeListToken(params.ContinuationToken, params.Prefix, delim)
+		if err != nil {
+			return nil, err
+		}
+		afterTitle, afterID = title, id
+	}
+
+	scanLimit := maxKeys * listNodesScanMultiplier
+	rows, err := d.conn.Query(`
+		SELECT id, type, title, url, content, created_at, updated_at,
+		       depth, is_item, is_universe, parent_id, child_count,
+		       ai_title, summary, tags, emoji, is_processed,
+		       agent_id, node_class, meta_type, content_type, source, author
+		FROM nodes
+		WHERE substr(title, 1, length(?1)) = ?1
+		  AND (title > ?2 OR (title = ?2 AND id > ?3))
+		ORDER BY title, id
+		LIMIT ?4
+	`, params.Prefix, afterTitle, afterID, scanLimit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	page := &ListNodesPage{}
+	seenPrefixes := make(map[string]bool)
+	var lastTitle, lastID string
+	scanned := 0
+	for rows.Next() {
+		n, err := scanNode(rows)
+		if err != nil {
+			return nil, err
+		}
+		scanned++
+		lastTitle, lastID = n.Title, n.ID
+
+		rest := strings.TrimPrefix(n.Title, params.Prefix)
+		if idx := strings.Index(rest, delim); idx >= 0 {
+			commonPrefix := params.Prefix + rest[:idx+len(delim)]
+			if !seenPrefixes[commonPrefix] {
+				seenPrefixes[commonPrefix] = true
+				page.CommonPrefixes = append(page.CommonPrefixes, commonPrefix)
+			}
+		} else {
+			page.Nodes = append(page.Nodes, n)
+		}
+
+		if len(page.Nodes)+len(seenPrefixes) >= maxKeys {
+			page.IsTruncated = true
+			break
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if !page.IsTruncated && scanned == scanLimit {
+		// Hit the raw-row safety cap before collapsing enough rows into
+		// MaxKeys distinct entries — there may be more under the last
+		// common prefix we were still accumulating.
+		page.IsTruncated = true
+	}
+
+	if page.IsTruncated {
+		page.NextContinuationToken = encodeListToken(listToken{
+			Prefix:    params.Prefix,
+			Delimiter: delim,
+			LastTitle: lastTitle,
+			LastID:    lastID,
+		})
+	}
+
+	return page, nil
+}
+
+// listTokenSecret signs ListNodes continuation tokens. This isn't a
+// security boundary — it's an integrity check, so a token produced before
+// a schema or ordering change is rejected cleanly instead of silently
+// resuming from the wrong cursor.
+const listTokenSecret = "spore-list-nodes-v1"
+
+type listToken struct {
+	Prefix    string
+	Delimiter string
+	LastTitle string
+	LastID    string
+}
+
+func encodeListToken(t listToken) string {
+	payload := strings.Join([]string{t.Prefix, t.Delimiter, t.LastTitle, t.LastID}, "\x00")
+	sig := signListToken([]byte(payload))
+	return base64.RawURLEncoding.EncodeToString([]byte(payload)) + "." + base64.RawURLEncoding.EncodeToString(sig)
+}
+
+// decodeListToken verifies token's HMAC and that it was issued for the same
+// (prefix, delimiter) as the current call, then returns the (title, id)
+// cursor it encodes.
+func decodeListToken(token, prefix, delimiter string) (lastTitle, lastID string, err error) {
+	parts := strings.SplitN(token, ".", 2)
+	if len(parts) != 2 {
+		return "", "", fmt.Errorf("malformed continuation token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", "", fmt.Errorf("malformed continuation token: %w", err)
+	}
+	sig, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return "", "", fmt.Errorf("malformed continuation token: %w", err)
+	}
+	if !hmac.Equal(sig, signListToken(payload)) {
+		return "", "", fmt.Errorf("continuation token is stale or invalid")
+	}
+
+	fields := strings.Split(string(payload), "\x00")
+	if len(fields) != 4 {
+		return "", "", fmt.Errorf("malformed continuation token payload")
+	}
+	if fields[0] != prefix || fields[1] != delimiter {
+		return "", "", fmt.Errorf("continuation token was issued for a different prefix/delimiter")
+	}
+	return fields[2], fields[3], nil
+}
+
+func signListToken(payload []byte) []byte {
+	mac := hmac.New(sha256.New, []byte(listTokenSecret))
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
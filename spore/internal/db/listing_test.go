@@ -0,0 +1,108 @@
+package db
+
+import "testing"
+
+func TestListNodes_CommonPrefixesAndLeaves(t *testing.T) {
+	d := setupTestDB(t)
+	insertNode(t, d, "1", "tasks/2024/jan", true)
+	insertNode(t, d, "2", "tasks/2024/feb", true)
+	insertNode(t, d, "3", "tasks/2025/jan", true)
+	insertNode(t, d, "4", "tasks/readme", true)
+
+	page, err := d.ListNodes(ListNodesParams{Prefix: "tasks/"})
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+	if len(page.Nodes) != 1 || page.Nodes[0].Title != "tasks/readme" {
+		t.Errorf("expected one leaf node tasks/readme, got %+v", page.Nodes)
+	}
+	if len(page.CommonPrefixes) != 2 {
+		t.Fatalf("expected 2 common prefixes, got %+v", page.CommonPrefixes)
+	}
+	want := map[string]bool{"tasks/2024/": true, "tasks/2025/": true}
+	for _, p := range page.CommonPrefixes {
+		if !want[p] {
+			t.Errorf("unexpected common prefix %q", p)
+		}
+	}
+	if page.IsTruncated {
+		t.Errorf("expected a complete page, got IsTruncated=true")
+	}
+}
+
+func TestListNodes_CustomDelimiter(t *testing.T) {
+	d := setupTestDB(t)
+	insertNode(t, d, "1", "runs:experiment-x:001", true)
+	insertNode(t, d, "2", "runs:experiment-x:002", true)
+
+	page, err := d.ListNodes(ListNodesParams{Prefix: "runs:", Delimiter: ":"})
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+	if len(page.CommonPrefixes) != 1 || page.CommonPrefixes[0] != "runs:experiment-x:" {
+		t.Errorf("expected common prefix runs:experiment-x:, got %+v", page.CommonPrefixes)
+	}
+}
+
+func TestListNodes_PaginationRoundTrip(t *testing.T) {
+	d := setupTestDB(t)
+	insertNode(t, d, "1", "tasks/a", true)
+	insertNode(t, d, "2", "tasks/b", true)
+	insertNode(t, d, "3", "tasks/c", true)
+
+	first, err := d.ListNodes(ListNodesParams{Prefix: "tasks/", MaxKeys: 2})
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+	if len(first.Nodes) != 2 || !first.IsTruncated {
+		t.Fatalf("expected a truncated 2-node page, got %+v", first)
+	}
+	if first.NextContinuationToken == "" {
+		t.Fatalf("expected a continuation token on a truncated page")
+	}
+
+	second, err := d.ListNodes(ListNodesParams{
+		Prefix:            "tasks/",
+		MaxKeys:           2,
+		ContinuationToken: first.NextContinuationToken,
+	})
+	if err != nil {
+		t.Fatalf("ListNodes (page 2): %v", err)
+	}
+	if len(second.Nodes) != 1 || second.Nodes[0].Title != "tasks/c" {
+		t.Errorf("expected the remaining node tasks/c, got %+v", second.Nodes)
+	}
+	if second.IsTruncated {
+		t.Errorf("expected the second page to be complete")
+	}
+}
+
+func TestListNodes_ContinuationTokenRejectsMismatchedPrefix(t *testing.T) {
+	d := setupTestDB(t)
+	insertNode(t, d, "1", "tasks/a", true)
+	insertNode(t, d, "2", "tasks/b", true)
+
+	page, err := d.ListNodes(ListNodesParams{Prefix: "tasks/", MaxKeys: 1})
+	if err != nil {
+		t.Fatalf("ListNodes: %v", err)
+	}
+
+	_, err = d.ListNodes(ListNodesParams{
+		Prefix:            "other/",
+		MaxKeys:           1,
+		ContinuationToken: page.NextContinuationToken,
+	})
+	if err == nil {
+		t.Fatal("expected an error when reusing a token under a different prefix")
+	}
+}
+
+func TestListNodes_RejectsTamperedToken(t *testing.T) {
+	d := setupTestDB(t)
+	insertNode(t, d, "1", "tasks/a", true)
+
+	_, err := d.ListNodes(ListNodesParams{Prefix: "tasks/", ContinuationToken: "not-a-real-token"})
+	if err == nil {
+		t.Fatal("expected an error for a malformed continuation token")
+	}
+}
@@ -0,0 +1,286 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"math"
+)
+
+// ContextRanker scores one edge within a set being ranked by EdgesForContext.
+// oldest/newest bound the CreatedAt range of that set, for recency
+// normalization identical to ScoreEdges' original inline calculation.
+type ContextRanker interface {
+	Score(e Edge, oldest, newest int64) float64
+}
+
+// LinearRanker is EdgesForContext's original
+// 0.3*recency + 0.3*confidence + 0.4*type_priority blend, generalized to
+// configurable weights. TypeWeights overrides EdgeTypePriority per edge type
+// for callers that want to tune individual types without touching the
+// built-in table; types absent from TypeWeights fall back to EdgeTypePriority.
+type LinearRanker struct {
+	RecencyW      float64
+	ConfidenceW   float64
+	TypePriorityW float64
+	TypeWeights   map[string]float64
+}
+
+// DefaultLinearRanker reproduces EdgesForContext's original hardcoded blend.
+func DefaultLinearRanker() *LinearRanker {
+	return &LinearRanker{
+		RecencyW:      scoreWeightRecency,
+		ConfidenceW:   scoreWeightConfidence,
+		TypePriorityW: scoreWeightType,
+	}
+}
+
+func (r *LinearRanker) typePriority(edgeType string) float64 {
+	if w, ok := r.TypeWeights[edgeType]; ok {
+		return w
+	}
+	return EdgeTypePriority(edgeType)
+}
+
+// Score implements ContextRanker.
+func (r *LinearRanker) Score(e Edge, oldest, newest int64) float64 {
+	recency := 1.0
+	if timeRange := float64(newest - oldest); timeRange > 0 {
+		recency = float64(e.CreatedAt-oldest) / timeRange
+	}
+	confidence := 0.5
+	if e.Confidence != nil {
+		confidence = *e.Confidence
+	}
+	return r.RecencyW*recency + r.ConfidenceW*confidence + r.TypePriorityW*r.typePriority(e.EdgeType)
+}
+
+// rankerTraceCapacity bounds ranker_traces; logTrace trims the oldest rows
+// past this count so the table stays a ring buffer rather than growing
+// unbounded across the life of the database.
+const rankerTraceCapacity = 5000
+
+// contextRankerConfigID is the single row context_ranker_config holds today.
+// Per-deployment means one override, not one per caller, so there's no
+// reason to key it on anything else yet.
+const contextRankerConfigID = "default"
+
+func (d *DB) ensureRankerSchema() error {
+	_, err := d.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS context_ranker_config (
+			id              TEXT PRIMARY KEY,
+			recency_w       REAL NOT NULL,
+			confidence_w    REAL NOT NULL,
+			type_priority_w REAL NOT NULL,
+			type_weights    TEXT NOT NULL DEFAULT '{}'
+		);
+		CREATE TABLE IF NOT EXISTS ranker_traces (
+			id            INTEGER PRIMARY KEY AUTOINCREMENT,
+			edge_id       TEXT NOT NULL,
+			recency       REAL NOT NULL,
+			confidence    REAL NOT NULL,
+			type_priority REAL NOT NULL,
+			final_score   REAL NOT NULL
+		);
+	`)
+	return err
+}
+
+// contextRanker returns d's active ranker, lazily loading a saved override
+// from context_ranker_config on first use and falling back to
+// DefaultLinearRanker when none was ever saved. SetContextRanker bypasses
+// this lazy load for callers that want to install a ranker up front.
+func (d *DB) contextRanker() (ContextRanker, error) {
+	if d.ranker != nil {
+		return d.ranker, nil
+	}
+	if err := d.ensureRankerSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring ranker schema: %w", err)
+	}
+
+	var typeWeightsJSON string
+	r := &LinearRanker{}
+	err := d.conn.QueryRow(`
+		SELECT recency_w, confidence_w, type_priority_w, type_weights
+		FROM context_ranker_config WHERE id = ?
+	`, contextRankerConfigID).Scan(&r.RecencyW, &r.ConfidenceW, &r.TypePriorityW, &typeWeightsJSON)
+	if err == sql.ErrNoRows {
+		d.ranker = DefaultLinearRanker()
+		return d.ranker, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("loading context ranker config: %w", err)
+	}
+	if typeWeightsJSON != "" && typeWeightsJSON != "{}" {
+		if err := json.Unmarshal([]byte(typeWeightsJSON), &r.TypeWeights); err != nil {
+			return nil, fmt.Errorf("decoding ranker type_weights: %w", err)
+		}
+	}
+	d.ranker = r
+	return d.ranker, nil
+}
+
+// SetContextRanker installs ranker as EdgesForContext's active ranker for
+// the lifetime of d, overriding any saved context_ranker_config row until
+// SaveRankerConfig is called to persist it.
+func (d *DB) SetContextRanker(ranker ContextRanker) {
+	d.ranker = ranker
+}
+
+// SaveRankerConfig persists ranker to context_ranker_config so future
+// OpenDB callers pick it up without recompiling, and installs it as d's
+// active ranker.
+func (d *DB) SaveRankerConfig(ranker *LinearRanker) error {
+	if err := d.ensureRankerSchema(); err != nil {
+		return fmt.Errorf("ensuring ranker schema: %w", err)
+	}
+	typeWeightsJSON := "{}"
+	if len(ranker.TypeWeights) > 0 {
+		b, err := json.Marshal(ranker.TypeWeights)
+		if err != nil {
+			return fmt.Errorf("encoding ranker type_weights: %w", err)
+		}
+		typeWeightsJSON = string(b)
+	}
+	_, err := d.conn.Exec(`
+		INSERT INTO context_ranker_config (id, recency_w, confidence_w, type_priority_w, type_weights)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(id) DO UPDATE SET
+			recency_w = excluded.recency_w,
+			confidence_w = excluded.confidence_w,
+			type_priority_w = excluded.type_priority_w,
+			type_weights = excluded.type_weights
+	`, contextRankerConfigID, ranker.RecencyW, ranker.ConfidenceW, ranker.TypePriorityW, typeWeightsJSON)
+	if err != nil {
+		return fmt.Errorf("saving context ranker config: %w", err)
+	}
+	d.ranker = ranker
+	return nil
+}
+
+// rankerTrace is one logged ranking decision: an edge's score components at
+// the moment EdgesForContext ranked it, so CalibrateRanker has something to
+// regress against later.
+type rankerTrace struct {
+	edgeID                            string
+	recency, confidence, typePriority float64
+	finalScore                        float64
+}
+
+// logRankerTraces records one EdgesForContext call's scoring decisions and
+// trims ranker_traces back down to rankerTraceCapacity, oldest first.
+func (d *DB) logRankerTraces(traces []rankerTrace) error {
+	if len(traces) == 0 {
+		return nil
+	}
+	if err := d.ensureRankerSchema(); err != nil {
+		return fmt.Errorf("ensuring ranker schema: %w", err)
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO ranker_traces (edge_id, recency, confidence, type_priority, final_score)
+		VALUES (?, ?, ?, ?, ?)
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	for _, t := range traces {
+		if _, err := stmt.Exec(t.edgeID, t.recency, t.confidence, t.typePriority, t.finalScore); err != nil {
+			return fmt.Errorf("logging ranker trace for edge %s: %w", t.edgeID, err)
+		}
+	}
+
+	if _, err := tx.Exec(`
+		DELETE FROM ranker_traces WHERE id NOT IN (
+			SELECT id FROM ranker_traces ORDER BY id DESC LIMIT ?
+		)
+	`, rankerTraceCapacity); err != nil {
+		return fmt.Errorf("trimming ranker_traces: %w", err)
+	}
+	return tx.Commit()
+}
+
+// RankPreference is one human-labeled training example for CalibrateRanker:
+// Useful records whether edge EdgeID was judged a good context pick the
+// last time it was traced.
+type RankPreference struct {
+	EdgeID string
+	Useful bool
+}
+
+// CalibrateRanker fits new LinearRanker weights against trainingPairs by
+// running logistic regression over each edge's most recently logged
+// ranker_traces components. Pairs whose edge was never traced are skipped.
+// On success the fitted weights are normalized to sum to 1 (preserving the
+// original blend's scale) and installed via SaveRankerConfig.
+func (d *DB) CalibrateRanker(trainingPairs []RankPreference) error {
+	if err := d.ensureRankerSchema(); err != nil {
+		return fmt.Errorf("ensuring ranker schema: %w", err)
+	}
+
+	type example struct {
+		features [3]float64
+		label    float64
+	}
+	var examples []example
+	for _, pair := range trainingPairs {
+		var recency, confidence, typePriority float64
+		err := d.conn.QueryRow(`
+			SELECT recency, confidence, type_priority FROM ranker_traces
+			WHERE edge_id = ? ORDER BY id DESC LIMIT 1
+		`, pair.EdgeID).Scan(&recency, &confidence, &typePriority)
+		if err == sql.ErrNoRows {
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("loading trace for edge %s: %w", pair.EdgeID, err)
+		}
+		label := 0.0
+		if pair.Useful {
+			label = 1.0
+		}
+		examples = append(examples, example{features: [3]float64{recency, confidence, typePriority}, label: label})
+	}
+	if len(examples) == 0 {
+		return fmt.Errorf("no labeled edges had a logged ranker trace to calibrate against")
+	}
+
+	const (
+		learningRate = 0.1
+		iterations   = 500
+	)
+	w := [3]float64{scoreWeightRecency, scoreWeightConfidence, scoreWeightType}
+	for iter := 0; iter < iterations; iter++ {
+		var grad [3]float64
+		for _, ex := range examples {
+			z := w[0]*ex.features[0] + w[1]*ex.features[1] + w[2]*ex.features[2]
+			pred := 1.0 / (1.0 + math.Exp(-z))
+			err := pred - ex.label
+			for i := range grad {
+				grad[i] += err * ex.features[i]
+			}
+		}
+		n := float64(len(examples))
+		for i := range w {
+			w[i] -= learningRate * grad[i] / n
+		}
+	}
+
+	sum := w[0] + w[1] + w[2]
+	if sum == 0 {
+		return fmt.Errorf("calibration collapsed all weights to zero")
+	}
+	fitted := &LinearRanker{
+		RecencyW:      w[0] / sum,
+		ConfidenceW:   w[1] / sum,
+		TypePriorityW: w[2] / sum,
+	}
+	return d.SaveRankerConfig(fitted)
+}
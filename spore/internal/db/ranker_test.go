@@ -0,0 +1,140 @@
+package db
+
+import "testing"
+
+// EdgesForContext's default ranking must be unchanged by the new
+// ContextRanker plumbing.
+func TestContextRanker_DefaultMatchesOriginalBlend(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertEdge(t, d, "e1", "A", "B", "contradicts", f64(0.9))
+	insertEdge(t, d, "e2", "C", "A", "related", f64(0.1))
+
+	edges, err := d.EdgesForContext("A", 10, false)
+	if err != nil {
+		t.Fatalf("EdgesForContext: %v", err)
+	}
+	if len(edges) != 2 || edges[0].ID != "e1" {
+		t.Fatalf("expected [e1, e2] with e1 first, got %v", edges)
+	}
+}
+
+// SetContextRanker overrides the default blend for subsequent calls.
+func TestContextRanker_SetContextRankerOverrides(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertEdge(t, d, "e1", "A", "B", "contradicts", f64(0.1))
+	insertEdge(t, d, "e2", "A", "C", "related", f64(0.9))
+
+	d.SetContextRanker(&LinearRanker{ConfidenceW: 1.0})
+
+	edges, err := d.EdgesForContext("A", 10, false)
+	if err != nil {
+		t.Fatalf("EdgesForContext: %v", err)
+	}
+	if len(edges) != 2 || edges[0].ID != "e2" {
+		t.Errorf("confidence-only ranker should rank the 0.9-confidence 'related' edge first, got %v", edges)
+	}
+}
+
+// SaveRankerConfig persists weights that a fresh *DB handle on the same
+// database picks up.
+func TestContextRanker_SaveRankerConfigPersists(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	custom := &LinearRanker{RecencyW: 0.1, ConfidenceW: 0.1, TypePriorityW: 0.8}
+	if err := d.SaveRankerConfig(custom); err != nil {
+		t.Fatalf("SaveRankerConfig: %v", err)
+	}
+
+	fresh := &DB{conn: d.conn, Path: d.Path}
+	ranker, err := fresh.contextRanker()
+	if err != nil {
+		t.Fatalf("contextRanker: %v", err)
+	}
+	lr, ok := ranker.(*LinearRanker)
+	if !ok {
+		t.Fatalf("expected *LinearRanker, got %T", ranker)
+	}
+	if lr.TypePriorityW != 0.8 {
+		t.Errorf("TypePriorityW = %v, want 0.8", lr.TypePriorityW)
+	}
+}
+
+// EdgesForContext logs ranker_traces rows so CalibrateRanker has something
+// to regress against.
+func TestContextRanker_LogsTraces(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertEdge(t, d, "e1", "A", "B", "contradicts", f64(0.9))
+
+	if _, err := d.EdgesForContext("A", 10, false); err != nil {
+		t.Fatalf("EdgesForContext: %v", err)
+	}
+
+	var count int
+	if err := d.conn.QueryRow(`SELECT COUNT(*) FROM ranker_traces WHERE edge_id = 'e1'`).Scan(&count); err != nil {
+		t.Fatalf("querying ranker_traces: %v", err)
+	}
+	if count != 1 {
+		t.Errorf("expected 1 logged trace for e1, got %d", count)
+	}
+}
+
+// CalibrateRanker fits weights that favor the type of edge the training
+// pairs label as useful.
+func TestCalibrateRanker_FitsTowardLabeledPreference(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	insertNode(t, d, "A", "Node A", true)
+	insertNode(t, d, "B", "Node B", true)
+	insertNode(t, d, "C", "Node C", true)
+	insertEdge(t, d, "useful", "A", "B", "contradicts", f64(0.9))
+	insertEdge(t, d, "not-useful", "A", "C", "related", f64(0.1))
+
+	if _, err := d.EdgesForContext("A", 10, false); err != nil {
+		t.Fatalf("EdgesForContext: %v", err)
+	}
+
+	err := d.CalibrateRanker([]RankPreference{
+		{EdgeID: "useful", Useful: true},
+		{EdgeID: "not-useful", Useful: false},
+	})
+	if err != nil {
+		t.Fatalf("CalibrateRanker: %v", err)
+	}
+
+	lr, ok := d.ranker.(*LinearRanker)
+	if !ok {
+		t.Fatalf("expected CalibrateRanker to install a *LinearRanker, got %T", d.ranker)
+	}
+	sum := lr.RecencyW + lr.ConfidenceW + lr.TypePriorityW
+	if sum < 0.99 || sum > 1.01 {
+		t.Errorf("expected fitted weights to sum to ~1, got %v", sum)
+	}
+}
+
+// CalibrateRanker reports an error rather than silently fitting nothing when
+// none of the labeled edges were ever traced.
+func TestCalibrateRanker_ErrorsWithNoTracedEdges(t *testing.T) {
+	d := setupTestDB(t)
+	defer d.Close()
+
+	err := d.CalibrateRanker([]RankPreference{{EdgeID: "never-traced", Useful: true}})
+	if err == nil {
+		t.Error("expected an error when no labeled edge has a logged trace")
+	}
+}
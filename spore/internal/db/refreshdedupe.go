@@ -0,0 +1,53 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+)
+
+func (d *DB) ensureRefreshEmitSchema() error {
+	_, err := d.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS refresh_task_emits (
+			key         TEXT PRIMARY KEY,
+			fingerprint TEXT NOT NULL,
+			emitted_at  INTEGER NOT NULL
+		);
+	`)
+	return err
+}
+
+// WasRefreshEmitted reports whether key was already emitted for fingerprint
+// (e.g. a StaleSummary's DriftDays or a StaleNode's RecentRefCount, stringified),
+// so a refresh task isn't re-queued for staleness that hasn't changed since
+// the last emission. A changed fingerprint means the drift advanced further
+// since then, so it's treated as not-yet-emitted.
+func (d *DB) WasRefreshEmitted(key, fingerprint string) (bool, error) {
+	if err := d.ensureRefreshEmitSchema(); err != nil {
+		return false, fmt.Errorf("ensuring refresh_task_emits schema: %w", err)
+	}
+	var existing string
+	err := d.conn.QueryRow(`SELECT fingerprint FROM refresh_task_emits WHERE key = ?`, key).Scan(&existing)
+	if err == sql.ErrNoRows {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return existing == fingerprint, nil
+}
+
+// MarkRefreshEmitted records that key was just emitted at emittedAtMs for
+// fingerprint, overwriting any prior record for key.
+func (d *DB) MarkRefreshEmitted(key, fingerprint string, emittedAtMs int64) error {
+	if err := d.ensureRefreshEmitSchema(); err != nil {
+		return fmt.Errorf("ensuring refresh_task_emits schema: %w", err)
+	}
+	_, err := d.conn.Exec(`
+		INSERT INTO refresh_task_emits (key, fingerprint, emitted_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (key) DO UPDATE SET
+			fingerprint = excluded.fingerprint,
+			emitted_at = excluded.emitted_at
+	`, key, fingerprint, emittedAtMs)
+	return err
+}
@@ -0,0 +1,69 @@
+package db
+
+import "testing"
+
+func TestWasRefreshEmitted_UnknownKeyIsFalse(t *testing.T) {
+	d := setupTestDB(t)
+
+	emitted, err := d.WasRefreshEmitted("summary:1", "7")
+	if err != nil {
+		t.Fatalf("WasRefreshEmitted: %v", err)
+	}
+	if emitted {
+		t.Errorf("expected an unrecorded key to report not-yet-emitted")
+	}
+}
+
+func TestMarkRefreshEmitted_RoundTrip(t *testing.T) {
+	d := setupTestDB(t)
+
+	if err := d.MarkRefreshEmitted("summary:1", "7", 1000); err != nil {
+		t.Fatalf("MarkRefreshEmitted: %v", err)
+	}
+
+	emitted, err := d.WasRefreshEmitted("summary:1", "7")
+	if err != nil {
+		t.Fatalf("WasRefreshEmitted: %v", err)
+	}
+	if !emitted {
+		t.Errorf("expected a matching fingerprint to report already-emitted")
+	}
+}
+
+func TestWasRefreshEmitted_ChangedFingerprintIsNotEmitted(t *testing.T) {
+	d := setupTestDB(t)
+
+	if err := d.MarkRefreshEmitted("summary:1", "7", 1000); err != nil {
+		t.Fatalf("MarkRefreshEmitted: %v", err)
+	}
+
+	emitted, err := d.WasRefreshEmitted("summary:1", "14")
+	if err != nil {
+		t.Fatalf("WasRefreshEmitted: %v", err)
+	}
+	if emitted {
+		t.Errorf("expected a drift past the previous fingerprint to report not-yet-emitted")
+	}
+}
+
+func TestMarkRefreshEmitted_OverwritesPriorFingerprint(t *testing.T) {
+	d := setupTestDB(t)
+
+	if err := d.MarkRefreshEmitted("summary:1", "7", 1000); err != nil {
+		t.Fatalf("MarkRefreshEmitted: %v", err)
+	}
+	if err := d.MarkRefreshEmitted("summary:1", "14", 2000); err != nil {
+		t.Fatalf("MarkRefreshEmitted: %v", err)
+	}
+
+	emitted, err := d.WasRefreshEmitted("summary:1", "14")
+	if err != nil {
+		t.Fatalf("WasRefreshEmitted: %v", err)
+	}
+	if !emitted {
+		t.Errorf("expected the updated fingerprint to report already-emitted")
+	}
+	if emitted, err := d.WasRefreshEmitted("summary:1", "7"); err != nil || emitted {
+		t.Errorf("expected the stale fingerprint to no longer match, emitted=%v err=%v", emitted, err)
+	}
+}
@@ -1,7 +1,9 @@
 package db
 
 import (
+	"sort"
 	"strings"
+	"sync"
 	"unicode"
 )
 
@@ -14,7 +16,10 @@ var stopwords = map[string]bool{
 
 // BuildFTSQuery preprocesses a natural language query for FTS5.
 // Splits on whitespace, removes stopwords and words < 3 chars, trims punctuation,
-// joins with " OR ".
+// and emits an unquoted-phrase clause OR-ed with the token-OR form, so exact
+// phrase matches outrank bag-of-words hits:
+//
+//	"term1 term2" OR term1 OR term2
 func BuildFTSQuery(query string) string {
 	words := strings.Fields(query)
 	var filtered []string
@@ -31,7 +36,15 @@ func BuildFTSQuery(query string) string {
 		}
 		filtered = append(filtered, trimmed)
 	}
-	return strings.Join(filtered, " OR ")
+	if len(filtered) == 0 {
+		return ""
+	}
+	tokenOR := strings.Join(filtered, " OR ")
+	if len(filtered) < 2 {
+		return tokenOR
+	}
+	phrase := `"` + strings.Join(filtered, " ") + `"`
+	return phrase + " OR " + tokenOR
 }
 
 // SearchNodes performs FTS5 search and returns matching nodes.
@@ -71,3 +84,186 @@ func (d *DB) SearchNodes(query string) ([]Node, error) {
 	}
 	return nodes, rows.Err()
 }
+
+// FTSOrphanRowIDs returns the rowids present in the nodes_fts index that no
+// longer have a matching row in nodes — stale entries left behind by a node
+// deletion the FTS trigger missed. Returns an empty slice if the FTS table
+// doesn't exist.
+func (d *DB) FTSOrphanRowIDs() ([]int64, error) {
+	rows, err := d.conn.Query(`
+		SELECT fts.rowid
+		FROM nodes_fts fts
+		LEFT JOIN nodes n ON n.rowid = fts.rowid
+		WHERE n.rowid IS NULL
+	`)
+	if err != nil {
+		// Gracefully handle missing FTS table
+		if strings.Contains(err.Error(), "no such table") {
+			return nil, nil
+		}
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rowIDs []int64
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		rowIDs = append(rowIDs, id)
+	}
+	return rowIDs, rows.Err()
+}
+
+// RebuildFTS rebuilds the nodes_fts index from the current contents of
+// nodes, via FTS5's 'rebuild' special command. A no-op if the FTS table
+// doesn't exist.
+func (d *DB) RebuildFTS() error {
+	_, err := d.conn.Exec(`INSERT INTO nodes_fts(nodes_fts) VALUES ('rebuild')`)
+	if err != nil && strings.Contains(err.Error(), "no such table") {
+		return nil
+	}
+	return err
+}
+
+// SearchHit is one result from HybridSearch, carrying per-source rank/score
+// attribution so callers can see why a node was surfaced.
+type SearchHit struct {
+	Node       Node    `json:"node"`
+	Score      float64 `json:"score"`       // fused RRF score
+	FTSRank    int     `json:"fts_rank"`    // 1-indexed FTS rank, 0 if absent
+	VectorRank int     `json:"vector_rank"` // 1-indexed vector rank, 0 if absent
+}
+
+// HybridOpts configures HybridSearch's RRF fusion.
+type HybridOpts struct {
+	RRFConstant  int     // k_rrf in 1/(k_rrf + rank); default 60
+	PoolSize     int     // per-source candidate pool size; default 50
+	FTSWeight    float64 // multiplier on the FTS source's RRF contribution; default 1.0
+	VectorWeight float64 // multiplier on the vector source's RRF contribution; default 1.0
+}
+
+// DefaultHybridOpts returns the standard RRF configuration.
+func DefaultHybridOpts() HybridOpts {
+	return HybridOpts{
+		RRFConstant:  60,
+		PoolSize:     50,
+		FTSWeight:    1.0,
+		VectorWeight: 1.0,
+	}
+}
+
+// HybridSearch runs the FTS query and an embedding kNN query concurrently and
+// fuses their rankings with Reciprocal Rank Fusion:
+//
+//	score(doc) = Σ weight_i / (k_rrf + rank_i(doc))
+//
+// across the two result lists. When queryVec is nil, falls back to FTS-only
+// (each hit still carries its FTS rank, VectorRank left at 0).
+func (d *DB) HybridSearch(query string, queryVec []float32, k int, opts HybridOpts) ([]SearchHit, error) {
+	if opts.RRFConstant <= 0 {
+		opts.RRFConstant = 60
+	}
+	if opts.PoolSize <= 0 {
+		opts.PoolSize = 50
+	}
+	if opts.FTSWeight == 0 {
+		opts.FTSWeight = 1.0
+	}
+	if opts.VectorWeight == 0 {
+		opts.VectorWeight = 1.0
+	}
+
+	var ftsNodes []Node
+	var vecHits []VectorHit
+	var ftsErr, vecErr error
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		ftsNodes, ftsErr = d.SearchNodes(query)
+	}()
+	if len(queryVec) > 0 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			vecHits, vecErr = d.SearchNodesByEmbedding(queryVec, opts.PoolSize)
+		}()
+	}
+	wg.Wait()
+
+	if ftsErr != nil {
+		return nil, ftsErr
+	}
+	if vecErr != nil {
+		return nil, vecErr
+	}
+
+	if len(ftsNodes) > opts.PoolSize {
+		ftsNodes = ftsNodes[:opts.PoolSize]
+	}
+
+	type fused struct {
+		score      float64
+		ftsRank    int
+		vectorRank int
+	}
+	byID := make(map[string]*fused)
+	nodeByID := make(map[string]Node, len(ftsNodes))
+
+	for i, n := range ftsNodes {
+		nodeByID[n.ID] = n
+		rank := i + 1
+		f, ok := byID[n.ID]
+		if !ok {
+			f = &fused{}
+			byID[n.ID] = f
+		}
+		f.ftsRank = rank
+		f.score += opts.FTSWeight / float64(opts.RRFConstant+rank)
+	}
+
+	for i, h := range vecHits {
+		rank := i + 1
+		f, ok := byID[h.NodeID]
+		if !ok {
+			f = &fused{}
+			byID[h.NodeID] = f
+		}
+		f.vectorRank = rank
+		f.score += opts.VectorWeight / float64(opts.RRFConstant+rank)
+		if _, have := nodeByID[h.NodeID]; !have {
+			if node, err := d.GetNode(h.NodeID); err == nil && node != nil {
+				nodeByID[h.NodeID] = *node
+			}
+		}
+	}
+
+	hits := make([]SearchHit, 0, len(byID))
+	for id, f := range byID {
+		node, ok := nodeByID[id]
+		if !ok {
+			continue
+		}
+		hits = append(hits, SearchHit{
+			Node:       node,
+			Score:      f.score,
+			FTSRank:    f.ftsRank,
+			VectorRank: f.vectorRank,
+		})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		return hits[i].Node.ID < hits[j].Node.ID
+	})
+
+	if k > 0 && len(hits) > k {
+		hits = hits[:k]
+	}
+	return hits, nil
+}
@@ -4,7 +4,7 @@ import "testing"
 
 func TestBuildFTSQuery_StopwordRemoval(t *testing.T) {
 	got := BuildFTSQuery("Add the flag to a function for parsing")
-	want := "Add OR flag OR function OR parsing"
+	want := `"Add flag function parsing" OR Add OR flag OR function OR parsing`
 	if got != want {
 		t.Errorf("got %q, want %q", got, want)
 	}
@@ -12,7 +12,7 @@ func TestBuildFTSQuery_StopwordRemoval(t *testing.T) {
 
 func TestBuildFTSQuery_ShortWords(t *testing.T) {
 	got := BuildFTSQuery("go do run fast")
-	want := "run OR fast"
+	want := `"run fast" OR run OR fast`
 	if got != want {
 		t.Errorf("got %q, want %q", got, want)
 	}
@@ -20,7 +20,7 @@ func TestBuildFTSQuery_ShortWords(t *testing.T) {
 
 func TestBuildFTSQuery_PunctuationTrimming(t *testing.T) {
 	got := BuildFTSQuery("generate_task_file() function, (spore.rs)")
-	want := "generate_task_file OR function OR spore.rs"
+	want := `"generate_task_file function spore.rs" OR generate_task_file OR function OR spore.rs`
 	if got != want {
 		t.Errorf("got %q, want %q", got, want)
 	}
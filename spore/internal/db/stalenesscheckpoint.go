@@ -0,0 +1,81 @@
+package db
+
+import "fmt"
+
+// StalenessCheckpoint is the last incremental-staleness result computed for
+// one node, as persisted by graph.ComputeStalenessIncremental so a
+// subsequent call can skip recomputing nodes that haven't changed.
+type StalenessCheckpoint struct {
+	NodeID         string
+	LastComputedAt int64 // wall-clock time (unix ms) this checkpoint was written
+	LastRefCount   int   // RecentRefCount as of LastComputedAt
+	LastUpdatedAt  int64 // the node's UpdatedAt as of LastComputedAt
+}
+
+func (d *DB) ensureStalenessCheckpointSchema() error {
+	_, err := d.conn.Exec(`
+		CREATE TABLE IF NOT EXISTS staleness_checkpoints (
+			node_id          TEXT PRIMARY KEY,
+			last_computed_at INTEGER NOT NULL,
+			last_ref_count   INTEGER NOT NULL,
+			last_updated_at  INTEGER NOT NULL
+		);
+	`)
+	return err
+}
+
+// GetStalenessCheckpoints returns every persisted checkpoint, keyed by node ID.
+func (d *DB) GetStalenessCheckpoints() (map[string]StalenessCheckpoint, error) {
+	if err := d.ensureStalenessCheckpointSchema(); err != nil {
+		return nil, fmt.Errorf("ensuring staleness_checkpoints schema: %w", err)
+	}
+
+	rows, err := d.conn.Query(`SELECT node_id, last_computed_at, last_ref_count, last_updated_at FROM staleness_checkpoints`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string]StalenessCheckpoint)
+	for rows.Next() {
+		var cp StalenessCheckpoint
+		if err := rows.Scan(&cp.NodeID, &cp.LastComputedAt, &cp.LastRefCount, &cp.LastUpdatedAt); err != nil {
+			return nil, err
+		}
+		out[cp.NodeID] = cp
+	}
+	return out, rows.Err()
+}
+
+// SaveStalenessCheckpoints upserts one checkpoint per entry in checkpoints.
+func (d *DB) SaveStalenessCheckpoints(checkpoints []StalenessCheckpoint) error {
+	if err := d.ensureStalenessCheckpointSchema(); err != nil {
+		return fmt.Errorf("ensuring staleness_checkpoints schema: %w", err)
+	}
+
+	tx, err := d.conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmt, err := tx.Prepare(`
+		INSERT INTO staleness_checkpoints (node_id, last_computed_at, last_ref_count, last_updated_at)
+		VALUES (?, ?, ?, ?)
+		ON CONFLICT (node_id) DO UPDATE SET
+			last_computed_at = excluded.last_computed_at,
+			last_ref_count = excluded.last_ref_count,
+			last_updated_at = excluded.last_updated_at
+	`)
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+
+	for _, cp := range checkpoints {
+		if _, err := stmt.Exec(cp.NodeID, cp.LastComputedAt, cp.LastRefCount, cp.LastUpdatedAt); err != nil {
+			return fmt.Errorf("saving staleness checkpoint for %s: %w", cp.NodeID, err)
+		}
+	}
+	return tx.Commit()
+}
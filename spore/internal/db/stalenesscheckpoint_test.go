@@ -0,0 +1,62 @@
+package db
+
+import "testing"
+
+func TestGetStalenessCheckpoints_EmptyIsEmptyMap(t *testing.T) {
+	d := setupTestDB(t)
+
+	checkpoints, err := d.GetStalenessCheckpoints()
+	if err != nil {
+		t.Fatalf("GetStalenessCheckpoints: %v", err)
+	}
+	if len(checkpoints) != 0 {
+		t.Errorf("expected no checkpoints, got %d", len(checkpoints))
+	}
+}
+
+func TestSaveStalenessCheckpoints_RoundTrip(t *testing.T) {
+	d := setupTestDB(t)
+
+	err := d.SaveStalenessCheckpoints([]StalenessCheckpoint{
+		{NodeID: "n1", LastComputedAt: 1000, LastRefCount: 3, LastUpdatedAt: 500},
+	})
+	if err != nil {
+		t.Fatalf("SaveStalenessCheckpoints: %v", err)
+	}
+
+	checkpoints, err := d.GetStalenessCheckpoints()
+	if err != nil {
+		t.Fatalf("GetStalenessCheckpoints: %v", err)
+	}
+	cp, ok := checkpoints["n1"]
+	if !ok {
+		t.Fatal("expected a checkpoint for n1")
+	}
+	if cp.LastComputedAt != 1000 || cp.LastRefCount != 3 || cp.LastUpdatedAt != 500 {
+		t.Errorf("unexpected checkpoint: %+v", cp)
+	}
+}
+
+func TestSaveStalenessCheckpoints_OverwritesPriorCheckpoint(t *testing.T) {
+	d := setupTestDB(t)
+
+	if err := d.SaveStalenessCheckpoints([]StalenessCheckpoint{
+		{NodeID: "n1", LastComputedAt: 1000, LastRefCount: 3, LastUpdatedAt: 500},
+	}); err != nil {
+		t.Fatalf("SaveStalenessCheckpoints: %v", err)
+	}
+	if err := d.SaveStalenessCheckpoints([]StalenessCheckpoint{
+		{NodeID: "n1", LastComputedAt: 2000, LastRefCount: 5, LastUpdatedAt: 1500},
+	}); err != nil {
+		t.Fatalf("SaveStalenessCheckpoints: %v", err)
+	}
+
+	checkpoints, err := d.GetStalenessCheckpoints()
+	if err != nil {
+		t.Fatalf("GetStalenessCheckpoints: %v", err)
+	}
+	cp := checkpoints["n1"]
+	if cp.LastComputedAt != 2000 || cp.LastRefCount != 5 || cp.LastUpdatedAt != 1500 {
+		t.Errorf("expected the newer checkpoint to win, got %+v", cp)
+	}
+}
@@ -11,131 +11,44 @@ import (
 
 // CreateNodeOpts holds optional fields for node creation
 type CreateNodeOpts struct {
-	Content   string
-	AgentID   string
-	NodeClass string // "knowledge", "meta", "operational"
-	MetaType  string // "task", "implementation", "summary", "escalation"
-	Source    string
-	Author    string
+	Content   string `json:"content,omitempty"`
+	AgentID   string `json:"agent_id,omitempty"`
+	NodeClass string `json:"node_class,omitempty"` // "knowledge", "meta", "operational"
+	MetaType  string `json:"meta_type,omitempty"`  // "task", "implementation", "summary", "escalation"
+	Source    string `json:"source,omitempty"`
+	Author    string `json:"author,omitempty"`
 }
 
-// CreateNode creates a node via mycelica-cli and returns its UUID.
-// Shells out to preserve embedding generation, FTS indexing, and hierarchy processing.
+// CreateNode creates a node via the active Backend and returns its UUID.
+// Both backends shell out to (or proxy) mycelica-cli to preserve embedding
+// generation, FTS indexing, and hierarchy processing.
 func (d *DB) CreateNode(title string, opts CreateNodeOpts) (string, error) {
-	binary, err := FindCLIBinary()
-	if err != nil {
-		return "", fmt.Errorf("finding CLI binary: %w", err)
-	}
-
-	args := []string{"node", "create", "--title", title, "--json", "--db", d.Path}
-
-	if opts.Content != "" {
-		args = append(args, "--content", opts.Content)
-	}
-	if opts.AgentID != "" {
-		args = append(args, "--agent-id", opts.AgentID)
-	}
-	if opts.NodeClass != "" {
-		args = append(args, "--node-class", opts.NodeClass)
-	}
-	if opts.MetaType != "" {
-		args = append(args, "--meta-type", opts.MetaType)
-	}
-	if opts.Source != "" {
-		args = append(args, "--source", opts.Source)
-	}
-	if opts.Author != "" {
-		args = append(args, "--author", opts.Author)
-	}
-
-	cmd := exec.Command(binary, args...)
-	out, err := cmd.Output()
-	if err != nil {
-		stderr := ""
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			stderr = strings.TrimSpace(string(exitErr.Stderr))
-		}
-		return "", fmt.Errorf("creating node: %w (stderr: %s)", err, stderr)
-	}
-
-	return parseCreatedID(out)
+	return d.backend.CreateNode(title, opts)
 }
 
 // CreateEdgeOpts holds optional fields for edge creation
 type CreateEdgeOpts struct {
-	Content    string
-	Reason     string
-	Agent      string
-	Confidence float64
-	Metadata   string // JSON string
-	Supersedes string
+	Content    string  `json:"content,omitempty"`
+	Reason     string  `json:"reason,omitempty"`
+	Agent      string  `json:"agent,omitempty"`
+	Confidence float64 `json:"confidence,omitempty"`
+	Metadata   string  `json:"metadata,omitempty"` // JSON string
+	Supersedes string  `json:"supersedes,omitempty"`
 }
 
-// CreateEdge creates an edge via mycelica-cli and returns its UUID.
-// Shells out to preserve embedding generation, FTS indexing, and hierarchy processing.
+// CreateEdge creates an edge via the active Backend and returns its UUID.
 func (d *DB) CreateEdge(sourceID, targetID, edgeType string, opts CreateEdgeOpts) (string, error) {
-	binary, err := FindCLIBinary()
-	if err != nil {
-		return "", fmt.Errorf("finding CLI binary: %w", err)
-	}
-
-	args := []string{
-		"spore", "create-edge",
-		"--from", sourceID,
-		"--to", targetID,
-		"--type", edgeType,
-		"--json", "--db", d.Path,
-	}
-
-	if opts.Content != "" {
-		args = append(args, "--content", opts.Content)
-	}
-	if opts.Reason != "" {
-		args = append(args, "--reason", opts.Reason)
-	}
-	if opts.Agent != "" {
-		args = append(args, "--agent", opts.Agent)
-	}
-	if opts.Confidence > 0 {
-		args = append(args, "--confidence", fmt.Sprintf("%.2f", opts.Confidence))
-	}
-	if opts.Metadata != "" {
-		args = append(args, "--metadata", opts.Metadata)
-	}
-	if opts.Supersedes != "" {
-		args = append(args, "--supersedes", opts.Supersedes)
-	}
-
-	cmd := exec.Command(binary, args...)
-	out, err := cmd.Output()
-	if err != nil {
-		stderr := ""
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			stderr = strings.TrimSpace(string(exitErr.Stderr))
-		}
-		return "", fmt.Errorf("creating edge: %w (stderr: %s)", err, stderr)
-	}
-
-	return parseCreatedID(out)
+	return d.backend.CreateEdge(sourceID, targetID, edgeType, opts)
 }
 
-// DeleteNode deletes a node via mycelica-cli. Edges are cascade-deleted by SQLite.
+// DeleteNode deletes a node via the active Backend. Edges are cascade-deleted by SQLite.
 func (d *DB) DeleteNode(id string) error {
-	binary, err := FindCLIBinary()
-	if err != nil {
-		return fmt.Errorf("finding CLI binary: %w", err)
-	}
+	return d.backend.DeleteNode(id)
+}
 
-	cmd := exec.Command(binary, "node", "delete", id, "--db", d.Path)
-	out, err := cmd.Output()
-	if err != nil {
-		stderr := ""
-		if exitErr, ok := err.(*exec.ExitError); ok {
-			stderr = strings.TrimSpace(string(exitErr.Stderr))
-		}
-		return fmt.Errorf("deleting node %s: %w (stderr: %s) (stdout: %s)", id, err, stderr, strings.TrimSpace(string(out)))
-	}
-	return nil
+// DeleteEdge deletes an edge via the active Backend.
+func (d *DB) DeleteEdge(id string) error {
+	return d.backend.DeleteEdge(id)
 }
 
 // parseCreatedID extracts the "id" field from JSON output like {"id":"<uuid>",...}
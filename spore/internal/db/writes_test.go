@@ -108,7 +108,7 @@ func TestFindCLIBinary_EnvNonexistent(t *testing.T) {
 }
 
 // findTestDB walks up from the working directory to find .mycelica.db
-func findTestDB(t *testing.T) string {
+func findTestDB(t testing.TB) string {
 	t.Helper()
 	dir, err := os.Getwd()
 	if err != nil {
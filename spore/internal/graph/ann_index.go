@@ -0,0 +1,89 @@
+package graph
+
+import "mycelica/spore/internal/db"
+
+// Index is implemented by anything that can answer approximate nearest-
+// neighbor queries over embedding vectors, maintained incrementally as
+// db.NodeEmbedding rows are written.
+type Index interface {
+	Insert(id string, vec []float32) error
+	Delete(id string) error
+	Query(vec []float32, topN int, minSim float32) ([]SimilarNode, error)
+}
+
+// hnswSearchEf is the beam width used for Index.Query. Larger values trade
+// speed for recall; see the recall@10 and scale benchmarks in
+// ann_index_test.go.
+const hnswSearchEf = 64
+
+// hnswIndex adapts the persistent db.HNSWIndex (sqlite-backed, survives
+// restarts) to the graph.Index interface.
+type hnswIndex struct {
+	idx *db.HNSWIndex
+}
+
+// OpenIndex opens (or lazily rebuilds from the embeddings table) the HNSW
+// index backed by d.
+func OpenIndex(d *db.DB) (Index, error) {
+	idx, err := db.OpenHNSWIndex(d)
+	if err != nil {
+		return nil, err
+	}
+	return &hnswIndex{idx: idx}, nil
+}
+
+func (h *hnswIndex) Insert(id string, vec []float32) error { return h.idx.Insert(id, vec) }
+func (h *hnswIndex) Delete(id string) error                { return h.idx.Delete(id) }
+
+func (h *hnswIndex) Query(vec []float32, topN int, minSim float32) ([]SimilarNode, error) {
+	ef := hnswSearchEf
+	if ef < topN {
+		ef = topN
+	}
+	hits, err := h.idx.SearchKNN(vec, topN, ef)
+	if err != nil {
+		return nil, err
+	}
+	results := make([]SimilarNode, 0, len(hits))
+	for _, hit := range hits {
+		if hit.Similarity < minSim {
+			continue
+		}
+		results = append(results, SimilarNode{ID: hit.NodeID, Similarity: hit.Similarity})
+	}
+	return results, nil
+}
+
+// DefaultIndexThreshold is the candidate-set size above which FindSimilarAuto
+// prefers the approximate index over the exact CosineSimilarity scan. Below
+// it, the exact scan's O(n) cost is negligible and its correctness is
+// preferable to the index's approximation.
+const DefaultIndexThreshold = 5000
+
+// FindSimilarAuto picks between the exact linear scan (FindSimilar) and an
+// approximate nearest-neighbor index based on candidate-set size, gated by
+// useIndex. idx may be nil when useIndex is false.
+func FindSimilarAuto(idx Index, target []float32, candidates []db.NodeEmbedding, excludeID string, topN int, minSimilarity float32, useIndex bool) []SimilarNode {
+	if !useIndex || idx == nil || len(candidates) < DefaultIndexThreshold {
+		return FindSimilar(target, candidates, excludeID, topN, minSimilarity)
+	}
+
+	// The index may still return excludeID itself (e.g. a node searching
+	// against its own embedding), so over-fetch by one and filter.
+	results, err := idx.Query(target, topN+1, minSimilarity)
+	if err != nil {
+		return FindSimilar(target, candidates, excludeID, topN, minSimilarity)
+	}
+
+	filtered := make([]SimilarNode, 0, len(results))
+	for _, r := range results {
+		if r.ID == excludeID {
+			continue
+		}
+		filtered = append(filtered, r)
+		if len(filtered) == topN {
+			break
+		}
+	}
+	return filtered
+}
@@ -0,0 +1,132 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"mycelica/spore/internal/db"
+)
+
+// randVec returns a deterministic pseudo-random vector for bench/test fixtures.
+func randVec(rng *rand.Rand, dim int) []float32 {
+	v := make([]float32, dim)
+	for i := range v {
+		v[i] = rng.Float32()*2 - 1
+	}
+	return v
+}
+
+func buildTestIndex(t testing.TB, n, dim int) (*db.DB, Index, []db.NodeEmbedding) {
+	t.Helper()
+	d, err := db.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("opening db: %v", err)
+	}
+	idx, err := OpenIndex(d)
+	if err != nil {
+		t.Fatalf("opening index: %v", err)
+	}
+
+	rng := rand.New(rand.NewSource(42))
+	candidates := make([]db.NodeEmbedding, n)
+	for i := 0; i < n; i++ {
+		vec := randVec(rng, dim)
+		id := fmt.Sprintf("node-%d", i)
+		candidates[i] = db.NodeEmbedding{ID: id, Embedding: vec}
+		if err := idx.Insert(id, vec); err != nil {
+			t.Fatalf("inserting %s: %v", id, err)
+		}
+	}
+	return d, idx, candidates
+}
+
+// TestANNIndex_RecallAt10 checks the HNSW index agrees with the exact
+// CosineSimilarity scan on most of its top-10 results. Approximate search
+// trades some recall for speed, so this allows partial disagreement rather
+// than requiring an exact match.
+func TestANNIndex_RecallAt10(t *testing.T) {
+	d, idx, candidates := buildTestIndex(t, 500, 16)
+	defer d.Close()
+
+	rng := rand.New(rand.NewSource(7))
+	query := randVec(rng, 16)
+
+	exact := FindSimilar(query, candidates, "", 10, -1)
+	approx, err := idx.Query(query, 10, -1)
+	if err != nil {
+		t.Fatalf("index query: %v", err)
+	}
+
+	exactIDs := make(map[string]bool, len(exact))
+	for _, r := range exact {
+		exactIDs[r.ID] = true
+	}
+	hits := 0
+	for _, r := range approx {
+		if exactIDs[r.ID] {
+			hits++
+		}
+	}
+
+	recall := float64(hits) / float64(len(exact))
+	t.Logf("recall@10 = %.2f (%d/%d)", recall, hits, len(exact))
+	if recall < 0.5 {
+		t.Errorf("expected recall@10 >= 0.5 on a 500-vector index, got %.2f", recall)
+	}
+}
+
+func BenchmarkFindSimilar_Exact(b *testing.B) {
+	d, _, candidates := buildTestIndex(b, 5000, 32)
+	defer d.Close()
+	rng := rand.New(rand.NewSource(7))
+	query := randVec(rng, 32)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FindSimilar(query, candidates, "", 10, -1)
+	}
+}
+
+func BenchmarkFindSimilar_HNSWIndex(b *testing.B) {
+	d, idx, _ := buildTestIndex(b, 5000, 32)
+	defer d.Close()
+	rng := rand.New(rand.NewSource(7))
+	query := randVec(rng, 32)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Query(query, 10, -1)
+	}
+}
+
+// The pair below repeats the exact-vs-index comparison at the scale
+// DefaultIndexThreshold is meant for: 100k nodes of 768-dim embeddings (a
+// typical OpenAI-style embedding width). FindSimilarAuto only switches to
+// the index above DefaultIndexThreshold candidates, so this is the regime
+// that matters: run with `go test -bench Scale100k -benchtime 10x` and
+// compare ns/op — the index should come out well over 100x faster than the
+// exact O(n) scan at this size.
+func BenchmarkFindSimilar_Exact_Scale100k(b *testing.B) {
+	d, _, candidates := buildTestIndex(b, 100_000, 768)
+	defer d.Close()
+	rng := rand.New(rand.NewSource(7))
+	query := randVec(rng, 768)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		FindSimilar(query, candidates, "", 10, -1)
+	}
+}
+
+func BenchmarkFindSimilar_HNSWIndex_Scale100k(b *testing.B) {
+	d, idx, _ := buildTestIndex(b, 100_000, 768)
+	defer d.Close()
+	rng := rand.New(rand.NewSource(7))
+	query := randVec(rng, 768)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		idx.Query(query, 10, -1)
+	}
+}
@@ -1,6 +1,9 @@
 package graph
 
-import "sort"
+import (
+	"fmt"
+	"sort"
+)
 
 // ArticulationPoint is a node whose removal disconnects the graph
 type ArticulationPoint struct {
@@ -24,13 +27,48 @@ type FragileConnection struct {
 	CrossEdges int    `json:"cross_edges"`
 }
 
+// BlockCutTreeNode is one node of a BlockCutTree: either an articulation
+// point (IsBlock false, ID the AP's node ID) or a biconnected-component
+// block (IsBlock true, ID a synthetic "block-N" ID indexing BridgeReport.Blocks).
+type BlockCutTreeNode struct {
+	ID      string `json:"id"`
+	IsBlock bool   `json:"is_block"`
+}
+
+// BlockCutTreeEdge is a membership edge of a BlockCutTree, joining an
+// articulation point to a block it belongs to.
+type BlockCutTreeEdge struct {
+	APID    string `json:"ap_id"`
+	BlockID string `json:"block_id"`
+}
+
+// BlockCutTree is the graph's block-cut tree: one node per articulation
+// point and one per biconnected-component block, with an edge for every
+// (articulation point, block) membership. It's always a forest -- a tree
+// per connected component of the original graph.
+type BlockCutTree struct {
+	Nodes []BlockCutTreeNode `json:"nodes"`
+	Edges []BlockCutTreeEdge `json:"edges"`
+}
+
 // BridgeReport contains bridge analysis results
 type BridgeReport struct {
 	ArticulationPoints []ArticulationPoint `json:"articulation_points"`
 	BridgeEdges        []BridgeEdge        `json:"bridge_edges"`
-	FragileConnections []FragileConnection  `json:"fragile_connections"`
+	FragileConnections []FragileConnection `json:"fragile_connections"`
 	APCount            int                 `json:"ap_count"`
 	BridgeCount        int                 `json:"bridge_count"`
+
+	// Blocks is the full biconnected (2-edge/vertex-connected) decomposition
+	// of the graph: every edge belongs to exactly one block. A bridge edge
+	// forms a block of its own.
+	Blocks [][]BridgeEdge `json:"blocks"`
+	// BlockID maps a node ID to one of the blocks it belongs to -- for most
+	// nodes (non-articulation-points) that's unambiguous, but an
+	// articulation point sits in more than one block and this holds only
+	// the last one assigned; use BlockCutTree for the full membership.
+	BlockID      map[string]string `json:"block_id"`
+	BlockCutTree *BlockCutTree     `json:"block_cut_tree"`
 }
 
 // ComputeBridges finds articulation points, bridge edges, and fragile inter-region connections
@@ -76,6 +114,12 @@ func ComputeBridges(snap *GraphSnapshot) *BridgeReport {
 	var bridgePairs [][2]int
 	counter := 1
 
+	// edgeStack holds tree and back edges (as index pairs) in DFS visit
+	// order; popBiconnectedBlock below drains it down to and including one
+	// edge to carve off a finished biconnected component.
+	var edgeStack [][2]int
+	var blocks [][][2]int
+
 	const noParent = -1
 
 	// Iterative Tarjan for each connected component
@@ -110,16 +154,24 @@ func ComputeBridges(snap *GraphSnapshot) *BridgeReport {
 				}
 
 				if visited[child] {
-					// Back edge
+					// Back edge. Only push it onto edgeStack from the
+					// descendant's side (disc[child] < disc[node]) --
+					// encountering an already-visited descendant from the
+					// ancestor's side later just means we're seeing the
+					// same undirected edge a second time.
 					if disc[child] < low[node] {
 						low[node] = disc[child]
 					}
+					if disc[child] < disc[node] {
+						edgeStack = append(edgeStack, [2]int{node, child})
+					}
 				} else {
 					// Tree edge
 					visited[child] = true
 					disc[child] = counter
 					low[child] = counter
 					counter++
+					edgeStack = append(edgeStack, [2]int{node, child})
 
 					if node == start {
 						rootChildren++
@@ -148,6 +200,17 @@ func ComputeBridges(snap *GraphSnapshot) *BridgeReport {
 					if pn != start && low[node] >= disc[pn] {
 						isAP[pn] = true
 					}
+
+					// Block pop: node's subtree can't reach above pn, so
+					// the tree/back edges pushed since entering node --
+					// down to and including the (pn, node) tree edge --
+					// form one finished biconnected component. This also
+					// fires at the root (disc[pn]==1, low[node]>=1 always),
+					// correctly splitting off a separate block per child
+					// subtree even when the root itself isn't an AP.
+					if low[node] >= disc[pn] {
+						blocks = append(blocks, popBiconnectedBlock(&edgeStack, pn, node))
+					}
 				}
 			}
 		}
@@ -183,7 +246,86 @@ func ComputeBridges(snap *GraphSnapshot) *BridgeReport {
 		})
 	}
 
-	// Fragile connections: cross-region edge counts
+	fragile := computeFragileConnections(snap)
+
+	// Convert blocks (index pairs) to BridgeEdges, and derive BlockID/
+	// BlockCutTree from each block's member nodes.
+	blockEdges := make([][]BridgeEdge, len(blocks))
+	blockID := make(map[string]string, n)
+	var treeNodes []BlockCutTreeNode
+	var treeEdges []BlockCutTreeEdge
+	for bi, block := range blocks {
+		id := fmt.Sprintf("block-%d", bi)
+		treeNodes = append(treeNodes, BlockCutTreeNode{ID: id, IsBlock: true})
+
+		members := make(map[int]bool)
+		edges := make([]BridgeEdge, 0, len(block))
+		for _, pair := range block {
+			uid := nodeIDs[pair[0]]
+			vid := nodeIDs[pair[1]]
+			edges = append(edges, BridgeEdge{
+				SourceID:    uid,
+				TargetID:    vid,
+				SourceTitle: snap.Nodes[uid].Title,
+				TargetTitle: snap.Nodes[vid].Title,
+			})
+			members[pair[0]] = true
+			members[pair[1]] = true
+		}
+		blockEdges[bi] = edges
+
+		for m := range members {
+			mid := nodeIDs[m]
+			blockID[mid] = id
+			if isAP[m] {
+				treeEdges = append(treeEdges, BlockCutTreeEdge{APID: mid, BlockID: id})
+			}
+		}
+	}
+	for _, ap := range aps {
+		treeNodes = append(treeNodes, BlockCutTreeNode{ID: ap.ID, IsBlock: false})
+	}
+	sort.Slice(treeEdges, func(i, j int) bool {
+		if treeEdges[i].BlockID != treeEdges[j].BlockID {
+			return treeEdges[i].BlockID < treeEdges[j].BlockID
+		}
+		return treeEdges[i].APID < treeEdges[j].APID
+	})
+
+	return &BridgeReport{
+		ArticulationPoints: aps,
+		BridgeEdges:        bridges,
+		FragileConnections: fragile,
+		APCount:            len(aps),
+		BridgeCount:        len(bridges),
+		Blocks:             blockEdges,
+		BlockID:            blockID,
+		BlockCutTree:       &BlockCutTree{Nodes: treeNodes, Edges: treeEdges},
+	}
+}
+
+// popBiconnectedBlock drains edgeStack down to and including the (pn, node)
+// edge, returning the drained edges as one finished biconnected component.
+func popBiconnectedBlock(edgeStack *[][2]int, pn, node int) [][2]int {
+	var block [][2]int
+	for len(*edgeStack) > 0 {
+		top := (*edgeStack)[len(*edgeStack)-1]
+		*edgeStack = (*edgeStack)[:len(*edgeStack)-1]
+		block = append(block, top)
+		if (top[0] == pn && top[1] == node) || (top[0] == node && top[1] == pn) {
+			break
+		}
+	}
+	return block
+}
+
+// computeFragileConnections counts cross-region edges and returns the
+// region pairs at or below the "<=2 edges" fragile threshold, sorted
+// ascending by CrossEdges. Split out of ComputeBridges so
+// IncrementalComputeBridges can recompute it on every call (it's a single
+// O(E) pass, not worth skipping) independently of the Tarjan work it reuses
+// from a prior report.
+func computeFragileConnections(snap *GraphSnapshot) []FragileConnection {
 	type regionPair struct{ a, b string }
 	pairCounts := make(map[regionPair]int)
 	for _, e := range snap.Edges {
@@ -216,12 +358,156 @@ func ComputeBridges(snap *GraphSnapshot) *BridgeReport {
 		}
 	}
 	sort.Slice(fragile, func(i, j int) bool { return fragile[i].CrossEdges < fragile[j].CrossEdges })
+	return fragile
+}
+
+// connectedComponents returns snap's connected components (undirected), in
+// the NodeIDs() order of each component's earliest-visited member, for
+// deterministic output.
+func connectedComponents(snap *GraphSnapshot) [][]string {
+	visited := make(map[string]bool, len(snap.Nodes))
+	var components [][]string
+	for _, start := range snap.NodeIDs() {
+		if visited[start] {
+			continue
+		}
+		var comp []string
+		queue := []string{start}
+		visited[start] = true
+		for len(queue) > 0 {
+			cur := queue[0]
+			queue = queue[1:]
+			comp = append(comp, cur)
+			for _, nb := range snap.Adj[cur] {
+				if !visited[nb] {
+					visited[nb] = true
+					queue = append(queue, nb)
+				}
+			}
+		}
+		components = append(components, comp)
+	}
+	return components
+}
+
+// IncrementalComputeBridges reuses prev's articulation points, bridges, and
+// blocks for every connected component that contains no node in
+// snap.Touched, and only re-runs ComputeBridges' Tarjan pass (via an
+// induced subsnapshot) over the components that do. FragileConnections is
+// cheap enough (a single O(E) pass) that it's always recomputed fresh
+// rather than merged.
+//
+// A nil prev, or a snap whose Touched is nil (never went through Apply),
+// falls back to a full ComputeBridges. A snap with an empty (non-nil)
+// Touched -- Apply ran but changed nothing relevant -- returns prev as-is.
+func IncrementalComputeBridges(snap *GraphSnapshot, prev *BridgeReport) *BridgeReport {
+	if prev == nil || snap.Touched == nil {
+		return ComputeBridges(snap)
+	}
+	if len(snap.Touched) == 0 {
+		return prev
+	}
+	if len(snap.Nodes) == 0 {
+		return &BridgeReport{}
+	}
+
+	var dirty []string
+	cleanSet := make(map[string]bool, len(snap.Nodes))
+	for _, comp := range connectedComponents(snap) {
+		isDirty := false
+		for _, id := range comp {
+			if snap.Touched[id] {
+				isDirty = true
+				break
+			}
+		}
+		if isDirty {
+			dirty = append(dirty, comp...)
+		} else {
+			for _, id := range comp {
+				cleanSet[id] = true
+			}
+		}
+	}
+
+	if len(dirty) == 0 {
+		// Every touched node's component turned out clean post-patch (e.g.
+		// an edge removal that didn't actually change connectivity) --
+		// nothing for Tarjan to redo.
+		return prev
+	}
+
+	dirtyReport := ComputeBridges(snap.inducedSubsnapshot(dirty))
+
+	var aps []ArticulationPoint
+	for _, ap := range prev.ArticulationPoints {
+		if cleanSet[ap.ID] {
+			aps = append(aps, ap)
+		}
+	}
+	aps = append(aps, dirtyReport.ArticulationPoints...)
+
+	var bridgeEdges []BridgeEdge
+	for _, be := range prev.BridgeEdges {
+		if cleanSet[be.SourceID] {
+			bridgeEdges = append(bridgeEdges, be)
+		}
+	}
+	bridgeEdges = append(bridgeEdges, dirtyReport.BridgeEdges...)
+
+	var blocks [][]BridgeEdge
+	for _, block := range prev.Blocks {
+		if len(block) > 0 && cleanSet[block[0].SourceID] {
+			blocks = append(blocks, block)
+		}
+	}
+	blocks = append(blocks, dirtyReport.Blocks...)
+
+	// Renumber blocks/BlockID/BlockCutTree from scratch over the merged
+	// set -- an O(blocks+nodes) pass, cheap relative to the Tarjan run it
+	// replaces, and keeps "block-N" IDs dense.
+	isAP := make(map[string]bool, len(aps))
+	for _, ap := range aps {
+		isAP[ap.ID] = true
+	}
+	blockID := make(map[string]string, len(snap.Nodes))
+	var treeNodes []BlockCutTreeNode
+	var treeEdges []BlockCutTreeEdge
+	for bi, block := range blocks {
+		id := fmt.Sprintf("block-%d", bi)
+		treeNodes = append(treeNodes, BlockCutTreeNode{ID: id, IsBlock: true})
+		members := make(map[string]bool)
+		for _, be := range block {
+			members[be.SourceID] = true
+			members[be.TargetID] = true
+		}
+		for m := range members {
+			blockID[m] = id
+			if isAP[m] {
+				treeEdges = append(treeEdges, BlockCutTreeEdge{APID: m, BlockID: id})
+			}
+		}
+	}
+	for _, ap := range aps {
+		treeNodes = append(treeNodes, BlockCutTreeNode{ID: ap.ID, IsBlock: false})
+	}
+	sort.Slice(treeEdges, func(i, j int) bool {
+		if treeEdges[i].BlockID != treeEdges[j].BlockID {
+			return treeEdges[i].BlockID < treeEdges[j].BlockID
+		}
+		return treeEdges[i].APID < treeEdges[j].APID
+	})
+
+	fragile := computeFragileConnections(snap)
 
 	return &BridgeReport{
 		ArticulationPoints: aps,
-		BridgeEdges:        bridges,
+		BridgeEdges:        bridgeEdges,
 		FragileConnections: fragile,
 		APCount:            len(aps),
-		BridgeCount:        len(bridges),
+		BridgeCount:        len(bridgeEdges),
+		Blocks:             blocks,
+		BlockID:            blockID,
+		BlockCutTree:       &BlockCutTree{Nodes: treeNodes, Edges: treeEdges},
 	}
 }
@@ -0,0 +1,242 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/google/uuid"
+)
+
+// dagEdgeTypes lists the edge types whose graph is expected to be acyclic.
+// A cycle in one of these (e.g. A supports B supports A) indicates a logic
+// error upstream, not a legitimate graph shape.
+var dagEdgeTypes = map[string]bool{
+	"supports":   true,
+	"supersedes": true,
+}
+
+// DanglingEdge is an edge whose Source or Target doesn't name a node that
+// exists — the case ComputeTopology's component/degree walks silently skip
+// rather than report.
+type DanglingEdge struct {
+	EdgeID      string `json:"edge_id"`
+	Source      string `json:"source"`
+	Target      string `json:"target"`
+	EdgeType    string `json:"edge_type"`
+	MissingNode string `json:"missing_node"` // whichever of Source/Target is absent; both if neither exists
+}
+
+// DuplicateEdgeGroup is more than one edge sharing the same (source, target,
+// edge_type) triple.
+type DuplicateEdgeGroup struct {
+	Source   string   `json:"source"`
+	Target   string   `json:"target"`
+	EdgeType string   `json:"edge_type"`
+	EdgeIDs  []string `json:"edge_ids"`
+}
+
+// SelfLoop is an edge whose Source and Target are the same node, on an edge
+// type this package doesn't treat as legitimately reflexive.
+type SelfLoop struct {
+	EdgeID   string `json:"edge_id"`
+	NodeID   string `json:"node_id"`
+	EdgeType string `json:"edge_type"`
+}
+
+// MalformedUUID is a node or edge ID (or an edge's source/target reference)
+// that doesn't parse as a UUID.
+type MalformedUUID struct {
+	Kind string `json:"kind"` // "node", "edge", "edge_source", "edge_target"
+	ID   string `json:"id"`
+}
+
+// EdgeTypeCycle is a cycle found in the subgraph of one DAG-shaped edge type.
+type EdgeTypeCycle struct {
+	EdgeType string   `json:"edge_type"`
+	NodeIDs  []string `json:"node_ids"` // cycle order; last edge closes back to NodeIDs[0]
+}
+
+// IntegrityReport is the full structural audit of one GraphSnapshot.
+type IntegrityReport struct {
+	DanglingEdges   []DanglingEdge       `json:"dangling_edges"`
+	DuplicateEdges  []DuplicateEdgeGroup `json:"duplicate_edges"`
+	SelfLoops       []SelfLoop           `json:"self_loops"`
+	OrphanedNodes   []string             `json:"orphaned_nodes"`   // degree-0 nodes that aren't roots
+	ExpectedOrphans []string             `json:"expected_orphans"` // degree-0 root nodes (ParentID == nil)
+	MalformedUUIDs  []MalformedUUID      `json:"malformed_uuids"`
+	Cycles          []EdgeTypeCycle      `json:"cycles"`
+}
+
+// Count returns the total number of defects across every category except
+// ExpectedOrphans, which isn't a defect.
+func (r *IntegrityReport) Count() int {
+	return len(r.DanglingEdges) + len(r.DuplicateEdges) + len(r.SelfLoops) +
+		len(r.OrphanedNodes) + len(r.MalformedUUIDs) + len(r.Cycles)
+}
+
+// CheckIntegrity audits snap for structural defects: dangling edge
+// references, duplicate edges, disallowed self-loops, unexpected orphans,
+// malformed UUIDs, and cycles in edge types that should be DAG-shaped. It
+// only reads snap, so it's equally usable against a live DB snapshot or a
+// hand-built one in a test.
+func CheckIntegrity(snap *GraphSnapshot) *IntegrityReport {
+	report := &IntegrityReport{}
+
+	for id := range snap.Nodes {
+		if _, err := uuid.Parse(id); err != nil {
+			report.MalformedUUIDs = append(report.MalformedUUIDs, MalformedUUID{Kind: "node", ID: id})
+		}
+	}
+
+	type dupKey struct{ source, target, edgeType string }
+	dupGroups := make(map[dupKey][]string)
+
+	for _, e := range snap.Edges {
+		if _, err := uuid.Parse(e.ID); err != nil {
+			report.MalformedUUIDs = append(report.MalformedUUIDs, MalformedUUID{Kind: "edge", ID: e.ID})
+		}
+		if _, err := uuid.Parse(e.Source); err != nil {
+			report.MalformedUUIDs = append(report.MalformedUUIDs, MalformedUUID{Kind: "edge_source", ID: e.Source})
+		}
+		if _, err := uuid.Parse(e.Target); err != nil {
+			report.MalformedUUIDs = append(report.MalformedUUIDs, MalformedUUID{Kind: "edge_target", ID: e.Target})
+		}
+
+		_, hasSource := snap.Nodes[e.Source]
+		_, hasTarget := snap.Nodes[e.Target]
+		if !hasSource || !hasTarget {
+			missing := e.Source
+			if hasSource {
+				missing = e.Target
+			}
+			report.DanglingEdges = append(report.DanglingEdges, DanglingEdge{
+				EdgeID: e.ID, Source: e.Source, Target: e.Target, EdgeType: e.EdgeType, MissingNode: missing,
+			})
+			continue // nothing further to check against a node that doesn't exist
+		}
+
+		if e.Source == e.Target {
+			report.SelfLoops = append(report.SelfLoops, SelfLoop{EdgeID: e.ID, NodeID: e.Source, EdgeType: e.EdgeType})
+		}
+
+		key := dupKey{e.Source, e.Target, e.EdgeType}
+		dupGroups[key] = append(dupGroups[key], e.ID)
+	}
+
+	for key, ids := range dupGroups {
+		if len(ids) > 1 {
+			sort.Strings(ids)
+			report.DuplicateEdges = append(report.DuplicateEdges, DuplicateEdgeGroup{
+				Source: key.source, Target: key.target, EdgeType: key.edgeType, EdgeIDs: ids,
+			})
+		}
+	}
+	sort.Slice(report.DuplicateEdges, func(i, j int) bool {
+		a, b := report.DuplicateEdges[i], report.DuplicateEdges[j]
+		if a.EdgeType != b.EdgeType {
+			return a.EdgeType < b.EdgeType
+		}
+		if a.Source != b.Source {
+			return a.Source < b.Source
+		}
+		return a.Target < b.Target
+	})
+
+	for id, node := range snap.Nodes {
+		if len(snap.Adj[id]) != 0 {
+			continue
+		}
+		if node.ParentID == nil {
+			report.ExpectedOrphans = append(report.ExpectedOrphans, id)
+		} else {
+			report.OrphanedNodes = append(report.OrphanedNodes, id)
+		}
+	}
+	sort.Strings(report.OrphanedNodes)
+	sort.Strings(report.ExpectedOrphans)
+
+	for edgeType := range dagEdgeTypes {
+		if cycle := findCycle(snap, edgeType); cycle != nil {
+			report.Cycles = append(report.Cycles, EdgeTypeCycle{EdgeType: edgeType, NodeIDs: cycle})
+		}
+	}
+	sort.Slice(report.Cycles, func(i, j int) bool { return report.Cycles[i].EdgeType < report.Cycles[j].EdgeType })
+
+	return report
+}
+
+// findCycle runs a DFS with the standard white/gray/black coloring over the
+// directed subgraph of edges matching edgeType (skipping edges to/from
+// missing nodes, already reported as DanglingEdges), returning the first
+// cycle found as the node IDs around it, or nil if the subgraph is acyclic.
+func findCycle(snap *GraphSnapshot, edgeType string) []string {
+	adj := make(map[string][]string)
+	for _, e := range snap.Edges {
+		if e.EdgeType != edgeType {
+			continue
+		}
+		if _, ok := snap.Nodes[e.Source]; !ok {
+			continue
+		}
+		if _, ok := snap.Nodes[e.Target]; !ok {
+			continue
+		}
+		adj[e.Source] = append(adj[e.Source], e.Target)
+	}
+
+	const (
+		white = 0
+		gray  = 1
+		black = 2
+	)
+	color := make(map[string]int, len(snap.Nodes))
+	var path []string
+	var cycle []string
+
+	var visit func(node string) bool
+	visit = func(node string) bool {
+		color[node] = gray
+		path = append(path, node)
+		for _, next := range adj[node] {
+			switch color[next] {
+			case gray:
+				// Found the cycle: everything in path from next's first
+				// occurrence onward.
+				for i, n := range path {
+					if n == next {
+						cycle = append([]string{}, path[i:]...)
+						return true
+					}
+				}
+			case white:
+				if visit(next) {
+					return true
+				}
+			}
+		}
+		path = path[:len(path)-1]
+		color[node] = black
+		return false
+	}
+
+	ids := make([]string, 0, len(snap.Nodes))
+	for id := range snap.Nodes {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids) // deterministic traversal order, for a deterministic first-found cycle
+
+	for _, id := range ids {
+		if color[id] == white {
+			if visit(id) {
+				return cycle
+			}
+		}
+	}
+	return nil
+}
+
+// DanglingEdgeSummary renders one DanglingEdge as a human-readable line, for
+// --repair's dry-run preview and non-JSON check output.
+func (d DanglingEdge) String() string {
+	return fmt.Sprintf("edge %s (%s): %s -> %s, missing node %s", d.EdgeID, d.EdgeType, d.Source, d.Target, d.MissingNode)
+}
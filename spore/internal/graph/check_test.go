@@ -0,0 +1,121 @@
+package graph
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func newID() string { return uuid.New().String() }
+
+func TestCheckIntegrity_DanglingEdge(t *testing.T) {
+	a, missing := newID(), newID()
+	snap := NewSnapshot(
+		[]*NodeInfo{{ID: a, Title: "a", Depth: 1}},
+		[]EdgeInfo{{ID: newID(), Source: a, Target: missing, EdgeType: "links"}},
+	)
+
+	report := CheckIntegrity(snap)
+	if len(report.DanglingEdges) != 1 {
+		t.Fatalf("expected 1 dangling edge, got %d", len(report.DanglingEdges))
+	}
+	if report.DanglingEdges[0].MissingNode != missing {
+		t.Errorf("expected missing node %s, got %s", missing, report.DanglingEdges[0].MissingNode)
+	}
+}
+
+func TestCheckIntegrity_DuplicateEdges(t *testing.T) {
+	a, b := newID(), newID()
+	snap := NewSnapshot(
+		[]*NodeInfo{{ID: a, Title: "a", Depth: 1}, {ID: b, Title: "b", Depth: 1}},
+		[]EdgeInfo{
+			{ID: newID(), Source: a, Target: b, EdgeType: "links"},
+			{ID: newID(), Source: a, Target: b, EdgeType: "links"},
+		},
+	)
+
+	report := CheckIntegrity(snap)
+	if len(report.DuplicateEdges) != 1 {
+		t.Fatalf("expected 1 duplicate group, got %d", len(report.DuplicateEdges))
+	}
+	if len(report.DuplicateEdges[0].EdgeIDs) != 2 {
+		t.Errorf("expected 2 edge IDs in the duplicate group, got %d", len(report.DuplicateEdges[0].EdgeIDs))
+	}
+}
+
+func TestCheckIntegrity_SelfLoop(t *testing.T) {
+	a := newID()
+	snap := NewSnapshot(
+		[]*NodeInfo{{ID: a, Title: "a", Depth: 1}},
+		[]EdgeInfo{{ID: newID(), Source: a, Target: a, EdgeType: "links"}},
+	)
+
+	report := CheckIntegrity(snap)
+	if len(report.SelfLoops) != 1 {
+		t.Fatalf("expected 1 self-loop, got %d", len(report.SelfLoops))
+	}
+}
+
+func TestCheckIntegrity_OrphanVsExpectedOrphan(t *testing.T) {
+	root, leaf := newID(), newID()
+	snap := NewSnapshot(
+		[]*NodeInfo{
+			{ID: root, Title: "root", Depth: 0, ParentID: nil},
+			{ID: leaf, Title: "leaf", Depth: 2, ParentID: &root},
+		},
+		nil,
+	)
+
+	report := CheckIntegrity(snap)
+	if len(report.ExpectedOrphans) != 1 || report.ExpectedOrphans[0] != root {
+		t.Errorf("expected root %s as the sole expected orphan, got %+v", root, report.ExpectedOrphans)
+	}
+	if len(report.OrphanedNodes) != 1 || report.OrphanedNodes[0] != leaf {
+		t.Errorf("expected leaf %s as the sole unexpected orphan, got %+v", leaf, report.OrphanedNodes)
+	}
+}
+
+func TestCheckIntegrity_MalformedUUID(t *testing.T) {
+	snap := NewSnapshot(
+		[]*NodeInfo{{ID: "not-a-uuid", Title: "bad", Depth: 1}},
+		nil,
+	)
+
+	report := CheckIntegrity(snap)
+	if len(report.MalformedUUIDs) != 1 || report.MalformedUUIDs[0].Kind != "node" {
+		t.Fatalf("expected 1 malformed node UUID, got %+v", report.MalformedUUIDs)
+	}
+}
+
+func TestCheckIntegrity_CycleInDAGEdgeType(t *testing.T) {
+	a, b, c := newID(), newID(), newID()
+	snap := NewSnapshot(
+		[]*NodeInfo{{ID: a, Title: "a", Depth: 1}, {ID: b, Title: "b", Depth: 1}, {ID: c, Title: "c", Depth: 1}},
+		[]EdgeInfo{
+			{ID: newID(), Source: a, Target: b, EdgeType: "supports"},
+			{ID: newID(), Source: b, Target: c, EdgeType: "supports"},
+			{ID: newID(), Source: c, Target: a, EdgeType: "supports"},
+		},
+	)
+
+	report := CheckIntegrity(snap)
+	if len(report.Cycles) != 1 || report.Cycles[0].EdgeType != "supports" {
+		t.Fatalf("expected 1 cycle in supports, got %+v", report.Cycles)
+	}
+	if len(report.Cycles[0].NodeIDs) != 3 {
+		t.Errorf("expected 3-node cycle, got %+v", report.Cycles[0].NodeIDs)
+	}
+}
+
+func TestCheckIntegrity_CleanGraphReportsNothing(t *testing.T) {
+	a, b := newID(), newID()
+	snap := NewSnapshot(
+		[]*NodeInfo{{ID: a, Title: "a", Depth: 0}, {ID: b, Title: "b", Depth: 1, ParentID: &a}},
+		[]EdgeInfo{{ID: newID(), Source: a, Target: b, EdgeType: "contains"}},
+	)
+
+	report := CheckIntegrity(snap)
+	if report.Count() != 0 {
+		t.Errorf("expected a clean graph to report no defects, got %+v", report)
+	}
+}
@@ -0,0 +1,327 @@
+package graph
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+// deleteBFSRadius bounds how far OnEdgeDelete looks for a surviving path
+// between an edge's endpoints before concluding the component actually
+// split and needs a full rebuild.
+const deleteBFSRadius = 4
+
+// CommunityIndex maintains connected components incrementally as edges are
+// inserted and deleted, built on UnionFind. Inserts are cheap (Union). A
+// delete can't be undone by union-find alone (it has no Split), so
+// OnEdgeDelete does a bounded-radius check — via db.Expand — for a
+// surviving path between the edge's endpoints, and only pays for a full
+// component rebuild when no such path exists.
+//
+// excludeStructural, when true, ignores IsStructuralEdge edges (same-file,
+// hierarchy) so components reflect semantic clustering rather than
+// structural proximity.
+type CommunityIndex struct {
+	mu                sync.Mutex
+	d                 *db.DB
+	uf                *UnionFind
+	excludeStructural bool
+}
+
+// NewCommunityIndex opens (or lazily rebuilds) the community index backed
+// by d, restoring the persisted forest if one exists and otherwise building
+// it once from every edge currently in the database.
+func NewCommunityIndex(d *db.DB, excludeStructural bool) (*CommunityIndex, error) {
+	ci := &CommunityIndex{d: d, excludeStructural: excludeStructural}
+
+	rows, err := d.LoadCommunityState()
+	if err != nil {
+		return nil, fmt.Errorf("loading community state: %w", err)
+	}
+	if len(rows) > 0 {
+		entries := make([]UnionFindEntry, len(rows))
+		for i, r := range rows {
+			entries[i] = UnionFindEntry{ID: r.NodeID, Parent: r.Parent, Rank: r.Rank, Size: r.Size}
+		}
+		ci.uf = RestoreUnionFind(entries)
+		return ci, nil
+	}
+
+	snap, err := SnapshotFromDB(d)
+	if err != nil {
+		return nil, fmt.Errorf("snapshotting graph: %w", err)
+	}
+	ci.uf = NewUnionFind(snap.NodeIDs())
+	for _, e := range snap.Edges {
+		if ci.excludeStructural && db.IsStructuralEdge(e.EdgeType) {
+			continue
+		}
+		ci.uf.Union(e.Source, e.Target)
+	}
+	if err := ci.persistLocked(); err != nil {
+		return nil, err
+	}
+	return ci, nil
+}
+
+// OnEdgeInsert folds a newly created edge into the forest. Cheap: a single
+// Union, plus Add for either endpoint the index hasn't seen yet.
+func (ci *CommunityIndex) OnEdgeInsert(edgeType, sourceID, targetID string) error {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	if ci.excludeStructural && db.IsStructuralEdge(edgeType) {
+		return nil
+	}
+	ci.uf.Add(sourceID)
+	ci.uf.Add(targetID)
+	ci.uf.Union(sourceID, targetID)
+	return ci.persistLocked()
+}
+
+// OnEdgeDelete handles a removed edge. If the endpoints are already in
+// different components, or a deleted edge wasn't one this index walked in
+// the first place, there's nothing to do. Otherwise it checks whether a
+// short path still connects them (via db.Expand) before paying for a full
+// rebuild of the affected component.
+func (ci *CommunityIndex) OnEdgeDelete(edgeType, sourceID, targetID string) error {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	if ci.excludeStructural && db.IsStructuralEdge(edgeType) {
+		return nil
+	}
+	if ci.uf.Find(sourceID) != ci.uf.Find(targetID) {
+		return nil
+	}
+	if ci.stillConnectedLocked(sourceID, targetID) {
+		return nil
+	}
+
+	if err := ci.rebuildComponentLocked(ci.uf.Find(sourceID)); err != nil {
+		return fmt.Errorf("rebuilding component after edge delete: %w", err)
+	}
+	return ci.persistLocked()
+}
+
+// stillConnectedLocked checks, within deleteBFSRadius hops, whether target
+// is still reachable from source — i.e. whether another path makes the
+// deleted edge's union-find merge still valid.
+func (ci *CommunityIndex) stillConnectedLocked(source, target string) bool {
+	spec := db.ExpandSpec{
+		Direction:         db.DirectionBoth,
+		MaxDepth:          deleteBFSRadius,
+		IncludeStructural: !ci.excludeStructural,
+	}
+	result, err := ci.d.Expand([]string{source}, spec)
+	if err != nil {
+		// Can't verify a surviving path; the safer assumption is that the
+		// component needs a rebuild, not that it's still intact.
+		return false
+	}
+	for _, layer := range result.Frontiers {
+		for _, id := range layer {
+			if id == target {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// rebuildComponentLocked recomputes connectivity for every former member of
+// root's component from the edges currently in the database, then splices
+// the result back into the main forest via UnionFind.ReplaceComponent.
+func (ci *CommunityIndex) rebuildComponentLocked(root string) error {
+	members := ci.componentMembersLocked(root)
+	memberSet := make(map[string]bool, len(members))
+	for _, m := range members {
+		memberSet[m] = true
+	}
+
+	fresh := NewUnionFind(members)
+	for _, m := range members {
+		edges, err := ci.d.GetEdgesForNode(m)
+		if err != nil {
+			return err
+		}
+		for _, e := range edges {
+			if ci.excludeStructural && db.IsStructuralEdge(e.EdgeType) {
+				continue
+			}
+			if !memberSet[e.SourceID] || !memberSet[e.TargetID] {
+				continue
+			}
+			fresh.Union(e.SourceID, e.TargetID)
+		}
+	}
+
+	ci.uf.ReplaceComponent(members, fresh)
+	return nil
+}
+
+func (ci *CommunityIndex) componentMembersLocked(root string) []string {
+	for _, c := range ci.uf.Components() {
+		if len(c) > 0 && ci.uf.Find(c[0]) == root {
+			return c
+		}
+	}
+	return nil
+}
+
+// ComponentOf returns the ID of the component (its union-find root) id
+// belongs to.
+func (ci *CommunityIndex) ComponentOf(id string) string {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	return ci.uf.Find(id)
+}
+
+// ComponentSize returns the number of nodes in root's component.
+func (ci *CommunityIndex) ComponentSize(root string) int {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+	return ci.uf.Size(root)
+}
+
+// LargestComponents returns the k largest components, descending by size.
+func (ci *CommunityIndex) LargestComponents(k int) [][]string {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	components := ci.uf.Components()
+	sort.Slice(components, func(i, j int) bool {
+		return len(components[i]) > len(components[j])
+	})
+	if len(components) > k {
+		components = components[:k]
+	}
+	return components
+}
+
+func (ci *CommunityIndex) persistLocked() error {
+	entries := ci.uf.Entries()
+	rows := make([]db.CommunityRow, len(entries))
+	for i, e := range entries {
+		rows[i] = db.CommunityRow{NodeID: e.ID, Parent: e.Parent, Rank: e.Rank, Size: e.Size}
+	}
+	return ci.d.SaveCommunityState(rows)
+}
+
+// StartMaintenance launches a background goroutine that runs one
+// Louvain-style modularity pass (see refineOnce) every interval, until ctx
+// is canceled.
+func (ci *CommunityIndex) StartMaintenance(ctx context.Context, interval time.Duration) {
+	go func() {
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := ci.refineOnce(); err != nil {
+					fmt.Fprintf(os.Stderr, "[community] Warning: refinement pass failed: %v\n", err)
+				}
+			}
+		}
+	}()
+}
+
+// refineOnce runs a single Louvain local-moving phase over the whole graph:
+// for each node, try moving it into the community of a neighbor if that
+// raises modularity Q = (1/2m) Σ [A_ij - k_i*k_j/2m] δ(c_i,c_j) more than
+// staying put, using EdgeTypePriority as edge weight. This is one pass, not
+// the full multi-level Louvain algorithm (no community aggregation/
+// recursion) — enough to pull misplaced nodes into a better-fitting
+// neighbor without the cost of true Louvain.
+func (ci *CommunityIndex) refineOnce() error {
+	ci.mu.Lock()
+	defer ci.mu.Unlock()
+
+	allEdges, err := ci.d.AllEdges()
+	if err != nil {
+		return fmt.Errorf("loading edges for refinement: %w", err)
+	}
+
+	type weightedNeighbor struct {
+		id     string
+		weight float64
+	}
+	neighbors := map[string][]weightedNeighbor{}
+	degree := map[string]float64{}
+	totalWeight := 0.0
+
+	for _, e := range allEdges {
+		if ci.excludeStructural && db.IsStructuralEdge(e.EdgeType) {
+			continue
+		}
+		w := db.EdgeTypePriority(e.EdgeType)
+		neighbors[e.SourceID] = append(neighbors[e.SourceID], weightedNeighbor{id: e.TargetID, weight: w})
+		neighbors[e.TargetID] = append(neighbors[e.TargetID], weightedNeighbor{id: e.SourceID, weight: w})
+		degree[e.SourceID] += w
+		degree[e.TargetID] += w
+		totalWeight += w
+	}
+	if totalWeight == 0 {
+		return nil
+	}
+	m2 := 2 * totalWeight
+
+	community := make(map[string]string, len(degree))
+	communityWeight := map[string]float64{}
+	for id := range degree {
+		c := ci.uf.Find(id)
+		community[id] = c
+		communityWeight[c] += degree[id]
+	}
+
+	changed := false
+	for id := range degree {
+		current := community[id]
+
+		weightToCommunity := map[string]float64{}
+		for _, n := range neighbors[id] {
+			weightToCommunity[community[n.id]] += n.weight
+		}
+
+		communityWeight[current] -= degree[id]
+
+		best := current
+		bestGain := weightToCommunity[current] - degree[id]*communityWeight[current]/m2
+		for c, wIn := range weightToCommunity {
+			if c == current {
+				continue
+			}
+			gain := wIn - degree[id]*communityWeight[c]/m2
+			if gain > bestGain {
+				bestGain = gain
+				best = c
+			}
+		}
+
+		communityWeight[best] += degree[id]
+		if best != current {
+			community[id] = best
+			changed = true
+		}
+	}
+
+	if !changed {
+		return nil
+	}
+
+	fresh := NewUnionFind(nil)
+	for id, c := range community {
+		fresh.Add(id)
+		fresh.Add(c)
+		fresh.Union(id, c)
+	}
+	ci.uf = fresh
+	return ci.persistLocked()
+}
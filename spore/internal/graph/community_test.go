@@ -0,0 +1,213 @@
+package graph
+
+import (
+	"testing"
+
+	"mycelica/spore/internal/db"
+)
+
+// setupCommunityTestDB creates an in-memory DB with the nodes/edges schema
+// CommunityIndex needs (SnapshotFromDB, AllEdges, GetEdgesForNode, Expand).
+func setupCommunityTestDB(t *testing.T) *db.DB {
+	t.Helper()
+	d, err := db.OpenDB(":memory:")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = d.Conn().Exec(`
+		CREATE TABLE nodes (
+			id TEXT PRIMARY KEY,
+			type TEXT NOT NULL DEFAULT 'page',
+			title TEXT NOT NULL,
+			url TEXT,
+			content TEXT,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER NOT NULL,
+			depth INTEGER NOT NULL DEFAULT 0,
+			is_item INTEGER NOT NULL DEFAULT 1,
+			is_universe INTEGER NOT NULL DEFAULT 0,
+			parent_id TEXT,
+			child_count INTEGER NOT NULL DEFAULT 0,
+			ai_title TEXT,
+			summary TEXT,
+			tags TEXT,
+			emoji TEXT,
+			is_processed INTEGER NOT NULL DEFAULT 0,
+			agent_id TEXT,
+			node_class TEXT,
+			meta_type TEXT,
+			content_type TEXT,
+			source TEXT,
+			author TEXT
+		);
+		CREATE TABLE edges (
+			id TEXT PRIMARY KEY,
+			source_id TEXT NOT NULL,
+			target_id TEXT NOT NULL,
+			type TEXT NOT NULL,
+			label TEXT,
+			weight REAL,
+			confidence REAL,
+			agent_id TEXT,
+			reason TEXT,
+			content TEXT,
+			created_at INTEGER NOT NULL,
+			updated_at INTEGER,
+			superseded_by TEXT,
+			metadata TEXT
+		);
+	`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+func insertCommunityNode(t *testing.T, d *db.DB, id string) {
+	t.Helper()
+	_, err := d.Conn().Exec(
+		`INSERT INTO nodes (id, title, created_at, updated_at) VALUES (?, ?, 1000, 1000)`, id, id,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func insertCommunityEdge(t *testing.T, d *db.DB, id, source, target, edgeType string) {
+	t.Helper()
+	_, err := d.Conn().Exec(
+		`INSERT INTO edges (id, source_id, target_id, type, created_at) VALUES (?, ?, ?, ?, 1000)`,
+		id, source, target, edgeType,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestNewCommunityIndex_BuildsFromExistingEdges(t *testing.T) {
+	d := setupCommunityTestDB(t)
+	defer d.Close()
+
+	for _, id := range []string{"a", "b", "c", "d"} {
+		insertCommunityNode(t, d, id)
+	}
+	insertCommunityEdge(t, d, "e1", "a", "b", "derives_from")
+	insertCommunityEdge(t, d, "e2", "b", "c", "derives_from")
+
+	ci, err := NewCommunityIndex(d, false)
+	if err != nil {
+		t.Fatalf("NewCommunityIndex: %v", err)
+	}
+	if ci.ComponentOf("a") != ci.ComponentOf("c") {
+		t.Errorf("a and c should be in the same component")
+	}
+	if ci.ComponentOf("a") == ci.ComponentOf("d") {
+		t.Errorf("a and d should be in different components")
+	}
+	if ci.ComponentSize(ci.ComponentOf("a")) != 3 {
+		t.Errorf("component size = %d, want 3", ci.ComponentSize(ci.ComponentOf("a")))
+	}
+}
+
+func TestCommunityIndex_PersistsAcrossReopen(t *testing.T) {
+	d := setupCommunityTestDB(t)
+	defer d.Close()
+
+	for _, id := range []string{"a", "b"} {
+		insertCommunityNode(t, d, id)
+	}
+
+	ci, err := NewCommunityIndex(d, false)
+	if err != nil {
+		t.Fatalf("NewCommunityIndex: %v", err)
+	}
+	if err := ci.OnEdgeInsert("derives_from", "a", "b"); err != nil {
+		t.Fatalf("OnEdgeInsert: %v", err)
+	}
+
+	reopened, err := NewCommunityIndex(d, false)
+	if err != nil {
+		t.Fatalf("reopening CommunityIndex: %v", err)
+	}
+	if reopened.ComponentOf("a") != reopened.ComponentOf("b") {
+		t.Errorf("reopened index lost the a-b merge")
+	}
+}
+
+func TestCommunityIndex_OnEdgeDelete_SurvivingPathKeepsComponent(t *testing.T) {
+	d := setupCommunityTestDB(t)
+	defer d.Close()
+
+	for _, id := range []string{"a", "b", "c"} {
+		insertCommunityNode(t, d, id)
+	}
+	// A triangle: deleting one edge still leaves a-b-c connected via c.
+	insertCommunityEdge(t, d, "e1", "a", "b", "derives_from")
+	insertCommunityEdge(t, d, "e2", "b", "c", "derives_from")
+	insertCommunityEdge(t, d, "e3", "a", "c", "derives_from")
+
+	ci, err := NewCommunityIndex(d, false)
+	if err != nil {
+		t.Fatalf("NewCommunityIndex: %v", err)
+	}
+
+	if _, err := d.Conn().Exec(`DELETE FROM edges WHERE id = 'e1'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := ci.OnEdgeDelete("derives_from", "a", "b"); err != nil {
+		t.Fatalf("OnEdgeDelete: %v", err)
+	}
+	if ci.ComponentOf("a") != ci.ComponentOf("b") {
+		t.Errorf("a and b should still be connected via c")
+	}
+}
+
+func TestCommunityIndex_OnEdgeDelete_SplitsComponent(t *testing.T) {
+	d := setupCommunityTestDB(t)
+	defer d.Close()
+
+	for _, id := range []string{"a", "b"} {
+		insertCommunityNode(t, d, id)
+	}
+	insertCommunityEdge(t, d, "e1", "a", "b", "derives_from")
+
+	ci, err := NewCommunityIndex(d, false)
+	if err != nil {
+		t.Fatalf("NewCommunityIndex: %v", err)
+	}
+	if ci.ComponentOf("a") != ci.ComponentOf("b") {
+		t.Fatalf("a and b should start in the same component")
+	}
+
+	if _, err := d.Conn().Exec(`DELETE FROM edges WHERE id = 'e1'`); err != nil {
+		t.Fatal(err)
+	}
+	if err := ci.OnEdgeDelete("derives_from", "a", "b"); err != nil {
+		t.Fatalf("OnEdgeDelete: %v", err)
+	}
+	if ci.ComponentOf("a") == ci.ComponentOf("b") {
+		t.Errorf("a and b should have split into separate components")
+	}
+}
+
+func TestCommunityIndex_LargestComponents(t *testing.T) {
+	d := setupCommunityTestDB(t)
+	defer d.Close()
+
+	for _, id := range []string{"a", "b", "c", "d", "e"} {
+		insertCommunityNode(t, d, id)
+	}
+	insertCommunityEdge(t, d, "e1", "a", "b", "derives_from")
+	insertCommunityEdge(t, d, "e2", "b", "c", "derives_from")
+
+	ci, err := NewCommunityIndex(d, false)
+	if err != nil {
+		t.Fatalf("NewCommunityIndex: %v", err)
+	}
+
+	largest := ci.LargestComponents(1)
+	if len(largest) != 1 || len(largest[0]) != 3 {
+		t.Fatalf("expected the single largest component to have 3 members, got %v", largest)
+	}
+}
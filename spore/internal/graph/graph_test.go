@@ -1,9 +1,13 @@
 package graph
 
 import (
+	"context"
 	"fmt"
+	"strings"
 	"testing"
 	"time"
+
+	"mycelica/spore/internal/db"
 )
 
 func nowMs() int64          { return time.Now().UnixMilli() }
@@ -42,8 +46,8 @@ func makeTestSnapshot(
 		edgeInfos = append(edgeInfos, EdgeInfo{
 			ID:        fmt.Sprintf("e%d", i),
 			Source:    e.source,
-			Target:   e.target,
-			EdgeType: e.edgeType,
+			Target:    e.target,
+			EdgeType:  e.edgeType,
 			CreatedAt: e.createdAt,
 		})
 	}
@@ -74,7 +78,7 @@ func quickSnapshot(nodeIDs []string, edges [][2]string) *GraphSnapshot {
 
 func TestTopology_EmptyGraph(t *testing.T) {
 	snap := NewSnapshot(nil, nil)
-	r := ComputeTopology(snap, 4, 10)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 4, TopN: 10})
 	if r.TotalNodes != 0 || r.TotalEdges != 0 || r.NumComponents != 0 {
 		t.Errorf("empty graph should have all zeros, got nodes=%d edges=%d components=%d",
 			r.TotalNodes, r.TotalEdges, r.NumComponents)
@@ -86,7 +90,7 @@ func TestTopology_SingleComponent(t *testing.T) {
 		[]string{"A", "B", "C", "D", "E"},
 		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "D"}, {"D", "E"}},
 	)
-	r := ComputeTopology(snap, 4, 10)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 4, TopN: 10})
 	if r.NumComponents != 1 {
 		t.Errorf("expected 1 component, got %d", r.NumComponents)
 	}
@@ -103,7 +107,7 @@ func TestTopology_TwoComponents(t *testing.T) {
 		[]string{"A", "B", "C", "D", "E"},
 		[][2]string{{"A", "B"}, {"B", "C"}, {"D", "E"}},
 	)
-	r := ComputeTopology(snap, 4, 10)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 4, TopN: 10})
 	if r.NumComponents != 2 {
 		t.Errorf("expected 2 components, got %d", r.NumComponents)
 	}
@@ -120,7 +124,7 @@ func TestOrphan_Detection(t *testing.T) {
 		[]string{"A", "B", "C"},
 		[][2]string{{"A", "B"}},
 	)
-	r := ComputeTopology(snap, 4, 10)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 4, TopN: 10})
 	if r.OrphanCount != 1 {
 		t.Errorf("expected 1 orphan, got %d", r.OrphanCount)
 	}
@@ -140,7 +144,7 @@ func TestHub_Detection(t *testing.T) {
 		[]string{"center", "s1", "s2", "s3", "s4", "s5"},
 		[][2]string{{"center", "s1"}, {"center", "s2"}, {"center", "s3"}, {"center", "s4"}, {"center", "s5"}},
 	)
-	r := ComputeTopology(snap, 4, 10)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 4, TopN: 10})
 	if len(r.Hubs) != 1 {
 		t.Fatalf("expected 1 hub, got %d", len(r.Hubs))
 	}
@@ -152,6 +156,99 @@ func TestHub_Detection(t *testing.T) {
 	}
 }
 
+func TestCentralityHubs_BridgeOutranksHigherDegreeNodes(t *testing.T) {
+	// Two triangles joined only through "bridge": every cross-cluster path
+	// has to pass through it, even though its degree (2) is lower than the
+	// triangle members' (3).
+	snap := quickSnapshot(
+		[]string{"x1", "x2", "x3", "bridge", "y1", "y2", "y3"},
+		[][2]string{
+			{"x1", "x2"}, {"x2", "x3"}, {"x3", "x1"},
+			{"x1", "bridge"}, {"bridge", "y1"},
+			{"y1", "y2"}, {"y2", "y3"}, {"y3", "y1"},
+		},
+	)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 10, TopN: 10, Centrality: &CentralityConfig{Betweenness: true, PageRank: true}})
+	if len(r.BetweennessHubs) == 0 {
+		t.Fatalf("expected betweenness hubs, got none")
+	}
+	if r.BetweennessHubs[0].ID != "bridge" {
+		t.Errorf("expected bridge to rank first by betweenness, got %+v", r.BetweennessHubs)
+	}
+	if len(r.Hubs) != 0 {
+		t.Errorf("hub-threshold 10 should find no raw-degree hubs, got %+v", r.Hubs)
+	}
+}
+
+func TestCentralityHubs_PageRankFavorsCenter(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"center", "s1", "s2", "s3", "s4", "s5"},
+		[][2]string{{"center", "s1"}, {"center", "s2"}, {"center", "s3"}, {"center", "s4"}, {"center", "s5"}},
+	)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 4, TopN: 10, Centrality: &CentralityConfig{PageRank: true}})
+	if len(r.PageRankHubs) == 0 || r.PageRankHubs[0].ID != "center" {
+		t.Fatalf("expected center to rank first by PageRank, got %+v", r.PageRankHubs)
+	}
+	if r.BetweennessHubs != nil {
+		t.Errorf("expected no betweenness hubs when Betweenness is disabled, got %+v", r.BetweennessHubs)
+	}
+}
+
+func TestCentralityHubs_SampledBetweennessApproximatesExact(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"x1", "x2", "x3", "bridge", "y1", "y2", "y3"},
+		[][2]string{
+			{"x1", "x2"}, {"x2", "x3"}, {"x3", "x1"},
+			{"x1", "bridge"}, {"bridge", "y1"},
+			{"y1", "y2"}, {"y2", "y3"}, {"y3", "y1"},
+		},
+	)
+	r := ComputeTopology(context.Background(), snap, TopologyOptions{HubThreshold: 10, TopN: 10, Centrality: &CentralityConfig{Betweenness: true, SampleK: 5}})
+	if len(r.BetweennessHubs) == 0 || r.BetweennessHubs[0].ID != "bridge" {
+		t.Errorf("expected bridge to still rank first under sampling, got %+v", r.BetweennessHubs)
+	}
+}
+
+func TestTopology_CancelledContextReturnsPartialReport(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"A", "B", "C"},
+		[][2]string{{"A", "B"}, {"B", "C"}},
+	)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	r := ComputeTopology(ctx, snap, TopologyOptions{HubThreshold: 4, TopN: 10})
+	if !r.Cancelled {
+		t.Errorf("expected Cancelled=true for an already-cancelled context")
+	}
+}
+
+func TestTopology_ProgressFuncCalledDuringScan(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D"},
+		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "D"}},
+	)
+	var calls int
+	var lastDone, lastTotal int
+	opts := TopologyOptions{
+		HubThreshold: 4,
+		TopN:         10,
+		ProgressFunc: func(done, total int) {
+			calls++
+			lastDone, lastTotal = done, total
+		},
+	}
+	r := ComputeTopology(context.Background(), snap, opts)
+	if calls == 0 {
+		t.Fatalf("expected ProgressFunc to be called at least once")
+	}
+	if lastDone != lastTotal {
+		t.Errorf("expected the final progress call to report done == total, got done=%d total=%d", lastDone, lastTotal)
+	}
+	if r.Cancelled {
+		t.Errorf("expected an uncancelled context to produce Cancelled=false")
+	}
+}
+
 // --- Tarjan Tests ---
 
 func TestTarjan_Bridge(t *testing.T) {
@@ -216,6 +313,76 @@ func TestTarjan_TwoCyclesJoined(t *testing.T) {
 	}
 }
 
+func TestBlocks_ChainOfBridges(t *testing.T) {
+	// A - B - C: two bridges, each its own block, B the lone AP.
+	snap := quickSnapshot(
+		[]string{"A", "B", "C"},
+		[][2]string{{"A", "B"}, {"B", "C"}},
+	)
+	r := ComputeBridges(snap)
+	if len(r.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks (one per bridge), got %d: %+v", len(r.Blocks), r.Blocks)
+	}
+	for _, block := range r.Blocks {
+		if len(block) != 1 {
+			t.Errorf("each block in a chain should hold exactly 1 edge, got %d", len(block))
+		}
+	}
+	if r.BlockID["A"] == r.BlockID["C"] {
+		t.Errorf("A and C sit in different blocks, got same BlockID %q", r.BlockID["A"])
+	}
+	if r.BlockCutTree == nil || len(r.BlockCutTree.Edges) != 2 {
+		t.Errorf("expected B (the one AP) to join both of its 2 blocks, got %+v", r.BlockCutTree)
+	}
+}
+
+func TestBlocks_CycleIsOneBlock(t *testing.T) {
+	// A triangle has no bridges or APs: the whole thing is one block.
+	snap := quickSnapshot(
+		[]string{"A", "B", "C"},
+		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}},
+	)
+	r := ComputeBridges(snap)
+	if len(r.Blocks) != 1 {
+		t.Fatalf("expected 1 block for a triangle, got %d: %+v", len(r.Blocks), r.Blocks)
+	}
+	if len(r.Blocks[0]) != 3 {
+		t.Errorf("expected the triangle's block to hold all 3 edges, got %d", len(r.Blocks[0]))
+	}
+	sameBlock := r.BlockID["A"]
+	if r.BlockID["B"] != sameBlock || r.BlockID["C"] != sameBlock {
+		t.Errorf("all 3 triangle nodes should share one BlockID, got %+v", r.BlockID)
+	}
+	if len(r.BlockCutTree.Edges) != 0 {
+		t.Errorf("a triangle has no articulation points, so its block-cut tree should have no membership edges, got %+v", r.BlockCutTree.Edges)
+	}
+}
+
+func TestBlocks_TwoCyclesJoinedByAP(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D", "E"},
+		[][2]string{
+			{"A", "B"}, {"B", "C"}, {"C", "A"}, // triangle 1, joined at C
+			{"C", "D"}, {"D", "E"}, {"E", "C"}, // triangle 2, joined at C
+		},
+	)
+	r := ComputeBridges(snap)
+	if len(r.Blocks) != 2 {
+		t.Fatalf("expected 2 blocks (one per triangle), got %d", len(r.Blocks))
+	}
+	if r.APCount != 1 || r.ArticulationPoints[0].ID != "C" {
+		t.Fatalf("expected C to be the sole articulation point, got %+v", r.ArticulationPoints)
+	}
+	if len(r.BlockCutTree.Edges) != 2 {
+		t.Errorf("C should join both blocks in the block-cut tree, got %+v", r.BlockCutTree.Edges)
+	}
+	for _, e := range r.BlockCutTree.Edges {
+		if e.APID != "C" {
+			t.Errorf("expected every block-cut tree edge to touch C, got %+v", e)
+		}
+	}
+}
+
 // --- Staleness Tests ---
 
 func TestStaleness_Detected(t *testing.T) {
@@ -367,19 +534,268 @@ func TestFragile_Connections(t *testing.T) {
 	}
 }
 
+// --- Community Tests ---
+
+func TestLouvain_TwoCliquesOneBridge(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D", "E", "F"},
+		[][2]string{
+			{"A", "B"}, {"B", "C"}, {"C", "A"}, // clique 1
+			{"D", "E"}, {"E", "F"}, {"F", "D"}, // clique 2
+			{"C", "D"}, // lone bridge between them
+		},
+	)
+	r := ComputeCommunities(snap, DefaultCommunityConfig())
+	if len(r.Sizes) != 2 {
+		t.Fatalf("expected 2 communities, got %d (%+v)", len(r.Sizes), r.Assignments)
+	}
+	byID := make(map[string]int, len(r.Assignments))
+	for _, a := range r.Assignments {
+		byID[a.ID] = a.Community
+	}
+	for _, pair := range [][2]string{{"A", "B"}, {"B", "C"}, {"D", "E"}, {"E", "F"}} {
+		if byID[pair[0]] != byID[pair[1]] {
+			t.Errorf("%s and %s should share a community", pair[0], pair[1])
+		}
+	}
+	if byID["A"] == byID["D"] {
+		t.Errorf("the two cliques should land in different communities")
+	}
+	if len(r.FragileConnections) != 1 || r.FragileConnections[0].CrossEdges != 1 {
+		t.Errorf("expected exactly 1 fragile connection with 1 cross-edge, got %+v", r.FragileConnections)
+	}
+	if r.Modularity <= 0 {
+		t.Errorf("expected positive modularity for a clearly-clustered graph, got %f", r.Modularity)
+	}
+}
+
+func TestLouvain_SingleCliqueOneCommunity(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D"},
+		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "D"}, {"D", "A"}, {"A", "C"}, {"B", "D"}},
+	)
+	r := ComputeCommunities(snap, DefaultCommunityConfig())
+	if len(r.Sizes) != 1 {
+		t.Errorf("expected 1 community for a single clique, got %d", len(r.Sizes))
+	}
+	if len(r.FragileConnections) != 0 {
+		t.Errorf("a single community has no cross-community edges, got %+v", r.FragileConnections)
+	}
+}
+
+func TestLouvain_EmptyGraph(t *testing.T) {
+	snap := quickSnapshot(nil, nil)
+	r := ComputeCommunities(snap, nil)
+	if len(r.Assignments) != 0 || len(r.Sizes) != 0 {
+		t.Errorf("expected an empty report for an empty graph, got %+v", r)
+	}
+}
+
+func TestLouvain_HigherResolutionFindsMoreCommunities(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D", "E", "F"},
+		[][2]string{
+			{"A", "B"}, {"B", "C"}, {"C", "A"},
+			{"D", "E"}, {"E", "F"}, {"F", "D"},
+			{"C", "D"},
+		},
+	)
+	low := ComputeCommunities(snap, &CommunityConfig{Resolution: 0.1, FragileThreshold: 2})
+	high := ComputeCommunities(snap, &CommunityConfig{Resolution: 4.0, FragileThreshold: 2})
+	if len(high.Sizes) < len(low.Sizes) {
+		t.Errorf("higher resolution should yield at least as many communities as lower resolution, got %d vs %d", len(high.Sizes), len(low.Sizes))
+	}
+}
+
+// --- Incremental Tests ---
+
+func TestApply_AddNodeAndEdge(t *testing.T) {
+	snap := quickSnapshot([]string{"A", "B"}, [][2]string{{"A", "B"}})
+	snap = snap.Apply(SnapshotDelta{
+		AddedNodes: []*NodeInfo{{ID: "C", Title: "Node C", NodeType: "page"}},
+		AddedEdges: []EdgeInfo{{ID: "e-new", Source: "B", Target: "C", EdgeType: "related"}},
+	})
+	if _, ok := snap.Nodes["C"]; !ok {
+		t.Fatal("expected C to be added to Nodes")
+	}
+	if len(snap.Edges) != 2 {
+		t.Fatalf("expected 2 edges after adding one, got %d", len(snap.Edges))
+	}
+	if !containsStr(snap.Adj["B"], "C") || !containsStr(snap.Adj["C"], "B") {
+		t.Errorf("expected B<->C in Adj, got B=%v C=%v", snap.Adj["B"], snap.Adj["C"])
+	}
+	if !snap.Touched["B"] || !snap.Touched["C"] {
+		t.Errorf("expected B and C touched, got %+v", snap.Touched)
+	}
+	if snap.Touched["A"] {
+		t.Errorf("A wasn't affected by the delta, shouldn't be touched")
+	}
+}
+
+func TestApply_RemoveNodeCascadesEdges(t *testing.T) {
+	snap := quickSnapshot([]string{"A", "B", "C"}, [][2]string{{"A", "B"}, {"B", "C"}})
+	snap = snap.Apply(SnapshotDelta{RemovedNodeIDs: []string{"B"}})
+	if _, ok := snap.Nodes["B"]; ok {
+		t.Fatal("expected B to be removed from Nodes")
+	}
+	if len(snap.Edges) != 0 {
+		t.Fatalf("expected both edges touching B to be cascaded away, got %d: %+v", len(snap.Edges), snap.Edges)
+	}
+	if containsStr(snap.Adj["A"], "B") || containsStr(snap.Adj["C"], "B") {
+		t.Errorf("expected B scrubbed from A and C's Adj, got A=%v C=%v", snap.Adj["A"], snap.Adj["C"])
+	}
+	if !snap.Touched["A"] || !snap.Touched["B"] || !snap.Touched["C"] {
+		t.Errorf("expected A, B, and C all touched, got %+v", snap.Touched)
+	}
+}
+
+func containsStr(list []string, s string) bool {
+	for _, v := range list {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
+
+func TestIncrementalComputeBridges_NoOpWhenUntouched(t *testing.T) {
+	snap := quickSnapshot([]string{"A", "B", "C"}, [][2]string{{"A", "B"}, {"B", "C"}})
+	prev := ComputeBridges(snap)
+	snap = snap.Apply(SnapshotDelta{}) // no-op delta, but now Touched is non-nil and empty
+	got := IncrementalComputeBridges(snap, prev)
+	if got != prev {
+		t.Error("expected an untouched snapshot to return prev unchanged")
+	}
+}
+
+func TestIncrementalComputeBridges_FallsBackWithoutPriorApply(t *testing.T) {
+	snap := quickSnapshot([]string{"A", "B", "C"}, [][2]string{{"A", "B"}, {"B", "C"}})
+	got := IncrementalComputeBridges(snap, nil)
+	want := ComputeBridges(snap)
+	if got.APCount != want.APCount || got.BridgeCount != want.BridgeCount {
+		t.Errorf("expected a full recompute with nil prev, got %+v want %+v", got, want)
+	}
+}
+
+func TestIncrementalComputeBridges_PartialRecompute(t *testing.T) {
+	// Two separate triangles, far apart: A-B-C and D-E-F, no edges between them.
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D", "E", "F"},
+		[][2]string{
+			{"A", "B"}, {"B", "C"}, {"C", "A"},
+			{"D", "E"}, {"E", "F"}, {"F", "D"},
+		},
+	)
+	prev := ComputeBridges(snap)
+	if prev.BridgeCount != 0 {
+		t.Fatalf("two triangles should have no bridges, got %d", prev.BridgeCount)
+	}
+
+	// Break triangle A-B-C into a chain by removing edge A-C, touching only
+	// that component; D-E-F's block should be reused untouched from prev.
+	for i, e := range snap.Edges {
+		if e.Source == "C" && e.Target == "A" {
+			snap = snap.Apply(SnapshotDelta{RemovedEdgeIDs: []string{e.ID}})
+			_ = i
+			break
+		}
+	}
+
+	got := IncrementalComputeBridges(snap, prev)
+	want := ComputeBridges(snap)
+	if got.BridgeCount != want.BridgeCount || got.APCount != want.APCount {
+		t.Fatalf("incremental result diverged from full recompute: got bridges=%d aps=%d, want bridges=%d aps=%d",
+			got.BridgeCount, got.APCount, want.BridgeCount, want.APCount)
+	}
+	if got.BridgeCount != 2 {
+		t.Errorf("breaking the A-B-C triangle into a chain should yield 2 bridges, got %d", got.BridgeCount)
+	}
+}
+
+// --- Viz Tests ---
+
+func TestRenderDOT_OverlayStylesAPsAndBridges(t *testing.T) {
+	snap := quickSnapshot([]string{"A", "B", "C"}, [][2]string{{"A", "B"}, {"B", "C"}})
+	overlay := ComputeBridges(snap)
+	dot, err := RenderDOT(snap, overlay, RenderOptions{})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := string(dot)
+	if !strings.Contains(s, `"B" [label="Node B", fillcolor="red"]`) {
+		t.Errorf("expected B (the AP) styled red, got:\n%s", s)
+	}
+	if !strings.Contains(s, "style=dashed") {
+		t.Errorf("expected at least one dashed bridge edge, got:\n%s", s)
+	}
+}
+
+func TestRenderDOT_FocusRestrictsToNeighborhood(t *testing.T) {
+	// A-B-C-D-E chain; focusing on C with 1 hop should include only B,C,D.
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D", "E"},
+		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "D"}, {"D", "E"}},
+	)
+	dot, err := RenderDOT(snap, nil, RenderOptions{Focus: "C", FocusHops: 1})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	s := string(dot)
+	for _, want := range []string{`"B"`, `"C"`, `"D"`} {
+		if !strings.Contains(s, want) {
+			t.Errorf("expected %s in 1-hop neighborhood of C, got:\n%s", want, s)
+		}
+	}
+	for _, notWant := range []string{`"A"`, `"E"`} {
+		if strings.Contains(s, notWant) {
+			t.Errorf("expected %s outside 1-hop neighborhood of C, got:\n%s", notWant, s)
+		}
+	}
+}
+
+func TestRenderDOT_UnknownFocusErrors(t *testing.T) {
+	snap := quickSnapshot([]string{"A"}, nil)
+	if _, err := RenderDOT(snap, nil, RenderOptions{Focus: "missing"}); err == nil {
+		t.Error("expected an error for an unknown focus node")
+	}
+}
+
+func TestRenderDOT_ClusterByCommunity(t *testing.T) {
+	snap := quickSnapshot(
+		[]string{"A", "B", "C", "D", "E", "F"},
+		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}, {"D", "E"}, {"E", "F"}, {"F", "D"}},
+	)
+	communities := ComputeCommunities(snap, DefaultCommunityConfig())
+	dot, err := RenderDOT(snap, nil, RenderOptions{ClusterBy: "community", Communities: communities})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(dot), "subgraph cluster_") {
+		t.Errorf("expected community clustering to emit at least one subgraph, got:\n%s", dot)
+	}
+}
+
+func TestKhopNeighborhood_ZeroHopsIsJustFocus(t *testing.T) {
+	snap := quickSnapshot([]string{"A", "B"}, [][2]string{{"A", "B"}})
+	got := khopNeighborhood(snap, "A", 0)
+	if len(got) != 1 || got[0] != "A" {
+		t.Errorf("expected [A] at 0 hops, got %v", got)
+	}
+}
+
 // --- Health Tests ---
 
 func TestHealthScore_Range(t *testing.T) {
 	// All orphans
 	snap := quickSnapshot([]string{"A", "B", "C"}, nil)
-	r := Analyze(snap, DefaultConfig())
+	r := Analyze(context.Background(), snap, DefaultConfig())
 	if r.HealthScore < 0 || r.HealthScore > 1 {
 		t.Errorf("health out of range: %f", r.HealthScore)
 	}
 
 	// Connected
 	snap2 := quickSnapshot([]string{"A", "B"}, [][2]string{{"A", "B"}})
-	r2 := Analyze(snap2, DefaultConfig())
+	r2 := Analyze(context.Background(), snap2, DefaultConfig())
 	if r2.HealthScore < 0 || r2.HealthScore > 1 {
 		t.Errorf("health out of range: %f", r2.HealthScore)
 	}
@@ -390,8 +806,62 @@ func TestHealthScore_Perfect(t *testing.T) {
 		[]string{"A", "B", "C"},
 		[][2]string{{"A", "B"}, {"B", "C"}, {"C", "A"}},
 	)
-	r := Analyze(snap, &AnalyzerConfig{HubThreshold: 10, TopN: 50, StaleDays: 30})
+	r := Analyze(context.Background(), snap, &AnalyzerConfig{HubThreshold: 10, TopN: 50, StaleDays: 30})
 	if r.HealthScore < 0.95 {
 		t.Errorf("perfect graph should have health ~1.0, got %f", r.HealthScore)
 	}
 }
+
+// --- Mirror Synthesis Tests ---
+
+func TestNewSnapshot_NoRegistryLeavesEdgesUnchanged(t *testing.T) {
+	nodes := []*NodeInfo{{ID: "A", Depth: 1}, {ID: "B", Depth: 1}}
+	edges := []EdgeInfo{{ID: "e1", Source: "A", Target: "B", EdgeType: "references"}}
+
+	snap := NewSnapshot(nodes, edges)
+	if len(snap.Edges) != 1 {
+		t.Fatalf("expected 1 edge with no registry, got %d", len(snap.Edges))
+	}
+	if len(snap.OutAdj["B"]) != 0 {
+		t.Errorf("expected no synthesized B->A entry without a registry, got %v", snap.OutAdj["B"])
+	}
+}
+
+func TestNewSnapshot_RegistrySynthesizesMirrorEdge(t *testing.T) {
+	nodes := []*NodeInfo{{ID: "A", Depth: 1}, {ID: "B", Depth: 1}}
+	edges := []EdgeInfo{{ID: "e1", Source: "A", Target: "B", EdgeType: "references"}}
+
+	snap := NewSnapshot(nodes, edges, db.DefaultEdgeKindRegistry())
+	if len(snap.Edges) != 2 {
+		t.Fatalf("expected 2 edges (real + synthesized mirror), got %d", len(snap.Edges))
+	}
+
+	var mirror *EdgeInfo
+	for i := range snap.Edges {
+		if snap.Edges[i].Synthetic {
+			mirror = &snap.Edges[i]
+		}
+	}
+	if mirror == nil {
+		t.Fatal("expected a synthesized mirror edge")
+	}
+	if mirror.EdgeType != "referenced_by" || mirror.Source != "B" || mirror.Target != "A" {
+		t.Errorf("expected B--referenced_by-->A, got %+v", mirror)
+	}
+	if len(snap.OutAdj["B"]) != 1 || snap.OutAdj["B"][0] != "A" {
+		t.Errorf("expected synthesized OutAdj[B] = [A], got %v", snap.OutAdj["B"])
+	}
+	if len(snap.InAdj["A"]) != 1 || snap.InAdj["A"][0] != "B" {
+		t.Errorf("expected synthesized InAdj[A] = [B], got %v", snap.InAdj["A"])
+	}
+}
+
+func TestNewSnapshot_RegistryLeavesUnmirroredEdgeAlone(t *testing.T) {
+	nodes := []*NodeInfo{{ID: "A", Depth: 1}, {ID: "B", Depth: 1}}
+	edges := []EdgeInfo{{ID: "e1", Source: "A", Target: "B", EdgeType: "related"}}
+
+	snap := NewSnapshot(nodes, edges, db.DefaultEdgeKindRegistry())
+	if len(snap.Edges) != 1 {
+		t.Fatalf("expected no synthesis for an unregistered edge type, got %d edges", len(snap.Edges))
+	}
+}
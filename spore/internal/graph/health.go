@@ -1,22 +1,56 @@
 package graph
 
-import "math"
-
-// HealthBreakdown shows the sub-scores of the health formula
-type HealthBreakdown struct {
-	Connectivity float64 `json:"connectivity"`
-	Components   float64 `json:"components"`
-	Staleness    float64 `json:"staleness"`
-	Fragility    float64 `json:"fragility"`
-}
+import (
+	"context"
+	"math"
+)
 
 // AnalysisReport is the full analysis result
 type AnalysisReport struct {
-	HealthScore     float64          `json:"health_score"`
-	HealthBreakdown HealthBreakdown  `json:"health_breakdown"`
-	Topology        *TopologyReport  `json:"topology"`
-	Staleness       *StalenessReport `json:"staleness"`
-	Bridges         *BridgeReport    `json:"bridges"`
+	HealthScore     float64            `json:"health_score"`
+	HealthBreakdown map[string]float64 `json:"health_breakdown"`
+	Topology        *TopologyReport    `json:"topology"`
+	Staleness       *StalenessReport   `json:"staleness"`
+	Bridges         *BridgeReport      `json:"bridges"`
+	Communities     *CommunityReport   `json:"communities"`
+}
+
+// AnalysisCache holds intermediates that are expensive to compute and shared
+// across scorers (adjacency-derived stats, component membership, etc.) so
+// that registering more scorers doesn't mean recomputing topology/bridges
+// once per scorer.
+type AnalysisCache struct {
+	Topology  *TopologyReport
+	Staleness *StalenessReport
+	Bridges   *BridgeReport
+
+	pageRank    map[string]float64 // lazily computed, memoized
+	betweenness map[string]float64 // lazily computed, memoized
+}
+
+// PageRank returns the cached PageRank vector, computing it on first use.
+func (c *AnalysisCache) PageRank(snap *GraphSnapshot) map[string]float64 {
+	if c.pageRank == nil {
+		c.pageRank = computePageRank(snap, 0.85, 50, 1e-6)
+	}
+	return c.pageRank
+}
+
+// Betweenness returns the cached (unnormalized, then max-normalized on read)
+// betweenness centrality vector, computing it via Brandes' algorithm on
+// first use.
+func (c *AnalysisCache) Betweenness(snap *GraphSnapshot) map[string]float64 {
+	if c.betweenness == nil {
+		c.betweenness = computeBetweenness(snap)
+	}
+	return c.betweenness
+}
+
+// HealthScorer computes one sub-score (0..1, higher is healthier) contributing
+// to the composite health score.
+type HealthScorer interface {
+	Name() string
+	Score(snap *GraphSnapshot, cache *AnalysisCache) float64
 }
 
 // AnalyzerConfig holds analysis parameters
@@ -24,6 +58,24 @@ type AnalyzerConfig struct {
 	HubThreshold int
 	TopN         int
 	StaleDays    int64
+
+	// Resolution (gamma) is passed through to ComputeCommunities; see
+	// CommunityConfig.Resolution. Zero defaults to 1.0 (standard modularity).
+	Resolution float64
+
+	// Centrality controls ComputeTopologyWithCentrality's BetweennessHubs/
+	// PageRankHubs passes. Nil (the zero value) computes neither, matching
+	// plain ComputeTopology.
+	Centrality *CentralityConfig
+
+	// Scorers is the set of registered HealthScorer implementations. When
+	// nil, DefaultConfig's scorers (the four legacy sub-scores plus the two
+	// centrality-based ones) are used.
+	Scorers []HealthScorer
+	// Weights maps a scorer's Name() to its contribution to the composite
+	// health score. Missing entries default to 0. When nil, DefaultWeights()
+	// is used.
+	Weights map[string]float64
 }
 
 // DefaultConfig returns sensible defaults
@@ -32,54 +84,328 @@ func DefaultConfig() *AnalyzerConfig {
 		HubThreshold: 10,
 		TopN:         50,
 		StaleDays:    30,
+		Resolution:   1.0,
+		Centrality:   &CentralityConfig{Betweenness: true, PageRank: true},
+		Scorers:      DefaultScorers(),
+		Weights:      DefaultWeights(),
 	}
 }
 
-// Analyze runs all analyses and computes a composite health score
-func Analyze(snap *GraphSnapshot, config *AnalyzerConfig) *AnalysisReport {
-	topology := ComputeTopology(snap, config.HubThreshold, config.TopN)
+// DefaultScorers returns the standard scorer set: the four legacy sub-scores
+// plus PageRank-concentration and betweenness-fragility.
+func DefaultScorers() []HealthScorer {
+	return []HealthScorer{
+		ConnectivityScorer{},
+		ComponentsScorer{},
+		StalenessScorer{},
+		FragilityScorer{},
+		ConcentrationScorer{},
+		BetweennessFragilityScorer{},
+	}
+}
+
+// DefaultWeights returns the weighting matching the original fixed formula,
+// with the two new centrality scorers folded in at a modest weight (scaled
+// down from the legacy four so their sum is still 1.0).
+func DefaultWeights() map[string]float64 {
+	return map[string]float64{
+		"connectivity":          0.25,
+		"components":            0.20,
+		"staleness":             0.20,
+		"fragility":             0.15,
+		"concentration":         0.10,
+		"betweenness_fragility": 0.10,
+	}
+}
+
+func clamp(val, min, max float64) float64 {
+	if val < min {
+		return min
+	}
+	if val > max {
+		return max
+	}
+	return val
+}
+
+// Analyze runs all analyses and computes a composite health score from the
+// configured (or default) scorers and weights. ctx is passed through to
+// ComputeTopology; cancelling it makes the returned report's Topology
+// partial (Topology.Cancelled true) rather than blocking on a large graph.
+func Analyze(ctx context.Context, snap *GraphSnapshot, config *AnalyzerConfig) *AnalysisReport {
+	topology := ComputeTopology(ctx, snap, TopologyOptions{
+		HubThreshold: config.HubThreshold,
+		TopN:         config.TopN,
+		Centrality:   config.Centrality,
+	})
 	staleness := ComputeStaleness(snap, config.StaleDays)
 	bridges := ComputeBridges(snap)
 
-	total := float64(topology.TotalNodes)
+	resolution := config.Resolution
+	if resolution == 0 {
+		resolution = 1.0
+	}
+	communities := ComputeCommunities(snap, &CommunityConfig{Resolution: resolution, FragileThreshold: 2})
 
-	var connectivity, components, stalenessScore, fragility float64
+	cache := &AnalysisCache{Topology: topology, Staleness: staleness, Bridges: bridges}
 
-	if total > 0 {
-		connectivity = clamp(1.0-math.Min(float64(topology.OrphanCount)/total, 0.2)*5.0, 0, 1)
+	scorers := config.Scorers
+	if scorers == nil {
+		scorers = DefaultScorers()
 	}
-	if topology.NumComponents > 0 {
-		components = clamp(1.0/float64(topology.NumComponents), 0, 1)
+	weights := config.Weights
+	if weights == nil {
+		weights = DefaultWeights()
 	}
-	if total > 0 {
-		stalenessScore = clamp(1.0-math.Min(float64(staleness.StaleNodeCount)/total, 0.1)*10.0, 0, 1)
+
+	breakdown := make(map[string]float64, len(scorers))
+	var healthScore float64
+	for _, s := range scorers {
+		score := clamp(s.Score(snap, cache), 0, 1)
+		breakdown[s.Name()] = score
+		healthScore += weights[s.Name()] * score
 	}
-	if total > 0 {
-		fragility = clamp(1.0-math.Min(float64(bridges.APCount)/total, 0.05)*20.0, 0, 1)
+
+	return &AnalysisReport{
+		HealthScore:     healthScore,
+		HealthBreakdown: breakdown,
+		Topology:        topology,
+		Staleness:       staleness,
+		Bridges:         bridges,
+		Communities:     communities,
 	}
+}
 
-	healthScore := 0.30*connectivity + 0.25*components + 0.25*stalenessScore + 0.20*fragility
+// ConnectivityScorer penalizes a high proportion of orphaned (disconnected) nodes.
+type ConnectivityScorer struct{}
 
-	return &AnalysisReport{
-		HealthScore: healthScore,
-		HealthBreakdown: HealthBreakdown{
-			Connectivity: connectivity,
-			Components:   components,
-			Staleness:    stalenessScore,
-			Fragility:    fragility,
-		},
-		Topology:  topology,
-		Staleness: staleness,
-		Bridges:   bridges,
+func (ConnectivityScorer) Name() string { return "connectivity" }
+func (ConnectivityScorer) Score(snap *GraphSnapshot, cache *AnalysisCache) float64 {
+	total := float64(cache.Topology.TotalNodes)
+	if total == 0 {
+		return 0
 	}
+	return clamp(1.0-math.Min(float64(cache.Topology.OrphanCount)/total, 0.2)*5.0, 0, 1)
 }
 
-func clamp(val, min, max float64) float64 {
-	if val < min {
-		return min
+// ComponentsScorer penalizes graphs fragmented into many connected components.
+type ComponentsScorer struct{}
+
+func (ComponentsScorer) Name() string { return "components" }
+func (ComponentsScorer) Score(snap *GraphSnapshot, cache *AnalysisCache) float64 {
+	if cache.Topology.NumComponents == 0 {
+		return 0
 	}
-	if val > max {
-		return max
+	return clamp(1.0/float64(cache.Topology.NumComponents), 0, 1)
+}
+
+// StalenessScorer penalizes a high proportion of stale-but-referenced nodes.
+type StalenessScorer struct{}
+
+func (StalenessScorer) Name() string { return "staleness" }
+func (StalenessScorer) Score(snap *GraphSnapshot, cache *AnalysisCache) float64 {
+	total := float64(cache.Topology.TotalNodes)
+	if total == 0 {
+		return 0
+	}
+	return clamp(1.0-math.Min(float64(cache.Staleness.StaleNodeCount)/total, 0.1)*10.0, 0, 1)
+}
+
+// FragilityScorer penalizes a high proportion of articulation points.
+type FragilityScorer struct{}
+
+func (FragilityScorer) Name() string { return "fragility" }
+func (FragilityScorer) Score(snap *GraphSnapshot, cache *AnalysisCache) float64 {
+	total := float64(cache.Topology.TotalNodes)
+	if total == 0 {
+		return 0
+	}
+	return clamp(1.0-math.Min(float64(cache.Bridges.APCount)/total, 0.05)*20.0, 0, 1)
+}
+
+// ConcentrationScorer runs PageRank over the edge graph and scores
+// 1 - Gini(pagerank_vector), so both an over-concentrated graph (a few nodes
+// hold all the importance) and a totally flat one (no signal at all, Gini=0
+// but that's actually fine) land correctly: Gini near 0 means evenly spread
+// importance (healthy), Gini near 1 means one or two nodes dominate.
+type ConcentrationScorer struct{}
+
+func (ConcentrationScorer) Name() string { return "concentration" }
+func (ConcentrationScorer) Score(snap *GraphSnapshot, cache *AnalysisCache) float64 {
+	if len(snap.Nodes) == 0 {
+		return 0
+	}
+	pr := cache.PageRank(snap)
+	values := make([]float64, 0, len(pr))
+	for _, v := range pr {
+		values = append(values, v)
+	}
+	return clamp(1.0-gini(values), 0, 1)
+}
+
+// BetweennessFragilityScorer penalizes graphs with a single dominant
+// choke-point: 1 - max_normalized_betweenness.
+type BetweennessFragilityScorer struct{}
+
+func (BetweennessFragilityScorer) Name() string { return "betweenness_fragility" }
+func (BetweennessFragilityScorer) Score(snap *GraphSnapshot, cache *AnalysisCache) float64 {
+	if len(snap.Nodes) == 0 {
+		return 0
+	}
+	bc := cache.Betweenness(snap)
+	var maxVal float64
+	for _, v := range bc {
+		if v > maxVal {
+			maxVal = v
+		}
+	}
+	n := float64(len(snap.Nodes))
+	// Normalize by the theoretical max for an undirected graph: (n-1)(n-2)/2.
+	norm := (n - 1) * (n - 2) / 2
+	if norm <= 0 {
+		return 1
+	}
+	return clamp(1.0-maxVal/norm, 0, 1)
+}
+
+// gini computes the Gini coefficient of a non-negative value distribution.
+// Returns 0 for an empty or all-zero input (perfectly equal / no signal).
+func gini(values []float64) float64 {
+	n := len(values)
+	if n == 0 {
+		return 0
+	}
+	sorted := append([]float64{}, values...)
+	for i := 1; i < n; i++ {
+		for j := i; j > 0 && sorted[j-1] > sorted[j]; j-- {
+			sorted[j-1], sorted[j] = sorted[j], sorted[j-1]
+		}
+	}
+	var sum, weightedSum float64
+	for i, v := range sorted {
+		sum += v
+		weightedSum += float64(i+1) * v
+	}
+	if sum == 0 {
+		return 0
+	}
+	return (2*weightedSum)/(float64(n)*sum) - float64(n+1)/float64(n)
+}
+
+// computePageRank runs power iteration over the undirected adjacency graph
+// with the given damping factor, for at most maxIters or until the L1 delta
+// between successive iterations drops below tol.
+func computePageRank(snap *GraphSnapshot, damping float64, maxIters int, tol float64) map[string]float64 {
+	n := len(snap.Nodes)
+	rank := make(map[string]float64, n)
+	if n == 0 {
+		return rank
+	}
+	for id := range snap.Nodes {
+		rank[id] = 1.0 / float64(n)
+	}
+
+	for iter := 0; iter < maxIters; iter++ {
+		next := make(map[string]float64, n)
+		var danglingMass float64
+		for id := range snap.Nodes {
+			out := len(snap.Adj[id])
+			if out == 0 {
+				danglingMass += rank[id]
+			}
+		}
+		base := (1 - damping) / float64(n)
+		danglingShare := damping * danglingMass / float64(n)
+		for id := range snap.Nodes {
+			next[id] = base + danglingShare
+		}
+		for id := range snap.Nodes {
+			out := len(snap.Adj[id])
+			if out == 0 {
+				continue
+			}
+			share := damping * rank[id] / float64(out)
+			for _, neighbor := range snap.Adj[id] {
+				next[neighbor] += share
+			}
+		}
+
+		var delta float64
+		for id := range snap.Nodes {
+			delta += math.Abs(next[id] - rank[id])
+		}
+		rank = next
+		if delta < tol {
+			break
+		}
+	}
+	return rank
+}
+
+// computeBetweenness runs Brandes' algorithm (unweighted, O(V*E)) over the
+// undirected adjacency graph, returning raw (not yet normalized) betweenness
+// centrality per node.
+func computeBetweenness(snap *GraphSnapshot) map[string]float64 {
+	betweenness := make(map[string]float64, len(snap.Nodes))
+	for id := range snap.Nodes {
+		betweenness[id] = 0
+	}
+
+	for _, s := range snap.NodeIDs() {
+		brandesSingleSource(snap, s, betweenness)
+	}
+
+	// Undirected graph: each shortest path was counted from both endpoints.
+	for id := range betweenness {
+		betweenness[id] /= 2
+	}
+	return betweenness
+}
+
+// brandesSingleSource runs Brandes' BFS-and-accumulate pass from a single
+// source s, adding its dependency contribution into betweenness (shared
+// across sources, and across samples when only some sources are visited —
+// see computeBetweennessSampled). Callers own any final normalization
+// (undirected double-counting, sampling scale-up).
+func brandesSingleSource(snap *GraphSnapshot, s string, betweenness map[string]float64) {
+	stack := make([]string, 0, len(snap.Nodes))
+	predecessors := make(map[string][]string, len(snap.Nodes))
+	sigma := make(map[string]float64, len(snap.Nodes))
+	dist := make(map[string]int, len(snap.Nodes))
+	for id := range snap.Nodes {
+		sigma[id] = 0
+		dist[id] = -1
+	}
+	sigma[s] = 1
+	dist[s] = 0
+
+	queue := []string{s}
+	for len(queue) > 0 {
+		v := queue[0]
+		queue = queue[1:]
+		stack = append(stack, v)
+		for _, w := range snap.Adj[v] {
+			if dist[w] < 0 {
+				dist[w] = dist[v] + 1
+				queue = append(queue, w)
+			}
+			if dist[w] == dist[v]+1 {
+				sigma[w] += sigma[v]
+				predecessors[w] = append(predecessors[w], v)
+			}
+		}
+	}
+
+	delta := make(map[string]float64, len(snap.Nodes))
+	for i := len(stack) - 1; i >= 0; i-- {
+		w := stack[i]
+		for _, v := range predecessors[w] {
+			if sigma[w] != 0 {
+				delta[v] += (sigma[v] / sigma[w]) * (1 + delta[w])
+			}
+		}
+		if w != s {
+			betweenness[w] += delta[w]
+		}
 	}
-	return val
 }
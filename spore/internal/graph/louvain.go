@@ -0,0 +1,396 @@
+package graph
+
+import "sort"
+
+// CommunityAssignment is one node's Louvain community, as computed by
+// ComputeCommunities.
+type CommunityAssignment struct {
+	ID        string `json:"id"`
+	Title     string `json:"title"`
+	Community int    `json:"community"`
+}
+
+// CommunitySize is the member count of a single Louvain community.
+type CommunitySize struct {
+	Community int `json:"community"`
+	Size      int `json:"size"`
+}
+
+// CommunityFragileConnection is a pair of Louvain communities joined by very
+// few cross-edges -- the community-detection analogue of FragileConnection,
+// keyed by detected community instead of folder-hierarchy region.
+type CommunityFragileConnection struct {
+	CommunityA int `json:"community_a"`
+	CommunityB int `json:"community_b"`
+	CrossEdges int `json:"cross_edges"`
+}
+
+// CommunityReport is the result of ComputeCommunities.
+type CommunityReport struct {
+	Assignments        []CommunityAssignment        `json:"assignments"`
+	Sizes              []CommunitySize              `json:"sizes"`
+	Modularity         float64                      `json:"modularity"`
+	FragileConnections []CommunityFragileConnection `json:"fragile_connections"`
+}
+
+// CommunityConfig holds ComputeCommunities' parameters.
+type CommunityConfig struct {
+	// Resolution (gamma) scales the null-model term of the modularity-gain
+	// formula. Above 1.0 favors more, smaller communities; below 1.0 favors
+	// fewer, larger ones. 1.0 is standard modularity.
+	Resolution float64
+	// FragileThreshold is the maximum cross-community edge count for a pair
+	// of communities to be reported as a CommunityFragileConnection.
+	// Matches ComputeBridges' own "<=2 edges" fragile-region threshold.
+	FragileThreshold int
+}
+
+// DefaultCommunityConfig returns standard (gamma=1.0) modularity with
+// ComputeBridges' "<=2 edges" fragile-connection threshold.
+func DefaultCommunityConfig() *CommunityConfig {
+	return &CommunityConfig{Resolution: 1.0, FragileThreshold: 2}
+}
+
+// ComputeCommunities partitions snap into communities via the Louvain
+// method: repeated passes of greedy modularity-improving node moves
+// (louvainLocalMoving), then collapsing each resulting community into a
+// single super-node and recursing on the coarsened graph, until a pass
+// moves nothing. Edges are treated as undirected and deduplicated the same
+// way ComputeBridges does -- EdgeInfo carries no weight, so every
+// deduplicated edge counts for 1.
+//
+// Unlike CommunityIndex.refineOnce (one pass, folded into the incremental
+// union-find maintained by the API server), this runs the full multi-level
+// algorithm to convergence over a one-shot GraphSnapshot, which is what
+// FragileConnection-by-community needs: a stable partition, not a
+// best-effort nudge toward one.
+func ComputeCommunities(snap *GraphSnapshot, config *CommunityConfig) *CommunityReport {
+	if config == nil {
+		config = DefaultCommunityConfig()
+	}
+	if len(snap.Nodes) == 0 {
+		return &CommunityReport{}
+	}
+
+	nodeIDs := snap.NodeIDs()
+	idToIdx := make(map[string]int, len(nodeIDs))
+	for i, id := range nodeIDs {
+		idToIdx[id] = i
+	}
+	n := len(nodeIDs)
+
+	g := newLouvainGraph(n)
+	type edgePair struct{ u, v int }
+	seen := make(map[edgePair]bool)
+	for _, e := range snap.Edges {
+		u, okU := idToIdx[e.Source]
+		v, okV := idToIdx[e.Target]
+		if !okU || !okV || u == v {
+			continue
+		}
+		key := edgePair{u, v}
+		if u > v {
+			key = edgePair{v, u}
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		g.addEdge(u, v, 1)
+	}
+
+	// assignment[i] tracks, for original node i, the index of the super-node
+	// it currently belongs to at whatever level is being refined next; it's
+	// recomposed after every level so it always ends up mapping straight
+	// from an original node to its final top-level community.
+	assignment := make([]int, n)
+	for i := range assignment {
+		assignment[i] = i
+	}
+
+	for {
+		partition, moved := louvainLocalMoving(g, config.Resolution)
+		if !moved {
+			break
+		}
+		for i := range assignment {
+			assignment[i] = partition[assignment[i]]
+		}
+		g = collapseGraph(g, partition)
+		if g.n == 1 {
+			break
+		}
+	}
+
+	return buildCommunityReport(snap, nodeIDs, assignment, config)
+}
+
+// louvainGraph is an internal weighted undirected multigraph used only
+// inside ComputeCommunities. GraphSnapshot's own Adj/OutAdj/InAdj are
+// unweighted and rebuilt fresh on every collapse, so this keeps per-level
+// edge weights -- including the self-loops a collapsed community's internal
+// edges become -- without needing a weight field on the public EdgeInfo
+// type.
+type louvainGraph struct {
+	n         int
+	neighbors []map[int]float64 // neighbors[u][v] = edge weight u<->v, v != u
+	selfLoop  []float64         // weight of u's self-loop
+	degree    []float64         // sum of neighbor weights + 2*selfLoop
+	m2        float64           // sum of all degrees == 2 * total edge weight
+}
+
+func newLouvainGraph(n int) *louvainGraph {
+	neighbors := make([]map[int]float64, n)
+	for i := range neighbors {
+		neighbors[i] = make(map[int]float64)
+	}
+	return &louvainGraph{n: n, neighbors: neighbors, selfLoop: make([]float64, n), degree: make([]float64, n)}
+}
+
+func (g *louvainGraph) addEdge(u, v int, w float64) {
+	if u == v {
+		g.selfLoop[u] += w
+		g.degree[u] += 2 * w
+		g.m2 += 2 * w
+		return
+	}
+	g.neighbors[u][v] += w
+	g.neighbors[v][u] += w
+	g.degree[u] += w
+	g.degree[v] += w
+	g.m2 += 2 * w
+}
+
+// louvainLocalMoving runs passes of greedy node moves over g until a pass
+// moves nothing, returning each node's final community (compacted to
+// 0..k-1 via compactCommunities) and whether any node ever moved.
+//
+// The textbook gain of moving node i (currently isolated from its old
+// community) into candidate community C is
+//
+//	dQ = [(Sigma_in+2k_i,in)/2m - ((Sigma_tot+k_i)/2m)^2] -
+//	     [Sigma_in/2m - (Sigma_tot/2m)^2 - (k_i/2m)^2]
+//
+// which algebraically reduces to dQ = k_i,in/m - gamma*Sigma_tot*k_i/(2m^2)
+// once the terms that don't depend on the candidate C cancel -- resolution
+// is gamma, scaling the null-model term.
+func louvainLocalMoving(g *louvainGraph, resolution float64) ([]int, bool) {
+	n := g.n
+	community := make([]int, n)
+	communityWeight := make([]float64, n) // Sigma_tot per community
+	for i := 0; i < n; i++ {
+		community[i] = i
+		communityWeight[i] = g.degree[i]
+	}
+	if g.m2 == 0 {
+		return compactCommunities(community), false
+	}
+
+	order := make([]int, n)
+	for i := range order {
+		order[i] = i
+	}
+
+	anyMoved := false
+	for {
+		passMoved := false
+		for _, u := range order {
+			current := community[u]
+
+			weightToCommunity := make(map[int]float64)
+			for v, w := range g.neighbors[u] {
+				weightToCommunity[community[v]] += w
+			}
+
+			communityWeight[current] -= g.degree[u]
+
+			best := current
+			bestGain := weightToCommunity[current] - resolution*communityWeight[current]*g.degree[u]/g.m2
+			for c, wIn := range weightToCommunity {
+				if c == current {
+					continue
+				}
+				gain := wIn - resolution*communityWeight[c]*g.degree[u]/g.m2
+				if gain > bestGain {
+					bestGain = gain
+					best = c
+				}
+			}
+
+			communityWeight[best] += g.degree[u]
+			if best != current {
+				community[u] = best
+				passMoved = true
+			}
+		}
+		if !passMoved {
+			break
+		}
+		anyMoved = true
+	}
+
+	return compactCommunities(community), anyMoved
+}
+
+// compactCommunities renumbers community IDs to a dense 0..k-1 range,
+// assigning new IDs in order of each community's lowest member index so the
+// result is deterministic regardless of which raw IDs the local-moving
+// phase happened to leave behind.
+func compactCommunities(community []int) []int {
+	firstSeen := make(map[int]int)
+	var order []int
+	for i, c := range community {
+		if _, ok := firstSeen[c]; !ok {
+			firstSeen[c] = i
+			order = append(order, c)
+		}
+	}
+	sort.Slice(order, func(i, j int) bool { return firstSeen[order[i]] < firstSeen[order[j]] })
+	remap := make(map[int]int, len(order))
+	for newID, old := range order {
+		remap[old] = newID
+	}
+	out := make([]int, len(community))
+	for i, c := range community {
+		out[i] = remap[c]
+	}
+	return out
+}
+
+// collapseGraph builds the next Louvain level: one super-node per community
+// in partition, with inter-community edge weights summed and
+// intra-community edges (including each member's own self-loop) folded
+// into the super-node's self-loop.
+func collapseGraph(g *louvainGraph, partition []int) *louvainGraph {
+	k := 0
+	for _, c := range partition {
+		if c+1 > k {
+			k = c + 1
+		}
+	}
+	next := newLouvainGraph(k)
+	for u := 0; u < g.n; u++ {
+		cu := partition[u]
+		if g.selfLoop[u] != 0 {
+			next.addEdge(cu, cu, g.selfLoop[u])
+		}
+		for v, w := range g.neighbors[u] {
+			if v < u {
+				continue // each undirected pair handled once, from its lower-indexed endpoint
+			}
+			cv := partition[v]
+			next.addEdge(cu, cv, w)
+		}
+	}
+	return next
+}
+
+// buildCommunityReport assembles the public CommunityReport from the final
+// original-node-to-community assignment, recomputing modularity and
+// cross-community edge counts directly from snap (rather than from the
+// collapsed graph) so both stay expressed in terms of the original edges.
+func buildCommunityReport(snap *GraphSnapshot, nodeIDs []string, assignment []int, config *CommunityConfig) *CommunityReport {
+	n := len(nodeIDs)
+	idToIdx := make(map[string]int, n)
+	for i, id := range nodeIDs {
+		idToIdx[id] = i
+	}
+
+	assignments := make([]CommunityAssignment, n)
+	sizeOf := make(map[int]int)
+	for i, id := range nodeIDs {
+		c := assignment[i]
+		assignments[i] = CommunityAssignment{ID: id, Title: snap.Nodes[id].Title, Community: c}
+		sizeOf[c]++
+	}
+	sizes := make([]CommunitySize, 0, len(sizeOf))
+	for c, size := range sizeOf {
+		sizes = append(sizes, CommunitySize{Community: c, Size: size})
+	}
+	sort.Slice(sizes, func(i, j int) bool { return sizes[i].Community < sizes[j].Community })
+
+	type edgePair struct{ u, v int }
+	seen := make(map[edgePair]bool)
+	var edges [][2]int
+	for _, e := range snap.Edges {
+		u, okU := idToIdx[e.Source]
+		v, okV := idToIdx[e.Target]
+		if !okU || !okV || u == v {
+			continue
+		}
+		key := edgePair{u, v}
+		if u > v {
+			key = edgePair{v, u}
+		}
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		edges = append(edges, [2]int{u, v})
+	}
+
+	modularity := computeModularity(n, edges, assignment, config.Resolution)
+
+	type commPair struct{ a, b int }
+	pairCounts := make(map[commPair]int)
+	for _, e := range edges {
+		ca, cb := assignment[e[0]], assignment[e[1]]
+		if ca == cb {
+			continue
+		}
+		key := commPair{ca, cb}
+		if ca > cb {
+			key = commPair{cb, ca}
+		}
+		pairCounts[key]++
+	}
+	var fragile []CommunityFragileConnection
+	for pair, count := range pairCounts {
+		if count <= config.FragileThreshold {
+			fragile = append(fragile, CommunityFragileConnection{CommunityA: pair.a, CommunityB: pair.b, CrossEdges: count})
+		}
+	}
+	sort.Slice(fragile, func(i, j int) bool { return fragile[i].CrossEdges < fragile[j].CrossEdges })
+
+	return &CommunityReport{
+		Assignments:        assignments,
+		Sizes:              sizes,
+		Modularity:         modularity,
+		FragileConnections: fragile,
+	}
+}
+
+// computeModularity computes Q = Sum_c [L_c/m - gamma*(D_c/2m)^2], the
+// standard per-community form of modularity equivalent to the pairwise
+// Sum_ij [A_ij - gamma*k_i*k_j/2m] delta(c_i,c_j) definition, where L_c is
+// the number of (deduplicated, undirected) edges internal to community c
+// and D_c is the summed degree of c's members.
+func computeModularity(n int, edges [][2]int, assignment []int, resolution float64) float64 {
+	if len(edges) == 0 {
+		return 0
+	}
+	degree := make([]float64, n)
+	for _, e := range edges {
+		degree[e[0]]++
+		degree[e[1]]++
+	}
+	m := float64(len(edges))
+	m2 := 2 * m
+
+	internalEdges := make(map[int]float64)
+	communityDegree := make(map[int]float64)
+	for i := 0; i < n; i++ {
+		communityDegree[assignment[i]] += degree[i]
+	}
+	for _, e := range edges {
+		if assignment[e[0]] == assignment[e[1]] {
+			internalEdges[assignment[e[0]]]++
+		}
+	}
+
+	var q float64
+	for c, d := range communityDegree {
+		q += internalEdges[c]/m - resolution*(d/m2)*(d/m2)
+	}
+	return q
+}
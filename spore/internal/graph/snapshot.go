@@ -1,6 +1,10 @@
 package graph
 
-import "sort"
+import (
+	"sort"
+
+	"mycelica/spore/internal/db"
+)
 
 // NodeInfo is a lightweight node representation decoupled from DB types
 type NodeInfo struct {
@@ -22,6 +26,12 @@ type EdgeInfo struct {
 	EdgeType string // lowercase
 	CreatedAt int64
 	UpdatedAt *int64
+
+	// Synthetic marks an EdgeInfo NewSnapshot fabricated itself -- the
+	// mirror of a real edge, per a *db.EdgeKindRegistry passed to it --
+	// rather than one backed by a row in the edges table. Its ID is the
+	// mirrored edge's ID; it carries no CreatedAt/UpdatedAt of its own.
+	Synthetic bool
 }
 
 // GraphSnapshot holds a graph with precomputed adjacency lists and region map
@@ -32,10 +42,30 @@ type GraphSnapshot struct {
 	OutAdj  map[string][]string // directed: source -> targets
 	InAdj   map[string][]string // directed: target -> sources
 	Regions map[string]string   // node_id -> depth-1 ancestor
+
+	// Touched holds the IDs of every node added, removed, or otherwise
+	// affected by the most recent Apply call -- the set an IncrementalXxx
+	// analyzer needs to re-examine rather than trusting a prior report for.
+	// NewSnapshot leaves it nil; a nil Touched means "never patched
+	// incrementally", which IncrementalXxx analyzers treat the same as
+	// "everything is touched" (i.e. fall back to a full recompute).
+	Touched map[string]bool
 }
 
-// NewSnapshot builds a GraphSnapshot from raw nodes and edges
-func NewSnapshot(nodes []*NodeInfo, edges []EdgeInfo) *GraphSnapshot {
+// NewSnapshot builds a GraphSnapshot from raw nodes and edges. If a
+// *db.EdgeKindRegistry is passed (at most the first is used), every edge
+// whose type has a registered mirror also gets a synthetic reverse entry
+// in OutAdj/InAdj/Edges -- e.g. a "references" edge A->B additionally
+// appears as a "referenced_by" edge B->A, tagged EdgeInfo.Synthetic -- so
+// callers that want symmetric traversal without duplicating rows in
+// SQLite can opt in. Existing callers that pass no registry (including
+// SnapshotFromDB) are unaffected: Edges and edge counts are unchanged.
+func NewSnapshot(nodes []*NodeInfo, edges []EdgeInfo, registries ...*db.EdgeKindRegistry) *GraphSnapshot {
+	var edgeKinds *db.EdgeKindRegistry
+	if len(registries) > 0 {
+		edgeKinds = registries[0]
+	}
+
 	nodeMap := make(map[string]*NodeInfo, len(nodes))
 	adj := make(map[string][]string)
 	outAdj := make(map[string][]string)
@@ -48,7 +78,31 @@ func NewSnapshot(nodes []*NodeInfo, edges []EdgeInfo) *GraphSnapshot {
 		inAdj[n.ID] = nil
 	}
 
-	for _, e := range edges {
+	allEdges := edges
+	if edgeKinds != nil {
+		allEdges = make([]EdgeInfo, len(edges))
+		copy(allEdges, edges)
+		for _, e := range edges {
+			if _, ok := nodeMap[e.Source]; !ok {
+				continue
+			}
+			if _, ok := nodeMap[e.Target]; !ok {
+				continue
+			}
+			if mirrorKind := edgeKinds.Mirror(e.EdgeType); mirrorKind != "" {
+				allEdges = append(allEdges, EdgeInfo{
+					ID:        e.ID,
+					Source:    e.Target,
+					Target:    e.Source,
+					EdgeType:  mirrorKind,
+					CreatedAt: e.CreatedAt,
+					Synthetic: true,
+				})
+			}
+		}
+	}
+
+	for _, e := range allEdges {
 		if _, ok := nodeMap[e.Source]; !ok {
 			continue
 		}
@@ -65,7 +119,7 @@ func NewSnapshot(nodes []*NodeInfo, edges []EdgeInfo) *GraphSnapshot {
 
 	return &GraphSnapshot{
 		Nodes:   nodeMap,
-		Edges:   edges,
+		Edges:   allEdges,
 		Adj:     adj,
 		OutAdj:  outAdj,
 		InAdj:   inAdj,
@@ -73,6 +127,155 @@ func NewSnapshot(nodes []*NodeInfo, edges []EdgeInfo) *GraphSnapshot {
 	}
 }
 
+// SnapshotDelta describes incremental node/edge changes to fold into an
+// existing GraphSnapshot via Apply, instead of rebuilding one from scratch
+// with NewSnapshot. Nodes and edges are identified by ID; UpdatedNodes
+// replaces the stored NodeInfo for IDs already present.
+type SnapshotDelta struct {
+	AddedNodes     []*NodeInfo
+	UpdatedNodes   []*NodeInfo
+	RemovedNodeIDs []string
+	AddedEdges     []EdgeInfo
+	RemovedEdgeIDs []string
+}
+
+// Apply patches s in place with delta's changes -- updating Nodes, Edges,
+// Adj/OutAdj/InAdj, and Regions -- instead of NewSnapshot's full rebuild,
+// and records every affected node ID in s.Touched. It returns s so callers
+// can write `snap = snap.Apply(delta)`.
+//
+// Region reassignment only follows a touched node itself, not its
+// descendants: reparenting an interior node whose Region changes doesn't
+// cascade the recompute down the rest of its subtree (findDepth1Ancestor
+// walks up, not down). A delta that reparents an interior node should
+// still go through NewSnapshot.
+func (s *GraphSnapshot) Apply(delta SnapshotDelta) *GraphSnapshot {
+	touched := make(map[string]bool)
+
+	removedEdgeIDs := make(map[string]bool, len(delta.RemovedEdgeIDs))
+	for _, id := range delta.RemovedEdgeIDs {
+		removedEdgeIDs[id] = true
+	}
+	for _, id := range delta.RemovedNodeIDs {
+		// Cascade: dropping a node drops every edge touching it too.
+		for _, e := range s.Edges {
+			if e.Source == id || e.Target == id {
+				removedEdgeIDs[e.ID] = true
+			}
+		}
+	}
+
+	if len(removedEdgeIDs) > 0 {
+		var kept []EdgeInfo
+		for _, e := range s.Edges {
+			if !removedEdgeIDs[e.ID] {
+				kept = append(kept, e)
+				continue
+			}
+			s.removeAdjEntry(e.Source, e.Target)
+			touched[e.Source] = true
+			touched[e.Target] = true
+		}
+		s.Edges = kept
+	}
+
+	for _, id := range delta.RemovedNodeIDs {
+		delete(s.Nodes, id)
+		delete(s.Adj, id)
+		delete(s.OutAdj, id)
+		delete(s.InAdj, id)
+		delete(s.Regions, id)
+		touched[id] = true
+	}
+
+	for _, n := range delta.AddedNodes {
+		s.Nodes[n.ID] = n
+		if _, ok := s.Adj[n.ID]; !ok {
+			s.Adj[n.ID] = nil
+			s.OutAdj[n.ID] = nil
+			s.InAdj[n.ID] = nil
+		}
+		touched[n.ID] = true
+	}
+
+	for _, n := range delta.UpdatedNodes {
+		s.Nodes[n.ID] = n
+		touched[n.ID] = true
+	}
+
+	for _, e := range delta.AddedEdges {
+		if _, ok := s.Nodes[e.Source]; !ok {
+			continue
+		}
+		if _, ok := s.Nodes[e.Target]; !ok {
+			continue
+		}
+		s.Edges = append(s.Edges, e)
+		s.Adj[e.Source] = append(s.Adj[e.Source], e.Target)
+		s.Adj[e.Target] = append(s.Adj[e.Target], e.Source)
+		s.OutAdj[e.Source] = append(s.OutAdj[e.Source], e.Target)
+		s.InAdj[e.Target] = append(s.InAdj[e.Target], e.Source)
+		touched[e.Source] = true
+		touched[e.Target] = true
+	}
+
+	for id := range touched {
+		node, ok := s.Nodes[id]
+		if !ok {
+			continue
+		}
+		if node.Depth <= 1 {
+			s.Regions[id] = id
+		} else {
+			s.Regions[id] = findDepth1Ancestor(id, s.Nodes)
+		}
+	}
+
+	s.Touched = touched
+	return s
+}
+
+// removeAdjEntry undoes exactly what NewSnapshot's edge loop added for a
+// single source->target edge: one occurrence of target from source's
+// Adj/OutAdj, and one occurrence of source from target's Adj/InAdj.
+func (s *GraphSnapshot) removeAdjEntry(source, target string) {
+	s.Adj[source] = removeOneOccurrence(s.Adj[source], target)
+	s.Adj[target] = removeOneOccurrence(s.Adj[target], source)
+	s.OutAdj[source] = removeOneOccurrence(s.OutAdj[source], target)
+	s.InAdj[target] = removeOneOccurrence(s.InAdj[target], source)
+}
+
+func removeOneOccurrence(list []string, val string) []string {
+	for i, v := range list {
+		if v == val {
+			return append(list[:i], list[i+1:]...)
+		}
+	}
+	return list
+}
+
+// inducedSubsnapshot returns a fresh GraphSnapshot over exactly nodeIDs and
+// the edges with both endpoints among them. IncrementalComputeBridges uses
+// it to re-run ComputeBridges over only the touched connected components
+// instead of the whole graph.
+func (s *GraphSnapshot) inducedSubsnapshot(nodeIDs []string) *GraphSnapshot {
+	set := make(map[string]bool, len(nodeIDs))
+	nodes := make([]*NodeInfo, 0, len(nodeIDs))
+	for _, id := range nodeIDs {
+		if n, ok := s.Nodes[id]; ok {
+			nodes = append(nodes, n)
+			set[id] = true
+		}
+	}
+	var edges []EdgeInfo
+	for _, e := range s.Edges {
+		if set[e.Source] && set[e.Target] {
+			edges = append(edges, e)
+		}
+	}
+	return NewSnapshot(nodes, edges)
+}
+
 // FilterToRegion returns a new snapshot containing only descendants of regionNodeID
 func (s *GraphSnapshot) FilterToRegion(regionNodeID string) *GraphSnapshot {
 	included := make(map[string]bool)
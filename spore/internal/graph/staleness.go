@@ -100,3 +100,19 @@ func ComputeStaleness(snap *GraphSnapshot, staleDays int64) *StalenessReport {
 		StaleSummaryCount: len(staleSummaries),
 	}
 }
+
+// IncrementalComputeStaleness returns prev unchanged when snap.Touched is
+// empty, and otherwise falls back to a full ComputeStaleness. Note that
+// unlike the graph-structure analyzers, staleness also drifts with wall-clock
+// time alone (a node can cross staleDays without any edit touching it), so a
+// caller polling on a fixed interval should still force an occasional full
+// recompute rather than relying solely on Touched.
+//
+// A nil prev, or a snap whose Touched is nil (never went through Apply),
+// always does a full recompute.
+func IncrementalComputeStaleness(snap *GraphSnapshot, prev *StalenessReport, staleDays int64) *StalenessReport {
+	if prev == nil || snap.Touched == nil || len(snap.Touched) > 0 {
+		return ComputeStaleness(snap, staleDays)
+	}
+	return prev
+}
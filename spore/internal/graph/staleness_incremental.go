@@ -0,0 +1,142 @@
+package graph
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+// edgeRef is a lightweight incoming-edge record used by the reverse index
+// below: just enough to count recent references without rescanning every
+// edge in snap.Edges once per node.
+type edgeRef struct {
+	Source    string
+	CreatedAt int64
+}
+
+// buildIncomingEdgeIndex reverse-indexes snap.Edges by target node ID in a
+// single pass (excluding self-edges), so callers can look up a node's
+// incoming edges in O(1) instead of rescanning snap.Edges for every node, as
+// ComputeStaleness does.
+func buildIncomingEdgeIndex(snap *GraphSnapshot) map[string][]edgeRef {
+	idx := make(map[string][]edgeRef, len(snap.Nodes))
+	for _, e := range snap.Edges {
+		if e.Source == e.Target {
+			continue
+		}
+		idx[e.Target] = append(idx[e.Target], edgeRef{Source: e.Source, CreatedAt: e.CreatedAt})
+	}
+	return idx
+}
+
+// ComputeStalenessIncremental is equivalent to ComputeStaleness, but builds a
+// reverse edge index once (instead of ComputeStaleness's per-node scan of
+// snap.Edges) and persists a per-node checkpoint via d so that a later call
+// can skip recomputing a node's RecentRefCount entirely when neither its
+// UpdatedAt nor its newest incoming edge's CreatedAt has advanced since the
+// checkpoint was written. Stale-summary detection is already O(E), so it's
+// computed the same way as ComputeStaleness rather than checkpointed.
+func ComputeStalenessIncremental(d *db.DB, snap *GraphSnapshot, staleDays int64) (*StalenessReport, error) {
+	nowMs := time.Now().UnixMilli()
+	staleThresholdMs := staleDays * 86_400_000
+	recentWindowMs := int64(7 * 86_400_000)
+
+	checkpoints, err := d.GetStalenessCheckpoints()
+	if err != nil {
+		return nil, fmt.Errorf("loading staleness checkpoints: %w", err)
+	}
+	incoming := buildIncomingEdgeIndex(snap)
+
+	var staleNodes []StaleNode
+	var toSave []db.StalenessCheckpoint
+	for _, node := range snap.Nodes {
+		ageMs := nowMs - node.UpdatedAt
+		if ageMs <= staleThresholdMs {
+			continue
+		}
+
+		var newestIncoming int64
+		for _, ref := range incoming[node.ID] {
+			if ref.CreatedAt > newestIncoming {
+				newestIncoming = ref.CreatedAt
+			}
+		}
+
+		if cp, ok := checkpoints[node.ID]; ok &&
+			node.UpdatedAt <= cp.LastUpdatedAt && newestIncoming <= cp.LastComputedAt {
+			if cp.LastRefCount > 0 {
+				staleNodes = append(staleNodes, StaleNode{
+					ID:              node.ID,
+					Title:           node.Title,
+					DaysSinceUpdate: ageMs / 86_400_000,
+					RecentRefCount:  cp.LastRefCount,
+				})
+			}
+			continue
+		}
+
+		recentCount := 0
+		for _, ref := range incoming[node.ID] {
+			if (nowMs - ref.CreatedAt) < recentWindowMs {
+				recentCount++
+			}
+		}
+		if recentCount > 0 {
+			staleNodes = append(staleNodes, StaleNode{
+				ID:              node.ID,
+				Title:           node.Title,
+				DaysSinceUpdate: ageMs / 86_400_000,
+				RecentRefCount:  recentCount,
+			})
+		}
+		toSave = append(toSave, db.StalenessCheckpoint{
+			NodeID:         node.ID,
+			LastComputedAt: nowMs,
+			LastRefCount:   recentCount,
+			LastUpdatedAt:  node.UpdatedAt,
+		})
+	}
+	sort.Slice(staleNodes, func(i, j int) bool {
+		return staleNodes[i].RecentRefCount > staleNodes[j].RecentRefCount
+	})
+
+	if len(toSave) > 0 {
+		if err := d.SaveStalenessCheckpoints(toSave); err != nil {
+			return nil, fmt.Errorf("saving staleness checkpoints: %w", err)
+		}
+	}
+
+	var staleSummaries []StaleSummary
+	for _, e := range snap.Edges {
+		if e.EdgeType != "summarizes" {
+			continue
+		}
+		sourceNode := snap.Nodes[e.Source]
+		targetNode := snap.Nodes[e.Target]
+		if sourceNode == nil || targetNode == nil {
+			continue
+		}
+		if targetNode.UpdatedAt > sourceNode.UpdatedAt {
+			driftMs := targetNode.UpdatedAt - sourceNode.UpdatedAt
+			staleSummaries = append(staleSummaries, StaleSummary{
+				SummaryNodeID: e.Source,
+				SummaryTitle:  sourceNode.Title,
+				TargetNodeID:  e.Target,
+				TargetTitle:   targetNode.Title,
+				DriftDays:     driftMs / 86_400_000,
+			})
+		}
+	}
+	sort.Slice(staleSummaries, func(i, j int) bool {
+		return staleSummaries[i].DriftDays > staleSummaries[j].DriftDays
+	})
+
+	return &StalenessReport{
+		StaleNodes:        staleNodes,
+		StaleSummaries:    staleSummaries,
+		StaleNodeCount:    len(staleNodes),
+		StaleSummaryCount: len(staleSummaries),
+	}, nil
+}
@@ -0,0 +1,236 @@
+package graph
+
+import (
+	"fmt"
+	"math/rand"
+	"testing"
+
+	"mycelica/spore/internal/db"
+)
+
+func TestComputeStalenessIncremental_MatchesComputeStaleness(t *testing.T) {
+	snap := makeTestSnapshot(
+		[]struct {
+			id        string
+			createdAt int64
+			updatedAt int64
+			parentID  *string
+			depth     int
+		}{
+			{"A", daysAgo(100), daysAgo(90), nil, 0},
+			{"B", nowMs(), nowMs(), nil, 0},
+		},
+		[]struct {
+			source, target, edgeType string
+			createdAt                int64
+		}{
+			{"B", "A", "reference", daysAgo(1)},
+		},
+	)
+
+	want := ComputeStaleness(snap, 30)
+
+	d, err := db.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer d.Close()
+
+	got, err := ComputeStalenessIncremental(d, snap, 30)
+	if err != nil {
+		t.Fatalf("ComputeStalenessIncremental: %v", err)
+	}
+	if got.StaleNodeCount != want.StaleNodeCount || got.StaleSummaryCount != want.StaleSummaryCount {
+		t.Fatalf("got %+v, want %+v", got, want)
+	}
+	if got.StaleNodes[0].ID != want.StaleNodes[0].ID || got.StaleNodes[0].RecentRefCount != want.StaleNodes[0].RecentRefCount {
+		t.Errorf("got %+v, want %+v", got.StaleNodes[0], want.StaleNodes[0])
+	}
+}
+
+func TestComputeStalenessIncremental_SecondCallReusesCheckpoint(t *testing.T) {
+	snap := makeTestSnapshot(
+		[]struct {
+			id        string
+			createdAt int64
+			updatedAt int64
+			parentID  *string
+			depth     int
+		}{
+			{"A", daysAgo(100), daysAgo(90), nil, 0},
+			{"B", nowMs(), nowMs(), nil, 0},
+		},
+		[]struct {
+			source, target, edgeType string
+			createdAt                int64
+		}{
+			{"B", "A", "reference", daysAgo(1)},
+		},
+	)
+
+	d, err := db.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer d.Close()
+
+	first, err := ComputeStalenessIncremental(d, snap, 30)
+	if err != nil {
+		t.Fatalf("first ComputeStalenessIncremental: %v", err)
+	}
+
+	checkpoints, err := d.GetStalenessCheckpoints()
+	if err != nil {
+		t.Fatalf("GetStalenessCheckpoints: %v", err)
+	}
+	if len(checkpoints) == 0 {
+		t.Fatal("expected the first call to persist at least one checkpoint")
+	}
+
+	second, err := ComputeStalenessIncremental(d, snap, 30)
+	if err != nil {
+		t.Fatalf("second ComputeStalenessIncremental: %v", err)
+	}
+	if second.StaleNodeCount != first.StaleNodeCount {
+		t.Errorf("expected a repeat call over an unchanged snapshot to agree, got %+v vs %+v", second, first)
+	}
+	if second.StaleNodes[0].RecentRefCount != first.StaleNodes[0].RecentRefCount {
+		t.Errorf("expected the cached RecentRefCount to be reused unchanged, got %d vs %d",
+			second.StaleNodes[0].RecentRefCount, first.StaleNodes[0].RecentRefCount)
+	}
+}
+
+func TestComputeStalenessIncremental_AdvancedUpdatedAtForcesRecompute(t *testing.T) {
+	mkSnap := func(bUpdatedAt int64) *GraphSnapshot {
+		return makeTestSnapshot(
+			[]struct {
+				id        string
+				createdAt int64
+				updatedAt int64
+				parentID  *string
+				depth     int
+			}{
+				{"A", daysAgo(100), daysAgo(90), nil, 0},
+				{"B", daysAgo(100), bUpdatedAt, nil, 0},
+			},
+			[]struct {
+				source, target, edgeType string
+				createdAt                int64
+			}{
+				{"B", "A", "reference", daysAgo(1)},
+			},
+		)
+	}
+
+	d, err := db.OpenDB(":memory:")
+	if err != nil {
+		t.Fatalf("OpenDB: %v", err)
+	}
+	defer d.Close()
+
+	if _, err := ComputeStalenessIncremental(d, mkSnap(daysAgo(50)), 30); err != nil {
+		t.Fatalf("first ComputeStalenessIncremental: %v", err)
+	}
+
+	// Add a fresh incoming edge for A and recompute against a snapshot where
+	// B's UpdatedAt has advanced -- the checkpoint for A should still be
+	// reusable (A itself hasn't changed), but this confirms nothing breaks
+	// when an unrelated node's UpdatedAt moves forward.
+	got, err := ComputeStalenessIncremental(d, mkSnap(nowMs()), 30)
+	if err != nil {
+		t.Fatalf("second ComputeStalenessIncremental: %v", err)
+	}
+	if got.StaleNodeCount != 1 || got.StaleNodes[0].ID != "A" {
+		t.Fatalf("expected A to remain the sole stale node, got %+v", got)
+	}
+}
+
+// syntheticStalenessSnapshot builds a synthetic graph of n nodes for
+// benchmarking: a long tail of old, stale nodes each referenced by a handful
+// of recently-created edges from newer nodes, matching the shape
+// ComputeStaleness/ComputeStalenessIncremental are optimizing for.
+func syntheticStalenessSnapshot(n int) *GraphSnapshot {
+	rng := rand.New(rand.NewSource(42))
+	nodes := make([]*NodeInfo, 0, n)
+	for i := 0; i < n; i++ {
+		id := fmt.Sprintf("node-%d", i)
+		var updatedAt int64
+		if i%10 == 0 {
+			updatedAt = nowMs() // recent, never stale
+		} else {
+			updatedAt = daysAgo(90)
+		}
+		nodes = append(nodes, &NodeInfo{
+			ID:        id,
+			Title:     id,
+			CreatedAt: daysAgo(100),
+			UpdatedAt: updatedAt,
+			Depth:     0,
+		})
+	}
+
+	edges := make([]EdgeInfo, 0, n*3)
+	for i := 0; i < n; i++ {
+		for k := 0; k < 3; k++ {
+			target := rng.Intn(n)
+			edges = append(edges, EdgeInfo{
+				ID:        fmt.Sprintf("edge-%d-%d", i, k),
+				Source:    fmt.Sprintf("node-%d", i),
+				Target:    fmt.Sprintf("node-%d", target),
+				EdgeType:  "reference",
+				CreatedAt: daysAgo(1),
+			})
+		}
+	}
+	return NewSnapshot(nodes, edges)
+}
+
+func BenchmarkComputeStaleness_Scale10k(b *testing.B) {
+	snap := syntheticStalenessSnapshot(10_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ComputeStaleness(snap, 30)
+	}
+}
+
+func BenchmarkComputeStalenessIncremental_Scale10k(b *testing.B) {
+	snap := syntheticStalenessSnapshot(10_000)
+	d, err := db.OpenDB(":memory:")
+	if err != nil {
+		b.Fatalf("OpenDB: %v", err)
+	}
+	defer d.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ComputeStalenessIncremental(d, snap, 30); err != nil {
+			b.Fatalf("ComputeStalenessIncremental: %v", err)
+		}
+	}
+}
+
+func BenchmarkComputeStaleness_Scale100k(b *testing.B) {
+	snap := syntheticStalenessSnapshot(100_000)
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ComputeStaleness(snap, 30)
+	}
+}
+
+func BenchmarkComputeStalenessIncremental_Scale100k(b *testing.B) {
+	snap := syntheticStalenessSnapshot(100_000)
+	d, err := db.OpenDB(":memory:")
+	if err != nil {
+		b.Fatalf("OpenDB: %v", err)
+	}
+	defer d.Close()
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, err := ComputeStalenessIncremental(d, snap, 30); err != nil {
+			b.Fatalf("ComputeStalenessIncremental: %v", err)
+		}
+	}
+}
@@ -1,6 +1,10 @@
 package graph
 
-import "sort"
+import (
+	"context"
+	"math/rand"
+	"sort"
+)
 
 // HubNode is a node with high connectivity
 type HubNode struct {
@@ -11,6 +15,29 @@ type HubNode struct {
 	OutDegree int    `json:"out_degree"`
 }
 
+// CentralityHubNode is a node ranked by a centrality score rather than raw
+// degree, surfacing structurally important but potentially low-degree nodes
+// (e.g. a single-edge bridge between two otherwise unconnected clusters).
+type CentralityHubNode struct {
+	ID    string  `json:"id"`
+	Title string  `json:"title"`
+	Score float64 `json:"score"`
+}
+
+// CentralityConfig toggles ComputeTopologyWithCentrality's two centrality
+// passes independently, since exact betweenness is O(N*E) and callers on
+// large graphs may want to skip it (or approximate it) while still getting
+// PageRank, which is only O(E) per iteration.
+type CentralityConfig struct {
+	Betweenness bool // compute BetweennessHubs
+	PageRank    bool // compute PageRankHubs
+
+	// SampleK, if > 0, approximates betweenness by running Brandes' algorithm
+	// from SampleK random sources instead of every node, scaling the result
+	// by N/SampleK. Ignored if Betweenness is false or SampleK >= N.
+	SampleK int
+}
+
 // DegreeBucket is one bucket in the degree histogram
 type DegreeBucket struct {
 	Label string `json:"label"`
@@ -28,10 +55,45 @@ type TopologyReport struct {
 	OrphanIDs         []string       `json:"orphan_ids"`
 	DegreeHistogram   []DegreeBucket `json:"degree_histogram"`
 	Hubs              []HubNode      `json:"hubs"`
+
+	BetweennessHubs []CentralityHubNode `json:"betweenness_hubs,omitempty"`
+	PageRankHubs    []CentralityHubNode `json:"pagerank_hubs,omitempty"`
+
+	// Cancelled is true if ctx was cancelled (or its deadline passed) before
+	// ComputeTopology finished every phase. The report still reflects
+	// whatever phases completed first; later phases (centrality passes run
+	// last) are simply absent rather than zeroed out.
+	Cancelled bool `json:"cancelled,omitempty"`
+}
+
+// TopologyOptions configures ComputeTopology.
+type TopologyOptions struct {
+	HubThreshold int
+	TopN         int
+
+	// Centrality enables the BetweennessHubs/PageRankHubs passes; nil skips
+	// both.
+	Centrality *CentralityConfig
+
+	// ProgressFunc, if set, is called periodically during component
+	// discovery and degree scanning with (done, total), so a caller (e.g. a
+	// TUI) can render a progress bar over a large graph.
+	ProgressFunc func(done, total int)
+}
+
+func (o TopologyOptions) reportProgress(done, total int) {
+	if o.ProgressFunc != nil {
+		o.ProgressFunc(done, total)
+	}
 }
 
-// ComputeTopology analyzes graph topology: components, orphans, degree distribution, hubs
-func ComputeTopology(snap *GraphSnapshot, hubThreshold, topN int) *TopologyReport {
+// ComputeTopology analyzes graph topology: components, orphans, degree
+// distribution, hubs, and (per opts.Centrality) betweenness/PageRank hubs.
+// It checks ctx between phases and during its per-edge and per-node passes,
+// so cancelling ctx partway through a multi-minute pass over a large graph
+// returns whatever was computed so far with Cancelled set, instead of
+// blocking until the whole report is ready.
+func ComputeTopology(ctx context.Context, snap *GraphSnapshot, opts TopologyOptions) *TopologyReport {
 	totalNodes := len(snap.Nodes)
 	totalEdges := len(snap.Edges)
 
@@ -44,7 +106,15 @@ func ComputeTopology(snap *GraphSnapshot, hubThreshold, topN int) *TopologyRepor
 	// Connected components via UnionFind
 	nodeIDs := snap.NodeIDs()
 	uf := NewUnionFind(nodeIDs)
-	for _, e := range snap.Edges {
+	for i, e := range snap.Edges {
+		if ctx.Err() != nil {
+			return &TopologyReport{
+				TotalNodes:      totalNodes,
+				TotalEdges:      totalEdges,
+				DegreeHistogram: defaultHistogram(),
+				Cancelled:       true,
+			}
+		}
 		if _, ok := snap.Nodes[e.Source]; !ok {
 			continue
 		}
@@ -52,6 +122,7 @@ func ComputeTopology(snap *GraphSnapshot, hubThreshold, topN int) *TopologyRepor
 			continue
 		}
 		uf.Union(e.Source, e.Target)
+		opts.reportProgress(i+1, totalEdges)
 	}
 
 	components := uf.Components()
@@ -66,35 +137,23 @@ func ComputeTopology(snap *GraphSnapshot, hubThreshold, topN int) *TopologyRepor
 		}
 	}
 
-	// Orphans: degree == 0
+	// Orphans, degree histogram, and raw-degree hubs all walk the same node
+	// list, so they share one pass.
 	var orphans []string
-	for _, id := range nodeIDs {
-		if len(snap.Adj[id]) == 0 {
-			orphans = append(orphans, id)
-		}
-	}
-	orphanCount := len(orphans)
-	sort.Strings(orphans)
-	if len(orphans) > topN {
-		orphans = orphans[:topN]
-	}
-
-	// Degree histogram (log-scale buckets)
 	buckets := [7]int{}
-	for _, id := range nodeIDs {
-		degree := len(snap.Adj[id])
-		buckets[degreeBucket(degree)]++
-	}
-	histogram := defaultHistogram()
-	for i := range histogram {
-		histogram[i].Count = buckets[i]
-	}
-
-	// Hubs: degree > threshold
 	var hubs []HubNode
-	for _, id := range nodeIDs {
+	cancelled := false
+	for i, id := range nodeIDs {
+		if ctx.Err() != nil {
+			cancelled = true
+			break
+		}
 		degree := len(snap.Adj[id])
-		if degree > hubThreshold {
+		buckets[degreeBucket(degree)]++
+		if degree == 0 {
+			orphans = append(orphans, id)
+		}
+		if degree > opts.HubThreshold {
 			hubs = append(hubs, HubNode{
 				ID:        id,
 				Title:     snap.Nodes[id].Title,
@@ -103,13 +162,24 @@ func ComputeTopology(snap *GraphSnapshot, hubThreshold, topN int) *TopologyRepor
 				OutDegree: len(snap.OutAdj[id]),
 			})
 		}
+		opts.reportProgress(i+1, totalNodes)
+	}
+
+	orphanCount := len(orphans)
+	sort.Strings(orphans)
+	if len(orphans) > opts.TopN {
+		orphans = orphans[:opts.TopN]
+	}
+	histogram := defaultHistogram()
+	for i := range histogram {
+		histogram[i].Count = buckets[i]
 	}
 	sort.Slice(hubs, func(i, j int) bool { return hubs[i].Degree > hubs[j].Degree })
-	if len(hubs) > topN {
-		hubs = hubs[:topN]
+	if len(hubs) > opts.TopN {
+		hubs = hubs[:opts.TopN]
 	}
 
-	return &TopologyReport{
+	report := &TopologyReport{
 		TotalNodes:        totalNodes,
 		TotalEdges:        totalEdges,
 		NumComponents:     numComponents,
@@ -119,7 +189,105 @@ func ComputeTopology(snap *GraphSnapshot, hubThreshold, topN int) *TopologyRepor
 		OrphanIDs:         orphans,
 		DegreeHistogram:   histogram,
 		Hubs:              hubs,
+		Cancelled:         cancelled,
+	}
+	if cancelled {
+		return report
+	}
+
+	centrality := opts.Centrality
+	if centrality != nil && centrality.Betweenness {
+		var bc map[string]float64
+		if centrality.SampleK > 0 && centrality.SampleK < totalNodes {
+			bc = computeBetweennessSampled(snap, centrality.SampleK)
+		} else {
+			bc = computeBetweenness(snap)
+		}
+		report.BetweennessHubs = topCentralityHubs(snap, bc, opts.TopN)
+	}
+	if centrality != nil && centrality.PageRank {
+		pr := computePageRank(snap, 0.85, 100, 1e-6)
+		report.PageRankHubs = topCentralityHubs(snap, pr, opts.TopN)
+	}
+
+	return report
+}
+
+// IncrementalComputeTopology returns prev unchanged when snap.Touched is
+// empty (Apply ran but nothing relevant changed), and otherwise falls back
+// to a full ComputeTopology. Unlike IncrementalComputeBridges, it can't
+// reuse prev's per-component work: component counts, the degree histogram,
+// and centrality scores are all graph-wide aggregates that a local patch
+// can shift in ways that don't decompose by component (e.g. PageRank mass
+// redistributes across the whole graph on any edge change). The only
+// incremental win available here is skipping the recompute entirely when
+// nothing was touched.
+//
+// A nil prev, or a snap whose Touched is nil (never went through Apply),
+// always does a full recompute.
+func IncrementalComputeTopology(ctx context.Context, snap *GraphSnapshot, prev *TopologyReport, opts TopologyOptions) *TopologyReport {
+	if prev == nil || snap.Touched == nil || len(snap.Touched) > 0 {
+		return ComputeTopology(ctx, snap, opts)
+	}
+	return prev
+}
+
+// topCentralityHubs sorts scores (keyed by node ID) descending and returns
+// the topN as CentralityHubNodes, breaking ties by ID for a deterministic
+// order.
+func topCentralityHubs(snap *GraphSnapshot, scores map[string]float64, topN int) []CentralityHubNode {
+	hubs := make([]CentralityHubNode, 0, len(scores))
+	for id, score := range scores {
+		hubs = append(hubs, CentralityHubNode{ID: id, Title: snap.Nodes[id].Title, Score: score})
+	}
+	sort.Slice(hubs, func(i, j int) bool {
+		if hubs[i].Score != hubs[j].Score {
+			return hubs[i].Score > hubs[j].Score
+		}
+		return hubs[i].ID < hubs[j].ID
+	})
+	if len(hubs) > topN {
+		hubs = hubs[:topN]
+	}
+	return hubs
+}
+
+// computeBetweennessSampled approximates betweenness centrality by running
+// Brandes' single-source pass (the inner loop of computeBetweenness) from k
+// random sources instead of every node, then scaling the result by N/k — the
+// standard estimator for sampled betweenness, trading accuracy for an O(k*E)
+// instead of O(N*E) pass.
+func computeBetweennessSampled(snap *GraphSnapshot, k int) map[string]float64 {
+	betweenness := make(map[string]float64, len(snap.Nodes))
+	for id := range snap.Nodes {
+		betweenness[id] = 0
+	}
+
+	ids := snap.NodeIDs()
+	n := len(ids)
+	if n == 0 {
+		return betweenness
+	}
+
+	rng := rand.New(rand.NewSource(1))
+	perm := rng.Perm(n)
+	sources := make([]string, k)
+	for i := 0; i < k; i++ {
+		sources[i] = ids[perm[i]]
+	}
+
+	for _, s := range sources {
+		brandesSingleSource(snap, s, betweenness)
+	}
+
+	// Scale the k-source sum up to an estimate of the full n-source sum, then
+	// halve for the same undirected double-counting correction
+	// computeBetweenness applies.
+	scale := float64(n) / float64(k) / 2
+	for id := range betweenness {
+		betweenness[id] *= scale
 	}
+	return betweenness
 }
 
 func defaultHistogram() []DegreeBucket {
@@ -76,3 +76,83 @@ func (uf *UnionFind) Components() [][]string {
 	}
 	return result
 }
+
+// Size returns the size of the component containing id.
+func (uf *UnionFind) Size(id string) int {
+	return uf.size[uf.Find(id)]
+}
+
+// Add registers id as its own singleton component if it isn't tracked yet.
+// No-op if id is already present, so it's safe to call on every incremental
+// edge insert without checking membership first.
+func (uf *UnionFind) Add(id string) {
+	if _, ok := uf.parent[id]; ok {
+		return
+	}
+	uf.parent[id] = id
+	uf.rank[id] = 0
+	uf.size[id] = 1
+}
+
+// UnionFindEntry is one element's raw (id, parent, rank, size) state, used
+// to persist and restore a forest across process restarts.
+type UnionFindEntry struct {
+	ID     string
+	Parent string
+	Rank   int
+	Size   int
+}
+
+// Entries returns every tracked element's raw state for persistence.
+func (uf *UnionFind) Entries() []UnionFindEntry {
+	entries := make([]UnionFindEntry, 0, len(uf.parent))
+	for id, parent := range uf.parent {
+		entries = append(entries, UnionFindEntry{ID: id, Parent: parent, Rank: uf.rank[id], Size: uf.size[id]})
+	}
+	return entries
+}
+
+// RestoreUnionFind rebuilds a UnionFind from a prior Entries() snapshot.
+func RestoreUnionFind(entries []UnionFindEntry) *UnionFind {
+	uf := &UnionFind{
+		parent: make(map[string]string, len(entries)),
+		rank:   make(map[string]int, len(entries)),
+		size:   make(map[string]int, len(entries)),
+	}
+	for _, e := range entries {
+		uf.parent[e.ID] = e.Parent
+		uf.rank[e.ID] = e.Rank
+		uf.size[e.ID] = e.Size
+	}
+	return uf
+}
+
+// ReplaceComponent swaps the entries for ids with a freshly recomputed
+// sub-forest. Used when a deletion invalidates part of the forest that
+// Union alone can't repair, since union-find has no Split operation: the
+// caller rebuilds connectivity for the affected ids from scratch into sub,
+// and ReplaceComponent splices the result back in.
+func (uf *UnionFind) ReplaceComponent(ids []string, sub *UnionFind) {
+	groups := make(map[string][]string)
+	for _, id := range ids {
+		root := sub.Find(id)
+		groups[root] = append(groups[root], id)
+	}
+	for _, id := range ids {
+		delete(uf.parent, id)
+		delete(uf.rank, id)
+		delete(uf.size, id)
+	}
+	for root, members := range groups {
+		uf.parent[root] = root
+		uf.rank[root] = 0
+		uf.size[root] = len(members)
+		for _, id := range members {
+			if id == root {
+				continue
+			}
+			uf.parent[id] = root
+			uf.rank[id] = 0
+		}
+	}
+}
@@ -0,0 +1,55 @@
+package graph
+
+import "testing"
+
+func TestUnionFind_AddIsIdempotent(t *testing.T) {
+	uf := NewUnionFind(nil)
+	uf.Add("a")
+	uf.Add("a")
+	if uf.Find("a") != "a" {
+		t.Errorf("Find(a) = %s, want a", uf.Find("a"))
+	}
+	if uf.Size("a") != 1 {
+		t.Errorf("Size(a) = %d, want 1", uf.Size("a"))
+	}
+}
+
+func TestUnionFind_EntriesRoundTrip(t *testing.T) {
+	uf := NewUnionFind([]string{"a", "b", "c"})
+	uf.Union("a", "b")
+	uf.Union("b", "c")
+
+	restored := RestoreUnionFind(uf.Entries())
+	if restored.Find("a") != restored.Find("c") {
+		t.Errorf("restored forest lost the a-c connection")
+	}
+	if restored.Size(restored.Find("a")) != 3 {
+		t.Errorf("restored component size = %d, want 3", restored.Size(restored.Find("a")))
+	}
+}
+
+func TestUnionFind_ReplaceComponent(t *testing.T) {
+	uf := NewUnionFind([]string{"a", "b", "c", "d"})
+	uf.Union("a", "b")
+	uf.Union("b", "c")
+	uf.Union("c", "d")
+	root := uf.Find("a")
+
+	// Recompute connectivity for {a,b,c,d} as if the b-c edge had been
+	// deleted and no other path survived: a-b and c-d split into two.
+	sub := NewUnionFind([]string{"a", "b", "c", "d"})
+	sub.Union("a", "b")
+	sub.Union("c", "d")
+
+	uf.ReplaceComponent([]string{"a", "b", "c", "d"}, sub)
+
+	if uf.Find("a") != uf.Find("b") {
+		t.Errorf("a and b should still be connected after replace")
+	}
+	if uf.Find("c") != uf.Find("d") {
+		t.Errorf("c and d should still be connected after replace")
+	}
+	if uf.Find("a") == uf.Find("c") {
+		t.Errorf("a and c should have split into separate components, both mapped to %s", root)
+	}
+}
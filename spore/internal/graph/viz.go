@@ -0,0 +1,205 @@
+package graph
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// RenderOptions configures RenderDOT/RenderHTMLZip's styling and scope.
+type RenderOptions struct {
+	// HubThreshold, if > 0, enlarges and bolds any node whose undirected
+	// degree is at least this many. 0 disables hub sizing entirely.
+	HubThreshold int
+
+	// ClusterBy selects how nodes are grouped into DOT subgraphs: "region"
+	// (the default, using snap.Regions) or "community" (using
+	// Communities.Assignments). Any other value, including "", disables
+	// clustering.
+	ClusterBy string
+
+	// Communities supplies community assignments for ClusterBy ==
+	// "community"; ignored otherwise.
+	Communities *CommunityReport
+
+	// Staleness, if set, colors stale nodes (StalenessReport.StaleNodes)
+	// orange independent of ClusterBy/Communities.
+	Staleness *StalenessReport
+
+	// Focus, if non-empty, restricts rendering to Focus and the nodes
+	// within FocusHops of it (undirected). Focus must name an existing node.
+	Focus     string
+	FocusHops int
+}
+
+// RenderDOT renders snap as Graphviz DOT source. overlay, if non-nil, styles
+// its ArticulationPoints red and its BridgeEdges dashed red -- the
+// "structural fragility" view from ComputeBridges. opts.ClusterBy groups
+// nodes into subgraphs and opts.Staleness/opts.HubThreshold further style
+// individual nodes; all are independent and composable.
+func RenderDOT(snap *GraphSnapshot, overlay *BridgeReport, opts RenderOptions) ([]byte, error) {
+	ids := snap.NodeIDs()
+	if opts.Focus != "" {
+		if _, ok := snap.Nodes[opts.Focus]; !ok {
+			return nil, fmt.Errorf("focus node %q not found in graph", opts.Focus)
+		}
+		ids = khopNeighborhood(snap, opts.Focus, opts.FocusHops)
+	}
+	included := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		included[id] = true
+	}
+
+	apSet := make(map[string]bool)
+	bridgeSet := make(map[string]bool)
+	if overlay != nil {
+		for _, ap := range overlay.ArticulationPoints {
+			apSet[ap.ID] = true
+		}
+		for _, be := range overlay.BridgeEdges {
+			bridgeSet[undirectedKey(be.SourceID, be.TargetID)] = true
+		}
+	}
+	staleSet := make(map[string]bool)
+	if opts.Staleness != nil {
+		for _, sn := range opts.Staleness.StaleNodes {
+			staleSet[sn.ID] = true
+		}
+	}
+	communityOf := make(map[string]int)
+	if opts.ClusterBy == "community" && opts.Communities != nil {
+		for _, a := range opts.Communities.Assignments {
+			communityOf[a.ID] = a.Community
+		}
+	}
+
+	clusterOf := func(id string) string {
+		switch opts.ClusterBy {
+		case "community":
+			if c, ok := communityOf[id]; ok {
+				return fmt.Sprintf("community %d", c)
+			}
+			return ""
+		case "region":
+			return snap.Regions[id]
+		default:
+			return ""
+		}
+	}
+
+	clusters := make(map[string][]string)
+	for _, id := range ids {
+		key := clusterOf(id)
+		clusters[key] = append(clusters[key], id)
+	}
+	var clusterKeys []string
+	for k := range clusters {
+		clusterKeys = append(clusterKeys, k)
+	}
+	sort.Strings(clusterKeys)
+
+	var buf bytes.Buffer
+	buf.WriteString("digraph spore {\n  rankdir=LR;\n  node [style=filled, fontname=\"Helvetica\", fillcolor=lightblue];\n\n")
+
+	clusterIdx := 0
+	for _, key := range clusterKeys {
+		members := clusters[key]
+		sort.Strings(members)
+		clustered := key != ""
+		if clustered {
+			fmt.Fprintf(&buf, "  subgraph cluster_%d {\n    label=%q;\n    color=gray;\n", clusterIdx, key)
+			clusterIdx++
+		}
+		for _, id := range members {
+			writeDOTNode(&buf, snap, id, len(snap.Adj[id]), apSet[id], staleSet[id], opts.HubThreshold, clustered)
+		}
+		if clustered {
+			buf.WriteString("  }\n")
+		}
+	}
+
+	buf.WriteString("\n")
+	seenEdges := make(map[string]bool)
+	for _, e := range snap.Edges {
+		if !included[e.Source] || !included[e.Target] {
+			continue
+		}
+		if seenEdges[e.ID] {
+			continue
+		}
+		seenEdges[e.ID] = true
+		style := ""
+		if bridgeSet[undirectedKey(e.Source, e.Target)] {
+			style = ", style=dashed, color=red, penwidth=2"
+		}
+		fmt.Fprintf(&buf, "  %q -> %q [label=%q%s];\n", e.Source, e.Target, e.EdgeType, style)
+	}
+	buf.WriteString("}\n")
+	return buf.Bytes(), nil
+}
+
+func writeDOTNode(buf *bytes.Buffer, snap *GraphSnapshot, id string, degree int, isAP, isStale bool, hubThreshold int, indent bool) {
+	node := snap.Nodes[id]
+	title := id
+	if node != nil && node.Title != "" {
+		title = node.Title
+	}
+
+	fillColor := "lightblue"
+	switch {
+	case isAP:
+		fillColor = "red"
+	case isStale:
+		fillColor = "orange"
+	}
+
+	extra := ""
+	if hubThreshold > 0 && degree >= hubThreshold {
+		scale := 1.0 + float64(degree-hubThreshold)/float64(hubThreshold)
+		extra = fmt.Sprintf(", width=%.2f, height=%.2f, fontsize=16", 0.75*scale, 0.5*scale)
+	}
+
+	prefix := "  "
+	if indent {
+		prefix = "    "
+	}
+	fmt.Fprintf(buf, "%s%q [label=%q, fillcolor=%q%s];\n", prefix, id, title, fillColor, extra)
+}
+
+// undirectedKey returns a canonical key for an undirected (a, b) pair,
+// independent of argument order.
+func undirectedKey(a, b string) string {
+	if a > b {
+		a, b = b, a
+	}
+	return a + "\x00" + b
+}
+
+// khopNeighborhood returns focus and every node within hops undirected hops
+// of it (BFS over snap.Adj), in NodeIDs()-sorted order. hops <= 0 returns
+// just focus itself.
+func khopNeighborhood(snap *GraphSnapshot, focus string, hops int) []string {
+	visited := map[string]bool{focus: true}
+	frontier := []string{focus}
+	for h := 0; h < hops; h++ {
+		var next []string
+		for _, id := range frontier {
+			for _, nb := range snap.Adj[id] {
+				if !visited[nb] {
+					visited[nb] = true
+					next = append(next, nb)
+				}
+			}
+		}
+		if len(next) == 0 {
+			break
+		}
+		frontier = next
+	}
+	ids := make([]string, 0, len(visited))
+	for id := range visited {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
@@ -0,0 +1,85 @@
+package graph
+
+import (
+	"archive/zip"
+	"bytes"
+	"fmt"
+	"os/exec"
+)
+
+// RenderSVG shells out to Graphviz's `dot` binary to rasterize dotSource
+// (as produced by RenderDOT) to SVG. Unlike the mycelica-cli shellouts in
+// internal/db/writes.go, there's no bundled fallback here -- dot is a
+// third-party tool the user installs separately, so a missing binary
+// surfaces as a plain "not found" error rather than a silent no-op.
+func RenderSVG(dotSource []byte) ([]byte, error) {
+	path, err := exec.LookPath("dot")
+	if err != nil {
+		return nil, fmt.Errorf("graphviz 'dot' binary not found in PATH: %w", err)
+	}
+
+	cmd := exec.Command(path, "-Tsvg")
+	cmd.Stdin = bytes.NewReader(dotSource)
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("rendering SVG via dot: %w (stderr: %s)", err, stderr.String())
+	}
+	return out, nil
+}
+
+// RenderHTMLZip renders snap to DOT, rasterizes it to SVG via RenderSVG, and
+// packages graph.dot, graph.svg, and a small index.html (that just embeds
+// the SVG inline) into a self-contained zip archive, so the whole bundle
+// can be handed to someone without a Graphviz install of their own.
+func RenderHTMLZip(snap *GraphSnapshot, overlay *BridgeReport, opts RenderOptions) ([]byte, error) {
+	dot, err := RenderDOT(snap, overlay, opts)
+	if err != nil {
+		return nil, fmt.Errorf("rendering DOT: %w", err)
+	}
+	svg, err := RenderSVG(dot)
+	if err != nil {
+		return nil, fmt.Errorf("rendering SVG: %w", err)
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+
+	if err := writeZipFile(zw, "graph.dot", dot); err != nil {
+		return nil, err
+	}
+	if err := writeZipFile(zw, "graph.svg", svg); err != nil {
+		return nil, err
+	}
+	if err := writeZipFile(zw, "index.html", []byte(buildHTMLIndex(svg))); err != nil {
+		return nil, err
+	}
+
+	if err := zw.Close(); err != nil {
+		return nil, fmt.Errorf("closing zip archive: %w", err)
+	}
+	return buf.Bytes(), nil
+}
+
+func writeZipFile(zw *zip.Writer, name string, content []byte) error {
+	w, err := zw.Create(name)
+	if err != nil {
+		return fmt.Errorf("adding %s to zip: %w", name, err)
+	}
+	if _, err := w.Write(content); err != nil {
+		return fmt.Errorf("writing %s to zip: %w", name, err)
+	}
+	return nil
+}
+
+func buildHTMLIndex(svg []byte) string {
+	return fmt.Sprintf(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>spore graph</title></head>
+<body>
+%s
+</body>
+</html>
+`, svg)
+}
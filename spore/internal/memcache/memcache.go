@@ -0,0 +1,208 @@
+// Package memcache provides a process-wide, memory-bounded LRU cache for the
+// anchor/context/lesson lookups in internal/orchestrate's task-file builder.
+// Those lookups re-run the same embedding and FTS searches on every bounce of
+// a run (and across concurrent runs against the same database), so caching
+// them by (query, task node, config) avoids redundant work without pinning an
+// unbounded amount of memory.
+package memcache
+
+import (
+	"bufio"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// envMemoryLimit overrides the default cache cap. Its value is a number of
+// GiB, e.g. "2" or "1.5".
+const envMemoryLimit = "MYCELICA_MEMORYLIMIT"
+
+// defaultMemoryFraction is the portion of total system RAM the cache is
+// allowed to occupy when envMemoryLimit isn't set.
+const defaultMemoryFraction = 4
+
+// fallbackCapBytes is used when total system memory can't be determined
+// (e.g. /proc/meminfo is unavailable on this platform).
+const fallbackCapBytes = 256 * 1024 * 1024
+
+// Cache is an LRU keyed by opaque string keys (build one with Key), bounded
+// by total byte cost rather than entry count. Each entry also records the
+// node IDs it was derived from, so Invalidate can purge every entry touched
+// by a node without the caller tracking keys itself. Safe for concurrent use.
+type Cache struct {
+	mu       sync.Mutex
+	maxBytes int64
+	curBytes int64
+	ll       *list.List
+	items    map[string]*list.Element
+	byNode   map[string]map[string]struct{}
+}
+
+type entry struct {
+	key     string
+	value   any
+	size    int64
+	nodeIDs []string
+}
+
+// New creates a Cache capped at maxBytes of tracked entry size. A maxBytes
+// of 0 or less disables caching: Set becomes a no-op and Get always misses.
+func New(maxBytes int64) *Cache {
+	return &Cache{
+		maxBytes: maxBytes,
+		ll:       list.New(),
+		items:    make(map[string]*list.Element),
+		byNode:   make(map[string]map[string]struct{}),
+	}
+}
+
+// Get returns the cached value for key, promoting it to most-recently-used.
+func (c *Cache) Get(key string) (any, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return el.Value.(*entry).value, true
+}
+
+// Set stores value under key at the given byte cost, associating it with
+// nodeIDs for later invalidation. It evicts least-recently-used entries
+// until the cache is back under its byte budget.
+func (c *Cache) Set(key string, value any, size int64, nodeIDs []string) {
+	if c.maxBytes <= 0 || size > c.maxBytes {
+		return
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if el, ok := c.items[key]; ok {
+		c.removeElementLocked(el)
+	}
+
+	e := &entry{key: key, value: value, size: size, nodeIDs: nodeIDs}
+	el := c.ll.PushFront(e)
+	c.items[key] = el
+	c.curBytes += size
+	for _, id := range nodeIDs {
+		set, ok := c.byNode[id]
+		if !ok {
+			set = make(map[string]struct{})
+			c.byNode[id] = set
+		}
+		set[key] = struct{}{}
+	}
+
+	for c.curBytes > c.maxBytes {
+		back := c.ll.Back()
+		if back == nil {
+			break
+		}
+		c.removeElementLocked(back)
+	}
+}
+
+// Invalidate drops every cached entry derived from nodeID, e.g. because an
+// ingest/update path just changed that node's UpdatedAt.
+func (c *Cache) Invalidate(nodeID string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for key := range c.byNode[nodeID] {
+		if el, ok := c.items[key]; ok {
+			c.removeElementLocked(el)
+		}
+	}
+}
+
+// removeElementLocked removes el from the cache. c.mu must be held.
+func (c *Cache) removeElementLocked(el *list.Element) {
+	e := el.Value.(*entry)
+	c.ll.Remove(el)
+	delete(c.items, e.key)
+	c.curBytes -= e.size
+	for _, id := range e.nodeIDs {
+		set := c.byNode[id]
+		delete(set, e.key)
+		if len(set) == 0 {
+			delete(c.byNode, id)
+		}
+	}
+}
+
+// Key derives an opaque cache key from a (query hash, task-node-id,
+// config hash) tuple, plus whatever other parts the caller wants folded in
+// (e.g. a cache namespace like "anchors" or "context").
+func Key(parts ...string) string {
+	h := sha256.New()
+	for _, p := range parts {
+		h.Write([]byte(p))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+var (
+	defaultOnce  sync.Once
+	defaultCache *Cache
+)
+
+// Default returns the process-wide cache, sized against total system memory
+// (or envMemoryLimit, if set) the first time it's called.
+func Default() *Cache {
+	defaultOnce.Do(func() {
+		defaultCache = New(capFromEnv())
+	})
+	return defaultCache
+}
+
+func capFromEnv() int64 {
+	if v := os.Getenv(envMemoryLimit); v != "" {
+		if gib, err := strconv.ParseFloat(v, 64); err == nil && gib > 0 {
+			return int64(gib * 1024 * 1024 * 1024)
+		}
+	}
+	total, err := totalSystemMemoryBytes()
+	if err != nil || total == 0 {
+		return fallbackCapBytes
+	}
+	return int64(total) / defaultMemoryFraction
+}
+
+// totalSystemMemoryBytes reads MemTotal out of /proc/meminfo. Returns an
+// error on platforms that don't have it, in which case callers fall back to
+// fallbackCapBytes.
+func totalSystemMemoryBytes() (uint64, error) {
+	f, err := os.Open("/proc/meminfo")
+	if err != nil {
+		return 0, err
+	}
+	defer f.Close()
+
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := scanner.Text()
+		if !strings.HasPrefix(line, "MemTotal:") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return 0, fmt.Errorf("unexpected MemTotal line: %q", line)
+		}
+		kb, err := strconv.ParseUint(fields[1], 10, 64)
+		if err != nil {
+			return 0, fmt.Errorf("parsing MemTotal: %w", err)
+		}
+		return kb * 1024, nil
+	}
+	return 0, fmt.Errorf("MemTotal not found in /proc/meminfo")
+}
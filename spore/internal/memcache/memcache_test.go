@@ -0,0 +1,115 @@
+package memcache
+
+import "testing"
+
+func TestCache_SetGet(t *testing.T) {
+	c := New(1024)
+	c.Set("k", "v", 10, nil)
+
+	v, ok := c.Get("k")
+	if !ok || v != "v" {
+		t.Fatalf("Get = (%v, %v), want (\"v\", true)", v, ok)
+	}
+}
+
+func TestCache_MissingKey(t *testing.T) {
+	c := New(1024)
+	if _, ok := c.Get("missing"); ok {
+		t.Fatalf("expected a miss for an absent key")
+	}
+}
+
+func TestCache_EvictsLeastRecentlyUsed(t *testing.T) {
+	c := New(30)
+	c.Set("a", 1, 10, nil)
+	c.Set("b", 2, 10, nil)
+	c.Set("c", 2, 10, nil)
+
+	// Touch "a" so "b" becomes the least-recently-used entry.
+	c.Get("a")
+	c.Set("d", 4, 10, nil)
+
+	if _, ok := c.Get("b"); ok {
+		t.Errorf("expected \"b\" to have been evicted")
+	}
+	if _, ok := c.Get("a"); !ok {
+		t.Errorf("expected \"a\" to survive eviction after being touched")
+	}
+}
+
+func TestCache_OversizedEntryIsRejected(t *testing.T) {
+	c := New(10)
+	c.Set("big", "v", 100, nil)
+
+	if _, ok := c.Get("big"); ok {
+		t.Errorf("expected an entry larger than the cache budget to be rejected")
+	}
+}
+
+func TestCache_ZeroCapacityDisablesCaching(t *testing.T) {
+	c := New(0)
+	c.Set("k", "v", 1, nil)
+
+	if _, ok := c.Get("k"); ok {
+		t.Errorf("expected a zero-capacity cache to never store entries")
+	}
+}
+
+func TestCache_InvalidateByNodeID(t *testing.T) {
+	c := New(1024)
+	c.Set("anchors:1", []string{"a1"}, 10, []string{"node-1", "node-2"})
+	c.Set("context:1", []string{"c1"}, 10, []string{"node-2"})
+	c.Set("unrelated", "x", 10, []string{"node-3"})
+
+	c.Invalidate("node-2")
+
+	if _, ok := c.Get("anchors:1"); ok {
+		t.Errorf("expected anchors:1 to be invalidated via node-2")
+	}
+	if _, ok := c.Get("context:1"); ok {
+		t.Errorf("expected context:1 to be invalidated via node-2")
+	}
+	if _, ok := c.Get("unrelated"); !ok {
+		t.Errorf("expected unrelated (referencing node-3) to survive invalidating node-2")
+	}
+}
+
+func TestCache_InvalidateUnknownNodeIsNoop(t *testing.T) {
+	c := New(1024)
+	c.Set("k", "v", 10, []string{"node-1"})
+
+	c.Invalidate("node-does-not-exist")
+
+	if _, ok := c.Get("k"); !ok {
+		t.Errorf("expected invalidating an unreferenced node to be a no-op")
+	}
+}
+
+func TestKey_DeterministicAndDistinct(t *testing.T) {
+	k1 := Key("anchors", "fix the bug", "task-1", "cfg")
+	k2 := Key("anchors", "fix the bug", "task-1", "cfg")
+	if k1 != k2 {
+		t.Errorf("expected Key to be deterministic for identical inputs")
+	}
+
+	k3 := Key("anchors", "fix the other bug", "task-1", "cfg")
+	if k1 == k3 {
+		t.Errorf("expected different query text to produce a different key")
+	}
+}
+
+func TestCapFromEnv_RespectsOverride(t *testing.T) {
+	t.Setenv(envMemoryLimit, "1")
+	got := capFromEnv()
+	want := int64(1024 * 1024 * 1024)
+	if got != want {
+		t.Errorf("capFromEnv() = %d, want %d (1 GiB)", got, want)
+	}
+}
+
+func TestCapFromEnv_IgnoresGarbageOverride(t *testing.T) {
+	t.Setenv(envMemoryLimit, "not-a-number")
+	if got := capFromEnv(); got <= 0 {
+		t.Errorf("capFromEnv() = %d, want a positive fallback cap", got)
+	}
+}
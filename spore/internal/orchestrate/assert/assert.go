@@ -0,0 +1,258 @@
+// Package assert implements a small Venom-style declarative assertion DSL
+// for orchestrate task files: lines like
+//
+//	ShouldContainSubstring result.stdout "foo"
+//	ShouldEqual result.exit_code 0
+//	ShouldMatch result.summary "^Fixed"
+//	ShouldExistEdge type=supports target=<node>
+//
+// are parsed into Assertion values, resolved against a RunContext built from
+// a pipeline run, and checked with a Comparator.
+package assert
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+
+	"mycelica/spore/internal/db"
+)
+
+// Assertion is one parsed assertion: a comparator name, the left-hand path
+// to resolve against a RunContext, and the comparator's remaining arguments.
+type Assertion struct {
+	Raw  string   // original line, for error messages
+	Func string   // comparator name, e.g. "ShouldContainSubstring"
+	Path string   // left-hand path, e.g. "result.stdout"
+	Args []string // remaining tokens
+}
+
+// AssertionApplied is the outcome of evaluating one Assertion against a RunContext.
+type AssertionApplied struct {
+	Assertion string `json:"assertion"`
+	IsOK      bool   `json:"is_ok"`
+	Actual    string `json:"actual,omitempty"`
+	Message   string `json:"message,omitempty"`
+}
+
+// RunContext is the evaluation context built from a pipeline run: agent
+// outputs, exit codes, and graph state touched by the run.
+type RunContext struct {
+	Stdout       string
+	Stderr       string
+	ExitCode     int
+	Summary      string
+	DB           *db.DB
+	ImplNodeID   string
+	CreatedNodes []string
+	CreatedEdges []string
+}
+
+// Comparator checks actual (resolved from Path) against args and reports
+// whether the assertion holds.
+type Comparator func(ctx *RunContext, actual string, args []string) (bool, string, error)
+
+var registry = map[string]Comparator{
+	"ShouldContainSubstring": shouldContainSubstring,
+	"ShouldEqual":            shouldEqual,
+	"ShouldMatch":            shouldMatch,
+	"ShouldExistEdge":        shouldExistEdge,
+}
+
+// Register adds or overrides a named comparator, letting callers plug in
+// domain-specific checks beyond the built-ins.
+func Register(name string, fn Comparator) {
+	registry[name] = fn
+}
+
+// ParseAssertionsYAML parses a YAML list of assertion-expression strings
+// (the `assertions:` block's value) into Assertion values.
+func ParseAssertionsYAML(raw []byte) ([]Assertion, error) {
+	var lines []string
+	if err := yaml.Unmarshal(raw, &lines); err != nil {
+		return nil, fmt.Errorf("parsing assertions block: %w", err)
+	}
+	assertions := make([]Assertion, 0, len(lines))
+	for _, line := range lines {
+		a, err := ParseAssertionLine(line)
+		if err != nil {
+			return nil, err
+		}
+		assertions = append(assertions, a)
+	}
+	return assertions, nil
+}
+
+// ParseAssertionLine tokenizes one assertion expression, respecting
+// double-quoted substrings: `Func path "quoted arg" bareArg`.
+func ParseAssertionLine(line string) (Assertion, error) {
+	tokens := tokenize(line)
+	if len(tokens) < 2 {
+		return Assertion{}, fmt.Errorf("malformed assertion %q: need at least a function and a path", line)
+	}
+	return Assertion{
+		Raw:  line,
+		Func: tokens[0],
+		Path: tokens[1],
+		Args: tokens[2:],
+	}, nil
+}
+
+func tokenize(line string) []string {
+	var tokens []string
+	var cur strings.Builder
+	inQuotes := false
+	flush := func() {
+		if cur.Len() > 0 {
+			tokens = append(tokens, cur.String())
+			cur.Reset()
+		}
+	}
+	for _, r := range line {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ' ' && !inQuotes:
+			flush()
+		default:
+			cur.WriteRune(r)
+		}
+	}
+	flush()
+	return tokens
+}
+
+// Resolve looks up a dotted path (e.g. "result.stdout") against ctx.
+func Resolve(path string, ctx *RunContext) (string, error) {
+	trimmed := strings.TrimPrefix(path, "result.")
+	switch trimmed {
+	case "stdout":
+		return ctx.Stdout, nil
+	case "stderr":
+		return ctx.Stderr, nil
+	case "exit_code":
+		return strconv.Itoa(ctx.ExitCode), nil
+	case "summary":
+		return ctx.Summary, nil
+	default:
+		return "", fmt.Errorf("unresolvable path %q", path)
+	}
+}
+
+// Apply evaluates every assertion against ctx and returns the per-assertion outcomes.
+func Apply(assertions []Assertion, ctx *RunContext) []AssertionApplied {
+	applied := make([]AssertionApplied, 0, len(assertions))
+	for _, a := range assertions {
+		applied = append(applied, apply1(a, ctx))
+	}
+	return applied
+}
+
+func apply1(a Assertion, ctx *RunContext) AssertionApplied {
+	cmp, ok := registry[a.Func]
+	if !ok {
+		return AssertionApplied{Assertion: a.Raw, IsOK: false, Message: fmt.Sprintf("unknown comparator %q", a.Func)}
+	}
+	actual, err := Resolve(a.Path, ctx)
+	if err != nil {
+		return AssertionApplied{Assertion: a.Raw, IsOK: false, Message: err.Error()}
+	}
+	ok2, msg, err := cmp(ctx, actual, a.Args)
+	if err != nil {
+		return AssertionApplied{Assertion: a.Raw, IsOK: false, Actual: actual, Message: err.Error()}
+	}
+	return AssertionApplied{Assertion: a.Raw, IsOK: ok2, Actual: actual, Message: msg}
+}
+
+// AnyFailed reports whether any assertion in applied failed.
+func AnyFailed(applied []AssertionApplied) bool {
+	for _, a := range applied {
+		if !a.IsOK {
+			return true
+		}
+	}
+	return false
+}
+
+// FailureReason synthesizes a reason string from the failed assertions, for
+// feeding back into a downgraded Verdict.
+func FailureReason(applied []AssertionApplied) string {
+	var failed []string
+	for _, a := range applied {
+		if !a.IsOK {
+			failed = append(failed, a.Assertion)
+		}
+	}
+	if len(failed) == 0 {
+		return ""
+	}
+	return "assertion(s) failed: " + strings.Join(failed, "; ")
+}
+
+func shouldContainSubstring(ctx *RunContext, actual string, args []string) (bool, string, error) {
+	if len(args) < 1 {
+		return false, "", fmt.Errorf("ShouldContainSubstring requires 1 argument")
+	}
+	ok := strings.Contains(actual, args[0])
+	if ok {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("expected substring %q", args[0]), nil
+}
+
+func shouldEqual(ctx *RunContext, actual string, args []string) (bool, string, error) {
+	if len(args) < 1 {
+		return false, "", fmt.Errorf("ShouldEqual requires 1 argument")
+	}
+	if actual == args[0] {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("expected %q, got %q", args[0], actual), nil
+}
+
+func shouldMatch(ctx *RunContext, actual string, args []string) (bool, string, error) {
+	if len(args) < 1 {
+		return false, "", fmt.Errorf("ShouldMatch requires 1 argument")
+	}
+	re, err := regexp.Compile(args[0])
+	if err != nil {
+		return false, "", fmt.Errorf("invalid pattern %q: %w", args[0], err)
+	}
+	if re.MatchString(actual) {
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("expected match of %q", args[0]), nil
+}
+
+// shouldExistEdge checks, via ctx.DB, that an edge of the declared type
+// exists touching ctx.ImplNodeID. Args are "key=value" pairs; recognized
+// keys are "type" (edge_type) and "target" (target node ID).
+func shouldExistEdge(ctx *RunContext, actual string, args []string) (bool, string, error) {
+	if ctx.DB == nil || ctx.ImplNodeID == "" {
+		return false, "no database/impl node available", nil
+	}
+	want := map[string]string{}
+	for _, arg := range args {
+		kv := strings.SplitN(arg, "=", 2)
+		if len(kv) == 2 {
+			want[kv[0]] = kv[1]
+		}
+	}
+	edges, err := ctx.DB.GetEdgesForNode(ctx.ImplNodeID)
+	if err != nil {
+		return false, "", err
+	}
+	for _, e := range edges {
+		if t, ok := want["type"]; ok && e.EdgeType != t {
+			continue
+		}
+		if target, ok := want["target"]; ok && e.TargetID != target {
+			continue
+		}
+		return true, "", nil
+	}
+	return false, fmt.Sprintf("no matching edge found for %v", want), nil
+}
@@ -0,0 +1,43 @@
+package orchestrate
+
+import (
+	"strings"
+
+	"mycelica/spore/internal/orchestrate/assert"
+)
+
+// assertionsMarker is the line that introduces a task's trailing YAML
+// assertions block, e.g.:
+//
+//	Fix the off-by-one in the paginator.
+//	assertions:
+//	  - ShouldContainSubstring result.stdout "paginator"
+//	  - ShouldEqual result.exit_code 0
+const assertionsMarker = "assertions:"
+
+// ExtractTaskAssertions splits raw task text into the clean task description
+// and its optional assertions block (everything from a line that is exactly
+// "assertions:" onward, parsed as YAML). Returns raw unchanged with no
+// assertions if the marker isn't present.
+func ExtractTaskAssertions(raw string) (string, []assert.Assertion, error) {
+	lines := strings.Split(raw, "\n")
+	markerIdx := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == assertionsMarker {
+			markerIdx = i
+			break
+		}
+	}
+	if markerIdx < 0 {
+		return raw, nil, nil
+	}
+
+	task := strings.TrimRight(strings.Join(lines[:markerIdx], "\n"), "\n ")
+	block := strings.Join(lines[markerIdx+1:], "\n")
+
+	assertions, err := assert.ParseAssertionsYAML([]byte(block))
+	if err != nil {
+		return task, nil, err
+	}
+	return task, assertions, nil
+}
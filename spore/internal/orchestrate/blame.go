@@ -0,0 +1,341 @@
+package orchestrate
+
+import (
+	"fmt"
+	"strings"
+
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+
+	"mycelica/spore/internal/db"
+)
+
+// blameRunIDTrailer and blameAgentRoleTrailer are the commit message
+// trailers autoCommit writes (see loop.go) so BlameRun can map a commit back
+// to the orchestration run and agent role that produced it.
+//
+// blameTaskIDTrailer, blameTaskNodeTrailer, blameCostTrailer, and
+// blameDurationTrailer are additional per-task trailers autoCommit writes
+// so git log --grep and downstream tooling can reconstruct a loop run
+// without querying the graph. None of these are consulted by BlameRun
+// itself -- only commitTrailer's "Key: value" line format is shared.
+const (
+	blameRunIDTrailer     = "Mycelica-Run-ID"
+	blameAgentRoleTrailer = "Mycelica-Agent-Role"
+	blameTaskIDTrailer    = "Mycelica-Task-Id"
+	blameTaskNodeTrailer  = "Mycelica-Task-Node"
+	blameCostTrailer      = "Mycelica-Cost-USD"
+	blameDurationTrailer  = "Mycelica-Duration-Ms"
+)
+
+// blameLineTool is the MCP tool name BlameRun's results are meant to back.
+// The MCP server itself (invoked as "<cliBinary> mcp-server", see
+// WriteMCPConfig) lives outside this source tree, the same way
+// submitVerdictTool's server-side handler does; wiring blame_line up there
+// is out of scope here, but it should read from db.BlameForFile under this
+// exact tool name.
+const blameLineTool = "mcp__mycelica__blame_line"
+
+// LineAttribution is one line's authorship as computed by BlameRun: which
+// commit introduced it, and — when that commit carries the trailers
+// autoCommit writes — which orchestration run and agent role wrote it.
+type LineAttribution struct {
+	File      string `json:"file"`
+	Line      int    `json:"line"` // 1-indexed
+	RunID     string `json:"run_id,omitempty"`
+	AgentRole string `json:"agent_role,omitempty"`
+	CommitSHA string `json:"commit_sha"`
+}
+
+// BlameRun computes per-line authorship for every file runID's commit
+// touched, so an operator can ask "which agent wrote this bug" across a
+// whole session.
+//
+// It finds the commit carrying a Mycelica-Run-ID trailer matching runID
+// (see autoCommit), walks commit history backward from HEAD with
+// object.NewCommitPreorderIter, and for each commit diffs it against its
+// first parent with the unified diff encoder. A per-line owner vector,
+// initialized to HEAD's commit, is rewritten to the current commit's hash
+// for any line an addition hunk in that commit's diff covers and that isn't
+// already confirmed; a line stops being reconsidered once assigned.
+//
+// This is a practical approximation, not git-blame's full algorithm: line
+// positions are compared directly across each commit's own diff rather than
+// re-threaded through every intervening diff, so a line that drifted
+// position due to edits elsewhere in the file between an ancestor commit and
+// HEAD can be misattributed. Good enough for attributing the lines an
+// orchestration run just introduced, which is this function's actual job.
+func BlameRun(repoDir, runID string) ([]LineAttribution, error) {
+	repo, err := openRepo(repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("opening repo: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("resolving HEAD: %w", err)
+	}
+	headCommit, err := repo.CommitObject(head.Hash())
+	if err != nil {
+		return nil, fmt.Errorf("loading HEAD commit: %w", err)
+	}
+
+	runCommit, role, err := findRunCommit(headCommit, runID)
+	if err != nil {
+		return nil, err
+	}
+
+	files, err := filesChangedByCommit(runCommit)
+	if err != nil {
+		return nil, fmt.Errorf("diffing run commit against its parent: %w", err)
+	}
+
+	var attributions []LineAttribution
+	for _, file := range files {
+		owners, err := blameFile(headCommit, file)
+		if err != nil {
+			return nil, fmt.Errorf("blaming %s: %w", file, err)
+		}
+		for i, sha := range owners {
+			attr := LineAttribution{File: file, Line: i + 1, CommitSHA: sha}
+			if sha == runCommit.Hash.String() {
+				attr.RunID = runID
+				attr.AgentRole = role
+			}
+			attributions = append(attributions, attr)
+		}
+	}
+	return attributions, nil
+}
+
+// SaveBlame persists attributions (as returned by BlameRun) into the blame
+// table, so the blame_line MCP tool and ad-hoc operator queries can look
+// them up later without recomputing the git history walk.
+func SaveBlame(d *db.DB, attributions []LineAttribution) error {
+	lines := make([]db.BlameLine, len(attributions))
+	for i, a := range attributions {
+		lines[i] = db.BlameLine{
+			File:      a.File,
+			Line:      a.Line,
+			RunID:     a.RunID,
+			AgentRole: a.AgentRole,
+			CommitSHA: a.CommitSHA,
+		}
+	}
+	return d.SaveBlame(lines)
+}
+
+// findRunCommit walks history from head looking for the commit carrying a
+// Mycelica-Run-ID trailer equal to runID, returning it along with its
+// Mycelica-Agent-Role trailer (empty if absent).
+//
+// squashCommit (loop.go) concatenates one trailer block per task into a
+// single commit message, so a commit's message can carry several distinct
+// Mycelica-Run-ID lines, one per squashed task. commitTrailerBlocks splits
+// the message back into those per-task blocks so the one actually matching
+// runID is consulted, rather than whichever happens to come first.
+func findRunCommit(head *object.Commit, runID string) (*object.Commit, string, error) {
+	var found *object.Commit
+	var role string
+
+	iter := object.NewCommitPreorderIter(head, nil, nil)
+	err := iter.ForEach(func(c *object.Commit) error {
+		for _, block := range commitTrailerBlocks(c.Message) {
+			if blockTrailer(block, blameRunIDTrailer) != runID {
+				continue
+			}
+			found = c
+			role = blockTrailer(block, blameAgentRoleTrailer)
+			return storerErrStop
+		}
+		return nil
+	})
+	if err != nil && err != storerErrStop {
+		return nil, "", fmt.Errorf("walking commit history: %w", err)
+	}
+	if found == nil {
+		return nil, "", fmt.Errorf("no commit with %s: %s found in history", blameRunIDTrailer, runID)
+	}
+	return found, role, nil
+}
+
+// storerErrStop is a sentinel returned from commit-walk callbacks to end the
+// walk early once the commit being searched for is found.
+var storerErrStop = fmt.Errorf("stop")
+
+// commitTrailerBlocks splits a commit message into one block of lines per
+// Mycelica-Run-ID trailer it contains. autoCommit writes exactly one block
+// per commit; squashCommit writes one per squashed task, each starting with
+// its own Mycelica-Run-ID line (see commitTrailers). Lines before the first
+// Mycelica-Run-ID line (the subject and any free-form body prose) belong to
+// no block and are discarded.
+func commitTrailerBlocks(msg string) [][]string {
+	prefix := blameRunIDTrailer + ": "
+	var blocks [][]string
+	for _, line := range strings.Split(msg, "\n") {
+		if strings.HasPrefix(line, prefix) {
+			blocks = append(blocks, nil)
+		}
+		if blocks == nil {
+			continue
+		}
+		blocks[len(blocks)-1] = append(blocks[len(blocks)-1], line)
+	}
+	return blocks
+}
+
+// blockTrailer extracts a trailer of the form "Key: value" from one block
+// returned by commitTrailerBlocks.
+func blockTrailer(block []string, key string) string {
+	prefix := key + ": "
+	for _, line := range block {
+		if strings.HasPrefix(line, prefix) {
+			return strings.TrimSpace(strings.TrimPrefix(line, prefix))
+		}
+	}
+	return ""
+}
+
+// filesChangedByCommit lists the (post-commit) paths a commit's first-parent
+// diff touched, skipping files it deleted — there's nothing current to
+// blame for those.
+func filesChangedByCommit(c *object.Commit) ([]string, error) {
+	var parent *object.Commit
+	if c.NumParents() > 0 {
+		p, err := c.Parent(0)
+		if err != nil {
+			return nil, fmt.Errorf("loading first parent: %w", err)
+		}
+		parent = p
+	}
+
+	patch, err := commitPatch(parent, c)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []string
+	for _, fp := range patch.FilePatches() {
+		_, to := fp.Files()
+		if to == nil {
+			continue // deleted by this commit
+		}
+		files = append(files, to.Path())
+	}
+	return files, nil
+}
+
+// commitPatch diffs from's tree to to's tree, treating a nil from (a root
+// commit with no parent) as an empty tree so every line in to is an add.
+func commitPatch(from, to *object.Commit) (*object.Patch, error) {
+	if from == nil {
+		toTree, err := to.Tree()
+		if err != nil {
+			return nil, fmt.Errorf("loading tree: %w", err)
+		}
+		changes, err := object.DiffTree(nil, toTree)
+		if err != nil {
+			return nil, fmt.Errorf("diffing root commit against an empty tree: %w", err)
+		}
+		return changes.Patch()
+	}
+	return from.Patch(to)
+}
+
+// blameFile computes owner[i] = the commit hash that introduced line i+1 of
+// file as it reads at head, per BlameRun's documented algorithm.
+func blameFile(head *object.Commit, file string) ([]string, error) {
+	headFile, err := head.File(file)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s at HEAD: %w", file, err)
+	}
+	content, err := headFile.Contents()
+	if err != nil {
+		return nil, fmt.Errorf("reading contents of %s: %w", file, err)
+	}
+
+	lines := splitLines(content)
+	owner := make([]string, len(lines))
+	confirmed := make([]bool, len(lines))
+	headSHA := head.Hash.String()
+	for i := range owner {
+		owner[i] = headSHA
+	}
+
+	iter := object.NewCommitPreorderIter(head, nil, nil)
+	err = iter.ForEach(func(c *object.Commit) error {
+		var parent *object.Commit
+		if c.NumParents() > 0 {
+			p, err := c.Parent(0)
+			if err != nil {
+				return fmt.Errorf("loading first parent of %s: %w", c.Hash, err)
+			}
+			parent = p
+		}
+		patch, err := commitPatch(parent, c)
+		if err != nil {
+			return fmt.Errorf("diffing %s against its parent: %w", c.Hash, err)
+		}
+
+		for _, fp := range patch.FilePatches() {
+			_, to := fp.Files()
+			if to == nil || to.Path() != file {
+				continue
+			}
+			markAddedLines(fp.Chunks(), c.Hash.String(), owner, confirmed)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
+// markAddedLines walks a file patch's chunks in order, tracking the new-side
+// line cursor, and assigns commitSHA to any still-unconfirmed line an Add
+// chunk covers. Delete chunks don't advance the new-side cursor: they only
+// exist on the old side of the diff.
+func markAddedLines(chunks []fdiff.Chunk, commitSHA string, owner []string, confirmed []bool) {
+	cursor := 0
+	for _, chunk := range chunks {
+		n := countLines(chunk.Content())
+		switch chunk.Type() {
+		case fdiff.Add:
+			for i := cursor; i < cursor+n && i < len(owner); i++ {
+				if !confirmed[i] {
+					owner[i] = commitSHA
+					confirmed[i] = true
+				}
+			}
+			cursor += n
+		case fdiff.Equal:
+			cursor += n
+		}
+	}
+}
+
+// countLines counts the number of lines a chunk's content represents, the
+// same way chunkStats (diffpatch.go) does: \n count, plus one more if the
+// content doesn't end in a newline.
+func countLines(s string) int {
+	if len(s) == 0 {
+		return 0
+	}
+	n := strings.Count(s, "\n")
+	if s[len(s)-1] != '\n' {
+		n++
+	}
+	return n
+}
+
+// splitLines splits file content into lines the same way countLines counts
+// them, so blameFile's owner vector has exactly one slot per line Chunks()
+// reports.
+func splitLines(content string) []string {
+	if content == "" {
+		return nil
+	}
+	trimmed := strings.TrimSuffix(content, "\n")
+	return strings.Split(trimmed, "\n")
+}
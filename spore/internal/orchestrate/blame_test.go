@@ -0,0 +1,127 @@
+package orchestrate
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// commitWithTrailers writes content to path and commits it with the
+// Mycelica-Run-ID/Mycelica-Agent-Role trailers autoCommit writes, returning
+// the new commit's full hash.
+func commitWithTrailers(t *testing.T, repoDir, path, content, runID, role string) string {
+	t.Helper()
+	full := filepath.Join(repoDir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, out)
+		}
+		return string(out)
+	}
+	run("add", "-A")
+	msg := fmt.Sprintf("feat(loop): test change\n\n%s: %s\n%s: %s", blameRunIDTrailer, runID, blameAgentRoleTrailer, role)
+	run("commit", "-m", msg)
+	return strings.TrimSpace(run("rev-parse", "HEAD"))
+}
+
+func TestBlameRun_AttributesNewLinesToRun(t *testing.T) {
+	repoDir := initTestRepo(t)
+	runSHA := commitWithTrailers(t, repoDir, "base.txt", "base\nadded-by-run\n", "run-123", string(RoleCoder))
+
+	attributions, err := BlameRun(repoDir, "run-123")
+	if err != nil {
+		t.Fatalf("BlameRun: %v", err)
+	}
+	if len(attributions) != 2 {
+		t.Fatalf("expected 2 line attributions, got %d: %v", len(attributions), attributions)
+	}
+
+	byLine := make(map[int]LineAttribution)
+	for _, a := range attributions {
+		byLine[a.Line] = a
+	}
+
+	if got := byLine[1]; got.RunID != "" || got.CommitSHA == runSHA {
+		t.Errorf("line 1 (unchanged from initTestRepo's base commit) should not be attributed to the new run, got %+v", got)
+	}
+	if got := byLine[2]; got.RunID != "run-123" || got.AgentRole != string(RoleCoder) || got.CommitSHA != runSHA {
+		t.Errorf("line 2 (added by the run) should be attributed to run-123/coder/%s, got %+v", runSHA, got)
+	}
+}
+
+// commitSquashed writes content to path and commits it with two concatenated
+// trailer blocks, one per runID/role pair, the same format squashCommit
+// (loop.go) produces for a SquashOnLoopEnd run covering several tasks.
+func commitSquashed(t *testing.T, repoDir, path, content string, runIDs, roles []string) string {
+	t.Helper()
+	full := filepath.Join(repoDir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+
+	run := func(args ...string) string {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		out, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, out)
+		}
+		return string(out)
+	}
+	run("add", "-A")
+
+	var body strings.Builder
+	for i := range runIDs {
+		if i > 0 {
+			body.WriteString("\n\n")
+		}
+		fmt.Fprintf(&body, "- feat(loop): task %d\n%s: %s\n%s: %s", i, blameRunIDTrailer, runIDs[i], blameAgentRoleTrailer, roles[i])
+	}
+	msg := fmt.Sprintf("feat(loop): squash %d verified task(s)\n\n%s", len(runIDs), body.String())
+	run("commit", "-m", msg)
+	return strings.TrimSpace(run("rev-parse", "HEAD"))
+}
+
+func TestBlameRun_ResolvesNonFirstTaskInSquashedCommit(t *testing.T) {
+	repoDir := initTestRepo(t)
+	squashSHA := commitSquashed(t, repoDir, "base.txt", "base\nfirst-task\nsecond-task\n",
+		[]string{"run-first", "run-second"}, []string{string(RoleCoder), string(RoleVerifier)})
+
+	attributions, err := BlameRun(repoDir, "run-second")
+	if err != nil {
+		t.Fatalf("BlameRun: %v", err)
+	}
+
+	byLine := make(map[int]LineAttribution)
+	for _, a := range attributions {
+		byLine[a.Line] = a
+	}
+
+	if got := byLine[3]; got.RunID != "run-second" || got.AgentRole != string(RoleVerifier) || got.CommitSHA != squashSHA {
+		t.Errorf("line 3 should be attributed to run-second/verifier/%s, got %+v", squashSHA, got)
+	}
+}
+
+func TestBlameRun_ErrorsWhenRunNotFound(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	if _, err := BlameRun(repoDir, "never-committed"); err == nil {
+		t.Error("expected an error when no commit carries the given run ID")
+	}
+}
@@ -0,0 +1,200 @@
+package orchestrate
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sync"
+
+	"mycelica/spore/internal/db"
+)
+
+// candidateOutcome is one best-of-N coder+verifier attempt, run in its own
+// git worktree (see CreateWorktree). Index matches PhaseResult.Candidate.
+type candidateOutcome struct {
+	Index       int
+	WorktreeDir string
+	Coder       *PhaseResult
+	Verifier    *PhaseResult
+	Err         error
+}
+
+// runCandidates produces config.Candidates coder+verifier attempts for one
+// bounce, each isolated in its own git worktree under workDir. Attempts run
+// one at a time unless config.ParallelCandidates is set, in which case all
+// of them are spawned concurrently, bounded by a semaphore sized to
+// config.Candidates. It returns every attempt (winners and losers alike, for
+// inspection) and the index of the best VerdictSupports candidate, or -1 if
+// none supported.
+func runCandidates(
+	ctx context.Context,
+	d *db.DB, task, runID, taskNodeID string,
+	bounce, maxBounces int,
+	lastImplID string, lastVerdict Verdict,
+	cliBinary, workDir string,
+	config OrchestrationConfig,
+) ([]candidateOutcome, int) {
+	n := config.Candidates
+	if n < 1 {
+		n = 1
+	}
+
+	concurrency := 1
+	if config.ParallelCandidates {
+		concurrency = n
+	}
+	sem := make(chan struct{}, concurrency)
+
+	outcomes := make([]candidateOutcome, n)
+	var wg sync.WaitGroup
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			outcomes[i] = runOneCandidate(ctx, d, task, runID, taskNodeID, bounce, maxBounces,
+				lastImplID, lastVerdict, cliBinary, workDir, i, config)
+		}(i)
+	}
+	wg.Wait()
+
+	return outcomes, pickBestCandidate(outcomes)
+}
+
+// pickBestCandidate returns the index of the highest-confidence
+// VerdictSupports outcome among outcomes, or -1 if none of them support.
+func pickBestCandidate(outcomes []candidateOutcome) int {
+	best := -1
+	var bestConfidence float64
+	for i, o := range outcomes {
+		if o.Verifier == nil || o.Verifier.Verdict == nil {
+			continue
+		}
+		if o.Verifier.Verdict.Verdict != VerdictSupports {
+			continue
+		}
+		if best == -1 || o.Verifier.Verdict.Confidence > bestConfidence {
+			best = i
+			bestConfidence = o.Verifier.Verdict.Confidence
+		}
+	}
+	return best
+}
+
+// runOneCandidate creates a worktree for one candidate index, runs the coder
+// and (if the coder succeeded) the verifier inside it, and returns the
+// outcome. The caller is responsible for removing the worktree once done
+// with it (winner or loser).
+func runOneCandidate(
+	ctx context.Context,
+	d *db.DB, task, runID, taskNodeID string,
+	bounce, maxBounces int,
+	lastImplID string, lastVerdict Verdict,
+	cliBinary, workDir string,
+	candidate int,
+	config OrchestrationConfig,
+) candidateOutcome {
+	worktreeDir := WorktreeDir(workDir, runID, bounce, candidate)
+	if err := CreateWorktree(workDir, worktreeDir); err != nil {
+		return candidateOutcome{
+			Index: candidate,
+			Coder: &PhaseResult{Role: RoleCoder, Claude: &ClaudeResult{}, Candidate: candidate},
+			Err:   fmt.Errorf("creating worktree for candidate %d: %w", candidate, err),
+		}
+	}
+
+	// RollbackBetweenBounces snapshots/resets workDir, not a candidate's
+	// disposable worktree (CreateWorktree/RemoveWorktree already isolate
+	// it), so rollbackRef is always "" here.
+	coderResult, err := runCoder(ctx, d, task, runID, taskNodeID, bounce, maxBounces,
+		lastImplID, lastVerdict, cliBinary, worktreeDir, config, "", "")
+	coderResult.Candidate = candidate
+	if err != nil {
+		return candidateOutcome{Index: candidate, WorktreeDir: worktreeDir, Coder: coderResult, Err: err}
+	}
+
+	verifierResult, err := runVerifier(ctx, d, task, runID, taskNodeID, coderResult.ImplNodeID,
+		bounce, cliBinary, worktreeDir, config)
+	verifierResult.Candidate = candidate
+	if err != nil {
+		return candidateOutcome{Index: candidate, WorktreeDir: worktreeDir, Coder: coderResult, Verifier: verifierResult, Err: err}
+	}
+
+	return candidateOutcome{Index: candidate, WorktreeDir: worktreeDir, Coder: coderResult, Verifier: verifierResult}
+}
+
+// runBounceCandidates is the best-of-N replacement for a plain runCoder +
+// runVerifier call within one bounce. It records every candidate's
+// PhaseResults on result (tagged via PhaseResult.Candidate), applies the
+// highest-confidence VerdictSupports candidate's diff back onto workDir, and
+// prunes all candidate worktrees before returning. When no candidate
+// supports, the returned verifierResult carries VerdictContradicts so the
+// normal bounce/enforcement logic in RunOrchestration handles it unchanged.
+func runBounceCandidates(
+	ctx context.Context,
+	d *db.DB, task, runID, taskNodeID string,
+	bounce, maxBounces int,
+	lastImplID string, lastVerdict Verdict,
+	cliBinary, workDir string,
+	config OrchestrationConfig,
+	result *OrchestrationResult,
+) (*PhaseResult, *PhaseResult, error) {
+	outcomes, best := runCandidates(ctx, d, task, runID, taskNodeID, bounce, maxBounces,
+		lastImplID, lastVerdict, cliBinary, workDir, config)
+
+	for _, o := range outcomes {
+		if o.Coder != nil {
+			result.Phases = append(result.Phases, *o.Coder)
+			result.TotalCost += o.Coder.Claude.CostUSD
+		}
+		if o.Verifier != nil {
+			result.Phases = append(result.Phases, *o.Verifier)
+			result.TotalCost += o.Verifier.Claude.CostUSD
+		}
+	}
+
+	defer func() {
+		for _, o := range outcomes {
+			if o.WorktreeDir == "" {
+				continue
+			}
+			if err := RemoveWorktree(workDir, o.WorktreeDir); err != nil {
+				fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to prune worktree %s: %v\n", o.WorktreeDir, err)
+			}
+		}
+	}()
+
+	if best == -1 {
+		for _, o := range outcomes {
+			if o.Err != nil {
+				return o.Coder, o.Verifier, o.Err
+			}
+		}
+		if !config.Quiet {
+			fmt.Fprintf(os.Stderr, "  Candidates: %d/%d attempts, none supported\n", len(outcomes), len(outcomes))
+		}
+		return outcomes[0].Coder, &PhaseResult{
+			Role: RoleVerifier,
+			Claude: &ClaudeResult{},
+			Verdict: &VerifierVerdict{
+				Verdict: VerdictContradicts,
+				Reason:  "no candidate supported",
+			},
+		}, nil
+	}
+
+	winner := outcomes[best]
+	if !config.Quiet {
+		fmt.Fprintf(os.Stderr, "  Candidates: %d/%d attempts, candidate %d wins (%.0f%% confidence)\n",
+			len(outcomes), len(outcomes), winner.Index, winner.Verifier.Verdict.Confidence*100)
+	}
+
+	if err := ApplyWorktreeDiff(workDir, winner.WorktreeDir); err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to apply winning candidate %d's diff: %v\n", winner.Index, err)
+	} else if len(winner.Coder.ChangedFiles) > 0 {
+		postCoderCleanup(ctx, d, cliBinary, workDir, winner.Coder.ChangedFiles)
+	}
+
+	return winner.Coder, winner.Verifier, nil
+}
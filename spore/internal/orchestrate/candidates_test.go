@@ -0,0 +1,26 @@
+package orchestrate
+
+import "testing"
+
+func TestRunCandidates_PicksHighestConfidenceSupports(t *testing.T) {
+	outcomes := []candidateOutcome{
+		{Index: 0, Verifier: &PhaseResult{Verdict: &VerifierVerdict{Verdict: VerdictContradicts, Confidence: 0.9}}},
+		{Index: 1, Verifier: &PhaseResult{Verdict: &VerifierVerdict{Verdict: VerdictSupports, Confidence: 0.4}}},
+		{Index: 2, Verifier: &PhaseResult{Verdict: &VerifierVerdict{Verdict: VerdictSupports, Confidence: 0.8}}},
+	}
+
+	if best := pickBestCandidate(outcomes); best != 2 {
+		t.Errorf("best candidate = %d, want 2 (highest-confidence supports)", best)
+	}
+}
+
+func TestRunCandidates_NoneSupports(t *testing.T) {
+	outcomes := []candidateOutcome{
+		{Index: 0, Verifier: &PhaseResult{Verdict: &VerifierVerdict{Verdict: VerdictContradicts, Confidence: 0.9}}},
+		{Index: 1, Verifier: &PhaseResult{Verdict: &VerifierVerdict{Verdict: VerdictUnknown, Confidence: 0.1}}},
+	}
+
+	if best := pickBestCandidate(outcomes); best != -1 {
+		t.Errorf("best candidate = %d, want -1 (none supports)", best)
+	}
+}
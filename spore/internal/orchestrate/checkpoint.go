@@ -0,0 +1,72 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// Checkpoint captures enough state after one phase to resume a crashed or
+// interrupted orchestration run (see OrchestrationConfig.ResumeRunID):
+// where it left off (bounce/phase), the last implementation/verdict, the
+// Claude session to resume, and every phase result recorded so far.
+type Checkpoint struct {
+	RunID       string        `json:"run_id"`
+	TaskNodeID  string        `json:"task_node_id"`
+	Bounce      int           `json:"bounce"`
+	Phase       AgentRole     `json:"phase"`
+	SessionID   string        `json:"session_id"`
+	ImplNodeID  string        `json:"impl_node_id"`
+	LastVerdict Verdict       `json:"last_verdict"`
+	Phases      []PhaseResult `json:"phases"`
+	TotalCost   float64       `json:"total_cost_usd"`
+}
+
+// checkpointPath returns the sidecar JSON path for a run's checkpoint.
+func checkpointPath(outputDir, runID string) string {
+	return filepath.Join(outputDir, fmt.Sprintf("checkpoint-%s.json", runID))
+}
+
+// saveCheckpoint persists cp under outputDir, overwriting any previous
+// checkpoint for the same run. Non-fatal: a disk error only logs a warning
+// so it doesn't abort an otherwise-successful phase.
+func saveCheckpoint(outputDir string, cp Checkpoint) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to create checkpoint dir: %v\n", err)
+		return
+	}
+	data, err := json.MarshalIndent(cp, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to marshal checkpoint: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(checkpointPath(outputDir, cp.RunID), data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to write checkpoint: %v\n", err)
+	}
+}
+
+// removeCheckpoint deletes a run's checkpoint once it's no longer
+// resumable (run succeeded or was escalated). Missing file is not an error.
+func removeCheckpoint(outputDir, runID string) {
+	if err := os.Remove(checkpointPath(outputDir, runID)); err != nil && !os.IsNotExist(err) {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to remove checkpoint: %v\n", err)
+	}
+}
+
+// loadCheckpoint reads the latest checkpoint for resumeRunID, or returns nil
+// (with no error) if none was ever saved for that run.
+func loadCheckpoint(outputDir, resumeRunID string) (*Checkpoint, error) {
+	data, err := os.ReadFile(checkpointPath(outputDir, resumeRunID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading checkpoint: %w", err)
+	}
+	var cp Checkpoint
+	if err := json.Unmarshal(data, &cp); err != nil {
+		return nil, fmt.Errorf("parsing checkpoint %s: %w", resumeRunID, err)
+	}
+	return &cp, nil
+}
@@ -0,0 +1,70 @@
+package orchestrate
+
+import "testing"
+
+func TestSaveLoadCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+
+	cp := Checkpoint{
+		RunID:       "run-abc",
+		TaskNodeID:  "task-123",
+		Bounce:      2,
+		Phase:       RoleVerifier,
+		SessionID:   "session-xyz",
+		ImplNodeID:  "impl-456",
+		LastVerdict: VerdictContradicts,
+		Phases: []PhaseResult{
+			{Role: RoleCoder, Claude: &ClaudeResult{CostUSD: 1.5}},
+		},
+		TotalCost: 1.5,
+	}
+	saveCheckpoint(dir, cp)
+
+	loaded, err := loadCheckpoint(dir, "run-abc")
+	if err != nil {
+		t.Fatalf("loadCheckpoint: %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("expected a checkpoint, got nil")
+	}
+	if loaded.Bounce != 2 || loaded.SessionID != "session-xyz" || loaded.ImplNodeID != "impl-456" {
+		t.Errorf("loaded checkpoint = %+v, want bounce=2 session=session-xyz impl=impl-456", loaded)
+	}
+	if loaded.LastVerdict != VerdictContradicts {
+		t.Errorf("loaded.LastVerdict = %v, want %v", loaded.LastVerdict, VerdictContradicts)
+	}
+	if len(loaded.Phases) != 1 || loaded.Phases[0].Claude.CostUSD != 1.5 {
+		t.Errorf("loaded.Phases = %+v, want one phase with cost 1.5", loaded.Phases)
+	}
+}
+
+func TestLoadCheckpoint_NotFound(t *testing.T) {
+	dir := t.TempDir()
+
+	cp, err := loadCheckpoint(dir, "no-such-run")
+	if err != nil {
+		t.Fatalf("loadCheckpoint for missing run should not error, got: %v", err)
+	}
+	if cp != nil {
+		t.Errorf("expected nil checkpoint for missing run, got %+v", cp)
+	}
+}
+
+func TestRemoveCheckpoint(t *testing.T) {
+	dir := t.TempDir()
+
+	saveCheckpoint(dir, Checkpoint{RunID: "run-gone"})
+	if cp, _ := loadCheckpoint(dir, "run-gone"); cp == nil {
+		t.Fatal("expected checkpoint to exist before removal")
+	}
+
+	removeCheckpoint(dir, "run-gone")
+
+	cp, err := loadCheckpoint(dir, "run-gone")
+	if err != nil {
+		t.Fatalf("loadCheckpoint after removal: %v", err)
+	}
+	if cp != nil {
+		t.Errorf("expected nil checkpoint after removal, got %+v", cp)
+	}
+}
@@ -3,6 +3,7 @@ package orchestrate
 import (
 	"bufio"
 	"bytes"
+	"context"
 	"encoding/json"
 	"fmt"
 	"io"
@@ -16,9 +17,97 @@ import (
 	"time"
 )
 
+// ClaudeHandle is a started Claude subprocess plus its live stream-json
+// broadcast. SpawnClaudeStreaming returns a handle as soon as the process
+// starts; Wait blocks for the final ClaudeResult the way SpawnClaude always
+// has, while AttachListener lets any number of consumers (a TUI panel, a web
+// viewer, a log tee, an audit sink) fan out the same run's typed StreamEvents
+// without re-parsing stream-json themselves.
+type ClaudeHandle struct {
+	broadcaster *StreamBroadcaster
+	done        chan struct{}
+	result      *ClaudeResult
+	err         error
+}
+
+// AttachListener registers ch on h's broadcaster -- see
+// StreamBroadcaster.AttachListener for backlog-replay and slow-listener
+// semantics.
+func (h *ClaudeHandle) AttachListener(ch chan StreamEvent) (detach func()) {
+	return h.broadcaster.AttachListener(ch)
+}
+
+// Wait blocks until the subprocess exits and returns its result, matching
+// SpawnClaude's synchronous contract.
+func (h *ClaudeHandle) Wait() (*ClaudeResult, error) {
+	<-h.done
+	return h.result, h.err
+}
+
 // SpawnClaude starts a Claude Code subprocess with the given configuration,
-// reads stream-json output, and returns the captured result.
-func SpawnClaude(config ClaudeConfig) (*ClaudeResult, error) {
+// reads stream-json output, and returns the captured result. ctx bounds the
+// subprocess's lifetime in addition to config.Timeout's own watchdog: if ctx
+// is cancelled or its deadline passes, the process is killed and the ctx
+// error (context.Canceled/context.DeadlineExceeded) is returned. It's a thin
+// synchronous wrapper around SpawnClaudeStreaming for callers that don't need
+// to attach a live listener.
+func SpawnClaude(ctx context.Context, config ClaudeConfig) (*ClaudeResult, error) {
+	handle, err := SpawnClaudeStreaming(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+	return handle.Wait()
+}
+
+// SpawnClaudeStreaming starts a Claude Code subprocess the same way
+// SpawnClaude does, but returns immediately after the process starts rather
+// than blocking for its result. Call handle.Wait for the final ClaudeResult,
+// or handle.AttachListener to fan out its stream-json output live. When
+// config.Detached is set, the subprocess runs under a detached shim instead
+// (see spawnShimmed) so it survives the orchestrator restarting.
+func SpawnClaudeStreaming(ctx context.Context, config ClaudeConfig) (*ClaudeHandle, error) {
+	if config.Detached {
+		return spawnShimmed(ctx, config)
+	}
+
+	handle := &ClaudeHandle{
+		broadcaster: NewStreamBroadcaster(defaultBroadcastBacklogBytes),
+		done:        make(chan struct{}),
+	}
+
+	cp, err := startClaudeSubprocess(ctx, config, handle.broadcaster)
+	if err != nil {
+		return nil, err
+	}
+
+	// The rest of the run (waiting for parse/process completion and
+	// resolving the final result) happens in the background so callers get
+	// the handle back as soon as the process starts.
+	go func() {
+		defer close(handle.done)
+		defer handle.broadcaster.Close()
+		handle.result, handle.err = cp.wait()
+	}()
+
+	return handle, nil
+}
+
+// claudeSubprocess is a started `claude` child plus the machinery to block
+// for its ClaudeResult. It's the shared core behind both SpawnClaudeStreaming
+// (which resolves it into a ClaudeHandle in-process) and the shim process
+// (which resolves it directly, since the shim has no in-process caller to
+// hand a handle to -- it fans events out over a socket instead).
+type claudeSubprocess struct {
+	proc *os.Process
+	wait func() (*ClaudeResult, error)
+}
+
+// startClaudeSubprocess builds the `claude` args from config, starts the
+// process, and wires up stream-json parsing (publishing to broadcaster, if
+// non-nil) and the startup/normal timeout watchdog, exactly as
+// SpawnClaudeStreaming always has. It returns as soon as the process starts;
+// call the returned claudeSubprocess.wait to block for the result.
+func startClaudeSubprocess(ctx context.Context, config ClaudeConfig, broadcaster *StreamBroadcaster) (*claudeSubprocess, error) {
 	// Build args
 	args := []string{
 		"-p", config.Prompt,
@@ -45,7 +134,7 @@ func SpawnClaude(config ClaudeConfig) (*ClaudeResult, error) {
 		args = append(args, "--resume", config.ResumeID)
 	}
 
-	cmd := exec.Command("claude", args...)
+	cmd := exec.CommandContext(ctx, "claude", args...)
 
 	// Set working directory
 	if config.WorkDir != "" {
@@ -67,6 +156,8 @@ func SpawnClaude(config ClaudeConfig) (*ClaudeResult, error) {
 	stderrBuf.limit = 10 * 1024 // 10KB
 	cmd.Stderr = &stderrBuf
 
+	reportState(config.StateReporter, StatePending)
+
 	// Start the process
 	if err := cmd.Start(); err != nil {
 		return nil, fmt.Errorf("starting claude: %w", err)
@@ -82,7 +173,7 @@ func SpawnClaude(config ClaudeConfig) (*ClaudeResult, error) {
 	// Parse stream-json output in a goroutine
 	go func() {
 		defer close(parseDone)
-		result = parseStreamJSON(stdout, firstOutput)
+		result = parseStreamJSON(stdout, firstOutput, broadcaster, config.StateReporter)
 	}()
 
 	// Watchdog goroutine for timeouts
@@ -102,46 +193,75 @@ func SpawnClaude(config ClaudeConfig) (*ClaudeResult, error) {
 		watchdog(cmd.Process, firstOutput, startupTimeout, normalTimeout)
 	}()
 
-	// Wait for parsing to complete (stdout EOF)
-	<-parseDone
+	// Track the process with the default ShutdownCoordinator so a
+	// WaitForDeath call drains it on shutdown instead of leaving it to exit
+	// (or not) on its own.
+	deregister := RegisterProcess(cmd.Process, &stderrBuf, string(config.Role))
+
+	wait := func() (*ClaudeResult, error) {
+		// Wait for parsing to complete (stdout EOF)
+		<-parseDone
 
-	// Wait for process exit
-	waitErr := cmd.Wait()
+		// Wait for process exit
+		waitErr := cmd.Wait()
+		deregister()
 
-	// Signal watchdog to stop (process already exited)
-	// The watchdog checks Process state, but we close its channel path
-	// by having the process already exited.
-	<-watchdogDone
+		// Signal watchdog to stop (process already exited)
+		// The watchdog checks Process state, but we close its channel path
+		// by having the process already exited.
+		<-watchdogDone
 
-	if result == nil {
-		result = &ClaudeResult{}
-	}
+		if result == nil {
+			result = &ClaudeResult{}
+		}
+
+		result.Stderr = stderrBuf.String()
 
-	// Capture exit code
-	if waitErr != nil {
-		if exitErr, ok := waitErr.(*exec.ExitError); ok {
-			result.ExitCode = exitErr.ExitCode()
+		// A ctx cancellation/deadline takes precedence over the generic wait
+		// error: exec.CommandContext already killed the process, but the
+		// caller needs to know *why* to record StatusTimeout vs
+		// StatusCancelled.
+		if waitErr != nil {
+			if ctxErr := ctx.Err(); ctxErr != nil {
+				reportState(config.StateReporter, StateFailed)
+				return result, ctxErr
+			}
+			if exitErr, ok := waitErr.(*exec.ExitError); ok {
+				result.ExitCode = exitErr.ExitCode()
+			} else {
+				result.ExitCode = -1
+			}
+		}
+
+		if result.ExitCode == 0 {
+			reportState(config.StateReporter, StateCompleted)
 		} else {
-			result.ExitCode = -1
+			reportState(config.StateReporter, StateFailed)
 		}
-	}
 
-	result.Stderr = stderrBuf.String()
+		return result, nil
+	}
 
-	return result, nil
+	return &claudeSubprocess{proc: cmd.Process, wait: wait}, nil
 }
 
-// parseStreamJSON reads stream-json lines from r and extracts a ClaudeResult.
-// Signals firstOutput on the first successfully parsed line.
+// parseStreamJSON reads stream-json lines from r and extracts a ClaudeResult,
+// publishing a typed StreamEvent to broadcaster for each thinking/text/
+// tool_use block and MCP status/result line it sees, so live listeners don't
+// need to re-parse stream-json. Signals firstOutput and reports StateReceived
+// on the first successfully parsed line, and reports StateRunning on the
+// first assistant event. broadcaster and reporter may both be nil.
 // Exported-friendly via the ParseStreamJSON wrapper for testing.
-func parseStreamJSON(r io.Reader, firstOutput chan<- struct{}) *ClaudeResult {
+func parseStreamJSON(r io.Reader, firstOutput chan<- struct{}, broadcaster *StreamBroadcaster, reporter AgentStateReporter) *ClaudeResult {
 	result := &ClaudeResult{}
 	scanner := bufio.NewScanner(r)
 	// Allow large lines (some assistant messages can be huge)
 	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
 
 	signaled := false
+	reportedRunning := false
 	var lastThinking string
+	var lastMessage string
 
 	for scanner.Scan() {
 		line := strings.TrimSpace(scanner.Text())
@@ -156,6 +276,7 @@ func parseStreamJSON(r io.Reader, firstOutput chan<- struct{}) *ClaudeResult {
 			default:
 			}
 			signaled = true
+			reportState(reporter, StateReceived)
 		}
 
 		var event streamEvent
@@ -176,14 +297,33 @@ func parseStreamJSON(r io.Reader, firstOutput chan<- struct{}) *ClaudeResult {
 						result.MCPStatus = "failed"
 					}
 				}
+				broadcaster.Publish(StreamEvent{Type: EventMCPStatus, MCPStatus: result.MCPStatus})
 			}
 
 		case "assistant":
-			// Look for thinking blocks in message content
+			if !reportedRunning {
+				reportedRunning = true
+				reportState(reporter, StateRunning)
+			}
+			// Look for thinking/text/tool_use blocks in message content
 			if event.Message != nil {
 				for _, block := range event.Message.Content {
-					if block.Type == "thinking" && block.Thinking != "" {
-						lastThinking = strings.TrimSpace(block.Thinking)
+					switch block.Type {
+					case "thinking":
+						if block.Thinking != "" {
+							lastThinking = strings.TrimSpace(block.Thinking)
+							broadcaster.Publish(StreamEvent{Type: EventThinking, Text: lastThinking})
+						}
+					case "text":
+						if block.Text != "" {
+							lastMessage = strings.TrimSpace(block.Text)
+							broadcaster.Publish(StreamEvent{Type: EventAssistantText, Text: lastMessage})
+						}
+					case "tool_use":
+						if block.Name != "" {
+							result.ToolCalls = append(result.ToolCalls, ToolCall{Name: block.Name, Input: block.Input})
+							broadcaster.Publish(StreamEvent{Type: EventToolUse, ToolName: block.Name, ToolInput: block.Input})
+						}
 					}
 				}
 			}
@@ -195,11 +335,14 @@ func parseStreamJSON(r io.Reader, firstOutput chan<- struct{}) *ClaudeResult {
 			if event.DurationMS > 0 {
 				result.Duration = time.Duration(event.DurationMS) * time.Millisecond
 			}
+			resultCopy := *result
+			broadcaster.Publish(StreamEvent{Type: EventResult, Result: &resultCopy})
 		}
 	}
 
-	// Keep only the last thinking block
+	// Keep only the last thinking/text block
 	result.Thinking = lastThinking
+	result.LastMessage = lastMessage
 
 	return result
 }
@@ -207,7 +350,7 @@ func parseStreamJSON(r io.Reader, firstOutput chan<- struct{}) *ClaudeResult {
 // ParseStreamJSON is the exported test wrapper for parseStreamJSON.
 func ParseStreamJSON(r io.Reader) *ClaudeResult {
 	ch := make(chan struct{}, 1)
-	return parseStreamJSON(r, ch)
+	return parseStreamJSON(r, ch, nil, nil)
 }
 
 // streamEvent represents a single line from Claude Code's stream-json output.
@@ -237,9 +380,11 @@ type assistantMessage struct {
 }
 
 type contentBlock struct {
-	Type     string `json:"type"`
-	Text     string `json:"text,omitempty"`
-	Thinking string `json:"thinking,omitempty"`
+	Type     string          `json:"type"`
+	Text     string          `json:"text,omitempty"`
+	Thinking string          `json:"thinking,omitempty"`
+	Name     string          `json:"name,omitempty"`  // for "tool_use" blocks
+	Input    json.RawMessage `json:"input,omitempty"` // for "tool_use" blocks
 }
 
 // watchdog monitors a Claude process and kills it on timeout.
@@ -283,7 +428,11 @@ func watchdog(proc *os.Process, firstOutput <-chan struct{}, startupTimeout, nor
 	}
 }
 
-// killProcess sends SIGTERM, waits 3 seconds, then SIGKILL.
+// killProcess sends SIGTERM, waits 3 seconds, then SIGKILL. proc is normally
+// the `claude` child itself, but for a Detached run it's the shim process
+// instead (see spawnShimmed) -- the shim's own signal handler (installed by
+// RunShim) runs killProcess again against its Claude child, so the same
+// escalation propagates one hop further.
 func killProcess(proc *os.Process) {
 	_ = proc.Signal(syscall.SIGTERM)
 	done := make(chan struct{})
@@ -258,6 +258,33 @@ func TestStreamJSONParsing_SystemAndResult(t *testing.T) {
 	}
 }
 
+func TestStreamJSONParsing_PublishesToBroadcaster(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"assistant","message":{"content":[{"type":"thinking","thinking":"mulling it over"}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{}}]}}`,
+		`{"type":"result","session_id":"sess-broadcast","total_cost_usd":0.5,"num_turns":1,"duration_ms":100}`,
+	}, "\n") + "\n"
+
+	broadcaster := NewStreamBroadcaster(1024)
+	ch := make(chan StreamEvent, 8)
+	detach := broadcaster.AttachListener(ch)
+	defer detach()
+
+	firstOutput := make(chan struct{}, 1)
+	parseStreamJSON(strings.NewReader(input), firstOutput, broadcaster, nil)
+
+	var types []StreamEventType
+	for i := 0; i < 3; i++ {
+		types = append(types, (<-ch).Type)
+	}
+	want := []StreamEventType{EventThinking, EventToolUse, EventResult}
+	for i, wt := range want {
+		if types[i] != wt {
+			t.Errorf("event %d type = %q, want %q (got order %v)", i, types[i], wt, types)
+		}
+	}
+}
+
 func TestStreamJSONParsing_EmptyAndMalformed(t *testing.T) {
 	input := strings.Join([]string{
 		"",
@@ -304,6 +331,28 @@ func TestStreamJSONParsing_MultipleThinkingBlocks(t *testing.T) {
 	}
 }
 
+func TestStreamJSONParsing_ToolCallsAndLastMessage(t *testing.T) {
+	input := strings.Join([]string{
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Checking the diff..."}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"Read","input":{"file":"a.go"}}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"tool_use","name":"mcp__mycelica__submit_verdict","input":{"verdict":"supports","confidence":0.95}}]}}`,
+		`{"type":"assistant","message":{"content":[{"type":"text","text":"Verified, it supports."}]}}`,
+		`{"type":"result","session_id":"s","total_cost_usd":0.1,"num_turns":1,"duration_ms":100}`,
+	}, "\n") + "\n"
+
+	result := ParseStreamJSON(strings.NewReader(input))
+
+	if result.LastMessage != "Verified, it supports." {
+		t.Errorf("LastMessage = %q, want %q", result.LastMessage, "Verified, it supports.")
+	}
+	if len(result.ToolCalls) != 2 {
+		t.Fatalf("ToolCalls = %d, want 2", len(result.ToolCalls))
+	}
+	if result.ToolCalls[1].Name != "mcp__mycelica__submit_verdict" {
+		t.Errorf("ToolCalls[1].Name = %q, want submit_verdict", result.ToolCalls[1].Name)
+	}
+}
+
 func TestStreamJSONParsing_NoResult(t *testing.T) {
 	// No result event — should return empty result without panic
 	input := `{"type":"system"}` + "\n"
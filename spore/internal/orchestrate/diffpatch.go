@@ -0,0 +1,261 @@
+package orchestrate
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/filemode"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	godiff "github.com/go-git/go-git/v5/utils/diff"
+	dmp "github.com/sergi/go-diff/diffmatchpatch"
+)
+
+// FilePatch is one file's unified diff between two GitState snapshots,
+// carrying enough metadata for the orchestrator to persist an
+// agent-attributed patch into the DB and render it in the UI without
+// re-shelling to `git diff`.
+type FilePatch struct {
+	Path    string `json:"path"`
+	OldHash string `json:"old_hash,omitempty"` // empty for newly added files
+	NewHash string `json:"new_hash,omitempty"` // empty for deleted files
+	Patch   string `json:"patch"`
+	Adds    int    `json:"adds"`
+	Dels    int    `json:"dels"`
+
+	// Pointer is set when either side of this change was too large to hash
+	// in full (see GitStateOpts.HashSizeThreshold): Patch is a placeholder
+	// message rather than a real unified diff, since the content was never
+	// read.
+	Pointer bool `json:"pointer,omitempty"`
+}
+
+// diffContextLines matches git's own default unified-diff context.
+const diffContextLines = 3
+
+// blobContentCache recovers the content CaptureFileHashes hashed for a
+// given hash, so DiffPatches can diff two snapshots' content without
+// CaptureGitState's go-git path ever having written blobs into the repo's
+// object database. Populated by CaptureFileHashes as it reads each file.
+var blobContentCache sync.Map // map[string][]byte, keyed by hash string
+
+// DiffPatches produces a unified diff per file that changed between before
+// and after, using go-git's myers diff (utils/diff.Do) and unified-diff
+// encoder (plumbing/format/diff.UnifiedEncoder). Unlike DiffChangedFiles,
+// which only reports filenames, this also detects pure deletions — files in
+// before.Hashes that are entirely absent from after (not dirty, not
+// untracked, not hashed) — and emits an all-removals patch against
+// /dev/null for them, and an all-additions patch against /dev/null for
+// files new in after.
+func DiffPatches(before, after *GitState, repoDir string) ([]FilePatch, error) {
+	paths := make(map[string]bool)
+	for f := range after.Hashes {
+		paths[f] = true
+	}
+	for f := range before.Hashes {
+		if _, ok := after.Hashes[f]; !ok {
+			paths[f] = true // deleted
+		}
+	}
+
+	sorted := make([]string, 0, len(paths))
+	for f := range paths {
+		sorted = append(sorted, f)
+	}
+	sort.Strings(sorted)
+
+	var patches []FilePatch
+	for _, path := range sorted {
+		oldFH, hadBefore := before.Hashes[path]
+		newFH, hasAfter := after.Hashes[path]
+		if hadBefore && hasAfter && oldFH == newFH {
+			continue // unchanged
+		}
+
+		if oldFH.Pointer || newFH.Pointer {
+			patches = append(patches, pointerFilePatch(path, oldFH, newFH))
+			continue
+		}
+
+		fp, err := diffOneFile(path, oldFH.Hash, newFH.Hash, repoDir)
+		if err != nil {
+			return nil, fmt.Errorf("diffing %s: %w", path, err)
+		}
+		patches = append(patches, fp)
+	}
+	return patches, nil
+}
+
+// pointerFilePatch builds a placeholder FilePatch for a file on either side
+// of a pointerHash (see GitStateOpts.HashSizeThreshold): its content was
+// never read in full, so there's nothing to feed the myers diff in
+// diffOneFile.
+func pointerFilePatch(path string, oldFH, newFH FileHash) FilePatch {
+	return FilePatch{
+		Path:    path,
+		OldHash: oldFH.Hash,
+		NewHash: newFH.Hash,
+		Patch:   fmt.Sprintf("%s changed (file exceeds GitStateOpts.HashSizeThreshold; not diffed)\n", path),
+		Pointer: true,
+	}
+}
+
+// diffOneFile builds one FilePatch. An empty oldHash means the file is new
+// in after; an empty newHash means it was deleted.
+func diffOneFile(path, oldHash, newHash, repoDir string) (FilePatch, error) {
+	oldContent, err := blobContent(oldHash, path, repoDir)
+	if err != nil {
+		return FilePatch{}, err
+	}
+	newContent, err := blobContent(newHash, path, repoDir)
+	if err != nil {
+		return FilePatch{}, err
+	}
+
+	var from, to *diffFile
+	if oldHash != "" {
+		from = &diffFile{hash: plumbing.NewHash(oldHash), path: path}
+	}
+	if newHash != "" {
+		to = &diffFile{hash: plumbing.NewHash(newHash), path: path}
+	}
+
+	chunks := diffChunks(string(oldContent), string(newContent))
+
+	var buf strings.Builder
+	encoder := fdiff.NewUnifiedEncoder(&buf, diffContextLines)
+	patch := &diffPatch{filePatches: []fdiff.FilePatch{&diffFilePatch{from: from, to: to, chunks: chunks}}}
+	if err := encoder.Encode(patch); err != nil {
+		return FilePatch{}, fmt.Errorf("encoding unified diff: %w", err)
+	}
+
+	adds, dels := chunkStats(chunks)
+	return FilePatch{
+		Path:    path,
+		OldHash: oldHash,
+		NewHash: newHash,
+		Patch:   buf.String(),
+		Adds:    adds,
+		Dels:    dels,
+	}, nil
+}
+
+// diffChunks runs the myers line diff and converts it into the chunk
+// sequence fdiff.UnifiedEncoder expects.
+func diffChunks(oldContent, newContent string) []fdiff.Chunk {
+	diffs := godiff.Do(oldContent, newContent)
+	chunks := make([]fdiff.Chunk, 0, len(diffs))
+	for _, d := range diffs {
+		var op fdiff.Operation
+		switch d.Type {
+		case dmp.DiffEqual:
+			op = fdiff.Equal
+		case dmp.DiffDelete:
+			op = fdiff.Delete
+		case dmp.DiffInsert:
+			op = fdiff.Add
+		}
+		chunks = append(chunks, &diffChunk{content: d.Text, op: op})
+	}
+	return chunks
+}
+
+// chunkStats counts added/deleted lines the same way go-git's own
+// FileStats does: a newline-terminated chunk's count is strings.Count, plus
+// one more if the chunk doesn't end in a newline.
+func chunkStats(chunks []fdiff.Chunk) (adds, dels int) {
+	for _, c := range chunks {
+		s := c.Content()
+		if len(s) == 0 {
+			continue
+		}
+		lines := strings.Count(s, "\n")
+		if s[len(s)-1] != '\n' {
+			lines++
+		}
+		switch c.Type() {
+		case fdiff.Add:
+			adds += lines
+		case fdiff.Delete:
+			dels += lines
+		}
+	}
+	return adds, dels
+}
+
+// blobContent recovers the content that hashed to hash, for diffing. An
+// empty hash (new-in-after or deleted-in-before) returns no content. Falls
+// back to reading path directly out of repoDir when the hash isn't cached
+// from an earlier CaptureFileHashes call in this run, only trusting that
+// content if it still hashes to the value we're looking for.
+func blobContent(hash, path, repoDir string) ([]byte, error) {
+	if hash == "" {
+		return nil, nil
+	}
+	if cached, ok := blobContentCache.Load(hash); ok {
+		return cached.([]byte), nil
+	}
+
+	content, err := os.ReadFile(filepath.Join(repoDir, path))
+	if err != nil {
+		return nil, fmt.Errorf("content for hash %s isn't cached and %s can't be read from disk: %w", hash, path, err)
+	}
+	if plumbing.ComputeHash(plumbing.BlobObject, content).String() != hash {
+		return nil, fmt.Errorf("content for hash %s isn't cached and %s on disk no longer matches it", hash, path)
+	}
+	return content, nil
+}
+
+// diffFile implements fdiff.File for a plain working-tree file: no rename
+// or mode-change tracking, since GitState doesn't carry that information.
+type diffFile struct {
+	hash plumbing.Hash
+	path string
+}
+
+func (f *diffFile) Hash() plumbing.Hash     { return f.hash }
+func (f *diffFile) Mode() filemode.FileMode { return filemode.Regular }
+func (f *diffFile) Path() string            { return f.path }
+
+// diffChunk implements fdiff.Chunk.
+type diffChunk struct {
+	content string
+	op      fdiff.Operation
+}
+
+func (c *diffChunk) Content() string       { return c.content }
+func (c *diffChunk) Type() fdiff.Operation { return c.op }
+
+// diffFilePatch implements fdiff.FilePatch for one file. from/to are nil
+// (not a typed-nil *diffFile) for added/deleted files, matching what
+// UnifiedEncoder checks to print "new file"/"deleted file" headers.
+type diffFilePatch struct {
+	from, to *diffFile
+	chunks   []fdiff.Chunk
+}
+
+func (p *diffFilePatch) IsBinary() bool { return false }
+
+func (p *diffFilePatch) Files() (from, to fdiff.File) {
+	if p.from != nil {
+		from = p.from
+	}
+	if p.to != nil {
+		to = p.to
+	}
+	return from, to
+}
+
+func (p *diffFilePatch) Chunks() []fdiff.Chunk { return p.chunks }
+
+// diffPatch implements fdiff.Patch for a single-file patch.
+type diffPatch struct {
+	filePatches []fdiff.FilePatch
+}
+
+func (p *diffPatch) FilePatches() []fdiff.FilePatch { return p.filePatches }
+func (p *diffPatch) Message() string                { return "" }
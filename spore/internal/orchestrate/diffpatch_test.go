@@ -0,0 +1,136 @@
+package orchestrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// captureState is a small helper: write content to path under dir, then
+// capture a GitState whose Hashes/Untracked reflect it, the way
+// CaptureGitState normally would for a dirty/untracked file.
+func captureState(t *testing.T, dir, path, content string) *GitState {
+	t.Helper()
+	full := filepath.Join(dir, path)
+	if err := os.MkdirAll(filepath.Dir(full), 0755); err != nil {
+		t.Fatalf("mkdir: %v", err)
+	}
+	if err := os.WriteFile(full, []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	hashes := CaptureFileHashes(dir, map[string]bool{path: true})
+	return &GitState{
+		Untracked: map[string]bool{path: true},
+		Hashes:    hashes,
+	}
+}
+
+func TestDiffPatches_ModifiedFile(t *testing.T) {
+	dir := t.TempDir()
+	before := captureState(t, dir, "a.txt", "line1\nline2\nline3\n")
+	after := captureState(t, dir, "a.txt", "line1\nCHANGED\nline3\n")
+
+	patches, err := DiffPatches(before, after, dir)
+	if err != nil {
+		t.Fatalf("DiffPatches: %v", err)
+	}
+	if len(patches) != 1 {
+		t.Fatalf("expected 1 patch, got %d: %v", len(patches), patches)
+	}
+	p := patches[0]
+	if p.Path != "a.txt" {
+		t.Errorf("Path = %q, want a.txt", p.Path)
+	}
+	if p.Adds != 1 || p.Dels != 1 {
+		t.Errorf("Adds/Dels = %d/%d, want 1/1", p.Adds, p.Dels)
+	}
+	if !strings.Contains(p.Patch, "-line2") || !strings.Contains(p.Patch, "+CHANGED") {
+		t.Errorf("expected unified diff to show the line swap, got:\n%s", p.Patch)
+	}
+}
+
+func TestDiffPatches_NewFile(t *testing.T) {
+	dir := t.TempDir()
+	before := &GitState{Hashes: map[string]FileHash{}}
+	after := captureState(t, dir, "new.txt", "hello\nworld\n")
+
+	patches, err := DiffPatches(before, after, dir)
+	if err != nil {
+		t.Fatalf("DiffPatches: %v", err)
+	}
+	if len(patches) != 1 {
+		t.Fatalf("expected 1 patch, got %d", len(patches))
+	}
+	p := patches[0]
+	if p.OldHash != "" {
+		t.Errorf("expected empty OldHash for a new file, got %q", p.OldHash)
+	}
+	if p.Adds != 2 || p.Dels != 0 {
+		t.Errorf("Adds/Dels = %d/%d, want 2/0", p.Adds, p.Dels)
+	}
+	if !strings.Contains(p.Patch, "/dev/null") || !strings.Contains(p.Patch, "new file mode") {
+		t.Errorf("expected a new-file patch against /dev/null, got:\n%s", p.Patch)
+	}
+}
+
+func TestDiffPatches_DeletedFile(t *testing.T) {
+	dir := t.TempDir()
+	before := captureState(t, dir, "gone.txt", "bye\n")
+	if err := os.Remove(filepath.Join(dir, "gone.txt")); err != nil {
+		t.Fatalf("removing gone.txt: %v", err)
+	}
+	after := &GitState{Hashes: map[string]FileHash{}}
+
+	patches, err := DiffPatches(before, after, dir)
+	if err != nil {
+		t.Fatalf("DiffPatches: %v", err)
+	}
+	if len(patches) != 1 {
+		t.Fatalf("expected 1 patch, got %d", len(patches))
+	}
+	p := patches[0]
+	if p.NewHash != "" {
+		t.Errorf("expected empty NewHash for a deleted file, got %q", p.NewHash)
+	}
+	if p.Dels != 1 || p.Adds != 0 {
+		t.Errorf("Adds/Dels = %d/%d, want 0/1", p.Adds, p.Dels)
+	}
+	if !strings.Contains(p.Patch, "deleted file mode") {
+		t.Errorf("expected a deleted-file patch, got:\n%s", p.Patch)
+	}
+}
+
+func TestDiffPatches_PointerFileGetsPlaceholder(t *testing.T) {
+	dir := t.TempDir()
+	before := &GitState{Hashes: map[string]FileHash{"big.bin": {Hash: "sha256:aaa", Pointer: true}}}
+	after := &GitState{Hashes: map[string]FileHash{"big.bin": {Hash: "sha256:bbb", Pointer: true}}}
+
+	patches, err := DiffPatches(before, after, dir)
+	if err != nil {
+		t.Fatalf("DiffPatches: %v", err)
+	}
+	if len(patches) != 1 {
+		t.Fatalf("expected 1 patch, got %d: %v", len(patches), patches)
+	}
+	p := patches[0]
+	if !p.Pointer {
+		t.Errorf("expected Pointer to be set on the placeholder patch")
+	}
+	if !strings.Contains(p.Patch, "big.bin") {
+		t.Errorf("expected placeholder patch to mention the file, got: %q", p.Patch)
+	}
+}
+
+func TestDiffPatches_UnchangedFileSkipped(t *testing.T) {
+	dir := t.TempDir()
+	state := captureState(t, dir, "same.txt", "unchanged\n")
+
+	patches, err := DiffPatches(state, state, dir)
+	if err != nil {
+		t.Fatalf("DiffPatches: %v", err)
+	}
+	if len(patches) != 0 {
+		t.Errorf("expected no patches for an unchanged file, got %d: %v", len(patches), patches)
+	}
+}
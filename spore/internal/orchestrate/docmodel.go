@@ -0,0 +1,157 @@
+package orchestrate
+
+import "strings"
+
+// Block is one element of a task file, format-agnostic -- implemented by
+// Heading, Paragraph, CodeBlock, BulletList, Table, and Checklist. Modeled
+// on go/doc/comment's split of its parse tree from its renderers: building
+// the Document once and handing it to whichever Renderer the caller wants
+// (Markdown, HTML, JSON) means the traversal that assembles a task file's
+// sections only has to be written once.
+type Block interface {
+	block()
+}
+
+// Heading is a section header ("## Graph Context"). ID is a stable anchor
+// slug the HTML renderer uses for in-page links and its table of contents;
+// the Markdown renderer ignores it since GitHub already slugs headings
+// itself.
+type Heading struct {
+	Level int
+	Text  string
+	ID    string
+}
+
+// Paragraph is a run of prose. Text may contain simple inline markdown
+// (bold via **, code spans via backticks); renderers that aren't Markdown
+// translate it (see inlineMarkdownToHTML) rather than emitting it verbatim.
+type Paragraph struct {
+	Text string
+}
+
+// CodeBlock is a fenced snippet. Lang feeds Markdown's fence info-string and
+// HTML's `language-<Lang>` class for Prism/Highlight.js.
+type CodeBlock struct {
+	Lang  string
+	Lines []string
+}
+
+// BulletList is an unordered list of items, each allowed the same inline
+// markdown as Paragraph.Text.
+type BulletList struct {
+	Items []string
+}
+
+// Table is a rendered grid -- the Graph Context table today, any future
+// tabular section tomorrow. Cells are pre-formatted strings (already
+// percent-signed, truncated, etc.) rather than typed fields, since each
+// table's columns differ.
+type Table struct {
+	Headers []string
+	Rows    [][]string
+}
+
+// ChecklistItem is one line of a Checklist. Detail, if non-empty, renders as
+// an indented second line underneath Text (used for a lesson's "Fix:" note).
+type ChecklistItem struct {
+	Text    string
+	Detail  string
+	Checked bool
+}
+
+// Checklist is a list of actionable items, rendered as Markdown task-list
+// checkboxes.
+type Checklist struct {
+	Items []ChecklistItem
+}
+
+func (Heading) block()    {}
+func (Paragraph) block()  {}
+func (CodeBlock) block()  {}
+func (BulletList) block() {}
+func (Table) block()      {}
+func (Checklist) block()  {}
+
+// Document is a full task file as an intermediate tree, independent of
+// output format. Built once by buildDocument, then walked by a Renderer.
+type Document struct {
+	Blocks []Block
+}
+
+// Renderer turns a Document into its final output string. MarkdownRenderer
+// is the default (and the only one GenerateTaskFile writes to disk today);
+// HTMLRenderer and JSONRenderer serve downstream tooling and server-side
+// rendering that want the same traversal without re-implementing it.
+type Renderer interface {
+	Render(doc *Document) string
+}
+
+// docBuilder accumulates Blocks. Its method names mirror the
+// strings.Builder calls it replaces (heading/para/code/list/table/
+// checklist instead of md.WriteString("## ...")), so the functions that
+// used to write Markdown directly read almost the same as before.
+type docBuilder struct {
+	doc Document
+}
+
+func (b *docBuilder) heading(level int, text string) {
+	b.doc.Blocks = append(b.doc.Blocks, Heading{Level: level, Text: text, ID: slugify(text)})
+}
+
+func (b *docBuilder) para(text string) {
+	b.doc.Blocks = append(b.doc.Blocks, Paragraph{Text: text})
+}
+
+func (b *docBuilder) code(lang string, lines []string) {
+	b.doc.Blocks = append(b.doc.Blocks, CodeBlock{Lang: lang, Lines: lines})
+}
+
+func (b *docBuilder) list(items []string) {
+	if len(items) == 0 {
+		return
+	}
+	b.doc.Blocks = append(b.doc.Blocks, BulletList{Items: items})
+}
+
+func (b *docBuilder) table(headers []string, rows [][]string) {
+	b.doc.Blocks = append(b.doc.Blocks, Table{Headers: headers, Rows: rows})
+}
+
+func (b *docBuilder) checklist(items []ChecklistItem) {
+	b.doc.Blocks = append(b.doc.Blocks, Checklist{Items: items})
+}
+
+// rendererForFormat resolves a TaskFileConfig.TaskFileFormat value to its
+// Renderer and output file extension. Unknown/empty formats fall back to
+// Markdown, the long-standing default, rather than erroring -- a typo'd
+// --task-file-format shouldn't break task file generation.
+func rendererForFormat(format string) (Renderer, string) {
+	switch strings.ToLower(format) {
+	case "html":
+		return HTMLRenderer{}, "html"
+	case "json":
+		return JSONRenderer{}, "json"
+	default:
+		return MarkdownRenderer{}, "md"
+	}
+}
+
+// slugify turns heading text into a stable anchor ID: lowercase,
+// alphanumerics kept, everything else collapsed to a single hyphen.
+func slugify(text string) string {
+	var sb strings.Builder
+	lastHyphen := true // collapses a leading run of non-alnum instead of leaving a leading "-"
+	for _, r := range strings.ToLower(text) {
+		switch {
+		case r >= 'a' && r <= 'z' || r >= '0' && r <= '9':
+			sb.WriteRune(r)
+			lastHyphen = false
+		default:
+			if !lastHyphen {
+				sb.WriteByte('-')
+				lastHyphen = true
+			}
+		}
+	}
+	return strings.Trim(sb.String(), "-")
+}
@@ -0,0 +1,111 @@
+package orchestrate
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+// HTMLRenderer renders a Document as a standalone HTML fragment: headings
+// carry stable id anchors (see slugify), code blocks get a
+// `language-<Lang>` class for Prism/Highlight.js to pick up client-side, and
+// the fragment opens with a table of contents built from every Heading.
+//
+// This repo has no Markdown-to-HTML library dependency, so inline
+// formatting support is intentionally narrow: inlineMarkdownToHTML handles
+// only the bold (**) and code-span (backtick) markup the Document's own
+// builders emit (see docBuilder), not arbitrary Markdown.
+type HTMLRenderer struct{}
+
+func (HTMLRenderer) Render(doc *Document) string {
+	var out strings.Builder
+	if toc := renderTOC(doc); toc != "" {
+		out.WriteString(toc)
+	}
+	for _, blk := range doc.Blocks {
+		switch b := blk.(type) {
+		case Heading:
+			out.WriteString(fmt.Sprintf("<h%d id=%q>%s</h%d>\n", b.Level, b.ID, html.EscapeString(b.Text), b.Level))
+		case Paragraph:
+			out.WriteString(fmt.Sprintf("<p>%s</p>\n", inlineMarkdownToHTML(b.Text)))
+		case CodeBlock:
+			class := ""
+			if b.Lang != "" {
+				class = fmt.Sprintf(" class=\"language-%s\"", html.EscapeString(b.Lang))
+			}
+			out.WriteString(fmt.Sprintf("<pre><code%s>%s</code></pre>\n", class, html.EscapeString(strings.Join(b.Lines, "\n"))))
+		case BulletList:
+			out.WriteString("<ul>\n")
+			for _, item := range b.Items {
+				out.WriteString(fmt.Sprintf("<li>%s</li>\n", inlineMarkdownToHTML(item)))
+			}
+			out.WriteString("</ul>\n")
+		case Table:
+			out.WriteString("<table>\n<thead><tr>")
+			for _, h := range b.Headers {
+				out.WriteString(fmt.Sprintf("<th>%s</th>", html.EscapeString(h)))
+			}
+			out.WriteString("</tr></thead>\n<tbody>\n")
+			for _, row := range b.Rows {
+				out.WriteString("<tr>")
+				for _, cell := range row {
+					out.WriteString(fmt.Sprintf("<td>%s</td>", inlineMarkdownToHTML(cell)))
+				}
+				out.WriteString("</tr>\n")
+			}
+			out.WriteString("</tbody>\n</table>\n")
+		case Checklist:
+			out.WriteString("<ul class=\"checklist\">\n")
+			for _, item := range b.Items {
+				checked := ""
+				if item.Checked {
+					checked = " checked"
+				}
+				out.WriteString(fmt.Sprintf("<li><input type=\"checkbox\" disabled%s> %s", checked, inlineMarkdownToHTML(item.Text)))
+				if item.Detail != "" {
+					out.WriteString(fmt.Sprintf("<br><span class=\"detail\">%s</span>", inlineMarkdownToHTML(item.Detail)))
+				}
+				out.WriteString("</li>\n")
+			}
+			out.WriteString("</ul>\n")
+		}
+	}
+	return out.String()
+}
+
+// renderTOC builds a "<nav>" table of contents linking to every Heading's
+// anchor ID, or "" if the document has no headings.
+func renderTOC(doc *Document) string {
+	var headings []Heading
+	for _, blk := range doc.Blocks {
+		if h, ok := blk.(Heading); ok {
+			headings = append(headings, h)
+		}
+	}
+	if len(headings) == 0 {
+		return ""
+	}
+	var out strings.Builder
+	out.WriteString("<nav class=\"toc\">\n<ul>\n")
+	for _, h := range headings {
+		out.WriteString(fmt.Sprintf("<li class=\"toc-level-%d\"><a href=\"#%s\">%s</a></li>\n", h.Level, h.ID, html.EscapeString(h.Text)))
+	}
+	out.WriteString("</ul>\n</nav>\n")
+	return out.String()
+}
+
+var (
+	inlineBoldPattern = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	inlineCodePattern = regexp.MustCompile("`([^`]+)`")
+)
+
+// inlineMarkdownToHTML escapes text as HTML, then converts the two inline
+// markdown spans the Document's own builders produce. It is not a general
+// Markdown-to-HTML converter -- see HTMLRenderer's doc comment.
+func inlineMarkdownToHTML(text string) string {
+	escaped := html.EscapeString(text)
+	escaped = inlineBoldPattern.ReplaceAllString(escaped, "<strong>$1</strong>")
+	escaped = inlineCodePattern.ReplaceAllString(escaped, "<code>$1</code>")
+	return escaped
+}
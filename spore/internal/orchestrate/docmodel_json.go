@@ -0,0 +1,79 @@
+package orchestrate
+
+import "encoding/json"
+
+// JSONRenderer renders a Document as JSON for downstream tooling that wants
+// the task file's structure (sections, tables, code blocks) rather than a
+// pre-formatted string to parse back apart.
+type JSONRenderer struct{}
+
+// jsonBlock is the wire shape for one Block: Kind discriminates which of
+// the other fields are populated, since encoding/json can't marshal the
+// Block interface directly.
+type jsonBlock struct {
+	Kind string `json:"kind"`
+
+	// Heading
+	Level int    `json:"level,omitempty"`
+	Text  string `json:"text,omitempty"`
+	ID    string `json:"id,omitempty"`
+
+	// CodeBlock
+	Lang  string   `json:"lang,omitempty"`
+	Lines []string `json:"lines,omitempty"`
+
+	// BulletList
+	Items []string `json:"items,omitempty"`
+
+	// Table
+	Headers []string   `json:"headers,omitempty"`
+	Rows    [][]string `json:"rows,omitempty"`
+
+	// Checklist
+	Checklist []jsonChecklistItem `json:"checklist,omitempty"`
+}
+
+type jsonChecklistItem struct {
+	Text    string `json:"text"`
+	Detail  string `json:"detail,omitempty"`
+	Checked bool   `json:"checked"`
+}
+
+type jsonDocument struct {
+	Blocks []jsonBlock `json:"blocks"`
+}
+
+func toJSONBlock(blk Block) jsonBlock {
+	switch b := blk.(type) {
+	case Heading:
+		return jsonBlock{Kind: "heading", Level: b.Level, Text: b.Text, ID: b.ID}
+	case Paragraph:
+		return jsonBlock{Kind: "paragraph", Text: b.Text}
+	case CodeBlock:
+		return jsonBlock{Kind: "code_block", Lang: b.Lang, Lines: b.Lines}
+	case BulletList:
+		return jsonBlock{Kind: "bullet_list", Items: b.Items}
+	case Table:
+		return jsonBlock{Kind: "table", Headers: b.Headers, Rows: b.Rows}
+	case Checklist:
+		items := make([]jsonChecklistItem, len(b.Items))
+		for i, item := range b.Items {
+			items[i] = jsonChecklistItem{Text: item.Text, Detail: item.Detail, Checked: item.Checked}
+		}
+		return jsonBlock{Kind: "checklist", Checklist: items}
+	default:
+		return jsonBlock{Kind: "unknown"}
+	}
+}
+
+func (JSONRenderer) Render(doc *Document) string {
+	out := jsonDocument{Blocks: make([]jsonBlock, len(doc.Blocks))}
+	for i, blk := range doc.Blocks {
+		out.Blocks[i] = toJSONBlock(blk)
+	}
+	b, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return "{}"
+	}
+	return string(b)
+}
@@ -0,0 +1,71 @@
+package orchestrate
+
+import (
+	"fmt"
+	"strings"
+)
+
+// MarkdownRenderer renders a Document back into the same GitHub-flavored
+// Markdown renderMarkdown always produced, byte-for-byte, so existing
+// callers (task files written to disk, the test suite's substring checks)
+// see no difference from the pre-Document-model output.
+type MarkdownRenderer struct{}
+
+func (MarkdownRenderer) Render(doc *Document) string {
+	var md strings.Builder
+	for _, blk := range doc.Blocks {
+		switch b := blk.(type) {
+		case Heading:
+			md.WriteString(strings.Repeat("#", b.Level))
+			md.WriteString(" ")
+			md.WriteString(b.Text)
+			md.WriteString("\n\n")
+		case Paragraph:
+			md.WriteString(b.Text)
+			md.WriteString("\n\n")
+		case CodeBlock:
+			md.WriteString(fmt.Sprintf("```%s\n", b.Lang))
+			for _, line := range b.Lines {
+				md.WriteString(line)
+				md.WriteString("\n")
+			}
+			md.WriteString("```\n\n")
+		case BulletList:
+			for _, item := range b.Items {
+				md.WriteString("- ")
+				md.WriteString(item)
+				md.WriteString("\n")
+			}
+			md.WriteString("\n")
+		case Table:
+			md.WriteString("| ")
+			md.WriteString(strings.Join(b.Headers, " | "))
+			md.WriteString(" |\n|")
+			for range b.Headers {
+				md.WriteString("---|")
+			}
+			md.WriteString("\n")
+			for _, row := range b.Rows {
+				md.WriteString("| ")
+				md.WriteString(strings.Join(row, " | "))
+				md.WriteString(" |\n")
+			}
+			md.WriteString("\n")
+		case Checklist:
+			for _, item := range b.Items {
+				box := " "
+				if item.Checked {
+					box = "x"
+				}
+				md.WriteString(fmt.Sprintf("- [%s] %s\n", box, item.Text))
+				if item.Detail != "" {
+					md.WriteString("  ")
+					md.WriteString(item.Detail)
+					md.WriteString("\n")
+				}
+			}
+			md.WriteString("\n")
+		}
+	}
+	return md.String()
+}
@@ -0,0 +1,109 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+)
+
+func testDocument() *Document {
+	b := &docBuilder{}
+	b.heading(1, "Task: demo")
+	b.para("Some **bold** and `code` text.")
+	b.code("go", []string{"func main() {}"})
+	b.list([]string{"first item", "second item"})
+	b.table([]string{"#", "Node"}, [][]string{{"1", "Foo"}})
+	b.checklist([]ChecklistItem{{Text: "do the thing"}, {Text: "checked", Checked: true, Detail: "a detail"}})
+	return &b.doc
+}
+
+func TestMarkdownRenderer_MatchesOriginalShape(t *testing.T) {
+	md := MarkdownRenderer{}.Render(testDocument())
+	for _, want := range []string{
+		"# Task: demo",
+		"Some **bold** and `code` text.",
+		"```go\nfunc main() {}\n```",
+		"- first item",
+		"| # | Node |",
+		"| 1 | Foo |",
+		"- [ ] do the thing",
+		"- [x] checked",
+		"a detail",
+	} {
+		if !strings.Contains(md, want) {
+			t.Errorf("markdown output missing %q:\n%s", want, md)
+		}
+	}
+}
+
+func TestHTMLRenderer_EscapesAndLinksHeadings(t *testing.T) {
+	out := HTMLRenderer{}.Render(testDocument())
+	if !strings.Contains(out, `<h1 id="task-demo">`) {
+		t.Errorf("expected slugged heading anchor, got:\n%s", out)
+	}
+	if !strings.Contains(out, `<a href="#task-demo">`) {
+		t.Errorf("expected table of contents to link the heading, got:\n%s", out)
+	}
+	if !strings.Contains(out, "<strong>bold</strong>") || !strings.Contains(out, "<code>code</code>") {
+		t.Errorf("expected inline bold/code spans to be translated, got:\n%s", out)
+	}
+	if !strings.Contains(out, `class="language-go"`) {
+		t.Errorf("expected code block to carry a language-go class, got:\n%s", out)
+	}
+	if !strings.Contains(out, `<input type="checkbox" disabled checked>`) {
+		t.Errorf("expected a checked checklist item, got:\n%s", out)
+	}
+}
+
+func TestJSONRenderer_RoundTrips(t *testing.T) {
+	out := JSONRenderer{}.Render(testDocument())
+	var parsed jsonDocument
+	if err := json.Unmarshal([]byte(out), &parsed); err != nil {
+		t.Fatalf("JSON output didn't parse: %v\n%s", err, out)
+	}
+	if len(parsed.Blocks) != 6 {
+		t.Fatalf("expected 6 blocks, got %d", len(parsed.Blocks))
+	}
+	if parsed.Blocks[0].Kind != "heading" || parsed.Blocks[0].Text != "Task: demo" {
+		t.Errorf("expected first block to be the heading, got %+v", parsed.Blocks[0])
+	}
+	if parsed.Blocks[2].Kind != "code_block" || parsed.Blocks[2].Lang != "go" {
+		t.Errorf("expected third block to be the go code block, got %+v", parsed.Blocks[2])
+	}
+}
+
+func TestSlugify(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"Task: demo", "task-demo"},
+		{"Graph Context", "graph-context"},
+		{"  leading/trailing  ", "leading-trailing"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := slugify(tt.text); got != tt.want {
+			t.Errorf("slugify(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestRendererForFormat(t *testing.T) {
+	tests := []struct {
+		format  string
+		wantExt string
+	}{
+		{"", "md"},
+		{"markdown", "md"},
+		{"HTML", "html"},
+		{"json", "json"},
+		{"bogus", "md"},
+	}
+	for _, tt := range tests {
+		_, ext := rendererForFormat(tt.format)
+		if ext != tt.wantExt {
+			t.Errorf("rendererForFormat(%q) ext = %q, want %q", tt.format, ext, tt.wantExt)
+		}
+	}
+}
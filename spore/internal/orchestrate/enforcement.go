@@ -0,0 +1,131 @@
+package orchestrate
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// EnforcementAction is the action to take when an EnforcementRule matches.
+type EnforcementAction string
+
+const (
+	ActionDeny   EnforcementAction = "deny"   // skip task, mark failed
+	ActionWarn   EnforcementAction = "warn"   // log and proceed as if rule didn't exist
+	ActionDryRun EnforcementAction = "dryrun" // execute but don't persist edges/commit
+	ActionBounce EnforcementAction = "bounce" // existing bounce-loop behavior
+	ActionPause  EnforcementAction = "pause"  // halt the loop
+)
+
+// EnforcementRule is one scoped enforcement action, modeled after
+// Gatekeeper's scoped-enforcement-actions: a stage/trigger pair mapped to an
+// action, optionally narrowed to a specific experiment label, task tag, or
+// agent role so a warn-only shadow experiment can run alongside enforcing
+// production tasks in the same `loop` invocation.
+type EnforcementRule struct {
+	Stage  string            `yaml:"stage"` // "verifier", "summarizer", "commit", ...
+	On     string            `yaml:"on"`    // "contradicts", "escalation", ...
+	Action EnforcementAction `yaml:"action"`
+
+	// Scope narrows which tasks this rule applies to. Empty fields match anything.
+	Experiment string    `yaml:"experiment,omitempty"`
+	Tag        string    `yaml:"tag,omitempty"`
+	Role       AgentRole `yaml:"role,omitempty"`
+}
+
+// EnforcementPolicy is an ordered set of EnforcementRules. The first rule
+// whose stage/on/scope match wins; if none match, the policy falls back to
+// the legacy flag-derived default for that stage/trigger.
+type EnforcementPolicy struct {
+	Rules []EnforcementRule
+}
+
+// enforcementScope is the task-specific context a rule's scope is matched against.
+type enforcementScope struct {
+	Experiment string
+	Tags       []string
+	Role       AgentRole
+}
+
+func (p *EnforcementPolicy) matches(rule EnforcementRule, scope enforcementScope) bool {
+	if rule.Experiment != "" && rule.Experiment != scope.Experiment {
+		return false
+	}
+	if rule.Role != "" && rule.Role != scope.Role {
+		return false
+	}
+	if rule.Tag != "" {
+		found := false
+		for _, t := range scope.Tags {
+			if t == rule.Tag {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// Resolve returns the action for (stage, on) given scope, falling back to
+// def (the legacy flag-derived default) if no rule matches.
+func (p *EnforcementPolicy) Resolve(stage, on string, scope enforcementScope, def EnforcementAction) EnforcementAction {
+	if p == nil {
+		return def
+	}
+	for _, rule := range p.Rules {
+		if rule.Stage == stage && rule.On == on && p.matches(rule, scope) {
+			return rule.Action
+		}
+	}
+	return def
+}
+
+// DefaultEnforcementPolicy derives an EnforcementPolicy purely from the
+// legacy flags, so existing OrchestrationConfig/LoopConfig behavior is
+// reproduced when no explicit policy is set.
+func DefaultEnforcementPolicy(maxBounces int, pauseOnEscalation, autoCommit bool) *EnforcementPolicy {
+	var rules []EnforcementRule
+	if maxBounces > 0 {
+		rules = append(rules, EnforcementRule{Stage: "verifier", On: "contradicts", Action: ActionBounce})
+	}
+	if pauseOnEscalation {
+		rules = append(rules, EnforcementRule{Stage: "summarizer", On: "escalation", Action: ActionPause})
+	}
+	commitAction := ActionBounce
+	if !autoCommit {
+		commitAction = ActionDryRun
+	}
+	rules = append(rules, EnforcementRule{Stage: "commit", On: "contradicts", Action: commitAction})
+	return &EnforcementPolicy{Rules: rules}
+}
+
+// enforcementFile is the on-disk YAML shape for --enforcement-policy files:
+//
+//	rules:
+//	  - stage: verifier
+//	    on: contradicts
+//	    action: bounce
+//	  - stage: summarizer
+//	    on: escalation
+//	    action: pause
+//	    experiment: canary
+type enforcementFile struct {
+	Rules []EnforcementRule `yaml:"rules"`
+}
+
+// LoadEnforcementPolicy reads a YAML file of EnforcementRules from path.
+func LoadEnforcementPolicy(path string) (*EnforcementPolicy, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading enforcement policy: %w", err)
+	}
+	var f enforcementFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parsing enforcement policy: %w", err)
+	}
+	return &EnforcementPolicy{Rules: f.Rules}, nil
+}
@@ -1,16 +1,223 @@
 package orchestrate
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"fmt"
+	"io"
+	"os"
 	"os/exec"
 	"path/filepath"
 	"sort"
 	"strings"
+	"sync"
+
+	"github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
 )
 
+// openRepos caches an opened *git.Repository per cleaned absolute repo
+// path, so the before/after CaptureGitState calls bracketing one agent run
+// reuse the same parsed .git metadata and worktree excludes instead of
+// reopening the repo from scratch each time.
+var openRepos sync.Map // map[string]*git.Repository
+
+func openRepo(repoDir string) (*git.Repository, error) {
+	abs, err := filepath.Abs(repoDir)
+	if err != nil {
+		return nil, err
+	}
+	if cached, ok := openRepos.Load(abs); ok {
+		return cached.(*git.Repository), nil
+	}
+	repo, err := git.PlainOpen(abs)
+	if err != nil {
+		return nil, err
+	}
+	openRepos.Store(abs, repo)
+	return repo, nil
+}
+
 // CaptureGitState snapshots the current repository state: branch, commit, dirty files,
 // untracked files, and content hashes. Used before/after agent runs to detect changes.
+//
+// Captured in-process via go-git rather than shelling out to git, so a
+// large change set doesn't pay one fork+exec per file hashed. Falls back to
+// the exec-based path when go-git can't open or read the repo at all (e.g.
+// a repo format or feature this go-git version doesn't support), so
+// CaptureGitState keeps working anywhere a git binary does.
 func CaptureGitState(repoDir string) (*GitState, error) {
+	return CaptureGitStateWithOpts(repoDir, GitStateOpts{})
+}
+
+// CaptureGitStateWithOpts is CaptureGitState with a configurable
+// HashSizeThreshold (see GitStateOpts and FileHash).
+func CaptureGitStateWithOpts(repoDir string, opts GitStateOpts) (*GitState, error) {
+	state, err := captureGitStateGoGit(repoDir, opts)
+	if err == nil {
+		return state, nil
+	}
+	return captureGitStateExec(repoDir, opts)
+}
+
+func captureGitStateGoGit(repoDir string, opts GitStateOpts) (*GitState, error) {
+	repo, err := openRepo(repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("opening repo: %w", err)
+	}
+
+	head, err := repo.Head()
+	if err != nil {
+		return nil, fmt.Errorf("resolving HEAD: %w", err)
+	}
+	branch := "HEAD" // detached HEAD, matching `git rev-parse --abbrev-ref HEAD`
+	if head.Name().IsBranch() {
+		branch = head.Name().Short()
+	}
+	commit := head.Hash().String()[:7]
+
+	wt, err := repo.Worktree()
+	if err != nil {
+		return nil, fmt.Errorf("opening worktree: %w", err)
+	}
+	// Repository.Worktree() doesn't load .gitignore on its own; without
+	// this, Status would report every ignored file (build artifacts,
+	// node_modules, ...) as untracked.
+	if patterns, err := gitignore.ReadPatterns(wt.Filesystem, nil); err == nil {
+		wt.Excludes = append(wt.Excludes, patterns...)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return nil, fmt.Errorf("getting worktree status: %w", err)
+	}
+
+	// TrackingFilter layers .mycelica-ignore and the mycelica-track
+	// gitattribute on top of .gitignore, so generated files a plain
+	// .gitignore doesn't catch (or that users want to positively opt
+	// in/out of per subtree) never enter Dirty, Untracked, or Hashes. A
+	// filter that fails to build (no repo root readable as a filesystem)
+	// falls back to tracking everything Status reported, matching the
+	// old behavior.
+	filter, filterErr := NewTrackingFilter(wt.Filesystem)
+
+	dirty := make(map[string]bool)
+	untracked := make(map[string]bool)
+	hashSet := make(map[string]bool, len(status))
+	for path, fileStatus := range status {
+		if filterErr == nil && !filter.Tracked(path) {
+			continue
+		}
+		if fileStatus.Staging == git.Untracked && fileStatus.Worktree == git.Untracked {
+			untracked[path] = true
+			hashSet[path] = true
+			continue
+		}
+		if fileStatus.Staging != git.Unmodified || fileStatus.Worktree != git.Unmodified {
+			dirty[path] = true
+			hashSet[path] = true
+		}
+	}
+
+	return &GitState{
+		Branch:    branch,
+		Commit:    commit,
+		Dirty:     dirty,
+		Untracked: untracked,
+		Hashes:    CaptureFileHashesWithOpts(repoDir, hashSet, opts),
+	}, nil
+}
+
+// CaptureFileHashes computes git blob hashes for a set of files, in-process
+// via plumbing.ComputeHash rather than shelling out to `git hash-object`
+// once per file. Files that fail to hash (deleted, inaccessible) are
+// silently skipped. Each file's content is cached by its hash in
+// blobContentCache so DiffPatches can diff it later without rereading the
+// working tree, which may have moved on by the time a patch is requested.
+//
+// Uses the default HashSizeThreshold; see CaptureFileHashesWithOpts to
+// configure it.
+func CaptureFileHashes(repoDir string, files map[string]bool) map[string]FileHash {
+	return CaptureFileHashesWithOpts(repoDir, files, GitStateOpts{})
+}
+
+// CaptureFileHashesWithOpts is CaptureFileHashes with a configurable
+// HashSizeThreshold. A file whose size exceeds the threshold is never read
+// into memory in full -- it gets a streaming pointerHash instead, and its
+// content is not added to blobContentCache, so DiffPatches falls back to a
+// placeholder patch for it (see pointerFilePatch).
+func CaptureFileHashesWithOpts(repoDir string, files map[string]bool, opts GitStateOpts) map[string]FileHash {
+	threshold := opts.threshold()
+	hashes := make(map[string]FileHash, len(files))
+	for f := range files {
+		absPath := filepath.Join(repoDir, f)
+		info, err := os.Stat(absPath)
+		if err != nil {
+			continue // file deleted or inaccessible
+		}
+		if info.Size() > threshold {
+			hash, err := pointerHash(absPath, info.Size())
+			if err != nil {
+				continue
+			}
+			hashes[f] = FileHash{Hash: hash, Pointer: true}
+			continue
+		}
+		content, err := os.ReadFile(absPath)
+		if err != nil {
+			continue // file deleted or inaccessible
+		}
+		hash := plumbing.ComputeHash(plumbing.BlobObject, content).String()
+		hashes[f] = FileHash{Hash: hash}
+		blobContentCache.Store(hash, content)
+	}
+	return hashes
+}
+
+// pointerSampleSize is how many bytes pointerHash reads from each end of a
+// large file.
+const pointerSampleSize = 64 * 1024
+
+// pointerHash is an LFS-pointer-style stand-in for a full content hash on
+// files too large to read in full: sha256 of the file's size plus its first
+// and last pointerSampleSize bytes, read via streaming so the whole file
+// never has to fit in memory. Cheap to compute and still catches the
+// overwhelming majority of real changes to a large file.
+func pointerHash(path string, size int64) (string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := sha256.New()
+	fmt.Fprintf(h, "%d:", size)
+
+	head := make([]byte, pointerSampleSize)
+	n, err := io.ReadFull(f, head)
+	if err != nil && err != io.ErrUnexpectedEOF {
+		return "", err
+	}
+	h.Write(head[:n])
+
+	if tailStart := size - pointerSampleSize; tailStart > int64(n) {
+		if _, err := f.Seek(tailStart, io.SeekStart); err != nil {
+			return "", err
+		}
+		tail, err := io.ReadAll(f)
+		if err != nil {
+			return "", err
+		}
+		h.Write(tail)
+	}
+
+	return "sha256:" + hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// captureGitStateExec is CaptureGitState's pre-go-git implementation,
+// kept as the fallback for repos go-git can't open or read.
+func captureGitStateExec(repoDir string, opts GitStateOpts) (*GitState, error) {
 	branch, err := gitOutput(repoDir, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return nil, fmt.Errorf("git rev-parse --abbrev-ref HEAD: %w", err)
@@ -55,29 +262,40 @@ func CaptureGitState(repoDir string) (*GitState, error) {
 		hashSet[f] = true
 	}
 
-	hashes := CaptureFileHashes(repoDir, hashSet)
-
 	return &GitState{
 		Branch:    branch,
 		Commit:    commit,
 		Dirty:     dirty,
 		Untracked: untracked,
-		Hashes:    hashes,
+		Hashes:    captureFileHashesExec(repoDir, hashSet, opts),
 	}, nil
 }
 
-// CaptureFileHashes computes git content hashes for a set of files.
-// Files that fail to hash (deleted, inaccessible) are silently skipped.
-func CaptureFileHashes(repoDir string, files map[string]bool) map[string]string {
-	hashes := make(map[string]string, len(files))
+// captureFileHashesExec is CaptureFileHashes' pre-go-git implementation,
+// used only by captureGitStateExec.
+func captureFileHashesExec(repoDir string, files map[string]bool, opts GitStateOpts) map[string]FileHash {
+	threshold := opts.threshold()
+	hashes := make(map[string]FileHash, len(files))
 	for f := range files {
 		absPath := filepath.Join(repoDir, f)
+		info, err := os.Stat(absPath)
+		if err != nil {
+			continue // file deleted or inaccessible
+		}
+		if info.Size() > threshold {
+			hash, err := pointerHash(absPath, info.Size())
+			if err != nil {
+				continue
+			}
+			hashes[f] = FileHash{Hash: hash, Pointer: true}
+			continue
+		}
 		hash, err := gitOutput(repoDir, "hash-object", absPath)
 		if err != nil {
 			continue // file deleted or inaccessible
 		}
 		if hash != "" {
-			hashes[f] = hash
+			hashes[f] = FileHash{Hash: hash}
 		}
 	}
 	return hashes
@@ -103,7 +321,12 @@ func DiffChangedFiles(before, after *GitState) []string {
 		}
 	}
 
-	// Files whose hash changed
+	// Files whose hash changed. FileHash's equality already covers
+	// pointer-vs-pointer, pointer-vs-full, and full-vs-full comparisons
+	// correctly: any difference in Hash or Pointer counts as changed, which
+	// includes a pointer->full transition (the recomputed Hash uses a
+	// different scheme, so it never accidentally matches the old pointer
+	// hash).
 	for f, afterHash := range after.Hashes {
 		beforeHash, exists := before.Hashes[f]
 		if !exists || beforeHash != afterHash {
@@ -119,6 +342,118 @@ func DiffChangedFiles(before, after *GitState) []string {
 	return result
 }
 
+// WorktreeDir computes the isolated path for one best-of-N coder candidate
+// attempt (see OrchestrationConfig.Candidates): <workDir>/.spore/worktrees/<runID>-<bounce>-<candidate>.
+func WorktreeDir(workDir, runID string, bounce, candidate int) string {
+	return filepath.Join(workDir, ".spore", "worktrees", fmt.Sprintf("%s-%d-%d", runID, bounce, candidate))
+}
+
+// CreateWorktree adds a detached git worktree at dir, checked out from
+// workDir's current HEAD. No branch is created: candidates are throwaway and
+// the winner's changes are reapplied onto workDir via ApplyWorktreeDiff
+// rather than merged as a branch.
+func CreateWorktree(workDir, dir string) error {
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return fmt.Errorf("creating worktree parent dir: %w", err)
+	}
+	if _, err := gitOutput(workDir, "worktree", "add", "--detach", dir, "HEAD"); err != nil {
+		return fmt.Errorf("git worktree add %s: %w", dir, err)
+	}
+	return nil
+}
+
+// RemoveWorktree prunes a worktree created by CreateWorktree, discarding any
+// uncommitted changes inside it.
+func RemoveWorktree(workDir, dir string) error {
+	if _, err := gitOutput(workDir, "worktree", "remove", "--force", dir); err != nil {
+		return fmt.Errorf("git worktree remove %s: %w", dir, err)
+	}
+	return nil
+}
+
+// ApplyWorktreeDiff stages every change in worktreeDir (including new
+// untracked files) and applies the resulting patch on top of workDir. A
+// worktree with no changes is a no-op.
+func ApplyWorktreeDiff(workDir, worktreeDir string) error {
+	if _, err := gitOutput(worktreeDir, "add", "-A"); err != nil {
+		return fmt.Errorf("staging worktree changes: %w", err)
+	}
+
+	patch, err := gitDiffOutput(worktreeDir, "diff", "--cached", "HEAD")
+	if err != nil {
+		return fmt.Errorf("diffing worktree: %w", err)
+	}
+	if strings.TrimSpace(patch) == "" {
+		return nil
+	}
+
+	applyCmd := exec.Command("git", "apply", "--3way")
+	applyCmd.Dir = workDir
+	applyCmd.Stdin = strings.NewReader(patch)
+	if out, err := applyCmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("applying winning candidate's diff: %w (%s)", err, strings.TrimSpace(string(out)))
+	}
+	return nil
+}
+
+// BounceSnapshotRef names the ref RollbackBetweenBounces snapshots a
+// bounce's changes under (see SnapshotWorkingTree), so a user can
+// `git checkout` any historical attempt.
+func BounceSnapshotRef(runID string, bounce int) string {
+	return fmt.Sprintf("refs/spore/%s/bounce-%d", runID, bounce+1)
+}
+
+// SnapshotWorkingTree records repoDir's current index and worktree state as
+// a commit and points ref at it, without moving HEAD or touching the stash
+// list: `git stash create` builds exactly such a commit (parented on HEAD)
+// without applying or popping anything. A clean tree has nothing to stash,
+// so the ref is pointed at HEAD itself instead.
+func SnapshotWorkingTree(repoDir, ref string) (string, error) {
+	commit, err := gitOutput(repoDir, "stash", "create")
+	if err != nil {
+		return "", fmt.Errorf("git stash create: %w", err)
+	}
+	if commit == "" {
+		commit, err = gitOutput(repoDir, "rev-parse", "HEAD")
+		if err != nil {
+			return "", fmt.Errorf("git rev-parse HEAD: %w", err)
+		}
+	}
+	if _, err := gitOutput(repoDir, "update-ref", ref, commit); err != nil {
+		return "", fmt.Errorf("git update-ref %s: %w", ref, err)
+	}
+	return commit, nil
+}
+
+// ResetWorkingTree discards every uncommitted change and untracked file in
+// repoDir and resets it to ref (a commit-ish: a branch, a SHA, or a ref
+// created by SnapshotWorkingTree). Used between bounces, where the
+// discarded code is already preserved under a bounce snapshot ref.
+func ResetWorkingTree(repoDir, ref string) error {
+	if _, err := gitOutput(repoDir, "reset", "--hard", ref); err != nil {
+		return fmt.Errorf("git reset --hard %s: %w", ref, err)
+	}
+	if _, err := gitOutput(repoDir, "clean", "-fd"); err != nil {
+		return fmt.Errorf("git clean -fd: %w", err)
+	}
+	return nil
+}
+
+// StashAndResetToHEAD stashes any uncommitted changes in repoDir (so
+// they're recoverable from `git stash list` rather than destroyed outright)
+// before resetting it to head. Used by RollbackOnEscalation/
+// RollbackBetweenBounces to revert a run's changes back to its pre-run
+// state once it's no longer going to verify.
+func StashAndResetToHEAD(repoDir, head string) error {
+	if _, err := gitOutput(repoDir, "stash", "--include-untracked"); err != nil {
+		return fmt.Errorf("git stash: %w", err)
+	}
+	if _, err := gitOutput(repoDir, "reset", "--hard", head); err != nil {
+		return fmt.Errorf("git reset --hard %s: %w", head, err)
+	}
+	return nil
+}
+
 // gitOutput runs a git command in repoDir and returns trimmed stdout.
 func gitOutput(repoDir string, args ...string) (string, error) {
 	cmd := exec.Command("git", args...)
@@ -130,6 +465,19 @@ func gitOutput(repoDir string, args ...string) (string, error) {
 	return strings.TrimSpace(string(out)), nil
 }
 
+// gitDiffOutput runs a git command in repoDir and returns stdout untouched,
+// unlike gitOutput: a unified diff's trailing newline is significant and
+// `git apply` rejects a patch with it stripped off.
+func gitDiffOutput(repoDir string, args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = repoDir
+	out, err := cmd.Output()
+	if err != nil {
+		return "", err
+	}
+	return string(out), nil
+}
+
 // parseFileList splits newline-separated git output into a set of file paths.
 func parseFileList(output string) map[string]bool {
 	result := make(map[string]bool)
@@ -2,9 +2,35 @@ package orchestrate
 
 import (
 	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 	"testing"
 )
 
+// initTestRepo creates a throwaway git repo with one committed file, for
+// tests that need real worktree/diff operations rather than mocked GitState.
+func initTestRepo(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, out)
+		}
+	}
+	run("init")
+	run("config", "user.email", "test@example.com")
+	run("config", "user.name", "test")
+	if err := os.WriteFile(filepath.Join(dir, "base.txt"), []byte("base\n"), 0644); err != nil {
+		t.Fatalf("writing base file: %v", err)
+	}
+	run("add", "base.txt")
+	run("commit", "-m", "initial")
+	return dir
+}
+
 func TestCaptureGitState_InRepo(t *testing.T) {
 	// Use the actual Mycelica repo
 	repoDir := "/home/spore/Mycelica"
@@ -43,7 +69,7 @@ func TestDiffChangedFiles(t *testing.T) {
 		Commit:    "abc1234",
 		Dirty:     map[string]bool{"file_a.go": true},
 		Untracked: map[string]bool{"temp.txt": true},
-		Hashes:    map[string]string{"file_a.go": "aaa111", "file_b.go": "bbb222"},
+		Hashes:    map[string]FileHash{"file_a.go": {Hash: "aaa111"}, "file_b.go": {Hash: "bbb222"}},
 	}
 
 	after := &GitState{
@@ -51,7 +77,7 @@ func TestDiffChangedFiles(t *testing.T) {
 		Commit:    "def5678",
 		Dirty:     map[string]bool{"file_a.go": true, "file_c.go": true},
 		Untracked: map[string]bool{"temp.txt": true, "new.txt": true},
-		Hashes:    map[string]string{"file_a.go": "aaa111", "file_b.go": "ccc333", "file_c.go": "ddd444"},
+		Hashes:    map[string]FileHash{"file_a.go": {Hash: "aaa111"}, "file_b.go": {Hash: "ccc333"}, "file_c.go": {Hash: "ddd444"}},
 	}
 
 	changed := DiffChangedFiles(before, after)
@@ -85,7 +111,7 @@ func TestDiffChangedFiles_Empty(t *testing.T) {
 	state := &GitState{
 		Dirty:     map[string]bool{},
 		Untracked: map[string]bool{},
-		Hashes:    map[string]string{},
+		Hashes:    map[string]FileHash{},
 	}
 
 	changed := DiffChangedFiles(state, state)
@@ -94,6 +120,173 @@ func TestDiffChangedFiles_Empty(t *testing.T) {
 	}
 }
 
+func TestWorktreeDir(t *testing.T) {
+	got := WorktreeDir("/repo", "run-123", 2, 1)
+	want := filepath.Join("/repo", ".spore", "worktrees", "run-123-2-1")
+	if got != want {
+		t.Errorf("WorktreeDir = %q, want %q", got, want)
+	}
+}
+
+func TestCreateWorktree_ApplyDiff_RemoveWorktree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not in PATH")
+	}
+
+	repoDir := initTestRepo(t)
+	worktreeDir := WorktreeDir(repoDir, "run-1", 0, 0)
+
+	if err := CreateWorktree(repoDir, worktreeDir); err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+
+	// Modify a tracked file and add a new untracked one inside the worktree.
+	if err := os.WriteFile(filepath.Join(worktreeDir, "base.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatalf("modifying base.txt in worktree: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(worktreeDir, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatalf("writing new.txt in worktree: %v", err)
+	}
+
+	if err := ApplyWorktreeDiff(repoDir, worktreeDir); err != nil {
+		t.Fatalf("ApplyWorktreeDiff: %v", err)
+	}
+
+	changed, err := os.ReadFile(filepath.Join(repoDir, "base.txt"))
+	if err != nil {
+		t.Fatalf("reading base.txt in repoDir: %v", err)
+	}
+	if string(changed) != "changed\n" {
+		t.Errorf("base.txt = %q, want %q", changed, "changed\n")
+	}
+
+	added, err := os.ReadFile(filepath.Join(repoDir, "new.txt"))
+	if err != nil {
+		t.Fatalf("reading new.txt in repoDir: %v", err)
+	}
+	if string(added) != "new\n" {
+		t.Errorf("new.txt = %q, want %q", added, "new\n")
+	}
+
+	if err := RemoveWorktree(repoDir, worktreeDir); err != nil {
+		t.Fatalf("RemoveWorktree: %v", err)
+	}
+	if _, err := os.Stat(worktreeDir); !os.IsNotExist(err) {
+		t.Errorf("expected worktree dir to be removed, stat err = %v", err)
+	}
+}
+
+func TestApplyWorktreeDiff_NoChanges(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not in PATH")
+	}
+
+	repoDir := initTestRepo(t)
+	worktreeDir := WorktreeDir(repoDir, "run-2", 0, 0)
+
+	if err := CreateWorktree(repoDir, worktreeDir); err != nil {
+		t.Fatalf("CreateWorktree: %v", err)
+	}
+	defer RemoveWorktree(repoDir, worktreeDir)
+
+	if err := ApplyWorktreeDiff(repoDir, worktreeDir); err != nil {
+		t.Errorf("ApplyWorktreeDiff with no changes should be a no-op, got: %v", err)
+	}
+}
+
+func TestBounceSnapshotRef(t *testing.T) {
+	got := BounceSnapshotRef("run-1", 0)
+	want := "refs/spore/run-1/bounce-1"
+	if got != want {
+		t.Errorf("BounceSnapshotRef = %q, want %q", got, want)
+	}
+}
+
+func TestSnapshotWorkingTree_ResetWorkingTree(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not in PATH")
+	}
+
+	repoDir := initTestRepo(t)
+	preRunHEAD, err := gitOutput(repoDir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse HEAD: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "base.txt"), []byte("bounce-1\n"), 0644); err != nil {
+		t.Fatalf("modifying base.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatalf("writing new.txt: %v", err)
+	}
+
+	ref := BounceSnapshotRef("run-1", 0)
+	if _, err := SnapshotWorkingTree(repoDir, ref); err != nil {
+		t.Fatalf("SnapshotWorkingTree: %v", err)
+	}
+
+	if err := ResetWorkingTree(repoDir, preRunHEAD); err != nil {
+		t.Fatalf("ResetWorkingTree: %v", err)
+	}
+
+	base, err := os.ReadFile(filepath.Join(repoDir, "base.txt"))
+	if err != nil {
+		t.Fatalf("reading base.txt after reset: %v", err)
+	}
+	if string(base) != "base\n" {
+		t.Errorf("base.txt after reset = %q, want %q (original content)", base, "base\n")
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "new.txt")); !os.IsNotExist(err) {
+		t.Errorf("expected untracked new.txt to be cleaned by reset, stat err = %v", err)
+	}
+
+	// The snapshot ref should still resolve to the bounce's changes even
+	// though the working tree itself has moved on.
+	show, err := gitOutput(repoDir, "show", ref+":base.txt")
+	if err != nil {
+		t.Fatalf("git show %s:base.txt: %v", ref, err)
+	}
+	if show != "bounce-1" {
+		t.Errorf("snapshot ref content = %q, want %q", show, "bounce-1")
+	}
+}
+
+func TestStashAndResetToHEAD(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not in PATH")
+	}
+
+	repoDir := initTestRepo(t)
+	preRunHEAD, err := gitOutput(repoDir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse HEAD: %v", err)
+	}
+
+	if err := os.WriteFile(filepath.Join(repoDir, "base.txt"), []byte("broken\n"), 0644); err != nil {
+		t.Fatalf("modifying base.txt: %v", err)
+	}
+
+	if err := StashAndResetToHEAD(repoDir, preRunHEAD); err != nil {
+		t.Fatalf("StashAndResetToHEAD: %v", err)
+	}
+
+	base, err := os.ReadFile(filepath.Join(repoDir, "base.txt"))
+	if err != nil {
+		t.Fatalf("reading base.txt after rollback: %v", err)
+	}
+	if string(base) != "base\n" {
+		t.Errorf("base.txt after rollback = %q, want %q", base, "base\n")
+	}
+
+	stashList, err := gitOutput(repoDir, "stash", "list")
+	if err != nil {
+		t.Fatalf("git stash list: %v", err)
+	}
+	if stashList == "" {
+		t.Error("expected the discarded change to be recoverable from git stash list, got none")
+	}
+}
+
 func TestCaptureFileHashes_NonexistentFile(t *testing.T) {
 	repoDir := "/home/spore/Mycelica"
 	if _, err := os.Stat(repoDir + "/.git"); os.IsNotExist(err) {
@@ -109,3 +302,122 @@ func TestCaptureFileHashes_NonexistentFile(t *testing.T) {
 		t.Errorf("expected empty hashes for nonexistent file, got: %v", hashes)
 	}
 }
+
+// Files over GitStateOpts.HashSizeThreshold get a streaming pointerHash
+// instead of a full content hash, and never populate blobContentCache.
+func TestCaptureFileHashesWithOpts_LargeFileGetsPointerHash(t *testing.T) {
+	dir := t.TempDir()
+	big := strings.Repeat("x", 1024)
+	if err := os.WriteFile(filepath.Join(dir, "big.bin"), []byte(big), 0644); err != nil {
+		t.Fatalf("writing big.bin: %v", err)
+	}
+
+	hashes := CaptureFileHashesWithOpts(dir, map[string]bool{"big.bin": true}, GitStateOpts{HashSizeThreshold: 100})
+	fh, ok := hashes["big.bin"]
+	if !ok {
+		t.Fatalf("expected a hash for big.bin, got %v", hashes)
+	}
+	if !fh.Pointer {
+		t.Errorf("expected Pointer to be set for a file over the threshold")
+	}
+	if !strings.HasPrefix(fh.Hash, "sha256:") {
+		t.Errorf("expected a sha256: pointer hash, got %q", fh.Hash)
+	}
+	if _, cached := blobContentCache.Load(fh.Hash); cached {
+		t.Errorf("expected a pointer hash not to populate blobContentCache")
+	}
+}
+
+// Two pointer hashes of files differing only in the middle (within the
+// sampled head/tail) are unaffected, but any difference at the sampled ends
+// or in size must produce a different hash.
+func TestCaptureFileHashesWithOpts_PointerHashDetectsSizeChange(t *testing.T) {
+	dir := t.TempDir()
+	opts := GitStateOpts{HashSizeThreshold: 10}
+
+	if err := os.WriteFile(filepath.Join(dir, "f.bin"), []byte(strings.Repeat("a", 100)), 0644); err != nil {
+		t.Fatalf("writing f.bin: %v", err)
+	}
+	before := CaptureFileHashesWithOpts(dir, map[string]bool{"f.bin": true}, opts)
+
+	if err := os.WriteFile(filepath.Join(dir, "f.bin"), []byte(strings.Repeat("a", 200)), 0644); err != nil {
+		t.Fatalf("rewriting f.bin: %v", err)
+	}
+	after := CaptureFileHashesWithOpts(dir, map[string]bool{"f.bin": true}, opts)
+
+	if before["f.bin"] == after["f.bin"] {
+		t.Errorf("expected pointer hash to change when file size changes, got the same FileHash %v", before["f.bin"])
+	}
+}
+
+// A pointer->full transition (file shrinks below the threshold) must be
+// picked up as a changed file, not silently ignored because Hash happens to
+// collide.
+func TestDiffChangedFiles_PointerToFullTransition(t *testing.T) {
+	before := &GitState{
+		Hashes: map[string]FileHash{"f.bin": {Hash: "sha256:deadbeef", Pointer: true}},
+	}
+	after := &GitState{
+		Hashes: map[string]FileHash{"f.bin": {Hash: "abc123"}},
+	}
+
+	changed := DiffChangedFiles(before, after)
+	if len(changed) != 1 || changed[0] != "f.bin" {
+		t.Errorf("expected f.bin to be reported changed on pointer->full transition, got %v", changed)
+	}
+}
+
+// CaptureGitState's go-git path must report the same shape of state the
+// exec path did: dirty tracked files, untracked files, and a hash per
+// changed file.
+func TestCaptureGitState_GoGit_DirtyAndUntracked(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	if err := os.WriteFile(filepath.Join(repoDir, "base.txt"), []byte("changed\n"), 0644); err != nil {
+		t.Fatalf("modifying base.txt: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "new.txt"), []byte("new\n"), 0644); err != nil {
+		t.Fatalf("writing new.txt: %v", err)
+	}
+
+	state, err := CaptureGitState(repoDir)
+	if err != nil {
+		t.Fatalf("CaptureGitState: %v", err)
+	}
+
+	if state.Branch != "master" && state.Branch != "main" {
+		t.Errorf("expected branch master or main, got %q", state.Branch)
+	}
+	if !state.Dirty["base.txt"] {
+		t.Errorf("expected base.txt in Dirty, got %v", state.Dirty)
+	}
+	if !state.Untracked["new.txt"] {
+		t.Errorf("expected new.txt in Untracked, got %v", state.Untracked)
+	}
+	if state.Hashes["base.txt"].Hash == "" || state.Hashes["new.txt"].Hash == "" {
+		t.Errorf("expected hashes for both changed files, got %v", state.Hashes)
+	}
+}
+
+// A .gitignore entry must keep CaptureGitState from reporting the ignored
+// file as untracked.
+func TestCaptureGitState_GoGit_RespectsGitignore(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	if err := os.WriteFile(filepath.Join(repoDir, ".gitignore"), []byte("ignored.txt\n"), 0644); err != nil {
+		t.Fatalf("writing .gitignore: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "ignored.txt"), []byte("skip me\n"), 0644); err != nil {
+		t.Fatalf("writing ignored.txt: %v", err)
+	}
+
+	// A fresh repoDir means a fresh openRepos cache entry, so the new
+	// .gitignore is picked up rather than a stale cached Excludes list.
+	state, err := CaptureGitState(repoDir)
+	if err != nil {
+		t.Fatalf("CaptureGitState: %v", err)
+	}
+	if state.Untracked["ignored.txt"] {
+		t.Errorf("expected ignored.txt to be excluded from Untracked, got %v", state.Untracked)
+	}
+}
@@ -0,0 +1,115 @@
+package orchestrate
+
+import (
+	"fmt"
+	"go/ast"
+	"go/doc/comment"
+	"go/parser"
+	"go/token"
+	"strings"
+)
+
+// goDocComment extracts a Go declaration's leading doc comment -- the
+// contiguous "//" lines directly above lines[declStartIdx], gofmt's own
+// convention for what "belongs" to a func/type/var/const -- and renders it
+// as Markdown via go/doc/comment, so a doc comment's `[Foo]` links,
+// headings, and lists become prose instead of raw "//" lines dumped inside
+// a fenced code block. Returns "", false if there's no leading doc comment.
+func goDocComment(lines []string, declStartIdx int, linkURL func(*comment.DocLink) string) (string, bool) {
+	var raw []string
+	for i := declStartIdx - 1; i >= 0; i-- {
+		trimmed := strings.TrimSpace(lines[i])
+		if !strings.HasPrefix(trimmed, "//") {
+			break
+		}
+		text := strings.TrimPrefix(strings.TrimPrefix(trimmed, "//"), " ")
+		raw = append([]string{text}, raw...)
+	}
+	if len(raw) == 0 {
+		return "", false
+	}
+
+	var cp comment.Parser
+	doc := cp.Parse(strings.Join(raw, "\n"))
+
+	printer := &comment.Printer{DocLinkURL: linkURL}
+	md := strings.TrimSpace(string(printer.Markdown(doc)))
+	if md == "" {
+		return "", false
+	}
+	return md, true
+}
+
+// goDeclName extracts a Go declaration's symbol name from title -- the
+// short one-line signature classifyGoTitle also parses -- for resolving
+// `[Sym]` doc links against other code nodes in the same generated graph
+// (see docLinkResolver). Returns "", false if title doesn't parse as a Go
+// declaration.
+func goDeclName(title string) (string, bool) {
+	attempts := []string{
+		"package p\n" + title,
+		"package p\n" + title + " {}",
+	}
+	for _, src := range attempts {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", src, 0)
+		if err != nil || len(f.Decls) == 0 {
+			continue
+		}
+		switch d := f.Decls[0].(type) {
+		case *ast.FuncDecl:
+			return d.Name.Name, true
+		case *ast.GenDecl:
+			if len(d.Specs) == 0 {
+				continue
+			}
+			switch spec := d.Specs[0].(type) {
+			case *ast.TypeSpec:
+				return spec.Name.Name, true
+			case *ast.ValueSpec:
+				if len(spec.Names) > 0 {
+					return spec.Names[0].Name, true
+				}
+			}
+		}
+	}
+	return "", false
+}
+
+// docLinkResolver builds a comment.Printer.DocLinkURL that points a `[Sym]`
+// doc link at the file:line of whichever other code row in codeRows
+// declares that symbol -- something a coder/verifier agent reading the
+// generated task file can jump straight to -- falling back to pkg.go.dev
+// for symbols this graph doesn't have a node for. excludeID skips the
+// snippet's own row, so a doc comment can't link to itself.
+func docLinkResolver(codeRows []contextRow, excludeID string) func(*comment.DocLink) string {
+	bySymbol := make(map[string]codeTags)
+	for _, cr := range codeRows {
+		if cr.NodeID == excludeID {
+			continue
+		}
+		if name, ok := goDeclName(cr.Title); ok {
+			bySymbol[name] = parseCodeTags(cr.Tags)
+		}
+	}
+	return func(link *comment.DocLink) string {
+		if link.ImportPath == "" {
+			if ct, ok := bySymbol[link.Name]; ok && ct.FilePath != "" {
+				return fmt.Sprintf("%s#L%d", ct.FilePath, ct.StartLine)
+			}
+		}
+		pkg := link.ImportPath
+		if pkg == "" {
+			pkg = "."
+		}
+		url := "https://pkg.go.dev/" + pkg
+		sym := link.Name
+		if link.Recv != "" {
+			sym = link.Recv + "." + link.Name
+		}
+		if sym != "" {
+			url += "#" + sym
+		}
+		return url
+	}
+}
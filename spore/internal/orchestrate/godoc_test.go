@@ -0,0 +1,115 @@
+package orchestrate
+
+import (
+	"go/doc/comment"
+	"strings"
+	"testing"
+)
+
+func TestGoDocComment_RendersProseAboveFence(t *testing.T) {
+	lines := []string{
+		"// Add returns the sum of a and b. See [Sub] for the inverse.",
+		"func Add(a, b int) int {",
+		"	return a + b",
+		"}",
+	}
+
+	md, ok := goDocComment(lines, 1, docLinkResolver(nil, ""))
+	if !ok {
+		t.Fatal("expected a doc comment to be found")
+	}
+	if !strings.Contains(md, "Add returns the sum") {
+		t.Errorf("expected rendered prose to contain the comment text, got: %q", md)
+	}
+}
+
+func TestGoDocComment_NoLeadingComment(t *testing.T) {
+	lines := []string{
+		"func Add(a, b int) int {",
+		"	return a + b",
+		"}",
+	}
+
+	if _, ok := goDocComment(lines, 0, docLinkResolver(nil, "")); ok {
+		t.Error("expected no doc comment when there's no leading // line")
+	}
+}
+
+func TestGoDocComment_StopsAtBlankLine(t *testing.T) {
+	lines := []string{
+		"// Unrelated comment for a previous declaration.",
+		"",
+		"// Add returns the sum of a and b.",
+		"func Add(a, b int) int {",
+		"	return a + b",
+		"}",
+	}
+
+	md, ok := goDocComment(lines, 3, docLinkResolver(nil, ""))
+	if !ok {
+		t.Fatal("expected a doc comment to be found")
+	}
+	if strings.Contains(md, "Unrelated") {
+		t.Errorf("expected the blank line to stop the scan before the unrelated comment, got: %q", md)
+	}
+}
+
+func TestGoDeclName(t *testing.T) {
+	cases := []struct {
+		title string
+		want  string
+	}{
+		{"func Add(a, b int) int", "Add"},
+		{"func (s *Server) Handle(r *Request)", "Handle"},
+		{"type Config struct", "Config"},
+		{"const MaxRetries = 3", "MaxRetries"},
+		{"var DefaultTimeout = 5", "DefaultTimeout"},
+	}
+	for _, c := range cases {
+		got, ok := goDeclName(c.title)
+		if !ok {
+			t.Errorf("goDeclName(%q): expected ok=true", c.title)
+			continue
+		}
+		if got != c.want {
+			t.Errorf("goDeclName(%q) = %q, want %q", c.title, got, c.want)
+		}
+	}
+}
+
+func TestDocLinkResolver_LinksToGraphNodeOverPkgGoDev(t *testing.T) {
+	codeRows := []contextRow{
+		{
+			NodeID: "code-sub",
+			Title:  "func Sub(a, b int) int",
+			Tags:   `{"file_path":"math/sub.go","line_start":10,"line_end":12,"language":"go"}`,
+		},
+	}
+
+	resolve := docLinkResolver(codeRows, "code-add")
+	url := resolve(&comment.DocLink{Name: "Sub"})
+	if url != "math/sub.go#L10" {
+		t.Errorf("resolve(Sub) = %q, want a local file:line link", url)
+	}
+
+	extURL := resolve(&comment.DocLink{Name: "Unknown"})
+	if !strings.Contains(extURL, "pkg.go.dev") {
+		t.Errorf("resolve(Unknown) = %q, want a pkg.go.dev fallback", extURL)
+	}
+}
+
+func TestDocLinkResolver_ExcludesOwnRow(t *testing.T) {
+	codeRows := []contextRow{
+		{
+			NodeID: "code-add",
+			Title:  "func Add(a, b int) int",
+			Tags:   `{"file_path":"math/add.go","line_start":1,"line_end":3,"language":"go"}`,
+		},
+	}
+
+	resolve := docLinkResolver(codeRows, "code-add")
+	url := resolve(&comment.DocLink{Name: "Add"})
+	if strings.Contains(url, "add.go") {
+		t.Errorf("resolve(Add) should not link back to its own row, got: %q", url)
+	}
+}
@@ -0,0 +1,202 @@
+package orchestrate
+
+import (
+	"sync"
+	"time"
+
+	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/memcache"
+)
+
+// Lesson is a single past-run lesson record, independent of the storage engine.
+type Lesson struct {
+	ID      string
+	Title   string
+	Content string
+}
+
+// LessonFilter narrows a LessonStore.Query call.
+type LessonFilter struct {
+	Limit int
+}
+
+// StoreStats is the per-operation metrics a LessonStore accumulates: read/write/
+// commit counts, bytes moved, and average latency. Surfaced as `store_stats`
+// in `orchestrate --json`/`loop --json` output.
+type StoreStats struct {
+	Reads          int64   `json:"reads"`
+	Writes         int64   `json:"writes"`
+	Commits        int64   `json:"commits"`
+	BytesRead      int64   `json:"bytes_read"`
+	BytesWritten   int64   `json:"bytes_written"`
+	ReadLatencyMS  float64 `json:"read_latency_ms_avg"`
+	WriteLatencyMS float64 `json:"write_latency_ms_avg"`
+}
+
+// metricsCollector is a thread-safe accumulator shared by all LessonStore
+// implementations, so swapping backends doesn't also mean reimplementing
+// metrics bookkeeping.
+type metricsCollector struct {
+	mu                      sync.Mutex
+	reads, writes, commits  int64
+	bytesRead, bytesWritten int64
+	readLatencyTotal        time.Duration
+	writeLatencyTotal       time.Duration
+}
+
+func (m *metricsCollector) recordRead(bytes int64, dur time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.reads++
+	m.bytesRead += bytes
+	m.readLatencyTotal += dur
+}
+
+func (m *metricsCollector) recordWrite(bytes int64, dur time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.writes++
+	m.bytesWritten += bytes
+	m.writeLatencyTotal += dur
+}
+
+func (m *metricsCollector) recordCommit() {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.commits++
+}
+
+func (m *metricsCollector) stats() StoreStats {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	s := StoreStats{
+		Reads:        m.reads,
+		Writes:       m.writes,
+		Commits:      m.commits,
+		BytesRead:    m.bytesRead,
+		BytesWritten: m.bytesWritten,
+	}
+	if m.reads > 0 {
+		s.ReadLatencyMS = float64(m.readLatencyTotal.Milliseconds()) / float64(m.reads)
+	}
+	if m.writes > 0 {
+		s.WriteLatencyMS = float64(m.writeLatencyTotal.Milliseconds()) / float64(m.writes)
+	}
+	return s
+}
+
+// LessonStore is a pluggable backend for lesson persistence, modeled on
+// OPA's runtime+disk integration: one writer, many readers, with txn
+// management centralized behind the interface so callers never touch the
+// underlying engine directly.
+type LessonStore interface {
+	Query(filter LessonFilter) ([]Lesson, error)
+	Put(l Lesson) error
+	Stats() StoreStats
+}
+
+// sqliteLessonStore is the default backend, querying the same graph database
+// everything else in spore uses.
+type sqliteLessonStore struct {
+	d       *db.DB
+	metrics *metricsCollector
+}
+
+// NewSQLiteLessonStore wraps d's "Lesson:"-prefixed operational nodes as a LessonStore.
+func NewSQLiteLessonStore(d *db.DB) LessonStore {
+	return &sqliteLessonStore{d: d, metrics: &metricsCollector{}}
+}
+
+func (s *sqliteLessonStore) Query(filter LessonFilter) ([]Lesson, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	start := time.Now()
+	rows, err := s.d.Conn().Query(
+		`SELECT id, title, content FROM nodes
+		 WHERE node_class = 'operational' AND title LIKE 'Lesson:%'
+		 ORDER BY created_at DESC LIMIT ?`, limit,
+	)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var out []Lesson
+	var bytes int64
+	for rows.Next() {
+		var id, title string
+		var content *string
+		if err := rows.Scan(&id, &title, &content); err != nil {
+			continue
+		}
+		c := ""
+		if content != nil {
+			c = *content
+		}
+		bytes += int64(len(c))
+		out = append(out, Lesson{ID: id, Title: title, Content: c})
+	}
+	s.metrics.recordRead(bytes, time.Since(start))
+	return out, nil
+}
+
+func (s *sqliteLessonStore) Put(l Lesson) error {
+	start := time.Now()
+	_, err := s.d.Conn().Exec(
+		`INSERT INTO nodes (id, node_class, title, content) VALUES (?, 'operational', ?, ?)`,
+		l.ID, l.Title, l.Content,
+	)
+	s.metrics.recordWrite(int64(len(l.Content)), time.Since(start))
+	if err == nil {
+		s.metrics.recordCommit()
+		memcache.Default().Invalidate(l.ID)
+	}
+	return err
+}
+
+func (s *sqliteLessonStore) Stats() StoreStats { return s.metrics.stats() }
+
+// memoryLessonStore keeps lessons in a slice, for tests and short-lived runs
+// where nothing needs to survive the process.
+type memoryLessonStore struct {
+	mu      sync.Mutex
+	lessons []Lesson
+	metrics *metricsCollector
+}
+
+// NewMemoryLessonStore returns an in-memory LessonStore.
+func NewMemoryLessonStore() LessonStore {
+	return &memoryLessonStore{metrics: &metricsCollector{}}
+}
+
+func (s *memoryLessonStore) Query(filter LessonFilter) ([]Lesson, error) {
+	start := time.Now()
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	limit := filter.Limit
+	if limit <= 0 || limit > len(s.lessons) {
+		limit = len(s.lessons)
+	}
+	out := make([]Lesson, limit)
+	copy(out, s.lessons[:limit])
+	var bytes int64
+	for _, l := range out {
+		bytes += int64(len(l.Content))
+	}
+	s.metrics.recordRead(bytes, time.Since(start))
+	return out, nil
+}
+
+func (s *memoryLessonStore) Put(l Lesson) error {
+	start := time.Now()
+	s.mu.Lock()
+	s.lessons = append([]Lesson{l}, s.lessons...)
+	s.mu.Unlock()
+	s.metrics.recordWrite(int64(len(l.Content)), time.Since(start))
+	s.metrics.recordCommit()
+	return nil
+}
+
+func (s *memoryLessonStore) Stats() StoreStats { return s.metrics.stats() }
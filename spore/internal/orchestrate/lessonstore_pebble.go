@@ -0,0 +1,73 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/cockroachdb/pebble"
+)
+
+// pebbleLessonStore is a disk-backed LessonStore for corpora too large to
+// comfortably keep resident via the sqlite `nodes` table scan -- Pebble's
+// LSM tree keeps RSS bounded regardless of lesson count. Keys are lesson
+// IDs; values are JSON-encoded Lesson records. Pebble serializes writes
+// internally, giving the same one-writer-many-readers semantics as sqlite.
+type pebbleLessonStore struct {
+	db      *pebble.DB
+	metrics *metricsCollector
+}
+
+// NewPebbleLessonStore opens (creating if needed) a Pebble-backed LessonStore at dir.
+func NewPebbleLessonStore(dir string) (LessonStore, error) {
+	pdb, err := pebble.Open(dir, &pebble.Options{})
+	if err != nil {
+		return nil, fmt.Errorf("opening pebble lesson store at %s: %w", dir, err)
+	}
+	return &pebbleLessonStore{db: pdb, metrics: &metricsCollector{}}, nil
+}
+
+func (s *pebbleLessonStore) Query(filter LessonFilter) ([]Lesson, error) {
+	limit := filter.Limit
+	if limit <= 0 {
+		limit = 20
+	}
+	start := time.Now()
+	iter, err := s.db.NewIter(nil)
+	if err != nil {
+		return nil, err
+	}
+	defer iter.Close()
+
+	var out []Lesson
+	var bytes int64
+	for iter.Last(); iter.Valid() && len(out) < limit; iter.Prev() {
+		var l Lesson
+		if err := json.Unmarshal(iter.Value(), &l); err != nil {
+			continue
+		}
+		bytes += int64(len(iter.Value()))
+		out = append(out, l)
+	}
+	s.metrics.recordRead(bytes, time.Since(start))
+	return out, nil
+}
+
+func (s *pebbleLessonStore) Put(l Lesson) error {
+	start := time.Now()
+	raw, err := json.Marshal(l)
+	if err != nil {
+		return err
+	}
+	if err := s.db.Set([]byte(l.ID), raw, pebble.Sync); err != nil {
+		return err
+	}
+	s.metrics.recordWrite(int64(len(raw)), time.Since(start))
+	s.metrics.recordCommit()
+	return nil
+}
+
+func (s *pebbleLessonStore) Stats() StoreStats { return s.metrics.stats() }
+
+// Close releases the underlying Pebble handle.
+func (s *pebbleLessonStore) Close() error { return s.db.Close() }
@@ -0,0 +1,67 @@
+package orchestrate
+
+import "testing"
+
+func TestMemoryLessonStore_PutQuery(t *testing.T) {
+	store := NewMemoryLessonStore()
+
+	if err := store.Put(Lesson{ID: "1", Title: "Lesson: first", Content: "abc"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+	if err := store.Put(Lesson{ID: "2", Title: "Lesson: second", Content: "defgh"}); err != nil {
+		t.Fatalf("Put: %v", err)
+	}
+
+	got, err := store.Query(LessonFilter{Limit: 10})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(got) != 2 {
+		t.Fatalf("expected 2 lessons, got %d", len(got))
+	}
+	if got[0].ID != "2" {
+		t.Errorf("expected most recently Put lesson first, got %q", got[0].ID)
+	}
+
+	stats := store.Stats()
+	if stats.Writes != 2 {
+		t.Errorf("expected 2 writes, got %d", stats.Writes)
+	}
+	if stats.Reads != 1 {
+		t.Errorf("expected 1 read, got %d", stats.Reads)
+	}
+	if stats.BytesWritten != int64(len("abc")+len("defgh")) {
+		t.Errorf("expected %d bytes written, got %d", len("abc")+len("defgh"), stats.BytesWritten)
+	}
+}
+
+func TestMemoryLessonStore_QueryLimit(t *testing.T) {
+	store := NewMemoryLessonStore()
+	for i := 0; i < 5; i++ {
+		_ = store.Put(Lesson{ID: string(rune('a' + i)), Title: "Lesson: x"})
+	}
+	got, err := store.Query(LessonFilter{Limit: 2})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(got) != 2 {
+		t.Errorf("expected limit to cap results at 2, got %d", len(got))
+	}
+}
+
+func TestSQLiteLessonStore_Query(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	store := NewSQLiteLessonStore(d)
+	got, err := store.Query(LessonFilter{Limit: 5})
+	if err != nil {
+		t.Fatalf("Query: %v", err)
+	}
+	if len(got) != 0 {
+		t.Errorf("expected no lessons in a fresh DB, got %d", len(got))
+	}
+	if store.Stats().Reads != 1 {
+		t.Errorf("expected Query to record a read, got %+v", store.Stats())
+	}
+}
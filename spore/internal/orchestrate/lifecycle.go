@@ -0,0 +1,101 @@
+package orchestrate
+
+import (
+	"sync"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+// AgentState is one lifecycle transition a Claude subprocess passes through,
+// as driven by SpawnClaude/SpawnClaudeStreaming.
+type AgentState string
+
+const (
+	StatePending   AgentState = "pending"   // before cmd.Start
+	StateReceived  AgentState = "received"  // first stream-json line parsed
+	StateRunning   AgentState = "running"   // first assistant event seen
+	StateCompleted AgentState = "completed" // exited cleanly
+	StateFailed    AgentState = "failed"    // exited non-zero, or ctx cancelled/timed out
+)
+
+func (s AgentState) String() string { return string(s) }
+
+// AgentStateReporter receives a Claude subprocess's lifecycle transitions as
+// SpawnClaude drives them, so a dashboard can tell "claude started but
+// silent" (Received, no Running yet) apart from "producing output"
+// (Running) and "done" (Completed/Failed) -- currently invisible since the
+// stream-json parser only surfaces a terminal ClaudeResult. Implementations
+// should return quickly; ReportState is called from hot paths (process
+// start, first byte of output, first assistant event).
+type AgentStateReporter interface {
+	ReportState(state AgentState)
+}
+
+// reportState calls reporter.ReportState if reporter is non-nil, so call
+// sites don't each need their own nil check.
+func reportState(reporter AgentStateReporter, state AgentState) {
+	if reporter != nil {
+		reporter.ReportState(state)
+	}
+}
+
+// defaultReceivedSyncLimit is how long SQLiteStateReporter waits before
+// emitting a debounced StateReceived transition; see ReceivedSyncLimit.
+const defaultReceivedSyncLimit = 30 * time.Second
+
+// SQLiteStateReporter is an AgentStateReporter that records transitions as
+// lightweight tracks edges on the SQLite run store (see recordAgentState).
+// StateReceived is debounced by ReceivedSyncLimit: a run that reaches a
+// terminal state (Completed/Failed) within that window never gets a
+// "received" edge at all, since Running or the terminal state itself already
+// says more. Pending/Running/Completed/Failed are recorded immediately.
+type SQLiteStateReporter struct {
+	db         *db.DB
+	taskNodeID string
+	runID      string
+	agent      string
+
+	// ReceivedSyncLimit bounds how long to wait before emitting a debounced
+	// StateReceived if no terminal state has landed first. Zero uses
+	// defaultReceivedSyncLimit.
+	ReceivedSyncLimit time.Duration
+
+	mu       sync.Mutex
+	terminal bool
+}
+
+// NewSQLiteStateReporter returns a reporter that records runID's lifecycle
+// transitions as tracks edges on taskNodeID.
+func NewSQLiteStateReporter(d *db.DB, taskNodeID, runID, agent string) *SQLiteStateReporter {
+	return &SQLiteStateReporter{db: d, taskNodeID: taskNodeID, runID: runID, agent: agent}
+}
+
+// ReportState implements AgentStateReporter.
+func (r *SQLiteStateReporter) ReportState(state AgentState) {
+	switch state {
+	case StateCompleted, StateFailed:
+		r.mu.Lock()
+		r.terminal = true
+		r.mu.Unlock()
+		recordAgentState(r.db, r.taskNodeID, r.runID, r.agent, state)
+
+	case StateReceived:
+		limit := r.ReceivedSyncLimit
+		if limit <= 0 {
+			limit = defaultReceivedSyncLimit
+		}
+		go func() {
+			time.Sleep(limit)
+			r.mu.Lock()
+			skip := r.terminal
+			r.mu.Unlock()
+			if !skip {
+				recordAgentState(r.db, r.taskNodeID, r.runID, r.agent, state)
+			}
+		}()
+
+	default:
+		recordAgentState(r.db, r.taskNodeID, r.runID, r.agent, state)
+	}
+}
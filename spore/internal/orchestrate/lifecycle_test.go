@@ -0,0 +1,61 @@
+package orchestrate
+
+import (
+	"sync"
+	"testing"
+	"time"
+)
+
+// recordingReporter is an AgentStateReporter test double that captures every
+// ReportState call in order.
+type recordingReporter struct {
+	mu     sync.Mutex
+	states []AgentState
+}
+
+func (r *recordingReporter) ReportState(state AgentState) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.states = append(r.states, state)
+}
+
+func (r *recordingReporter) snapshot() []AgentState {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]AgentState(nil), r.states...)
+}
+
+func TestReportState_NilReporterIsNoop(t *testing.T) {
+	reportState(nil, StatePending) // must not panic
+}
+
+func TestReportState_CallsReporter(t *testing.T) {
+	r := &recordingReporter{}
+	reportState(r, StateRunning)
+	if got := r.snapshot(); len(got) != 1 || got[0] != StateRunning {
+		t.Errorf("states = %v, want [running]", got)
+	}
+}
+
+func TestSQLiteStateReporter_DebouncedReceivedSkippedAfterTerminal(t *testing.T) {
+	r := &SQLiteStateReporter{ReceivedSyncLimit: 20 * time.Millisecond}
+
+	// ReportState issues the real SQLite write via recordAgentState, which
+	// no-ops on a nil *db.DB -- exercising that path here only proves the
+	// debounce timing, not the write itself.
+	r.ReportState(StateReceived)
+	r.ReportState(StateCompleted)
+
+	r.mu.Lock()
+	terminal := r.terminal
+	r.mu.Unlock()
+	if !terminal {
+		t.Fatal("expected terminal to be set immediately on StateCompleted")
+	}
+
+	// Give the debounced goroutine time to observe terminal and skip.
+	time.Sleep(40 * time.Millisecond)
+	// No assertion beyond "doesn't panic and terminal stays true" -- the
+	// debounced branch has no externally observable effect here since
+	// recordAgentState no-ops without a *db.DB.
+}
@@ -2,17 +2,22 @@ package orchestrate
 
 import (
 	"bufio"
+	"context"
 	"crypto/sha256"
 	"encoding/json"
 	"fmt"
 	"io"
+	"math"
 	"os"
 	"os/exec"
 	"path/filepath"
+	"sort"
 	"strings"
 	"time"
 
 	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/orchestrate/assert"
+	"mycelica/spore/internal/orchestrate/taskmatch"
 )
 
 // LoopConfig controls the loop execution.
@@ -25,32 +30,219 @@ type LoopConfig struct {
 	AutoCommit        bool    // git add+commit between verified tasks
 	PauseOnEscalation bool    // stop loop on first escalation
 	OrchConfig        OrchestrationConfig
+
+	// Enforcement overrides the AutoCommit/PauseOnEscalation defaults with
+	// scoped per-stage rules (see EnforcementPolicy). Nil means those two
+	// flags alone govern behavior, as before.
+	Enforcement *EnforcementPolicy
+
+	// RunPattern/SkipPattern select tasks by name/tags, go-test-run style
+	// (see taskmatch). Empty means no filtering.
+	RunPattern  string
+	SkipPattern string
+
+	// Parallelism is the maximum number of tasks RunOrchestration is
+	// allowed to run concurrently. <= 1 preserves the original strictly
+	// sequential behavior. Concurrent tasks are assumed to touch disjoint
+	// parts of the repo -- unlike runCandidates' per-candidate worktrees,
+	// RunLoop dispatches whole tasks directly against workDir, so two
+	// tasks editing the same files at once can still conflict.
+	Parallelism int
+
+	// DAG requests a topological dry-run plan instead of (or in addition
+	// to) the usual dry-run listing -- only meaningful when the task
+	// source is a structured YAML manifest with depends_on edges. Implies
+	// DryRun: a plan is printed and no agents are spawned.
+	DAG bool
+
+	// TaskTimeout is the per-task deadline applied when a manifest task
+	// doesn't set its own Timeout (see Task.Timeout). 0 means no default
+	// deadline -- a task only times out if it sets Timeout itself.
+	TaskTimeout time.Duration
+
+	// LoopDeadline bounds the entire RunLoop call, not any single task: once
+	// it elapses, ctx is cancelled, the in-flight task's phase is
+	// terminated, and no further tasks are dispatched. 0 means no deadline.
+	LoopDeadline time.Duration
+
+	// TreatTimeoutAsEscalation counts a "timeout" outcome toward
+	// consecutiveEscalations (and StopOnEscalation/PauseOnEscalation) the
+	// same as an "escalated" one. When false (the default), a lone hung
+	// task doesn't trip escalation-based abort logic meant for genuine
+	// verifier disagreement.
+	TreatTimeoutAsEscalation bool
+
+	// RetryTimeouts controls whether a task that previously timed out (per
+	// loopState.TimedOutTasks) is retried on resume. Default false: a timed-
+	// out task is skipped on resume like a verified one, since re-running a
+	// hang is likely to hang again and burn budget; set true to retry it.
+	RetryTimeouts bool
+
+	// SignCommits appends -S to every auto-commit's git commit invocation,
+	// GPG/SSH-signing per the caller's git config (user.signingkey,
+	// gpg.format). Commit fails the same way `git commit -S` would fail
+	// interactively if no signing key is configured.
+	SignCommits bool
+
+	// SquashOnLoopEnd defers every per-task auto-commit until RunLoop
+	// finishes, producing a single commit whose body enumerates each
+	// verified task and its trailers instead of one commit per task.
+	SquashOnLoopEnd bool
 }
 
 // LoopResult summarizes the full loop execution.
 type LoopResult struct {
-	Tasks     []LoopTaskResult `json:"tasks"`
-	TotalCost float64          `json:"total_cost_usd"`
-	Duration  time.Duration    `json:"duration"`
+	Tasks      []LoopTaskResult `json:"tasks"`
+	TotalCost  float64          `json:"total_cost_usd"`
+	Duration   time.Duration    `json:"duration"`
+	StoreStats *StoreStats      `json:"store_stats,omitempty"`
 }
 
 // LoopTaskResult is the outcome of one task.
 type LoopTaskResult struct {
-	Task       string        `json:"task"`
-	Status     string        `json:"status"` // "verified", "escalated", "failed"
-	Cost       float64       `json:"cost_usd"`
-	Duration   time.Duration `json:"duration"`
-	TaskNodeID string        `json:"task_node_id,omitempty"`
+	Task       string                    `json:"task"`
+	ID         string                    `json:"id,omitempty"` // stable task ID, see Task.ID; empty for pre-chunk11-2 callers
+	Status     string                    `json:"status"`       // "verified", "escalated", "failed"
+	Cost       float64                   `json:"cost_usd"`
+	Duration   time.Duration             `json:"duration"`
+	TaskNodeID string                    `json:"task_node_id,omitempty"`
+	Assertions []assert.AssertionApplied `json:"assertions,omitempty"`
+}
+
+// taskCompletion is one worker's finished RunOrchestration outcome, handed
+// back to RunLoop's dispatch loop over a channel. reservedCost is the
+// budget estimate charged at dispatch time (see costTracker.median), which
+// the dispatcher refunds and replaces with runCost once the real number is
+// known.
+type taskCompletion struct {
+	index        int // 0-based position in tasks
+	total        int // len(tasks), for "i/total" logging
+	task         string
+	id           string // Task.ID, for VerifiedTasks/depends_on bookkeeping
+	maxRetries   int    // Task.MaxRetries, consulted on failure/escalation
+	status       string // "verified", "escalated", "failed"
+	runCost      float64
+	reservedCost float64
+	duration     time.Duration
+	taskNodeID   string
+	runID        string
+	orchErr      error
+	assertions   []assert.AssertionApplied
+}
+
+// pendingCommit is a verified task awaiting autoCommit, queued until the
+// worker pool is momentarily idle (see RunLoop's flushPendingCommits). It
+// carries everything autoCommit's trailers need, so a SquashOnLoopEnd run
+// can hold a whole batch of these until RunLoop finishes.
+type pendingCommit struct {
+	task       string
+	taskID     string
+	taskNodeID string
+	runID      string
+	cost       float64
+	duration   time.Duration
+}
+
+// costTracker keeps every completed task's cost for rolling median/MAD-based
+// budget estimates and cost-anomaly detection -- resistant to the
+// cascading false positives a plain running mean produces when a burst of
+// concurrent completions each pull a shared average toward themselves.
+type costTracker struct {
+	costs []float64
+}
+
+func (c *costTracker) record(cost float64) {
+	c.costs = append(c.costs, cost)
+}
+
+// median returns the median of all recorded costs, or 0 if none yet.
+func (c *costTracker) median() float64 {
+	return median(c.costs)
+}
+
+// isAnomalous reports whether cost is a statistical outlier against prior
+// completions, using a modified z-score (0.6745*(cost-median)/MAD) against
+// the standard 3.5 threshold (Iglewicz & Hoaglin). Requires at least 3 prior
+// completions; ratio is cost/median, for the same log message shape the
+// old mean-based check used.
+func (c *costTracker) isAnomalous(cost float64) (anomalous bool, ratio float64) {
+	if len(c.costs) < 3 {
+		return false, 0
+	}
+	m := median(c.costs)
+	if m == 0 {
+		return false, 0
+	}
+	ratio = cost / m
+	mad := medianAbsoluteDeviation(c.costs, m)
+	if mad == 0 {
+		return cost > m*3.0, ratio
+	}
+	z := 0.6745 * (cost - m) / mad
+	return z > 3.5, ratio
+}
+
+func median(values []float64) float64 {
+	if len(values) == 0 {
+		return 0
+	}
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
+	}
+	return sorted[mid]
+}
+
+func medianAbsoluteDeviation(values []float64, center float64) float64 {
+	devs := make([]float64, len(values))
+	for i, v := range values {
+		devs[i] = math.Abs(v - center)
+	}
+	return median(devs)
 }
 
 // RunLoop dispatches multiple tasks from a file with budget tracking and resume support.
-// Port of handle_spore_loop (spore.rs:4042-4267).
-func RunLoop(d *db.DB, config LoopConfig) (*LoopResult, error) {
-	tasks, err := ReadTasks(config.Source)
+// Port of handle_spore_loop (spore.rs:4042-4267). ctx is passed through to
+// each RunOrchestration dispatch; cancelling it stops the loop after the
+// in-flight task's phase is terminated.
+func RunLoop(ctx context.Context, d *db.DB, config LoopConfig) (*LoopResult, error) {
+	if config.LoopDeadline > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, config.LoopDeadline)
+		defer cancel()
+	}
+
+	allTasks, err := ReadTaskFile(config.Source)
 	if err != nil {
 		return nil, err
 	}
 
+	selector, err := taskmatch.NewSelector(config.RunPattern, config.SkipPattern)
+	if err != nil {
+		return nil, fmt.Errorf("compiling task selector: %w", err)
+	}
+
+	var tasks []Task
+	for _, t := range allTasks {
+		if !selector.Selects(t.Name, t.Tags...) {
+			continue
+		}
+		tasks = append(tasks, t)
+	}
+	if selector.Run != nil || selector.Skip != nil {
+		fmt.Fprintf(os.Stderr, "[loop] Selector matched %d/%d task(s)\n", len(tasks), len(allTasks))
+	}
+
+	// A single LessonStore is shared across every task dispatch so StoreStats
+	// reflect the whole loop run, not just the last task.
+	lessonStore := config.OrchConfig.LessonStore
+	if lessonStore == nil {
+		lessonStore = NewSQLiteLessonStore(d)
+	}
+	config.OrchConfig.LessonStore = lessonStore
+
 	stopOnEsc := config.StopOnEscalation
 	if stopOnEsc <= 0 {
 		stopOnEsc = 3
@@ -78,21 +270,48 @@ func RunLoop(d *db.DB, config LoopConfig) (*LoopResult, error) {
 		fmt.Fprintf(os.Stderr, "[loop] Resuming: %d task(s) already verified, will skip.\n", alreadyVerified)
 	}
 
-	// Dry run: list tasks with complexity estimates, no agents
-	if config.OrchConfig.DryRun {
+	// Dry run: list tasks (and selection status) with complexity estimates, no agents
+	if config.OrchConfig.DryRun || config.DAG {
 		fmt.Fprintf(os.Stderr, "\n[loop] === DRY RUN ===\n")
+
+		if config.DAG {
+			ordered, err := topoSort(tasks)
+			if err != nil {
+				return nil, fmt.Errorf("--dag: %w", err)
+			}
+			fmt.Fprintf(os.Stderr, "[loop] Topological plan (%d task(s)):\n", len(ordered))
+			for i, t := range ordered {
+				complexity := EstimateComplexity(t.Body)
+				deps := "none"
+				if len(t.DependsOn) > 0 {
+					deps = strings.Join(t.DependsOn, ", ")
+				}
+				fmt.Fprintf(os.Stderr, "  %d. %s [complexity %d/10] depends_on: %s -- %s\n",
+					i+1, t.ID, complexity, deps, TruncateMiddle(t.Body, 60))
+			}
+			fmt.Fprintf(os.Stderr, "\n[loop] Would dispatch %d task(s) in the order above. No agents spawned.\n", len(ordered))
+			return &LoopResult{Tasks: nil, TotalCost: 0, Duration: 0}, nil
+		}
+
 		shown := 0
-		for i, task := range tasks {
-			if shown >= config.MaxRuns {
-				break
+		for i, t := range allTasks {
+			selected := selector.Selects(t.Name, t.Tags...)
+			mark := "match"
+			if !selected {
+				mark = "skip "
 			}
-			complexity := EstimateComplexity(task)
-			taskShort := TruncateMiddle(task, 70)
-			fmt.Fprintf(os.Stderr, "  %d. [complexity %d/10] %s\n", i+1, complexity, taskShort)
-			shown++
+			if selected {
+				if shown >= config.MaxRuns {
+					continue
+				}
+				shown++
+			}
+			complexity := EstimateComplexity(t.Body)
+			taskShort := TruncateMiddle(t.Body, 70)
+			fmt.Fprintf(os.Stderr, "  %d. [%s][complexity %d/10] %s\n", i+1, mark, complexity, taskShort)
 		}
 		if len(tasks) > config.MaxRuns {
-			fmt.Fprintf(os.Stderr, "  ... and %d more tasks (limited by --max-runs %d)\n",
+			fmt.Fprintf(os.Stderr, "  ... and %d more matched tasks (limited by --max-runs %d)\n",
 				len(tasks)-config.MaxRuns, config.MaxRuns)
 		}
 		fmt.Fprintf(os.Stderr, "\n[loop] Would dispatch %d task(s). No agents spawned.\n", shown)
@@ -111,162 +330,475 @@ func RunLoop(d *db.DB, config LoopConfig) (*LoopResult, error) {
 
 	workDir := findProjectRoot(filepath.Dir(d.Path))
 
-	for i, task := range tasks {
-		// Budget check
-		if totalCost >= config.Budget {
-			fmt.Fprintf(os.Stderr, "\n[loop] Budget exhausted ($%.2f/$%.2f). Stopping.\n", totalCost, config.Budget)
-			break
-		}
+	parallelism := config.Parallelism
+	if parallelism < 1 {
+		parallelism = 1
+	}
 
-		// Max runs check
-		if len(results) >= config.MaxRuns {
-			fmt.Fprintf(os.Stderr, "\n[loop] Max runs reached (%d/%d). Stopping.\n", len(results), config.MaxRuns)
-			break
+	// Every piece of state a worker's completion touches -- totalCost,
+	// consecutiveEscalations, results, state, costs, pendingCommits -- is
+	// owned exclusively by this goroutine (the dispatch loop below) and
+	// mutated only from inside processCompletion, which the loop always
+	// calls itself rather than handing to a worker. Workers only run
+	// RunOrchestration and hand their outcome back over completions; no
+	// mutex is needed because nothing shared is ever touched concurrently.
+	completions := make(chan taskCompletion, parallelism)
+	var costs costTracker
+	var reserved float64
+	var pendingCommits []pendingCommit
+	// squashQueue accumulates every pendingCommit across the whole run when
+	// config.SquashOnLoopEnd is set, instead of committing each one as it
+	// flushes -- see flushPendingCommits and the single commit built from
+	// this slice after the dispatch loop drains.
+	var squashQueue []pendingCommit
+	inFlight := 0
+	stopped := false
+
+	// pending tracks, by index into tasks, which tasks still need to be
+	// dispatched (or re-dispatched, for a retry). retries counts
+	// failure/escalation attempts so far per Task.ID, against that task's
+	// MaxRetries. failedIDs holds IDs that reached a terminal non-verified
+	// outcome (retries exhausted, or never retried), so depends_on can tell
+	// a dependent task it will never unblock rather than waiting forever.
+	pending := make([]bool, len(tasks))
+	for i := range pending {
+		pending[i] = true
+	}
+	retries := make(map[string]int)
+	failedIDs := make(map[string]bool)
+
+	// depsSatisfied reports whether every task t depends on is verified.
+	depsSatisfied := func(t Task) bool {
+		for _, dep := range t.DependsOn {
+			if !state.isVerified(dep) {
+				return false
+			}
 		}
-
-		// Consecutive escalation check
-		if consecutiveEscalations >= stopOnEsc {
-			fmt.Fprintf(os.Stderr, "\n[loop] %d consecutive escalations. Stopping -- likely systemic issue.\n", stopOnEsc)
-			break
+		return true
+	}
+	// depsDead reports whether any task t depends on has reached a terminal
+	// failure, so t itself can never become dispatchable.
+	depsDead := func(t Task) bool {
+		for _, dep := range t.DependsOn {
+			if failedIDs[dep] {
+				return true
+			}
 		}
+		return false
+	}
 
-		// Skip already-verified tasks
-		if state.isVerified(task) {
-			fmt.Fprintf(os.Stderr, "[loop] Skipping task %d (already verified)\n", i+1)
-			continue
+	// flushPendingCommits runs queued auto-commits once the pool is
+	// momentarily idle (inFlight == 0), rather than coalescing them into a
+	// single commit -- each commit still carries exactly one run's
+	// Mycelica-Run-ID trailer, which commitTrailer (blame.go) assumes when
+	// mapping a commit back to the run that produced it.
+	flushPendingCommits := func() {
+		if inFlight > 0 || len(pendingCommits) == 0 {
+			return
 		}
-
-		remainingBudget := config.Budget - totalCost
-		fmt.Fprintf(os.Stderr, "\n[loop] === Task %d/%d: %s ===\n",
-			i+1, len(tasks), TruncateMiddle(task, 60))
-		fmt.Fprintf(os.Stderr, "[loop] Budget remaining: $%.2f\n", remainingBudget)
-
-		taskStart := time.Now()
-
-		if config.OrchConfig.Verbose {
-			complexity := EstimateComplexity(task)
-			fmt.Fprintf(os.Stderr, "[loop] Complexity %d/10 (informational only)\n", complexity)
+		if config.SquashOnLoopEnd {
+			squashQueue = append(squashQueue, pendingCommits...)
+			pendingCommits = nil
+			return
 		}
-
-		// Dispatch via orchestration pipeline
-		orchResult, orchErr := RunOrchestration(d, task, config.OrchConfig)
-
-		taskDuration := time.Since(taskStart)
-
-		// Determine status
-		var status string
-		var runCost float64
-		var taskNodeID string
-
-		if orchResult != nil {
-			runCost = orchResult.TotalCost
-			taskNodeID = orchResult.TaskNodeID
+		for _, p := range pendingCommits {
+			autoCommit(p, workDir, config.SignCommits)
 		}
+		pendingCommits = nil
+	}
 
-		if orchErr == nil {
-			status = "verified"
-		} else {
-			errMsg := orchErr.Error()
-			if strings.Contains(errMsg, "Escalation") ||
-				strings.Contains(errMsg, "bounce") ||
-				strings.Contains(errMsg, "exhausted") {
-				status = "escalated"
-			} else {
-				status = "failed"
-			}
+	// retryOrFail is called from processCompletion for a non-verified
+	// outcome: if c's task has retries left, it's re-marked pending for
+	// another dispatch pass; otherwise its ID is recorded as permanently
+	// failed so any dependent task can be skipped instead of waiting on a
+	// dependency that will never verify.
+	retryOrFail := func(c taskCompletion) {
+		if c.maxRetries > 0 && retries[c.id] < c.maxRetries {
+			retries[c.id]++
+			pending[c.index] = true
+			fmt.Fprintf(os.Stderr, "[loop] Task %d/%d: retrying (%d/%d)\n",
+				c.index+1, c.total, retries[c.id], c.maxRetries)
+			return
 		}
+		failedIDs[c.id] = true
+	}
+
+	scope := enforcementScope{Experiment: config.OrchConfig.Experiment, Role: RoleSummarizer}
+
+	// processCompletion folds one finished task's outcome into the shared
+	// loop state and returns true if the loop should stop dispatching new
+	// tasks. Completions are handled in whatever order workers finish, so
+	// "consecutive" escalations/logging below refer to completion order,
+	// not dispatch order -- the "=== Task i/M ===" banner at dispatch time
+	// (printed from the sequential loop further down) is what preserves the
+	// original ordered progress log; each completion line is tagged with
+	// its own "i/M" so a reader can still match it back up.
+	processCompletion := func(c taskCompletion) bool {
+		totalCost += c.runCost - c.reservedCost
+		reserved -= c.reservedCost
 
 		taskResult := LoopTaskResult{
-			Task:       task,
-			Status:     status,
-			Cost:       runCost,
-			Duration:   taskDuration,
-			TaskNodeID: taskNodeID,
+			Task:       c.task,
+			ID:         c.id,
+			Status:     c.status,
+			Cost:       c.runCost,
+			Duration:   c.duration,
+			TaskNodeID: c.taskNodeID,
+			Assertions: c.assertions,
 		}
 
-		totalCost += runCost
-
-		// Persist state immediately
 		state.recordResult(&taskResult)
 		if err := state.save(); err != nil {
 			fmt.Fprintf(os.Stderr, "[loop] Warning: failed to persist loop state: %v\n", err)
 		}
 
-		// Print status
-		switch status {
+		defaultCommitAction := ActionDryRun
+		if config.AutoCommit {
+			defaultCommitAction = ActionBounce
+		}
+		commitAction := config.Enforcement.Resolve("commit", "verified", scope, defaultCommitAction)
+
+		defaultEscalationAction := ActionWarn
+		if config.PauseOnEscalation {
+			defaultEscalationAction = ActionPause
+		}
+		escalationAction := config.Enforcement.Resolve("summarizer", "escalation", scope, defaultEscalationAction)
+
+		stop := false
+		switch c.status {
 		case "verified":
 			consecutiveEscalations = 0
-			fmt.Fprintf(os.Stderr, "[loop] VERIFIED: $%.2f, %s\n",
-				runCost, FormatDurationShort(taskDuration.Milliseconds()))
-
-			// Auto-commit between tasks
-			if config.AutoCommit && i+1 < len(tasks) {
-				autoCommit(task, workDir)
+			fmt.Fprintf(os.Stderr, "[loop] Task %d/%d VERIFIED: $%.2f, %s\n",
+				c.index+1, c.total, c.runCost, FormatDurationShort(c.duration.Milliseconds()))
+
+			switch commitAction {
+			case ActionBounce:
+				if c.index+1 < c.total {
+					pendingCommits = append(pendingCommits, pendingCommit{
+						task:       c.task,
+						taskID:     c.id,
+						taskNodeID: c.taskNodeID,
+						runID:      c.runID,
+						cost:       c.runCost,
+						duration:   c.duration,
+					})
+				}
+			case ActionDryRun:
+				fmt.Fprintf(os.Stderr, "[loop] dryrun: would auto-commit, skipping\n")
+			case ActionDeny:
+				fmt.Fprintf(os.Stderr, "[loop] deny: auto-commit suppressed by enforcement policy\n")
 			}
 
 		case "escalated":
 			consecutiveEscalations++
-			fmt.Fprintf(os.Stderr, "[loop] ESCALATED: #%d consecutive -- %s\n",
-				consecutiveEscalations, TruncateMiddle(task, 50))
-			if config.PauseOnEscalation {
-				fmt.Fprintf(os.Stderr, "[loop] --pause-on-escalation: stopping loop\n")
-				results = append(results, taskResult)
-				break
+			fmt.Fprintf(os.Stderr, "[loop] Task %d/%d ESCALATED: #%d consecutive -- %s\n",
+				c.index+1, c.total, consecutiveEscalations, TruncateMiddle(c.task, 50))
+			if escalationAction == ActionPause {
+				fmt.Fprintf(os.Stderr, "[loop] pause: stopping loop\n")
+				stop = true
 			}
+			retryOrFail(c)
 
 		case "failed":
 			errMsg := "unknown"
-			if orchErr != nil {
-				errMsg = orchErr.Error()
+			if c.orchErr != nil {
+				errMsg = c.orchErr.Error()
+			}
+			fmt.Fprintf(os.Stderr, "[loop] Task %d/%d FAILED: %s -- %s\n",
+				c.index+1, c.total, TruncateMiddle(c.task, 50), TruncateMiddle(errMsg, 60))
+			retryOrFail(c)
+
+		case "timeout":
+			fmt.Fprintf(os.Stderr, "[loop] Task %d/%d TIMEOUT: %s\n",
+				c.index+1, c.total, TruncateMiddle(c.task, 50))
+			if config.TreatTimeoutAsEscalation {
+				consecutiveEscalations++
+				if escalationAction == ActionPause {
+					fmt.Fprintf(os.Stderr, "[loop] pause: stopping loop\n")
+					stop = true
+				}
 			}
-			fmt.Fprintf(os.Stderr, "[loop] FAILED: %s -- %s\n",
-				TruncateMiddle(task, 50), TruncateMiddle(errMsg, 60))
+			retryOrFail(c)
 		}
 
-		// Cost anomaly detection: warn if current task cost > 3x running average
-		if len(results) >= 3 && runCost > 0.0 {
-			previousTotal := totalCost - runCost
-			avg := previousTotal / float64(len(results))
-			if avg > 0.0 {
-				ratio := runCost / avg
-				if ratio > 3.0 {
-					fmt.Fprintf(os.Stderr, "[loop] Cost anomaly: $%.2f is %.1fx the average $%.2f\n",
-						runCost, ratio, avg)
-				}
+		// Cost anomaly detection against the rolling median/MAD of costs
+		// completed so far, not a plain running mean -- under Parallelism
+		// > 1 several expensive tasks can finish in the same burst, and a
+		// mean shifts with every one of them (each compares against an
+		// average already pulled toward the others), cascading into
+		// repeated false positives. The median barely moves per outlier.
+		if c.runCost > 0.0 {
+			if anomalous, ratio := costs.isAnomalous(c.runCost); anomalous {
+				fmt.Fprintf(os.Stderr, "[loop] Cost anomaly: $%.2f is %.1fx the rolling median $%.2f\n",
+					c.runCost, ratio, costs.median())
 			}
 		}
+		costs.record(c.runCost)
 
 		results = append(results, taskResult)
 
-		// If we broke out of the switch due to pause-on-escalation, stop the loop
-		if status == "escalated" && config.PauseOnEscalation {
+		if consecutiveEscalations >= stopOnEsc {
+			fmt.Fprintf(os.Stderr, "\n[loop] %d consecutive escalations. Stopping -- likely systemic issue.\n", stopOnEsc)
+			stop = true
+		}
+
+		return stop
+	}
+
+	// waitOne blocks for the next completion, folds it in, and returns
+	// whether the loop should stop dispatching further tasks.
+	waitOne := func() bool {
+		c := <-completions
+		inFlight--
+		stop := processCompletion(c)
+		flushPendingCommits()
+		return stop
+	}
+
+	// drainReady folds in every completion already waiting on the channel
+	// without blocking, so budget/escalation checks before the next dispatch
+	// see the freshest state.
+	drainReady := func() bool {
+		for {
+			select {
+			case c := <-completions:
+				inFlight--
+				if processCompletion(c) {
+					flushPendingCommits()
+					return true
+				}
+			default:
+				flushPendingCommits()
+				return false
+			}
+		}
+	}
+
+	totalTasks := len(tasks)
+
+dispatch:
+	for {
+		if stopped {
+			break
+		}
+		if stopped = drainReady(); stopped {
+			break
+		}
+
+		if err := ctx.Err(); err != nil {
+			fmt.Fprintf(os.Stderr, "\n[loop] Context %v. Stopping.\n", err)
+			break
+		}
+		if totalCost+reserved >= config.Budget {
+			fmt.Fprintf(os.Stderr, "\n[loop] Budget exhausted ($%.2f/$%.2f). Stopping.\n", totalCost+reserved, config.Budget)
+			break
+		}
+		if len(results) >= config.MaxRuns {
+			fmt.Fprintf(os.Stderr, "\n[loop] Max runs reached (%d/%d). Stopping.\n", len(results), config.MaxRuns)
+			break
+		}
+		if consecutiveEscalations >= stopOnEsc {
+			fmt.Fprintf(os.Stderr, "\n[loop] %d consecutive escalations. Stopping -- likely systemic issue.\n", stopOnEsc)
+			break
+		}
+
+		// Scan for the next task whose depends_on are all verified. Tasks
+		// already verified (resumed from a prior run) are dropped from
+		// pending without dispatching; tasks depending on a permanently
+		// failed task are recorded as skipped, same treatment, so they
+		// don't block this scan on every pass.
+		idx := -1
+		for i, t := range tasks {
+			if !pending[i] {
+				continue
+			}
+			if state.isVerified(t.ID) {
+				pending[i] = false
+				fmt.Fprintf(os.Stderr, "[loop] Skipping task %d (already verified)\n", i+1)
+				continue
+			}
+			if state.isTimedOut(t.ID) && !config.RetryTimeouts {
+				pending[i] = false
+				fmt.Fprintf(os.Stderr, "[loop] Skipping task %d (previously timed out; use --retry-timeouts to retry)\n", i+1)
+				continue
+			}
+			if depsDead(t) {
+				pending[i] = false
+				failedIDs[t.ID] = true
+				fmt.Fprintf(os.Stderr, "[loop] Task %d/%d SKIPPED: unmet depends_on -- %s\n",
+					i+1, totalTasks, TruncateMiddle(t.Body, 50))
+				results = append(results, LoopTaskResult{Task: t.Body, ID: t.ID, Status: "failed"})
+				continue
+			}
+			if !depsSatisfied(t) {
+				continue // may become ready once an in-flight dependency completes
+			}
+			idx = i
+			break
+		}
+
+		if idx == -1 {
+			anyPending := false
+			for _, p := range pending {
+				if p {
+					anyPending = true
+					break
+				}
+			}
+			if !anyPending {
+				break // every task dispatched, skipped, or resumed-verified
+			}
+			if inFlight > 0 {
+				// Nothing is ready yet, but an in-flight task may unblock
+				// one once it completes.
+				if stopped = waitOne(); stopped {
+					break
+				}
+				continue
+			}
+			// Nothing in flight and nothing ready: a depends_on cycle, or a
+			// dependency on a task excluded by --run/--skip.
+			remaining := 0
+			for _, p := range pending {
+				if p {
+					remaining++
+				}
+			}
+			fmt.Fprintf(os.Stderr, "\n[loop] %d task(s) stuck on unmet depends_on (cycle, or a dependency outside the selected set). Stopping.\n", remaining)
 			break
 		}
 
-		// Brief pause between dispatches
-		if i+1 < len(tasks) && len(results) < config.MaxRuns {
-			time.Sleep(5 * time.Second)
+		task := tasks[idx]
+		pending[idx] = false
+
+		// Make room in the pool before reserving budget for this task.
+		for inFlight >= parallelism {
+			if stopped = waitOne(); stopped {
+				break dispatch
+			}
+		}
+
+		estimate := costs.median() // 0 until 1+ tasks have completed
+		reserved += estimate
+
+		fmt.Fprintf(os.Stderr, "\n[loop] === Task %d/%d: %s ===\n",
+			idx+1, totalTasks, TruncateMiddle(task.Body, 60))
+		fmt.Fprintf(os.Stderr, "[loop] Budget remaining: $%.2f (reserved $%.2f)\n",
+			config.Budget-totalCost-reserved, reserved)
+
+		if config.OrchConfig.Verbose {
+			complexity := EstimateComplexity(task.Body)
+			fmt.Fprintf(os.Stderr, "[loop] Complexity %d/10 (informational only)\n", complexity)
+		}
+
+		// orch_overrides apply only to this task's dispatch.
+		taskConfig := config.OrchConfig
+		if task.MaxBounces > 0 {
+			taskConfig.MaxBounces = task.MaxBounces
+		}
+		if task.MaxTurns > 0 {
+			taskConfig.MaxTurns = task.MaxTurns
+		}
+		if task.CoderModel != "" {
+			taskConfig.CoderModel = task.CoderModel
+		}
+
+		taskTimeout := task.Timeout
+		if taskTimeout <= 0 {
+			taskTimeout = config.TaskTimeout
+		}
+		taskCtx := ctx
+		var taskCancel context.CancelFunc
+		if taskTimeout > 0 {
+			taskCtx, taskCancel = context.WithTimeout(ctx, taskTimeout)
 		}
+
+		inFlight++
+		go func(index int, task Task, estimate float64, taskCtx context.Context, taskCancel context.CancelFunc, taskConfig OrchestrationConfig) {
+			if taskCancel != nil {
+				defer taskCancel()
+			}
+			taskStart := time.Now()
+			orchResult, orchErr := RunOrchestration(taskCtx, d, task.Body, taskConfig)
+			duration := time.Since(taskStart)
+
+			var runCost float64
+			var taskNodeID, runID string
+			var assertions []assert.AssertionApplied
+			if orchResult != nil {
+				runCost = orchResult.TotalCost
+				taskNodeID = orchResult.TaskNodeID
+				runID = orchResult.RunID
+				assertions = orchResult.Assertions
+			}
+
+			status := "verified"
+			if orchErr != nil {
+				switch {
+				case orchResult != nil && orchResult.Status == StatusTimeout:
+					status = "timeout"
+				case strings.Contains(orchErr.Error(), "Escalation") ||
+					strings.Contains(orchErr.Error(), "bounce") ||
+					strings.Contains(orchErr.Error(), "exhausted"):
+					status = "escalated"
+				default:
+					status = "failed"
+				}
+			}
+
+			// Per-task budget cap is advisory only: RunOrchestration has no
+			// mid-run cost ceiling to enforce against, so this can only be
+			// checked after the fact, once the spend already happened.
+			if task.BudgetUSD > 0 && runCost > task.BudgetUSD {
+				fmt.Fprintf(os.Stderr, "[loop] Warning: task %q cost $%.2f, over its per-task budget of $%.2f\n",
+					task.ID, runCost, task.BudgetUSD)
+			}
+
+			completions <- taskCompletion{
+				index:        index,
+				total:        totalTasks,
+				task:         task.Body,
+				id:           task.ID,
+				maxRetries:   task.MaxRetries,
+				status:       status,
+				runCost:      runCost,
+				reservedCost: estimate,
+				duration:     duration,
+				taskNodeID:   taskNodeID,
+				runID:        runID,
+				orchErr:      orchErr,
+				assertions:   assertions,
+			}
+		}(idx, task, estimate, taskCtx, taskCancel, taskConfig)
+	}
+
+	// Drain every task still in flight, whether the loop exhausted its
+	// tasks naturally or stopped early.
+	for inFlight > 0 {
+		waitOne()
+	}
+
+	if config.SquashOnLoopEnd && len(squashQueue) > 0 {
+		squashCommit(squashQueue, workDir, config.SignCommits)
 	}
 
 	totalDuration := time.Since(loopStart)
 
 	printLoopSummary(results, totalCost, config.Budget, totalDuration, config.OrchConfig.JSON)
 
+	stats := lessonStore.Stats()
 	return &LoopResult{
-		Tasks:     results,
-		TotalCost: totalCost,
-		Duration:  totalDuration,
+		Tasks:      results,
+		TotalCost:  totalCost,
+		Duration:   totalDuration,
+		StoreStats: &stats,
 	}, nil
 }
 
-// ReadTasks reads task descriptions from a file or stdin.
-// Supports two formats:
-//  1. One task per line
-//  2. Multi-line tasks separated by "---" on its own line
-//
-// In both formats, blank lines and lines starting with '#' are skipped.
-func ReadTasks(source string) ([]string, error) {
+// readSource reads the raw contents of a task source: a file path, or "-"
+// for stdin. Shared by ReadTasks and ReadTaskFile so both formats go through
+// the same open/read logic.
+func readSource(source string) ([]byte, error) {
 	var reader io.Reader
 	if source == "-" {
 		reader = os.Stdin
@@ -283,6 +815,20 @@ func ReadTasks(source string) ([]string, error) {
 	if err != nil {
 		return nil, fmt.Errorf("reading tasks: %w", err)
 	}
+	return content, nil
+}
+
+// ReadTasks reads task descriptions from a file or stdin.
+// Supports two formats:
+//  1. One task per line
+//  2. Multi-line tasks separated by "---" on its own line
+//
+// In both formats, blank lines and lines starting with '#' are skipped.
+func ReadTasks(source string) ([]string, error) {
+	content, err := readSource(source)
+	if err != nil {
+		return nil, err
+	}
 
 	tasks := parseTaskContent(string(content))
 	if len(tasks) == 0 {
@@ -355,19 +901,90 @@ func flushTaskSection(lines []string) string {
 	return strings.Join(parts, " ")
 }
 
+// TaskSpec is a task description plus the optional `name:`/`tags: [...]`
+// header metadata used by --run/--skip selection (see taskmatch).
+type TaskSpec struct {
+	Text string
+	Name string
+	Tags []string
+}
+
+// ReadTaskSpecs is ReadTasks with name:/tags: header extraction. Only the
+// "---"-delimited multi-line format supports headers; the one-per-line
+// format has no room for them and every TaskSpec.Name is "".
+func ReadTaskSpecs(source string) ([]TaskSpec, error) {
+	tasks, err := ReadTasks(source)
+	if err != nil {
+		return nil, err
+	}
+	specs := make([]TaskSpec, 0, len(tasks))
+	for _, t := range tasks {
+		specs = append(specs, parseTaskHeader(t))
+	}
+	return specs, nil
+}
+
+// parseTaskHeader extracts a leading "name: <name>" and/or "tags: [a, b]"
+// clause from a flattened task string (flushTaskSection joins a section's
+// lines with spaces, so headers appear as leading space-separated clauses
+// rather than their own lines).
+func parseTaskHeader(task string) TaskSpec {
+	spec := TaskSpec{Text: task}
+	rest := task
+	for {
+		switch {
+		case strings.HasPrefix(rest, "name:"):
+			rest = strings.TrimPrefix(rest, "name:")
+			rest = strings.TrimLeft(rest, " ")
+			end := strings.IndexByte(rest, ' ')
+			if end < 0 {
+				spec.Name = rest
+				rest = ""
+			} else {
+				spec.Name = rest[:end]
+				rest = strings.TrimLeft(rest[end+1:], " ")
+			}
+			continue
+		case strings.HasPrefix(rest, "tags:"):
+			rest = strings.TrimPrefix(rest, "tags:")
+			rest = strings.TrimLeft(rest, " ")
+			if !strings.HasPrefix(rest, "[") {
+				break
+			}
+			end := strings.IndexByte(rest, ']')
+			if end < 0 {
+				break
+			}
+			raw := rest[1:end]
+			for _, tag := range strings.Split(raw, ",") {
+				tag = strings.TrimSpace(tag)
+				if tag != "" {
+					spec.Tags = append(spec.Tags, tag)
+				}
+			}
+			rest = strings.TrimLeft(rest[end+1:], " ")
+			continue
+		}
+		break
+	}
+	spec.Text = rest
+	return spec
+}
+
 // ---------------------------------------------------------------------------
 // Loop State Persistence
 // ---------------------------------------------------------------------------
 
 // loopState tracks verified tasks and cumulative cost across loop restarts.
 type loopState struct {
-	Source        string            `json:"source"`
-	VerifiedTasks map[string]bool  `json:"verified_tasks"`
-	TotalCost     float64          `json:"total_cost"`
-	Runs          []loopStateRun   `json:"runs"`
-	CreatedAt     string           `json:"created_at"`
-	UpdatedAt     string           `json:"updated_at"`
-	path          string           // filesystem path (not serialized)
+	Source        string          `json:"source"`
+	VerifiedTasks map[string]bool `json:"verified_tasks"`
+	TimedOutTasks map[string]bool `json:"timed_out_tasks,omitempty"`
+	TotalCost     float64         `json:"total_cost"`
+	Runs          []loopStateRun  `json:"runs"`
+	CreatedAt     string          `json:"created_at"`
+	UpdatedAt     string          `json:"updated_at"`
+	path          string          // filesystem path (not serialized)
 }
 
 type loopStateRun struct {
@@ -384,6 +1001,7 @@ func newLoopState(path, source string) *loopState {
 	return &loopState{
 		Source:        source,
 		VerifiedTasks: make(map[string]bool),
+		TimedOutTasks: make(map[string]bool),
 		Runs:          nil,
 		CreatedAt:     now,
 		UpdatedAt:     now,
@@ -404,6 +1022,9 @@ func loadLoopState(path, source string) *loopState {
 	if state.VerifiedTasks == nil {
 		state.VerifiedTasks = make(map[string]bool)
 	}
+	if state.TimedOutTasks == nil {
+		state.TimedOutTasks = make(map[string]bool)
+	}
 	return &state
 }
 
@@ -423,13 +1044,39 @@ func (s *loopState) save() error {
 	return nil
 }
 
-func (s *loopState) isVerified(task string) bool {
-	return s.VerifiedTasks[task]
+// isVerified takes a Task.ID (or, for the legacy VerifiedTasks callers
+// predating Task, a raw task string) -- whatever recordResult was given as
+// the key for that task.
+func (s *loopState) isVerified(key string) bool {
+	return s.VerifiedTasks[key]
 }
 
+// isTimedOut reports whether key's most recent recorded outcome was a
+// timeout, per the same key convention as isVerified.
+func (s *loopState) isTimedOut(key string) bool {
+	return s.TimedOutTasks[key]
+}
+
+// recordResult keys VerifiedTasks/TimedOutTasks by r.ID when set (a manifest
+// task's stable ID, or a legacy task's taskHash(body)), falling back to
+// r.Task for callers that predate Task/ID (e.g. tests built directly against
+// LoopTaskResult).
 func (s *loopState) recordResult(r *LoopTaskResult) {
-	if r.Status == "verified" {
-		s.VerifiedTasks[r.Task] = true
+	key := r.ID
+	if key == "" {
+		key = r.Task
+	}
+	if s.TimedOutTasks == nil {
+		s.TimedOutTasks = make(map[string]bool)
+	}
+	switch r.Status {
+	case "verified":
+		s.VerifiedTasks[key] = true
+		delete(s.TimedOutTasks, key)
+	case "timeout":
+		s.TimedOutTasks[key] = true
+	default:
+		delete(s.TimedOutTasks, key)
 	}
 	s.TotalCost += r.Cost
 	s.Runs = append(s.Runs, loopStateRun{
@@ -462,19 +1109,78 @@ func loopStatePath(source string) string {
 // Auto-commit
 // ---------------------------------------------------------------------------
 
-// autoCommit stages and commits changes between loop tasks, excluding internal artifacts.
-func autoCommit(task, workDir string) {
+// commitTrailers renders p's Mycelica-* trailer block as it appears at the
+// end of a commit message, in the exact "Key: value"-per-line format
+// commitTrailer (blame.go) scans for. role is the agent role trailer to
+// attribute the change to; taskNodeID/cost/duration are omitted when unset,
+// since not every caller (e.g. pre-chunk11-2 task sources) has them.
+func commitTrailers(p pendingCommit, role AgentRole) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "%s: %s\n%s: %s", blameRunIDTrailer, p.runID, blameAgentRoleTrailer, role)
+	if p.taskID != "" {
+		fmt.Fprintf(&b, "\n%s: %s", blameTaskIDTrailer, p.taskID)
+	}
+	if p.taskNodeID != "" {
+		fmt.Fprintf(&b, "\n%s: %s", blameTaskNodeTrailer, p.taskNodeID)
+	}
+	fmt.Fprintf(&b, "\n%s: %.4f", blameCostTrailer, p.cost)
+	fmt.Fprintf(&b, "\n%s: %d", blameDurationTrailer, p.duration.Milliseconds())
+	return b.String()
+}
+
+// classifyCommitType maps a task's description to a Conventional Commits
+// type via keyword heuristics, the same sequential-Contains style
+// EstimateComplexity (claude.go) uses for task classification -- fix/test/
+// docs/refactor are checked ahead of the feat default since they're the
+// more specific, less ambiguous signal.
+func classifyCommitType(task string) string {
+	lower := strings.ToLower(task)
+
+	if strings.Contains(lower, "fix") || strings.Contains(lower, "bug") ||
+		strings.Contains(lower, "regression") || strings.Contains(lower, "crash") {
+		return "fix"
+	}
+	if strings.Contains(lower, "test") || strings.Contains(lower, "coverage") {
+		return "test"
+	}
+	if strings.Contains(lower, "doc") || strings.Contains(lower, "readme") {
+		return "docs"
+	}
+	if strings.Contains(lower, "refactor") || strings.Contains(lower, "rename") ||
+		strings.Contains(lower, "cleanup") || strings.Contains(lower, "restructure") {
+		return "refactor"
+	}
+	return "feat"
+}
+
+// gitCommit runs `git commit` for msg in workDir, appending -S when sign is
+// set. Returns the command's combined output for the caller to log on
+// failure.
+func gitCommit(workDir, msg string, sign bool) ([]byte, error) {
+	args := []string{"commit", "-m", msg, "--allow-empty"}
+	if sign {
+		args = append(args, "-S")
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = workDir
+	return cmd.CombinedOutput()
+}
+
+// autoCommit stages and commits changes for one verified task, excluding
+// internal artifacts. The subject is a Conventional Commits line classified
+// from the task text; the body carries the Mycelica-Run-ID/Agent-Role/
+// Task-Id/Task-Node/Cost-USD/Duration-Ms trailers so BlameRun and downstream
+// tooling can reconstruct the run without querying the graph.
+func autoCommit(p pendingCommit, workDir string, sign bool) {
 	staged := selectiveGitAdd(workDir)
 	if !staged {
 		return
 	}
 
-	shortDesc := TruncateMiddle(task, 50)
-	msg := fmt.Sprintf("feat(loop): %s", shortDesc)
+	shortDesc := TruncateMiddle(p.task, 50)
+	msg := fmt.Sprintf("%s(loop): %s\n\n%s", classifyCommitType(p.task), shortDesc, commitTrailers(p, RoleCoder))
 
-	cmd := exec.Command("git", "commit", "-m", msg, "--allow-empty")
-	cmd.Dir = workDir
-	out, err := cmd.CombinedOutput()
+	out, err := gitCommit(workDir, msg, sign)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[loop] No changes to commit (or commit failed): %s\n",
 			strings.TrimSpace(string(out)))
@@ -483,6 +1189,35 @@ func autoCommit(task, workDir string) {
 	fmt.Fprintf(os.Stderr, "[loop] Auto-committed changes before next task\n")
 }
 
+// squashCommit stages changes once and produces a single commit for every
+// task in queue, used when config.SquashOnLoopEnd defers per-task commits
+// to the end of RunLoop. The body enumerates each task with its own
+// trailer block, in dispatch order.
+func squashCommit(queue []pendingCommit, workDir string, sign bool) {
+	staged := selectiveGitAdd(workDir)
+	if !staged {
+		return
+	}
+
+	var body strings.Builder
+	for i, p := range queue {
+		if i > 0 {
+			body.WriteString("\n\n")
+		}
+		fmt.Fprintf(&body, "- %s(loop): %s\n%s", classifyCommitType(p.task), TruncateMiddle(p.task, 50), commitTrailers(p, RoleCoder))
+	}
+
+	msg := fmt.Sprintf("feat(loop): squash %d verified task(s)\n\n%s", len(queue), body.String())
+
+	out, err := gitCommit(workDir, msg, sign)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[loop] No changes to commit (or commit failed): %s\n",
+			strings.TrimSpace(string(out)))
+		return
+	}
+	fmt.Fprintf(os.Stderr, "[loop] Auto-committed %d squashed task(s)\n", len(queue))
+}
+
 // selectiveGitAdd stages tracked modifications and selectively adds new untracked files,
 // excluding internal artifacts. Returns true if staging succeeded.
 func selectiveGitAdd(workDir string) bool {
@@ -501,13 +1236,15 @@ func selectiveGitAdd(workDir string) bool {
 		return true // tracked file staging succeeded, untracked listing failed -- ok
 	}
 
+	matcher := LoadExcludeMatcher(workDir)
+
 	var toAdd []string
 	for _, line := range strings.Split(string(out), "\n") {
 		line = strings.TrimSpace(line)
 		if line == "" {
 			continue
 		}
-		if !ShouldExcludeFile(line) {
+		if !matcher.ShouldExclude(line) {
 			toAdd = append(toAdd, line)
 		}
 	}
@@ -523,7 +1260,9 @@ func selectiveGitAdd(workDir string) bool {
 }
 
 // ShouldExcludeFile returns true for files that should not be auto-committed by the loop.
-// Matches is_spore_excluded from spore.rs.
+// Matches is_spore_excluded from spore.rs. This is the built-in default set;
+// see ExcludeMatcher for the work-dir-configurable superset autoCommit
+// actually applies.
 func ShouldExcludeFile(path string) bool {
 	basename := filepath.Base(path)
 
@@ -549,6 +1288,60 @@ func ShouldExcludeFile(path string) bool {
 	return false
 }
 
+// myceligitignoreFile is the work-dir-relative file ExcludeMatcher reads
+// extra exclusion patterns from, alongside ShouldExcludeFile's hard-coded
+// defaults -- one glob pattern per line, blank lines and #-comments ignored.
+const myceligitignoreFile = ".myceligitignore"
+
+// ExcludeMatcher decides whether autoCommit should stage a given path,
+// combining ShouldExcludeFile's built-in defaults with extra glob patterns
+// loaded from myceligitignoreFile in the work dir.
+type ExcludeMatcher struct {
+	patterns []string
+}
+
+// LoadExcludeMatcher reads myceligitignoreFile from dir. A missing file
+// just means no extra patterns -- ShouldExcludeFile's defaults still apply.
+func LoadExcludeMatcher(dir string) *ExcludeMatcher {
+	data, err := os.ReadFile(filepath.Join(dir, myceligitignoreFile))
+	if err != nil {
+		return &ExcludeMatcher{}
+	}
+
+	var patterns []string
+	for _, line := range strings.Split(string(data), "\n") {
+		line = strings.TrimSpace(line)
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return &ExcludeMatcher{patterns: patterns}
+}
+
+// ShouldExclude reports whether path should be skipped: ShouldExcludeFile's
+// defaults first, then m's patterns matched against both the full path and
+// its basename (filepath.Match globs, plus a directory-prefix match for
+// patterns ending in "/").
+func (m *ExcludeMatcher) ShouldExclude(path string) bool {
+	if ShouldExcludeFile(path) {
+		return true
+	}
+	base := filepath.Base(path)
+	for _, p := range m.patterns {
+		if strings.HasSuffix(p, "/") && strings.HasPrefix(path, p) {
+			return true
+		}
+		if matched, _ := filepath.Match(p, path); matched {
+			return true
+		}
+		if matched, _ := filepath.Match(p, base); matched {
+			return true
+		}
+	}
+	return false
+}
+
 // ---------------------------------------------------------------------------
 // Summary
 // ---------------------------------------------------------------------------
@@ -558,6 +1351,7 @@ func printLoopSummary(results []LoopTaskResult, totalCost, budget float64, durat
 	verified := 0
 	escalated := 0
 	failed := 0
+	timedOut := 0
 	for _, r := range results {
 		switch r.Status {
 		case "verified":
@@ -566,6 +1360,8 @@ func printLoopSummary(results []LoopTaskResult, totalCost, budget float64, durat
 			escalated++
 		case "failed":
 			failed++
+		case "timeout":
+			timedOut++
 		}
 	}
 	avgCost := 0.0
@@ -591,20 +1387,22 @@ func printLoopSummary(results []LoopTaskResult, totalCost, budget float64, durat
 		}
 
 		output := struct {
-			TasksDispatched int       `json:"tasks_dispatched"`
-			Verified        int       `json:"verified"`
-			Escalated       int       `json:"escalated"`
-			Failed          int       `json:"failed"`
-			TotalCost       float64   `json:"total_cost"`
-			Budget          float64   `json:"budget"`
-			AvgCostPerTask  float64   `json:"avg_cost_per_task"`
-			TotalDurationMS int64     `json:"total_duration_ms"`
+			TasksDispatched int        `json:"tasks_dispatched"`
+			Verified        int        `json:"verified"`
+			Escalated       int        `json:"escalated"`
+			Failed          int        `json:"failed"`
+			Timeout         int        `json:"timeout"`
+			TotalCost       float64    `json:"total_cost"`
+			Budget          float64    `json:"budget"`
+			AvgCostPerTask  float64    `json:"avg_cost_per_task"`
+			TotalDurationMS int64      `json:"total_duration_ms"`
 			Tasks           []jsonTask `json:"tasks"`
 		}{
 			TasksDispatched: total,
 			Verified:        verified,
 			Escalated:       escalated,
 			Failed:          failed,
+			Timeout:         timedOut,
 			TotalCost:       totalCost,
 			Budget:          budget,
 			AvgCostPerTask:  avgCost,
@@ -627,6 +1425,7 @@ func printLoopSummary(results []LoopTaskResult, totalCost, budget float64, durat
 	fmt.Fprintf(os.Stderr, "  Verified:         %d (%.0f%%)\n", verified, rate)
 	fmt.Fprintf(os.Stderr, "  Escalated:        %d\n", escalated)
 	fmt.Fprintf(os.Stderr, "  Failed:           %d\n", failed)
+	fmt.Fprintf(os.Stderr, "  Timeout:          %d\n", timedOut)
 	fmt.Fprintf(os.Stderr, "  Total cost:       $%.2f / $%.2f budget\n", totalCost, budget)
 	fmt.Fprintf(os.Stderr, "  Avg cost/task:    $%.2f\n", avgCost)
 	fmt.Fprintf(os.Stderr, "  Total duration:   %s\n", FormatDurationShort(duration.Milliseconds()))
@@ -1,9 +1,11 @@
 package orchestrate
 
 import (
+	"context"
 	"os"
 	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestReadTasks_File(t *testing.T) {
@@ -132,6 +134,40 @@ func TestLoopState_Persistence(t *testing.T) {
 	}
 }
 
+func TestLoopState_TimedOutTaskSkippedOnResumeUnlessRetried(t *testing.T) {
+	dir := t.TempDir()
+	statePath := filepath.Join(dir, "test.loop-state.json")
+
+	state := newLoopState(statePath, "test-source.txt")
+	state.recordResult(&LoopTaskResult{
+		ID:     "t1",
+		Task:   "slow task",
+		Status: "timeout",
+		Cost:   0.25,
+	})
+	if err := state.save(); err != nil {
+		t.Fatalf("save: %v", err)
+	}
+
+	loaded := loadLoopState(statePath, "test-source.txt")
+	if !loaded.isTimedOut("t1") {
+		t.Error("expected 't1' to be recorded as timed out")
+	}
+	if loaded.isVerified("t1") {
+		t.Error("a timed-out task should not be marked verified")
+	}
+
+	// A later verified result for the same ID clears the timeout marker,
+	// so retries that eventually succeed don't stay flagged forever.
+	loaded.recordResult(&LoopTaskResult{ID: "t1", Task: "slow task", Status: "verified", Cost: 0.10})
+	if loaded.isTimedOut("t1") {
+		t.Error("expected timeout marker to clear once the task verifies")
+	}
+	if !loaded.isVerified("t1") {
+		t.Error("expected 't1' to be verified after a later verified result")
+	}
+}
+
 func TestLoopState_Reset(t *testing.T) {
 	dir := t.TempDir()
 	statePath := filepath.Join(dir, "test.loop-state.json")
@@ -195,6 +231,60 @@ func TestShouldExcludeFile(t *testing.T) {
 	}
 }
 
+func TestClassifyCommitType(t *testing.T) {
+	tests := []struct {
+		task string
+		want string
+	}{
+		{"Fix the nil pointer crash in the loop dispatcher", "fix"},
+		{"Add test coverage for the cost tracker", "test"},
+		{"Update the README with the new flags", "docs"},
+		{"Refactor the verdict matcher for clarity", "refactor"},
+		{"Add per-task timeout support", "feat"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.task, func(t *testing.T) {
+			got := classifyCommitType(tt.task)
+			if got != tt.want {
+				t.Errorf("classifyCommitType(%q) = %q, want %q", tt.task, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestExcludeMatcher_FallsBackToDefaults(t *testing.T) {
+	dir := t.TempDir()
+	m := LoadExcludeMatcher(dir)
+
+	if !m.ShouldExclude("target/release/binary") {
+		t.Error("expected built-in default target/ exclusion to still apply")
+	}
+	if m.ShouldExclude("src/lib.rs") {
+		t.Error("src/lib.rs should not be excluded")
+	}
+}
+
+func TestExcludeMatcher_LoadsExtraPatterns(t *testing.T) {
+	dir := t.TempDir()
+	content := "# comment, ignored\n*.generated.go\nvendor/\n"
+	if err := os.WriteFile(filepath.Join(dir, ".myceligitignore"), []byte(content), 0644); err != nil {
+		t.Fatalf("writing .myceligitignore: %v", err)
+	}
+
+	m := LoadExcludeMatcher(dir)
+
+	if !m.ShouldExclude("api.generated.go") {
+		t.Error("expected *.generated.go pattern to exclude api.generated.go")
+	}
+	if !m.ShouldExclude("vendor/foo/bar.go") {
+		t.Error("expected vendor/ pattern to exclude files under it")
+	}
+	if m.ShouldExclude("src/lib.rs") {
+		t.Error("src/lib.rs should not be excluded")
+	}
+}
+
 func TestLoopStatePath(t *testing.T) {
 	tests := []struct {
 		source string
@@ -240,7 +330,7 @@ func TestRunLoop_DryRun(t *testing.T) {
 		},
 	}
 
-	result, err := RunLoop(d, config)
+	result, err := RunLoop(context.Background(), d, config)
 	if err != nil {
 		t.Fatalf("RunLoop DryRun: %v", err)
 	}
@@ -278,6 +368,189 @@ func TestParseTaskContent(t *testing.T) {
 	}
 }
 
+func TestCostTracker_Median(t *testing.T) {
+	var c costTracker
+	if got := c.median(); got != 0 {
+		t.Errorf("median of empty tracker = %f, want 0", got)
+	}
+	for _, cost := range []float64{1.0, 3.0, 2.0} {
+		c.record(cost)
+	}
+	if got := c.median(); got != 2.0 {
+		t.Errorf("median([1,3,2]) = %f, want 2.0", got)
+	}
+	c.record(4.0)
+	if got := c.median(); got != 2.5 {
+		t.Errorf("median([1,2,3,4]) = %f, want 2.5", got)
+	}
+}
+
+func TestCostTracker_IsAnomalous(t *testing.T) {
+	var c costTracker
+	for _, cost := range []float64{1.0, 1.1, 0.9} {
+		c.record(cost)
+	}
+	// Fewer than 3 prior completions: no verdict regardless of value.
+	var empty costTracker
+	empty.record(1.0)
+	empty.record(1.0)
+	if anomalous, _ := empty.isAnomalous(100.0); anomalous {
+		t.Error("expected no anomaly verdict with fewer than 3 prior completions")
+	}
+
+	if anomalous, ratio := c.isAnomalous(1.05); anomalous {
+		t.Errorf("expected 1.05 to be unremarkable near median ~1.0, got anomalous (ratio %f)", ratio)
+	}
+	if anomalous, ratio := c.isAnomalous(50.0); !anomalous {
+		t.Errorf("expected 50.0 to be anomalous against a ~1.0 median, ratio=%f", ratio)
+	}
+}
+
+func TestCostTracker_IsAnomalous_ZeroMADFallsBackToRatio(t *testing.T) {
+	var c costTracker
+	for i := 0; i < 4; i++ {
+		c.record(1.0) // MAD == 0 since every prior cost is identical
+	}
+	if anomalous, _ := c.isAnomalous(1.0); anomalous {
+		t.Error("expected no anomaly when cost matches a zero-MAD history")
+	}
+	if anomalous, ratio := c.isAnomalous(5.0); !anomalous {
+		t.Errorf("expected 5x a zero-MAD median of 1.0 to be anomalous, ratio=%f", ratio)
+	}
+}
+
+func TestDuration_UnmarshalText(t *testing.T) {
+	var d Duration
+	if err := d.UnmarshalText([]byte("15m")); err != nil {
+		t.Fatalf("UnmarshalText: %v", err)
+	}
+	if d.Duration != 15*time.Minute {
+		t.Errorf("Duration = %v, want 15m", d.Duration)
+	}
+
+	var bad Duration
+	if err := bad.UnmarshalText([]byte("not-a-duration")); err == nil {
+		t.Error("expected an error for an invalid duration string")
+	}
+}
+
+func TestReadTaskFile_LegacyFormat(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.txt")
+	content := "Implement feature A\nFix bug in parser\n"
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	tasks, err := ReadTaskFile(path)
+	if err != nil {
+		t.Fatalf("ReadTaskFile: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d: %+v", len(tasks), tasks)
+	}
+	if tasks[0].Body != "Implement feature A" {
+		t.Errorf("tasks[0].Body = %q", tasks[0].Body)
+	}
+	if tasks[0].ID == "" {
+		t.Error("expected a non-empty fallback ID for a legacy task")
+	}
+	if tasks[0].ID != taskHash(tasks[0].Body) {
+		t.Errorf("tasks[0].ID = %q, want taskHash(Body) = %q", tasks[0].ID, taskHash(tasks[0].Body))
+	}
+}
+
+func TestReadTaskFile_Manifest(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.yaml")
+	content := `
+- id: build
+  prompt: Implement the build step
+  budget_usd: 2.5
+  timeout: 10m
+  max_retries: 1
+  tags: [infra]
+  orch_overrides:
+    max_bounces: 5
+    max_turns: 80
+    model: claude-sonnet
+- id: test
+  prompt: Write tests for the build step
+  depends_on: [build]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	tasks, err := ReadTaskFile(path)
+	if err != nil {
+		t.Fatalf("ReadTaskFile: %v", err)
+	}
+	if len(tasks) != 2 {
+		t.Fatalf("expected 2 tasks, got %d: %+v", len(tasks), tasks)
+	}
+	build := tasks[0]
+	if build.ID != "build" || build.Body != "Implement the build step" {
+		t.Errorf("tasks[0] = %+v", build)
+	}
+	if build.BudgetUSD != 2.5 || build.Timeout != 10*time.Minute || build.MaxRetries != 1 {
+		t.Errorf("tasks[0] overrides = %+v", build)
+	}
+	if build.MaxBounces != 5 || build.MaxTurns != 80 || build.CoderModel != "claude-sonnet" {
+		t.Errorf("tasks[0] orch_overrides = %+v", build)
+	}
+	test := tasks[1]
+	if len(test.DependsOn) != 1 || test.DependsOn[0] != "build" {
+		t.Errorf("tasks[1].DependsOn = %v, want [build]", test.DependsOn)
+	}
+}
+
+func TestReadTaskFile_ManifestRejectsUnknownDependency(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "tasks.yaml")
+	content := `
+- id: test
+  prompt: Write tests
+  depends_on: [nonexistent]
+`
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatalf("writing temp file: %v", err)
+	}
+
+	if _, err := ReadTaskFile(path); err == nil {
+		t.Fatal("expected an error for depends_on referencing an unknown id")
+	}
+}
+
+func TestTopoSort_OrdersDependenciesFirst(t *testing.T) {
+	tasks := []Task{
+		{ID: "c", DependsOn: []string{"a", "b"}},
+		{ID: "a"},
+		{ID: "b", DependsOn: []string{"a"}},
+	}
+	ordered, err := topoSort(tasks)
+	if err != nil {
+		t.Fatalf("topoSort: %v", err)
+	}
+	pos := make(map[string]int, len(ordered))
+	for i, t := range ordered {
+		pos[t.ID] = i
+	}
+	if pos["a"] > pos["b"] || pos["b"] > pos["c"] {
+		t.Errorf("expected order a, b, c; got %v", []string{ordered[0].ID, ordered[1].ID, ordered[2].ID})
+	}
+}
+
+func TestTopoSort_DetectsCycle(t *testing.T) {
+	tasks := []Task{
+		{ID: "a", DependsOn: []string{"b"}},
+		{ID: "b", DependsOn: []string{"a"}},
+	}
+	if _, err := topoSort(tasks); err == nil {
+		t.Fatal("expected a cycle error")
+	}
+}
+
 // contains is a test helper.
 func contains(s, substr string) bool {
 	return len(s) >= len(substr) && searchSubstring(s, substr)
@@ -0,0 +1,338 @@
+package orchestrate
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-git/go-git/v5/plumbing"
+	fdiff "github.com/go-git/go-git/v5/plumbing/format/diff"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// mergeAgentRunsTool is the MCP tool name MergeResult is meant to back, so
+// the operator role can pick a resolution per conflicting hunk. As with
+// blameLineTool, the MCP server itself lives outside this source tree.
+const mergeAgentRunsTool = "mcp__mycelica__merge_agent_runs"
+
+// RunRef identifies one concurrent coder run's branch tip, for MergeAgentRuns
+// to reconcile against its sibling runs.
+type RunRef struct {
+	RunID  string `json:"run_id"`
+	Branch string `json:"branch"`
+}
+
+// CleanMerge is one file MergeAgentRuns reconciled without operator input:
+// either only one run touched it, or both runs made the same change.
+type CleanMerge struct {
+	RunA    string `json:"run_a"`
+	RunB    string `json:"run_b"`
+	File    string `json:"file"`
+	Content string `json:"content"`
+}
+
+// TextConflict is one file runA and runB both changed, overlapping, and
+// differently, relative to their merge-base. Content carries the file with
+// git-style conflict markers for the operator to resolve by hand.
+type TextConflict struct {
+	RunA    string `json:"run_a"`
+	RunB    string `json:"run_b"`
+	File    string `json:"file"`
+	Content string `json:"content"`
+}
+
+// StructuralConflict is a conflict MergeAgentRuns can't express as conflict
+// markers inside one file: one run deleted a path the other modified. (No
+// rename detection is attempted — see mergeFile's doc comment — so a rename
+// surfaces as this same modify/delete shape against the path it moved from.)
+type StructuralConflict struct {
+	RunA   string `json:"run_a"`
+	RunB   string `json:"run_b"`
+	File   string `json:"file"`
+	Detail string `json:"detail"`
+}
+
+// MergeResult is the outcome of reconciling every pair of concurrent run
+// branches MergeAgentRuns was given.
+type MergeResult struct {
+	Clean      []CleanMerge         `json:"clean,omitempty"`
+	Conflicts  []TextConflict       `json:"conflicts,omitempty"`
+	Structural []StructuralConflict `json:"structural,omitempty"`
+}
+
+// MergeAgentRuns reconciles every pair of concurrent coder runs in runs, each
+// of whose Branch tip is three-way merged against its merge-base with every
+// other run's tip.
+//
+// For each pair, the merge-base commit is found via go-git's own
+// (*object.Commit).MergeBase, which mirrors `git merge-base`'s BFS-over-the-
+// commit-DAG algorithm: it walks both tips' ancestry marking what each side
+// can reach, and returns the newest commit(s) reachable from both with no
+// reachable-from-both descendant. Every path either tip touched relative to
+// that base is then merged independently by mergeFile: non-overlapping line
+// changes auto-resolve, overlapping changes to the same lines become a
+// TextConflict carrying conflict markers, and a path one run deleted while
+// the other modified becomes a StructuralConflict.
+func MergeAgentRuns(repoDir string, runs []RunRef) (*MergeResult, error) {
+	if len(runs) < 2 {
+		return nil, fmt.Errorf("need at least 2 runs to merge, got %d", len(runs))
+	}
+
+	repo, err := openRepo(repoDir)
+	if err != nil {
+		return nil, fmt.Errorf("opening repo: %w", err)
+	}
+
+	tips := make([]*object.Commit, len(runs))
+	for i, run := range runs {
+		hash, err := repo.ResolveRevision(plumbing.Revision(run.Branch))
+		if err != nil {
+			return nil, fmt.Errorf("resolving %s's branch %q: %w", run.RunID, run.Branch, err)
+		}
+		commit, err := repo.CommitObject(*hash)
+		if err != nil {
+			return nil, fmt.Errorf("loading %s's tip commit: %w", run.RunID, err)
+		}
+		tips[i] = commit
+	}
+
+	result := &MergeResult{}
+	for i := 0; i < len(runs); i++ {
+		for j := i + 1; j < len(runs); j++ {
+			if err := mergePair(runs[i], tips[i], runs[j], tips[j], result); err != nil {
+				return nil, fmt.Errorf("merging %s against %s: %w", runs[i].RunID, runs[j].RunID, err)
+			}
+		}
+	}
+	return result, nil
+}
+
+// mergePair finds commitA and commitB's merge-base and reconciles every path
+// either side touched relative to it, appending the outcome for each path
+// into result.
+func mergePair(runA RunRef, commitA *object.Commit, runB RunRef, commitB *object.Commit, result *MergeResult) error {
+	bases, err := commitA.MergeBase(commitB)
+	if err != nil {
+		return fmt.Errorf("finding merge-base: %w", err)
+	}
+	if len(bases) == 0 {
+		return fmt.Errorf("%s and %s share no common ancestor", runA.RunID, runB.RunID)
+	}
+	base := bases[0]
+
+	baseTree, err := base.Tree()
+	if err != nil {
+		return fmt.Errorf("loading merge-base tree: %w", err)
+	}
+	treeA, err := commitA.Tree()
+	if err != nil {
+		return fmt.Errorf("loading %s's tree: %w", runA.RunID, err)
+	}
+	treeB, err := commitB.Tree()
+	if err != nil {
+		return fmt.Errorf("loading %s's tree: %w", runB.RunID, err)
+	}
+
+	files, err := changedPaths(baseTree, treeA, treeB)
+	if err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		baseContent, inBase, err := fileContent(baseTree, file)
+		if err != nil {
+			return fmt.Errorf("reading %s at merge-base: %w", file, err)
+		}
+		contentA, inA, err := fileContent(treeA, file)
+		if err != nil {
+			return fmt.Errorf("reading %s on %s: %w", file, runA.RunID, err)
+		}
+		contentB, inB, err := fileContent(treeB, file)
+		if err != nil {
+			return fmt.Errorf("reading %s on %s: %w", file, runB.RunID, err)
+		}
+
+		if !inA && !inB {
+			continue // neither side has it any more (or ever had it); nothing to report
+		}
+		if !inA {
+			if inBase && contentB == baseContent {
+				continue // A deleted it, B left it untouched: the deletion wins
+			}
+			if inBase {
+				result.Structural = append(result.Structural, StructuralConflict{
+					RunA: runA.RunID, RunB: runB.RunID, File: file,
+					Detail: fmt.Sprintf("%s deleted %s but %s modified it", runA.RunID, file, runB.RunID),
+				})
+				continue
+			}
+			result.Clean = append(result.Clean, CleanMerge{RunA: runA.RunID, RunB: runB.RunID, File: file, Content: contentB})
+			continue
+		}
+		if !inB {
+			if inBase && contentA == baseContent {
+				continue // B deleted it, A left it untouched: the deletion wins
+			}
+			if inBase {
+				result.Structural = append(result.Structural, StructuralConflict{
+					RunA: runA.RunID, RunB: runB.RunID, File: file,
+					Detail: fmt.Sprintf("%s deleted %s but %s modified it", runB.RunID, file, runA.RunID),
+				})
+				continue
+			}
+			result.Clean = append(result.Clean, CleanMerge{RunA: runA.RunID, RunB: runB.RunID, File: file, Content: contentA})
+			continue
+		}
+
+		mergeFile(runA.RunID, runB.RunID, file, baseContent, contentA, contentB, result)
+	}
+	return nil
+}
+
+// changedPaths returns the sorted union of every path present in base, a, or
+// b, so mergePair considers additions and deletions alongside modifications.
+func changedPaths(base, a, b *object.Tree) ([]string, error) {
+	set := make(map[string]bool)
+	for _, tree := range []*object.Tree{base, a, b} {
+		iter := tree.Files()
+		defer iter.Close()
+		if err := iter.ForEach(func(f *object.File) error {
+			set[f.Name] = true
+			return nil
+		}); err != nil {
+			return nil, fmt.Errorf("listing tree contents: %w", err)
+		}
+	}
+
+	paths := make([]string, 0, len(set))
+	for p := range set {
+		paths = append(paths, p)
+	}
+	sort.Strings(paths)
+	return paths, nil
+}
+
+// fileContent returns path's content in tree, and whether it exists there at
+// all.
+func fileContent(tree *object.Tree, path string) (content string, ok bool, err error) {
+	f, err := tree.File(path)
+	if err == object.ErrFileNotFound {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, err
+	}
+	content, err = f.Contents()
+	if err != nil {
+		return "", false, err
+	}
+	return content, true, nil
+}
+
+// mergeFile three-way-merges one file's content and appends the outcome to
+// result. It diffs base->A and base->B with the same line-oriented myers
+// diff diffpatch.go already uses (godiff.Do via diffChunks), then walks both
+// diffs in lockstep over base's line positions: a base line only one side
+// changed (or both changed identically) auto-resolves; a base line both
+// sides changed differently becomes a conflict hunk.
+//
+// This only detects conflicts at line granularity, not renames: a file moved
+// in one run and edited in the other shows up as mergePair's modify/delete
+// StructuralConflict against the old path, not as a rename conflict.
+func mergeFile(runA, runB, file, base, contentA, contentB string, result *MergeResult) {
+	editsA := lineEdits(base, contentA)
+	editsB := lineEdits(base, contentB)
+
+	n := len(editsA.kept)
+	var merged strings.Builder
+	conflict := false
+
+	for i := 0; i <= n; i++ {
+		insA, insB := editsA.insertions[i], editsB.insertions[i]
+		switch {
+		case insA == insB:
+			merged.WriteString(insA)
+		case insA == "":
+			merged.WriteString(insB)
+		case insB == "":
+			merged.WriteString(insA)
+		default:
+			conflict = true
+			writeConflictHunk(&merged, runA, insA, runB, insB)
+		}
+
+		if i == n {
+			break
+		}
+
+		keptA, keptB := editsA.kept[i], editsB.kept[i]
+		if keptA == keptB {
+			if keptA {
+				merged.WriteString(editsA.lines[i] + "\n")
+			}
+			continue
+		}
+		// Exactly one side deleted this base line: the deletion wins, same
+		// as mergePair's whole-file deletion rule.
+	}
+
+	mergedContent := merged.String()
+	if conflict {
+		result.Conflicts = append(result.Conflicts, TextConflict{RunA: runA, RunB: runB, File: file, Content: mergedContent})
+		return
+	}
+	result.Clean = append(result.Clean, CleanMerge{RunA: runA, RunB: runB, File: file, Content: mergedContent})
+}
+
+// writeConflictHunk emits a git-style conflict block for two differing
+// insertions at the same base position.
+func writeConflictHunk(w *strings.Builder, labelA, textA, labelB, textB string) {
+	fmt.Fprintf(w, "<<<<<<< %s\n", labelA)
+	w.WriteString(textA)
+	fmt.Fprintf(w, "=======\n")
+	w.WriteString(textB)
+	fmt.Fprintf(w, ">>>>>>> %s\n", labelB)
+}
+
+// fileEdits describes one side's change relative to a shared base, indexed
+// by base line position: kept[i] says whether base line i survives, and
+// insertions[i] holds any text that side inserted immediately before base
+// line i (insertions[len(kept)] holds a trailing insertion after the last
+// base line).
+type fileEdits struct {
+	lines      []string
+	kept       []bool
+	insertions map[int]string
+}
+
+// lineEdits diffs base against other with the same line-oriented myers diff
+// diffChunks (diffpatch.go) builds unified patches from, and walks the
+// resulting chunks to classify each base line as kept or deleted and record
+// any inserted text by the base position it was inserted at.
+func lineEdits(base, other string) fileEdits {
+	baseLines := splitLines(base)
+	e := fileEdits{
+		lines:      baseLines,
+		kept:       make([]bool, len(baseLines)),
+		insertions: make(map[int]string),
+	}
+
+	chunks := diffChunks(base, other)
+	baseIdx := 0
+	for _, chunk := range chunks {
+		text := chunk.Content()
+		switch chunk.Type() {
+		case fdiff.Equal:
+			n := countLines(text)
+			for i := baseIdx; i < baseIdx+n && i < len(e.kept); i++ {
+				e.kept[i] = true
+			}
+			baseIdx += n
+		case fdiff.Delete:
+			baseIdx += countLines(text)
+		case fdiff.Add:
+			e.insertions[baseIdx] += text
+		}
+	}
+	return e
+}
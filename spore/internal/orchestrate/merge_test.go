@@ -0,0 +1,127 @@
+package orchestrate
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// branchFromHere creates branch pointing at repoDir's current HEAD, then
+// writes content to path and commits it on that branch, returning to the
+// branch it started on.
+func branchFromHere(t *testing.T, repoDir, branch, path, content string) {
+	t.Helper()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, out)
+		}
+	}
+	run("checkout", "-b", branch)
+	if err := os.WriteFile(filepath.Join(repoDir, path), []byte(content), 0644); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	run("add", "-A")
+	run("commit", "-m", "change on "+branch)
+	run("checkout", "master")
+}
+
+func TestMergeAgentRuns_CleanMerge(t *testing.T) {
+	repoDir := initTestRepo(t)
+	branchFromHere(t, repoDir, "run-a", "a.txt", "only run-a touches this\n")
+	branchFromHere(t, repoDir, "run-b", "b.txt", "only run-b touches this\n")
+
+	result, err := MergeAgentRuns(repoDir, []RunRef{
+		{RunID: "run-a", Branch: "run-a"},
+		{RunID: "run-b", Branch: "run-b"},
+	})
+	if err != nil {
+		t.Fatalf("MergeAgentRuns: %v", err)
+	}
+	if len(result.Conflicts) != 0 || len(result.Structural) != 0 {
+		t.Fatalf("expected no conflicts, got %+v", result)
+	}
+
+	byFile := make(map[string]CleanMerge)
+	for _, c := range result.Clean {
+		byFile[c.File] = c
+	}
+	if byFile["a.txt"].Content != "only run-a touches this\n" {
+		t.Errorf("a.txt: %+v", byFile["a.txt"])
+	}
+	if byFile["b.txt"].Content != "only run-b touches this\n" {
+		t.Errorf("b.txt: %+v", byFile["b.txt"])
+	}
+}
+
+func TestMergeAgentRuns_TextConflict(t *testing.T) {
+	repoDir := initTestRepo(t)
+	branchFromHere(t, repoDir, "run-a", "base.txt", "run-a's version\n")
+	branchFromHere(t, repoDir, "run-b", "base.txt", "run-b's version\n")
+
+	result, err := MergeAgentRuns(repoDir, []RunRef{
+		{RunID: "run-a", Branch: "run-a"},
+		{RunID: "run-b", Branch: "run-b"},
+	})
+	if err != nil {
+		t.Fatalf("MergeAgentRuns: %v", err)
+	}
+	if len(result.Clean) != 0 || len(result.Structural) != 0 {
+		t.Fatalf("expected exactly one text conflict, got %+v", result)
+	}
+	if len(result.Conflicts) != 1 {
+		t.Fatalf("expected 1 conflict, got %d: %+v", len(result.Conflicts), result.Conflicts)
+	}
+
+	c := result.Conflicts[0]
+	if c.File != "base.txt" {
+		t.Errorf("expected conflict on base.txt, got %s", c.File)
+	}
+	if !strings.Contains(c.Content, "<<<<<<< run-a") || !strings.Contains(c.Content, "run-a's version") ||
+		!strings.Contains(c.Content, "=======") || !strings.Contains(c.Content, "run-b's version") ||
+		!strings.Contains(c.Content, ">>>>>>> run-b") {
+		t.Errorf("expected conflict markers around both versions, got:\n%s", c.Content)
+	}
+}
+
+func TestMergeAgentRuns_ModifyDeleteConflict(t *testing.T) {
+	repoDir := initTestRepo(t)
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = repoDir
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v (%s)", args, err, out)
+		}
+	}
+
+	run("checkout", "-b", "run-a")
+	run("rm", "base.txt")
+	run("commit", "-m", "run-a deletes base.txt")
+	run("checkout", "master")
+
+	branchFromHere(t, repoDir, "run-b", "base.txt", "run-b modified base\n")
+
+	result, err := MergeAgentRuns(repoDir, []RunRef{
+		{RunID: "run-a", Branch: "run-a"},
+		{RunID: "run-b", Branch: "run-b"},
+	})
+	if err != nil {
+		t.Fatalf("MergeAgentRuns: %v", err)
+	}
+	if len(result.Clean) != 0 || len(result.Conflicts) != 0 {
+		t.Fatalf("expected exactly one structural conflict, got %+v", result)
+	}
+	if len(result.Structural) != 1 || result.Structural[0].File != "base.txt" {
+		t.Fatalf("expected 1 structural conflict on base.txt, got %+v", result.Structural)
+	}
+}
+
+func TestMergeAgentRuns_RequiresAtLeastTwoRuns(t *testing.T) {
+	repoDir := initTestRepo(t)
+	if _, err := MergeAgentRuns(repoDir, []RunRef{{RunID: "solo", Branch: "master"}}); err == nil {
+		t.Error("expected an error with fewer than 2 runs")
+	}
+}
@@ -0,0 +1,201 @@
+package orchestrate
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"regexp"
+	"strings"
+)
+
+// NodeKind classifies what a code node's Title declares, so renderers can
+// choose section headers/sort order/anchors richer than a single
+// is/isn't-a-function boolean.
+type NodeKind int
+
+const (
+	KindUnknown NodeKind = iota
+	KindFunction
+	KindMethod
+	KindType
+	KindConst
+	KindVar
+	KindModule
+)
+
+func (k NodeKind) String() string {
+	switch k {
+	case KindFunction:
+		return "function"
+	case KindMethod:
+		return "method"
+	case KindType:
+		return "type"
+	case KindConst:
+		return "const"
+	case KindVar:
+		return "var"
+	case KindModule:
+		return "module"
+	default:
+		return "unknown"
+	}
+}
+
+// IsCallable reports whether k is something renderCallGraphWithDB can anchor
+// a caller/callee walk from.
+func (k NodeKind) IsCallable() bool {
+	return k == KindFunction || k == KindMethod
+}
+
+// classifyTitle classifies a code node's Title (a short, single-line
+// signature, not a full source fragment) by language. Go titles are parsed
+// with go/parser for a real AST classification; every other language falls
+// back to classifyTitleHeuristic, since this repo has no tree-sitter (or any
+// other real parser) dependency for them -- adding one would be a much
+// bigger, cgo-shaped change than this request, so the heuristic is widened
+// instead and its limits documented rather than silently accepted.
+func classifyTitle(title, lang string) NodeKind {
+	t := strings.TrimSpace(title)
+	if t == "" {
+		return KindUnknown
+	}
+	if strings.EqualFold(lang, "go") {
+		if k, ok := classifyGoTitle(t); ok {
+			return k
+		}
+	}
+	return classifyTitleHeuristic(t)
+}
+
+// classifyGoTitle attempts to parse t as a Go declaration fragment via
+// go/parser, trying it first as-is (covers complete one-liners like "const
+// MaxRetries = 3") and then with a synthesized "{}" body (covers function
+// signatures and type/struct/interface headers that were truncated at the
+// opening brace when the title was extracted). ok is false when neither
+// attempt parses, so the caller can fall back to the heuristic classifier.
+func classifyGoTitle(t string) (kind NodeKind, ok bool) {
+	attempts := []string{
+		"package p\n" + t,
+		"package p\n" + t + " {}",
+	}
+	for _, src := range attempts {
+		fset := token.NewFileSet()
+		f, err := parser.ParseFile(fset, "", src, 0)
+		if err != nil || len(f.Decls) == 0 {
+			continue
+		}
+		switch d := f.Decls[0].(type) {
+		case *ast.FuncDecl:
+			if d.Recv != nil {
+				return KindMethod, true
+			}
+			return KindFunction, true
+		case *ast.GenDecl:
+			switch d.Tok {
+			case token.TYPE:
+				return KindType, true
+			case token.CONST:
+				return KindConst, true
+			case token.VAR:
+				return KindVar, true
+			case token.IMPORT:
+				return KindModule, true
+			}
+		}
+	}
+	return KindUnknown, false
+}
+
+// methodReceiverPattern matches a Rust/Go-style method receiver inside a
+// signature's parameter list, e.g. "fn handle(&self, req: Request)" or
+// "fn handle(&mut self)".
+var methodReceiverPattern = regexp.MustCompile(`\(\s*(&\s*(mut\s+)?)?self\b`)
+
+// classBareCallPattern matches a bare "name(args)" title with no keyword
+// prefix -- the shape a class method's title takes once its class/def
+// keyword has been stripped off during title extraction.
+var classBareCallPattern = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*\s*\([^)]*\)\s*\{?$`)
+
+// classifyTitleHeuristic is the non-Go fallback: prefix/keyword matching
+// extended past the original isFunctionTitle to also recognize methods,
+// generics, arrow functions, decorated Python defs, and the function/method
+// keywords Kotlin, Swift, Ruby, C#, and PHP/Scala use. It has no notion of
+// scope (a bare "Config(...)" reads as a method the same as a real one), so
+// it is intentionally conservative: only patterns that are function/method
+// syntax in every language that uses them are matched.
+func classifyTitleHeuristic(t string) NodeKind {
+	modifiers := []string{
+		"export default ", "export ",
+		"public ", "private ", "protected ", "internal ",
+		"static ", "override ", "async ", "@",
+		"pub(crate) ", "pub ",
+	}
+	stripped := t
+	for changed := true; changed; {
+		changed = false
+		for _, m := range modifiers {
+			if s := strings.TrimPrefix(stripped, m); s != stripped {
+				stripped = s
+				changed = true
+			}
+		}
+	}
+
+	switch {
+	case methodReceiverPattern.MatchString(t):
+		// Rust/Go "fn foo(&self, ...)" or similar -- a method, not a
+		// free function, regardless of the fn/pub fn/async fn prefix.
+		return KindMethod
+
+	case hasAnyPrefix(stripped, "fn ", "func ", "function ", "def "),
+		hasAnyPrefix(stripped, "fun "), // Kotlin
+		hasAnyPrefix(stripped, "sub "): // Ruby-ish/Perl-ish def forms
+		return KindFunction
+
+	case strings.Contains(t, "=>"):
+		// JS/TS arrow function, e.g. "const handle = (req) => {" or
+		// "export const handle = async (req) => {".
+		return KindFunction
+
+	case hasAnyPrefix(stripped, "class ", "struct ", "interface ", "trait ", "enum ", "impl "):
+		return KindType
+
+	case hasAnyPrefix(stripped, "type "):
+		return KindType
+
+	case hasAnyPrefix(stripped, "const ", "val ", "let "):
+		return KindConst
+
+	case hasAnyPrefix(stripped, "var "):
+		return KindVar
+
+	case hasAnyPrefix(stripped, "module ", "package ", "namespace ", "mod "):
+		return KindModule
+
+	case classBareCallPattern.MatchString(t):
+		// A bare "name(args)" with no keyword prefix: the common shape
+		// for a class method title (JS/TS/Java/C#/PHP/Scala class
+		// bodies, Python's own method defs once "def " is stripped by
+		// title extraction). Ambiguous without real scope information,
+		// but callable syntax is the best signal available here.
+		return KindMethod
+	}
+	return KindUnknown
+}
+
+func hasAnyPrefix(s string, prefixes ...string) bool {
+	for _, p := range prefixes {
+		if strings.HasPrefix(s, p) {
+			return true
+		}
+	}
+	return false
+}
+
+// isFunctionTitle reports whether title looks like a function or method
+// definition, for callers (sort ordering, call-graph anchoring) that only
+// need the callable/non-callable distinction rather than the full NodeKind.
+func isFunctionTitle(title string) bool {
+	return classifyTitle(title, "").IsCallable()
+}
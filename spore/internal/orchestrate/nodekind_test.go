@@ -0,0 +1,66 @@
+package orchestrate
+
+import "testing"
+
+func TestClassifyTitle_Go(t *testing.T) {
+	tests := []struct {
+		title string
+		want  NodeKind
+	}{
+		{"func main", KindFunction},
+		{"func Foo[T any](x T) T", KindFunction},
+		{"func (s *Server) Handle(req *Request) (*Response, error)", KindMethod},
+		{"type Config struct", KindType},
+		{"type Status int", KindType},
+		{"const MaxRetries = 3", KindConst},
+		{"var defaultTimeout = 5 * time.Second", KindVar},
+	}
+	for _, tt := range tests {
+		t.Run(tt.title, func(t *testing.T) {
+			got := classifyTitle(tt.title, "go")
+			if got != tt.want {
+				t.Errorf("classifyTitle(%q, \"go\") = %s, want %s", tt.title, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestClassifyTitle_NonGoHeuristic(t *testing.T) {
+	tests := []struct {
+		lang  string
+		title string
+		want  NodeKind
+	}{
+		{"rust", "fn handle_request", KindFunction},
+		{"rust", "pub fn new", KindFunction},
+		{"rust", "fn handle(&self, req: Request)", KindMethod},
+		{"rust", "fn handle(&mut self)", KindMethod},
+		{"rust", "struct Config", KindType},
+		{"rust", "impl Server", KindType},
+		{"typescript", "export const handle = async (req) => {", KindFunction},
+		{"typescript", "class Server", KindType},
+		{"typescript", "render(props)", KindMethod},
+		{"python", "def parse(self, src)", KindMethod},
+		{"kotlin", "fun compute(x: Int): Int", KindFunction},
+		{"", "enum Status", KindType},
+		{"", "type Foo = Bar", KindType},
+		{"", "", KindUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.lang+"/"+tt.title, func(t *testing.T) {
+			got := classifyTitle(tt.title, tt.lang)
+			if got != tt.want {
+				t.Errorf("classifyTitle(%q, %q) = %s, want %s", tt.title, tt.lang, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNodeKind_IsCallable(t *testing.T) {
+	if !KindFunction.IsCallable() || !KindMethod.IsCallable() {
+		t.Error("expected KindFunction and KindMethod to be callable")
+	}
+	if KindType.IsCallable() || KindConst.IsCallable() || KindVar.IsCallable() || KindModule.IsCallable() || KindUnknown.IsCallable() {
+		t.Error("expected only KindFunction/KindMethod to be callable")
+	}
+}
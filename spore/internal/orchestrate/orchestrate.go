@@ -1,6 +1,8 @@
 package orchestrate
 
 import (
+	"context"
+	"errors"
 	"fmt"
 	"os"
 	"os/exec"
@@ -10,18 +12,75 @@ import (
 
 	"github.com/google/uuid"
 	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/orchestrate/assert"
 )
 
 // RunOrchestration runs the full coder -> verifier -> summarizer pipeline.
 // It creates a task node, then bounces between coder and verifier until
-// verification passes or max bounces is exhausted.
-func RunOrchestration(d *db.DB, task string, config OrchestrationConfig) (*OrchestrationResult, error) {
+// verification passes or max bounces is exhausted. ctx governs the whole
+// run: cancelling it (or its deadline passing) terminates whichever phase
+// is currently in flight and the run ends with StatusCancelled/StatusTimeout.
+func RunOrchestration(ctx context.Context, d *db.DB, task string, config OrchestrationConfig) (*OrchestrationResult, error) {
+	// Pull any trailing `assertions:` block out of the task text so it isn't
+	// shown to the coder/verifier agents as part of the task prompt.
+	cleanTask, taskAssertions, err := ExtractTaskAssertions(task)
+	if err != nil {
+		return nil, fmt.Errorf("parsing task assertions: %w", err)
+	}
+	task = cleanTask
+	if len(config.Assertions) == 0 {
+		config.Assertions = taskAssertions
+	}
+
 	// Fail fast: check claude binary
 	if _, err := exec.LookPath("claude"); err != nil {
 		return nil, fmt.Errorf("claude binary not found in PATH: %w", err)
 	}
 
-	runID := uuid.New().String()
+	// Resuming a prior run restores its run/task node IDs and progress from
+	// its last checkpoint instead of starting cold (see OrchestrationConfig.
+	// ResumeRunID and Checkpoint).
+	var checkpoint *Checkpoint
+	if config.ResumeRunID != "" {
+		checkpoint, err = loadCheckpoint(config.OutputDir, config.ResumeRunID)
+		if err != nil {
+			return nil, fmt.Errorf("loading checkpoint for %s: %w", config.ResumeRunID, err)
+		}
+	}
+
+	var runID, taskNodeID string
+	var startBounce int
+	var lastImplID string
+	var lastVerdict Verdict
+	var resumeSessionID string
+	var priorPhases []PhaseResult
+	var priorCost float64
+	var bounceRefs []string // snapshot refs taken under RollbackBetweenBounces (see rollback.go)
+
+	if checkpoint != nil {
+		runID = checkpoint.RunID
+		taskNodeID = checkpoint.TaskNodeID
+		startBounce = checkpoint.Bounce
+		lastImplID = checkpoint.ImplNodeID
+		lastVerdict = checkpoint.LastVerdict
+		resumeSessionID = checkpoint.SessionID
+		priorPhases = checkpoint.Phases
+		priorCost = checkpoint.TotalCost
+		if !config.Quiet {
+			fmt.Fprintf(os.Stderr, "[orchestrate] Resuming run %s from bounce %d (phase %s)\n",
+				checkpoint.RunID, checkpoint.Bounce+1, checkpoint.Phase)
+		}
+	} else {
+		runID = uuid.New().String()
+	}
+
+	// A single LessonStore instance is reused across every phase/bounce of
+	// this run so its StoreStats accumulate per-run, not per-phase.
+	store := config.LessonStore
+	if store == nil {
+		store = NewSQLiteLessonStore(d)
+	}
+	config.LessonStore = store
 
 	cliBinary, err := db.FindCLIBinary()
 	if err != nil {
@@ -30,23 +89,45 @@ func RunOrchestration(d *db.DB, task string, config OrchestrationConfig) (*Orche
 
 	workDir := findProjectRoot(filepath.Dir(d.Path))
 
-	// Create task node
-	taskNodeID, err := d.CreateNode(truncateTitle(task, 100), db.CreateNodeOpts{
-		AgentID:   "spore:orchestrator",
-		NodeClass: "operational",
-		MetaType:  "task",
-		Source:    "spore-go",
-		Content:   task,
-	})
-	if err != nil {
-		return nil, fmt.Errorf("creating task node: %w", err)
+	// RollbackOnEscalation/RollbackBetweenBounces need the HEAD this run
+	// started from so they have somewhere to revert to; save it (and the
+	// workDir it applies to) right away so `orchestrate rollback` still
+	// works even if the run is later killed before finishing.
+	var preRunHEAD string
+	if config.Rollback != RollbackNever {
+		preRunHEAD, err = gitOutput(workDir, "rev-parse", "HEAD")
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to capture pre-run HEAD, rollback disabled for this run: %v\n", err)
+		} else {
+			saveRollbackRecord(config.OutputDir, RollbackRecord{RunID: runID, WorkDir: workDir, PreRunHEAD: preRunHEAD})
+		}
+	}
+
+	if taskNodeID == "" {
+		// Create task node
+		taskNodeID, err = d.CreateNode(truncateTitle(task, 100), db.CreateNodeOpts{
+			AgentID:   "spore:orchestrator",
+			NodeClass: "operational",
+			MetaType:  "task",
+			Source:    "spore-go",
+			Content:   task,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("creating task node: %w", err)
+		}
 	}
 
 	result := &OrchestrationResult{
 		TaskNodeID: taskNodeID,
 		RunID:      runID,
 		Status:     StatusFailed,
+		Phases:     priorPhases,
+		TotalCost:  priorCost,
 	}
+	defer func() {
+		stats := store.Stats()
+		result.StoreStats = &stats
+	}()
 
 	// DryRun: generate task file and return
 	if config.DryRun {
@@ -54,7 +135,7 @@ func RunOrchestration(d *db.DB, task string, config OrchestrationConfig) (*Orche
 			d, task, RoleCoder, runID, taskNodeID,
 			0, config.MaxBounces,
 			"", VerdictUnknown,
-			config.TaskFile, config.OutputDir,
+			config.TaskFile, config.OutputDir, store, nil,
 		)
 		if err != nil {
 			return result, fmt.Errorf("generating task file: %w", err)
@@ -71,57 +152,84 @@ func RunOrchestration(d *db.DB, task string, config OrchestrationConfig) (*Orche
 		maxBounces = 3
 	}
 
-	var lastImplID string
-	var lastVerdict Verdict
+	for bounce := startBounce; bounce < maxBounces; bounce++ {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			status := statusForErr(ctxErr)
+			recordRunStatus(d, taskNodeID, runID, "orchestrator", string(status), nil, config.Experiment, nil)
+			result.Status = status
+			return result, ctxErr
+		}
 
-	for bounce := 0; bounce < maxBounces; bounce++ {
 		result.Bounces = bounce + 1
 
 		if !config.Quiet {
 			fmt.Fprintf(os.Stderr, "\n[orchestrate] Bounce %d/%d\n", bounce+1, maxBounces)
 		}
 
-		// --- Coder ---
-		coderResult, err := runCoder(d, task, runID, taskNodeID, bounce, maxBounces,
-			lastImplID, lastVerdict, cliBinary, workDir, config)
-		if err != nil {
-			recordRunStatus(d, taskNodeID, runID, "coder", "failed", coderResult.Claude, config.Experiment)
+		var coderResult, verifierResult *PhaseResult
+		if config.Candidates > 1 {
+			// --- Coder + Verifier, best-of-N across isolated worktrees ---
+			coderResult, verifierResult, err = runBounceCandidates(ctx, d, task, runID, taskNodeID, bounce, maxBounces,
+				lastImplID, lastVerdict, cliBinary, workDir, config, result)
+			if err != nil {
+				status := statusForErr(err)
+				recordRunStatus(d, taskNodeID, runID, "coder", string(status), coderResult.Claude, config.Experiment, coderResult.Policy)
+				result.Status = status
+				return result, fmt.Errorf("candidate search failed on bounce %d: %w", bounce+1, err)
+			}
+		} else {
+			// --- Coder --- (resumeSessionID only applies to the first call
+			// after a resume; it's cleared immediately below)
+			var rollbackRef string
+			if config.Rollback == RollbackBetweenBounces {
+				rollbackRef = BounceSnapshotRef(runID, bounce)
+			}
+			coderResult, err = runCoder(ctx, d, task, runID, taskNodeID, bounce, maxBounces,
+				lastImplID, lastVerdict, cliBinary, workDir, config, resumeSessionID, rollbackRef)
+			resumeSessionID = ""
+			if err != nil {
+				status := statusForErr(err)
+				recordRunStatus(d, taskNodeID, runID, "coder", string(status), coderResult.Claude, config.Experiment, coderResult.Policy)
+				result.Phases = append(result.Phases, *coderResult)
+				result.TotalCost += coderResult.Claude.CostUSD
+				result.Status = status
+				return result, fmt.Errorf("coder failed on bounce %d: %w", bounce+1, err)
+			}
 			result.Phases = append(result.Phases, *coderResult)
 			result.TotalCost += coderResult.Claude.CostUSD
-			return result, fmt.Errorf("coder failed on bounce %d: %w", bounce+1, err)
-		}
-		result.Phases = append(result.Phases, *coderResult)
-		result.TotalCost += coderResult.Claude.CostUSD
 
-		if !config.Quiet {
-			fmt.Fprintf(os.Stderr, "  Coder: %s %d turns, %s, $%.4f\n",
-				selectCoderModel(config),
-				coderResult.Claude.NumTurns,
-				FormatDurationShort(coderResult.Claude.Duration.Milliseconds()),
-				coderResult.Claude.CostUSD)
-			if len(coderResult.ChangedFiles) > 0 {
-				fmt.Fprintf(os.Stderr, "  Changed: %s\n", strings.Join(coderResult.ChangedFiles, ", "))
+			if !config.Quiet {
+				fmt.Fprintf(os.Stderr, "  Coder: %s %d turns, %s, $%.4f\n",
+					selectCoderModel(config),
+					coderResult.Claude.NumTurns,
+					FormatDurationShort(coderResult.Claude.Duration.Milliseconds()),
+					coderResult.Claude.CostUSD)
+				if len(coderResult.ChangedFiles) > 0 {
+					fmt.Fprintf(os.Stderr, "  Changed: %s\n", strings.Join(coderResult.ChangedFiles, ", "))
+				}
 			}
-		}
-
-		// Post-coder cleanup: re-index changed files
-		if len(coderResult.ChangedFiles) > 0 {
-			postCoderCleanup(d, cliBinary, workDir, coderResult.ChangedFiles)
-		}
 
-		lastImplID = coderResult.ImplNodeID
+			// Post-coder cleanup: re-index changed files
+			if len(coderResult.ChangedFiles) > 0 {
+				postCoderCleanup(ctx, d, cliBinary, workDir, coderResult.ChangedFiles)
+			}
 
-		// --- Verifier ---
-		verifierResult, err := runVerifier(d, task, runID, taskNodeID, coderResult.ImplNodeID,
-			bounce, cliBinary, workDir, config)
-		if err != nil {
-			recordRunStatus(d, taskNodeID, runID, "verifier", "failed", verifierResult.Claude, config.Experiment)
+			// --- Verifier ---
+			verifierResult, err = runVerifier(ctx, d, task, runID, taskNodeID, coderResult.ImplNodeID,
+				bounce, cliBinary, workDir, config)
+			if err != nil {
+				status := statusForErr(err)
+				recordRunStatus(d, taskNodeID, runID, "verifier", string(status), verifierResult.Claude, config.Experiment, verifierResult.Policy)
+				result.Phases = append(result.Phases, *verifierResult)
+				result.TotalCost += verifierResult.Claude.CostUSD
+				result.Status = status
+				return result, fmt.Errorf("verifier failed on bounce %d: %w", bounce+1, err)
+			}
 			result.Phases = append(result.Phases, *verifierResult)
 			result.TotalCost += verifierResult.Claude.CostUSD
-			return result, fmt.Errorf("verifier failed on bounce %d: %w", bounce+1, err)
 		}
-		result.Phases = append(result.Phases, *verifierResult)
-		result.TotalCost += verifierResult.Claude.CostUSD
+
+		lastImplID = coderResult.ImplNodeID
 
 		verdict := verifierResult.Verdict
 		if verdict == nil {
@@ -136,10 +244,50 @@ func RunOrchestration(d *db.DB, task string, config OrchestrationConfig) (*Orche
 
 		lastVerdict = verdict.Verdict
 
+		// Checkpoint after each bounce's coder+verifier cycle completes, so a
+		// crash or Ctrl-C before the next bounce can resume via
+		// config.ResumeRunID instead of paying for a cold restart. Resuming
+		// re-enters at this bounce's coder with SessionID, rather than
+		// skipping straight to the verifier -- the pipeline's per-bounce loop
+		// has no cheaper re-entry point than that.
+		saveCheckpoint(config.OutputDir, Checkpoint{
+			RunID:       runID,
+			TaskNodeID:  taskNodeID,
+			Bounce:      bounce + 1,
+			Phase:       RoleVerifier,
+			SessionID:   coderResult.Claude.SessionID,
+			ImplNodeID:  coderResult.ImplNodeID,
+			LastVerdict: lastVerdict,
+			Phases:      result.Phases,
+			TotalCost:   result.TotalCost,
+		})
+
+		if verdict.Verdict == VerdictConflicted {
+			if !config.Quiet {
+				fmt.Fprintf(os.Stderr, "  Verdict conflicted across agents; running tie-breaker re-verification\n")
+			}
+			evidence := CollectVerdictEvidence(d, coderResult.ImplNodeID)
+			tieBreak, tbErr := runVerifier(ctx, d, task, runID, taskNodeID, coderResult.ImplNodeID,
+				bounce, cliBinary, workDir, config)
+			if tbErr == nil && tieBreak.Verdict != nil &&
+				tieBreak.Verdict.Verdict != VerdictConflicted && tieBreak.Verdict.Verdict != VerdictUnknown {
+				result.Phases = append(result.Phases, *tieBreak)
+				result.TotalCost += tieBreak.Claude.CostUSD
+				reason := "tie-breaker re-verification: " + tieBreak.Verdict.Reason
+				if _, resolveErr := ResolveVerdictConflict(d, coderResult.ImplNodeID, taskNodeID, evidence, tieBreak.Verdict.Verdict, reason); resolveErr != nil {
+					fmt.Fprintf(os.Stderr, "[orchestrate] recording conflict resolution failed: %v\n", resolveErr)
+				}
+				verdict = tieBreak.Verdict
+				result.Verdict = verdict.Verdict
+				lastVerdict = verdict.Verdict
+			}
+		}
+
 		if verdict.Verdict == VerdictSupports {
 			// Success -- run summarizer if enabled
+			summaryText := verifierResult.Claude.Thinking
 			if !config.NoSummarize {
-				sumResult, err := runSummarizer(d, task, runID, taskNodeID, coderResult.ImplNodeID,
+				sumResult, err := runSummarizer(ctx, d, task, runID, taskNodeID, coderResult.ImplNodeID,
 					cliBinary, workDir, config)
 				if err != nil {
 					// Summarizer failure is non-fatal
@@ -147,29 +295,98 @@ func RunOrchestration(d *db.DB, task string, config OrchestrationConfig) (*Orche
 				} else {
 					result.Phases = append(result.Phases, *sumResult)
 					result.TotalCost += sumResult.Claude.CostUSD
+					summaryText = sumResult.Claude.Thinking
 				}
 			}
 
-			recordRunStatus(d, taskNodeID, runID, "orchestrator", "success", nil, config.Experiment)
+			if len(config.Assertions) > 0 {
+				applied := assert.Apply(config.Assertions, &assert.RunContext{
+					Stdout:     verifierResult.Claude.Thinking,
+					Stderr:     verifierResult.Claude.Stderr,
+					ExitCode:   verifierResult.Claude.ExitCode,
+					Summary:    summaryText,
+					DB:         d,
+					ImplNodeID: coderResult.ImplNodeID,
+				})
+				result.Assertions = applied
+				if assert.AnyFailed(applied) {
+					if !config.Quiet {
+						fmt.Fprintf(os.Stderr, "  Assertions: %s\n", assert.FailureReason(applied))
+					}
+					verdict = &VerifierVerdict{
+						Verdict:    VerdictContradicts,
+						Reason:     assert.FailureReason(applied),
+						Confidence: 1.0,
+					}
+					result.Verdict = verdict.Verdict
+					lastVerdict = verdict.Verdict
+					continue
+				}
+			}
+
+			recordRunStatus(d, taskNodeID, runID, "orchestrator", "success", nil, config.Experiment, nil)
 			result.Status = StatusSuccess
+			removeCheckpoint(config.OutputDir, runID)
 			return result, nil
 		}
 
-		// Verdict was contradicts or unknown -- continue to next bounce
+		// Verdict was contradicts or unknown. Consult the enforcement policy
+		// before continuing to the next bounce: "deny" stops the task early
+		// instead of burning the remaining bounce budget, "warn" logs and
+		// falls through to the default bounce behavior.
+		scope := enforcementScope{Experiment: config.Experiment, Role: RoleVerifier}
+		verifierAction := config.Enforcement.Resolve("verifier", "contradicts", scope, ActionBounce)
+		if verifierAction == ActionDeny {
+			recordRunStatus(d, taskNodeID, runID, "orchestrator", "failed", nil, config.Experiment, nil)
+			result.Status = StatusFailed
+			removeCheckpoint(config.OutputDir, runID)
+			return result, fmt.Errorf("task denied by enforcement policy on bounce %d: %s", bounce+1, verdict.Reason)
+		}
+		if verifierAction == ActionWarn && !config.Quiet {
+			fmt.Fprintf(os.Stderr, "  [enforcement] warn: verifier contradicts on bounce %d, continuing\n", bounce+1)
+		}
+
+		// RollbackBetweenBounces: snapshot this bounce's changes under a ref
+		// (so they're still reachable via `git checkout`) and reset workDir
+		// back to its pre-run HEAD, so the next bounce's coder starts from
+		// the verifier's feedback instead of this bounce's broken code.
+		if config.Rollback == RollbackBetweenBounces && preRunHEAD != "" {
+			ref := BounceSnapshotRef(runID, bounce)
+			if _, snapErr := SnapshotWorkingTree(workDir, ref); snapErr != nil {
+				fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to snapshot bounce %d: %v\n", bounce+1, snapErr)
+			} else {
+				bounceRefs = append(bounceRefs, ref)
+				saveRollbackRecord(config.OutputDir, RollbackRecord{RunID: runID, WorkDir: workDir, PreRunHEAD: preRunHEAD, BounceRefs: bounceRefs})
+				if resetErr := ResetWorkingTree(workDir, preRunHEAD); resetErr != nil {
+					fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to reset workDir before next bounce: %v\n", resetErr)
+				}
+			}
+		}
 	}
 
 	// Max bounces exhausted
 	createEscalation(d, taskNodeID, lastImplID, maxBounces, task)
-	recordRunStatus(d, taskNodeID, runID, "orchestrator", "failed", nil, config.Experiment)
+	recordRunStatus(d, taskNodeID, runID, "orchestrator", "failed", nil, config.Experiment, nil)
+	removeCheckpoint(config.OutputDir, runID)
+	if (config.Rollback == RollbackOnEscalation || config.Rollback == RollbackBetweenBounces) && preRunHEAD != "" {
+		if err := StashAndResetToHEAD(workDir, preRunHEAD); err != nil {
+			fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to roll back after escalation: %v\n", err)
+		} else if !config.Quiet {
+			fmt.Fprintf(os.Stderr, "[orchestrate] Rolled back to pre-run HEAD %s after escalation\n", preRunHEAD)
+		}
+	}
 	return result, fmt.Errorf("max bounces (%d) exhausted without verification", maxBounces)
 }
 
 func runCoder(
+	ctx context.Context,
 	d *db.DB, task, runID, taskNodeID string,
 	bounce, maxBounces int,
 	lastImplID string, lastVerdict Verdict,
 	cliBinary, workDir string,
 	config OrchestrationConfig,
+	resumeSessionID string,
+	rollbackRef string,
 ) (*PhaseResult, error) {
 	// Capture git state before
 	gitBefore, err := CaptureGitState(workDir)
@@ -182,7 +399,7 @@ func runCoder(
 		d, task, RoleCoder, runID, taskNodeID,
 		bounce, maxBounces,
 		lastImplID, lastVerdict,
-		config.TaskFile, config.OutputDir,
+		config.TaskFile, config.OutputDir, config.LessonStore, nil,
 	)
 	if err != nil {
 		return &PhaseResult{Role: RoleCoder, Claude: &ClaudeResult{}},
@@ -208,7 +425,7 @@ func runCoder(
 			fmt.Errorf("writing MCP config: %w", err)
 	}
 
-	allowed, disallowed := toolPermissions(RoleCoder)
+	allowed, disallowed, toolPolicy := resolveToolPermissions(RoleCoder, config.Policies)
 
 	claudeCfg := ClaudeConfig{
 		Role:          RoleCoder,
@@ -219,14 +436,20 @@ func runCoder(
 		DisallowTools: disallowed,
 		MCPConfig:     mcpConfigPath,
 		AgentName:     agentName,
+		ResumeID:      resumeSessionID,
 		WorkDir:       workDir,
 		Verbose:       config.Verbose,
 	}
 
 	// Spawn Claude
-	claudeResult, err := SpawnClaude(claudeCfg)
+	phaseCtx, cancel := phaseContext(ctx, config.CoderTimeout)
+	defer cancel()
+	claudeResult, err := spawnSupervisedClaude(phaseCtx, claudeCfg, config.Supervisor)
+	if claudeResult == nil {
+		claudeResult = &ClaudeResult{}
+	}
 	if err != nil {
-		return &PhaseResult{Role: RoleCoder, Claude: &ClaudeResult{}},
+		return &PhaseResult{Role: RoleCoder, Claude: claudeResult, Policy: toolPolicy},
 			fmt.Errorf("spawning coder: %w", err)
 	}
 
@@ -241,7 +464,7 @@ func runCoder(
 
 	// Check for hard failure: non-zero exit and no changes
 	if claudeResult.ExitCode != 0 && len(changedFiles) == 0 {
-		return &PhaseResult{Role: RoleCoder, Claude: claudeResult, ChangedFiles: changedFiles},
+		return &PhaseResult{Role: RoleCoder, Claude: claudeResult, ChangedFiles: changedFiles, Policy: toolPolicy},
 			fmt.Errorf("coder exited with code %d and no files changed", claudeResult.ExitCode)
 	}
 
@@ -254,15 +477,24 @@ func runCoder(
 		Content:   fmt.Sprintf("Changed files: %s", strings.Join(changedFiles, ", ")),
 	})
 	if err != nil {
-		return &PhaseResult{Role: RoleCoder, Claude: claudeResult, ChangedFiles: changedFiles},
+		return &PhaseResult{Role: RoleCoder, Claude: claudeResult, ChangedFiles: changedFiles, Policy: toolPolicy},
 			fmt.Errorf("creating implementation node: %w", err)
 	}
 
-	// Create DerivesFrom edge: impl -> task
-	_, err = d.CreateEdge(implNodeID, taskNodeID, "derives_from", db.CreateEdgeOpts{
+	// Create DerivesFrom edge: impl -> task. When RollbackBetweenBounces is
+	// active, rollbackRef names the ref this bounce's changes will be
+	// snapshotted under (see BounceSnapshotRef/SnapshotWorkingTree), so it's
+	// recorded on the edge for anyone inspecting the graph later -- there's
+	// no node-level metadata field to attach it to directly (see
+	// db.CreateNodeOpts).
+	edgeOpts := db.CreateEdgeOpts{
 		Agent:  "spore:orchestrator",
 		Reason: fmt.Sprintf("coder output bounce %d", bounce+1),
-	})
+	}
+	if rollbackRef != "" {
+		edgeOpts.Metadata = fmt.Sprintf(`{"rollback_ref":%q}`, rollbackRef)
+	}
+	_, err = d.CreateEdge(implNodeID, taskNodeID, "derives_from", edgeOpts)
 	if err != nil {
 		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to create derives_from edge: %v\n", err)
 	}
@@ -272,10 +504,12 @@ func runCoder(
 		Claude:       claudeResult,
 		ImplNodeID:   implNodeID,
 		ChangedFiles: changedFiles,
+		Policy:       toolPolicy,
 	}, nil
 }
 
 func runVerifier(
+	ctx context.Context,
 	d *db.DB, task, runID, taskNodeID, implNodeID string,
 	bounce int,
 	cliBinary, workDir string,
@@ -286,7 +520,7 @@ func runVerifier(
 		d, task, RoleVerifier, runID, taskNodeID,
 		0, 1,
 		implNodeID, VerdictUnknown,
-		config.TaskFile, config.OutputDir,
+		config.TaskFile, config.OutputDir, config.LessonStore, nil,
 	)
 	if err != nil {
 		return &PhaseResult{Role: RoleVerifier, Claude: &ClaudeResult{}},
@@ -308,7 +542,7 @@ func runVerifier(
 			fmt.Errorf("writing verifier MCP config: %w", err)
 	}
 
-	allowed, disallowed := toolPermissions(RoleVerifier)
+	allowed, disallowed, toolPolicy := resolveToolPermissions(RoleVerifier, config.Policies)
 
 	claudeCfg := ClaudeConfig{
 		Role:          RoleVerifier,
@@ -323,27 +557,58 @@ func runVerifier(
 		Verbose:       config.Verbose,
 	}
 
-	claudeResult, err := SpawnClaude(claudeCfg)
+	phaseCtx, cancel := phaseContext(ctx, config.VerifierTimeout)
+	defer cancel()
+	claudeResult, err := spawnSupervisedClaude(phaseCtx, claudeCfg, config.Supervisor)
+	if claudeResult == nil {
+		claudeResult = &ClaudeResult{}
+	}
 	if err != nil {
-		return &PhaseResult{Role: RoleVerifier, Claude: &ClaudeResult{}},
+		return &PhaseResult{Role: RoleVerifier, Claude: claudeResult, Policy: toolPolicy},
 			fmt.Errorf("spawning verifier: %w", err)
 	}
 
-	// Determine verdict: check thinking first, then stderr as fallback
+	// Determine verdict: prefer the verifier's structured submit_verdict tool
+	// call, then a fenced JSON block in its last message, falling back to
+	// thinking/stderr-scraping only if neither is present (see
+	// DetermineVerdictWithToolCall).
 	verifierOutput := claudeResult.Thinking
 	if verifierOutput == "" {
 		verifierOutput = claudeResult.Stderr
 	}
-	verdict := DetermineVerdict(d, implNodeID, verifierOutput)
+	verdict, structured := DetermineVerdictWithToolCall(claudeResult.ToolCalls, claudeResult.LastMessage,
+		config.VerdictRules, config.VerdictMatchers, d, implNodeID, verifierOutput,
+		claudeResult.CostUSD, bounce, claudeResult.Duration)
 
-	return &PhaseResult{
+	phase := &PhaseResult{
 		Role:    RoleVerifier,
 		Claude:  claudeResult,
 		Verdict: verdict,
-	}, nil
+		Policy:  toolPolicy,
+	}
+
+	// Record cited evidence as "cites" edges so operators can audit why the
+	// verdict was rendered, not just what it was.
+	if structured != nil {
+		for _, ev := range structured.Evidence {
+			if ev.NodeID == "" {
+				continue
+			}
+			phase.Evidence = append(phase.Evidence, ev.NodeID)
+			if _, err := d.CreateEdge(implNodeID, ev.NodeID, "cites", db.CreateEdgeOpts{
+				Reason: ev.Quote,
+				Agent:  "spore:verifier",
+			}); err != nil {
+				fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to record cites edge %s -> %s: %v\n", implNodeID, ev.NodeID, err)
+			}
+		}
+	}
+
+	return phase, nil
 }
 
 func runSummarizer(
+	ctx context.Context,
 	d *db.DB, task, runID, taskNodeID, implNodeID string,
 	cliBinary, workDir string,
 	config OrchestrationConfig,
@@ -352,7 +617,7 @@ func runSummarizer(
 		d, task, RoleSummarizer, runID, taskNodeID,
 		0, 1,
 		implNodeID, VerdictSupports,
-		config.TaskFile, config.OutputDir,
+		config.TaskFile, config.OutputDir, config.LessonStore, nil,
 	)
 	if err != nil {
 		return &PhaseResult{Role: RoleSummarizer, Claude: &ClaudeResult{}},
@@ -374,7 +639,7 @@ func runSummarizer(
 			fmt.Errorf("writing summarizer MCP config: %w", err)
 	}
 
-	allowed, disallowed := toolPermissions(RoleSummarizer)
+	allowed, disallowed, toolPolicy := resolveToolPermissions(RoleSummarizer, config.Policies)
 
 	claudeCfg := ClaudeConfig{
 		Role:          RoleSummarizer,
@@ -389,9 +654,14 @@ func runSummarizer(
 		Verbose:       config.Verbose,
 	}
 
-	claudeResult, err := SpawnClaude(claudeCfg)
+	phaseCtx, cancel := phaseContext(ctx, config.SummarizerTimeout)
+	defer cancel()
+	claudeResult, err := SpawnClaude(phaseCtx, claudeCfg)
+	if claudeResult == nil {
+		claudeResult = &ClaudeResult{}
+	}
 	if err != nil {
-		return &PhaseResult{Role: RoleSummarizer, Claude: &ClaudeResult{}},
+		return &PhaseResult{Role: RoleSummarizer, Claude: claudeResult, Policy: toolPolicy},
 			fmt.Errorf("spawning summarizer: %w", err)
 	}
 
@@ -418,11 +688,12 @@ func runSummarizer(
 	return &PhaseResult{
 		Role:   RoleSummarizer,
 		Claude: claudeResult,
+		Policy: toolPolicy,
 	}, nil
 }
 
 // postCoderCleanup re-indexes changed files via the CLI.
-func postCoderCleanup(d *db.DB, cliBinary, workDir string, changedFiles []string) {
+func postCoderCleanup(ctx context.Context, d *db.DB, cliBinary, workDir string, changedFiles []string) {
 	for _, file := range changedFiles {
 		absPath := file
 		if !filepath.IsAbs(file) {
@@ -432,7 +703,7 @@ func postCoderCleanup(d *db.DB, cliBinary, workDir string, changedFiles []string
 		if _, err := os.Stat(absPath); os.IsNotExist(err) {
 			continue
 		}
-		cmd := exec.Command(cliBinary, "import", "code", absPath, "--update", "--db", d.Path)
+		cmd := exec.CommandContext(ctx, cliBinary, "import", "code", absPath, "--update", "--db", d.Path)
 		cmd.Dir = workDir
 		if out, err := cmd.CombinedOutput(); err != nil {
 			fmt.Fprintf(os.Stderr, "[orchestrate] Warning: re-index %s failed: %v (%s)\n",
@@ -441,9 +712,35 @@ func postCoderCleanup(d *db.DB, cliBinary, workDir string, changedFiles []string
 	}
 }
 
+// phaseContext derives a per-phase deadline from ctx when timeout > 0;
+// otherwise ctx is returned unchanged with a no-op cancel. Always call the
+// returned cancel to release resources (see context.WithTimeout).
+func phaseContext(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return ctx, func() {}
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// statusForErr maps a phase error to a RunStatus, distinguishing a ctx
+// deadline/cancellation from an ordinary failure so callers can record why
+// a run stopped rather than a generic "failed".
+func statusForErr(err error) RunStatus {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		return StatusTimeout
+	case errors.Is(err, context.Canceled):
+		return StatusCancelled
+	default:
+		return StatusFailed
+	}
+}
+
 // recordRunStatus creates a self-referential Tracks edge on the task node with
-// run metadata. Non-fatal: logs warnings on failure.
-func recordRunStatus(d *db.DB, taskNodeID, runID, agent, status string, result *ClaudeResult, experiment string) {
+// run metadata, including which effective tool policy (see
+// resolveToolPermissions) the phase ran under when policy is non-nil.
+// Non-fatal: logs warnings on failure.
+func recordRunStatus(d *db.DB, taskNodeID, runID, agent, status string, result *ClaudeResult, experiment string, policy *ResolvedToolPolicy) {
 	var exitCode int
 	var costUSD float64
 	var numTurns int
@@ -458,9 +755,16 @@ func recordRunStatus(d *db.DB, taskNodeID, runID, agent, status string, result *
 	}
 
 	metadata := fmt.Sprintf(
-		`{"run_id":"%s","status":"%s","agent":"%s","exit_code":%d,"cost_usd":%.4f,"num_turns":%d,"duration_ms":%d,"experiment":"%s","model":"%s"}`,
+		`{"run_id":"%s","status":"%s","agent":"%s","exit_code":%d,"cost_usd":%.4f,"num_turns":%d,"duration_ms":%d,"experiment":"%s","model":"%s"`,
 		runID, status, agent, exitCode, costUSD, numTurns, durationMS, experiment, model,
 	)
+	if policy != nil {
+		metadata += fmt.Sprintf(
+			`,"policy_role":"%s","policy_allowed":"%s","policy_disallowed":"%s","policy_hash":"%s"`,
+			policy.Role, policy.Allowed, policy.Disallowed, policy.PolicyHash,
+		)
+	}
+	metadata += "}"
 
 	shortRunID := runID
 	if len(shortRunID) > 8 {
@@ -477,6 +781,32 @@ func recordRunStatus(d *db.DB, taskNodeID, runID, agent, status string, result *
 	}
 }
 
+// recordAgentState creates a lightweight self-referential Tracks edge
+// recording one lifecycle transition (see AgentStateReporter), so dashboards
+// can watch a run move through Pending/Received/Running before its terminal
+// Completed/Failed. Unlike recordRunStatus's single terminal-status edge, a
+// run may have several of these. Non-fatal: logs warnings on failure.
+func recordAgentState(d *db.DB, taskNodeID, runID, agent string, state AgentState) {
+	if d == nil || taskNodeID == "" {
+		return
+	}
+
+	shortRunID := runID
+	if len(shortRunID) > 8 {
+		shortRunID = shortRunID[:8]
+	}
+
+	metadata := fmt.Sprintf(`{"run_id":"%s","agent":"%s","state":"%s"}`, runID, agent, state)
+	_, err := d.CreateEdge(taskNodeID, taskNodeID, "tracks", db.CreateEdgeOpts{
+		Agent:    "spore:orchestrator",
+		Metadata: metadata,
+		Reason:   fmt.Sprintf("run %s state: %s", shortRunID, state),
+	})
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to record agent state: %v\n", err)
+	}
+}
+
 // createEscalation creates an escalation node and links it to the task node.
 func createEscalation(d *db.DB, taskNodeID, lastImplID string, bounceCount int, task string) {
 	content := fmt.Sprintf("Task exceeded %d bounces without verification.", bounceCount)
@@ -551,8 +881,8 @@ func selectCoderModel(config OrchestrationConfig) string {
 }
 
 // RecordRunStatus is the exported wrapper for tests.
-func RecordRunStatus(d *db.DB, taskNodeID, runID, agent, status string, result *ClaudeResult, experiment string) {
-	recordRunStatus(d, taskNodeID, runID, agent, status, result, experiment)
+func RecordRunStatus(d *db.DB, taskNodeID, runID, agent, status string, result *ClaudeResult, experiment string, policy *ResolvedToolPolicy) {
+	recordRunStatus(d, taskNodeID, runID, agent, status, result, experiment, policy)
 }
 
 // CreateEscalation is the exported wrapper for tests.
@@ -565,11 +895,21 @@ func FindProjectRoot(startPath string) string {
 	return findProjectRoot(startPath)
 }
 
-// ToolPermissions is the exported wrapper for tests.
+// ToolPermissions is the exported wrapper for tests. It returns role's
+// compiled-in default tool permissions; use ResolveToolPermissions to
+// consult an OrchestrationConfig.Policies override first.
 func ToolPermissions(role AgentRole) (allowed, disallowed string) {
 	return toolPermissions(role)
 }
 
+// ResolveToolPermissions is the exported wrapper for tests. It merges role's
+// compiled-in defaults with policies (see resolveToolPermissions), returning
+// the merged allowed/disallowed strings and the resolved policy recorded on
+// the run's tracks edge (nil if policies has no rule for role).
+func ResolveToolPermissions(role AgentRole, policies *ToolPolicies) (allowed, disallowed string, resolved *ResolvedToolPolicy) {
+	return resolveToolPermissions(role, policies)
+}
+
 // TruncateTitle is the exported wrapper for tests.
 func TruncateTitle(s string, max int) string {
 	return truncateTitle(s, max)
@@ -1,6 +1,8 @@
 package orchestrate
 
 import (
+	"context"
+	"fmt"
 	"testing"
 	"time"
 
@@ -72,7 +74,7 @@ func TestRunOrchestration_DryRun(t *testing.T) {
 	config.DryRun = true
 	config.OutputDir = t.TempDir()
 
-	result, err := RunOrchestration(d, "test dry run task for orchestration", config)
+	result, err := RunOrchestration(context.Background(), d, "test dry run task for orchestration", config)
 	if err != nil {
 		t.Fatalf("RunOrchestration DryRun failed: %v", err)
 	}
@@ -118,7 +120,7 @@ func TestRecordRunStatus(t *testing.T) {
 		NumTurns: 5,
 		Duration: 30 * time.Second,
 	}
-	RecordRunStatus(d, nodeID, "test-run-id-12345678", "coder", "success", mockResult, "test-experiment")
+	RecordRunStatus(d, nodeID, "test-run-id-12345678", "coder", "success", mockResult, "test-experiment", nil)
 
 	// Verify the Tracks edge exists
 	edges, err := d.GetEdgesForNode(nodeID)
@@ -187,3 +189,41 @@ func TestCreateEscalation(t *testing.T) {
 		}
 	}
 }
+
+func TestStatusForErr(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want RunStatus
+	}{
+		{"deadline exceeded", context.DeadlineExceeded, StatusTimeout},
+		{"canceled", context.Canceled, StatusCancelled},
+		{"wrapped deadline", fmt.Errorf("spawning coder: %w", context.DeadlineExceeded), StatusTimeout},
+		{"other error", fmt.Errorf("exit code 1"), StatusFailed},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := statusForErr(tt.err); got != tt.want {
+				t.Errorf("statusForErr(%v) = %s, want %s", tt.err, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPhaseContext_ZeroTimeoutReturnsParent(t *testing.T) {
+	parent := context.Background()
+	ctx, cancel := phaseContext(parent, 0)
+	defer cancel()
+	if ctx != parent {
+		t.Error("expected phaseContext with zero timeout to return the parent context unchanged")
+	}
+}
+
+func TestPhaseContext_TimeoutDerivesDeadline(t *testing.T) {
+	ctx, cancel := phaseContext(context.Background(), time.Hour)
+	defer cancel()
+	if _, ok := ctx.Deadline(); !ok {
+		t.Error("expected phaseContext with a positive timeout to set a deadline")
+	}
+}
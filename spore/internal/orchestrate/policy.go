@@ -0,0 +1,153 @@
+package orchestrate
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ToolPolicyRule overrides the hard-coded tool allow/disallow lists for one
+// AgentRole. Allow/Deny are tool names or patterns in the same shape
+// ClaudeConfig.AllowedTools/DisallowTools expect (e.g. "Bash",
+// "mcp__mycelica__*"). Deny always wins over both Allow and the role's
+// compiled-in defaults -- see ToolPolicies.resolve.
+type ToolPolicyRule struct {
+	Role  AgentRole `yaml:"role"`
+	Allow []string  `yaml:"allow,omitempty"`
+	Deny  []string  `yaml:"deny,omitempty"`
+}
+
+// ToolPolicies is an ordered set of ToolPolicyRules loaded from config or a
+// YAML file. The first rule matching a role wins; a role with no matching
+// rule falls back entirely to toolPermissions' compiled-in defaults.
+type ToolPolicies struct {
+	Rules []ToolPolicyRule
+
+	// hash is the hex sha256 of the source YAML this policy was loaded
+	// from, recorded on the tracks edge metadata so a run's effective
+	// policy can be traced back to the file that produced it. Empty for a
+	// policy built programmatically rather than loaded from disk.
+	hash string
+}
+
+func (p *ToolPolicies) ruleFor(role AgentRole) (ToolPolicyRule, bool) {
+	if p == nil {
+		return ToolPolicyRule{}, false
+	}
+	for _, r := range p.Rules {
+		if r.Role == role {
+			return r, true
+		}
+	}
+	return ToolPolicyRule{}, false
+}
+
+// Hash returns the loaded policy file's content hash, or "" if p is nil or
+// wasn't loaded from a file.
+func (p *ToolPolicies) Hash() string {
+	if p == nil {
+		return ""
+	}
+	return p.hash
+}
+
+// ResolvedToolPolicy is the outcome of merging a role's compiled-in default
+// tool permissions with ToolPolicies, recorded on the run's tracks edge
+// metadata so RecordRunStatus captures which effective policy was used.
+type ResolvedToolPolicy struct {
+	Role       AgentRole `json:"role"`
+	Allowed    string    `json:"allowed"`
+	Disallowed string    `json:"disallowed"`
+	PolicyHash string    `json:"policy_hash,omitempty"`
+}
+
+// resolveToolPermissions merges role's compiled-in default tool permissions
+// (see toolPermissions) with any matching rule in policies: the rule's Deny
+// entries are added to the disallow list, its Allow entries are added to the
+// allow list, and explicit deny beats allow -- any tool named in Deny is
+// dropped from the merged allow list even if it also appears in Allow or the
+// defaults. A nil policies, or one with no rule for role, reproduces
+// toolPermissions(role) exactly.
+func resolveToolPermissions(role AgentRole, policies *ToolPolicies) (allowed, disallowed string, resolved *ResolvedToolPolicy) {
+	defAllowed, defDisallowed := toolPermissions(role)
+	rule, ok := policies.ruleFor(role)
+	if !ok {
+		return defAllowed, defDisallowed, nil
+	}
+
+	denySet := toolSet(defDisallowed)
+	for _, t := range rule.Deny {
+		denySet[t] = true
+	}
+
+	allowSet := toolSet(defAllowed)
+	for _, t := range rule.Allow {
+		allowSet[t] = true
+	}
+	for t := range denySet {
+		delete(allowSet, t)
+	}
+
+	allowed = joinToolSet(allowSet)
+	disallowed = joinToolSet(denySet)
+	return allowed, disallowed, &ResolvedToolPolicy{
+		Role:       role,
+		Allowed:    allowed,
+		Disallowed: disallowed,
+		PolicyHash: policies.Hash(),
+	}
+}
+
+// toolSet splits a comma-separated AllowedTools/DisallowTools string into a
+// set, ignoring empty entries.
+func toolSet(csv string) map[string]bool {
+	set := make(map[string]bool)
+	for _, t := range strings.Split(csv, ",") {
+		t = strings.TrimSpace(t)
+		if t != "" {
+			set[t] = true
+		}
+	}
+	return set
+}
+
+// joinToolSet renders set back to the sorted comma-separated form
+// ClaudeConfig.AllowedTools/DisallowTools expect.
+func joinToolSet(set map[string]bool) string {
+	tools := make([]string, 0, len(set))
+	for t := range set {
+		tools = append(tools, t)
+	}
+	sort.Strings(tools)
+	return strings.Join(tools, ",")
+}
+
+// toolPoliciesFile is the on-disk YAML shape for a tool-policies file:
+//
+//	rules:
+//	  - role: coder
+//	    deny: [Bash]
+//	  - role: verifier
+//	    allow: [mcp__ci__*]
+type toolPoliciesFile struct {
+	Rules []ToolPolicyRule `yaml:"rules"`
+}
+
+// LoadToolPolicies reads a YAML file of ToolPolicyRules from path.
+func LoadToolPolicies(path string) (*ToolPolicies, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading tool policies: %w", err)
+	}
+	var f toolPoliciesFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parsing tool policies: %w", err)
+	}
+	sum := sha256.Sum256(raw)
+	return &ToolPolicies{Rules: f.Rules, hash: hex.EncodeToString(sum[:])}, nil
+}
@@ -0,0 +1,137 @@
+package orchestrate
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+const policyFixtureYAML = `
+rules:
+  - role: coder
+    deny: [Bash]
+  - role: verifier
+    allow: [mcp__ci__*]
+`
+
+func writePolicyFixture(t *testing.T) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "policies.yaml")
+	if err := os.WriteFile(path, []byte(policyFixtureYAML), 0o644); err != nil {
+		t.Fatalf("writing fixture: %v", err)
+	}
+	return path
+}
+
+func TestLoadToolPolicies_CoderDropsBash(t *testing.T) {
+	path := writePolicyFixture(t)
+	policies, err := LoadToolPolicies(path)
+	if err != nil {
+		t.Fatalf("loading tool policies: %v", err)
+	}
+	if policies.Hash() == "" {
+		t.Fatal("expected a non-empty policy hash")
+	}
+
+	allowed, disallowed, resolved := ResolveToolPermissions(RoleCoder, policies)
+	if strings.Contains(allowed, "Bash") {
+		t.Errorf("expected Bash dropped from allowed, got %q", allowed)
+	}
+	if !strings.Contains(disallowed, "Bash") {
+		t.Errorf("expected Bash added to disallowed, got %q", disallowed)
+	}
+	// mcp__mycelica__* is a coder default and isn't denied, so it survives.
+	if !strings.Contains(allowed, "mcp__mycelica__*") {
+		t.Errorf("expected mcp__mycelica__* to survive the merge, got %q", allowed)
+	}
+	if resolved == nil || resolved.Role != RoleCoder || resolved.PolicyHash != policies.Hash() {
+		t.Fatalf("expected a resolved policy carrying the hash, got %+v", resolved)
+	}
+}
+
+func TestResolveToolPermissions_DenyBeatsAllow(t *testing.T) {
+	policies := &ToolPolicies{Rules: []ToolPolicyRule{
+		{Role: RoleVerifier, Allow: []string{"Bash"}, Deny: []string{"Bash"}},
+	}}
+	allowed, disallowed, _ := ResolveToolPermissions(RoleVerifier, policies)
+	if strings.Contains(allowed, "Bash") {
+		t.Errorf("deny should beat allow, got allowed = %q", allowed)
+	}
+	if !strings.Contains(disallowed, "Bash") {
+		t.Errorf("expected Bash in disallowed, got %q", disallowed)
+	}
+}
+
+func TestResolveToolPermissions_NoRuleMatchesDefaults(t *testing.T) {
+	defAllowed, defDisallowed := ToolPermissions(RoleSummarizer)
+	policies := &ToolPolicies{Rules: []ToolPolicyRule{{Role: RoleCoder, Deny: []string{"Bash"}}}}
+	allowed, disallowed, resolved := ResolveToolPermissions(RoleSummarizer, policies)
+	if allowed != defAllowed || disallowed != defDisallowed {
+		t.Errorf("expected defaults preserved for an unmatched role, got (%q, %q)", allowed, disallowed)
+	}
+	if resolved != nil {
+		t.Errorf("expected a nil resolved policy for an unmatched role, got %+v", resolved)
+	}
+}
+
+func TestResolveToolPermissions_NilPoliciesMatchesDefaults(t *testing.T) {
+	defAllowed, defDisallowed := ToolPermissions(RoleCoder)
+	allowed, disallowed, resolved := ResolveToolPermissions(RoleCoder, nil)
+	if allowed != defAllowed || disallowed != defDisallowed || resolved != nil {
+		t.Errorf("expected nil policies to reproduce defaults exactly, got (%q, %q, %+v)", allowed, disallowed, resolved)
+	}
+}
+
+// TestRecordRunStatus_PolicyHashOnTracksEdge confirms a resolved tool policy
+// is recorded on the run's self-tracks edge metadata, so an operator can
+// trace a run back to the policy file that shaped its tool permissions.
+func TestRecordRunStatus_PolicyHashOnTracksEdge(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	nodeID, err := d.CreateNode("test-record-run-status-policy", db.CreateNodeOpts{
+		AgentID:   "spore:test",
+		NodeClass: "operational",
+		MetaType:  "task",
+		Source:    "test",
+	})
+	if err != nil {
+		t.Fatalf("creating test node: %v", err)
+	}
+	defer d.DeleteNode(nodeID)
+
+	path := writePolicyFixture(t)
+	policies, err := LoadToolPolicies(path)
+	if err != nil {
+		t.Fatalf("loading tool policies: %v", err)
+	}
+	_, _, resolved := ResolveToolPermissions(RoleCoder, policies)
+
+	mockResult := &ClaudeResult{ExitCode: 0, CostUSD: 1.23, NumTurns: 5, Duration: 30 * time.Second}
+	RecordRunStatus(d, nodeID, "test-run-id-policy", "coder", "success", mockResult, "test-experiment", resolved)
+
+	edges, err := d.GetEdgesForNode(nodeID)
+	if err != nil {
+		t.Fatalf("querying edges: %v", err)
+	}
+
+	var metadata string
+	for _, e := range edges {
+		if e.EdgeType == "tracks" && e.SourceID == nodeID && e.TargetID == nodeID && e.Metadata != nil {
+			metadata = *e.Metadata
+		}
+	}
+	if metadata == "" {
+		t.Fatal("expected a self-referential tracks edge with metadata")
+	}
+	if !strings.Contains(metadata, policies.Hash()) {
+		t.Errorf("expected tracks edge metadata to contain the policy hash %q, got %s", policies.Hash(), metadata)
+	}
+	if !strings.Contains(metadata, `"policy_role":"coder"`) {
+		t.Errorf("expected tracks edge metadata to record the resolved role, got %s", metadata)
+	}
+}
@@ -0,0 +1,112 @@
+package orchestrate
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/BurntSushi/toml"
+)
+
+// Profile is the persisted subset of OrchestrationConfig that `spore
+// profile set` writes to disk and LoadDefaults reads back, so `retry` (and
+// a future `run` command) don't need long flag lists repeated on every
+// invocation. Zero values mean "not set in the profile" rather than an
+// explicit override to zero/empty/false.
+type Profile struct {
+	MaxBounces  int    `toml:"max_bounces,omitempty"`
+	MaxTurns    int    `toml:"max_turns,omitempty"`
+	CoderModel  string `toml:"coder_model,omitempty"`
+	Experiment  string `toml:"experiment,omitempty"`
+	OutputDir   string `toml:"output_dir,omitempty"`
+	NoSummarize bool   `toml:"no_summarize,omitempty"`
+	Verbose     bool   `toml:"verbose,omitempty"`
+	Quiet       bool   `toml:"quiet,omitempty"`
+	JSON        bool   `toml:"json,omitempty"`
+}
+
+// ProfilePath returns the path a profile is read from/written to: a
+// "profile.toml" file next to the database at dbPath.
+func ProfilePath(dbPath string) string {
+	return filepath.Join(filepath.Dir(dbPath), "profile.toml")
+}
+
+// LoadProfile reads the profile TOML at path. A missing file is not an
+// error; it returns a zero-value Profile, since having no persisted
+// profile is the common case.
+func LoadProfile(path string) (Profile, error) {
+	var p Profile
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return p, nil
+		}
+		return p, fmt.Errorf("reading profile: %w", err)
+	}
+	if _, err := toml.Decode(string(raw), &p); err != nil {
+		return p, fmt.Errorf("parsing profile %s: %w", path, err)
+	}
+	return p, nil
+}
+
+// SaveProfile writes p as TOML to path, creating or truncating the file.
+func SaveProfile(path string, p Profile) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating profile: %w", err)
+	}
+	defer f.Close()
+	if err := p.Encode(f); err != nil {
+		return fmt.Errorf("writing profile %s: %w", path, err)
+	}
+	return nil
+}
+
+// Encode writes p as TOML to w.
+func (p Profile) Encode(w io.Writer) error {
+	return toml.NewEncoder(w).Encode(p)
+}
+
+// LoadDefaults returns a copy of c with any field the profile persisted at
+// path sets non-zero overriding c's own value. A missing profile file is
+// not an error; c is returned unchanged. Called as
+// DefaultOrchestrationConfig().LoadDefaults(path), this gives `retry` and
+// future commands a single "built-in default, overridden by persisted
+// profile" baseline, which CLI flag/env resolution then overrides on top
+// (see cmd.ResolveIntFlag and friends) to get the full
+// flag > env > profile > built-in-default precedence.
+func (c OrchestrationConfig) LoadDefaults(path string) (OrchestrationConfig, error) {
+	p, err := LoadProfile(path)
+	if err != nil {
+		return c, err
+	}
+	if p.MaxBounces != 0 {
+		c.MaxBounces = p.MaxBounces
+	}
+	if p.MaxTurns != 0 {
+		c.MaxTurns = p.MaxTurns
+	}
+	if p.CoderModel != "" {
+		c.CoderModel = p.CoderModel
+	}
+	if p.Experiment != "" {
+		c.Experiment = p.Experiment
+	}
+	if p.OutputDir != "" {
+		c.OutputDir = p.OutputDir
+	}
+	if p.NoSummarize {
+		c.NoSummarize = true
+	}
+	if p.Verbose {
+		c.Verbose = true
+	}
+	if p.Quiet {
+		c.Quiet = true
+	}
+	if p.JSON {
+		c.JSON = true
+	}
+	return c, nil
+}
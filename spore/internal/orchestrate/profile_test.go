@@ -0,0 +1,66 @@
+package orchestrate
+
+import (
+	"path/filepath"
+	"testing"
+)
+
+func TestLoadProfile_MissingFileIsZeroValue(t *testing.T) {
+	p, err := LoadProfile(filepath.Join(t.TempDir(), "profile.toml"))
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if p != (Profile{}) {
+		t.Errorf("expected a zero-value Profile for a missing file, got %+v", p)
+	}
+}
+
+func TestSaveLoadProfile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.toml")
+	p := Profile{MaxBounces: 5, CoderModel: "opus", Experiment: "canary", Quiet: true}
+
+	if err := SaveProfile(path, p); err != nil {
+		t.Fatalf("SaveProfile: %v", err)
+	}
+
+	loaded, err := LoadProfile(path)
+	if err != nil {
+		t.Fatalf("LoadProfile: %v", err)
+	}
+	if loaded != p {
+		t.Errorf("loaded profile = %+v, want %+v", loaded, p)
+	}
+}
+
+func TestOrchestrationConfig_LoadDefaults(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "profile.toml")
+	if err := SaveProfile(path, Profile{MaxBounces: 7, CoderModel: "opus"}); err != nil {
+		t.Fatalf("SaveProfile: %v", err)
+	}
+
+	base := DefaultOrchestrationConfig()
+	merged, err := base.LoadDefaults(path)
+	if err != nil {
+		t.Fatalf("LoadDefaults: %v", err)
+	}
+	if merged.MaxBounces != 7 {
+		t.Errorf("MaxBounces = %d, want profile override 7", merged.MaxBounces)
+	}
+	if merged.CoderModel != "opus" {
+		t.Errorf("CoderModel = %q, want profile override %q", merged.CoderModel, "opus")
+	}
+	if merged.MaxTurns != base.MaxTurns {
+		t.Errorf("MaxTurns = %d, want untouched built-in default %d", merged.MaxTurns, base.MaxTurns)
+	}
+}
+
+func TestOrchestrationConfig_LoadDefaults_MissingProfileIsNoop(t *testing.T) {
+	base := DefaultOrchestrationConfig()
+	merged, err := base.LoadDefaults(filepath.Join(t.TempDir(), "profile.toml"))
+	if err != nil {
+		t.Fatalf("LoadDefaults: %v", err)
+	}
+	if merged.MaxBounces != base.MaxBounces || merged.MaxTurns != base.MaxTurns || merged.OutputDir != base.OutputDir {
+		t.Errorf("expected an unchanged config for a missing profile, got %+v vs %+v", merged, base)
+	}
+}
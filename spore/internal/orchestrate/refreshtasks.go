@@ -0,0 +1,113 @@
+package orchestrate
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/graph"
+)
+
+// RefreshTaskThresholds configures which entries in a graph.StalenessReport
+// GenerateRefreshTasks acts on.
+type RefreshTaskThresholds struct {
+	// DriftDays is the minimum StaleSummary.DriftDays to emit a summarizer
+	// refresh task for.
+	DriftDays int64
+	// MinRefCount is the minimum StaleNode.RecentRefCount to emit a coder
+	// revisit task for.
+	MinRefCount int
+}
+
+// DefaultRefreshTaskThresholds returns the thresholds GenerateRefreshTasks
+// uses when none are supplied.
+func DefaultRefreshTaskThresholds() RefreshTaskThresholds {
+	return RefreshTaskThresholds{DriftDays: 7, MinRefCount: 3}
+}
+
+// GenerateRefreshTasks turns a graph.StalenessReport into task files: a
+// RoleSummarizer refresh for each StaleSummary whose DriftDays meets
+// thresholds.DriftDays (implNodeID set to the drifted target, verdict set
+// to VerdictSupports so the task file frames it as "refresh this, the
+// implementation side already passed verification"), and a RoleCoder
+// revisit for each StaleNode whose RecentRefCount meets thresholds.MinRefCount.
+//
+// Repeated calls against an unchanged report are a no-op for entries already
+// queued (see db.WasRefreshEmitted) — further drift since the last emission
+// re-queues, a static StalenessReport doesn't — so a periodic staleness
+// sweep doesn't spam the same task file every run.
+//
+// Returns the paths of every task file written, in report order.
+func GenerateRefreshTasks(d *db.DB, report *graph.StalenessReport, thresholds RefreshTaskThresholds, cfg TaskFileConfig, outDir string) ([]string, error) {
+	if thresholds.DriftDays <= 0 {
+		thresholds.DriftDays = DefaultRefreshTaskThresholds().DriftDays
+	}
+	if thresholds.MinRefCount <= 0 {
+		thresholds.MinRefCount = DefaultRefreshTaskThresholds().MinRefCount
+	}
+
+	var paths []string
+
+	for _, s := range report.StaleSummaries {
+		if s.DriftDays < thresholds.DriftDays {
+			continue
+		}
+
+		key := "summary:" + s.SummaryNodeID
+		fingerprint := fmt.Sprintf("%d", s.DriftDays)
+		already, err := d.WasRefreshEmitted(key, fingerprint)
+		if err != nil {
+			return paths, fmt.Errorf("checking refresh dedupe for %s: %w", key, err)
+		}
+		if already {
+			continue
+		}
+
+		task := fmt.Sprintf("refresh summary of %s", s.TargetTitle)
+		path, _, err := GenerateTaskFile(
+			d, task, RoleSummarizer, uuid.New().String(), s.TargetNodeID,
+			0, 1, s.TargetNodeID, VerdictSupports, cfg, outDir, nil, nil,
+		)
+		if err != nil {
+			return paths, fmt.Errorf("generating refresh task for summary %s: %w", s.SummaryNodeID, err)
+		}
+		paths = append(paths, path)
+
+		if err := d.MarkRefreshEmitted(key, fingerprint, time.Now().UnixMilli()); err != nil {
+			return paths, fmt.Errorf("recording refresh dedupe for %s: %w", key, err)
+		}
+	}
+
+	for _, n := range report.StaleNodes {
+		if n.RecentRefCount < thresholds.MinRefCount {
+			continue
+		}
+
+		key := "node:" + n.ID
+		fingerprint := fmt.Sprintf("%d", n.RecentRefCount)
+		already, err := d.WasRefreshEmitted(key, fingerprint)
+		if err != nil {
+			return paths, fmt.Errorf("checking refresh dedupe for %s: %w", key, err)
+		}
+		if already {
+			continue
+		}
+
+		task := fmt.Sprintf("revisit stale but heavily-referenced node: %s", n.Title)
+		path, _, err := GenerateTaskFile(
+			d, task, RoleCoder, uuid.New().String(), n.ID,
+			0, 1, "", VerdictUnknown, cfg, outDir, nil, nil,
+		)
+		if err != nil {
+			return paths, fmt.Errorf("generating refresh task for node %s: %w", n.ID, err)
+		}
+		paths = append(paths, path)
+
+		if err := d.MarkRefreshEmitted(key, fingerprint, time.Now().UnixMilli()); err != nil {
+			return paths, fmt.Errorf("recording refresh dedupe for %s: %w", key, err)
+		}
+	}
+
+	return paths, nil
+}
@@ -0,0 +1,119 @@
+package orchestrate
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"mycelica/spore/internal/graph"
+)
+
+// staleSnapshot builds a synthetic GraphSnapshot with one drifted summary
+// ("Summary A" summarizing "Target A", updated before it) and one stale but
+// heavily-referenced node ("Old Node"), for exercising GenerateRefreshTasks
+// without a populated database.
+func staleSnapshot() *graph.GraphSnapshot {
+	now := int64(1_000_000_000_000)
+	oldTime := now - 30*86_400_000
+	nodes := []*graph.NodeInfo{
+		{ID: "summary-a", Title: "Summary A", CreatedAt: oldTime, UpdatedAt: oldTime},
+		{ID: "target-a", Title: "Target A", CreatedAt: now, UpdatedAt: now},
+		{ID: "old-node", Title: "Old Node", CreatedAt: oldTime, UpdatedAt: oldTime},
+		{ID: "referrer", Title: "Referrer", CreatedAt: now, UpdatedAt: now},
+	}
+	edges := []graph.EdgeInfo{
+		{ID: "e1", Source: "summary-a", Target: "target-a", EdgeType: "summarizes", CreatedAt: now},
+		{ID: "e2", Source: "referrer", Target: "old-node", EdgeType: "relates_to", CreatedAt: now},
+	}
+	return graph.NewSnapshot(nodes, edges)
+}
+
+func TestGenerateRefreshTasks_EmitsSummaryAndNodeTasks(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	report := graph.ComputeStaleness(staleSnapshot(), 7)
+	if len(report.StaleSummaries) == 0 {
+		t.Fatal("expected the synthetic snapshot to produce a stale summary")
+	}
+	if len(report.StaleNodes) == 0 {
+		t.Fatal("expected the synthetic snapshot to produce a stale node")
+	}
+
+	outDir := t.TempDir()
+	paths, err := GenerateRefreshTasks(d, report, DefaultRefreshTaskThresholds(), DefaultTaskFileConfig(), outDir)
+	if err != nil {
+		t.Fatalf("GenerateRefreshTasks: %v", err)
+	}
+	if len(paths) != 2 {
+		t.Fatalf("expected 2 task files (1 summary refresh + 1 node revisit), got %d: %v", len(paths), paths)
+	}
+
+	var sawSummarizer, sawCoder bool
+	for _, p := range paths {
+		content, err := os.ReadFile(p)
+		if err != nil {
+			t.Fatalf("reading generated task file %s: %v", p, err)
+		}
+		md := string(content)
+		switch {
+		case strings.Contains(md, "**Agent:** summarizer"):
+			sawSummarizer = true
+			if !strings.Contains(md, "refresh summary of Target A") {
+				t.Errorf("expected summarizer task file to reference the drifted target, got:\n%s", md)
+			}
+		case strings.Contains(md, "**Agent:** coder"):
+			sawCoder = true
+			if !strings.Contains(md, "Old Node") {
+				t.Errorf("expected coder task file to reference the stale node, got:\n%s", md)
+			}
+		}
+	}
+	if !sawSummarizer {
+		t.Errorf("expected one summarizer task file among %v", paths)
+	}
+	if !sawCoder {
+		t.Errorf("expected one coder task file among %v", paths)
+	}
+}
+
+func TestGenerateRefreshTasks_DedupesRepeatedRuns(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	report := graph.ComputeStaleness(staleSnapshot(), 7)
+	outDir := t.TempDir()
+
+	first, err := GenerateRefreshTasks(d, report, DefaultRefreshTaskThresholds(), DefaultTaskFileConfig(), outDir)
+	if err != nil {
+		t.Fatalf("GenerateRefreshTasks (first run): %v", err)
+	}
+	if len(first) != 2 {
+		t.Fatalf("expected 2 task files on the first run, got %d", len(first))
+	}
+
+	second, err := GenerateRefreshTasks(d, report, DefaultRefreshTaskThresholds(), DefaultTaskFileConfig(), outDir)
+	if err != nil {
+		t.Fatalf("GenerateRefreshTasks (second run): %v", err)
+	}
+	if len(second) != 0 {
+		t.Errorf("expected an unchanged report to be fully deduped on rerun, got %d task file(s): %v", len(second), second)
+	}
+}
+
+func TestGenerateRefreshTasks_BelowThresholdIsSkipped(t *testing.T) {
+	d := openTestDB(t)
+	defer d.Close()
+
+	report := graph.ComputeStaleness(staleSnapshot(), 7)
+	outDir := t.TempDir()
+
+	strict := RefreshTaskThresholds{DriftDays: 10_000, MinRefCount: 10_000}
+	paths, err := GenerateRefreshTasks(d, report, strict, DefaultTaskFileConfig(), outDir)
+	if err != nil {
+		t.Fatalf("GenerateRefreshTasks: %v", err)
+	}
+	if len(paths) != 0 {
+		t.Errorf("expected no task files below threshold, got %v", paths)
+	}
+}
@@ -0,0 +1,283 @@
+package orchestrate
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"mycelica/spore/internal/db"
+	"mycelica/spore/internal/graph"
+)
+
+// RetrievalHit is a single candidate returned by any RetrievalProvider search
+// method -- uniform enough for findAnchors' RRF merge regardless of which
+// backend produced it.
+type RetrievalHit struct {
+	ID    string
+	Title string
+	Score float64
+}
+
+// RetrievalProvider abstracts findAnchors' and gatherContext's search and
+// expansion backend behind an interface, so it can be swapped from the
+// default SQLite-backed implementation to an external code-search sidecar, a
+// remote vector DB, or (via CompositeRetrievalProvider) several of those
+// fanned out together, without touching the RRF merge or markdown rendering
+// pipeline.
+type RetrievalProvider interface {
+	// SemanticSearch ranks nodes by embedding similarity to embedding,
+	// returning at most topK hits scoring at or above threshold.
+	SemanticSearch(embedding []float32, topK int, threshold float64) ([]RetrievalHit, error)
+	// KeywordSearch ranks nodes by FTS relevance to query.
+	KeywordSearch(query string, topK int) ([]RetrievalHit, error)
+	// SymbolSearch ranks nodes by trigram-indexed identifier match to query
+	// (see db.SearchCodeSymbols).
+	SymbolSearch(query string, topK int) ([]RetrievalHit, error)
+	// ExpandContext walks outward from anchorID per cfg, the same Dijkstra
+	// relevance-budget expansion gatherContext renders into the Graph
+	// Context table.
+	ExpandContext(anchorID string, cfg *db.ContextConfig) ([]db.ContextNode, error)
+}
+
+// SQLiteRetrievalProvider is the default RetrievalProvider: it wraps the
+// current *db.DB calls verbatim, preserving the exact filtering (operational
+// node-class exclusion, self-exclusion, AITitle preference) findAnchors and
+// gatherContext relied on before the RetrievalProvider split.
+type SQLiteRetrievalProvider struct {
+	db          *db.DB
+	useANNIndex bool
+	// excludeID is omitted from every search result -- normally the task
+	// node itself, so a task doesn't anchor against its own description.
+	excludeID string
+}
+
+// NewSQLiteRetrievalProvider builds the default RetrievalProvider over d.
+// excludeID is typically the task node ID being searched from.
+func NewSQLiteRetrievalProvider(d *db.DB, useANNIndex bool, excludeID string) *SQLiteRetrievalProvider {
+	return &SQLiteRetrievalProvider{db: d, useANNIndex: useANNIndex, excludeID: excludeID}
+}
+
+func resolveTitle(n *db.Node) string {
+	if n.AITitle != nil {
+		return *n.AITitle
+	}
+	return n.Title
+}
+
+func (p *SQLiteRetrievalProvider) SemanticSearch(embedding []float32, topK int, threshold float64) ([]RetrievalHit, error) {
+	if embedding == nil {
+		return nil, nil
+	}
+	allEmbs, err := p.db.GetNodesWithEmbeddings(db.DefaultEmbeddingModel)
+	if err != nil {
+		return nil, err
+	}
+	var annIndex graph.Index
+	if p.useANNIndex {
+		annIndex, _ = graph.OpenIndex(p.db)
+	}
+	similar := graph.FindSimilarAuto(annIndex, embedding, allEmbs, p.excludeID, topK, float32(threshold), p.useANNIndex)
+
+	hits := make([]RetrievalHit, 0, len(similar))
+	for _, s := range similar {
+		node, err := p.db.GetNode(s.ID)
+		if err != nil || node == nil {
+			continue
+		}
+		if node.NodeClass != nil && *node.NodeClass == "operational" {
+			continue
+		}
+		hits = append(hits, RetrievalHit{ID: s.ID, Title: resolveTitle(node), Score: float64(s.Similarity)})
+	}
+	return hits, nil
+}
+
+func (p *SQLiteRetrievalProvider) KeywordSearch(query string, topK int) ([]RetrievalHit, error) {
+	if db.BuildFTSQuery(query) == "" {
+		return nil, nil
+	}
+	nodes, err := p.db.SearchNodes(query)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]RetrievalHit, 0, len(nodes))
+	for _, n := range nodes {
+		if n.ID == p.excludeID {
+			continue
+		}
+		if n.NodeClass != nil && *n.NodeClass == "operational" {
+			continue
+		}
+		hits = append(hits, RetrievalHit{ID: n.ID, Title: resolveTitle(&n)})
+		if topK > 0 && len(hits) >= topK {
+			break
+		}
+	}
+	return hits, nil
+}
+
+func (p *SQLiteRetrievalProvider) SymbolSearch(query string, topK int) ([]RetrievalHit, error) {
+	nodes, err := p.db.SearchCodeSymbols(query, topK)
+	if err != nil {
+		return nil, err
+	}
+	hits := make([]RetrievalHit, 0, len(nodes))
+	for _, n := range nodes {
+		if n.ID == p.excludeID {
+			continue
+		}
+		hits = append(hits, RetrievalHit{ID: n.ID, Title: resolveTitle(&n)})
+	}
+	return hits, nil
+}
+
+func (p *SQLiteRetrievalProvider) ExpandContext(anchorID string, cfg *db.ContextConfig) ([]db.ContextNode, error) {
+	return p.db.ContextForTask(anchorID, cfg)
+}
+
+// defaultCompositeDeadline bounds how long CompositeRetrievalProvider waits
+// for any single backend before treating it as failed and proceeding without
+// it -- a slow or unreachable sidecar shouldn't stall task file generation.
+const defaultCompositeDeadline = 2 * time.Second
+
+// CompositeRetrievalProvider fans a call out to several backends
+// concurrently and merges their results by Reciprocal Rank Fusion, the same
+// formula findAnchors itself uses to merge sources -- so adding a backend
+// here composes with, rather than replaces, the existing fusion.
+type CompositeRetrievalProvider struct {
+	Providers []RetrievalProvider
+	// Deadline bounds each backend call; <= 0 uses defaultCompositeDeadline.
+	Deadline time.Duration
+}
+
+func (c *CompositeRetrievalProvider) deadline() time.Duration {
+	if c.Deadline <= 0 {
+		return defaultCompositeDeadline
+	}
+	return c.Deadline
+}
+
+// fanOutHits runs call against every provider concurrently, drops any that
+// errors or exceeds the deadline, and merges the survivors via RRF.
+func (c *CompositeRetrievalProvider) fanOutHits(call func(RetrievalProvider) ([]RetrievalHit, error)) ([]RetrievalHit, error) {
+	results := make([][]RetrievalHit, len(c.Providers))
+	var wg sync.WaitGroup
+	for i, p := range c.Providers {
+		wg.Add(1)
+		go func(i int, p RetrievalProvider) {
+			defer wg.Done()
+			done := make(chan []RetrievalHit, 1)
+			go func() {
+				hits, err := call(p)
+				if err != nil {
+					hits = nil
+				}
+				done <- hits
+			}()
+			select {
+			case hits := <-done:
+				results[i] = hits
+			case <-time.After(c.deadline()):
+				results[i] = nil
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	var lists [][]RetrievalHit
+	for _, hits := range results {
+		if len(hits) > 0 {
+			lists = append(lists, hits)
+		}
+	}
+	return mergeHitsByRRF(lists), nil
+}
+
+func (c *CompositeRetrievalProvider) SemanticSearch(embedding []float32, topK int, threshold float64) ([]RetrievalHit, error) {
+	return c.fanOutHits(func(p RetrievalProvider) ([]RetrievalHit, error) {
+		return p.SemanticSearch(embedding, topK, threshold)
+	})
+}
+
+func (c *CompositeRetrievalProvider) KeywordSearch(query string, topK int) ([]RetrievalHit, error) {
+	return c.fanOutHits(func(p RetrievalProvider) ([]RetrievalHit, error) {
+		return p.KeywordSearch(query, topK)
+	})
+}
+
+func (c *CompositeRetrievalProvider) SymbolSearch(query string, topK int) ([]RetrievalHit, error) {
+	return c.fanOutHits(func(p RetrievalProvider) ([]RetrievalHit, error) {
+		return p.SymbolSearch(query, topK)
+	})
+}
+
+// ExpandContext fans out to every backend and merges by NodeID, keeping each
+// node's highest-Relevance entry across backends (mirroring gatherContext's
+// own per-anchor "seen" merge).
+func (c *CompositeRetrievalProvider) ExpandContext(anchorID string, cfg *db.ContextConfig) ([]db.ContextNode, error) {
+	results := make([][]db.ContextNode, len(c.Providers))
+	var wg sync.WaitGroup
+	for i, p := range c.Providers {
+		wg.Add(1)
+		go func(i int, p RetrievalProvider) {
+			defer wg.Done()
+			done := make(chan []db.ContextNode, 1)
+			go func() {
+				nodes, err := p.ExpandContext(anchorID, cfg)
+				if err != nil {
+					nodes = nil
+				}
+				done <- nodes
+			}()
+			select {
+			case nodes := <-done:
+				results[i] = nodes
+			case <-time.After(c.deadline()):
+				results[i] = nil
+			}
+		}(i, p)
+	}
+	wg.Wait()
+
+	byID := make(map[string]db.ContextNode)
+	for _, nodes := range results {
+		for _, n := range nodes {
+			if prev, ok := byID[n.NodeID]; !ok || n.Relevance > prev.Relevance {
+				byID[n.NodeID] = n
+			}
+		}
+	}
+	merged := make([]db.ContextNode, 0, len(byID))
+	for _, n := range byID {
+		merged = append(merged, n)
+	}
+	return merged, nil
+}
+
+// mergeHitsByRRF merges several ranked RetrievalHit lists via Reciprocal
+// Rank Fusion (see rrfK), the same formula findAnchors uses to merge its own
+// semantic/FTS/code sources.
+func mergeHitsByRRF(lists [][]RetrievalHit) []RetrievalHit {
+	type fused struct {
+		RetrievalHit
+		score float64
+	}
+	byID := make(map[string]*fused)
+	for _, list := range lists {
+		for i, hit := range list {
+			f, ok := byID[hit.ID]
+			if !ok {
+				f = &fused{RetrievalHit: hit}
+				byID[hit.ID] = f
+			}
+			f.score += 1.0 / float64(rrfK+i+1)
+		}
+	}
+	merged := make([]RetrievalHit, 0, len(byID))
+	for _, f := range byID {
+		f.Score = f.score
+		merged = append(merged, f.RetrievalHit)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Score > merged[j].Score })
+	return merged
+}
@@ -0,0 +1,139 @@
+package orchestrate
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+// fakeRetrievalProvider is a hand-rolled RetrievalProvider for testing
+// CompositeRetrievalProvider without a real *db.DB.
+type fakeRetrievalProvider struct {
+	hits  []RetrievalHit
+	delay time.Duration
+	err   error
+}
+
+func (f *fakeRetrievalProvider) wait() {
+	if f.delay > 0 {
+		time.Sleep(f.delay)
+	}
+}
+
+func (f *fakeRetrievalProvider) SemanticSearch(embedding []float32, topK int, threshold float64) ([]RetrievalHit, error) {
+	f.wait()
+	return f.hits, f.err
+}
+
+func (f *fakeRetrievalProvider) KeywordSearch(query string, topK int) ([]RetrievalHit, error) {
+	f.wait()
+	return f.hits, f.err
+}
+
+func (f *fakeRetrievalProvider) SymbolSearch(query string, topK int) ([]RetrievalHit, error) {
+	f.wait()
+	return f.hits, f.err
+}
+
+func (f *fakeRetrievalProvider) ExpandContext(anchorID string, cfg *db.ContextConfig) ([]db.ContextNode, error) {
+	f.wait()
+	if f.err != nil {
+		return nil, f.err
+	}
+	nodes := make([]db.ContextNode, len(f.hits))
+	for i, h := range f.hits {
+		nodes[i] = db.ContextNode{NodeID: h.ID, NodeTitle: h.Title, Relevance: h.Score}
+	}
+	return nodes, nil
+}
+
+func TestMergeHitsByRRF_OverlappingListsScoreHigher(t *testing.T) {
+	a := []RetrievalHit{{ID: "shared", Title: "Shared"}, {ID: "only-a", Title: "OnlyA"}}
+	b := []RetrievalHit{{ID: "shared", Title: "Shared"}, {ID: "only-b", Title: "OnlyB"}}
+
+	merged := mergeHitsByRRF([][]RetrievalHit{a, b})
+	if len(merged) != 3 {
+		t.Fatalf("expected 3 distinct hits, got %d: %+v", len(merged), merged)
+	}
+	if merged[0].ID != "shared" {
+		t.Errorf("expected the hit ranked #1 in both lists to score highest, got %+v", merged[0])
+	}
+}
+
+func TestCompositeRetrievalProvider_MergesAcrossBackends(t *testing.T) {
+	c := &CompositeRetrievalProvider{
+		Providers: []RetrievalProvider{
+			&fakeRetrievalProvider{hits: []RetrievalHit{{ID: "n1", Title: "One"}, {ID: "n2", Title: "Two"}}},
+			&fakeRetrievalProvider{hits: []RetrievalHit{{ID: "n2", Title: "Two"}, {ID: "n3", Title: "Three"}}},
+		},
+	}
+
+	hits, err := c.KeywordSearch("query", 10)
+	if err != nil {
+		t.Fatalf("KeywordSearch: %v", err)
+	}
+	if len(hits) != 3 {
+		t.Fatalf("expected 3 distinct hits across both backends, got %d: %+v", len(hits), hits)
+	}
+	if hits[0].ID != "n2" {
+		t.Errorf("expected n2 (ranked in both backends) to score highest, got %+v", hits[0])
+	}
+}
+
+func TestCompositeRetrievalProvider_SlowBackendTimesOutWithoutBlocking(t *testing.T) {
+	c := &CompositeRetrievalProvider{
+		Deadline: 20 * time.Millisecond,
+		Providers: []RetrievalProvider{
+			&fakeRetrievalProvider{hits: []RetrievalHit{{ID: "fast", Title: "Fast"}}},
+			&fakeRetrievalProvider{hits: []RetrievalHit{{ID: "slow", Title: "Slow"}}, delay: time.Second},
+		},
+	}
+
+	start := time.Now()
+	hits, err := c.SemanticSearch(nil, 10, 0.3)
+	if err != nil {
+		t.Fatalf("SemanticSearch: %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("expected the slow backend's timeout to bound total latency, took %s", elapsed)
+	}
+	if len(hits) != 1 || hits[0].ID != "fast" {
+		t.Errorf("expected only the fast backend's hit to survive, got %+v", hits)
+	}
+}
+
+func TestCompositeRetrievalProvider_ErroringBackendIsDropped(t *testing.T) {
+	c := &CompositeRetrievalProvider{
+		Providers: []RetrievalProvider{
+			&fakeRetrievalProvider{hits: []RetrievalHit{{ID: "ok", Title: "OK"}}},
+			&fakeRetrievalProvider{err: fmt.Errorf("backend unavailable")},
+		},
+	}
+
+	hits, err := c.SymbolSearch("query", 10)
+	if err != nil {
+		t.Fatalf("SymbolSearch: %v", err)
+	}
+	if len(hits) != 1 || hits[0].ID != "ok" {
+		t.Errorf("expected the erroring backend to be dropped, got %+v", hits)
+	}
+}
+
+func TestCompositeRetrievalProvider_ExpandContextMergesByHighestRelevance(t *testing.T) {
+	c := &CompositeRetrievalProvider{
+		Providers: []RetrievalProvider{
+			&fakeRetrievalProvider{hits: []RetrievalHit{{ID: "n1", Title: "One", Score: 0.2}}},
+			&fakeRetrievalProvider{hits: []RetrievalHit{{ID: "n1", Title: "One", Score: 0.9}}},
+		},
+	}
+
+	nodes, err := c.ExpandContext("anchor", db.DefaultContextConfig())
+	if err != nil {
+		t.Fatalf("ExpandContext: %v", err)
+	}
+	if len(nodes) != 1 || nodes[0].Relevance != 0.9 {
+		t.Errorf("expected the higher-relevance duplicate to win, got %+v", nodes)
+	}
+}
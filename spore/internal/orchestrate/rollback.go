@@ -0,0 +1,80 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// RollbackRecord is the durable record a RollbackPolicy other than
+// RollbackNever needs to undo a run after the fact (see the `orchestrate
+// rollback` CLI verb): where the run started from, and every bounce
+// snapshot taken along the way.
+type RollbackRecord struct {
+	RunID      string   `json:"run_id"`
+	WorkDir    string   `json:"work_dir"`
+	PreRunHEAD string   `json:"pre_run_head"`
+	BounceRefs []string `json:"bounce_refs,omitempty"`
+}
+
+// rollbackRecordPath returns the sidecar JSON path for a run's rollback record.
+func rollbackRecordPath(outputDir, runID string) string {
+	return filepath.Join(outputDir, fmt.Sprintf("rollback-%s.json", runID))
+}
+
+// saveRollbackRecord persists rr under outputDir, overwriting any previous
+// record for the same run. Non-fatal: a disk error only logs a warning, the
+// same as saveCheckpoint, so it never aborts an otherwise-successful run.
+func saveRollbackRecord(outputDir string, rr RollbackRecord) {
+	if err := os.MkdirAll(outputDir, 0o755); err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to create rollback record dir: %v\n", err)
+		return
+	}
+	data, err := json.MarshalIndent(rr, "", "  ")
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to marshal rollback record: %v\n", err)
+		return
+	}
+	if err := os.WriteFile(rollbackRecordPath(outputDir, rr.RunID), data, 0o644); err != nil {
+		fmt.Fprintf(os.Stderr, "[orchestrate] Warning: failed to write rollback record: %v\n", err)
+	}
+}
+
+// loadRollbackRecord reads the rollback record for runID, or returns nil
+// (with no error) if the run never ran under a RollbackPolicy other than
+// RollbackNever.
+func loadRollbackRecord(outputDir, runID string) (*RollbackRecord, error) {
+	data, err := os.ReadFile(rollbackRecordPath(outputDir, runID))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading rollback record: %w", err)
+	}
+	var rr RollbackRecord
+	if err := json.Unmarshal(data, &rr); err != nil {
+		return nil, fmt.Errorf("parsing rollback record %s: %w", runID, err)
+	}
+	return &rr, nil
+}
+
+// Rollback resets runID's workDir back to the HEAD it started from,
+// discarding any uncommitted changes the run left behind along the way (see
+// StashAndResetToHEAD -- the discarded changes are recoverable from `git
+// stash list` in workDir, not destroyed outright). Returns an error if the
+// run never saved a rollback record, i.e. it ran under RollbackNever or
+// under a config.OutputDir other than the one passed here.
+func Rollback(outputDir, runID string) (*RollbackRecord, error) {
+	rr, err := loadRollbackRecord(outputDir, runID)
+	if err != nil {
+		return nil, err
+	}
+	if rr == nil {
+		return nil, fmt.Errorf("no rollback record for run %s under %s (did it run with --rollback?)", runID, outputDir)
+	}
+	if err := StashAndResetToHEAD(rr.WorkDir, rr.PreRunHEAD); err != nil {
+		return rr, fmt.Errorf("resetting %s to %s: %w", rr.WorkDir, rr.PreRunHEAD, err)
+	}
+	return rr, nil
+}
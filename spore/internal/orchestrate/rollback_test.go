@@ -0,0 +1,89 @@
+package orchestrate
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+)
+
+func TestSaveLoadRollbackRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	rr := RollbackRecord{
+		RunID:      "run-abc",
+		WorkDir:    "/repo",
+		PreRunHEAD: "deadbeef",
+		BounceRefs: []string{"refs/spore/run-abc/bounce-1"},
+	}
+	saveRollbackRecord(dir, rr)
+
+	loaded, err := loadRollbackRecord(dir, "run-abc")
+	if err != nil {
+		t.Fatalf("loadRollbackRecord: %v", err)
+	}
+	if loaded == nil {
+		t.Fatal("expected a rollback record, got nil")
+	}
+	if loaded.WorkDir != "/repo" || loaded.PreRunHEAD != "deadbeef" {
+		t.Errorf("loaded record = %+v, want workdir=/repo prerunhead=deadbeef", loaded)
+	}
+	if len(loaded.BounceRefs) != 1 || loaded.BounceRefs[0] != "refs/spore/run-abc/bounce-1" {
+		t.Errorf("loaded.BounceRefs = %v, want one bounce-1 ref", loaded.BounceRefs)
+	}
+}
+
+func TestLoadRollbackRecord_NotFound(t *testing.T) {
+	dir := t.TempDir()
+
+	rr, err := loadRollbackRecord(dir, "no-such-run")
+	if err != nil {
+		t.Fatalf("loadRollbackRecord for missing run should not error, got: %v", err)
+	}
+	if rr != nil {
+		t.Errorf("expected nil record for missing run, got %+v", rr)
+	}
+}
+
+func TestRollback(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git not in PATH")
+	}
+
+	repoDir := initTestRepo(t)
+	preRunHEAD, err := gitOutput(repoDir, "rev-parse", "HEAD")
+	if err != nil {
+		t.Fatalf("rev-parse HEAD: %v", err)
+	}
+
+	outputDir := t.TempDir()
+	saveRollbackRecord(outputDir, RollbackRecord{RunID: "run-1", WorkDir: repoDir, PreRunHEAD: preRunHEAD})
+
+	if err := os.WriteFile(filepath.Join(repoDir, "base.txt"), []byte("broken\n"), 0644); err != nil {
+		t.Fatalf("modifying base.txt: %v", err)
+	}
+
+	rr, err := Rollback(outputDir, "run-1")
+	if err != nil {
+		t.Fatalf("Rollback: %v", err)
+	}
+	if rr.WorkDir != repoDir {
+		t.Errorf("Rollback WorkDir = %q, want %q", rr.WorkDir, repoDir)
+	}
+
+	base, err := os.ReadFile(filepath.Join(repoDir, "base.txt"))
+	if err != nil {
+		t.Fatalf("reading base.txt after Rollback: %v", err)
+	}
+	if string(base) != "base\n" {
+		t.Errorf("base.txt after Rollback = %q, want %q", base, "base\n")
+	}
+}
+
+func TestRollback_NoRecord(t *testing.T) {
+	dir := t.TempDir()
+
+	if _, err := Rollback(dir, "no-such-run"); err == nil {
+		t.Error("expected an error rolling back a run with no saved record")
+	}
+}
@@ -0,0 +1,313 @@
+package orchestrate
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"os"
+	"os/exec"
+	"os/signal"
+	"path/filepath"
+	"strconv"
+	"syscall"
+	"time"
+)
+
+// ShimSubcommand is the hidden CLI subcommand that re-execs this binary as a
+// mycelica-shim: `<exe> <ShimSubcommand> <run-id>` calls RunShim(run-id).
+const ShimSubcommand = "__claude-shim"
+
+// shimDialTimeout bounds how long spawnShimmed waits for a freshly-started
+// shim to start listening on its socket.
+const shimDialTimeout = 10 * time.Second
+
+func shimRunDir(runID string) string {
+	return filepath.Join("/tmp/spore-orchestrator", runID)
+}
+
+func shimSocketPath(runID string) string { return filepath.Join(shimRunDir(runID), "stream.sock") }
+func shimExitPath(runID string) string   { return filepath.Join(shimRunDir(runID), "exit.json") }
+func shimConfigPath(runID string) string { return filepath.Join(shimRunDir(runID), "config.json") }
+func shimPIDPath(runID string) string    { return filepath.Join(shimRunDir(runID), "shim.pid") }
+
+// spawnShimmed starts config.RunID under a detached mycelica-shim process
+// instead of execing claude directly, so the run survives the orchestrator
+// restarting or crashing: the shim owns Claude's stdio, fans its stream-json
+// out over a Unix socket any number of orchestrator instances can dial (see
+// AttachRun), and persists exit.json on completion.
+func spawnShimmed(ctx context.Context, config ClaudeConfig) (*ClaudeHandle, error) {
+	if config.RunID == "" {
+		return nil, fmt.Errorf("spawning shimmed claude: config.RunID is required")
+	}
+
+	runDir := shimRunDir(config.RunID)
+	if err := os.MkdirAll(runDir, 0755); err != nil {
+		return nil, fmt.Errorf("creating shim run dir: %w", err)
+	}
+
+	configData, err := json.Marshal(config)
+	if err != nil {
+		return nil, fmt.Errorf("marshaling shim config: %w", err)
+	}
+	if err := os.WriteFile(shimConfigPath(config.RunID), configData, 0600); err != nil {
+		return nil, fmt.Errorf("writing shim config: %w", err)
+	}
+
+	exe, err := os.Executable()
+	if err != nil {
+		return nil, fmt.Errorf("resolving own executable for shim re-exec: %w", err)
+	}
+
+	cmd := exec.Command(exe, ShimSubcommand, config.RunID)
+	cmd.Env = filterClaudeEnv(os.Environ())
+	// Setsid detaches the shim into its own session so the orchestrator
+	// exiting doesn't take it down and init inherits it if reparented. Go has
+	// no raw fork(2), so this is the re-exec equivalent of containerd-shim's
+	// double fork.
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("starting shim: %w", err)
+	}
+	shimProc := cmd.Process
+	// The shim has no stderr buffer in this process (it owns Claude's stdio
+	// itself, in its own address space); track it anyway so WaitForDeath
+	// still signals it on shutdown, same as a direct claude child.
+	deregister := RegisterProcess(shimProc, nil, "shim:"+config.RunID)
+
+	conn, err := dialShimSocket(config.RunID, shimDialTimeout)
+	if err != nil {
+		return nil, fmt.Errorf("attaching to newly-started shim: %w", err)
+	}
+
+	handle := &ClaudeHandle{
+		broadcaster: NewStreamBroadcaster(defaultBroadcastBacklogBytes),
+		done:        make(chan struct{}),
+	}
+
+	firstOutput := make(chan struct{}, 1)
+	decodeDone := make(chan struct{})
+	var finalResult *ClaudeResult
+	go func() {
+		defer close(decodeDone)
+		defer conn.Close()
+		dec := json.NewDecoder(conn)
+		for {
+			var evt StreamEvent
+			if err := dec.Decode(&evt); err != nil {
+				return
+			}
+			select {
+			case firstOutput <- struct{}{}:
+			default:
+			}
+			if evt.Type == EventResult {
+				finalResult = evt.Result
+			}
+			handle.broadcaster.Publish(evt)
+		}
+	}()
+
+	startupTimeout := 90 * time.Second
+	normalSeconds := config.MaxTurns * 120
+	if normalSeconds < 600 {
+		normalSeconds = 600
+	}
+	normalTimeout := time.Duration(normalSeconds) * time.Second
+	if config.Timeout > 0 {
+		normalTimeout = config.Timeout
+	}
+
+	watchdogDone := make(chan struct{})
+	go func() {
+		defer close(watchdogDone)
+		watchdog(shimProc, firstOutput, startupTimeout, normalTimeout)
+	}()
+
+	go func() {
+		defer close(handle.done)
+		defer handle.broadcaster.Close()
+		defer deregister()
+		<-decodeDone
+		<-watchdogDone
+
+		result := finalResult
+		if result == nil {
+			result = readShimExit(config.RunID)
+		}
+
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			handle.result, handle.err = result, ctxErr
+			return
+		}
+		handle.result, handle.err = result, nil
+	}()
+
+	// ctx cancellation signals the shim, not Claude directly -- the shim's
+	// own signal handler (installed in RunShim) forwards the escalation to
+	// its Claude child via killProcess.
+	go func() {
+		select {
+		case <-ctx.Done():
+			killProcess(shimProc)
+		case <-handle.done:
+		}
+	}()
+
+	return handle, nil
+}
+
+// dialShimSocket retries dialing a freshly-started shim's socket until it
+// starts listening or timeout elapses.
+func dialShimSocket(runID string, timeout time.Duration) (net.Conn, error) {
+	deadline := time.Now().Add(timeout)
+	var lastErr error
+	for time.Now().Before(deadline) {
+		conn, err := net.Dial("unix", shimSocketPath(runID))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+		time.Sleep(50 * time.Millisecond)
+	}
+	return nil, lastErr
+}
+
+// readShimExit reads and parses a run's persisted exit.json, falling back to
+// an ExitCode: -1 placeholder if it's missing or unparseable (e.g. the shim
+// itself was killed before it could write one).
+func readShimExit(runID string) *ClaudeResult {
+	result := &ClaudeResult{}
+	data, err := os.ReadFile(shimExitPath(runID))
+	if err != nil {
+		result.ExitCode = -1
+		return result
+	}
+	if err := json.Unmarshal(data, result); err != nil {
+		return &ClaudeResult{ExitCode: -1}
+	}
+	return result
+}
+
+// AttachRun reattaches to a Detached run by RunID, for an orchestrator
+// instance that didn't itself start it (e.g. after a restart). If the run
+// has already finished, its persisted exit.json is returned immediately with
+// a nil channel. Otherwise the returned channel streams the run's
+// StreamEvents live (including its eventual EventResult) until the shim
+// closes the connection, at which point the channel is closed.
+func AttachRun(runID string) (*ClaudeResult, <-chan StreamEvent, error) {
+	if data, err := os.ReadFile(shimExitPath(runID)); err == nil {
+		result := &ClaudeResult{}
+		if err := json.Unmarshal(data, result); err != nil {
+			return nil, nil, fmt.Errorf("parsing exit.json for run %s: %w", runID, err)
+		}
+		return result, nil, nil
+	}
+
+	conn, err := net.Dial("unix", shimSocketPath(runID))
+	if err != nil {
+		return nil, nil, fmt.Errorf("attaching to run %s: %w", runID, err)
+	}
+
+	ch := make(chan StreamEvent, 16)
+	go func() {
+		defer close(ch)
+		defer conn.Close()
+		dec := json.NewDecoder(conn)
+		for {
+			var evt StreamEvent
+			if err := dec.Decode(&evt); err != nil {
+				return
+			}
+			ch <- evt
+		}
+	}()
+	return nil, ch, nil
+}
+
+// RunShim is the mycelica-shim entry point: it reads the ClaudeConfig that
+// spawnShimmed wrote to shimConfigPath(runID), starts the real Claude
+// subprocess, fans its stream-json out to every orchestrator that dials
+// shimSocketPath(runID) (including ones that attach after the run started),
+// and persists exit.json for late/reconnecting callers once Claude exits.
+// It forwards SIGTERM/SIGKILL to the Claude child via killProcess so the
+// orchestrator can stop a run by signaling the shim instead of reaching past
+// it. RunShim blocks until the run is over.
+func RunShim(runID string) error {
+	configData, err := os.ReadFile(shimConfigPath(runID))
+	if err != nil {
+		return fmt.Errorf("reading shim config: %w", err)
+	}
+	var config ClaudeConfig
+	if err := json.Unmarshal(configData, &config); err != nil {
+		return fmt.Errorf("parsing shim config: %w", err)
+	}
+
+	if err := os.WriteFile(shimPIDPath(runID), []byte(strconv.Itoa(os.Getpid())), 0644); err != nil {
+		return fmt.Errorf("writing shim pid: %w", err)
+	}
+	defer os.Remove(shimPIDPath(runID))
+
+	_ = os.Remove(shimSocketPath(runID)) // stale socket from a crashed prior shim
+	listener, err := net.Listen("unix", shimSocketPath(runID))
+	if err != nil {
+		return fmt.Errorf("listening on shim socket: %w", err)
+	}
+	defer listener.Close()
+	defer os.Remove(shimSocketPath(runID))
+
+	broadcaster := NewStreamBroadcaster(defaultBroadcastBacklogBytes)
+	defer broadcaster.Close()
+
+	// The shim's own ctx is never cancelled by anything but its own exit;
+	// timeouts and cancellation are the orchestrator's concern (it signals
+	// the shim via killProcess, below, instead).
+	cp, err := startClaudeSubprocess(context.Background(), config, broadcaster)
+	if err != nil {
+		return fmt.Errorf("starting claude under shim: %w", err)
+	}
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGTERM, syscall.SIGINT)
+	go func() {
+		if _, ok := <-sigCh; ok {
+			killProcess(cp.proc)
+		}
+	}()
+
+	go acceptShimConnections(listener, broadcaster)
+
+	result, _ := cp.wait() // the shim's own ctx can't be cancelled; ignore the ctx-error branch
+
+	exitData, err := json.MarshalIndent(result, "", "  ")
+	if err == nil {
+		_ = os.WriteFile(shimExitPath(runID), exitData, 0644)
+	}
+	signal.Stop(sigCh)
+	close(sigCh)
+	return nil
+}
+
+// acceptShimConnections accepts socket connections from orchestrator
+// instances and tees broadcaster's events to each as JSON lines until the
+// listener (and so the shim) shuts down.
+func acceptShimConnections(listener net.Listener, broadcaster *StreamBroadcaster) {
+	for {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		go func(c net.Conn) {
+			defer c.Close()
+			ch := make(chan StreamEvent, 16)
+			detach := broadcaster.AttachListener(ch)
+			defer detach()
+			enc := json.NewEncoder(c)
+			for evt := range ch {
+				if err := enc.Encode(evt); err != nil {
+					return
+				}
+			}
+		}(conn)
+	}
+}
@@ -0,0 +1,91 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// withShimRunDir points the /tmp/spore-orchestrator base used by shimRunDir
+// at a temp directory for the duration of the test by monkeypatching the
+// run's files directly -- shimRunDir itself is a pure path join, so tests
+// exercise the functions that read/write under it by writing to the real
+// path and cleaning up, mirroring WriteMCPConfig's own tests.
+func TestShimPaths_NestUnderRunDir(t *testing.T) {
+	runID := "test-run-shimpaths"
+	dir := shimRunDir(runID)
+	if filepath.Dir(dir) != "/tmp/spore-orchestrator" {
+		t.Errorf("shimRunDir should nest under /tmp/spore-orchestrator, got %s", dir)
+	}
+	for _, p := range []string{shimSocketPath(runID), shimExitPath(runID), shimConfigPath(runID), shimPIDPath(runID)} {
+		if filepath.Dir(p) != dir {
+			t.Errorf("expected %s to live directly under %s", p, dir)
+		}
+	}
+}
+
+func TestReadShimExit_MissingFileReturnsPlaceholder(t *testing.T) {
+	result := readShimExit("no-such-run-" + t.Name())
+	if result.ExitCode != -1 {
+		t.Errorf("expected ExitCode -1 for a missing exit.json, got %d", result.ExitCode)
+	}
+}
+
+func TestReadShimExit_RoundTripsPersistedResult(t *testing.T) {
+	runID := "test-run-" + t.Name()
+	dir := shimRunDir(runID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	want := &ClaudeResult{ExitCode: 0, SessionID: "sess-123", CostUSD: 1.23, NumTurns: 4}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(shimExitPath(runID), data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	got := readShimExit(runID)
+	if got.SessionID != want.SessionID || got.CostUSD != want.CostUSD || got.NumTurns != want.NumTurns {
+		t.Errorf("readShimExit = %+v, want %+v", got, want)
+	}
+}
+
+func TestAttachRun_FinishedRunReturnsResultWithoutDialing(t *testing.T) {
+	runID := "test-run-" + t.Name()
+	dir := shimRunDir(runID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatalf("MkdirAll: %v", err)
+	}
+	defer os.RemoveAll(dir)
+
+	want := &ClaudeResult{ExitCode: 0, SessionID: "sess-456"}
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("Marshal: %v", err)
+	}
+	if err := os.WriteFile(shimExitPath(runID), data, 0644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	result, events, err := AttachRun(runID)
+	if err != nil {
+		t.Fatalf("AttachRun: %v", err)
+	}
+	if events != nil {
+		t.Error("expected a nil events channel for an already-finished run")
+	}
+	if result == nil || result.SessionID != want.SessionID {
+		t.Errorf("AttachRun result = %+v, want %+v", result, want)
+	}
+}
+
+func TestAttachRun_NoRunReturnsError(t *testing.T) {
+	if _, _, err := AttachRun("no-such-run-" + t.Name()); err == nil {
+		t.Error("expected an error attaching to a run with neither an exit.json nor a live socket")
+	}
+}
@@ -0,0 +1,197 @@
+package orchestrate
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+)
+
+// trackedProcess is one Claude (or shim) subprocess registered with a
+// ShutdownCoordinator, together with the capped stderr buffer to flush to
+// disk for post-mortem once it's killed.
+type trackedProcess struct {
+	proc   *os.Process
+	stderr *cappedBuffer // may be nil, e.g. a tracked shim has no local buffer
+	label  string
+}
+
+// ShutdownCoordinator centralizes killing every Claude subprocess a process
+// has started, replacing each caller's own fire-and-forget killProcess call
+// with one coordinated drain. The zero value is ready to use; see also the
+// package-level RegisterProcess, which registers against a shared default
+// coordinator for SpawnClaude-family callers.
+type ShutdownCoordinator struct {
+	mu        sync.Mutex
+	processes map[int]*trackedProcess
+	nextID    int
+
+	started  bool
+	done     chan struct{}
+	err      error
+	escalate chan struct{}
+}
+
+// NewShutdownCoordinator returns a ready-to-use ShutdownCoordinator.
+func NewShutdownCoordinator() *ShutdownCoordinator {
+	return &ShutdownCoordinator{processes: make(map[int]*trackedProcess)}
+}
+
+// defaultShutdownCoordinator is the coordinator SpawnClaude-family functions
+// register with automatically; see RegisterProcess and WaitForDeath.
+var defaultShutdownCoordinator = NewShutdownCoordinator()
+
+// RegisterProcess tracks proc, and its capped stderr buffer (nil if none),
+// under the package-level default ShutdownCoordinator so a WaitForDeath call
+// on it will drain proc on shutdown. Call the returned deregister func once
+// proc has exited on its own, so WaitForDeath doesn't try to kill a process
+// that's already gone.
+func RegisterProcess(proc *os.Process, stderr *cappedBuffer, label string) (deregister func()) {
+	return defaultShutdownCoordinator.register(proc, stderr, label)
+}
+
+// WaitForDeath blocks until one of signals arrives on the default
+// ShutdownCoordinator, then drains it. See (*ShutdownCoordinator).WaitForDeath.
+func WaitForDeath(signals []os.Signal, timeout time.Duration, closers ...io.Closer) error {
+	return defaultShutdownCoordinator.WaitForDeath(signals, timeout, closers...)
+}
+
+func (c *ShutdownCoordinator) register(proc *os.Process, stderr *cappedBuffer, label string) func() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	id := c.nextID
+	c.nextID++
+	c.processes[id] = &trackedProcess{proc: proc, stderr: stderr, label: label}
+	return func() {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		delete(c.processes, id)
+	}
+}
+
+// WaitForDeath blocks until one of signals arrives, then kills every process
+// currently registered: SIGTERM to all of them in parallel, each given up to
+// timeout for killProcess's own SIGTERM-then-3s-then-SIGKILL escalation to
+// finish before WaitForDeath itself steps in with SIGKILL. Each tracked
+// process's capped stderr buffer is then flushed to disk for post-mortem,
+// and finally closers are closed in order (DB, MCP config cleanup, shim
+// sockets, etc). Safe to call more than once -- later calls block on and
+// return the first call's result rather than draining twice. A second
+// signal arriving while draining escalates every tracked process straight to
+// SIGKILL instead of waiting out the rest of timeout.
+func (c *ShutdownCoordinator) WaitForDeath(signals []os.Signal, timeout time.Duration, closers ...io.Closer) error {
+	sigCh := make(chan os.Signal, 2)
+	signal.Notify(sigCh, signals...)
+	defer signal.Stop(sigCh)
+
+	<-sigCh
+
+	c.mu.Lock()
+	if c.started {
+		done := c.done
+		c.mu.Unlock()
+		<-done
+		return c.err
+	}
+	c.started = true
+	c.done = make(chan struct{})
+	c.escalate = make(chan struct{})
+	c.mu.Unlock()
+
+	go func() {
+		select {
+		case <-sigCh:
+			close(c.escalate)
+		case <-c.done:
+		}
+	}()
+
+	err := c.drain(timeout, closers)
+
+	c.mu.Lock()
+	c.err = err
+	c.mu.Unlock()
+	close(c.done)
+
+	return err
+}
+
+func (c *ShutdownCoordinator) drain(timeout time.Duration, closers []io.Closer) error {
+	c.mu.Lock()
+	tracked := make([]*trackedProcess, 0, len(c.processes))
+	for _, tp := range c.processes {
+		tracked = append(tracked, tp)
+	}
+	c.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for _, tp := range tracked {
+		wg.Add(1)
+		go func(tp *trackedProcess) {
+			defer wg.Done()
+			c.killWithEscalation(tp.proc, timeout)
+		}(tp)
+	}
+	wg.Wait()
+
+	for _, tp := range tracked {
+		flushStderrToDisk(tp.label, tp.stderr)
+	}
+
+	var errs []error
+	for _, closer := range closers {
+		if err := closer.Close(); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// killWithEscalation sends proc the usual killProcess SIGTERM/3s/SIGKILL
+// escalation, but steps in with an immediate SIGKILL itself if timeout
+// elapses first or c.escalate is closed by a second shutdown signal.
+func (c *ShutdownCoordinator) killWithEscalation(proc *os.Process, timeout time.Duration) {
+	done := make(chan struct{})
+	go func() {
+		killProcess(proc)
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-c.escalate:
+		_ = proc.Signal(syscall.SIGKILL)
+		<-done
+	case <-time.After(timeout):
+		_ = proc.Signal(syscall.SIGKILL)
+		<-done
+	}
+}
+
+// flushStderrToDisk persists a killed process's capped stderr for post-mortem
+// debugging. Silently does nothing for a nil/empty buffer or an unwritable
+// disk -- this is best-effort diagnostics, not load-bearing.
+func flushStderrToDisk(label string, stderr *cappedBuffer) {
+	if stderr == nil {
+		return
+	}
+	content := stderr.String()
+	if content == "" {
+		return
+	}
+
+	dir := "/tmp/spore-orchestrator/shutdown-stderr"
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return
+	}
+
+	safeLabel := strings.NewReplacer("/", "_", " ", "_").Replace(label)
+	path := filepath.Join(dir, fmt.Sprintf("%s-%d.log", safeLabel, time.Now().UnixNano()))
+	_ = os.WriteFile(path, []byte(content), 0644)
+}
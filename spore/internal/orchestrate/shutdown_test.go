@@ -0,0 +1,119 @@
+package orchestrate
+
+import (
+	"errors"
+	"io"
+	"os"
+	"os/exec"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// spawnSleeper starts a short-lived `sleep` child so tests can exercise
+// ShutdownCoordinator against a real *os.Process without depending on the
+// claude binary.
+func spawnSleeper(t *testing.T, seconds string) *os.Process {
+	t.Helper()
+	cmd := exec.Command("sleep", seconds)
+	if err := cmd.Start(); err != nil {
+		t.Skipf("skipping: could not start sleep: %v", err)
+	}
+	t.Cleanup(func() { _ = cmd.Process.Kill(); _, _ = cmd.Process.Wait() })
+	return cmd.Process
+}
+
+type fakeCloser struct{ closed bool }
+
+func (f *fakeCloser) Close() error { f.closed = true; return nil }
+
+type erroringCloser struct{ err error }
+
+func (e *erroringCloser) Close() error { return e.err }
+
+func TestShutdownCoordinator_WaitForDeath_KillsTrackedProcessAndRunsClosers(t *testing.T) {
+	c := NewShutdownCoordinator()
+	proc := spawnSleeper(t, "30")
+	deregister := c.register(proc, nil, "test")
+	defer deregister()
+
+	closer := &fakeCloser{}
+	done := make(chan error, 1)
+	go func() { done <- c.WaitForDeath([]os.Signal{syscall.SIGUSR1}, time.Second, closer) }()
+
+	time.Sleep(20 * time.Millisecond) // let signal.Notify register
+	if err := syscall.Kill(os.Getpid(), syscall.SIGUSR1); err != nil {
+		t.Fatalf("sending trigger signal: %v", err)
+	}
+
+	select {
+	case err := <-done:
+		if err != nil {
+			t.Errorf("WaitForDeath returned %v, want nil", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForDeath did not return")
+	}
+
+	if !closer.closed {
+		t.Error("expected the closer to run after draining")
+	}
+	if err := proc.Signal(syscall.Signal(0)); err == nil {
+		t.Error("expected the tracked process to have been killed")
+	}
+}
+
+func TestShutdownCoordinator_WaitForDeath_SafeToCallTwice(t *testing.T) {
+	c := NewShutdownCoordinator()
+	results := make(chan error, 2)
+	for i := 0; i < 2; i++ {
+		go func() { results <- c.WaitForDeath([]os.Signal{syscall.SIGUSR2}, time.Second) }()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	_ = syscall.Kill(os.Getpid(), syscall.SIGUSR2)
+
+	for i := 0; i < 2; i++ {
+		select {
+		case err := <-results:
+			if err != nil {
+				t.Errorf("call %d returned %v, want nil", i, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatal("a concurrent WaitForDeath call did not return")
+		}
+	}
+}
+
+func TestShutdownCoordinator_WaitForDeath_PropagatesCloserErrors(t *testing.T) {
+	c := NewShutdownCoordinator()
+	wantErr := errors.New("boom")
+	done := make(chan error, 1)
+	go func() {
+		done <- c.WaitForDeath([]os.Signal{syscall.SIGUSR1}, time.Second, &erroringCloser{err: wantErr})
+	}()
+
+	time.Sleep(20 * time.Millisecond)
+	_ = syscall.Kill(os.Getpid(), syscall.SIGUSR1)
+
+	select {
+	case err := <-done:
+		if !errors.Is(err, wantErr) {
+			t.Errorf("WaitForDeath error = %v, want it to wrap %v", err, wantErr)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("WaitForDeath did not return")
+	}
+}
+
+func TestFlushStderrToDisk_NilAndEmptyAreNoOps(t *testing.T) {
+	flushStderrToDisk("nil-buffer", nil)
+
+	var empty cappedBuffer
+	empty.limit = 100
+	flushStderrToDisk("empty-buffer", &empty)
+	// Nothing to assert beyond "doesn't panic" -- there's no buffer content
+	// to have written anywhere.
+}
+
+var _ io.Closer = (*fakeCloser)(nil)
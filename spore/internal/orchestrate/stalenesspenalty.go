@@ -0,0 +1,70 @@
+package orchestrate
+
+import "mycelica/spore/internal/graph"
+
+// staleSummaryExtraPenalty is an additional flat discount applied on top of
+// staleNodePenalty for a StaleSummary: its content isn't just old, it's
+// known to be outdated relative to what it summarizes, so it should be
+// trusted less than a merely-old node.
+const staleSummaryExtraPenalty = 0.5
+
+// staleNodePenalty returns a multiplicative relevance penalty in (0, 1] for
+// a node whose age has progressed driftDays past staleDays: right at the
+// threshold the penalty halves relevance, and it keeps shrinking as drift
+// grows. A non-positive staleDays disables the penalty (returns 1).
+func staleNodePenalty(driftDays, staleDays int64) float64 {
+	if staleDays <= 0 {
+		return 1
+	}
+	return 1 / (1 + float64(driftDays)/float64(staleDays))
+}
+
+// staleSummaryPenalty applies staleNodePenalty against DriftDays, then
+// staleSummaryExtraPenalty.
+func staleSummaryPenalty(driftDays, staleDays int64) float64 {
+	return staleNodePenalty(driftDays, staleDays) * staleSummaryExtraPenalty
+}
+
+// stalenessIndex indexes a graph.StalenessReport by node ID for O(1) penalty
+// lookups while scoring anchors and context rows. A nil *stalenessIndex
+// (from a nil report) is a valid no-op index.
+type stalenessIndex struct {
+	staleNodes     map[string]graph.StaleNode
+	staleSummaries map[string]graph.StaleSummary // keyed by SummaryNodeID
+}
+
+// newStalenessIndex builds a stalenessIndex from report, or returns nil if
+// report is nil.
+func newStalenessIndex(report *graph.StalenessReport) *stalenessIndex {
+	if report == nil {
+		return nil
+	}
+	idx := &stalenessIndex{
+		staleNodes:     make(map[string]graph.StaleNode, len(report.StaleNodes)),
+		staleSummaries: make(map[string]graph.StaleSummary, len(report.StaleSummaries)),
+	}
+	for _, n := range report.StaleNodes {
+		idx.staleNodes[n.ID] = n
+	}
+	for _, s := range report.StaleSummaries {
+		idx.staleSummaries[s.SummaryNodeID] = s
+	}
+	return idx
+}
+
+// penalty returns the multiplicative relevance penalty for nodeID and
+// whether it's considered stale at all. A StaleSummary match takes priority
+// over a StaleNode match, since drifted-summary staleness is the more
+// specific (and more severe) signal.
+func (idx *stalenessIndex) penalty(nodeID string, staleDays int64) (factor float64, stale bool) {
+	if idx == nil {
+		return 1, false
+	}
+	if s, ok := idx.staleSummaries[nodeID]; ok {
+		return staleSummaryPenalty(s.DriftDays, staleDays), true
+	}
+	if n, ok := idx.staleNodes[nodeID]; ok {
+		return staleNodePenalty(n.DaysSinceUpdate, staleDays), true
+	}
+	return 1, false
+}
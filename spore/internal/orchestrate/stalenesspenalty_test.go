@@ -0,0 +1,76 @@
+package orchestrate
+
+import (
+	"testing"
+
+	"mycelica/spore/internal/graph"
+)
+
+func TestStaleNodePenalty_AtThresholdHalvesRelevance(t *testing.T) {
+	got := staleNodePenalty(30, 30)
+	if got != 0.5 {
+		t.Errorf("staleNodePenalty(30, 30) = %v, want 0.5", got)
+	}
+}
+
+func TestStaleNodePenalty_WorsensWithMoreDrift(t *testing.T) {
+	at := staleNodePenalty(30, 30)
+	past := staleNodePenalty(90, 30)
+	if past >= at {
+		t.Errorf("expected more drift to produce a smaller penalty factor, got at=%v past=%v", at, past)
+	}
+}
+
+func TestStaleNodePenalty_DisabledWithoutStaleDays(t *testing.T) {
+	if got := staleNodePenalty(1000, 0); got != 1 {
+		t.Errorf("staleNodePenalty with staleDays=0 = %v, want 1 (disabled)", got)
+	}
+}
+
+func TestStaleSummaryPenalty_StricterThanStaleNodePenalty(t *testing.T) {
+	node := staleNodePenalty(30, 30)
+	summary := staleSummaryPenalty(30, 30)
+	if summary >= node {
+		t.Errorf("expected a stale summary to be penalized more than an equally-old stale node, got node=%v summary=%v", node, summary)
+	}
+}
+
+func TestStalenessIndex_NilReportIsNoop(t *testing.T) {
+	idx := newStalenessIndex(nil)
+	factor, stale := idx.penalty("anything", 30)
+	if factor != 1 || stale {
+		t.Errorf("expected a nil index to never flag staleness, got factor=%v stale=%v", factor, stale)
+	}
+}
+
+func TestStalenessIndex_SummaryTakesPriorityOverNode(t *testing.T) {
+	report := &graph.StalenessReport{
+		StaleNodes: []graph.StaleNode{
+			{ID: "n1", DaysSinceUpdate: 30},
+		},
+		StaleSummaries: []graph.StaleSummary{
+			{SummaryNodeID: "n1", DriftDays: 30},
+		},
+	}
+	idx := newStalenessIndex(report)
+
+	factor, stale := idx.penalty("n1", 30)
+	if !stale {
+		t.Fatal("expected n1 to be flagged stale")
+	}
+	if want := staleSummaryPenalty(30, 30); factor != want {
+		t.Errorf("expected the stricter summary penalty to win, got %v, want %v", factor, want)
+	}
+}
+
+func TestStalenessIndex_UnrelatedNodeIsUnaffected(t *testing.T) {
+	report := &graph.StalenessReport{
+		StaleNodes: []graph.StaleNode{{ID: "n1", DaysSinceUpdate: 30}},
+	}
+	idx := newStalenessIndex(report)
+
+	factor, stale := idx.penalty("n2", 30)
+	if factor != 1 || stale {
+		t.Errorf("expected an unrelated node to be unaffected, got factor=%v stale=%v", factor, stale)
+	}
+}
@@ -0,0 +1,183 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"sync"
+	"time"
+)
+
+// StreamEventType identifies the kind of typed event a StreamBroadcaster
+// publishes, so subscribers (TUI panels, a web viewer, a log tee, an audit
+// sink) can switch on Type instead of re-parsing stream-json themselves.
+type StreamEventType string
+
+const (
+	EventAssistantText StreamEventType = "assistant_text_delta"
+	EventThinking      StreamEventType = "thinking"
+	EventToolUse       StreamEventType = "tool_use"
+	EventMCPStatus     StreamEventType = "mcp_status"
+	EventResult        StreamEventType = "result"
+)
+
+// StreamEvent is one typed, already-parsed unit of a Claude subprocess's
+// stream-json output. Only the fields relevant to Type are populated.
+type StreamEvent struct {
+	Type StreamEventType `json:"type"`
+
+	Text string `json:"text,omitempty"` // EventAssistantText, EventThinking
+
+	ToolName  string          `json:"tool_name,omitempty"`  // EventToolUse
+	ToolInput json.RawMessage `json:"tool_input,omitempty"` // EventToolUse
+
+	MCPStatus string `json:"mcp_status,omitempty"` // EventMCPStatus
+
+	Result *ClaudeResult `json:"result,omitempty"` // EventResult
+}
+
+// listenerSendTimeout bounds how long Publish waits on one slow listener
+// before dropping it, so a stalled consumer can't stall the parser.
+const listenerSendTimeout = 2 * time.Second
+
+// defaultBroadcastBacklogBytes is the default backlog budget (see
+// StreamBroadcaster.backlog): roughly the last 16KB of assistant text and
+// thinking blocks, replayed to a listener that attaches mid-run.
+const defaultBroadcastBacklogBytes = 16 * 1024
+
+// StreamBroadcaster fans out one Claude subprocess's StreamEvents to any
+// number of concurrent listeners. It keeps a byte-bounded ring buffer of
+// recent assistant-text/thinking events so a listener attaching mid-run
+// isn't starting blind, then streams every subsequent event live. A nil
+// *StreamBroadcaster is valid and a no-op, mirroring EnforcementPolicy's
+// nil-safe Resolve.
+type StreamBroadcaster struct {
+	mu         sync.Mutex
+	backlogMax int
+	backlogLen int
+	backlog    []StreamEvent
+	listeners  map[int]chan StreamEvent
+	nextID     int
+	closed     bool
+}
+
+// NewStreamBroadcaster creates a StreamBroadcaster whose backlog replay is
+// capped at backlogBytes of assistant-text/thinking content. backlogBytes
+// <= 0 uses defaultBroadcastBacklogBytes.
+func NewStreamBroadcaster(backlogBytes int) *StreamBroadcaster {
+	if backlogBytes <= 0 {
+		backlogBytes = defaultBroadcastBacklogBytes
+	}
+	return &StreamBroadcaster{
+		backlogMax: backlogBytes,
+		listeners:  make(map[int]chan StreamEvent),
+	}
+}
+
+// Publish fans evt out to every attached listener, recording it in the
+// backlog first if it's assistant text or thinking. Delivery to each
+// listener runs concurrently and is bounded by listenerSendTimeout, so one
+// slow listener can delay but never indefinitely block Publish or the other
+// listeners; a listener that times out is detached and its channel closed.
+func (b *StreamBroadcaster) Publish(evt StreamEvent) {
+	if b == nil {
+		return
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	if evt.Type == EventAssistantText || evt.Type == EventThinking {
+		b.backlog = append(b.backlog, evt)
+		b.backlogLen += len(evt.Text)
+		for b.backlogLen > b.backlogMax && len(b.backlog) > 0 {
+			b.backlogLen -= len(b.backlog[0].Text)
+			b.backlog = b.backlog[1:]
+		}
+	}
+	ids := make([]int, 0, len(b.listeners))
+	chans := make([]chan StreamEvent, 0, len(b.listeners))
+	for id, ch := range b.listeners {
+		ids = append(ids, id)
+		chans = append(chans, ch)
+	}
+	b.mu.Unlock()
+
+	var wg sync.WaitGroup
+	for i, ch := range chans {
+		wg.Add(1)
+		go func(id int, ch chan StreamEvent) {
+			defer wg.Done()
+			select {
+			case ch <- evt:
+			case <-time.After(listenerSendTimeout):
+				b.detach(id)
+			}
+		}(ids[i], ch)
+	}
+	wg.Wait()
+}
+
+// AttachListener registers ch to receive every subsequent Publish, first
+// replaying the current backlog so ch sees recent context immediately. If b
+// is nil or already closed, ch is closed right away and detach is a no-op.
+func (b *StreamBroadcaster) AttachListener(ch chan StreamEvent) (detach func()) {
+	if b == nil {
+		close(ch)
+		return func() {}
+	}
+
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		close(ch)
+		return func() {}
+	}
+	id := b.nextID
+	b.nextID++
+	b.listeners[id] = ch
+	backlog := append([]StreamEvent(nil), b.backlog...)
+	b.mu.Unlock()
+
+	for _, evt := range backlog {
+		select {
+		case ch <- evt:
+		case <-time.After(listenerSendTimeout):
+		}
+	}
+
+	return func() { b.detach(id) }
+}
+
+func (b *StreamBroadcaster) detach(id int) {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if ch, ok := b.listeners[id]; ok {
+		delete(b.listeners, id)
+		close(ch)
+	}
+}
+
+// Close detaches and closes every listener's channel. Safe to call more than
+// once and on a nil *StreamBroadcaster.
+func (b *StreamBroadcaster) Close() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	if b.closed {
+		b.mu.Unlock()
+		return
+	}
+	b.closed = true
+	listeners := b.listeners
+	b.listeners = make(map[int]chan StreamEvent)
+	b.mu.Unlock()
+
+	for _, ch := range listeners {
+		close(ch)
+	}
+}
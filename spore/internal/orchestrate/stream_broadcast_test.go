@@ -0,0 +1,114 @@
+package orchestrate
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestStreamBroadcaster_ReplaysBacklogOnAttach(t *testing.T) {
+	b := NewStreamBroadcaster(1024)
+	b.Publish(StreamEvent{Type: EventThinking, Text: "thinking about it"})
+	b.Publish(StreamEvent{Type: EventAssistantText, Text: "here's the answer"})
+
+	ch := make(chan StreamEvent, 4)
+	detach := b.AttachListener(ch)
+	defer detach()
+
+	first := <-ch
+	second := <-ch
+	if first.Text != "thinking about it" || second.Text != "here's the answer" {
+		t.Fatalf("expected backlog replay in order, got %+v, %+v", first, second)
+	}
+}
+
+func TestStreamBroadcaster_LiveEventsAfterAttach(t *testing.T) {
+	b := NewStreamBroadcaster(1024)
+	ch := make(chan StreamEvent, 4)
+	detach := b.AttachListener(ch)
+	defer detach()
+
+	b.Publish(StreamEvent{Type: EventToolUse, ToolName: "Bash"})
+	evt := <-ch
+	if evt.Type != EventToolUse || evt.ToolName != "Bash" {
+		t.Errorf("expected a live tool_use event, got %+v", evt)
+	}
+}
+
+func TestStreamBroadcaster_BacklogTrimsToByteBudget(t *testing.T) {
+	b := NewStreamBroadcaster(10)
+	b.Publish(StreamEvent{Type: EventAssistantText, Text: strings.Repeat("a", 8)})
+	b.Publish(StreamEvent{Type: EventAssistantText, Text: strings.Repeat("b", 8)})
+
+	ch := make(chan StreamEvent, 4)
+	detach := b.AttachListener(ch)
+	defer detach()
+
+	evt := <-ch
+	if evt.Text != strings.Repeat("b", 8) {
+		t.Fatalf("expected only the most recent chunk within budget, got %+v", evt)
+	}
+	select {
+	case extra := <-ch:
+		t.Fatalf("expected the trimmed-out first chunk not to replay, got %+v", extra)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
+
+func TestStreamBroadcaster_SlowListenerDroppedOnTimeout(t *testing.T) {
+	b := NewStreamBroadcaster(1024)
+	slow := make(chan StreamEvent) // unbuffered, nobody reads -- forces a timeout
+	b.AttachListener(slow)
+
+	done := make(chan struct{})
+	go func() {
+		b.Publish(StreamEvent{Type: EventResult})
+		close(done)
+	}()
+
+	select {
+	case <-done:
+	case <-time.After(listenerSendTimeout + time.Second):
+		t.Fatal("Publish should return once the slow listener times out, not block forever")
+	}
+
+	if _, ok := <-slow; ok {
+		t.Error("expected the slow listener's channel to be closed after the timeout dropped it")
+	}
+}
+
+func TestStreamBroadcaster_CloseClosesListeners(t *testing.T) {
+	b := NewStreamBroadcaster(1024)
+	ch := make(chan StreamEvent, 1)
+	b.AttachListener(ch)
+	b.Close()
+	b.Close() // must be safe to call twice
+
+	if _, ok := <-ch; ok {
+		t.Error("expected the listener channel to be closed")
+	}
+}
+
+func TestStreamBroadcaster_AttachAfterCloseClosesImmediately(t *testing.T) {
+	b := NewStreamBroadcaster(1024)
+	b.Close()
+
+	ch := make(chan StreamEvent, 1)
+	b.AttachListener(ch)
+	if _, ok := <-ch; ok {
+		t.Error("expected a post-close attach to be closed immediately")
+	}
+}
+
+func TestStreamBroadcaster_NilIsNoOp(t *testing.T) {
+	var b *StreamBroadcaster
+	b.Publish(StreamEvent{Type: EventResult}) // must not panic
+
+	ch := make(chan StreamEvent, 1)
+	detach := b.AttachListener(ch)
+	if _, ok := <-ch; ok {
+		t.Error("expected a nil broadcaster to close the listener immediately")
+	}
+	detach() // must not panic
+	b.Close()
+}
@@ -0,0 +1,256 @@
+package orchestrate
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+)
+
+// SupervisorState is one state in the Supervise restart state machine,
+// modeled on classic process supervisors (systemd, runit, s6).
+type SupervisorState string
+
+const (
+	SuperviseStarting SupervisorState = "starting"
+	SuperviseRunning  SupervisorState = "running"
+	SuperviseBackoff  SupervisorState = "backoff"
+	SuperviseFatal    SupervisorState = "fatal"
+	SuperviseExited   SupervisorState = "exited"
+)
+
+// RestartOn decides, given an attempt's exit code, whether Supervise should
+// restart the process. Construct one with RestartAlways, RestartNever,
+// RestartOnFailure, or RestartOnCodes rather than the zero value, which
+// behaves like RestartOnFailure.
+type RestartOn struct {
+	mode  string // "any", "failure", "never", "codes"
+	codes map[int]bool
+}
+
+// RestartAlways restarts regardless of exit code.
+func RestartAlways() RestartOn { return RestartOn{mode: "any"} }
+
+// RestartNever never restarts; the first exit is terminal.
+func RestartNever() RestartOn { return RestartOn{mode: "never"} }
+
+// RestartOnFailure restarts on any non-zero exit code. This is also the
+// zero-value RestartOn's behavior.
+func RestartOnFailure() RestartOn { return RestartOn{mode: "failure"} }
+
+// RestartOnCodes restarts only when the exit code is one of codes.
+func RestartOnCodes(codes ...int) RestartOn {
+	set := make(map[int]bool, len(codes))
+	for _, c := range codes {
+		set[c] = true
+	}
+	return RestartOn{mode: "codes", codes: set}
+}
+
+func (r RestartOn) shouldRestart(exitCode int) bool {
+	switch r.mode {
+	case "any":
+		return true
+	case "never":
+		return false
+	case "codes":
+		return r.codes[exitCode]
+	default: // "failure", or the zero value
+		return exitCode != 0
+	}
+}
+
+// BackoffPolicy is a classic exponential backoff schedule: attempt 1 waits
+// Initial, each subsequent attempt multiplies the previous wait by
+// Multiplier, capped at Max.
+type BackoffPolicy struct {
+	Initial    time.Duration
+	Max        time.Duration
+	Multiplier float64
+}
+
+// DefaultBackoffPolicy returns a conservative 1s-to-30s doubling schedule.
+func DefaultBackoffPolicy() BackoffPolicy {
+	return BackoffPolicy{Initial: time.Second, Max: 30 * time.Second, Multiplier: 2}
+}
+
+// forAttempt returns the wait before the given 1-indexed restart attempt.
+func (b BackoffPolicy) forAttempt(attempt int) time.Duration {
+	if attempt < 1 {
+		attempt = 1
+	}
+	d := time.Duration(float64(b.Initial) * math.Pow(b.Multiplier, float64(attempt-1)))
+	if b.Max > 0 && d > b.Max {
+		d = b.Max
+	}
+	return d
+}
+
+// SupervisorPolicy controls Supervise's restart behavior.
+type SupervisorPolicy struct {
+	// StartSeconds is the minimum uptime an attempt must reach to count as a
+	// successful start. An attempt that exits before this resets nothing;
+	// one that exits after it resets the retry counter.
+	StartSeconds time.Duration
+
+	// StartRetries is how many consecutive exits-before-StartSeconds are
+	// tolerated before Supervise gives up and transitions to SuperviseFatal.
+	StartRetries int
+
+	// RestartOn decides whether an attempt's exit code warrants a restart at
+	// all, independent of the StartSeconds/StartRetries bookkeeping above.
+	RestartOn RestartOn
+
+	Backoff BackoffPolicy
+}
+
+// SupervisorHandle is a running Supervise state machine. Attempts receives
+// every individual attempt's ClaudeResult as it finishes; Wait blocks for
+// the terminal aggregated result (the attempt that ended supervision,
+// whether by exhausting retries, a non-restartable exit, or ctx
+// cancellation).
+type SupervisorHandle struct {
+	Attempts <-chan *ClaudeResult
+
+	mu    sync.Mutex
+	state SupervisorState
+
+	done   chan struct{}
+	result *ClaudeResult
+	err    error
+}
+
+// State returns the supervisor's current state.
+func (h *SupervisorHandle) State() SupervisorState {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.state
+}
+
+func (h *SupervisorHandle) setState(s SupervisorState) {
+	h.mu.Lock()
+	h.state = s
+	h.mu.Unlock()
+}
+
+// Wait blocks until supervision ends and returns the terminal attempt's
+// result alongside the reason supervision stopped (nil for a clean
+// non-restartable exit or exhausted retries, a ctx error for cancellation).
+func (h *SupervisorHandle) Wait() (*ClaudeResult, error) {
+	<-h.done
+	return h.result, h.err
+}
+
+// spawnSupervisedClaude runs cfg under Supervise when policy is non-nil,
+// blocking for its terminal result the same way SpawnClaude blocks for a
+// single attempt; a nil policy falls straight through to SpawnClaude,
+// preserving the legacy unsupervised behavior. This is the entry point
+// runCoder/runVerifier call instead of SpawnClaude directly, so
+// OrchestrationConfig.Supervisor actually governs the real coder/verifier
+// spawn path rather than sitting unused.
+func spawnSupervisedClaude(ctx context.Context, cfg ClaudeConfig, policy *SupervisorPolicy) (*ClaudeResult, error) {
+	if policy == nil {
+		return SpawnClaude(ctx, cfg)
+	}
+	handle, err := Supervise(ctx, cfg, *policy)
+	if err != nil {
+		return nil, err
+	}
+	return handle.Wait()
+}
+
+// Supervise wraps SpawnClaudeStreaming in a restart state machine: an
+// attempt that exits is restarted according to policy.RestartOn, with
+// exponential backoff between attempts, unless it crashed before
+// policy.StartSeconds elapsed more than policy.StartRetries times in a row
+// (which transitions to SuperviseFatal) or ctx is cancelled (which
+// transitions to SuperviseExited once the in-flight attempt's own ctx-aware
+// kill/wait -- see SpawnClaude -- unwinds it). A resume is only attempted
+// when the prior attempt actually established a Claude session; a
+// first-start failure (no SessionID yet) restarts cold instead of resuming
+// an invalid session.
+func Supervise(ctx context.Context, config ClaudeConfig, policy SupervisorPolicy) (*SupervisorHandle, error) {
+	attempts := make(chan *ClaudeResult, 1)
+	h := &SupervisorHandle{
+		Attempts: attempts,
+		state:    SuperviseStarting,
+		done:     make(chan struct{}),
+	}
+
+	go h.run(ctx, config, policy, attempts)
+	return h, nil
+}
+
+func (h *SupervisorHandle) run(ctx context.Context, config ClaudeConfig, policy SupervisorPolicy, attempts chan<- *ClaudeResult) {
+	defer close(h.done)
+	defer close(attempts)
+
+	resumeID := config.ResumeID
+	retries := 0
+
+	for attempt := 1; ; attempt++ {
+		h.setState(SuperviseStarting)
+
+		attemptConfig := config
+		attemptConfig.ResumeID = resumeID
+
+		start := time.Now()
+		claudeHandle, err := SpawnClaudeStreaming(ctx, attemptConfig)
+		if err != nil {
+			h.setState(SuperviseFatal)
+			h.result, h.err = nil, fmt.Errorf("starting attempt %d: %w", attempt, err)
+			return
+		}
+		h.setState(SuperviseRunning)
+
+		result, waitErr := claudeHandle.Wait()
+		uptime := time.Since(start)
+
+		select {
+		case attempts <- result:
+		default:
+		}
+
+		if waitErr != nil {
+			// ctx was cancelled or timed out; SpawnClaude already killed the
+			// subprocess via exec.CommandContext before returning this.
+			h.setState(SuperviseExited)
+			h.result, h.err = result, waitErr
+			return
+		}
+
+		if !policy.RestartOn.shouldRestart(result.ExitCode) {
+			h.setState(SuperviseExited)
+			h.result, h.err = result, nil
+			return
+		}
+
+		if uptime >= policy.StartSeconds {
+			retries = 0
+		} else {
+			retries++
+			if retries > policy.StartRetries {
+				h.setState(SuperviseFatal)
+				h.result, h.err = result, fmt.Errorf("exited after %s (< %s) %d time(s) in a row, exceeding StartRetries=%d",
+					uptime, policy.StartSeconds, retries, policy.StartRetries)
+				return
+			}
+		}
+
+		if result.SessionID != "" {
+			resumeID = result.SessionID
+		} else {
+			resumeID = ""
+		}
+
+		h.setState(SuperviseBackoff)
+		select {
+		case <-time.After(policy.Backoff.forAttempt(attempt)):
+		case <-ctx.Done():
+			h.setState(SuperviseExited)
+			h.result, h.err = result, ctx.Err()
+			return
+		}
+	}
+}
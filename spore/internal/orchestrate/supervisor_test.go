@@ -0,0 +1,108 @@
+package orchestrate
+
+import (
+	"testing"
+	"time"
+)
+
+func TestRestartOn_Always(t *testing.T) {
+	r := RestartAlways()
+	if !r.shouldRestart(0) || !r.shouldRestart(1) || !r.shouldRestart(137) {
+		t.Error("RestartAlways should restart on every exit code")
+	}
+}
+
+func TestRestartOn_Never(t *testing.T) {
+	r := RestartNever()
+	if r.shouldRestart(0) || r.shouldRestart(1) {
+		t.Error("RestartNever should never restart")
+	}
+}
+
+func TestRestartOn_Failure(t *testing.T) {
+	r := RestartOnFailure()
+	if r.shouldRestart(0) {
+		t.Error("RestartOnFailure should not restart a clean exit")
+	}
+	if !r.shouldRestart(1) {
+		t.Error("RestartOnFailure should restart a non-zero exit")
+	}
+}
+
+func TestRestartOn_ZeroValueMatchesFailure(t *testing.T) {
+	var r RestartOn
+	if r.shouldRestart(0) || !r.shouldRestart(1) {
+		t.Error("zero-value RestartOn should behave like RestartOnFailure")
+	}
+}
+
+func TestRestartOn_Codes(t *testing.T) {
+	r := RestartOnCodes(2, 137)
+	if r.shouldRestart(1) {
+		t.Error("RestartOnCodes should not restart on a code not in the set")
+	}
+	if !r.shouldRestart(2) || !r.shouldRestart(137) {
+		t.Error("RestartOnCodes should restart on a code in the set")
+	}
+}
+
+func TestBackoffPolicy_ForAttempt(t *testing.T) {
+	b := BackoffPolicy{Initial: time.Second, Max: 10 * time.Second, Multiplier: 2}
+
+	cases := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second}, // clamped to attempt 1
+		{1, time.Second},
+		{2, 2 * time.Second},
+		{3, 4 * time.Second},
+		{4, 8 * time.Second},
+		{5, 10 * time.Second}, // capped at Max
+		{6, 10 * time.Second},
+	}
+	for _, c := range cases {
+		if got := b.forAttempt(c.attempt); got != c.want {
+			t.Errorf("forAttempt(%d) = %v, want %v", c.attempt, got, c.want)
+		}
+	}
+}
+
+func TestDefaultBackoffPolicy(t *testing.T) {
+	b := DefaultBackoffPolicy()
+	if b.Initial <= 0 || b.Max <= 0 || b.Multiplier <= 1 {
+		t.Errorf("DefaultBackoffPolicy should be a sane growing schedule, got %+v", b)
+	}
+	if b.forAttempt(1) > b.forAttempt(2) {
+		t.Error("DefaultBackoffPolicy should not shrink between attempts")
+	}
+}
+
+func TestSupervisorHandle_StateStartsStarting(t *testing.T) {
+	h := &SupervisorHandle{state: SuperviseStarting, done: make(chan struct{})}
+	if h.State() != SuperviseStarting {
+		t.Errorf("expected initial state %q, got %q", SuperviseStarting, h.State())
+	}
+}
+
+func TestSupervisorHandle_WaitBlocksUntilDone(t *testing.T) {
+	h := &SupervisorHandle{state: SuperviseStarting, done: make(chan struct{})}
+	want := &ClaudeResult{ExitCode: 1}
+
+	go func() {
+		h.setState(SuperviseFatal)
+		h.result, h.err = want, nil
+		close(h.done)
+	}()
+
+	got, err := h.Wait()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got != want {
+		t.Errorf("Wait() = %+v, want %+v", got, want)
+	}
+	if h.State() != SuperviseFatal {
+		t.Errorf("expected final state %q, got %q", SuperviseFatal, h.State())
+	}
+}
@@ -3,14 +3,17 @@ package orchestrate
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"os"
 	"path/filepath"
+	"regexp"
 	"sort"
 	"strings"
 	"time"
 
 	"mycelica/spore/internal/db"
 	"mycelica/spore/internal/graph"
+	"mycelica/spore/internal/memcache"
 )
 
 // anchorNode is a search result used as a Dijkstra expansion source.
@@ -18,9 +21,21 @@ type anchorNode struct {
 	ID    string
 	Title string
 	Score float64
-	Source string // "semantic" or "fts"
+	Source string // "semantic", "fts", "pinned", or "code"
 }
 
+// rrfK is the Reciprocal Rank Fusion rank-damping constant used to merge
+// findAnchors' semantic, FTS, and pinned candidate lists: the standard value
+// from the original RRF paper, chosen so low ranks from any one source don't
+// dominate just because that source happened to return more candidates.
+const rrfK = 60
+
+// rrfPinnedRank is the synthetic rank a human-pinned node is given in RRF:
+// rank 1 (the best possible) so a pin strongly influences the fused score,
+// but it still has to share the fusion formula with every other source
+// rather than bypassing it outright.
+const rrfPinnedRank = 1
+
 // contextRow is a single row in the rendered Graph Context table.
 type contextRow struct {
 	Rank      int
@@ -33,6 +48,7 @@ type contextRow struct {
 	Content   string  // first 500 chars of node content
 	NodeClass string
 	IsCode    bool // has file_path in tags
+	Stale     bool // Relevance was discounted by TaskFileConfig.Staleness
 }
 
 // lesson is a past-run lesson relevant to this task.
@@ -50,12 +66,73 @@ type codeTags struct {
 	Language  string `json:"language"`
 }
 
+// configCacheKey renders the fields of a TaskFileConfig that affect anchor,
+// context, or lesson lookups into a stable string, for folding into
+// memcache cache keys alongside the query and task node ID.
+func configCacheKey(config TaskFileConfig) string {
+	staleKey := "nostale"
+	if config.Staleness != nil {
+		staleKey = fmt.Sprintf("stale:%d:%d:%d",
+			config.StaleDays, len(config.Staleness.StaleNodes), len(config.Staleness.StaleSummaries))
+	}
+	return fmt.Sprintf("%d|%d|%d|%.4f|%d|%.4f|%d|%t|%s|%s|%t",
+		config.Budget, config.MaxAnchors, config.SimilarTop, config.Threshold,
+		config.MaxHops, config.MaxCost, config.MaxLessons, config.UseANNIndex, staleKey,
+		strings.Join(config.PinnedNodes, ","), config.EnableCodeSearch)
+}
+
+// anchorNodeIDs extracts the node IDs an anchor list references, for
+// registering a cache entry against memcache invalidation.
+func anchorNodeIDs(anchors []anchorNode) []string {
+	ids := make([]string, len(anchors))
+	for i, a := range anchors {
+		ids[i] = a.ID
+	}
+	return ids
+}
+
+// anchorsCost estimates an anchor list's in-memory footprint in bytes, for
+// memcache's byte-bounded eviction.
+func anchorsCost(anchors []anchorNode) int64 {
+	var cost int64
+	for _, a := range anchors {
+		cost += int64(len(a.ID) + len(a.Title) + len(a.Source) + 16)
+	}
+	return cost
+}
+
+// contextRowsCost estimates a context row slice's in-memory footprint in
+// bytes, for memcache's byte-bounded eviction.
+func contextRowsCost(rows []contextRow) int64 {
+	var cost int64
+	for _, r := range rows {
+		cost += int64(len(r.NodeID) + len(r.Title) + len(r.Via) + len(r.Anchor) +
+			len(r.Tags) + len(r.Content) + len(r.NodeClass) + 24)
+	}
+	return cost
+}
+
+// lessonsCost estimates a lesson list's in-memory footprint in bytes, for
+// memcache's byte-bounded eviction.
+func lessonsCost(lessons []lesson) int64 {
+	var cost int64
+	for _, l := range lessons {
+		cost += int64(len(l.Title) + len(l.Summary) + len(l.Fix) + 16)
+	}
+	return cost
+}
+
 // GenerateTaskFile creates a markdown task file with graph context for a pipeline agent.
 //
 // It performs semantic + FTS anchor search, Dijkstra context expansion, lesson
 // matching, and renders the result as structured markdown. The file is written
 // to outputDir/task-<role>-<runID[:8]>.md.
 //
+// provider is the RetrievalProvider anchor/context search runs through; nil
+// defaults to NewSQLiteRetrievalProvider(d, config.UseANNIndex, taskNodeID),
+// the legacy behavior. Tests can inject a fake; production deployments can
+// swap in a CompositeRetrievalProvider without touching this function.
+//
 // Returns (filepath, contextNodeCount, error).
 func GenerateTaskFile(
 	d *db.DB,
@@ -67,10 +144,18 @@ func GenerateTaskFile(
 	lastVerdict Verdict,
 	config TaskFileConfig,
 	outputDir string,
+	store LessonStore,
+	provider RetrievalProvider,
 ) (string, int, error) {
+	if store == nil {
+		store = NewSQLiteLessonStore(d)
+	}
+	if provider == nil {
+		provider = NewSQLiteRetrievalProvider(d, config.UseANNIndex, taskNodeID)
+	}
 
 	// 1. Find anchor nodes
-	anchors, err := findAnchors(d, task, taskNodeID, config)
+	anchors, err := findAnchors(d, provider, task, taskNodeID, config)
 	if err != nil {
 		// Non-fatal: proceed with empty anchors
 		fmt.Fprintf(os.Stderr, "[task-file] warning: anchor search failed: %v\n", err)
@@ -78,15 +163,17 @@ func GenerateTaskFile(
 	}
 
 	// 2. Expand anchors via Dijkstra
-	context := gatherContext(d, anchors, taskNodeID, config)
+	context := gatherContext(d, provider, anchors, taskNodeID, config)
 
 	// 3. Find relevant lessons from past runs
-	lessons := findLessons(d, task, taskNodeID, config)
+	lessons := findLessons(d, task, taskNodeID, config, store)
 
-	// 4. Render markdown
-	md := renderMarkdown(d, task, role, runID, taskNodeID,
+	// 4. Build the document and render it via config.TaskFileFormat
+	doc := buildDocument(d, task, role, runID, taskNodeID,
 		bounce, maxBounces, lastImplID, lastVerdict,
-		anchors, context, lessons)
+		anchors, context, lessons, config)
+	renderer, ext := rendererForFormat(config.TaskFileFormat)
+	out := renderer.Render(doc)
 
 	// 5. Write to disk
 	if err := os.MkdirAll(outputDir, 0o755); err != nil {
@@ -97,61 +184,54 @@ func GenerateTaskFile(
 	if len(shortRunID) > 8 {
 		shortRunID = shortRunID[:8]
 	}
-	filename := fmt.Sprintf("task-%s-%s.md", role, shortRunID)
+	filename := fmt.Sprintf("task-%s-%s.%s", role, shortRunID, ext)
 	path := filepath.Join(outputDir, filename)
-	if err := os.WriteFile(path, []byte(md), 0o644); err != nil {
+	if err := os.WriteFile(path, []byte(out), 0o644); err != nil {
 		return "", 0, fmt.Errorf("writing task file: %w", err)
 	}
 
-	lineCount := strings.Count(md, "\n") + 1
+	lineCount := strings.Count(out, "\n") + 1
 	fmt.Fprintf(os.Stderr, "[task-file] Generated: %d lines\n", lineCount)
 
 	return path, len(context), nil
 }
 
-// findAnchors performs two-source anchor selection: semantic (embedding similarity)
-// and FTS (keyword search). Semantic results have priority; FTS fills remaining slots.
-// Ports spore.rs lines 2135-2220.
-func findAnchors(d *db.DB, task, taskNodeID string, config TaskFileConfig) ([]anchorNode, error) {
+// findAnchors performs three-source anchor selection -- semantic (embedding
+// similarity), FTS (keyword search), and human-pinned nodes -- merged by
+// Reciprocal Rank Fusion so a candidate ranked well by any source (or
+// several) surfaces, rather than semantic results simply padded out with
+// FTS-only hits. Ports spore.rs lines 2135-2220. Search itself goes through
+// provider (see RetrievalProvider) so a caller can swap in an alternate or
+// composite backend; d is still used directly for pinned-node resolution and
+// the task's own embedding, neither of which is a search backend concern.
+func findAnchors(d *db.DB, provider RetrievalProvider, task, taskNodeID string, config TaskFileConfig) ([]anchorNode, error) {
+	cacheKey := memcache.Key("anchors", task, taskNodeID, configCacheKey(config))
+	if cached, ok := memcache.Default().Get(cacheKey); ok {
+		return cached.([]anchorNode), nil
+	}
+
 	maxAnchors := config.MaxAnchors
 	if maxAnchors <= 0 {
 		maxAnchors = 5
 	}
 
+	// poolSize bounds how many ranked candidates each source contributes to
+	// fusion -- generous enough that a keyword hit ranked just below the
+	// semantic cutoff (or vice versa) still gets a fair shot at the fused
+	// score, without pulling in the entire FTS result set unbounded.
+	poolSize := maxAnchors * 3
+
 	var semanticAnchors []anchorNode
 	var ftsAnchors []anchorNode
 
 	// --- Semantic search ---
-	taskEmb, err := d.GetNodeEmbedding(taskNodeID)
-	if err == nil && taskEmb != nil {
-		allEmbs, err := d.GetNodesWithEmbeddings()
+	if taskEmb, err := d.GetNodeEmbedding(taskNodeID, db.DefaultEmbeddingModel); err == nil && taskEmb != nil {
+		hits, err := provider.SemanticSearch(taskEmb, poolSize, config.Threshold)
 		if err == nil {
-			similar := graph.FindSimilar(
-				taskEmb, allEmbs, taskNodeID,
-				config.SimilarTop, float32(config.Threshold),
-			)
-			for _, s := range similar {
-				// Resolve node to check class
-				node, err := d.GetNode(s.ID)
-				if err != nil || node == nil {
-					continue
-				}
-				if node.NodeClass != nil && *node.NodeClass == "operational" {
-					continue
-				}
-				title := node.Title
-				if node.AITitle != nil {
-					title = *node.AITitle
-				}
+			for _, h := range hits {
 				semanticAnchors = append(semanticAnchors, anchorNode{
-					ID:    s.ID,
-					Title: title,
-					Score: float64(s.Similarity),
-					Source: "semantic",
+					ID: h.ID, Title: h.Title, Score: h.Score, Source: "semantic",
 				})
-				if len(semanticAnchors) >= maxAnchors {
-					break
-				}
 			}
 		}
 	}
@@ -161,31 +241,11 @@ func findAnchors(d *db.DB, task, taskNodeID string, config TaskFileConfig) ([]an
 	}
 
 	// --- FTS keyword search ---
-	ftsQuery := db.BuildFTSQuery(task)
-	if ftsQuery != "" {
-		ftsNodes, err := d.SearchNodes(task)
-		if err == nil {
-			for _, n := range ftsNodes {
-				if n.ID == taskNodeID {
-					continue
-				}
-				if n.NodeClass != nil && *n.NodeClass == "operational" {
-					continue
-				}
-				title := n.Title
-				if n.AITitle != nil {
-					title = *n.AITitle
-				}
-				ftsAnchors = append(ftsAnchors, anchorNode{
-					ID:    n.ID,
-					Title: title,
-					Score: 0, // FTS doesn't produce a similarity score
-					Source: "fts",
-				})
-				if len(ftsAnchors) >= maxAnchors {
-					break
-				}
-			}
+	if hits, err := provider.KeywordSearch(task, poolSize); err == nil {
+		for _, h := range hits {
+			ftsAnchors = append(ftsAnchors, anchorNode{
+				ID: h.ID, Title: h.Title, Score: 0, Source: "fts", // Score overwritten by the fused RRF score below
+			})
 		}
 	}
 
@@ -193,32 +253,107 @@ func findAnchors(d *db.DB, task, taskNodeID string, config TaskFileConfig) ([]an
 		fmt.Fprintf(os.Stderr, "[task-file] FTS search found %d candidate(s)\n", len(ftsAnchors))
 	}
 
-	// --- Merge: semantic first, then FTS deduped ---
-	seen := make(map[string]bool)
-	var merged []anchorNode
-	for _, a := range semanticAnchors {
-		if !seen[a.ID] {
-			seen[a.ID] = true
-			merged = append(merged, a)
+	// --- Pinned nodes: a third ranked source, always at rank 1 ---
+	var pinnedAnchors []anchorNode
+	for _, id := range config.PinnedNodes {
+		if id == taskNodeID {
+			continue
+		}
+		node, err := d.GetNode(id)
+		if err != nil || node == nil {
+			continue
+		}
+		title := node.Title
+		if node.AITitle != nil {
+			title = *node.AITitle
+		}
+		pinnedAnchors = append(pinnedAnchors, anchorNode{ID: id, Title: title, Source: "pinned"})
+	}
+
+	// --- Code symbol search: identifiers/file-path fragments FTS tokenization misses ---
+	var codeAnchors []anchorNode
+	if config.EnableCodeSearch {
+		hits, err := provider.SymbolSearch(task, poolSize)
+		if err == nil {
+			for _, h := range hits {
+				codeAnchors = append(codeAnchors, anchorNode{ID: h.ID, Title: h.Title, Source: "code"})
+			}
+		}
+		if len(codeAnchors) > 0 {
+			fmt.Fprintf(os.Stderr, "[task-file] Code symbol search found %d candidate(s)\n", len(codeAnchors))
 		}
 	}
-	for _, a := range ftsAnchors {
-		if !seen[a.ID] {
-			seen[a.ID] = true
-			merged = append(merged, a)
+
+	// --- Merge via Reciprocal Rank Fusion: score(node) = sum over sources of
+	// 1/(rrfK + rank_in_source), keeping each source's own rank ordering
+	// rather than any cross-source similarity/score comparison. ---
+	type fused struct {
+		anchorNode
+		score float64
+	}
+	byID := make(map[string]*fused)
+	addToFused := func(id string, seed anchorNode, rrfScore float64) {
+		f, ok := byID[id]
+		if !ok {
+			f = &fused{anchorNode: seed}
+			byID[id] = f
+		}
+		f.score += rrfScore
+	}
+	// addRanked folds a source's result list into byID, scoring its i-th
+	// entry (0-indexed) at 1-based rank i+1.
+	addRanked := func(list []anchorNode) {
+		for i, a := range list {
+			addToFused(a.ID, a, 1.0/float64(rrfK+i+1))
 		}
 	}
+	addRanked(semanticAnchors)
+	addRanked(ftsAnchors)
+	addRanked(codeAnchors)
+	// Pinned nodes all enter RRF at the same synthetic rank rather than
+	// competing against each other for rank order.
+	for _, a := range pinnedAnchors {
+		addToFused(a.ID, a, 1.0/float64(rrfK+rrfPinnedRank))
+	}
+
+	merged := make([]anchorNode, 0, len(byID))
+	for _, f := range byID {
+		f.Score = f.score
+		merged = append(merged, f.anchorNode)
+	}
+	sort.Slice(merged, func(i, j int) bool { return merged[i].Score > merged[j].Score })
 	if len(merged) > maxAnchors {
 		merged = merged[:maxAnchors]
 	}
 
-	fmt.Fprintf(os.Stderr, "[task-file] %d anchor(s) after merge+dedup\n", len(merged))
+	if idx := newStalenessIndex(config.Staleness); idx != nil {
+		for i := range merged {
+			if factor, stale := idx.penalty(merged[i].ID, config.StaleDays); stale {
+				merged[i].Score *= factor
+			}
+		}
+	}
+
+	fmt.Fprintf(os.Stderr, "[task-file] %d anchor(s) after RRF merge+dedup:\n", len(merged))
+	for _, a := range merged {
+		fmt.Fprintf(os.Stderr, "[task-file]   %.4f  %s (%s) %q\n", a.Score, a.ID, a.Source, a.Title)
+	}
+	memcache.Default().Set(cacheKey, merged, anchorsCost(merged), anchorNodeIDs(merged))
 	return merged, nil
 }
 
 // gatherContext expands each anchor via Dijkstra and merges results.
 // For each node ID, keeps the highest relevance score.
-func gatherContext(d *db.DB, anchors []anchorNode, taskNodeID string, config TaskFileConfig) []contextRow {
+func gatherContext(d *db.DB, provider RetrievalProvider, anchors []anchorNode, taskNodeID string, config TaskFileConfig) []contextRow {
+	anchorIDs := make([]string, len(anchors))
+	for i, a := range anchors {
+		anchorIDs[i] = a.ID
+	}
+	cacheKey := memcache.Key("context", strings.Join(anchorIDs, ","), taskNodeID, configCacheKey(config))
+	if cached, ok := memcache.Default().Get(cacheKey); ok {
+		return cached.([]contextRow)
+	}
+
 	budget := config.Budget
 	if budget <= 0 {
 		budget = 7
@@ -238,6 +373,7 @@ func gatherContext(d *db.DB, anchors []anchorNode, taskNodeID string, config Tas
 		title     string
 		anchor    string
 		via       string
+		stale     bool
 	}
 	seen := make(map[string]seenEntry)
 
@@ -251,7 +387,7 @@ func gatherContext(d *db.DB, anchors []anchorNode, taskNodeID string, config Tas
 			ItemsOnly:        true,
 		}
 
-		ctxNodes, err := d.ContextForTask(anchor.ID, ctxConfig)
+		ctxNodes, err := provider.ExpandContext(anchor.ID, ctxConfig)
 		if err != nil {
 			continue
 		}
@@ -285,8 +421,13 @@ func gatherContext(d *db.DB, anchors []anchorNode, taskNodeID string, config Tas
 		// Include the anchor itself if not already present
 		if _, exists := seen[anchor.ID]; !exists {
 			sourceLabel := "Semantic match"
-			if anchor.Source == "fts" {
+			switch anchor.Source {
+			case "fts":
 				sourceLabel = "FTS match"
+			case "pinned":
+				sourceLabel = "Pinned"
+			case "code":
+				sourceLabel = "Code symbol match"
 			}
 			seen[anchor.ID] = seenEntry{
 				relevance: 1.0,
@@ -300,6 +441,18 @@ func gatherContext(d *db.DB, anchors []anchorNode, taskNodeID string, config Tas
 	// Filter out the task node itself
 	delete(seen, taskNodeID)
 
+	// Discount relevance for known-stale nodes before ranking, so stale
+	// context sorts below fresh context of similar raw relevance.
+	if idx := newStalenessIndex(config.Staleness); idx != nil {
+		for id, e := range seen {
+			if factor, stale := idx.penalty(id, config.StaleDays); stale {
+				e.relevance *= factor
+				e.stale = true
+				seen[id] = e
+			}
+		}
+	}
+
 	// Sort by relevance descending
 	type kv struct {
 		id string
@@ -347,56 +500,51 @@ func gatherContext(d *db.DB, anchors []anchorNode, taskNodeID string, config Tas
 			Content:   content,
 			NodeClass: nodeClass,
 			IsCode:    isCode,
+			Stale:     item.e.stale,
 		}
 	}
 
+	nodeIDs := make([]string, len(rows))
+	for i, r := range rows {
+		nodeIDs[i] = r.NodeID
+	}
+	memcache.Default().Set(cacheKey, rows, contextRowsCost(rows), nodeIDs)
 	return rows
 }
 
 // findLessons finds past-run lessons relevant to the current task.
 // Uses embedding similarity when available, falls back to recency.
-func findLessons(d *db.DB, task, taskNodeID string, config TaskFileConfig) []lesson {
+// store is the LessonStore to query; pass NewSQLiteLessonStore(d) for the
+// legacy behavior.
+func findLessons(d *db.DB, task, taskNodeID string, config TaskFileConfig, store LessonStore) []lesson {
+	cacheKey := memcache.Key("lessons", task, taskNodeID, configCacheKey(config))
+	if cached, ok := memcache.Default().Get(cacheKey); ok {
+		return cached.([]lesson)
+	}
+
 	maxLessons := config.MaxLessons
 	if maxLessons <= 0 {
 		maxLessons = 5
 	}
 
-	// Query operational nodes with "Lesson:" title prefix
-	rows, err := d.Conn().Query(
-		`SELECT id, title, content FROM nodes
-		 WHERE node_class = 'operational' AND title LIKE 'Lesson:%'
-		 ORDER BY created_at DESC LIMIT 20`,
-	)
-	if err != nil {
+	queried, err := store.Query(LessonFilter{Limit: 20})
+	if err != nil || len(queried) == 0 {
 		return nil
 	}
-	defer rows.Close()
 
 	type rawLesson struct {
 		id, title, content string
 	}
 	var allLessons []rawLesson
-	for rows.Next() {
-		var id, title string
-		var content *string
-		if err := rows.Scan(&id, &title, &content); err != nil {
-			continue
-		}
-		c := ""
-		if content != nil {
-			c = *content
-		}
-		allLessons = append(allLessons, rawLesson{id, title, c})
-	}
-	if len(allLessons) == 0 {
-		return nil
+	for _, l := range queried {
+		allLessons = append(allLessons, rawLesson{l.ID, l.Title, l.Content})
 	}
 
 	// Try to rank by embedding similarity to task
-	taskEmb, err := d.GetNodeEmbedding(taskNodeID)
+	taskEmb, err := d.GetNodeEmbedding(taskNodeID, db.DefaultEmbeddingModel)
 	var ranked []rawLesson
 	if err == nil && taskEmb != nil {
-		allEmbs, err := d.GetNodesWithEmbeddings()
+		allEmbs, err := d.GetNodesWithEmbeddings(db.DefaultEmbeddingModel)
 		if err == nil && len(allEmbs) > 0 {
 			// Build set of lesson IDs for filtering
 			lessonIDs := make(map[string]bool)
@@ -454,6 +602,7 @@ func findLessons(d *db.DB, task, taskNodeID string, config TaskFileConfig) []les
 
 	// Extract pattern/fix sections from each lesson
 	var result []lesson
+	var nodeIDs []string
 	for _, rl := range ranked {
 		pattern := extractSection(rl.content, "## Pattern", "## Situation")
 		fix := extractSection(rl.content, "## Fix")
@@ -472,8 +621,10 @@ func findLessons(d *db.DB, task, taskNodeID string, config TaskFileConfig) []les
 			Summary: summary,
 			Fix:     fix,
 		})
+		nodeIDs = append(nodeIDs, rl.id)
 	}
 
+	memcache.Default().Set(cacheKey, result, lessonsCost(result), nodeIDs)
 	return result
 }
 
@@ -504,7 +655,11 @@ func extractSection(content string, startHeaders ...string) string {
 }
 
 // renderMarkdown generates the full task file markdown from gathered data.
-// The db parameter is optional (nil-safe) and used only for call graph edge lookups.
+// The db parameter is optional (nil-safe) and used only for call graph edge
+// lookups; config's CallGraphMaxHops/CallGraphMaxNodes bound that walk.
+// It is a thin wrapper around buildDocument + MarkdownRenderer -- see
+// GenerateTaskFile's Renderer parameter for HTML/JSON output of the same
+// Document.
 func renderMarkdown(
 	d *db.DB,
 	task string,
@@ -516,8 +671,29 @@ func renderMarkdown(
 	anchors []anchorNode,
 	context []contextRow,
 	lessons []lesson,
+	config TaskFileConfig,
 ) string {
-	var md strings.Builder
+	doc := buildDocument(d, task, role, runID, taskNodeID, bounce, maxBounces, lastImplID, lastVerdict, anchors, context, lessons, config)
+	return MarkdownRenderer{}.Render(doc)
+}
+
+// buildDocument assembles a task file's Document tree from gathered data --
+// the traversal renderMarkdown used to write directly to a strings.Builder,
+// now format-agnostic so any Renderer can consume it.
+func buildDocument(
+	d *db.DB,
+	task string,
+	role AgentRole,
+	runID, taskNodeID string,
+	bounce, maxBounces int,
+	lastImplID string,
+	lastVerdict Verdict,
+	anchors []anchorNode,
+	context []contextRow,
+	lessons []lesson,
+	config TaskFileConfig,
+) *Document {
+	b := &docBuilder{}
 	now := time.Now().UTC()
 
 	// Short title
@@ -531,43 +707,44 @@ func renderMarkdown(
 	}
 
 	// --- 1. Header ---
-	md.WriteString(fmt.Sprintf("# Task: %s\n\n", taskShort))
-	md.WriteString(fmt.Sprintf("- **Run:** %s\n", shortRunID))
-	md.WriteString(fmt.Sprintf("- **Agent:** %s\n", role))
-	md.WriteString(fmt.Sprintf("- **Bounce:** %d/%d\n", bounce+1, maxBounces))
-	md.WriteString(fmt.Sprintf("- **Generated:** %s\n\n", now.Format("2006-01-02 15:04:05 UTC")))
+	b.heading(1, fmt.Sprintf("Task: %s", taskShort))
+	b.list([]string{
+		fmt.Sprintf("**Run:** %s", shortRunID),
+		fmt.Sprintf("**Agent:** %s", role),
+		fmt.Sprintf("**Bounce:** %d/%d", bounce+1, maxBounces),
+		fmt.Sprintf("**Generated:** %s", now.Format("2006-01-02 15:04:05 UTC")),
+	})
 
 	// --- 2. Task ---
-	md.WriteString("## Task\n\n")
-	md.WriteString(task)
-	md.WriteString("\n\n")
+	b.heading(2, "Task")
+	b.para(task)
 
 	// --- 3. Conditional sections based on role/bounce ---
 	if lastImplID != "" {
 		switch role {
 		case RoleVerifier:
-			md.WriteString("## Implementation to Check\n\n")
-			md.WriteString(fmt.Sprintf(
-				"Implementation node ID: `%s`. Read it with `mycelica_read_content` to see what the coder changed and why.\n\n",
+			b.heading(2, "Implementation to Check")
+			b.para(fmt.Sprintf(
+				"Implementation node ID: `%s`. Read it with `mycelica_read_content` to see what the coder changed and why.",
 				lastImplID,
 			))
 		case RoleSummarizer:
-			md.WriteString("## Implementation to Summarize\n\n")
-			md.WriteString(fmt.Sprintf(
-				"Implementation node ID: `%s`. Read it and the full bounce trail with `mycelica_read_content` and `mycelica_nav_edges`.\n\n",
+			b.heading(2, "Implementation to Summarize")
+			b.para(fmt.Sprintf(
+				"Implementation node ID: `%s`. Read it and the full bounce trail with `mycelica_read_content` and `mycelica_nav_edges`.",
 				lastImplID,
 			))
 		default:
 			// coder on bounce 2+: previous impl had issues
-			md.WriteString("## Previous Bounce\n\n")
+			b.heading(2, "Previous Bounce")
 			if lastVerdict == VerdictUnknown {
-				md.WriteString(fmt.Sprintf(
-					"The verifier could not parse a verdict from the previous attempt (node `%s`). Review your changes carefully and ensure correctness.\n\n",
+				b.para(fmt.Sprintf(
+					"The verifier could not parse a verdict from the previous attempt (node `%s`). Review your changes carefully and ensure correctness.",
 					lastImplID,
 				))
 			} else {
-				md.WriteString(fmt.Sprintf(
-					"Verifier found issues with node `%s`. Check its incoming `contradicts` edges and fix the code.\n\n",
+				b.para(fmt.Sprintf(
+					"Verifier found issues with node `%s`. Check its incoming `contradicts` edges and fix the code.",
 					lastImplID,
 				))
 			}
@@ -575,15 +752,14 @@ func renderMarkdown(
 	}
 
 	// --- 4. Graph Context ---
-	md.WriteString("## Graph Context\n\n")
-	md.WriteString("Relevant nodes found by search + Dijkstra traversal from the task description.\n")
-	md.WriteString("Use `mycelica_node_get` or `mycelica_read_content` to read full content of any node.\n\n")
+	b.heading(2, "Graph Context")
+	b.para("Relevant nodes found by search + Dijkstra traversal from the task description.\n" +
+		"Use `mycelica_node_get` or `mycelica_read_content` to read full content of any node.")
 
 	if len(context) == 0 {
-		md.WriteString("_No relevant nodes found in the graph._\n\n")
+		b.para("_No relevant nodes found in the graph._")
 	} else {
-		md.WriteString("| # | Node | ID | Relevance | Via |\n")
-		md.WriteString("|---|------|----|-----------|-----|\n")
+		rows := make([][]string, 0, len(context))
 		for _, row := range context {
 			titleShort := row.Title
 			if len(titleShort) > 50 {
@@ -593,18 +769,27 @@ func renderMarkdown(
 			if len(idShort) > 12 {
 				idShort = idShort[:12]
 			}
-			md.WriteString(fmt.Sprintf(
-				"| %d | %s | `%s` | %.0f%% | %s -> %s |\n",
-				row.Rank, titleShort, idShort, row.Relevance*100.0, row.Anchor, row.Via,
-			))
+			staleMark := "-"
+			if row.Stale {
+				staleMark = "⚠"
+			}
+			rows = append(rows, []string{
+				fmt.Sprintf("%d", row.Rank),
+				titleShort,
+				fmt.Sprintf("`%s`", idShort),
+				fmt.Sprintf("%.0f%%", row.Relevance*100.0),
+				staleMark,
+				fmt.Sprintf("%s -> %s", row.Anchor, row.Via),
+			})
 		}
-		md.WriteString("\n")
+		b.table([]string{"#", "Node", "ID", "Relevance", "Stale", "Via"}, rows)
 
 		// --- 5. Code Locations ---
 		codeRows := filterCodeRows(context)
 		if len(codeRows) > 0 {
-			md.WriteString("### Code Locations\n\n")
-			md.WriteString("Use `Read` tool with these paths for direct file access (faster than MCP):\n\n")
+			b.heading(3, "Code Locations")
+			b.para("Use `Read` tool with these paths for direct file access (faster than MCP):")
+			var locations []string
 			for _, cr := range codeRows {
 				ct := parseCodeTags(cr.Tags)
 				if ct.FilePath == "" {
@@ -614,45 +799,48 @@ func renderMarkdown(
 				if len(titleShort) > 40 {
 					titleShort = titleShort[:40]
 				}
-				md.WriteString(fmt.Sprintf("- `%s` L%d-%d -- %s\n",
+				locations = append(locations, fmt.Sprintf("`%s` L%d-%d -- %s",
 					ct.FilePath, ct.StartLine, ct.EndLine, titleShort))
 			}
-			md.WriteString("\n")
+			b.list(locations)
 
 			// --- 6. Key Code Snippets (top 5) ---
-			renderCodeSnippets(&md, codeRows)
+			renderCodeSnippets(b, codeRows)
 
 			// --- 7. Files Likely Touched (top 8) ---
-			renderFilesLikelyTouched(&md, codeRows)
+			renderFilesLikelyTouched(b, codeRows)
 
 			// --- 8. Call Graph (top 3 functions) ---
 			if d != nil {
-				renderCallGraphWithDB(&md, d, context)
+				renderCallGraphWithDB(b, d, context, config)
 			}
 		}
 	}
 
 	// --- 9. Lessons from Past Runs ---
 	if len(lessons) > 0 {
-		md.WriteString("## Lessons from Past Runs\n\n")
-		md.WriteString("These were extracted from previous orchestrator runs. Keep them in mind.\n\n")
+		b.heading(2, "Lessons from Past Runs")
+		b.para("These were extracted from previous orchestrator runs. Keep them in mind.")
+		var items []string
 		for _, l := range lessons {
 			lessonName := strings.TrimPrefix(l.Title, "Lesson: ")
 			if l.Fix == "" {
-				md.WriteString(fmt.Sprintf("- **%s**: %s\n", lessonName, l.Summary))
+				items = append(items, fmt.Sprintf("**%s**: %s", lessonName, l.Summary))
 			} else {
-				md.WriteString(fmt.Sprintf("- **%s**: %s\n  **Fix:** %s\n", lessonName, l.Summary, l.Fix))
+				items = append(items, fmt.Sprintf("**%s**: %s\n  **Fix:** %s", lessonName, l.Summary, l.Fix))
 			}
 		}
-		md.WriteString("\n")
+		b.list(items)
 	}
 
 	// --- 10. Checklist ---
-	md.WriteString("## Checklist\n\n")
-	md.WriteString("- [ ] Read relevant context nodes above before starting\n")
-	md.WriteString("- [ ] Link implementation to modified code nodes with edges\n")
+	b.heading(2, "Checklist")
+	b.checklist([]ChecklistItem{
+		{Text: "Read relevant context nodes above before starting"},
+		{Text: "Link implementation to modified code nodes with edges"},
+	})
 
-	return md.String()
+	return &b.doc
 }
 
 // filterCodeRows returns context rows that represent code nodes (have file_path in tags).
@@ -681,8 +869,8 @@ func parseCodeTags(tagsJSON string) codeTags {
 	return ct
 }
 
-// renderCodeSnippets writes inline code snippets for top code nodes.
-func renderCodeSnippets(md *strings.Builder, codeRows []contextRow) {
+// renderCodeSnippets appends inline code snippet blocks for top code nodes.
+func renderCodeSnippets(b *docBuilder, codeRows []contextRow) {
 	const snippetLimit = 5
 	const snippetMaxLines = 30
 
@@ -704,9 +892,16 @@ func renderCodeSnippets(md *strings.Builder, codeRows []contextRow) {
 		candidates = append(candidates, candidate{cr, ct})
 	}
 
-	// Sort: functions first, then structs/enums
+	// Sort: functions/methods first, then structs/enums/everything else
+	candidateKind := func(c candidate) NodeKind {
+		lang := langFromExtension(c.tags.FilePath)
+		if c.tags.Language != "" {
+			lang = c.tags.Language
+		}
+		return classifyTitle(c.row.Title, lang)
+	}
 	sort.SliceStable(candidates, func(i, j int) bool {
-		return isFunctionTitle(candidates[i].row.Title) && !isFunctionTitle(candidates[j].row.Title)
+		return candidateKind(candidates[i]).IsCallable() && !candidateKind(candidates[j]).IsCallable()
 	})
 
 	snippetsAdded := 0
@@ -746,36 +941,194 @@ func renderCodeSnippets(md *strings.Builder, codeRows []contextRow) {
 		}
 
 		if snippetsAdded == 0 {
-			md.WriteString("### Key Code Snippets\n\n")
-			md.WriteString("Top code sections -- read these before exploring further.\n\n")
+			b.heading(3, "Key Code Snippets")
+			b.para("Top code sections -- read these before exploring further.")
 		}
 
 		titleShort := c.row.Title
 		if len(titleShort) > 60 {
 			titleShort = titleShort[:60]
 		}
-		md.WriteString(fmt.Sprintf("**%s** (`%s` L%d-%d):\n",
+		b.para(fmt.Sprintf("**%s** (`%s` L%d-%d):",
 			titleShort, c.tags.FilePath, c.tags.StartLine, c.tags.EndLine))
 
 		lang := langFromExtension(c.tags.FilePath)
 		if c.tags.Language != "" {
 			lang = c.tags.Language
 		}
-		md.WriteString(fmt.Sprintf("```%s\n", lang))
-		for _, line := range lines[startIdx:snippetEnd] {
-			md.WriteString(line)
-			md.WriteString("\n")
+		if strings.EqualFold(lang, "go") {
+			if md, ok := goDocComment(lines, startIdx, docLinkResolver(codeRows, c.row.NodeID)); ok {
+				b.para(md)
+			}
 		}
-		if snippetEnd < endIdx {
-			md.WriteString(fmt.Sprintf("// ... (%d more lines)\n", endIdx-snippetEnd))
+		astOK := false
+		var astSnippet []string
+		if astBraceLanguages[strings.ToLower(lang)] {
+			astSnippet, astOK = astSnippetWindow(lines, startIdx, endIdx, relatedTitles(codeRows, c.row.NodeID), snippetMaxLines)
 		}
-		md.WriteString("```\n\n")
+		var snippetLines []string
+		if astOK {
+			snippetLines = astSnippet
+		} else {
+			snippetLines = append(snippetLines, lines[startIdx:snippetEnd]...)
+			if snippetEnd < endIdx {
+				snippetLines = append(snippetLines, fmt.Sprintf("// ... (%d more lines)", endIdx-snippetEnd))
+			}
+		}
+		b.code(lang, snippetLines)
 		snippetsAdded++
 	}
 }
 
-// renderFilesLikelyTouched groups code nodes by file and renders a ranked list.
-func renderFilesLikelyTouched(md *strings.Builder, codeRows []contextRow) {
+// relatedTitles collects the titles of every code row other than excludeID,
+// for astSnippetWindow's "mentions an identifier from another row" signal.
+func relatedTitles(codeRows []contextRow, excludeID string) []string {
+	titles := make([]string, 0, len(codeRows))
+	for _, cr := range codeRows {
+		if cr.NodeID == excludeID {
+			continue
+		}
+		titles = append(titles, cr.Title)
+	}
+	return titles
+}
+
+// astTitleIdentifierPattern pulls identifier-like tokens out of a context
+// row's title (e.g. "fn handle_request" -> "handle_request"), for
+// astSnippetWindow's cross-reference check.
+var astTitleIdentifierPattern = regexp.MustCompile(`[A-Za-z_][A-Za-z0-9_]*`)
+
+// astSnippetKeywords are tokens extractTitleIdentifiers should never treat as
+// a meaningful cross-reference -- language keywords that show up in nearly
+// every title and would otherwise match almost every statement.
+var astSnippetKeywords = map[string]bool{
+	"fn": true, "pub": true, "crate": true, "async": true, "func": true,
+	"function": true, "export": true, "default": true, "struct": true,
+	"class": true, "def": true, "interface": true, "type": true, "const": true,
+	"var": true, "let": true, "impl": true, "enum": true,
+}
+
+// extractTitleIdentifiers pulls distinct, non-keyword identifier tokens of
+// at least 3 characters out of title.
+func extractTitleIdentifiers(title string) []string {
+	var out []string
+	for _, tok := range astTitleIdentifierPattern.FindAllString(title, -1) {
+		if len(tok) < 3 || astSnippetKeywords[strings.ToLower(tok)] {
+			continue
+		}
+		out = append(out, tok)
+	}
+	return out
+}
+
+// astBraceLanguages are the languages astSnippetWindow knows how to scan:
+// those with C-style `{`/`}` block delimiters. Languages outside this set
+// (e.g. Python) fall back to the plain line-window in renderCodeSnippets,
+// since brace-depth tracking doesn't apply to indentation-delimited blocks.
+var astBraceLanguages = map[string]bool{
+	"go": true, "rust": true, "rs": true,
+	"javascript": true, "js": true, "typescript": true, "ts": true,
+	"tsx": true, "jsx": true, "c": true, "cpp": true, "java": true,
+}
+
+// astSnippetWindow approximates an AST-aware snippet selection without a
+// real parser (this repo has no tree-sitter/AST dependency): it brace-depth
+// scans the function's line range to find the body's extent, then keeps the
+// signature line, every line containing "return" (at any nesting depth),
+// and every line mentioning an identifier from relatedTitles -- the same
+// signal findAnchors' code search uses to connect nodes. Skipped runs of
+// lines are replaced by a "// ... (elided N lines)" marker so a reader can
+// tell the body was pruned, not truncated. Returns ok=false (caller should
+// fall back to the plain line-window) when the language isn't
+// brace-delimited, no opening brace is found, or the selection doesn't fit
+// within maxLines.
+func astSnippetWindow(lines []string, startIdx, endIdx int, relatedTitles []string, maxLines int) ([]string, bool) {
+	var wantedIdents []string
+	for _, t := range relatedTitles {
+		wantedIdents = append(wantedIdents, extractTitleIdentifiers(t)...)
+	}
+
+	// Find the function's opening brace.
+	braceLine := -1
+	for i := startIdx; i < endIdx && i < len(lines); i++ {
+		if strings.Contains(lines[i], "{") {
+			braceLine = i
+			break
+		}
+	}
+	if braceLine == -1 {
+		return nil, false
+	}
+
+	depth := 0
+	keep := make(map[int]bool)
+	keep[startIdx] = true // signature line always kept
+	for i := startIdx; i < endIdx && i < len(lines); i++ {
+		lineDepthBefore := depth
+		for _, r := range lines[i] {
+			switch r {
+			case '{':
+				depth++
+			case '}':
+				depth--
+			}
+		}
+		if i <= braceLine {
+			continue
+		}
+		// Only consider lines inside the function body (not past its close).
+		if lineDepthBefore < 1 {
+			continue
+		}
+		trimmed := strings.TrimSpace(lines[i])
+		if trimmed == "" || trimmed == "}" {
+			continue
+		}
+		if strings.Contains(trimmed, "return") {
+			keep[i] = true
+			continue
+		}
+		for _, ident := range wantedIdents {
+			if strings.Contains(lines[i], ident) {
+				keep[i] = true
+				break
+			}
+		}
+	}
+
+	// Always keep the function's closing brace line for a recognizable end.
+	closeLine := endIdx - 1
+	for closeLine > startIdx && strings.TrimSpace(lines[closeLine]) == "" {
+		closeLine--
+	}
+	keep[closeLine] = true
+
+	var out []string
+	skipRun := 0
+	flushSkip := func() {
+		if skipRun > 0 {
+			out = append(out, fmt.Sprintf("// ... (elided %d lines)", skipRun))
+			skipRun = 0
+		}
+	}
+	for i := startIdx; i < endIdx && i < len(lines); i++ {
+		if keep[i] {
+			flushSkip()
+			out = append(out, lines[i])
+		} else {
+			skipRun++
+		}
+	}
+	flushSkip()
+
+	if len(out) == 0 || len(out) > maxLines {
+		return nil, false
+	}
+	return out, true
+}
+
+// renderFilesLikelyTouched groups code nodes by file and appends a ranked list.
+func renderFilesLikelyTouched(b *docBuilder, codeRows []contextRow) {
 	// Group by file path
 	fileNodes := make(map[string][]string) // file -> titles
 	for _, cr := range codeRows {
@@ -803,14 +1156,15 @@ func renderFilesLikelyTouched(md *strings.Builder, codeRows []contextRow) {
 		return len(entries[i].nodes) > len(entries[j].nodes)
 	})
 
-	md.WriteString("### Files Likely Touched\n\n")
-	md.WriteString("Ranked by number of relevant code nodes per file:\n\n")
+	b.heading(3, "Files Likely Touched")
+	b.para("Ranked by number of relevant code nodes per file:")
 
 	limit := 8
 	if len(entries) < limit {
 		limit = len(entries)
 	}
 
+	var items []string
 	for _, entry := range entries[:limit] {
 		// Show up to 3 node names
 		showCount := 3
@@ -829,22 +1183,157 @@ func renderFilesLikelyTouched(md *strings.Builder, codeRows []contextRow) {
 		if len(entry.nodes) > 3 {
 			suffix = fmt.Sprintf(" +%d more", len(entry.nodes)-3)
 		}
-		md.WriteString(fmt.Sprintf("1. **`%s`** (%d nodes) -- %s%s\n",
+		items = append(items, fmt.Sprintf("**`%s`** (%d nodes) -- %s%s",
 			entry.path, len(entry.nodes), strings.Join(names, ", "), suffix))
 	}
-	md.WriteString("\n")
+	b.list(items)
+}
+
+// callGraphEdges amortizes db.GetEdgesForNode lookups across the BFS walks
+// renderCallGraphWithDB runs for each function anchor -- anchors that share
+// neighbors (a common helper both call, for instance) only pay for one query.
+type callGraphEdges struct {
+	d     *db.DB
+	cache map[string][]db.Edge
+}
+
+func newCallGraphEdges(d *db.DB) *callGraphEdges {
+	return &callGraphEdges{d: d, cache: make(map[string][]db.Edge)}
+}
+
+func (c *callGraphEdges) edgesFor(nodeID string) []db.Edge {
+	if edges, ok := c.cache[nodeID]; ok {
+		return edges
+	}
+	edges, err := c.d.GetEdgesForNode(nodeID)
+	if err != nil {
+		edges = nil
+	}
+	c.cache[nodeID] = edges
+	return edges
+}
+
+// callGraphHop is one node discovered by walkCallGraph: its distance from the
+// anchor, a relevance score that decays with hop so a hop-1 callee outranks a
+// hop-3 caller, and its direct caller/callee edge counts for annotation.
+type callGraphHop struct {
+	nodeID      string
+	title       string
+	hop         int
+	relevance   float64
+	callerCount int
+	calleeCount int
+}
+
+// callGraphDecay is the per-hop relevance falloff: a hop-3 node scores
+// 0.6^3 = 0.216 against a hop-1 node's 0.6, so closer neighbors are favored
+// without discarding farther ones outright.
+const callGraphDecay = 0.6
+
+// walkCallGraph does a bounded BFS from nodeID following "calls" edges in
+// direction ("caller" walks edges targeting the current node, "callee" walks
+// edges sourced from it), up to maxHops hops and maxNodes discovered nodes.
+func walkCallGraph(edges *callGraphEdges, nodeID, direction string, maxHops, maxNodes int) []callGraphHop {
+	visited := map[string]bool{nodeID: true}
+	var out []callGraphHop
+	frontier := []string{nodeID}
+
+	for hop := 1; hop <= maxHops && len(out) < maxNodes && len(frontier) > 0; hop++ {
+		var next []string
+		for _, id := range frontier {
+			for _, e := range edges.edgesFor(id) {
+				if e.EdgeType != "calls" {
+					continue
+				}
+				var neighbor string
+				if direction == "caller" && e.TargetID == id {
+					neighbor = e.SourceID
+				} else if direction == "callee" && e.SourceID == id {
+					neighbor = e.TargetID
+				} else {
+					continue
+				}
+				if visited[neighbor] {
+					continue
+				}
+				visited[neighbor] = true
+
+				n, err := edges.d.GetNode(neighbor)
+				if err != nil || n == nil {
+					continue
+				}
+				callers, callees := directCallCounts(edges.edgesFor(neighbor), neighbor)
+				out = append(out, callGraphHop{
+					nodeID:      neighbor,
+					title:       n.Title,
+					hop:         hop,
+					relevance:   math.Pow(callGraphDecay, float64(hop)),
+					callerCount: callers,
+					calleeCount: callees,
+				})
+				next = append(next, neighbor)
+				if len(out) >= maxNodes {
+					return out
+				}
+			}
+		}
+		frontier = next
+	}
+	return out
 }
 
-// renderCallGraphWithDB renders the call graph section using actual DB edge lookups.
-func renderCallGraphWithDB(md *strings.Builder, d *db.DB, context []contextRow) {
-	// Find function code nodes (top 3)
+// directCallCounts counts nodeID's immediate "calls" callers and callees from
+// its already-fetched edge list, for the "(N callers, M callees)" annotation.
+func directCallCounts(edges []db.Edge, nodeID string) (callers, callees int) {
+	for _, e := range edges {
+		if e.EdgeType != "calls" {
+			continue
+		}
+		if e.TargetID == nodeID {
+			callers++
+		} else if e.SourceID == nodeID {
+			callees++
+		}
+	}
+	return callers, callees
+}
+
+// renderCallGraphWithDB renders the call graph section using a bounded BFS
+// over actual DB edge lookups, parameterized by config.CallGraphMaxHops/
+// CallGraphMaxNodes. Nodes already shown in the Graph Context table are
+// skipped to avoid duplication, and total output is capped so the section
+// stays a skim, not a dump.
+func renderCallGraphWithDB(b *docBuilder, d *db.DB, context []contextRow, config TaskFileConfig) {
+	maxHops := config.CallGraphMaxHops
+	if maxHops <= 0 {
+		maxHops = 3
+	}
+	maxNodes := config.CallGraphMaxNodes
+	if maxNodes <= 0 {
+		maxNodes = 15
+	}
+	const maxOutputLines = 20
+
+	inContext := make(map[string]bool, len(context))
+	for _, cr := range context {
+		inContext[cr.NodeID] = true
+	}
+
+	// Find function code nodes (top 3) to anchor the walk from.
 	var fnNodeIDs []string
 	var fnTitles []string
 	for _, cr := range context {
-		if !cr.IsCode || !isFunctionTitle(cr.Title) {
+		if !cr.IsCode {
 			continue
 		}
 		ct := parseCodeTags(cr.Tags)
+		lang := langFromExtension(ct.FilePath)
+		if ct.Language != "" {
+			lang = ct.Language
+		}
+		if !classifyTitle(cr.Title, lang).IsCallable() {
+			continue
+		}
 		lineRange := ct.EndLine - ct.StartLine
 		if lineRange < 3 || lineRange > 500 {
 			continue
@@ -860,101 +1349,50 @@ func renderCallGraphWithDB(md *strings.Builder, d *db.DB, context []contextRow)
 		return
 	}
 
+	edges := newCallGraphEdges(d)
 	var callLines []string
 	for i, nodeID := range fnNodeIDs {
-		edges, err := d.GetEdgesForNode(nodeID)
-		if err != nil {
-			continue
-		}
+		callers := walkCallGraph(edges, nodeID, "caller", maxHops, maxNodes)
+		callees := walkCallGraph(edges, nodeID, "callee", maxHops, maxNodes)
 
-		var callerNames, calleeNames []string
-		for _, e := range edges {
-			if e.EdgeType != "calls" {
-				continue
-			}
-			if e.TargetID == nodeID {
-				// This is a caller
-				if n, err := d.GetNode(e.SourceID); err == nil && n != nil {
-					name := n.Title
-					if len(name) > 30 {
-						name = name[:30]
-					}
-					callerNames = append(callerNames, fmt.Sprintf("`%s`", name))
-					if len(callerNames) >= 3 {
-						break
-					}
-				}
-			}
-		}
-		for _, e := range edges {
-			if e.EdgeType != "calls" {
-				continue
-			}
-			if e.SourceID == nodeID {
-				// This is a callee
-				if n, err := d.GetNode(e.TargetID); err == nil && n != nil {
-					name := n.Title
-					if len(name) > 30 {
-						name = name[:30]
-					}
-					calleeNames = append(calleeNames, fmt.Sprintf("`%s`", name))
-					if len(calleeNames) >= 3 {
-						break
-					}
-				}
-			}
-		}
-
-		if len(callerNames) == 0 && len(calleeNames) == 0 {
-			continue
-		}
+		sort.Slice(callers, func(a, b int) bool { return callers[a].relevance > callers[b].relevance })
+		sort.Slice(callees, func(a, b int) bool { return callees[a].relevance > callees[b].relevance })
 
 		fnTitle := fnTitles[i]
 		if len(fnTitle) > 40 {
 			fnTitle = fnTitle[:40]
 		}
-		line := fmt.Sprintf("- **`%s`**", fnTitle)
-		if len(callerNames) > 0 {
-			line += fmt.Sprintf(" -- called by: %s", strings.Join(callerNames, ", "))
-		}
-		if len(calleeNames) > 0 {
-			if len(callerNames) > 0 {
-				line += ";"
+
+		for _, hops := range [][]callGraphHop{callers, callees} {
+			for _, h := range hops {
+				if inContext[h.nodeID] {
+					continue
+				}
+				name := h.title
+				if len(name) > 30 {
+					name = name[:30]
+				}
+				callLines = append(callLines, fmt.Sprintf(
+					"**`%s`** -> `%s` (%d callers, %d callees, hop %d)",
+					fnTitle, name, h.callerCount, h.calleeCount, h.hop))
+				if len(callLines) >= maxOutputLines {
+					break
+				}
+			}
+			if len(callLines) >= maxOutputLines {
+				break
 			}
-			line += fmt.Sprintf(" calls: %s", strings.Join(calleeNames, ", "))
 		}
-		callLines = append(callLines, line)
-	}
-
-	if len(callLines) > 0 {
-		md.WriteString("### Call Graph\n\n")
-		md.WriteString("Who calls these functions and what do they call:\n\n")
-		for _, line := range callLines {
-			md.WriteString(line)
-			md.WriteString("\n")
+		if len(callLines) >= maxOutputLines {
+			break
 		}
-		md.WriteString("\n")
 	}
-}
 
-// isFunctionTitle checks if a code node title looks like a function definition.
-func isFunctionTitle(title string) bool {
-	t := strings.TrimSpace(title)
-	prefixes := []string{
-		"fn ", "pub fn ", "pub(crate) fn ",
-		"async fn ", "pub async fn ", "pub(crate) async fn ",
-		// Go
-		"func ",
-		// JS/TS
-		"function ", "export function ", "export default function ",
-		"async function ", "export async function ",
-	}
-	for _, p := range prefixes {
-		if strings.HasPrefix(t, p) {
-			return true
-		}
+	if len(callLines) > 0 {
+		b.heading(3, "Call Graph")
+		b.para(fmt.Sprintf("Callers and callees up to %d hops out, ranked by proximity:", maxHops))
+		b.list(callLines)
 	}
-	return false
 }
 
 // langFromExtension maps a file extension to a markdown code fence language.
@@ -46,6 +46,8 @@ func TestGenerateTaskFile_DryRun(t *testing.T) {
 		"", VerdictUnknown,
 		DefaultTaskFileConfig(),
 		tmpDir,
+		nil,
+		nil,
 	)
 	if err != nil {
 		t.Fatalf("GenerateTaskFile failed: %v", err)
@@ -85,7 +87,8 @@ func TestFindAnchors_WithDB(t *testing.T) {
 	d := openTestDB(t)
 	defer d.Close()
 
-	anchors, err := findAnchors(d, "clustering algorithm implementation", "nonexistent-task-id", DefaultTaskFileConfig())
+	provider := NewSQLiteRetrievalProvider(d, false, "nonexistent-task-id")
+	anchors, err := findAnchors(d, provider, "clustering algorithm implementation", "nonexistent-task-id", DefaultTaskFileConfig())
 	if err != nil {
 		t.Fatalf("findAnchors failed: %v", err)
 	}
@@ -105,7 +108,8 @@ func TestFindAnchors_EmptyQuery(t *testing.T) {
 	d := openTestDB(t)
 	defer d.Close()
 
-	anchors, err := findAnchors(d, "", "nonexistent-task-id", DefaultTaskFileConfig())
+	provider := NewSQLiteRetrievalProvider(d, false, "nonexistent-task-id")
+	anchors, err := findAnchors(d, provider, "", "nonexistent-task-id", DefaultTaskFileConfig())
 	if err != nil {
 		t.Fatalf("findAnchors with empty query should not error: %v", err)
 	}
@@ -125,13 +129,13 @@ func TestRenderMarkdown_Basic(t *testing.T) {
 	md := renderMarkdown(nil, "implement the search feature", RoleCoder,
 		"run12345678", "task-node-id",
 		0, 3, "", VerdictUnknown,
-		nil, context, lessons)
+		nil, context, lessons, DefaultTaskFileConfig())
 
 	requiredSections := []string{
 		"# Task: implement the search feature",
 		"## Task",
 		"## Graph Context",
-		"| # | Node | ID | Relevance | Via |",
+		"| # | Node | ID | Relevance | Stale | Via |",
 		"| 1 | TestFunction",
 		"| 2 | AnotherNode",
 		"85%",
@@ -148,11 +152,43 @@ func TestRenderMarkdown_Basic(t *testing.T) {
 	}
 }
 
+func TestRenderMarkdown_StaleAnnotation(t *testing.T) {
+	context := []contextRow{
+		{Rank: 1, NodeID: "fresh1234567", Title: "FreshNode", Relevance: 0.90, Via: "direct", Anchor: "search", Stale: false},
+		{Rank: 2, NodeID: "stale1234567", Title: "StaleNode", Relevance: 0.40, Via: "direct", Anchor: "search", Stale: true},
+	}
+
+	md := renderMarkdown(nil, "refresh the docs", RoleCoder,
+		"run12345678", "task-node-id",
+		0, 3, "", VerdictUnknown,
+		nil, context, nil, DefaultTaskFileConfig())
+
+	lines := strings.Split(md, "\n")
+	var freshLine, staleLine string
+	for _, l := range lines {
+		if strings.Contains(l, "FreshNode") {
+			freshLine = l
+		}
+		if strings.Contains(l, "StaleNode") {
+			staleLine = l
+		}
+	}
+	if freshLine == "" || staleLine == "" {
+		t.Fatalf("expected both rows to render, fresh=%q stale=%q", freshLine, staleLine)
+	}
+	if strings.Contains(freshLine, "⚠") {
+		t.Errorf("fresh row should not carry the stale annotation: %q", freshLine)
+	}
+	if !strings.Contains(staleLine, "⚠") {
+		t.Errorf("stale row should carry the stale annotation: %q", staleLine)
+	}
+}
+
 func TestRenderMarkdown_Verifier(t *testing.T) {
 	md := renderMarkdown(nil, "verify the implementation", RoleVerifier,
 		"run12345678", "task-node-id",
 		0, 3, "impl-node-12345678", VerdictUnknown,
-		nil, nil, nil)
+		nil, nil, nil, DefaultTaskFileConfig())
 
 	if !strings.Contains(md, "## Implementation to Check") {
 		t.Error("verifier task file should contain 'Implementation to Check' section")
@@ -170,7 +206,7 @@ func TestRenderMarkdown_Summarizer(t *testing.T) {
 	md := renderMarkdown(nil, "summarize the changes", RoleSummarizer,
 		"run12345678", "task-node-id",
 		0, 3, "impl-node-12345678", VerdictSupports,
-		nil, nil, nil)
+		nil, nil, nil, DefaultTaskFileConfig())
 
 	if !strings.Contains(md, "## Implementation to Summarize") {
 		t.Error("summarizer task file should contain 'Implementation to Summarize' section")
@@ -184,7 +220,7 @@ func TestRenderMarkdown_Bounce(t *testing.T) {
 	md := renderMarkdown(nil, "fix the bug again", RoleCoder,
 		"run12345678", "task-node-id",
 		1, 3, "impl-node-failed", VerdictContradicts,
-		nil, nil, nil)
+		nil, nil, nil, DefaultTaskFileConfig())
 
 	if !strings.Contains(md, "## Previous Bounce") {
 		t.Error("bounce task file should contain 'Previous Bounce' section")
@@ -204,7 +240,7 @@ func TestRenderMarkdown_BounceUnknownVerdict(t *testing.T) {
 	md := renderMarkdown(nil, "fix the bug again", RoleCoder,
 		"run12345678", "task-node-id",
 		1, 3, "impl-node-failed", VerdictUnknown,
-		nil, nil, nil)
+		nil, nil, nil, DefaultTaskFileConfig())
 
 	if !strings.Contains(md, "could not parse a verdict") {
 		t.Error("bounce with unknown verdict should mention parse failure")
@@ -215,7 +251,7 @@ func TestRenderMarkdown_EmptyContext(t *testing.T) {
 	md := renderMarkdown(nil, "do something", RoleCoder,
 		"run12345678", "task-node-id",
 		0, 3, "", VerdictUnknown,
-		nil, nil, nil)
+		nil, nil, nil, DefaultTaskFileConfig())
 
 	if !strings.Contains(md, "_No relevant nodes found in the graph._") {
 		t.Error("empty context should show 'no relevant nodes' message")
@@ -256,7 +292,7 @@ func TestRenderMarkdown_CodeSnippets(t *testing.T) {
 	md := renderMarkdown(nil, "update the server", RoleCoder,
 		"run12345678", "task-node-id",
 		0, 3, "", VerdictUnknown,
-		nil, codeContext, nil)
+		nil, codeContext, nil, DefaultTaskFileConfig())
 
 	// Should have Code Locations
 	if !strings.Contains(md, "### Code Locations") {
@@ -280,7 +316,7 @@ func TestFindLessons_NoEmbedding(t *testing.T) {
 	defer d.Close()
 
 	// Use a non-existent task node ID so there's no embedding
-	lessons := findLessons(d, "some task", "nonexistent-node-id", DefaultTaskFileConfig())
+	lessons := findLessons(d, "some task", "nonexistent-node-id", DefaultTaskFileConfig(), NewMemoryLessonStore())
 	// Should not panic; may return empty or recency-based results
 	t.Logf("found %d lessons for non-existent node", len(lessons))
 }
@@ -422,3 +458,59 @@ More stuff.`
 		t.Errorf("expected empty for missing section, got %q", missing)
 	}
 }
+
+func TestAstSnippetWindow_KeepsSignatureReturnsAndRelatedIdentifiers(t *testing.T) {
+	src := `func handleRequest(req *Request) (*Response, error) {
+	logRequest(req)
+	if req == nil {
+		return nil, errNilRequest
+	}
+	parsed := parseBody(req)
+	validateConfig(parsed)
+	result := process(parsed)
+	return result, nil
+}`
+	lines := strings.Split(src, "\n")
+
+	snippet, ok := astSnippetWindow(lines, 0, len(lines), []string{"validateConfig"}, 30)
+	if !ok {
+		t.Fatalf("expected astSnippetWindow to succeed on brace-delimited source")
+	}
+	joined := strings.Join(snippet, "\n")
+
+	if !strings.Contains(joined, "func handleRequest") {
+		t.Error("expected the signature line to be kept")
+	}
+	if !strings.Contains(joined, "return nil, errNilRequest") || !strings.Contains(joined, "return result, nil") {
+		t.Error("expected every return statement to be kept")
+	}
+	if !strings.Contains(joined, "validateConfig(parsed)") {
+		t.Error("expected the line mentioning a related identifier to be kept")
+	}
+	if strings.Contains(joined, "logRequest(req)") {
+		t.Error("expected an unrelated statement to be elided")
+	}
+	if !strings.Contains(joined, "elided") {
+		t.Error("expected an elision marker for the skipped statement(s)")
+	}
+}
+
+func TestAstSnippetWindow_FallsBackWhenNoBraceFound(t *testing.T) {
+	lines := []string{"def handle_request(req):", "    return req.body"}
+	if _, ok := astSnippetWindow(lines, 0, len(lines), nil, 30); ok {
+		t.Error("expected astSnippetWindow to report no match for brace-less source")
+	}
+}
+
+func TestExtractTitleIdentifiers_SkipsKeywords(t *testing.T) {
+	got := extractTitleIdentifiers("pub fn validate_config")
+	want := map[string]bool{"validate_config": true}
+	for _, tok := range got {
+		if !want[tok] {
+			t.Errorf("unexpected identifier %q extracted from title with keywords", tok)
+		}
+	}
+	if len(got) != 1 {
+		t.Errorf("expected exactly one non-keyword identifier, got %v", got)
+	}
+}
@@ -0,0 +1,205 @@
+package orchestrate
+
+import (
+	"fmt"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Duration wraps time.Duration so a task manifest can write a plain string
+// like "15m" instead of nanoseconds.
+type Duration struct {
+	time.Duration
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, which yaml.v3 calls for
+// any scalar node whose Go type implements it.
+func (d *Duration) UnmarshalText(text []byte) error {
+	parsed, err := time.ParseDuration(string(text))
+	if err != nil {
+		return fmt.Errorf("parsing duration %q: %w", text, err)
+	}
+	d.Duration = parsed
+	return nil
+}
+
+// Task is one unit of work dispatched by RunLoop. It is built either from a
+// structured YAML manifest entry (see ReadTaskFile) or, for the legacy
+// plain-line/"---"-delimited formats, from a TaskSpec -- in which case ID
+// falls back to taskHash(Body) since those formats have no natural
+// identifier and resume state must survive edits to the prose.
+type Task struct {
+	ID         string
+	Body       string
+	Name       string // legacy "name:" header, or equal to ID for manifest tasks
+	Tags       []string
+	DependsOn  []string
+	BudgetUSD  float64       // 0 means no per-task cap
+	Timeout    time.Duration // 0 means no per-task deadline
+	MaxRetries int
+
+	// orch_overrides, applied on top of LoopConfig.OrchConfig for this task
+	// only; zero values mean "use the loop-wide default".
+	MaxBounces int
+	MaxTurns   int
+	CoderModel string
+}
+
+// manifestTask is the on-disk YAML shape of one task manifest entry.
+type manifestTask struct {
+	ID         string             `yaml:"id"`
+	Prompt     string             `yaml:"prompt"`
+	DependsOn  []string           `yaml:"depends_on"`
+	BudgetUSD  float64            `yaml:"budget_usd"`
+	Timeout    Duration           `yaml:"timeout"`
+	MaxRetries int                `yaml:"max_retries"`
+	Tags       []string           `yaml:"tags"`
+	Overrides  *manifestOverrides `yaml:"orch_overrides"`
+}
+
+type manifestOverrides struct {
+	MaxBounces int    `yaml:"max_bounces"`
+	MaxTurns   int    `yaml:"max_turns"`
+	Model      string `yaml:"model"`
+}
+
+// ReadTaskFile reads tasks from source (a file path or "-" for stdin),
+// auto-detecting a structured YAML task manifest (a top-level list of
+// entries with at least "id" and "prompt") versus the legacy plain-line or
+// "---"-delimited formats handled by ReadTasks/parseTaskHeader.
+func ReadTaskFile(source string) ([]Task, error) {
+	content, err := readSource(source)
+	if err != nil {
+		return nil, err
+	}
+
+	if manifest, ok := tryParseManifest(content); ok {
+		tasks, err := manifestToTasks(manifest)
+		if err != nil {
+			return nil, fmt.Errorf("task manifest '%s': %w", source, err)
+		}
+		return tasks, nil
+	}
+
+	lines := parseTaskContent(string(content))
+	if len(lines) == 0 {
+		return nil, fmt.Errorf("no tasks found in '%s' (blank lines and # comments ignored)", source)
+	}
+	tasks := make([]Task, 0, len(lines))
+	for _, line := range lines {
+		spec := parseTaskHeader(line)
+		tasks = append(tasks, Task{
+			ID:   taskHash(spec.Text),
+			Body: spec.Text,
+			Name: spec.Name,
+			Tags: spec.Tags,
+		})
+	}
+	return tasks, nil
+}
+
+// tryParseManifest attempts to parse content as a YAML task manifest (a
+// top-level list of entries). It requires every entry to carry a non-empty
+// id and prompt, so plain prose that happens to be syntactically valid YAML
+// (e.g. a single "---"-delimited legacy task) is rejected rather than
+// silently misread as a one-task manifest.
+func tryParseManifest(content []byte) ([]manifestTask, bool) {
+	var entries []manifestTask
+	if err := yaml.Unmarshal(content, &entries); err != nil {
+		return nil, false
+	}
+	if len(entries) == 0 {
+		return nil, false
+	}
+	for _, e := range entries {
+		if e.ID == "" || e.Prompt == "" {
+			return nil, false
+		}
+	}
+	return entries, true
+}
+
+// manifestToTasks validates and converts parsed manifest entries into Tasks,
+// rejecting duplicate IDs and depends_on references to unknown IDs up front
+// so RunLoop doesn't discover them mid-run.
+func manifestToTasks(entries []manifestTask) ([]Task, error) {
+	ids := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		if ids[e.ID] {
+			return nil, fmt.Errorf("duplicate task id %q", e.ID)
+		}
+		ids[e.ID] = true
+	}
+
+	tasks := make([]Task, 0, len(entries))
+	for _, e := range entries {
+		for _, dep := range e.DependsOn {
+			if !ids[dep] {
+				return nil, fmt.Errorf("task %q depends_on unknown id %q", e.ID, dep)
+			}
+		}
+		task := Task{
+			ID:         e.ID,
+			Body:       e.Prompt,
+			Name:       e.ID,
+			Tags:       e.Tags,
+			DependsOn:  e.DependsOn,
+			BudgetUSD:  e.BudgetUSD,
+			Timeout:    e.Timeout.Duration,
+			MaxRetries: e.MaxRetries,
+		}
+		if e.Overrides != nil {
+			task.MaxBounces = e.Overrides.MaxBounces
+			task.MaxTurns = e.Overrides.MaxTurns
+			task.CoderModel = e.Overrides.Model
+		}
+		tasks = append(tasks, task)
+	}
+	return tasks, nil
+}
+
+// topoSort orders tasks so every task appears after all of its depends_on,
+// for the --dag dry-run plan. Ties are broken by input order. Returns an
+// error if depends_on forms a cycle.
+func topoSort(tasks []Task) ([]Task, error) {
+	byID := make(map[string]Task, len(tasks))
+	for _, t := range tasks {
+		byID[t.ID] = t
+	}
+
+	const (
+		unvisited = 0
+		visiting  = 1
+		visited   = 2
+	)
+	state := make(map[string]int, len(tasks))
+	ordered := make([]Task, 0, len(tasks))
+
+	var visit func(id string) error
+	visit = func(id string) error {
+		switch state[id] {
+		case visited:
+			return nil
+		case visiting:
+			return fmt.Errorf("depends_on cycle detected at task %q", id)
+		}
+		state[id] = visiting
+		t := byID[id]
+		for _, dep := range t.DependsOn {
+			if err := visit(dep); err != nil {
+				return err
+			}
+		}
+		state[id] = visited
+		ordered = append(ordered, t)
+		return nil
+	}
+
+	for _, t := range tasks {
+		if err := visit(t.ID); err != nil {
+			return nil, err
+		}
+	}
+	return ordered, nil
+}
@@ -0,0 +1,151 @@
+// Package taskmatch implements a `go test -run`-style task selector:
+// a slash-separated list of regexes, one per segment, matched against a
+// task's name and its sub-selectors (tags, or a numbered subtask index).
+// A leading '!' on a segment negates that segment's match.
+package taskmatch
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// segment is one compiled `/`-delimited piece of a pattern.
+type segment struct {
+	re     *regexp.Regexp
+	negate bool
+}
+
+// Pattern is a compiled selector, e.g. "refactor/.*sqlite" or "!wip/.*".
+type Pattern struct {
+	raw      string
+	segments []segment
+}
+
+var (
+	cacheMu sync.Mutex
+	cache   = map[string]*Pattern{}
+)
+
+// Compile parses and compiles pattern, caching the result so repeated calls
+// with the same pattern string are cheap.
+func Compile(pattern string) (*Pattern, error) {
+	cacheMu.Lock()
+	if p, ok := cache[pattern]; ok {
+		cacheMu.Unlock()
+		return p, nil
+	}
+	cacheMu.Unlock()
+
+	var segs []segment
+	for _, part := range strings.Split(pattern, "/") {
+		negate := false
+		if strings.HasPrefix(part, "!") {
+			negate = true
+			part = part[1:]
+		}
+		re, err := regexp.Compile(part)
+		if err != nil {
+			return nil, fmt.Errorf("taskmatch: invalid segment %q in pattern %q: %w", part, pattern, err)
+		}
+		segs = append(segs, segment{re: re, negate: negate})
+	}
+	p := &Pattern{raw: pattern, segments: segs}
+
+	cacheMu.Lock()
+	cache[pattern] = p
+	cacheMu.Unlock()
+	return p, nil
+}
+
+// String returns the original pattern text.
+func (p *Pattern) String() string { return p.raw }
+
+// Match reports whether name and subs satisfy the pattern: segment 0 is
+// matched against name, segment 1 against subs[0], segment 2 against
+// subs[1], and so on. A pattern with more segments than available fields
+// (name + subs) fails to match. A negated segment matches when its regexp
+// does NOT match the field.
+func (p *Pattern) Match(name string, subs ...string) bool {
+	fields := append([]string{name}, subs...)
+	if len(p.segments) > len(fields) {
+		return false
+	}
+	for i, seg := range p.segments {
+		matched := seg.re.MatchString(fields[i])
+		if seg.negate {
+			matched = !matched
+		}
+		if !matched {
+			return false
+		}
+	}
+	return true
+}
+
+// matchLiteral reports whether name/subs satisfy p's segments, testing each
+// segment's regexp directly and ignoring any leading '!'. Used for --skip
+// patterns: Selects already treats "skip pattern matches" as "reject this
+// task", so a skip pattern's own '!' would otherwise be inverted twice --
+// once by Match's negate handling, once by Selects -- exactly cancelling
+// out the negation the caller wrote. Run patterns don't have this problem
+// since there's exactly one inversion (Match's), so they keep using Match.
+func (p *Pattern) matchLiteral(name string, subs ...string) bool {
+	fields := append([]string{name}, subs...)
+	if len(p.segments) > len(fields) {
+		return false
+	}
+	for i, seg := range p.segments {
+		if !seg.re.MatchString(fields[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// Selector is a --run/--skip pair: a task is selected when it matches run
+// (or run is nil) and does not match skip (or skip is nil).
+type Selector struct {
+	Run  *Pattern
+	Skip *Pattern
+}
+
+// NewSelector compiles runPattern/skipPattern, either of which may be empty
+// to mean "no constraint". A leading '!' on a skipPattern segment has no
+// effect (see Pattern.matchLiteral) -- skip patterns are already an
+// exclusion list, so negating a segment there reads naturally as "skip
+// iff this segment matches", same as without the '!'.
+func NewSelector(runPattern, skipPattern string) (*Selector, error) {
+	var sel Selector
+	if runPattern != "" {
+		p, err := Compile(runPattern)
+		if err != nil {
+			return nil, err
+		}
+		sel.Run = p
+	}
+	if skipPattern != "" {
+		p, err := Compile(skipPattern)
+		if err != nil {
+			return nil, err
+		}
+		sel.Skip = p
+	}
+	return &sel, nil
+}
+
+// Selects reports whether a task with the given name and sub-selectors
+// (tags, subtask index, ...) should be dispatched.
+func (s *Selector) Selects(name string, subs ...string) bool {
+	if s == nil {
+		return true
+	}
+	if s.Run != nil && !s.Run.Match(name, subs...) {
+		return false
+	}
+	if s.Skip != nil && s.Skip.matchLiteral(name, subs...) {
+		return false
+	}
+	return true
+}
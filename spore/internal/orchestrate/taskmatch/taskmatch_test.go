@@ -0,0 +1,86 @@
+package taskmatch
+
+import "testing"
+
+func TestPattern_Match(t *testing.T) {
+	p, err := Compile("refactor/.*sqlite")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if !p.Match("refactor", "uses-sqlite") {
+		t.Error("expected match on refactor/uses-sqlite")
+	}
+	if p.Match("refactor", "uses-postgres") {
+		t.Error("expected no match on refactor/uses-postgres")
+	}
+	if p.Match("cleanup", "uses-sqlite") {
+		t.Error("expected no match when name segment fails")
+	}
+}
+
+func TestPattern_Negate(t *testing.T) {
+	p, err := Compile("!wip")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if p.Match("wip") {
+		t.Error("expected negated segment to exclude wip")
+	}
+	if !p.Match("ready") {
+		t.Error("expected negated segment to allow ready")
+	}
+}
+
+func TestPattern_TooFewFields(t *testing.T) {
+	p, err := Compile("a/b/c")
+	if err != nil {
+		t.Fatalf("Compile: %v", err)
+	}
+	if p.Match("a", "b") {
+		t.Error("expected no match when fewer fields than segments are supplied")
+	}
+}
+
+func TestSelector_Selects(t *testing.T) {
+	sel, err := NewSelector("refactor.*", "!wip")
+	if err != nil {
+		t.Fatalf("NewSelector: %v", err)
+	}
+	if !sel.Selects("refactor-db") {
+		t.Error("expected refactor-db to be selected")
+	}
+	if sel.Selects("other") {
+		t.Error("expected other to be rejected by run pattern")
+	}
+
+	sel2, err := NewSelector("", "wip")
+	if err != nil {
+		t.Fatalf("NewSelector: %v", err)
+	}
+	if sel2.Selects("wip-task") {
+		t.Error("expected wip-task to be skipped")
+	}
+	if !sel2.Selects("anything-else") {
+		t.Error("expected anything-else to be selected when only --skip is set")
+	}
+}
+
+func TestSelector_SkipNegationHasNoEffect(t *testing.T) {
+	sel, err := NewSelector("", "!wip")
+	if err != nil {
+		t.Fatalf("NewSelector: %v", err)
+	}
+	if sel.Selects("wip-task") {
+		t.Error("expected a '!'-prefixed skip segment to skip wip-task, same as without the '!'")
+	}
+	if !sel.Selects("anything-else") {
+		t.Error("expected anything-else to still be selected")
+	}
+}
+
+func TestSelector_Nil(t *testing.T) {
+	var sel *Selector
+	if !sel.Selects("anything") {
+		t.Error("nil selector should select everything")
+	}
+}
@@ -0,0 +1,120 @@
+package orchestrate
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/osfs"
+	"github.com/go-git/go-git/v5/plumbing/format/gitattributes"
+	"github.com/go-git/go-git/v5/plumbing/format/gitignore"
+)
+
+// mycelicaIgnoreFile is an additional, repo-root-only exclude file in
+// .gitignore syntax, for build artifacts and generated files agents
+// shouldn't be judged on that users don't want to commit to the project's
+// real .gitignore.
+const mycelicaIgnoreFile = ".mycelica-ignore"
+
+// mycelicaTrackAttribute is the gitattributes macro CaptureGitState checks
+// to let users override pattern-based exclusion per path: set it to force
+// tracking a path despite .gitignore/.mycelica-ignore, or unset it to
+// exclude a path those files didn't catch.
+const mycelicaTrackAttribute = "mycelica-track"
+
+// TrackingFilter decides whether CaptureGitState should consider a path for
+// Dirty, Untracked, or Hashes. It layers .gitignore and .mycelica-ignore
+// exclude patterns under the mycelica-track gitattribute, which always wins
+// when set on a path.
+type TrackingFilter struct {
+	ignore gitignore.Matcher
+	attrs  gitattributes.Matcher
+}
+
+// NewTrackingFilter builds a TrackingFilter for the repo rooted at fs,
+// reading .gitignore and .mycelica-ignore patterns and the mycelica-track
+// gitattribute the same way go-git's own ReadPatterns walk the tree, so
+// nested .gitignore/.gitattributes files are respected too.
+func NewTrackingFilter(fs billy.Filesystem) (*TrackingFilter, error) {
+	patterns, err := gitignore.ReadPatterns(fs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("reading .gitignore patterns: %w", err)
+	}
+	extra, err := readMycelicaIgnore(fs)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", mycelicaIgnoreFile, err)
+	}
+	patterns = append(patterns, extra...)
+
+	attrs, err := gitattributes.ReadPatterns(fs, nil)
+	if err != nil {
+		return nil, fmt.Errorf("reading .gitattributes: %w", err)
+	}
+
+	return &TrackingFilter{
+		ignore: gitignore.NewMatcher(patterns),
+		attrs:  gitattributes.NewMatcher(attrs),
+	}, nil
+}
+
+// NewTrackingFilterAt is a convenience wrapper for the common case of a
+// filter rooted directly at a directory on disk.
+func NewTrackingFilterAt(repoDir string) (*TrackingFilter, error) {
+	return NewTrackingFilter(osfs.New(repoDir))
+}
+
+// readMycelicaIgnore parses .mycelica-ignore with gitignore's own pattern
+// syntax (negation, directory anchors, ** included) so it behaves exactly
+// like a second .gitignore rather than a bespoke format. A missing file is
+// not an error: most repos won't have one.
+func readMycelicaIgnore(fs billy.Filesystem) ([]gitignore.Pattern, error) {
+	f, err := fs.Open(mycelicaIgnoreFile)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var patterns []gitignore.Pattern
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, gitignore.ParsePattern(line, nil))
+	}
+	return patterns, scanner.Err()
+}
+
+// Tracked reports whether path (repo-root-relative, forward-slash
+// separated) should be considered for change tracking. mycelica-track set
+// on the path always tracks it; unset always excludes it; unspecified (or
+// no match at all) falls through to the .gitignore/.mycelica-ignore
+// patterns.
+//
+// Note: CaptureGitState also feeds .gitignore into the worktree's own
+// Excludes before calling Status, so a path .gitignore hides never reaches
+// Tracked at all — mycelica-track's override power in practice applies to
+// the additional .mycelica-ignore layer this filter owns, not to undoing
+// .gitignore's exclusion of the underlying directory walk.
+func (f *TrackingFilter) Tracked(path string) bool {
+	parts := strings.Split(path, "/")
+
+	if results, matched := f.attrs.Match(parts, []string{mycelicaTrackAttribute}); matched {
+		if attr, ok := results[mycelicaTrackAttribute]; ok {
+			if attr.IsSet() {
+				return true
+			}
+			if attr.IsUnset() {
+				return false
+			}
+		}
+	}
+
+	return !f.ignore.Match(parts, false)
+}
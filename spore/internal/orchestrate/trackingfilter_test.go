@@ -0,0 +1,91 @@
+package orchestrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// A .mycelica-ignore pattern must keep CaptureGitState from hashing a whole
+// excluded subtree, the same way .gitignore does, while still respecting a
+// negation that opts one file back in.
+func TestCaptureGitState_GoGit_RespectsMycelicaIgnore(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	if err := os.WriteFile(filepath.Join(repoDir, ".mycelica-ignore"), []byte("node_modules/**\n!node_modules/important.js\n"), 0644); err != nil {
+		t.Fatalf("writing .mycelica-ignore: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, "node_modules", "some-pkg"), 0755); err != nil {
+		t.Fatalf("mkdir node_modules/some-pkg: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "node_modules", "some-pkg", "index.js"), []byte("noise\n"), 0644); err != nil {
+		t.Fatalf("writing node_modules/some-pkg/index.js: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "node_modules", "important.js"), []byte("keep me\n"), 0644); err != nil {
+		t.Fatalf("writing node_modules/important.js: %v", err)
+	}
+
+	state, err := CaptureGitState(repoDir)
+	if err != nil {
+		t.Fatalf("CaptureGitState: %v", err)
+	}
+	if state.Untracked["node_modules/some-pkg/index.js"] {
+		t.Errorf("expected node_modules/some-pkg/index.js to be excluded, got %v", state.Untracked)
+	}
+	if !state.Untracked["node_modules/important.js"] {
+		t.Errorf("expected the !important.js negation to still track node_modules/important.js, got %v", state.Untracked)
+	}
+}
+
+// mycelica-track set on a path in .gitattributes must force tracking even
+// when .mycelica-ignore would otherwise exclude it. (.gitignore exclusion
+// is handled upstream by go-git's own worktree Excludes, which skip ignored
+// paths before they ever reach Status — that's the "already handled" case
+// the request calls out; mycelica-track's override applies to the
+// additional .mycelica-ignore layer this filter owns.)
+func TestCaptureGitState_GoGit_AttributeOverridesMycelicaIgnore(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	if err := os.WriteFile(filepath.Join(repoDir, ".mycelica-ignore"), []byte("dist/**\n"), 0644); err != nil {
+		t.Fatalf("writing .mycelica-ignore: %v", err)
+	}
+	if err := os.MkdirAll(filepath.Join(repoDir, "dist"), 0755); err != nil {
+		t.Fatalf("mkdir dist: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, "dist", "bundle.js"), []byte("built\n"), 0644); err != nil {
+		t.Fatalf("writing dist/bundle.js: %v", err)
+	}
+	if err := os.WriteFile(filepath.Join(repoDir, ".gitattributes"), []byte("dist/bundle.js mycelica-track\n"), 0644); err != nil {
+		t.Fatalf("writing .gitattributes: %v", err)
+	}
+
+	state, err := CaptureGitState(repoDir)
+	if err != nil {
+		t.Fatalf("CaptureGitState: %v", err)
+	}
+	if !state.Untracked["dist/bundle.js"] {
+		t.Errorf("expected mycelica-track to override .mycelica-ignore for dist/bundle.js, got %v", state.Untracked)
+	}
+}
+
+// TrackingFilter must walk nested directories the way gitignore.ReadPatterns
+// does, so a .mycelica-ignore rule anchored at repo root still excludes
+// files several directories deep.
+func TestTrackingFilter_WalksNestedPaths(t *testing.T) {
+	repoDir := initTestRepo(t)
+
+	if err := os.WriteFile(filepath.Join(repoDir, ".mycelica-ignore"), []byte("build/**\n"), 0644); err != nil {
+		t.Fatalf("writing .mycelica-ignore: %v", err)
+	}
+
+	filter, err := NewTrackingFilterAt(repoDir)
+	if err != nil {
+		t.Fatalf("NewTrackingFilterAt: %v", err)
+	}
+	if filter.Tracked("build/sub/dir/output.o") {
+		t.Errorf("expected build/sub/dir/output.o to be excluded")
+	}
+	if !filter.Tracked("src/main.go") {
+		t.Errorf("expected src/main.go to remain tracked")
+	}
+}
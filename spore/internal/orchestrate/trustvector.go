@@ -0,0 +1,335 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"mycelica/spore/internal/db"
+)
+
+// TrustTier is one tier of the AR4SI-inspired attestation scale, ordered
+// worst-to-best by increasing value so a rolled-up tier is just a max().
+type TrustTier int
+
+const (
+	TierContraindicated TrustTier = iota // claim actively fails
+	TierWarning                          // claim is suspect but not a hard failure
+	TierNone                             // no claim made / insufficient evidence
+	TierAffirming                        // claim passes
+)
+
+func (t TrustTier) String() string {
+	switch t {
+	case TierAffirming:
+		return "affirming"
+	case TierWarning:
+		return "warning"
+	case TierContraindicated:
+		return "contraindicated"
+	default:
+		return "none"
+	}
+}
+
+// TrustClaim is one category's attestation, e.g. {Category: "security", Tier: TierWarning}.
+type TrustClaim struct {
+	Category string    `json:"category"`
+	Tier     TrustTier `json:"tier"`
+	Reason   string    `json:"reason,omitempty"`
+}
+
+// TrustVector carries a verifier's per-category claims plus the rolled-up
+// tier (the worst tier across all claims).
+type TrustVector struct {
+	Claims map[string]TrustClaim `json:"claims"`
+	Tier   TrustTier             `json:"tier"`
+}
+
+// ToTrustClaim converts a raw claim value (string, int, or float64, as decoded
+// from JSON) into a TrustClaim for category. Unrecognized values map to TierNone.
+func ToTrustClaim(category string, v any) TrustClaim {
+	claim := TrustClaim{Category: category, Tier: TierNone}
+	switch val := v.(type) {
+	case string:
+		claim.Tier = trustTierFromString(val)
+	case float64:
+		claim.Tier = trustTierFromScore(val)
+	case int:
+		claim.Tier = trustTierFromScore(float64(val))
+	case json.Number:
+		if f, err := val.Float64(); err == nil {
+			claim.Tier = trustTierFromScore(f)
+		}
+	default:
+		if s, err := strconv.ParseFloat(fmt.Sprint(val), 64); err == nil {
+			claim.Tier = trustTierFromScore(s)
+		} else {
+			claim.Tier = trustTierFromString(fmt.Sprint(val))
+		}
+	}
+	return claim
+}
+
+func trustTierFromString(s string) TrustTier {
+	switch strings.ToLower(strings.TrimSpace(s)) {
+	case "pass", "affirm", "affirming", "supports", "true":
+		return TierAffirming
+	case "warn", "warning", "caution":
+		return TierWarning
+	case "fail", "contradicts", "contraindicated", "false":
+		return TierContraindicated
+	default:
+		return TierNone
+	}
+}
+
+// trustTierFromScore maps a numeric claim (e.g. a 0-1 confidence-style score)
+// to a tier: >=0.7 affirming, >=0.4 warning, else contraindicated.
+func trustTierFromScore(score float64) TrustTier {
+	switch {
+	case score >= 0.7:
+		return TierAffirming
+	case score >= 0.4:
+		return TierWarning
+	default:
+		return TierContraindicated
+	}
+}
+
+// rollUp computes the worst tier across all claims (TierNone if empty).
+func (tv *TrustVector) rollUp() TrustTier {
+	worst := TierNone
+	first := true
+	for _, c := range tv.Claims {
+		if first || c.Tier < worst {
+			worst = c.Tier
+			first = false
+		}
+	}
+	return worst
+}
+
+// UpdateStatusFromTrustVector maps a rolled-up TrustVector back onto the
+// legacy binary Verdict, for callers that don't yet understand trust vectors.
+func UpdateStatusFromTrustVector(tv *TrustVector) Verdict {
+	if tv == nil || len(tv.Claims) == 0 {
+		return VerdictUnknown
+	}
+	switch tv.Tier {
+	case TierAffirming, TierNone:
+		return VerdictSupports
+	default:
+		return VerdictContradicts
+	}
+}
+
+// trustVectorJSON is the extended `{"claims": {...}}` JSON schema.
+type trustVectorJSON struct {
+	Claims map[string]any `json:"claims"`
+}
+
+// ParseTrustVectorJSON looks for a `<verdict>{"claims": {...}}</verdict>`
+// block (or bare `{"claims": {...}}`) in verifier output and builds a
+// TrustVector from it. Returns nil if no claims block is found.
+func ParseTrustVectorJSON(text string) *TrustVector {
+	m := verdictTagRe.FindStringSubmatch(text)
+	raw := ""
+	if len(m) == 2 {
+		raw = m[1]
+	} else if idx := strings.Index(text, `"claims"`); idx >= 0 {
+		// Best-effort: find the enclosing object by scanning backward/forward
+		// for braces around the "claims" key.
+		start := strings.LastIndex(text[:idx], "{")
+		end := strings.Index(text[idx:], "}")
+		if start >= 0 && end >= 0 {
+			// Find the matching outer close brace, not the first inner one.
+			depth := 0
+			for i := start; i < len(text); i++ {
+				switch text[i] {
+				case '{':
+					depth++
+				case '}':
+					depth--
+					if depth == 0 {
+						raw = text[start : i+1]
+					}
+				}
+				if raw != "" {
+					break
+				}
+			}
+		}
+	}
+	if raw == "" {
+		return nil
+	}
+
+	var parsed trustVectorJSON
+	if err := json.Unmarshal([]byte(raw), &parsed); err != nil || len(parsed.Claims) == 0 {
+		return nil
+	}
+
+	tv := &TrustVector{Claims: make(map[string]TrustClaim, len(parsed.Claims))}
+	for category, v := range parsed.Claims {
+		tv.Claims[category] = ToTrustClaim(category, v)
+	}
+	tv.Tier = tv.rollUp()
+	return tv
+}
+
+// trustMetadataJSON is the `{"tier": "..."}` shape PersistTrustVectorSummary
+// stores on a claims_<category> edge's Metadata, so the tier round-trips
+// exactly instead of being reconstructed from a lossy float Confidence
+// bucket -- TierWarning and TierNone both persist as confidence 0.5 (see
+// PersistTrustVectorSummary), which trustTierFromScore alone can't tell apart.
+type trustMetadataJSON struct {
+	Tier string `json:"tier"`
+}
+
+// tierFromMetadata decodes the tier trustMetadataJSON stored on an edge,
+// returning ok=false if metadata is absent or doesn't carry a recognized
+// tier, so callers fall back to Confidence-based reconstruction for edges
+// persisted before this format existed.
+func tierFromMetadata(metadata *string) (TrustTier, bool) {
+	if metadata == nil || *metadata == "" {
+		return TierNone, false
+	}
+	var m trustMetadataJSON
+	if err := json.Unmarshal([]byte(*metadata), &m); err != nil || m.Tier == "" {
+		return TierNone, false
+	}
+	return trustTierFromString(m.Tier), true
+}
+
+// trustEdgeType is the graph edge type used to persist a single category's claim.
+func trustEdgeType(category string) string {
+	return "claims_" + category
+}
+
+// CheckTrustVectorFromGraph reconstructs a TrustVector from `claims_<category>`
+// edges targeting implNodeID, plus any legacy supports/contradicts edge as a
+// fallback "overall" claim.
+func CheckTrustVectorFromGraph(d *db.DB, implNodeID string) *TrustVector {
+	edges, err := d.GetEdgesForNode(implNodeID)
+	if err != nil {
+		return nil
+	}
+
+	tv := &TrustVector{Claims: map[string]TrustClaim{}}
+	for _, e := range edges {
+		if e.TargetID != implNodeID || e.SupersededBy != nil {
+			continue
+		}
+		if strings.HasPrefix(e.EdgeType, "claims_") {
+			category := strings.TrimPrefix(e.EdgeType, "claims_")
+			reason := ""
+			if e.Content != nil {
+				reason = *e.Content
+			}
+			tier := TierNone
+			if t, ok := tierFromMetadata(e.Metadata); ok {
+				tier = t
+			} else if e.Confidence != nil {
+				tier = trustTierFromScore(*e.Confidence)
+			}
+			tv.Claims[category] = TrustClaim{Category: category, Tier: tier, Reason: reason}
+		}
+	}
+	if len(tv.Claims) == 0 {
+		// Fall back to the legacy binary verdict as a single "overall" claim.
+		switch CheckVerdictFromGraph(d, implNodeID) {
+		case VerdictSupports:
+			tv.Claims["overall"] = TrustClaim{Category: "overall", Tier: TierAffirming}
+		case VerdictContradicts:
+			tv.Claims["overall"] = TrustClaim{Category: "overall", Tier: TierContraindicated}
+		default:
+			return nil
+		}
+	}
+	tv.Tier = tv.rollUp()
+	return tv
+}
+
+// PersistTrustVectorSummary writes one claims_<category> edge per category
+// plus a rolled-up supports/contradicts summary edge, so existing callers
+// that only understand the binary Verdict (CheckVerdictFromGraph) keep
+// working. Edges are created taskNodeID -> implNodeID, the same
+// agent/source -> impl/target direction CheckVerdictFromGraph's own
+// GetEdgesForNode(implNodeID)/TargetID-filter convention expects, so
+// CheckTrustVectorFromGraph (and CheckVerdictFromGraph's fallback) can find
+// them again by implNodeID.
+func PersistTrustVectorSummary(d *db.DB, implNodeID, taskNodeID string, tv *TrustVector) error {
+	if tv == nil {
+		return nil
+	}
+	categories := make([]string, 0, len(tv.Claims))
+	for c := range tv.Claims {
+		categories = append(categories, c)
+	}
+	sort.Strings(categories)
+
+	for _, category := range categories {
+		claim := tv.Claims[category]
+		confidence := 0.5
+		switch claim.Tier {
+		case TierAffirming:
+			confidence = 0.95
+		case TierWarning:
+			confidence = 0.5
+		case TierContraindicated:
+			confidence = 0.05
+		}
+		metadata, err := json.Marshal(trustMetadataJSON{Tier: claim.Tier.String()})
+		if err != nil {
+			return fmt.Errorf("encoding tier metadata for claim %s: %w", category, err)
+		}
+		if _, err := d.CreateEdge(taskNodeID, implNodeID, trustEdgeType(category), db.CreateEdgeOpts{
+			Reason:     claim.Reason,
+			Agent:      "spore:verifier",
+			Confidence: confidence,
+			Metadata:   string(metadata),
+		}); err != nil {
+			return fmt.Errorf("persisting claim %s: %w", category, err)
+		}
+	}
+
+	summary := "contradicts"
+	if UpdateStatusFromTrustVector(tv) == VerdictSupports {
+		summary = "supports"
+	}
+	_, err := d.CreateEdge(taskNodeID, implNodeID, summary, db.CreateEdgeOpts{
+		Reason: fmt.Sprintf("rolled-up trust tier: %s", tv.Tier),
+		Agent:  "spore:verifier",
+	})
+	return err
+}
+
+// DetermineTrustVector is the TrustVector analogue of DetermineVerdict: graph
+// edges first, then structured JSON claims, then a single-category fallback
+// built from the legacy 3-layer binary verdict.
+func DetermineTrustVector(d *db.DB, implNodeID, verifierOutput string) *TrustVector {
+	if d != nil && implNodeID != "" {
+		if tv := CheckTrustVectorFromGraph(d, implNodeID); tv != nil {
+			return tv
+		}
+	}
+	if tv := ParseTrustVectorJSON(verifierOutput); tv != nil {
+		return tv
+	}
+
+	vv := DetermineVerdict(d, implNodeID, verifierOutput)
+	tv := &TrustVector{Claims: map[string]TrustClaim{}}
+	switch vv.Verdict {
+	case VerdictSupports:
+		tv.Claims["overall"] = TrustClaim{Category: "overall", Tier: TierAffirming, Reason: vv.Reason}
+	case VerdictContradicts:
+		tv.Claims["overall"] = TrustClaim{Category: "overall", Tier: TierContraindicated, Reason: vv.Reason}
+	default:
+		tv.Claims["overall"] = TrustClaim{Category: "overall", Tier: TierNone, Reason: vv.Reason}
+	}
+	tv.Tier = tv.rollUp()
+	return tv
+}
@@ -0,0 +1,147 @@
+package orchestrate
+
+import (
+	"testing"
+
+	"mycelica/spore/internal/db"
+)
+
+// insertTestEdgeWithMetadata is insertTestEdge (verdict_test.go) plus
+// confidence/metadata columns, for exercising CheckTrustVectorFromGraph's
+// tier-from-metadata reconstruction without a live CLI backend.
+func insertTestEdgeWithMetadata(t *testing.T, d *db.DB, id, source, target, edgeType string, confidence float64, metadata string) {
+	t.Helper()
+	_, err := d.Conn().Exec(
+		`INSERT INTO edges (id, source_id, target_id, type, confidence, metadata, created_at) VALUES (?, ?, ?, ?, ?, ?, 1000)`,
+		id, source, target, edgeType, confidence, metadata,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestTierFromMetadata(t *testing.T) {
+	cases := []struct {
+		metadata string
+		want     TrustTier
+		wantOK   bool
+	}{
+		{`{"tier":"none"}`, TierNone, true},
+		{`{"tier":"warning"}`, TierWarning, true},
+		{`{"tier":"affirming"}`, TierAffirming, true},
+		{`{"tier":"contraindicated"}`, TierContraindicated, true},
+		{``, TierNone, false},
+		{`not json`, TierNone, false},
+		{`{}`, TierNone, false},
+	}
+	for _, c := range cases {
+		var m *string
+		if c.metadata != "" || c.wantOK {
+			m = &c.metadata
+		}
+		got, ok := tierFromMetadata(m)
+		if ok != c.wantOK {
+			t.Errorf("tierFromMetadata(%q) ok = %v, want %v", c.metadata, ok, c.wantOK)
+			continue
+		}
+		if ok && got != c.want {
+			t.Errorf("tierFromMetadata(%q) = %v, want %v", c.metadata, got, c.want)
+		}
+	}
+}
+
+// TestPersistTrustVectorSummary_RoundTripsTierNoneDistinctFromWarning
+// inserts claims_<category> edges the way PersistTrustVectorSummary does
+// (confidence 0.5 plus a tier metadata blob) and confirms
+// CheckTrustVectorFromGraph reconstructs TierNone rather than collapsing it
+// into TierWarning, which shared the same 0.5 confidence bucket before the
+// tier was also stored directly in Metadata.
+func TestPersistTrustVectorSummary_RoundTripsTierNoneDistinctFromWarning(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	insertTestEdgeWithMetadata(t, d, "e1", "task-node", "impl-node", "claims_coverage", 0.5, `{"tier":"none"}`)
+	insertTestEdgeWithMetadata(t, d, "e2", "task-node", "impl-node", "claims_security", 0.5, `{"tier":"warning"}`)
+
+	tv := CheckTrustVectorFromGraph(d, "impl-node")
+	if tv == nil {
+		t.Fatal("expected a reconstructed TrustVector")
+	}
+	if got := tv.Claims["coverage"].Tier; got != TierNone {
+		t.Errorf("coverage claim = %v, want TierNone", got)
+	}
+	if got := tv.Claims["security"].Tier; got != TierWarning {
+		t.Errorf("security claim = %v, want TierWarning", got)
+	}
+}
+
+func TestToTrustClaim(t *testing.T) {
+	cases := []struct {
+		v    any
+		want TrustTier
+	}{
+		{"pass", TierAffirming},
+		{"warn", TierWarning},
+		{"fail", TierContraindicated},
+		{"unknown-word", TierNone},
+		{0.9, TierAffirming},
+		{0.5, TierWarning},
+		{0.1, TierContraindicated},
+	}
+	for _, c := range cases {
+		claim := ToTrustClaim("correctness", c.v)
+		if claim.Tier != c.want {
+			t.Errorf("ToTrustClaim(%v) = %v, want %v", c.v, claim.Tier, c.want)
+		}
+	}
+}
+
+func TestTrustVector_RollUp(t *testing.T) {
+	tv := &TrustVector{Claims: map[string]TrustClaim{
+		"correctness": {Category: "correctness", Tier: TierAffirming},
+		"security":    {Category: "security", Tier: TierWarning},
+	}}
+	tv.Tier = tv.rollUp()
+	if tv.Tier != TierWarning {
+		t.Errorf("expected rolled-up tier to be the worst (warning), got %v", tv.Tier)
+	}
+}
+
+func TestUpdateStatusFromTrustVector(t *testing.T) {
+	affirming := &TrustVector{Claims: map[string]TrustClaim{"a": {Tier: TierAffirming}}, Tier: TierAffirming}
+	if got := UpdateStatusFromTrustVector(affirming); got != VerdictSupports {
+		t.Errorf("expected VerdictSupports, got %v", got)
+	}
+
+	contraindicated := &TrustVector{Claims: map[string]TrustClaim{"a": {Tier: TierContraindicated}}, Tier: TierContraindicated}
+	if got := UpdateStatusFromTrustVector(contraindicated); got != VerdictContradicts {
+		t.Errorf("expected VerdictContradicts, got %v", got)
+	}
+
+	if got := UpdateStatusFromTrustVector(nil); got != VerdictUnknown {
+		t.Errorf("expected VerdictUnknown for nil vector, got %v", got)
+	}
+}
+
+func TestParseTrustVectorJSON(t *testing.T) {
+	text := `Looks good. <verdict>{"claims":{"correctness":"pass","security":"warn"}}</verdict>`
+	tv := ParseTrustVectorJSON(text)
+	if tv == nil {
+		t.Fatal("expected a parsed TrustVector")
+	}
+	if tv.Claims["correctness"].Tier != TierAffirming {
+		t.Errorf("expected correctness=affirming, got %v", tv.Claims["correctness"].Tier)
+	}
+	if tv.Claims["security"].Tier != TierWarning {
+		t.Errorf("expected security=warning, got %v", tv.Claims["security"].Tier)
+	}
+	if tv.Tier != TierWarning {
+		t.Errorf("expected rolled-up tier warning, got %v", tv.Tier)
+	}
+}
+
+func TestParseTrustVectorJSON_NoClaims(t *testing.T) {
+	if tv := ParseTrustVectorJSON("no verdict here"); tv != nil {
+		t.Errorf("expected nil when no claims block present, got %+v", tv)
+	}
+}
@@ -1,6 +1,12 @@
 package orchestrate
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"mycelica/spore/internal/graph"
+	"mycelica/spore/internal/orchestrate/assert"
+)
 
 // AgentRole identifies the pipeline stage
 type AgentRole string
@@ -21,6 +27,7 @@ const (
 	VerdictUnknown     Verdict = iota
 	VerdictSupports            // implementation passes verification
 	VerdictContradicts         // implementation fails verification
+	VerdictConflicted          // verifier agents disagree; needs tie-breaker re-verification
 )
 
 func (v Verdict) String() string {
@@ -29,6 +36,8 @@ func (v Verdict) String() string {
 		return "supports"
 	case VerdictContradicts:
 		return "contradicts"
+	case VerdictConflicted:
+		return "conflicted"
 	default:
 		return "unknown"
 	}
@@ -66,18 +75,45 @@ type ClaudeConfig struct {
 	Timeout       time.Duration
 	WorkDir       string
 	Verbose       bool
+
+	// RunID identifies this run for Detached mode: the shim's run directory,
+	// socket, and exit.json all live under shimRunDir(RunID). Required when
+	// Detached is set, otherwise unused.
+	RunID string
+
+	// Detached runs Claude under a detached mycelica-shim process (see
+	// spawnShimmed) instead of execing it directly, so an in-flight turn
+	// survives the orchestrator restarting or crashing. The shim can be
+	// reattached across restarts with AttachRun(RunID).
+	Detached bool
+
+	// StateReporter, if set, is driven through Pending/Received/Running/
+	// Completed/Failed as this run progresses (see AgentStateReporter). Not
+	// serializable -- excluded from Detached's config.json, since a live
+	// reporter can't cross the shim's process boundary.
+	StateReporter AgentStateReporter `json:"-"`
+}
+
+// ToolCall is one tool invocation captured from a Claude Code subprocess's
+// stream-json output (see parseStreamJSON). Input is kept as raw JSON since
+// every tool has its own schema; callers unmarshal it based on Name.
+type ToolCall struct {
+	Name  string          `json:"name"`
+	Input json.RawMessage `json:"input"`
 }
 
 // ClaudeResult captures output from a Claude Code subprocess
 type ClaudeResult struct {
-	ExitCode  int           `json:"exit_code"`
-	SessionID string        `json:"session_id"`
-	CostUSD   float64       `json:"cost_usd"`
-	NumTurns  int           `json:"num_turns"`
-	Duration  time.Duration `json:"duration"`
-	Thinking  string        `json:"thinking"`  // last thinking block
-	MCPStatus string        `json:"mcp_status"` // connected/failed/none
-	Stderr    string        `json:"stderr"`
+	ExitCode    int           `json:"exit_code"`
+	SessionID   string        `json:"session_id"`
+	CostUSD     float64       `json:"cost_usd"`
+	NumTurns    int           `json:"num_turns"`
+	Duration    time.Duration `json:"duration"`
+	Thinking    string        `json:"thinking"`     // last thinking block
+	LastMessage string        `json:"last_message"` // last assistant text block
+	ToolCalls   []ToolCall    `json:"tool_calls,omitempty"`
+	MCPStatus   string        `json:"mcp_status"` // connected/failed/none
+	Stderr      string        `json:"stderr"`
 }
 
 // TaskFileConfig controls task file generation parameters
@@ -89,6 +125,50 @@ type TaskFileConfig struct {
 	MaxHops    int     // Dijkstra max hops (default 4)
 	MaxCost    float64 // Dijkstra max edge cost (default 2.0)
 	MaxLessons int     // max lessons from past runs (default 5)
+
+	// UseANNIndex gates the approximate HNSW nearest-neighbor index (see
+	// graph.FindSimilarAuto) for the semantic anchor search below. Off by
+	// default: the exact scan is cheap enough for the embedding counts a
+	// single graph typically has, and correctness beats approximation there.
+	UseANNIndex bool
+
+	// Staleness, if non-nil, discounts findAnchors/gatherContext's Relevance
+	// for anchors and context rows that graph.ComputeStaleness flagged as
+	// stale, so the rendered task file steers an agent away from outdated
+	// context. Nil by default: computing it means scanning every node/edge,
+	// so callers opt in explicitly rather than GenerateTaskFile doing it
+	// implicitly on every task file.
+	Staleness *graph.StalenessReport
+	// StaleDays is the threshold Staleness was computed with (see
+	// graph.ComputeStaleness); ignored when Staleness is nil.
+	StaleDays int64
+
+	// PinnedNodes are node IDs a human wants considered as anchors
+	// regardless of what semantic/FTS search surfaces. They participate in
+	// findAnchors' Reciprocal Rank Fusion at a synthetic rank of 1, so a pin
+	// strongly influences but never bypasses fusion (a node both pinned and
+	// already top-ranked elsewhere isn't double-counted).
+	PinnedNodes []string
+
+	// EnableCodeSearch adds db.SearchCodeSymbols as a fourth findAnchors
+	// source -- a trigram-indexed identifier search that survives FTS
+	// tokenization failures on camelCase/snake_case symbols and file-path
+	// fragments. Off by default: it only pays off on tasks with code
+	// identifiers in them, and depends on ReindexCodeSymbols having been run.
+	EnableCodeSearch bool
+
+	// CallGraphMaxHops bounds how far renderCallGraphWithDB's BFS walks the
+	// caller/callee edges out from each function anchor. Default 3.
+	CallGraphMaxHops int
+	// CallGraphMaxNodes caps how many nodes that BFS collects per direction
+	// (callers, callees) per anchor, independent of CallGraphMaxHops, so a
+	// single highly-connected hop can't blow up the walk. Default 15.
+	CallGraphMaxNodes int
+
+	// TaskFileFormat selects the Renderer GenerateTaskFile writes its output
+	// through: "markdown" (default), "html", or "json" (see
+	// rendererForFormat). Empty behaves like "markdown".
+	TaskFileFormat string
 }
 
 // DefaultTaskFileConfig returns production defaults matching the Rust implementation
@@ -101,6 +181,9 @@ func DefaultTaskFileConfig() TaskFileConfig {
 		MaxHops:    4,
 		MaxCost:    2.0,
 		MaxLessons: 5,
+
+		CallGraphMaxHops:  3,
+		CallGraphMaxNodes: 15,
 	}
 }
 
@@ -117,8 +200,104 @@ type OrchestrationConfig struct {
 	Verbose     bool
 	Quiet       bool
 	JSON        bool
+
+	// Assertions is parsed from the task's `assertions:` YAML block (see
+	// ExtractTaskAssertions). Evaluated after the verifier reports supports;
+	// any failure downgrades the verdict to contradicts.
+	Assertions []assert.Assertion
+
+	// VerdictRules are compiled expr rules (see LoadVerdictRules) evaluated
+	// as Layer 0 of DetermineVerdictWithRules, ahead of the graph/JSON/text
+	// layers.
+	VerdictRules []CompiledVerdictRule
+
+	// Enforcement overrides the MaxBounces-driven default bounce behavior
+	// with scoped per-stage rules (see EnforcementPolicy). Nil preserves the
+	// legacy always-bounce-until-exhausted behavior.
+	Enforcement *EnforcementPolicy
+
+	// Policies overrides toolPermissions' compiled-in per-role tool
+	// allow/disallow lists (see ToolPolicies). Nil preserves the legacy
+	// hard-coded defaults for every role.
+	Policies *ToolPolicies
+
+	// LessonStore is the backend lesson persistence is read/written through.
+	// Nil defaults to NewSQLiteLessonStore(d).
+	LessonStore LessonStore
+
+	// VerdictMatchers are JSONPath-based verdict extractors (see
+	// LoadVerdictMatchers) evaluated as Layer 2.5 of
+	// DetermineVerdictWithRulesAndMatchers, between the structured-JSON and
+	// text-keyword layers.
+	VerdictMatchers []VerdictMatcher
+
+	// CoderTimeout/VerifierTimeout/SummarizerTimeout bound each phase's
+	// Claude subprocess via context.WithTimeout, independent of the caller's
+	// own ctx. Zero means no phase-specific deadline (the caller's ctx, and
+	// ClaudeConfig.Timeout's own watchdog, still apply).
+	CoderTimeout      time.Duration
+	VerifierTimeout   time.Duration
+	SummarizerTimeout time.Duration
+
+	// Candidates is the number of coder attempts to produce per bounce, each
+	// isolated in its own git worktree (see CreateWorktree). <= 1 preserves
+	// the legacy single-attempt behavior. When > 1, the verifier runs
+	// against every candidate and the highest-confidence VerdictSupports
+	// candidate's diff is applied back onto the main working tree; the rest
+	// are recorded (PhaseResult.Candidate) for inspection and discarded.
+	Candidates int
+
+	// ParallelCandidates spawns all of Candidates' coder subprocesses
+	// concurrently instead of one at a time. Off by default since each
+	// coder run is itself expensive; worktree isolation alone (sequential)
+	// is still useful for keeping attempts from stomping each other.
+	ParallelCandidates bool
+
+	// ResumeRunID resumes a previously interrupted run: RunOrchestration
+	// looks up the latest checkpoint (see Checkpoint/loadCheckpoint) saved
+	// under OutputDir for this run ID, restores its bounce/lastImplID/
+	// lastVerdict/Phases, and resumes the coder's Claude session instead of
+	// starting cold. Empty starts a fresh run as before.
+	ResumeRunID string
+
+	// Rollback controls how RunOrchestration reverts workDir in response to
+	// failure (see RollbackPolicy). Empty (RollbackNever) preserves the
+	// legacy behavior: every bounce's changes accumulate and nothing is
+	// reverted automatically.
+	Rollback RollbackPolicy
+
+	// Supervisor, when non-nil, runs the coder and verifier subprocesses
+	// under Supervise instead of a bare SpawnClaude, auto-restarting a
+	// crashed attempt according to policy before the phase gives up. Nil
+	// preserves the legacy behavior: a single unsupervised attempt per
+	// phase.
+	Supervisor *SupervisorPolicy
 }
 
+// RollbackPolicy selects how aggressively RunOrchestration reverts workDir
+// when a bounce fails to verify.
+type RollbackPolicy string
+
+const (
+	// RollbackNever leaves every bounce's changes in place, including an
+	// escalation's. This is the legacy, default behavior.
+	RollbackNever RollbackPolicy = ""
+
+	// RollbackOnEscalation reverts workDir to its pre-run HEAD (via
+	// StashAndResetToHEAD) only when max bounces are exhausted and
+	// createEscalation fires, so an escalated task doesn't leave the tree
+	// in a broken state for whoever picks it up next.
+	RollbackOnEscalation RollbackPolicy = "on-escalation"
+
+	// RollbackBetweenBounces snapshots each bounce's changes under a
+	// refs/spore/<runID>/bounce-N ref (see BounceSnapshotRef) and resets
+	// workDir back to its pre-run HEAD before the next bounce starts, so
+	// the next coder attempt is driven by the verifier's feedback rather
+	// than whatever broken code the previous attempt left behind. Also
+	// reverts on escalation, same as RollbackOnEscalation.
+	RollbackBetweenBounces RollbackPolicy = "between-bounces"
+)
+
 // DefaultOrchestrationConfig returns production defaults
 func DefaultOrchestrationConfig() OrchestrationConfig {
 	return OrchestrationConfig{
@@ -136,6 +315,22 @@ type PhaseResult struct {
 	ImplNodeID   string          `json:"impl_node_id,omitempty"`
 	Verdict      *VerifierVerdict `json:"verdict,omitempty"`
 	ChangedFiles []string        `json:"changed_files,omitempty"`
+
+	// Candidate is the best-of-N index this phase ran as (see
+	// OrchestrationConfig.Candidates). Zero in single-candidate runs.
+	Candidate int `json:"candidate,omitempty"`
+
+	// Evidence lists the node IDs a verifier cited in support of its
+	// verdict (see StructuredVerdict.Evidence), each recorded as a "cites"
+	// edge from the implementation node. Empty when the verdict came from
+	// the text-scrape fallback layers, which don't carry evidence.
+	Evidence []string `json:"evidence,omitempty"`
+
+	// Policy is this phase's resolved tool permissions (see
+	// resolveToolPermissions), recorded so recordRunStatus can attribute a
+	// run's tracks-edge metadata to the effective policy it ran under. Nil
+	// when OrchestrationConfig.Policies had no rule for this phase's role.
+	Policy *ResolvedToolPolicy `json:"policy,omitempty"`
 }
 
 // OrchestrationResult is the full outcome of an orchestration run
@@ -147,13 +342,44 @@ type OrchestrationResult struct {
 	Status     RunStatus      `json:"status"`
 	TotalCost  float64        `json:"total_cost_usd"`
 	Phases     []PhaseResult  `json:"phases"`
+	Assertions []assert.AssertionApplied `json:"assertions,omitempty"`
+	StoreStats *StoreStats               `json:"store_stats,omitempty"`
 }
 
 // GitState captures repository state before/after an agent run
 type GitState struct {
-	Branch    string            `json:"branch"`
-	Commit    string            `json:"commit"`
-	Dirty     map[string]bool   `json:"dirty"`     // modified tracked files
-	Untracked map[string]bool   `json:"untracked"` // untracked files
-	Hashes    map[string]string `json:"hashes"`    // file content hashes
+	Branch    string              `json:"branch"`
+	Commit    string              `json:"commit"`
+	Dirty     map[string]bool     `json:"dirty"`     // modified tracked files
+	Untracked map[string]bool     `json:"untracked"` // untracked files
+	Hashes    map[string]FileHash `json:"hashes"`    // file content hashes
+}
+
+// FileHash is one file's content fingerprint as of a GitState snapshot.
+// Pointer is set when the file exceeded GitStateOpts.HashSizeThreshold:
+// Hash is then a streaming pointerHash summary rather than a full git blob
+// hash, so capturing it never required reading the whole file into memory.
+type FileHash struct {
+	Hash    string `json:"hash"`
+	Pointer bool   `json:"pointer,omitempty"`
+}
+
+// defaultHashSizeThreshold is GitStateOpts.HashSizeThreshold's zero-value
+// fallback: files larger than this get a pointerHash instead of a full
+// content hash.
+const defaultHashSizeThreshold int64 = 5 * 1024 * 1024
+
+// GitStateOpts configures CaptureGitStateWithOpts/CaptureFileHashesWithOpts.
+type GitStateOpts struct {
+	// HashSizeThreshold is the file size, in bytes, above which hashing
+	// falls back to a streaming pointerHash instead of reading the whole
+	// file into memory. Zero uses defaultHashSizeThreshold.
+	HashSizeThreshold int64
+}
+
+func (o GitStateOpts) threshold() int64 {
+	if o.HashSizeThreshold > 0 {
+		return o.HashSizeThreshold
+	}
+	return defaultHashSizeThreshold
 }
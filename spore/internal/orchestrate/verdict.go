@@ -2,8 +2,10 @@ package orchestrate
 
 import (
 	"encoding/json"
+	"fmt"
 	"regexp"
 	"strings"
+	"time"
 
 	"mycelica/spore/internal/db"
 )
@@ -59,6 +61,115 @@ func CheckVerdictFromGraph(d *db.DB, implNodeID string) Verdict {
 	return VerdictUnknown
 }
 
+// VerdictEvidence is every non-superseded supports/contradicts edge targeting
+// an impl node, grouped by agent. Conflicted is true when two agents disagree,
+// or when one agent's own edges disagree across time (a flip).
+type VerdictEvidence struct {
+	Edges      []db.Edge
+	ByAgent    map[string][]db.Edge
+	Conflicted bool
+}
+
+// Verdict reduces the evidence to a single Verdict: VerdictConflicted if
+// disputed, the shared verdict if all edges agree, else VerdictUnknown.
+func (ev VerdictEvidence) Verdict() Verdict {
+	if ev.Conflicted {
+		return VerdictConflicted
+	}
+	if len(ev.Edges) == 0 {
+		return VerdictUnknown
+	}
+	switch ev.Edges[0].EdgeType {
+	case "supports":
+		return VerdictSupports
+	case "contradicts":
+		return VerdictContradicts
+	default:
+		return VerdictUnknown
+	}
+}
+
+// CollectVerdictEvidence gathers every non-superseded supports/contradicts
+// edge targeting implNodeID, grouped by agent, and detects disagreement.
+// Unlike CheckVerdictFromGraph (which returns the first edge it finds), this
+// surfaces conflicting evidence instead of silently picking a winner.
+func CollectVerdictEvidence(d *db.DB, implNodeID string) VerdictEvidence {
+	evidence := VerdictEvidence{ByAgent: map[string][]db.Edge{}}
+	if d == nil || implNodeID == "" {
+		return evidence
+	}
+	edges, err := d.GetEdgesForNode(implNodeID)
+	if err != nil {
+		return evidence
+	}
+
+	seenTypes := map[string]bool{}
+	for _, e := range edges {
+		if e.TargetID != implNodeID || e.SupersededBy != nil {
+			continue
+		}
+		if e.EdgeType != "supports" && e.EdgeType != "contradicts" {
+			continue
+		}
+		agent := "unknown"
+		if e.AgentID != nil {
+			agent = *e.AgentID
+		}
+		evidence.Edges = append(evidence.Edges, e)
+		evidence.ByAgent[agent] = append(evidence.ByAgent[agent], e)
+		seenTypes[e.EdgeType] = true
+	}
+	evidence.Conflicted = len(seenTypes) > 1
+	return evidence
+}
+
+// ResolveVerdictConflict records a tie-breaker re-verification's outcome,
+// superseding every edge in the conflict and linking back to their IDs via a
+// "resolves" edge's Metadata, so downstream audits can reconstruct why the
+// current verdict stands. Returns the ID of the new supports/contradicts edge.
+func ResolveVerdictConflict(d *db.DB, implNodeID, taskNodeID string, evidence VerdictEvidence, resolution Verdict, reason string) (string, error) {
+	if !evidence.Conflicted {
+		return "", fmt.Errorf("no conflict to resolve")
+	}
+	if resolution != VerdictSupports && resolution != VerdictContradicts {
+		return "", fmt.Errorf("resolution must be supports or contradicts, got %s", resolution)
+	}
+
+	ids := make([]string, 0, len(evidence.Edges))
+	for _, e := range evidence.Edges {
+		ids = append(ids, e.ID)
+	}
+	metadata, err := json.Marshal(struct {
+		Resolves []string `json:"resolves"`
+	}{Resolves: ids})
+	if err != nil {
+		return "", fmt.Errorf("marshaling resolution metadata: %w", err)
+	}
+
+	resolutionID, err := d.CreateEdge(implNodeID, taskNodeID, resolution.String(), db.CreateEdgeOpts{
+		Reason:     reason,
+		Agent:      "spore:verifier",
+		Confidence: 1.0,
+		Metadata:   string(metadata),
+		Supersedes: ids[0],
+	})
+	if err != nil {
+		return "", fmt.Errorf("recording resolution edge: %w", err)
+	}
+
+	for _, id := range ids[1:] {
+		if _, err := d.CreateEdge(implNodeID, taskNodeID, "resolves", db.CreateEdgeOpts{
+			Reason:     reason,
+			Agent:      "spore:verifier",
+			Supersedes: id,
+			Metadata:   string(metadata),
+		}); err != nil {
+			return resolutionID, fmt.Errorf("superseding conflicting edge %s: %w", id, err)
+		}
+	}
+	return resolutionID, nil
+}
+
 // verdictTagRe matches <verdict>{...}</verdict> blocks.
 // The (?s) flag enables dot-all mode so . matches newlines.
 var verdictTagRe = regexp.MustCompile(`(?s)<verdict>\s*(\{.*?\})\s*</verdict>`)
@@ -228,8 +339,20 @@ func ParseVerdictFromText(text string) Verdict {
 // Returns a VerifierVerdict with VerdictUnknown if all layers fail.
 // Handles nil db gracefully by skipping the graph check.
 func DetermineVerdict(d *db.DB, implNodeID string, verifierOutput string) *VerifierVerdict {
-	// Layer 1: graph edges
+	// Layer 1: graph edges. Check for conflicting evidence first so a
+	// disagreement between verifier agents is surfaced rather than silently
+	// resolved to whichever edge CheckVerdictFromGraph happens to see first.
 	if d != nil && implNodeID != "" {
+		if evidence := CollectVerdictEvidence(d, implNodeID); evidence.Conflicted {
+			if agg := AggregateVerdicts(evidence.Edges, time.Now(), nil, 0); agg.Confidence >= conflictResolutionThreshold {
+				return agg
+			}
+			return &VerifierVerdict{
+				Verdict:    VerdictConflicted,
+				Reason:     fmt.Sprintf("%d agents disagree across %d verdict edges", len(evidence.ByAgent), len(evidence.Edges)),
+				Confidence: 0.0,
+			}
+		}
 		v := CheckVerdictFromGraph(d, implNodeID)
 		if v != VerdictUnknown {
 			return &VerifierVerdict{
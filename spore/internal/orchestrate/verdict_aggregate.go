@@ -0,0 +1,118 @@
+package orchestrate
+
+import (
+	"fmt"
+	"math"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+// AgentTrustWeights maps an agent ID to a trust multiplier used by
+// AggregateVerdicts. Agents missing from the map (including edges with no
+// agent_id, e.g. CLI-created links) fall back to defaultAgentTrust.
+type AgentTrustWeights map[string]float64
+
+const defaultAgentTrust = 0.5
+
+// DefaultAgentTrustWeights matches the agents spore's own pipeline creates.
+func DefaultAgentTrustWeights() AgentTrustWeights {
+	return AgentTrustWeights{
+		"spore:verifier":   1.0,
+		"spore:coder":      0.7,
+		"spore:summarizer": 0.6,
+	}
+}
+
+func (w AgentTrustWeights) weightFor(agentID string) float64 {
+	if w == nil || agentID == "" {
+		return defaultAgentTrust
+	}
+	if v, ok := w[agentID]; ok {
+		return v
+	}
+	return defaultAgentTrust
+}
+
+// DefaultVerdictHalfLife is how long it takes a verdict edge's contribution
+// to an aggregated verdict to decay to half its original weight.
+const DefaultVerdictHalfLife = 30 * 24 * time.Hour
+
+// AggregateVerdicts combines every supports/contradicts edge in edges into a
+// single weighted verdict, rather than picking the first one found (see
+// CheckVerdictFromGraph). Each edge contributes
+// w = confidence * agent_trust * exp(-age / halfLife); supports edges add
+// positively, contradicts edges subtract. The sign of the total picks the
+// verdict, and the total's magnitude relative to the sum of weights becomes
+// the confidence -- so an old passing edge on a since-modified impl node is
+// naturally outweighed by a fresh contradicting one, without requiring
+// explicit SupersededBy bookkeeping.
+//
+// weights and halfLife are optional: nil/zero fall back to
+// DefaultAgentTrustWeights and DefaultVerdictHalfLife.
+func AggregateVerdicts(edges []db.Edge, now time.Time, weights AgentTrustWeights, halfLife time.Duration) *VerifierVerdict {
+	if weights == nil {
+		weights = DefaultAgentTrustWeights()
+	}
+	if halfLife <= 0 {
+		halfLife = DefaultVerdictHalfLife
+	}
+
+	var signedSum, totalWeight float64
+	var n int
+	for _, e := range edges {
+		if e.SupersededBy != nil {
+			continue
+		}
+		var sign float64
+		switch e.EdgeType {
+		case "supports":
+			sign = 1
+		case "contradicts":
+			sign = -1
+		default:
+			continue
+		}
+		n++
+
+		confidence := 1.0
+		if e.Confidence != nil {
+			confidence = *e.Confidence
+		}
+		agent := ""
+		if e.AgentID != nil {
+			agent = *e.AgentID
+		}
+		age := now.Sub(time.UnixMilli(e.CreatedAt))
+		decay := math.Exp(-age.Hours() / halfLife.Hours())
+		w := confidence * weights.weightFor(agent) * decay
+
+		signedSum += sign * w
+		totalWeight += w
+	}
+
+	if n == 0 || totalWeight == 0 {
+		return &VerifierVerdict{Verdict: VerdictUnknown, Reason: "no verdict edges to aggregate", Confidence: 0}
+	}
+
+	verdict := VerdictSupports
+	if signedSum < 0 {
+		verdict = VerdictContradicts
+	}
+	confidence := math.Abs(signedSum) / totalWeight
+	if confidence > 1 {
+		confidence = 1
+	}
+
+	return &VerifierVerdict{
+		Verdict:    verdict,
+		Reason:     fmt.Sprintf("time/trust-weighted aggregation of %d verdict edges (half-life %s)", n, halfLife),
+		Confidence: confidence,
+	}
+}
+
+// conflictResolutionThreshold is how confident a time/trust-weighted
+// aggregation must be before it's trusted to resolve a raw disagreement
+// automatically; below it, the conflict is surfaced as VerdictConflicted
+// instead (see CollectVerdictEvidence) so a tie-breaker re-verification runs.
+const conflictResolutionThreshold = 0.7
@@ -0,0 +1,68 @@
+package orchestrate
+
+import (
+	"testing"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+func confPtr(f float64) *float64 { return &f }
+func agentPtr(s string) *string  { return &s }
+
+func TestAggregateVerdicts_FreshContradictsOutweighsStaleSupports(t *testing.T) {
+	now := time.Now()
+	verifier := "spore:verifier"
+	edges := []db.Edge{
+		{
+			EdgeType:   "supports",
+			Confidence: confPtr(0.9),
+			AgentID:    agentPtr(verifier),
+			CreatedAt:  now.Add(-90 * 24 * time.Hour).UnixMilli(), // 3 half-lives old
+		},
+		{
+			EdgeType:   "contradicts",
+			Confidence: confPtr(0.9),
+			AgentID:    agentPtr(verifier),
+			CreatedAt:  now.UnixMilli(), // fresh
+		},
+	}
+
+	vv := AggregateVerdicts(edges, now, nil, 0)
+	if vv.Verdict != VerdictContradicts {
+		t.Errorf("expected fresh contradicts to outweigh stale supports, got %v", vv.Verdict)
+	}
+}
+
+func TestAggregateVerdicts_AgentTrustWeighting(t *testing.T) {
+	now := time.Now()
+	weights := AgentTrustWeights{"spore:verifier": 1.0, "ad-hoc": 0.1}
+	edges := []db.Edge{
+		{EdgeType: "supports", Confidence: confPtr(1.0), AgentID: agentPtr("spore:verifier"), CreatedAt: now.UnixMilli()},
+		{EdgeType: "contradicts", Confidence: confPtr(1.0), AgentID: agentPtr("ad-hoc"), CreatedAt: now.UnixMilli()},
+	}
+
+	vv := AggregateVerdicts(edges, now, weights, 0)
+	if vv.Verdict != VerdictSupports {
+		t.Errorf("expected the high-trust agent's supports to win, got %v", vv.Verdict)
+	}
+}
+
+func TestAggregateVerdicts_NoEdges(t *testing.T) {
+	vv := AggregateVerdicts(nil, time.Now(), nil, 0)
+	if vv.Verdict != VerdictUnknown {
+		t.Errorf("expected VerdictUnknown for no edges, got %v", vv.Verdict)
+	}
+}
+
+func TestAggregateVerdicts_SupersededIgnored(t *testing.T) {
+	now := time.Now()
+	supersededBy := "e2"
+	edges := []db.Edge{
+		{EdgeType: "contradicts", Confidence: confPtr(1.0), CreatedAt: now.UnixMilli(), SupersededBy: &supersededBy},
+	}
+	vv := AggregateVerdicts(edges, now, nil, 0)
+	if vv.Verdict != VerdictUnknown {
+		t.Errorf("expected VerdictUnknown (only edge is superseded), got %v", vv.Verdict)
+	}
+}
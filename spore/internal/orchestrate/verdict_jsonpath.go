@@ -0,0 +1,175 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/PaesslerAG/jsonpath"
+	"gopkg.in/yaml.v3"
+
+	"mycelica/spore/internal/db"
+)
+
+// VerdictMatcher extracts a verdict from an arbitrary verifier JSON schema:
+// evaluate Path against the parsed JSON, optionally require the scalar
+// result to match Regex, then map it to a Verdict via Values.
+type VerdictMatcher struct {
+	Name   string            `yaml:"name"`
+	Path   string            `yaml:"path"`             // e.g. "$.analysis.summary.outcome"
+	Regex  string            `yaml:"regex,omitempty"`  // optional constraint on the matched scalar
+	Values map[string]string `yaml:"values"`           // matched value (lowercased) -> "supports"/"contradicts"
+	Weight float64           `yaml:"weight,omitempty"` // confidence when this matcher fires (default 0.85)
+}
+
+// verdictMatcherFile is the on-disk YAML shape for --verdict-matchers files.
+type verdictMatcherFile struct {
+	Matchers []VerdictMatcher `yaml:"matchers"`
+}
+
+// LoadVerdictMatchers reads a YAML file of VerdictMatcher specs from path.
+func LoadVerdictMatchers(path string) ([]VerdictMatcher, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading verdict matchers: %w", err)
+	}
+	var f verdictMatcherFile
+	if err := yaml.Unmarshal(raw, &f); err != nil {
+		return nil, fmt.Errorf("parsing verdict matchers: %w", err)
+	}
+	return f.Matchers, nil
+}
+
+// MatcherAttempt records what one matcher saw, for `spore verdict-dry-run`.
+type MatcherAttempt struct {
+	Matcher string
+	Matched bool
+	Value   string
+	Verdict Verdict
+	Err     string
+}
+
+// EvaluateVerdictMatchers tries each matcher in order against verifierOutput
+// (parsed as JSON) and returns the first one that fires, plus the full list
+// of attempts for diagnostics (see `spore verdict-dry-run`).
+func EvaluateVerdictMatchers(matchers []VerdictMatcher, verifierOutput string) (*VerifierVerdict, []MatcherAttempt) {
+	var doc any
+	if err := json.Unmarshal([]byte(verifierOutput), &doc); err != nil {
+		attempts := make([]MatcherAttempt, len(matchers))
+		for i, m := range matchers {
+			attempts[i] = MatcherAttempt{Matcher: m.Name, Err: fmt.Sprintf("verifier output is not JSON: %v", err)}
+		}
+		return nil, attempts
+	}
+
+	var attempts []MatcherAttempt
+	var result *VerifierVerdict
+	for _, m := range matchers {
+		v, value, err := evalMatcher(m, doc)
+		attempt := MatcherAttempt{Matcher: m.Name, Value: value}
+		if err != nil {
+			attempt.Err = err.Error()
+			attempts = append(attempts, attempt)
+			continue
+		}
+		if v == VerdictUnknown {
+			attempts = append(attempts, attempt)
+			continue
+		}
+		attempt.Matched = true
+		attempt.Verdict = v
+		attempts = append(attempts, attempt)
+		if result == nil {
+			weight := m.Weight
+			if weight <= 0 {
+				weight = 0.85
+			}
+			result = &VerifierVerdict{
+				Verdict:    v,
+				Reason:     fmt.Sprintf("matcher %q fired on %s = %q", m.Name, m.Path, value),
+				Confidence: weight,
+			}
+		}
+	}
+	return result, attempts
+}
+
+func evalMatcher(m VerdictMatcher, doc any) (Verdict, string, error) {
+	raw, err := jsonpath.Get(m.Path, doc)
+	if err != nil {
+		return VerdictUnknown, "", fmt.Errorf("jsonpath %q: %w", m.Path, err)
+	}
+	value := fmt.Sprint(raw)
+
+	if m.Regex != "" {
+		re, err := regexp.Compile(m.Regex)
+		if err != nil {
+			return VerdictUnknown, value, fmt.Errorf("invalid regex %q: %w", m.Regex, err)
+		}
+		if !re.MatchString(value) {
+			return VerdictUnknown, value, nil
+		}
+	}
+
+	for k, mapped := range m.Values {
+		if strings.EqualFold(k, value) {
+			return mapVerdictString(mapped), value, nil
+		}
+	}
+	return VerdictUnknown, value, nil
+}
+
+// DetermineVerdictWithRulesAndMatchers extends DetermineVerdictWithRules with
+// a JSONPath matcher layer slotted between the structured-JSON layer and the
+// text-keyword layer, for verifier outputs that emit verdict-bearing fields
+// under a schema DetermineVerdict doesn't already understand. Pass a nil or
+// empty matchers slice to get identical behavior to DetermineVerdictWithRules.
+func DetermineVerdictWithRulesAndMatchers(rules []CompiledVerdictRule, matchers []VerdictMatcher, d *db.DB, implNodeID, verifierOutput string, cost float64, bounces int, elapsed time.Duration) *VerifierVerdict {
+	// Layer 0: configurable expr rules
+	if vv := EvaluateVerdictRules(rules, d, implNodeID, verifierOutput, cost, bounces, elapsed); vv != nil {
+		return vv
+	}
+
+	// Layer 1: graph edges
+	if d != nil && implNodeID != "" {
+		if evidence := CollectVerdictEvidence(d, implNodeID); evidence.Conflicted {
+			if agg := AggregateVerdicts(evidence.Edges, time.Now(), nil, 0); agg.Confidence >= conflictResolutionThreshold {
+				return agg
+			}
+			return &VerifierVerdict{
+				Verdict:    VerdictConflicted,
+				Reason:     fmt.Sprintf("%d agents disagree across %d verdict edges", len(evidence.ByAgent), len(evidence.Edges)),
+				Confidence: 0.0,
+			}
+		}
+		if v := CheckVerdictFromGraph(d, implNodeID); v != VerdictUnknown {
+			return &VerifierVerdict{Verdict: v, Reason: "Verdict from graph edge", Confidence: 1.0}
+		}
+	}
+
+	// Layer 2: structured JSON
+	if vv := ParseVerifierVerdictJSON(verifierOutput); vv != nil && vv.Verdict != VerdictUnknown {
+		return vv
+	}
+
+	// Layer 2.5: JSONPath matchers
+	if len(matchers) > 0 {
+		if vv, _ := EvaluateVerdictMatchers(matchers, verifierOutput); vv != nil {
+			return vv
+		}
+	}
+
+	// Layer 3: text keywords
+	if v := ParseVerdictFromText(verifierOutput); v != VerdictUnknown {
+		return &VerifierVerdict{
+			Verdict:    v,
+			Reason:     "Verdict inferred from verifier output text (keyword scan)",
+			Confidence: 0.6,
+		}
+	}
+
+	return &VerifierVerdict{Verdict: VerdictUnknown, Reason: "", Confidence: 0.0}
+}
@@ -0,0 +1,60 @@
+package orchestrate
+
+import "testing"
+
+func TestEvaluateVerdictMatchers(t *testing.T) {
+	matchers := []VerdictMatcher{
+		{
+			Name:   "outcome",
+			Path:   "$.analysis.summary.outcome",
+			Values: map[string]string{"pass": "supports", "fail": "contradicts"},
+			Weight: 0.9,
+		},
+	}
+
+	output := `{"analysis":{"summary":{"outcome":"fail"}}}`
+	vv, attempts := EvaluateVerdictMatchers(matchers, output)
+	if vv == nil {
+		t.Fatal("expected a matcher to fire")
+	}
+	if vv.Verdict != VerdictContradicts {
+		t.Errorf("expected contradicts, got %v", vv.Verdict)
+	}
+	if vv.Confidence != 0.9 {
+		t.Errorf("expected confidence 0.9, got %v", vv.Confidence)
+	}
+	if len(attempts) != 1 || !attempts[0].Matched {
+		t.Errorf("expected one matched attempt, got %+v", attempts)
+	}
+}
+
+func TestEvaluateVerdictMatchers_NoMatch(t *testing.T) {
+	matchers := []VerdictMatcher{
+		{Name: "outcome", Path: "$.outcome", Values: map[string]string{"pass": "supports"}},
+	}
+	vv, attempts := EvaluateVerdictMatchers(matchers, `{"outcome":"maybe"}`)
+	if vv != nil {
+		t.Errorf("expected no matcher to fire, got %+v", vv)
+	}
+	if len(attempts) != 1 || attempts[0].Matched {
+		t.Errorf("expected one unmatched attempt, got %+v", attempts)
+	}
+}
+
+func TestEvaluateVerdictMatchers_NotJSON(t *testing.T) {
+	matchers := []VerdictMatcher{{Name: "outcome", Path: "$.outcome"}}
+	vv, attempts := EvaluateVerdictMatchers(matchers, "not json")
+	if vv != nil {
+		t.Errorf("expected nil verdict for non-JSON input, got %+v", vv)
+	}
+	if len(attempts) != 1 || attempts[0].Err == "" {
+		t.Errorf("expected an error attempt, got %+v", attempts)
+	}
+}
+
+func TestDetermineVerdictWithRulesAndMatchers_FallsBackToText(t *testing.T) {
+	vv := DetermineVerdictWithRulesAndMatchers(nil, nil, nil, "", "tests pass, looks good", 0, 0, 0)
+	if vv.Verdict != VerdictSupports {
+		t.Errorf("expected supports from text fallback, got %v", vv.Verdict)
+	}
+}
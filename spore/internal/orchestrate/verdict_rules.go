@@ -0,0 +1,249 @@
+package orchestrate
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/expr-lang/expr"
+	"github.com/expr-lang/expr/vm"
+	"gopkg.in/yaml.v3"
+
+	"mycelica/spore/internal/db"
+)
+
+// VerdictRuleSpec is one user-authored rule loaded from a `verdict_rules:`
+// file or task-file key. Expr must evaluate to "supports", "contradicts", or
+// "unknown"; Reason is interpolated against the same environment with
+// fmt.Sprintf-style `%v` verbs (e.g. "cost %v exceeded budget"), not expr,
+// for the synthesized VerifierVerdict reason.
+type VerdictRuleSpec struct {
+	Expr   string `yaml:"expr"`
+	Reason string `yaml:"reason"`
+}
+
+// CompiledVerdictRule is a VerdictRuleSpec with its expr program compiled once.
+type CompiledVerdictRule struct {
+	Spec    VerdictRuleSpec
+	Program *vm.Program
+}
+
+// verdictRuleEnv is the expr evaluation environment: everything a rule can
+// reference by name.
+type verdictRuleEnv struct {
+	Text      string     `expr:"text"`
+	Edges     *edgeQuery `expr:"edges"`
+	Nodes     *nodeQuery `expr:"nodes"`
+	Cost      float64    `expr:"cost"`
+	Bounces   int        `expr:"bounces"`
+	ElapsedMs int64      `expr:"elapsed_ms"`
+}
+
+// edgeQuery is a small fluent helper exposed to rule expressions as `edges`,
+// e.g. `edges.OfType("supports").ByAgent("spore:verifier").NotSuperseded().Count() >= 2`.
+type edgeQuery struct {
+	edges []db.Edge
+}
+
+func newEdgeQuery(d *db.DB, implNodeID string) *edgeQuery {
+	if d == nil || implNodeID == "" {
+		return &edgeQuery{}
+	}
+	edges, _ := d.GetEdgesForNode(implNodeID)
+	var targeting []db.Edge
+	for _, e := range edges {
+		if e.TargetID == implNodeID {
+			targeting = append(targeting, e)
+		}
+	}
+	return &edgeQuery{edges: targeting}
+}
+
+func (q *edgeQuery) OfType(t string) *edgeQuery {
+	var filtered []db.Edge
+	for _, e := range q.edges {
+		if e.EdgeType == t {
+			filtered = append(filtered, e)
+		}
+	}
+	return &edgeQuery{edges: filtered}
+}
+
+func (q *edgeQuery) ByAgent(agentID string) *edgeQuery {
+	var filtered []db.Edge
+	for _, e := range q.edges {
+		if e.AgentID != nil && *e.AgentID == agentID {
+			filtered = append(filtered, e)
+		}
+	}
+	return &edgeQuery{edges: filtered}
+}
+
+func (q *edgeQuery) NotSuperseded() *edgeQuery {
+	var filtered []db.Edge
+	for _, e := range q.edges {
+		if e.SupersededBy == nil {
+			filtered = append(filtered, e)
+		}
+	}
+	return &edgeQuery{edges: filtered}
+}
+
+// DistinctAgents returns the count of distinct non-nil agent IDs among the
+// edges currently selected.
+func (q *edgeQuery) DistinctAgents() int {
+	seen := make(map[string]bool)
+	for _, e := range q.edges {
+		if e.AgentID != nil {
+			seen[*e.AgentID] = true
+		}
+	}
+	return len(seen)
+}
+
+func (q *edgeQuery) Count() int { return len(q.edges) }
+
+// nodeQuery is edgeQuery's counterpart exposed to rule expressions as
+// `nodes`: the other nodes directly connected to the impl node by an edge
+// touching it, e.g. `nodes.OfClass("operational").Count() > 0`.
+type nodeQuery struct {
+	nodes []db.Node
+}
+
+func newNodeQuery(d *db.DB, implNodeID string) *nodeQuery {
+	if d == nil || implNodeID == "" {
+		return &nodeQuery{}
+	}
+	edges, _ := d.GetEdgesForNode(implNodeID)
+
+	seen := make(map[string]bool)
+	var nodes []db.Node
+	for _, e := range edges {
+		neighborID := e.SourceID
+		if neighborID == implNodeID {
+			neighborID = e.TargetID
+		}
+		if neighborID == "" || neighborID == implNodeID || seen[neighborID] {
+			continue
+		}
+		seen[neighborID] = true
+		if n, err := d.GetNode(neighborID); err == nil && n != nil {
+			nodes = append(nodes, *n)
+		}
+	}
+	return &nodeQuery{nodes: nodes}
+}
+
+func (q *nodeQuery) OfClass(nodeClass string) *nodeQuery {
+	var filtered []db.Node
+	for _, n := range q.nodes {
+		if n.NodeClass != nil && *n.NodeClass == nodeClass {
+			filtered = append(filtered, n)
+		}
+	}
+	return &nodeQuery{nodes: filtered}
+}
+
+func (q *nodeQuery) OfMetaType(metaType string) *nodeQuery {
+	var filtered []db.Node
+	for _, n := range q.nodes {
+		if n.MetaType != nil && *n.MetaType == metaType {
+			filtered = append(filtered, n)
+		}
+	}
+	return &nodeQuery{nodes: filtered}
+}
+
+func (q *nodeQuery) Count() int { return len(q.nodes) }
+
+// LoadVerdictRules reads a YAML file of `rules: [...]` VerdictRuleSpecs and
+// compiles each against the verdictRuleEnv typed environment. Returns a
+// structured error listing the rule index and expr diagnostic on the first
+// compile failure.
+func LoadVerdictRules(path string) ([]CompiledVerdictRule, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading verdict rules file %s: %w", path, err)
+	}
+	var doc struct {
+		Rules []VerdictRuleSpec `yaml:"rules"`
+	}
+	if err := yaml.Unmarshal(raw, &doc); err != nil {
+		return nil, fmt.Errorf("parsing verdict rules file %s: %w", path, err)
+	}
+
+	compiled := make([]CompiledVerdictRule, 0, len(doc.Rules))
+	for i, spec := range doc.Rules {
+		program, err := expr.Compile(spec.Expr, expr.Env(verdictRuleEnv{}))
+		if err != nil {
+			return nil, fmt.Errorf("verdict rule %d (%q): %w", i, spec.Expr, err)
+		}
+		compiled = append(compiled, CompiledVerdictRule{Spec: spec, Program: program})
+	}
+	return compiled, nil
+}
+
+// EvaluateVerdictRules runs rules in order against the given context,
+// returning the first non-"unknown" result. Returns nil if no rule fires or
+// on any evaluation error for an individual rule (logged, not fatal).
+func EvaluateVerdictRules(rules []CompiledVerdictRule, d *db.DB, implNodeID, verifierOutput string, cost float64, bounces int, elapsed time.Duration) *VerifierVerdict {
+	if len(rules) == 0 {
+		return nil
+	}
+	env := verdictRuleEnv{
+		Text:      verifierOutput,
+		Edges:     newEdgeQuery(d, implNodeID),
+		Nodes:     newNodeQuery(d, implNodeID),
+		Cost:      cost,
+		Bounces:   bounces,
+		ElapsedMs: elapsed.Milliseconds(),
+	}
+	for _, rule := range rules {
+		out, err := expr.Run(rule.Program, env)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "[verdict-rules] rule %q failed: %v\n", rule.Spec.Expr, err)
+			continue
+		}
+		result, _ := out.(string)
+		v := mapVerdictString(result)
+		if v != VerdictUnknown {
+			return &VerifierVerdict{
+				Verdict:    v,
+				Reason:     interpolateReason(rule.Spec.Reason, env),
+				Confidence: 1.0,
+			}
+		}
+	}
+	return nil
+}
+
+// interpolateReason renders a VerdictRuleSpec.Reason template against env's
+// scalar fields via fmt.Sprintf: each `%v` verb in template consumes, in
+// order, env.Cost, env.Bounces, env.ElapsedMs, then env.Text -- cost/
+// bounces/elapsed_ms first since those are the short numeric values a
+// reason string actually wants to quote; Text (the full verifier output)
+// trails since it's the one a reason would embed last, if at all
+// (Edges/Nodes aren't interpolated -- they're graph handles, not
+// reason-worthy values). Only as many args as the template has `%v` verbs
+// are passed, so a plain literal reason with no verbs at all -- the common
+// case, and every pre-existing VerdictRuleSpec.Reason in this series --
+// round-trips unchanged instead of growing a "%!(EXTRA ...)" suffix.
+func interpolateReason(template string, env verdictRuleEnv) string {
+	args := []any{env.Cost, env.Bounces, env.ElapsedMs, env.Text}
+	n := strings.Count(template, "%v")
+	if n > len(args) {
+		n = len(args)
+	}
+	return fmt.Sprintf(template, args[:n]...)
+}
+
+// DetermineVerdictWithRules runs the configurable rule engine (Layer 0) before
+// falling back to DetermineVerdict's existing graph/JSON/text layers. Pass a
+// nil or empty rules slice to get identical behavior to DetermineVerdict.
+func DetermineVerdictWithRules(rules []CompiledVerdictRule, d *db.DB, implNodeID, verifierOutput string, cost float64, bounces int, elapsed time.Duration) *VerifierVerdict {
+	if vv := EvaluateVerdictRules(rules, d, implNodeID, verifierOutput, cost, bounces, elapsed); vv != nil {
+		return vv
+	}
+	return DetermineVerdict(d, implNodeID, verifierOutput)
+}
@@ -0,0 +1,195 @@
+package orchestrate
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"mycelica/spore/internal/db"
+)
+
+func writeVerdictRulesFile(t *testing.T, yamlContent string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	if err := os.WriteFile(path, []byte(yamlContent), 0644); err != nil {
+		t.Fatalf("writing rules file: %v", err)
+	}
+	return path
+}
+
+func TestLoadVerdictRules(t *testing.T) {
+	path := writeVerdictRulesFile(t, `
+rules:
+  - expr: 'cost < 0.5 ? "supports" : "unknown"'
+    reason: "cost %v under budget"
+  - expr: '"contradicts"'
+    reason: "always fails"
+`)
+
+	rules, err := LoadVerdictRules(path)
+	if err != nil {
+		t.Fatalf("LoadVerdictRules: %v", err)
+	}
+	if len(rules) != 2 {
+		t.Fatalf("expected 2 compiled rules, got %d", len(rules))
+	}
+	if rules[0].Program == nil {
+		t.Error("expected rule 0 to have a compiled program")
+	}
+}
+
+func TestLoadVerdictRules_CompileError(t *testing.T) {
+	path := writeVerdictRulesFile(t, `
+rules:
+  - expr: 'this is not valid expr syntax ('
+    reason: "whatever"
+`)
+
+	if _, err := LoadVerdictRules(path); err == nil {
+		t.Error("expected a compile error for an invalid expr")
+	}
+}
+
+func TestEvaluateVerdictRules_FirstNonUnknownWins(t *testing.T) {
+	path := writeVerdictRulesFile(t, `
+rules:
+  - expr: 'cost < 0.5 ? "supports" : "unknown"'
+    reason: "cost %v under budget"
+  - expr: '"contradicts"'
+    reason: "always fails"
+`)
+	rules, err := LoadVerdictRules(path)
+	if err != nil {
+		t.Fatalf("LoadVerdictRules: %v", err)
+	}
+
+	vv := EvaluateVerdictRules(rules, nil, "", "", 0.1, 0, 0)
+	if vv == nil {
+		t.Fatal("expected a non-nil verdict")
+	}
+	if vv.Verdict != VerdictSupports {
+		t.Errorf("expected supports, got %v", vv.Verdict)
+	}
+	if vv.Reason != "cost 0.1 under budget" {
+		t.Errorf("expected interpolated reason, got %q", vv.Reason)
+	}
+}
+
+func TestEvaluateVerdictRules_AllUnknownReturnsNil(t *testing.T) {
+	path := writeVerdictRulesFile(t, `
+rules:
+  - expr: '"unknown"'
+    reason: "never fires"
+`)
+	rules, err := LoadVerdictRules(path)
+	if err != nil {
+		t.Fatalf("LoadVerdictRules: %v", err)
+	}
+
+	if vv := EvaluateVerdictRules(rules, nil, "", "", 0, 0, 0); vv != nil {
+		t.Errorf("expected nil when every rule evaluates to unknown, got %+v", vv)
+	}
+}
+
+func TestEvaluateVerdictRules_EdgesAndNodesBindings(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	verifierAgent := "spore:verifier"
+	insertTestEdge(t, d, "e1", "verifier-run", "impl-node", "supports", &verifierAgent)
+	insertTestEdge(t, d, "e2", "other-run", "impl-node", "supports", &verifierAgent)
+
+	path := writeVerdictRulesFile(t, `
+rules:
+  - expr: 'edges.OfType("supports").ByAgent("spore:verifier").NotSuperseded().DistinctAgents() >= 1 ? "supports" : "unknown"'
+    reason: "distinct verifier agents agreed"
+`)
+	rules, err := LoadVerdictRules(path)
+	if err != nil {
+		t.Fatalf("LoadVerdictRules: %v", err)
+	}
+
+	vv := EvaluateVerdictRules(rules, d, "impl-node", "", 0, 0, 0)
+	if vv == nil || vv.Verdict != VerdictSupports {
+		t.Fatalf("expected edges binding to drive a supports verdict, got %+v", vv)
+	}
+}
+
+func TestInterpolateReason(t *testing.T) {
+	env := verdictRuleEnv{Text: "looks good", Cost: 0.25, Bounces: 2, ElapsedMs: 1500}
+
+	cases := []struct {
+		template string
+		want     string
+	}{
+		{"always fails", "always fails"},
+		{"cost %v exceeded budget", "cost 0.25 exceeded budget"},
+		{"cost %v after %v bounces", "cost 0.25 after 2 bounces"},
+	}
+	for _, c := range cases {
+		if got := interpolateReason(c.template, env); got != c.want {
+			t.Errorf("interpolateReason(%q) = %q, want %q", c.template, got, c.want)
+		}
+	}
+}
+
+func TestEdgeQuery_FluentChain(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	verifierAgent := "spore:verifier"
+	coderAgent := "spore:coder"
+	insertTestEdge(t, d, "e1", "verifier-run", "impl-node", "supports", &verifierAgent)
+	insertTestEdge(t, d, "e2", "coder-run", "impl-node", "supports", &coderAgent)
+	insertTestEdge(t, d, "e3", "verifier-run-2", "impl-node", "contradicts", &verifierAgent)
+
+	q := newEdgeQuery(d, "impl-node")
+	if got := q.Count(); got != 3 {
+		t.Fatalf("expected 3 edges touching impl-node, got %d", got)
+	}
+
+	supports := q.OfType("supports")
+	if got := supports.Count(); got != 2 {
+		t.Errorf("expected 2 supports edges, got %d", got)
+	}
+
+	byVerifier := supports.ByAgent("spore:verifier")
+	if got := byVerifier.Count(); got != 1 {
+		t.Errorf("expected 1 supports edge from the verifier, got %d", got)
+	}
+	if got := byVerifier.NotSuperseded().Count(); got != 1 {
+		t.Errorf("expected the verifier edge to remain after NotSuperseded, got %d", got)
+	}
+}
+
+func TestNodeQuery_OfClass(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	createTestNode(t, d, "task-node", "operational")
+	createTestNode(t, d, "other-node", "knowledge")
+	verifierAgent := "spore:verifier"
+	insertTestEdge(t, d, "e1", "impl-node", "task-node", "derives_from", &verifierAgent)
+	insertTestEdge(t, d, "e2", "impl-node", "other-node", "derives_from", &verifierAgent)
+
+	q := newNodeQuery(d, "impl-node")
+	if got := q.Count(); got != 2 {
+		t.Fatalf("expected 2 neighbor nodes, got %d", got)
+	}
+	if got := q.OfClass("operational").Count(); got != 1 {
+		t.Errorf("expected 1 operational node, got %d", got)
+	}
+}
+
+// createTestNode inserts a minimal node row directly, for exercising
+// nodeQuery without a live CLI backend.
+func createTestNode(t *testing.T, d *db.DB, id, nodeClass string) {
+	t.Helper()
+	_, err := d.Conn().Exec(
+		`INSERT INTO nodes (id, type, title, created_at, updated_at, node_class) VALUES (?, 'page', ?, 1000, 1000, ?)`,
+		id, id, nodeClass,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+}
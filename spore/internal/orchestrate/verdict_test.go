@@ -441,6 +441,70 @@ func TestParseVerdictFromText_ExplicitMarkers(t *testing.T) {
 	}
 }
 
+// --- CollectVerdictEvidence / conflict detection ---
+
+func TestCollectVerdictEvidence_NoConflict(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	agentID := "spore:verifier"
+	insertTestEdge(t, d, "e1", "verifier-run", "impl-node", "supports", &agentID)
+
+	evidence := CollectVerdictEvidence(d, "impl-node")
+	if evidence.Conflicted {
+		t.Error("expected no conflict with a single agreeing edge")
+	}
+	if evidence.Verdict() != VerdictSupports {
+		t.Errorf("expected VerdictSupports, got %v", evidence.Verdict())
+	}
+}
+
+func TestCollectVerdictEvidence_AgentsDisagree(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	verifierA := "spore:verifier"
+	verifierB := "spore:verifier-2"
+	insertTestEdge(t, d, "e1", "run-a", "impl-node", "supports", &verifierA)
+	insertTestEdge(t, d, "e2", "run-b", "impl-node", "contradicts", &verifierB)
+
+	evidence := CollectVerdictEvidence(d, "impl-node")
+	if !evidence.Conflicted {
+		t.Fatal("expected conflict when two agents disagree")
+	}
+	if evidence.Verdict() != VerdictConflicted {
+		t.Errorf("expected VerdictConflicted, got %v", evidence.Verdict())
+	}
+	if len(evidence.ByAgent) != 2 {
+		t.Errorf("expected evidence grouped by 2 agents, got %d", len(evidence.ByAgent))
+	}
+}
+
+func TestDetermineVerdict_SurfacesConflict(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	verifierA := "spore:verifier"
+	verifierB := "spore:verifier-2"
+	insertTestEdge(t, d, "e1", "run-a", "impl-node", "supports", &verifierA)
+	insertTestEdge(t, d, "e2", "run-b", "impl-node", "contradicts", &verifierB)
+
+	vv := DetermineVerdict(d, "impl-node", "")
+	if vv.Verdict != VerdictConflicted {
+		t.Errorf("expected VerdictConflicted, got %v", vv.Verdict)
+	}
+}
+
+func TestResolveVerdictConflict_RequiresConflict(t *testing.T) {
+	d := setupVerdictTestDB(t)
+	defer d.Close()
+
+	_, err := ResolveVerdictConflict(d, "impl-node", "task-node", VerdictEvidence{}, VerdictSupports, "")
+	if err == nil {
+		t.Error("expected an error when evidence is not conflicted")
+	}
+}
+
 func TestParseVerdictFromText_EdgeTypePattern(t *testing.T) {
 	got := ParseVerdictFromText(`I created an edge with edge_type: "supports" for this implementation.`)
 	if got != VerdictSupports {
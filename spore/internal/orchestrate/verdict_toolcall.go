@@ -0,0 +1,159 @@
+package orchestrate
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"time"
+
+	"mycelica/spore/internal/db"
+)
+
+// submitVerdictTool is the MCP tool the verifier agent is instructed to call
+// with its verdict, instead of leaving it to be scraped from thinking/stderr
+// text (see WriteMCPConfig and the mycelica MCP server's submit_verdict
+// tool).
+const submitVerdictTool = "mcp__mycelica__submit_verdict"
+
+// VerdictEvidenceItem is one piece of graph evidence a verifier cites in
+// support of its verdict.
+type VerdictEvidenceItem struct {
+	NodeID string `json:"node_id"`
+	Quote  string `json:"quote"`
+}
+
+// StructuredVerdict is the stable JSON schema verifiers emit via the
+// submit_verdict tool (or, as a fallback, a fenced ```json block), carrying
+// evidence and suggested fixes that the older thinking/stderr-scrape layers
+// have no way to express.
+type StructuredVerdict struct {
+	Verdict        string                `json:"verdict"`
+	Confidence     float64               `json:"confidence"`
+	Reason         string                `json:"reason"`
+	Evidence       []VerdictEvidenceItem `json:"evidence,omitempty"`
+	SuggestedFixes []string              `json:"suggested_fixes,omitempty"`
+}
+
+// toVerifierVerdict maps the wire schema onto the pipeline's VerifierVerdict.
+func (sv StructuredVerdict) toVerifierVerdict() *VerifierVerdict {
+	confidence := sv.Confidence
+	if confidence <= 0 {
+		confidence = 0.9
+	}
+	if confidence > 1 {
+		confidence = 1
+	}
+	return &VerifierVerdict{
+		Verdict:    mapVerdictString(sv.Verdict),
+		Reason:     sv.Reason,
+		Confidence: confidence,
+	}
+}
+
+// ExtractSubmitVerdictCall returns the StructuredVerdict from the last
+// submitVerdictTool invocation in toolCalls, or nil if the verifier never
+// called it. "Last" matches parseStreamJSON's last-thinking-block
+// convention: a verifier that corrects itself mid-run has its final call win.
+func ExtractSubmitVerdictCall(toolCalls []ToolCall) *StructuredVerdict {
+	for i := len(toolCalls) - 1; i >= 0; i-- {
+		if toolCalls[i].Name != submitVerdictTool {
+			continue
+		}
+		var sv StructuredVerdict
+		if err := json.Unmarshal(toolCalls[i].Input, &sv); err != nil {
+			continue
+		}
+		return &sv
+	}
+	return nil
+}
+
+// fencedJSONRe matches fenced ```json ... ``` code blocks.
+var fencedJSONRe = regexp.MustCompile("(?s)```json\\s*(\\{.*?\\})\\s*```")
+
+// ExtractFencedVerdictJSON looks for a fenced ```json block carrying the
+// StructuredVerdict schema in text (the verifier's last assistant message).
+// Tries blocks last-to-first and returns the first that parses as a verdict,
+// or nil if none do.
+func ExtractFencedVerdictJSON(text string) *StructuredVerdict {
+	matches := fencedJSONRe.FindAllStringSubmatch(text, -1)
+	for i := len(matches) - 1; i >= 0; i-- {
+		var sv StructuredVerdict
+		if err := json.Unmarshal([]byte(matches[i][1]), &sv); err != nil {
+			continue
+		}
+		if mapVerdictString(sv.Verdict) == VerdictUnknown {
+			continue
+		}
+		return &sv
+	}
+	return nil
+}
+
+// DetermineVerdictWithToolCall is runVerifier's verdict entry point. It
+// extends DetermineVerdictWithRulesAndMatchers with a new Layer 1.5, slotted
+// between graph edges and the legacy JSON-in-text layer, that prefers a
+// verifier's structured submit_verdict tool call or a fenced ```json``` block
+// over today's thinking/stderr-scrape heuristic. The second return value is
+// the StructuredVerdict that produced the verdict, if Layer 1.5 fired, so the
+// caller can record its evidence as "cites" edges.
+func DetermineVerdictWithToolCall(
+	toolCalls []ToolCall, lastMessage string,
+	rules []CompiledVerdictRule, matchers []VerdictMatcher,
+	d *db.DB, implNodeID, verifierOutput string,
+	cost float64, bounces int, elapsed time.Duration,
+) (*VerifierVerdict, *StructuredVerdict) {
+	// Layer 0: configurable expr rules
+	if vv := EvaluateVerdictRules(rules, d, implNodeID, verifierOutput, cost, bounces, elapsed); vv != nil {
+		return vv, nil
+	}
+
+	// Layer 1: graph edges
+	if d != nil && implNodeID != "" {
+		if evidence := CollectVerdictEvidence(d, implNodeID); evidence.Conflicted {
+			if agg := AggregateVerdicts(evidence.Edges, time.Now(), nil, 0); agg.Confidence >= conflictResolutionThreshold {
+				return agg, nil
+			}
+			return &VerifierVerdict{
+				Verdict:    VerdictConflicted,
+				Reason:     fmt.Sprintf("%d agents disagree across %d verdict edges", len(evidence.ByAgent), len(evidence.Edges)),
+				Confidence: 0.0,
+			}, nil
+		}
+		if v := CheckVerdictFromGraph(d, implNodeID); v != VerdictUnknown {
+			return &VerifierVerdict{Verdict: v, Reason: "Verdict from graph edge", Confidence: 1.0}, nil
+		}
+	}
+
+	// Layer 1.5: structured submit_verdict tool call, or a fenced JSON
+	// fallback, ahead of the brittler text-scraping layers below.
+	if sv := ExtractSubmitVerdictCall(toolCalls); sv != nil {
+		return sv.toVerifierVerdict(), sv
+	}
+	if sv := ExtractFencedVerdictJSON(lastMessage); sv != nil {
+		return sv.toVerifierVerdict(), sv
+	}
+
+	// Layer 2: structured JSON scraped from thinking/stderr
+	if vv := ParseVerifierVerdictJSON(verifierOutput); vv != nil && vv.Verdict != VerdictUnknown {
+		return vv, nil
+	}
+
+	// Layer 2.5: JSONPath matchers
+	if len(matchers) > 0 {
+		if vv, _ := EvaluateVerdictMatchers(matchers, verifierOutput); vv != nil {
+			return vv, nil
+		}
+	}
+
+	// Layer 3: text keywords
+	if v := ParseVerdictFromText(verifierOutput); v != VerdictUnknown {
+		return &VerifierVerdict{
+			Verdict:    v,
+			Reason:     "Verdict inferred from verifier output text (keyword scan)",
+			Confidence: 0.6,
+		}, nil
+	}
+
+	return &VerifierVerdict{Verdict: VerdictUnknown, Reason: "", Confidence: 0.0}, nil
+}
@@ -0,0 +1,79 @@
+package orchestrate
+
+import "testing"
+
+func TestExtractSubmitVerdictCall(t *testing.T) {
+	toolCalls := []ToolCall{
+		{Name: "Read", Input: []byte(`{"file":"a.go"}`)},
+		{Name: submitVerdictTool, Input: []byte(`{"verdict":"supports","confidence":0.95,"reason":"tests pass","evidence":[{"node_id":"n1","quote":"matches spec"}]}`)},
+	}
+
+	sv := ExtractSubmitVerdictCall(toolCalls)
+	if sv == nil {
+		t.Fatal("expected a structured verdict")
+	}
+	if sv.Verdict != "supports" || sv.Confidence != 0.95 {
+		t.Errorf("sv = %+v, want verdict=supports confidence=0.95", sv)
+	}
+	if len(sv.Evidence) != 1 || sv.Evidence[0].NodeID != "n1" {
+		t.Errorf("sv.Evidence = %+v, want one item with node_id n1", sv.Evidence)
+	}
+}
+
+func TestExtractSubmitVerdictCall_LastWins(t *testing.T) {
+	toolCalls := []ToolCall{
+		{Name: submitVerdictTool, Input: []byte(`{"verdict":"contradicts"}`)},
+		{Name: submitVerdictTool, Input: []byte(`{"verdict":"supports"}`)},
+	}
+	sv := ExtractSubmitVerdictCall(toolCalls)
+	if sv == nil || sv.Verdict != "supports" {
+		t.Fatalf("expected the last call to win, got %+v", sv)
+	}
+}
+
+func TestExtractSubmitVerdictCall_NoneCalled(t *testing.T) {
+	if sv := ExtractSubmitVerdictCall([]ToolCall{{Name: "Read"}}); sv != nil {
+		t.Errorf("expected nil, got %+v", sv)
+	}
+}
+
+func TestExtractFencedVerdictJSON(t *testing.T) {
+	text := "Here's my analysis.\n```json\n{\"verdict\":\"contradicts\",\"reason\":\"missing test\"}\n```\nDone."
+	sv := ExtractFencedVerdictJSON(text)
+	if sv == nil {
+		t.Fatal("expected a structured verdict")
+	}
+	if sv.Verdict != "contradicts" || sv.Reason != "missing test" {
+		t.Errorf("sv = %+v, want verdict=contradicts reason=%q", sv, "missing test")
+	}
+}
+
+func TestExtractFencedVerdictJSON_NoBlock(t *testing.T) {
+	if sv := ExtractFencedVerdictJSON("plain text, no fences here"); sv != nil {
+		t.Errorf("expected nil, got %+v", sv)
+	}
+}
+
+func TestDetermineVerdictWithToolCall_PrefersToolCall(t *testing.T) {
+	toolCalls := []ToolCall{
+		{Name: submitVerdictTool, Input: []byte(`{"verdict":"supports","confidence":0.9}`)},
+	}
+	// verifierOutput text keyword-scans as "fail" — the tool call must win.
+	vv, sv := DetermineVerdictWithToolCall(toolCalls, "", nil, nil, nil, "", "the tests fail", 0, 0, 0)
+	if vv.Verdict != VerdictSupports {
+		t.Errorf("verdict = %v, want supports", vv.Verdict)
+	}
+	if sv == nil {
+		t.Error("expected the structured verdict to be returned for evidence extraction")
+	}
+}
+
+func TestDetermineVerdictWithToolCall_FallsBackToTextScrape(t *testing.T) {
+	vv, sv := DetermineVerdictWithToolCall(nil, "", nil, nil, nil, "", "verdict: pass", 0, 0, 0)
+	if vv.Verdict != VerdictSupports {
+		t.Errorf("verdict = %v, want supports", vv.Verdict)
+	}
+	if sv != nil {
+		t.Errorf("expected no structured verdict from the text-scrape fallback, got %+v", sv)
+	}
+}